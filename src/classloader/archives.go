@@ -70,7 +70,7 @@ func (archive *Archive) scanArchive() error {
 	for _, file := range reader.File {
 		entry := archive.recordFile(file)
 		if entry.Type == Manifest {
-			if archive.parseManifest(file); err != nil {
+			if err = archive.parseManifest(file); err != nil {
 				return err
 			}
 		}
@@ -102,25 +102,31 @@ func (archive *Archive) recordFile(file *zip.File) ResourceEntry {
 	return entry
 }
 
+// parseManifest reads the name: value attributes of the manifest. Its lines can end in
+// CR LF, LF, or CR, and a line that begins with a space continues the previous line.
 func (archive *Archive) parseManifest(file *zip.File) error {
 	rc, err := file.Open()
 
-	defer rc.Close()
-
 	if err != nil {
 		return err
 	}
 
+	defer rc.Close()
+
 	data, err := io.ReadAll(rc)
 
-	contents := string(data)
+	if err != nil {
+		return err
+	}
 
-	lines := strings.Split(contents, "\r\n")
+	contents := strings.ReplaceAll(string(data), "\r\n", "\n")
+	contents = strings.ReplaceAll(contents, "\r", "\n")
+	contents = strings.ReplaceAll(contents, "\n ", "") // join the continuation lines
 
-	for _, line := range lines {
-		parts := strings.Split(line, ":")
-		if len(parts) > 1 {
-			archive.manifest[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	for _, line := range strings.Split(contents, "\n") {
+		name, value, found := strings.Cut(line, ":")
+		if found {
+			archive.manifest[strings.TrimSpace(name)] = strings.TrimSpace(value)
 		}
 	}
 
@@ -137,7 +143,12 @@ func (archive *Archive) hasResource(name string, resourceType ResourceType) bool
 	return item.Type == resourceType
 }
 
+// loadClass reads the bytes of a class in the archive. The class name can be in the
+// dotted form, as in the manifest's Main-Class, or in the internal form used by the
+// classloader, whose separators can be slashes or the platform's path separator.
 func (archive *Archive) loadClass(className string) (*LoadResult, error) {
+	className = strings.TrimSuffix(className, ".class")
+	className = strings.NewReplacer("/", ".", "\\", ".").Replace(className)
 	item, ok := archive.entryCache[className]
 
 	if !ok {
@@ -151,20 +162,20 @@ func (archive *Archive) loadClass(className string) (*LoadResult, error) {
 
 	reader, err := zip.OpenReader(archive.Filename)
 
-	defer reader.Close()
-
 	if err != nil {
 		return nil, err
 	}
 
-	file, err := reader.Open(item.Location)
+	defer reader.Close()
 
-	defer file.Close()
+	file, err := reader.Open(item.Location)
 
 	if err != nil {
 		return nil, err
 	}
 
+	defer file.Close()
+
 	bytes, err := io.ReadAll(file)
 
 	if err != nil {
@@ -7,6 +7,8 @@
 package classloader
 
 import (
+	"archive/zip"
+	"jacobin/globals"
 	"os"
 	"path/filepath"
 	"testing"
@@ -102,3 +104,83 @@ func TestLoadClassDoesNotExist(t *testing.T) {
 		t.Error("Expected error loading class, but didn't get one.")
 	}
 }
+
+// a jar whose manifest has LF line endings and a continuation line, and whose classes
+// are looked up by the internal names the classloader uses as well as by dotted names
+func TestJarWithPackagedClasses(t *testing.T) {
+	jarName := filepath.Join(t.TempDir(), "app.jar")
+	jarFile, err := os.Create(jarName)
+	if err != nil {
+		t.Fatalf("Unable to create the jar file: %s", err.Error())
+	}
+	writer := zip.NewWriter(jarFile)
+	entries := map[string]string{
+		"META-INF/MANIFEST.MF":     "Manifest-Version: 1.0\nMain-Class: com.example.\n Main\nImplementation-URL: http://example.com\n\n",
+		"com/example/Main.class":   "main",
+		"com/example/Helper.class": "helper",
+	}
+	for name, contents := range entries {
+		w, _ := writer.Create(name)
+		_, _ = w.Write([]byte(contents))
+	}
+	_ = writer.Close()
+	_ = jarFile.Close()
+
+	jar, err := NewJarFile(jarName)
+	if err != nil {
+		t.Fatalf("Unable to read the jar file: %s", err.Error())
+	}
+
+	if mainClass := jar.getMainClass(); mainClass != "com.example.Main" {
+		t.Errorf("Expected Main-Class to be 'com.example.Main', but was '%s'", mainClass)
+	}
+	if url := jar.manifest["Implementation-URL"]; url != "http://example.com" {
+		t.Errorf("Expected Implementation-URL to be 'http://example.com', but was '%s'", url)
+	}
+
+	for className, contents := range map[string]string{
+		"com.example.Main":   "main",
+		"com/example/Helper": "helper",
+	} {
+		result, err := jar.loadClass(className)
+		if err != nil {
+			t.Errorf("Error loading class %s: %s", className, err.Error())
+			continue
+		}
+		if !result.Success || string(*result.Data) != contents {
+			t.Errorf("Loading class %s did not return its bytes", className)
+		}
+	}
+}
+
+// ClassExists looks for a class in the starting jar, where LoadClassFromNameOnly() does
+func TestClassExistsInStartingJar(t *testing.T) {
+	globals.InitGlobals("test")
+	jarName := filepath.Join(t.TempDir(), "app.jar")
+	jarFile, err := os.Create(jarName)
+	if err != nil {
+		t.Fatalf("Unable to create the jar file: %s", err.Error())
+	}
+	writer := zip.NewWriter(jarFile)
+	w, _ := writer.Create("com/example/Main.class")
+	_, _ = w.Write([]byte("main"))
+	_ = writer.Close()
+	_ = jarFile.Close()
+
+	savedMap, savedSize, savedArchives := JMODMAP, jmodMapSize, AppCL.Archives
+	defer func() { JMODMAP, jmodMapSize, AppCL.Archives = savedMap, savedSize, savedArchives }()
+	JMODMAP = map[string]string{"java/lang/Object.class": "java.base.jmod"}
+	jmodMapSize = len(JMODMAP)
+	AppCL.Archives = make(map[string]*Archive)
+	globals.GetGlobalRef().StartingJar = jarName
+
+	if !ClassExists("com/example/Main") {
+		t.Errorf("Expected com/example/Main to be found in the starting jar")
+	}
+	if ClassExists("com/example/Missing") {
+		t.Errorf("Expected com/example/Missing not to be found in the starting jar")
+	}
+	if !ClassExists("java/lang/Object") {
+		t.Errorf("Expected java/lang/Object to be found in its jmod")
+	}
+}
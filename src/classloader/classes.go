@@ -299,6 +299,7 @@ func FetchMethodAndCP(className, methName, methType string) (MTentry, error) {
 			Code:        m.CodeAttr.Code,
 			Exceptions:  m.CodeAttr.Exceptions,
 			Attribs:     m.CodeAttr.Attributes,
+			LineTable:   m.CodeAttr.BytecodeSourceMap,
 			params:      m.Parameters,
 			deprecated:  m.Deprecated,
 			Cp:          &k.Data.CP,
@@ -343,6 +344,7 @@ func FetchMethodAndCP(className, methName, methType string) (MTentry, error) {
 				Code:        m.CodeAttr.Code,
 				Exceptions:  m.CodeAttr.Exceptions,
 				Attribs:     m.CodeAttr.Attributes,
+				LineTable:   m.CodeAttr.BytecodeSourceMap,
 				params:      m.Parameters,
 				deprecated:  m.Deprecated,
 				Cp:          &k.Data.CP,
@@ -364,6 +366,115 @@ func FetchMethodAndCP(className, methName, methType string) (MTentry, error) {
 	}
 }
 
+// FetchDefaultMethod finds the default method (an interface method with a body) that a class
+// inherits when neither it nor its superclasses implement the method. The interfaces of the
+// class and of its superclasses, and their superinterfaces, are searched in that order, and
+// the first default method found is returned, along with the name of its interface. Static
+// and private interface methods are not inherited, so they are skipped. A gfunction registered
+// for an interface method (such as Map.getOrDefault) also counts as a default method. If there
+// is no default method, an error is returned.
+func FetchDefaultMethod(className, methName, methType string) (MTentry, string, error) {
+	searchName := methName + methType
+	var found *Method
+	var foundKlass *Klass
+	var gEntry MTentry
+	forEachInterface(className, func(k *Klass) bool {
+		// as with class methods, a gfunction takes precedence over the interface's bytecode
+		if entry := MTable[k.Data.Name+"."+searchName]; entry.Meth != nil && entry.MType == 'G' {
+			gEntry, foundKlass = entry, k
+			return true
+		}
+		m, ok := k.Data.MethodTable[searchName]
+		if ok && m.AccessFlags&(0x0400|0x0008|0x0002) == 0 { // not abstract, static, or private
+			found, foundKlass = m, k
+			return true
+		}
+		return false
+	})
+
+	if gEntry.Meth != nil {
+		AddEntry(&MTable, className+"."+searchName, gEntry)
+		return gEntry, foundKlass.Data.Name, nil
+	}
+
+	if found == nil {
+		errMsg := fmt.Sprintf("FetchDefaultMethod: Neither %s nor its interfaces contain a default method %s",
+			className, searchName)
+		return MTentry{}, "", errors.New(errMsg)
+	}
+
+	jme := JmEntry{
+		AccessFlags: found.AccessFlags,
+		MaxStack:    found.CodeAttr.MaxStack,
+		MaxLocals:   found.CodeAttr.MaxLocals,
+		Code:        found.CodeAttr.Code,
+		Exceptions:  found.CodeAttr.Exceptions,
+		Attribs:     found.CodeAttr.Attributes,
+		LineTable:   found.CodeAttr.BytecodeSourceMap,
+		params:      found.Parameters,
+		deprecated:  found.Deprecated,
+		Cp:          &foundKlass.Data.CP,
+	}
+	methodEntry := MTentry{Meth: jme, MType: 'J'}
+	AddEntry(&MTable, className+"."+searchName, methodEntry)
+	return methodEntry, foundKlass.Data.Name, nil
+}
+
+// ImplementsInterface reports whether a class, one of its superclasses, or one
+// of their interfaces implements the named interface.
+func ImplementsInterface(className, interfaceName string) bool {
+	return forEachInterface(className, func(k *Klass) bool {
+		return k.Data.Name == interfaceName
+	})
+}
+
+// forEachInterface calls visit for each interface of a class and of its superclasses, as well as
+// for the superinterfaces of those interfaces, loading them as needed. Each interface is visited
+// once. The search stops (and forEachInterface returns true) when visit returns true.
+func forEachInterface(className string, visit func(k *Klass) bool) bool {
+	var queue []string
+	for name := className; name != ""; {
+		k := MethAreaFetch(name)
+		if k == nil || k.Data == nil {
+			break
+		}
+		for _, index := range k.Data.Interfaces {
+			queue = append(queue, *stringPool.GetStringPointer(uint32(index)))
+		}
+		if name == types.ObjectClassName {
+			break
+		}
+		name = *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+	}
+
+	visited := make(map[string]bool)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+
+		if MethAreaFetch(name) == nil {
+			if err := LoadClassFromNameOnly(name); err != nil {
+				continue
+			}
+		}
+		k := MethAreaFetch(name)
+		if k == nil || k.Data == nil {
+			continue
+		}
+		if visit(k) {
+			return true
+		}
+		for _, index := range k.Data.Interfaces {
+			queue = append(queue, *stringPool.GetStringPointer(uint32(index)))
+		}
+	}
+	return false
+}
+
 // error message when main() can't be found. Syntax mirrors OpenJDK HotSpot
 func noMainError(className string) {
 	errMsg := fmt.Sprintf("Error: main() method not found in class %s\n"+
@@ -350,3 +350,76 @@ func TestInvalidClassName(t *testing.T) {
 	_ = w.Close()
 	os.Stderr = normalStderr
 }
+
+// insertTestClass puts a class (or interface) with the given superclass, interfaces, and
+// methods (each mapped to its access flags) into the method area
+func insertTestClass(name, superclass string, interfaces []string, methods map[string]int) {
+	methodTable := make(map[string]*Method)
+	for method, accessFlags := range methods {
+		methodTable[method] = &Method{AccessFlags: accessFlags}
+	}
+	var interfaceIndexes []uint16
+	for i := range interfaces {
+		interfaceIndexes = append(interfaceIndexes, uint16(stringPool.GetStringIndex(&interfaces[i])))
+	}
+	MethAreaInsert(name, &Klass{
+		Status: 'X',
+		Loader: "bootstrap",
+		Data: &ClData{
+			Name:            name,
+			Superclass:      superclass,
+			SuperclassIndex: stringPool.GetStringIndex(&superclass),
+			Interfaces:      interfaceIndexes,
+			MethodTable:     methodTable,
+		},
+	})
+}
+
+func TestFetchDefaultMethod(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	InitMethodArea()
+	defer InitMethodArea() // leave only the preloaded classes for the tests that follow
+	MTable = make(map[string]MTentry)
+
+	// Greeter has the default method greet() and the abstract method name().
+	// Polite extends Greeter; Base implements Polite; Derived extends Base.
+	insertTestClass(types.ObjectClassName, "", nil, map[string]int{})
+	insertTestClass("Greeter", types.ObjectClassName, nil,
+		map[string]int{"greet()V": 0x0001, "name()V": 0x0401, "make()V": 0x0009})
+	insertTestClass("Polite", types.ObjectClassName, []string{"Greeter"}, map[string]int{})
+	insertTestClass("Base", types.ObjectClassName, []string{"Polite"}, map[string]int{})
+	insertTestClass("Derived", "Base", nil, map[string]int{})
+
+	mtEntry, interfaceName, err := FetchDefaultMethod("Derived", "greet", "()V")
+	if err != nil || mtEntry.MType != 'J' || interfaceName != "Greeter" {
+		t.Errorf("Expected Derived to inherit Greeter's default greet(), got: %v, %q, %v",
+			mtEntry, interfaceName, err)
+	}
+	if MTable["Derived.greet()V"].Meth == nil {
+		t.Errorf("Expected the default method to be added to the MTable for Derived")
+	}
+
+	// abstract and static interface methods are not default methods
+	if _, _, err = FetchDefaultMethod("Derived", "name", "()V"); err == nil {
+		t.Errorf("Expected no default method for the abstract name()")
+	}
+	if _, _, err = FetchDefaultMethod("Derived", "make", "()V"); err == nil {
+		t.Errorf("Expected no default method for the static make()")
+	}
+
+	// a gfunction registered for an interface method is a default method, too
+	MTable["Polite.bow()V"] = MTentry{Meth: "gfunction", MType: 'G'}
+	mtEntry, interfaceName, err = FetchDefaultMethod("Derived", "bow", "()V")
+	if err != nil || mtEntry.MType != 'G' || interfaceName != "Polite" {
+		t.Errorf("Expected Derived to inherit Polite's gfunction bow(), got: %v, %q, %v",
+			mtEntry, interfaceName, err)
+	}
+
+	if !ImplementsInterface("Derived", "Greeter") || !ImplementsInterface("Derived", "Polite") {
+		t.Errorf("Expected Derived to implement Greeter and Polite via its superclass")
+	}
+	if ImplementsInterface("Derived", "java/lang/Runnable") {
+		t.Errorf("Did not expect Derived to implement java/lang/Runnable")
+	}
+}
@@ -190,6 +190,11 @@ func cfe(msg string) error {
 
 func CFE(msg string) error { return cfe(msg) }
 
+// verifyError returns the error for a class that fails verification
+func verifyError(msg string) error {
+	return errors.New("VerifyError: " + msg)
+}
+
 // LoadBaseClasses loads a basic set of classes that are found in
 // the JAVA_HOME/jmods/java.base.jmod zip file.
 // In Java 17.0.7, there are currently a total of 6401 embedded classes in java.base.jmod.
@@ -319,7 +324,8 @@ func LoadClassFromNameOnly(className string) error {
 			_ = log.Log("LoadClassFromNameOnly: GetClassBytes className="+className+" from jmodFileName="+jmodFileName+" failed", log.SEVERE)
 			_ = log.Log(err.Error(), log.SEVERE)
 		}
-		_, err = loadClassFromBytes(AppCL, className, classBytes)
+		source := "jrt:/" + strings.TrimSuffix(filepath.Base(jmodFileName), ".jmod")
+		_, err = loadClassFromBytes(AppCL, className, source, classBytes)
 		return err
 	}
 
@@ -348,6 +354,27 @@ func LoadClassFromNameOnly(className string) error {
 	return err
 }
 
+// ClassExists reports whether LoadClassFromNameOnly() can find the class, which it looks for
+// in the same places, in the same order: in a jmod, in the starting jar, or in a .class file.
+// Unlike LoadClassFromNameOnly(), it doesn't throw an exception for a missing class.
+func ClassExists(className string) bool {
+	if JmodMapFetch(className) != "" {
+		return true
+	}
+
+	if jarFileName := globals.GetGlobalRef().StartingJar; len(jarFileName) > 0 {
+		jar, err := getJarFile(AppCL, jarFileName)
+		if err != nil {
+			return false
+		}
+		dottedName := strings.NewReplacer("/", ".", "\\", ".").Replace(className)
+		return jar.hasResource(dottedName, ClassFile)
+	}
+
+	_, err := os.Stat(util.ConvertToPlatformPathSeparators(className) + ".class")
+	return err == nil
+}
+
 // LoadClassFromFile first canonicalizes the filename, and reads
 // the indicated file, and runs it through the classloader.
 func LoadClassFromFile(cl Classloader, fname string) (uint32, error) {
@@ -371,7 +398,11 @@ func LoadClassFromFile(cl Classloader, fname string) (uint32, error) {
 	}
 	_ = log.Log("LoadClassFromFile: File "+fname+" was read", log.CLASS)
 
-	return loadClassFromBytes(cl, filename, rawBytes)
+	source := filename
+	if absPath, err := filepath.Abs(filename); err == nil {
+		source = absPath
+	}
+	return loadClassFromBytes(cl, filename, "file:"+filepath.ToSlash(source), rawBytes)
 }
 
 func getJarFile(cl Classloader, jarFileName string) (*Archive, error) {
@@ -419,24 +450,48 @@ func LoadClassFromJar(cl Classloader, filename string, jarFileName string) (uint
 		return types.InvalidStringIndex, fmt.Errorf("unable to find file %s in JAR file %s", filename, jarFileName)
 	}
 
-	return ParseAndPostClass(&cl, filename, *result.Data)
+	source := jarFileName
+	if absPath, err := filepath.Abs(jarFileName); err == nil {
+		source = absPath
+	}
+	return parseAndPostClassFrom(&cl, filename, "file:"+filepath.ToSlash(source), *result.Data)
 }
 
-func loadClassFromBytes(cl Classloader, filename string, rawBytes []byte) (uint32, error) {
-	return ParseAndPostClass(&cl, filename, rawBytes)
+func loadClassFromBytes(cl Classloader, filename string, source string, rawBytes []byte) (uint32, error) {
+	return parseAndPostClassFrom(&cl, filename, source, rawBytes)
 }
 
 // ParseAndPostClass parses a class, presented as a slice of bytes, and
 // if no errors occurred, posts/loads it to the method area.
 func ParseAndPostClass(cl *Classloader, filename string, rawBytes []byte) (uint32, error) {
+	return parseAndPostClassFrom(cl, filename, "", rawBytes)
+}
+
+// parseAndPostClassFrom does the work of ParseAndPostClass. The source is where the
+// class bytes came from (a file:, or jrt: URL, or "" if unknown) and is used
+// only for the -verbose:class trace.
+func parseAndPostClassFrom(cl *Classloader, filename string, source string, rawBytes []byte) (uint32, error) {
 
 	_ = log.Log("ParseAndPostClass: File "+filename+" to be processed", log.CLASS)
-	fullyParsedClass, err := parse(rawBytes)
+	fullyParsedClass, pos, err := parseThroughClassName(rawBytes)
 	if err != nil {
 		_ = log.Log("ParseAndPostClass: error parsing "+filename+". Exiting.", log.SEVERE)
 		return types.InvalidStringIndex, fmt.Errorf("parsing error")
 	}
 
+	// if the class has already been loaded, as can happen when it's reached by two different
+	// code paths, keep the loaded class rather than replace it and lose its static state. This
+	// is checked as soon as the class's name is known, so the rest of it isn't parsed for nothing.
+	if loaded := MethAreaFetch(fullyParsedClass.className); loaded != nil && loaded.Status != 'I' && loaded.Data != nil {
+		_ = log.Log("ParseAndPostClass: class "+fullyParsedClass.className+" is already loaded", log.CLASS)
+		return loaded.Data.NameIndex, nil
+	}
+
+	if err = parseRest(rawBytes, pos, &fullyParsedClass); err != nil {
+		_ = log.Log("ParseAndPostClass: error parsing "+filename+". Exiting.", log.SEVERE)
+		return types.InvalidStringIndex, fmt.Errorf("parsing error")
+	}
+
 	// format check the class
 	if formatCheckClass(&fullyParsedClass) != nil {
 		_ = log.Log("ParseAndPostClass: error format-checking "+filename+". Exiting.", log.SEVERE)
@@ -444,14 +499,28 @@ func ParseAndPostClass(cl *Classloader, filename string, rawBytes []byte) (uint3
 	}
 	_ = log.Log("Class "+fullyParsedClass.className+" has been format-checked.", log.FINEST)
 
+	// verify the class, unless it's a JDK class, which is trusted (see stackMapTable.go)
+	status := byte('F') // F = format-checked
+	if !util.IsFilePartOfJDK(&fullyParsedClass.className) {
+		if err = verifyStackMaps(&fullyParsedClass); err != nil {
+			_ = log.Log("ParseAndPostClass: error verifying "+filename+": "+err.Error(), log.SEVERE)
+			globals.GetGlobalRef().FuncThrowException(excNames.VerifyError, err.Error())
+			return types.InvalidStringIndex, err // return for tests only
+		}
+		status = 'V' // V = verified
+	}
+
 	// prepare the class for posting
 	classToPost := convertToPostableClass(&fullyParsedClass)
 	eKF := Klass{
-		Status: 'F', // F = format-checked
+		Status: status,
 		Loader: cl.Name,
 		Data:   &classToPost,
 	}
-	MethAreaInsert(fullyParsedClass.className, &eKF)
+	// another thread might have posted the same class while this one was checking it
+	if loaded, inserted := MethAreaInsertIfAbsent(fullyParsedClass.className, &eKF); !inserted {
+		return loaded.Data.NameIndex, nil
+	}
 
 	// record the class in the classloader
 	ClassesLock.Lock()
@@ -459,9 +528,24 @@ func ParseAndPostClass(cl *Classloader, filename string, rawBytes []byte) (uint3
 	ClassesLock.Unlock()
 	_ = log.Log("ParseAndPostClass: File "+filename+" fully processed", log.CLASS)
 
+	if globals.GetGlobalRef().VerboseClass {
+		traceClassLoad(fullyParsedClass.className, source)
+	}
+
 	return fullyParsedClass.classNameIndex, nil
 }
 
+// traceClassLoad prints the -verbose:class line for a newly loaded class to stderr,
+// in the same format as HotSpot, e.g.: [Loaded java.lang.Object from jrt:/java.base]
+func traceClassLoad(className string, source string) {
+	javaName := strings.ReplaceAll(className, "/", ".")
+	if source == "" {
+		_, _ = fmt.Fprintf(os.Stderr, "[Loaded %s]\n", javaName)
+	} else {
+		_, _ = fmt.Fprintf(os.Stderr, "[Loaded %s from %s]\n", javaName, source)
+	}
+}
+
 // load the parsed class into a form suitable for posting to the method area (which is
 // exec.MethArea). This mostly involves copying the data, converting most indexes to uint16
 // and removing some fields we needed in parsing, but which are no longer required.
@@ -529,8 +613,9 @@ func convertToPostableClass(fullyParsedClass *ParsedClass) ClData {
 					kdm.CodeAttr.Attributes = append(kdm.CodeAttr.Attributes, kdmca)
 				}
 			}
-			fullyParsedClass.methods[i].codeAttr.sourceLineTable =
-				fullyParsedClass.methods[i].codeAttr.sourceLineTable
+			if fullyParsedClass.methods[i].codeAttr.sourceLineTable != nil {
+				kdm.CodeAttr.BytecodeSourceMap = *fullyParsedClass.methods[i].codeAttr.sourceLineTable
+			}
 
 			if len(fullyParsedClass.methods[i].attributes) > 0 {
 				for n := 0; n < len(fullyParsedClass.methods[i].attributes); n++ {
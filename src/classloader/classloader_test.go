@@ -350,6 +350,33 @@ func TestInvalidMagicNumberViaParseAndPostFunction(t *testing.T) {
 	}
 }
 
+// with -verbose:class, each loaded class is reported on stderr in the HotSpot format
+func TestVerboseClassTraceOfLoadedClass(t *testing.T) {
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	globals.InitGlobals("test")
+	log.Init()
+	_ = log.SetLogLevel(log.WARNING)
+	InitMethodArea()
+	globals.GetGlobalRef().VerboseClass = true
+
+	_, err := loadClassFromBytes(AppCL, "Hello2.class", "file:/tmp/Hello2.class", Hello2Bytes)
+	globals.GetGlobalRef().VerboseClass = false
+
+	_ = w.Close()
+	msg, _ := io.ReadAll(r)
+	os.Stderr = normalStderr
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading Hello2: %s", err.Error())
+	}
+	if !strings.Contains(string(msg), "[Loaded Hello2 from file:/tmp/Hello2.class]") {
+		t.Errorf("Did not get expected -verbose:class trace, got: %s", string(msg))
+	}
+}
+
 var Hello2Bytes = []byte{
 	0xCA, 0xFE, 0xBA, 0xBE, 0x00, 0x00, 0x00, 0x37, 0x00, 0x2B, 0x07, 0x00, 0x02, 0x01, 0x00, 0x06,
 	0x48, 0x65, 0x6C, 0x6C, 0x6F, 0x32, 0x07, 0x00, 0x04, 0x01, 0x00, 0x10, 0x6A, 0x61, 0x76, 0x61,
@@ -411,3 +438,51 @@ func TestLoadFullyParsedClass(t *testing.T) {
 		t.Errorf("Invalid number of methods in Hello2.class: %d", len(classToPost.Methods))
 	}
 }
+
+// loading a class that's already loaded reuses the loaded class, so its initialization state
+// and static fields are kept and its <clinit> is not run again
+func TestLoadingSameClassTwiceReusesLoadedClass(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	_ = log.SetLogLevel(log.WARNING)
+	InitMethodArea()
+
+	cl := Classloader{Name: "app", Archives: make(map[string]*Archive)}
+	firstIndex, err := ParseAndPostClass(&cl, "Hello2", Hello2Bytes)
+	if err != nil {
+		t.Fatalf("Unexpected error loading Hello2: %s", err.Error())
+	}
+	first := MethAreaFetch("Hello2")
+	if first == nil {
+		t.Fatalf("Hello2 is not in the method area after it was loaded")
+	}
+	first.Data.ClInit = types.ClInitRun // as though its <clinit> has been run
+	sizeAfterFirstLoad := MethAreaSize()
+
+	secondIndex, err := ParseAndPostClass(&cl, "Hello2", Hello2Bytes)
+	if err != nil {
+		t.Fatalf("Unexpected error loading Hello2 the second time: %s", err.Error())
+	}
+	if secondIndex != firstIndex {
+		t.Errorf("Expected the second load to return class name index %d, got %d", firstIndex, secondIndex)
+	}
+	if second := MethAreaFetch("Hello2"); second != first {
+		t.Errorf("Expected the second load to keep the Klass of the first")
+	}
+	if first.Data.ClInit != types.ClInitRun {
+		t.Errorf("Expected the loaded class to still be initialized, got ClInit status: %d", first.Data.ClInit)
+	}
+	if MethAreaSize() != sizeAfterFirstLoad {
+		t.Errorf("Expected the method area size to stay %d, got %d", sizeAfterFirstLoad, MethAreaSize())
+	}
+	if cl.ClassCount != 1 {
+		t.Errorf("Expected the classloader to count the class once, got %d", cl.ClassCount)
+	}
+	// the class is recognized as loaded from its name, before the rest of it is parsed, so even
+	// a copy that's cut short after its name is taken to be the loaded class
+	truncated := Hello2Bytes[:len(Hello2Bytes)-20]
+	if thirdIndex, err := ParseAndPostClass(&cl, "Hello2", truncated); err != nil || thirdIndex != firstIndex {
+		t.Errorf("Expected a truncated copy of the loaded class to return index %d, got %d (error: %v)",
+			firstIndex, thirdIndex, err)
+	}
+}
@@ -70,7 +70,7 @@ func WalkBaseJmod() error {
 		_ = rc.Close()
 
 		// Parse and post class into MethArea
-		ParseAndPostClass(&BootstrapCL, classFile.Name, classBytes)
+		_, _ = parseAndPostClassFrom(&BootstrapCL, classFile.Name, "jrt:/java.base", classBytes)
 
 	}
 
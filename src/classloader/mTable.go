@@ -47,6 +47,7 @@ type JmEntry struct {
 	Code        []byte
 	Exceptions  []CodeException
 	Attribs     []Attr
+	LineTable   []BytecodeToSourceLine // maps bytecode positions to source lines, sorted by position
 	params      []ParamAttrib
 	deprecated  bool
 	Cp          *CPool
@@ -80,16 +80,47 @@ func MethAreaFetch(key string) *Klass {
 }
 
 // MethAreaInsert adds a class to the method area, using a pointer to the parsed class.
+// An entry already present under the same name is replaced.
 func MethAreaInsert(name string, klass *Klass) {
 	_ = log.Log("MethAreaInsert: key("+name+")", log.CLASS)
 	MethAreaMutex.Lock()
+	if _, present := MethArea.Load(name); !present {
+		methAreaSize++
+	}
+	MethArea.Store(name, klass)
+	MethAreaMutex.Unlock()
+
+	if klass.Status == 'F' || klass.Status == 'V' || klass.Status == 'L' {
+		_ = log.Log("Method area insert: "+klass.Data.Name+", loader: "+klass.Loader, log.CLASS)
+	}
+}
+
+// MethAreaInsertIfAbsent adds a class to the method area, unless a class of the same name
+// has already been loaded there, in which case the loaded class is kept, so that its static
+// fields and initialization state are not lost. It returns the class that is in the method
+// area after the call and whether it's the class that was passed in. An entry whose load
+// is still in progress (status 'I') is replaced.
+func MethAreaInsertIfAbsent(name string, klass *Klass) (*Klass, bool) {
+	MethAreaMutex.Lock()
+	v, present := MethArea.Load(name)
+	if present {
+		loaded := v.(*Klass)
+		if loaded.Status != 'I' && loaded.Data != nil {
+			MethAreaMutex.Unlock()
+			_ = log.Log("MethAreaInsertIfAbsent: key("+name+") is already loaded", log.CLASS)
+			return loaded, false
+		}
+	} else {
+		methAreaSize++
+	}
 	MethArea.Store(name, klass)
-	methAreaSize++
 	MethAreaMutex.Unlock()
 
+	_ = log.Log("MethAreaInsertIfAbsent: key("+name+")", log.CLASS)
 	if klass.Status == 'F' || klass.Status == 'V' || klass.Status == 'L' {
 		_ = log.Log("Method area insert: "+klass.Data.Name+", loader: "+klass.Loader, log.CLASS)
 	}
+	return klass, true
 }
 
 // MethAreaSize returns the number of entries in MethArea. Because the golang's sync.Map
@@ -256,3 +256,27 @@ func TestMethArea42(t *testing.T) {
 	tryMethod(t, "java/io/BufferedOutputStream", "<init>", "(Ljava/io/OutputStream;I)V")
 	tryMethod(t, "java/io/InputStream", "<init>", "()V")
 }
+
+func TestMethAreaInsertIfAbsent(t *testing.T) {
+	MethArea = &sync.Map{}
+	methAreaSize = 0
+
+	loading := Klass{Status: 'I'} // a placeholder for a class whose load is in progress
+	MethAreaInsert("TestEntry", &loading)
+
+	first := Klass{Status: 'F', Loader: "testloader", Data: &ClData{Name: "TestEntry"}}
+	if k, inserted := MethAreaInsertIfAbsent("TestEntry", &first); !inserted || k != &first {
+		t.Errorf("Expected the class to replace the placeholder of its load")
+	}
+
+	second := Klass{Status: 'F', Loader: "testloader", Data: &ClData{Name: "TestEntry"}}
+	if k, inserted := MethAreaInsertIfAbsent("TestEntry", &second); inserted || k != &first {
+		t.Errorf("Expected the already-loaded class to be kept")
+	}
+	if MethAreaFetch("TestEntry") != &first {
+		t.Errorf("Expected the method area to hold the first class")
+	}
+	if MethAreaSize() != 1 {
+		t.Errorf("Expecting MethArea size of 1, got: %d", MethAreaSize())
+	}
+}
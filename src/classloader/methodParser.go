@@ -237,14 +237,12 @@ func buildLineNumberTable(codeAttr *codeAttrib, thisAttr *attr, methodName strin
 	entryCount := uint(thisAttr.attrContent[0])*256 + uint(thisAttr.attrContent[1])
 	loc := 2 // we're two bytes into the attr.Content byte array
 	if entryCount < 1 {
-		(*codeAttr).sourceLineTable = nil
 		return
 	}
 
 	var table []BytecodeToSourceLine
 	if (*codeAttr).sourceLineTable != nil { // we could be adding to the table
-		table = []BytecodeToSourceLine{}
-		(*codeAttr).sourceLineTable = &table
+		table = *(*codeAttr).sourceLineTable
 	}
 	var i uint
 	for i = 0; i < entryCount; i++ {
@@ -268,6 +266,20 @@ func buildLineNumberTable(codeAttr *codeAttrib, thisAttr *attr, methodName strin
 	// }
 }
 
+// SourceLineForPC returns the source line of the bytecode at PC, using a table sorted
+// by buildLineNumberTable(). That's the line of the last entry that starts at or
+// before PC. Returns -1 if the table has no such entry.
+func SourceLineForPC(table []BytecodeToSourceLine, PC int) int {
+	line := -1
+	for _, entry := range table {
+		if int(entry.BytecodePos) > PC {
+			break
+		}
+		line = int(entry.SourceLine)
+	}
+	return line
+}
+
 // the following four lines are all needed for the call to Sort()
 type b2sTable []BytecodeToSourceLine
 
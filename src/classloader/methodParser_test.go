@@ -331,3 +331,29 @@ func TestValidMethodParameterAttribute(t *testing.T) {
 		t.Error("MethodParameter name: " + mp.name + " is not a valid unqualified name")
 	}
 }
+
+// a method can have more than one LineNumberTable attribute. Their entries go into a single
+// table, sorted by bytecode position, that maps a PC to the line of the last entry at or before it.
+func TestLineNumberTablesMergedAndSearched(t *testing.T) {
+	ca := codeAttrib{}
+	first := attr{attrContent: []byte{0, 2, 0, 8, 0, 12, 0, 0, 0, 10}} // PC 8: line 12, PC 0: line 10
+	second := attr{attrContent: []byte{0, 1, 0, 4, 0, 11}}             // PC 4: line 11
+	buildLineNumberTable(&ca, &first, "test")
+	buildLineNumberTable(&ca, &second, "test")
+
+	if ca.sourceLineTable == nil || len(*ca.sourceLineTable) != 3 {
+		t.Fatalf("Expected a line number table with 3 entries, got: %v", ca.sourceLineTable)
+	}
+
+	table := *ca.sourceLineTable
+	expected := map[int]int{0: 10, 3: 10, 4: 11, 7: 11, 8: 12, 20: 12}
+	for pc, line := range expected {
+		if got := SourceLineForPC(table, pc); got != line {
+			t.Errorf("Expected PC %d to be on line %d, got: %d", pc, line, got)
+		}
+	}
+
+	if got := SourceLineForPC(nil, 0); got != -1 {
+		t.Errorf("Expected -1 for an empty line number table, got: %d", got)
+	}
+}
@@ -24,97 +24,112 @@ import (
 //
 // ClassFormatError - if the parser finds anything unexpected
 func parse(rawBytes []byte) (ParsedClass, error) {
+	pClass, pos, err := parseThroughClassName(rawBytes)
+	if err != nil {
+		return pClass, err
+	}
+	return pClass, parseRest(rawBytes, pos, &pClass)
+}
+
+// parseThroughClassName parses the start of a class file, through the name of the class, so
+// that the class can be identified before the rest of it is parsed. It returns the position
+// of the superclass entry, at which parseRest continues.
+func parseThroughClassName(rawBytes []byte) (ParsedClass, int, error) {
 
 	// the parsed class as we'll give it to the classloader
 	var pClass = ParsedClass{}
 
 	err := parseMagicNumber(rawBytes)
 	if err != nil {
-		return pClass, err
+		return pClass, 0, err
 	}
 
 	err = parseJavaVersionNumber(rawBytes, &pClass)
 	if err != nil {
-		return pClass, err
+		return pClass, 0, err
 	}
 
 	err = getConstantPoolCount(rawBytes, &pClass)
 	if err != nil {
-		return pClass, err
+		return pClass, 0, err
 	}
 
 	pos, err := parseConstantPool(rawBytes, &pClass)
 	if err != nil || pos < 10 {
-		return pClass, err
+		if err == nil {
+			err = errors.New("invalid constant pool")
+		}
+		return pClass, 0, err
 	}
 
 	pos, err = parseAccessFlags(rawBytes, pos, &pClass)
 	if err != nil {
-		return pClass, err
+		return pClass, 0, err
 	}
 
 	pos, err = parseClassName(rawBytes, pos, &pClass)
-	if err != nil {
-		return pClass, err
-	}
+	return pClass, pos, err
+}
 
-	pos, err = parseSuperClassName(rawBytes, pos, &pClass)
+// parseRest parses the rest of a class file, after parseThroughClassName, into pClass
+func parseRest(rawBytes []byte, pos int, pClass *ParsedClass) error {
+	pos, err := parseSuperClassName(rawBytes, pos, pClass)
 	if err != nil {
-		return pClass, err
+		return err
 	}
 
-	pos, err = parseInterfaceCount(rawBytes, pos, &pClass)
+	pos, err = parseInterfaceCount(rawBytes, pos, pClass)
 	if err != nil {
-		return pClass, err
+		return err
 	}
 
 	if pClass.interfaceCount > 0 {
-		pos, err = parseInterfaces(rawBytes, pos, &pClass)
+		pos, err = parseInterfaces(rawBytes, pos, pClass)
 		if err != nil {
-			return pClass, err
+			return err
 		}
 	}
 
-	pos, err = parseFieldCount(rawBytes, pos, &pClass)
+	pos, err = parseFieldCount(rawBytes, pos, pClass)
 	if err != nil {
-		return pClass, err
+		return err
 	}
 
 	if pClass.fieldCount > 0 {
-		pos, err = parseFields(rawBytes, pos, &pClass)
+		pos, err = parseFields(rawBytes, pos, pClass)
 		if err != nil {
-			return pClass, err
+			return err
 		}
 	}
 
-	pos, err = parseMethodCount(rawBytes, pos, &pClass)
+	pos, err = parseMethodCount(rawBytes, pos, pClass)
 	if err != nil {
-		return pClass, err
+		return err
 	}
 
 	if pClass.methodCount > 0 {
-		pos, err = parseMethods(rawBytes, pos, &pClass)
+		pos, err = parseMethods(rawBytes, pos, pClass)
 		if err != nil {
-			return pClass, err
+			return err
 		}
 	}
 
-	pos, err = parseClassAttributeCount(rawBytes, pos, &pClass)
+	pos, err = parseClassAttributeCount(rawBytes, pos, pClass)
 	if err != nil {
-		return pClass, err
+		return err
 	}
 
 	if pClass.attribCount > 0 {
-		pos, err = parseClassAttributes(rawBytes, pos, &pClass)
+		pos, err = parseClassAttributes(rawBytes, pos, pClass)
 	}
 	if err != nil {
-		return pClass, err
+		return err
 	}
 
 	if pos != len(rawBytes)-1 {
-		return pClass, cfe("Unexpected bytes found at end of class file: " + pClass.className)
+		return cfe("Unexpected bytes found at end of class file: " + pClass.className)
 	}
-	return pClass, nil
+	return nil
 }
 
 // all bytecode files start with 0xCAFEBABE ( it was the 90s!)
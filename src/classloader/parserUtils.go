@@ -136,6 +136,13 @@ func fetchAttribute(klass *ParsedClass, bytes []byte, loc int) (attr, int, error
 	}
 	attribute.attrSize = length
 
+	// Attributes the JVM doesn't use, such as RuntimeVisibleAnnotations, are skipped using
+	// their length, so a length that runs past the end of the class would misalign the parse
+	if pos+1+length > len(bytes) {
+		return attribute, pos, cfe("attribute " + klass.utf8Refs[nameSlot].content +
+			" has a length of " + strconv.Itoa(length) + ", which is longer than the rest of the class")
+	}
+
 	b := make([]byte, length)
 	for i := 0; i < length; i++ {
 		b[i] = bytes[pos+1+i]
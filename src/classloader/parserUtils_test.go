@@ -335,6 +335,38 @@ func TestFetchValidAttribute(t *testing.T) {
 	}
 }
 
+// an attribute whose length runs past the end of the class is a format error, not a panic
+func TestFetchTruncatedAttribute(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+
+	// redirect stderr to capture the error message
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	klass := ParsedClass{}
+	klass.cpIndex = append(klass.cpIndex, cpEntry{})
+	klass.cpIndex = append(klass.cpIndex, cpEntry{1, 0}) // UTF-8 rec w/ attribute name
+	klass.utf8Refs = append(klass.utf8Refs, utf8Entry{"RuntimeVisibleAnnotations"})
+	klass.cpCount = 2
+
+	// a name index of 01 and a length of 6, followed by only 2 of the 6 bytes
+	bytes := []byte{00, 00, 01, 00, 00, 00, 06, 00, 01}
+	_, _, err := fetchAttribute(&klass, bytes, 0)
+
+	_ = w.Close()
+	_, _ = io.ReadAll(r)
+	os.Stderr = normalStderr
+
+	if err == nil {
+		t.Fatal("Expected an error fetching a truncated attribute, but got none")
+	}
+	if !strings.Contains(err.Error(), "attribute RuntimeVisibleAnnotations has a length of 6") {
+		t.Errorf("Unexpected error message for a truncated attribute: %s", err.Error())
+	}
+}
+
 func TestFetchInvalidUTF8Slot_Test0(t *testing.T) {
 	globals.InitGlobals("test")
 	log.Init()
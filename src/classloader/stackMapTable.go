@@ -0,0 +1,402 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"fmt"
+	"jacobin/opcodes"
+)
+
+// A lightweight verifier that uses the StackMapTable attribute of each method's Code attribute.
+// The StackMapTable holds a frame for each branch target and exception handler of the method:
+// the types of the local variables and the operand stack at that point in the bytecode. See:
+// https://docs.oracle.com/javase/specs/jvms/se17/html/jvms-4.html#jvms-4.7.4
+//
+// The frames are decoded and checked for consistency with the bytecode: each frame must be at
+// the start of an instruction and must fit in the method's max locals and max stack; the class
+// of an Object type must be a ClassRef in the CP; an Uninitialized type must point to a NEW;
+// the frame of an exception handler must have just the exception on its stack; and (for class
+// files of version 51, that is, Java 7, and later) every branch target and exception handler
+// must have a frame. The types are not inferred from the bytecode, so whether the instructions
+// themselves use the right types is not checked.
+//
+// As in HotSpot, which by default verifies only classes not loaded by the bootstrap
+// classloader, the classes of the JDK are trusted and not verified (see parseAndPostClassFrom).
+
+// the tags of the verification_type_info entries in a frame
+const (
+	vtTop               = 0
+	vtInteger           = 1
+	vtFloat             = 2
+	vtDouble            = 3
+	vtLong              = 4
+	vtNull              = 5
+	vtUninitializedThis = 6
+	vtObject            = 7
+	vtUninitialized     = 8
+)
+
+type verificationType struct {
+	tag       int
+	index     int    // for an Object, the CP index of its class; for an Uninitialized, the offset of its NEW
+	className string // for an Object implied by the method's descriptor, the name of its class
+}
+
+type stackMapFrame struct {
+	offset int // the bytecode offset to which the frame applies
+	locals []verificationType
+	stack  []verificationType
+}
+
+// verifyStackMaps verifies the methods of a class using their StackMapTables
+func verifyStackMaps(klass *ParsedClass) error {
+	if klass.javaVersion < 50 {
+		return nil // before Java 6, classes have no StackMapTables
+	}
+
+	for i := range klass.methods {
+		meth := &klass.methods[i]
+		if meth.codeAttr.code == nil {
+			continue // abstract and native methods have no code
+		}
+		if err := verifyMethodStackMap(klass, meth); err != nil {
+			methodName := klass.utf8Refs[meth.name].content
+			return verifyError(fmt.Sprintf("%s.%s%s: %s", klass.className, methodName,
+				klass.utf8Refs[meth.description].content, err.Error()))
+		}
+	}
+	return nil
+}
+
+// verifyMethodStackMap checks a method's StackMapTable against its bytecode
+func verifyMethodStackMap(klass *ParsedClass, meth *method) error {
+	ca := &meth.codeAttr
+	instructions, targets, err := scanBytecode(ca.code)
+	if err != nil {
+		return err
+	}
+
+	var frames []stackMapFrame
+	for _, att := range ca.attributes {
+		if klass.utf8Refs[att.attrName].content == "StackMapTable" {
+			if frames, err = decodeStackMapTable(att.attrContent, initialLocals(klass, meth)); err != nil {
+				return err
+			}
+		}
+	}
+
+	framesAt := make(map[int]*stackMapFrame)
+	for i := range frames {
+		frame := &frames[i]
+		if frame.offset >= len(ca.code) || !instructions[frame.offset] {
+			return fmt.Errorf("StackMapTable frame at offset %d is not at the start of an instruction", frame.offset)
+		}
+		if slotCount(frame.locals) > ca.maxLocals {
+			return fmt.Errorf("StackMapTable frame at offset %d has more locals than max locals (%d)",
+				frame.offset, ca.maxLocals)
+		}
+		if slotCount(frame.stack) > ca.maxStack {
+			return fmt.Errorf("StackMapTable frame at offset %d has a deeper stack than max stack (%d)",
+				frame.offset, ca.maxStack)
+		}
+		for _, vt := range append(append([]verificationType{}, frame.locals...), frame.stack...) {
+			if err = checkVerificationType(klass, ca.code, instructions, vt); err != nil {
+				return fmt.Errorf("StackMapTable frame at offset %d: %s", frame.offset, err.Error())
+			}
+		}
+		framesAt[frame.offset] = frame
+	}
+
+	for _, ex := range ca.exceptions {
+		frame, present := framesAt[ex.handlerPc]
+		if present && (len(frame.stack) != 1 || frame.stack[0].tag != vtObject) {
+			return fmt.Errorf("StackMapTable frame of the exception handler at offset %d "+
+				"must have just the exception on its stack", ex.handlerPc)
+		}
+		targets = append(targets, ex.handlerPc)
+	}
+
+	if klass.javaVersion >= 51 { // from Java 7 on, the frames are required
+		for _, target := range targets {
+			if _, present := framesAt[target]; !present {
+				return fmt.Errorf("no StackMapTable frame for the branch target at offset %d", target)
+			}
+		}
+	}
+	return nil
+}
+
+// decodeStackMapTable decodes the content of a StackMapTable attribute into full frames. Each
+// frame other than a full_frame is a change to the previous frame, and the first is a change
+// to the frame implied by the method's descriptor, whose locals are passed in.
+func decodeStackMapTable(content []byte, locals []verificationType) ([]stackMapFrame, error) {
+	pos := 0
+	next := func(count int) (int, error) {
+		if pos+count > len(content) {
+			return 0, fmt.Errorf("StackMapTable is truncated")
+		}
+		value := 0
+		for i := 0; i < count; i++ {
+			value = value<<8 | int(content[pos+i])
+		}
+		pos += count
+		return value, nil
+	}
+	nextTypes := func(count int) ([]verificationType, error) {
+		vts := make([]verificationType, 0, count)
+		for i := 0; i < count; i++ {
+			tag, err := next(1)
+			if err != nil {
+				return nil, err
+			}
+			vt := verificationType{tag: tag}
+			switch {
+			case tag == vtObject || tag == vtUninitialized:
+				if vt.index, err = next(2); err != nil {
+					return nil, err
+				}
+			case tag > vtUninitialized:
+				return nil, fmt.Errorf("invalid verification type tag %d in StackMapTable", tag)
+			}
+			vts = append(vts, vt)
+		}
+		return vts, nil
+	}
+
+	entryCount, err := next(2)
+	if err != nil {
+		return nil, err
+	}
+	frames := make([]stackMapFrame, 0, entryCount)
+	offset := -1
+	for i := 0; i < entryCount; i++ {
+		frameType, err := next(1)
+		if err != nil {
+			return nil, err
+		}
+
+		var delta int
+		var stack []verificationType
+		switch {
+		case frameType <= 63: // same_frame
+			delta = frameType
+		case frameType <= 127: // same_locals_1_stack_item_frame
+			delta = frameType - 64
+			stack, err = nextTypes(1)
+		case frameType <= 246:
+			return nil, fmt.Errorf("reserved frame type %d in StackMapTable", frameType)
+		case frameType == 247: // same_locals_1_stack_item_frame_extended
+			if delta, err = next(2); err == nil {
+				stack, err = nextTypes(1)
+			}
+		case frameType <= 250: // chop_frame
+			delta, err = next(2)
+			chopped := 251 - frameType
+			if chopped > len(locals) {
+				return nil, fmt.Errorf("StackMapTable chop_frame removes %d locals of %d", chopped, len(locals))
+			}
+			locals = locals[:len(locals)-chopped]
+		case frameType == 251: // same_frame_extended
+			delta, err = next(2)
+		case frameType <= 254: // append_frame
+			var appended []verificationType
+			if delta, err = next(2); err == nil {
+				appended, err = nextTypes(frameType - 251)
+			}
+			locals = append(append([]verificationType{}, locals...), appended...)
+		default: // full_frame
+			var count int
+			if delta, err = next(2); err == nil {
+				if count, err = next(2); err == nil {
+					if locals, err = nextTypes(count); err == nil {
+						if count, err = next(2); err == nil {
+							stack, err = nextTypes(count)
+						}
+					}
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		offset += delta + 1 // the offset of the first frame is its delta
+		frames = append(frames, stackMapFrame{offset: offset, locals: locals, stack: stack})
+	}
+
+	if pos != len(content) {
+		return nil, fmt.Errorf("StackMapTable has %d bytes after its last frame", len(content)-pos)
+	}
+	return frames, nil
+}
+
+// initialLocals returns the types of the locals at the start of a method: the object (unless
+// the method is static), followed by the parameters
+func initialLocals(klass *ParsedClass, meth *method) []verificationType {
+	var locals []verificationType
+	if meth.accessFlags&0x0008 == 0 { // if not static
+		if klass.utf8Refs[meth.name].content == "<init>" && klass.className != "java/lang/Object" {
+			locals = append(locals, verificationType{tag: vtUninitializedThis})
+		} else {
+			locals = append(locals, verificationType{tag: vtObject, className: klass.className})
+		}
+	}
+
+	desc := klass.utf8Refs[meth.description].content
+	for i := 1; i < len(desc) && desc[i] != ')'; i++ {
+		vt := verificationType{tag: vtObject}
+		switch desc[i] {
+		case 'B', 'C', 'I', 'S', 'Z':
+			vt.tag = vtInteger
+		case 'F':
+			vt.tag = vtFloat
+		case 'D':
+			vt.tag = vtDouble
+		case 'J':
+			vt.tag = vtLong
+		}
+		start := i
+		for desc[i] == '[' {
+			i++
+		}
+		if desc[i] == 'L' {
+			for i < len(desc) && desc[i] != ';' {
+				i++
+			}
+		}
+		if vt.tag == vtObject { // the internal name of the class: an array keeps its descriptor
+			if desc[start] == 'L' {
+				vt.className = desc[start+1 : i]
+			} else {
+				vt.className = desc[start : i+1]
+			}
+		}
+		locals = append(locals, vt)
+	}
+	return locals
+}
+
+// slotCount returns the number of slots the types take up: two for a long or a double, else one
+func slotCount(vts []verificationType) int {
+	count := 0
+	for _, vt := range vts {
+		count++
+		if vt.tag == vtLong || vt.tag == vtDouble {
+			count++
+		}
+	}
+	return count
+}
+
+// checkVerificationType checks that the class of an Object type read from the StackMapTable
+// is a ClassRef, and that the offset of an Uninitialized type is that of a NEW instruction.
+// The Object types implied by the method's descriptor carry their class name instead.
+func checkVerificationType(klass *ParsedClass, code []byte, instructions []bool, vt verificationType) error {
+	switch vt.tag {
+	case vtObject:
+		if vt.className == "" && (vt.index < 1 || vt.index >= len(klass.cpIndex) || klass.cpIndex[vt.index].entryType != ClassRef) {
+			return fmt.Errorf("the class of an Object type, CP entry %d, is not a ClassRef", vt.index)
+		}
+	case vtUninitialized:
+		if vt.index >= len(code) || !instructions[vt.index] || code[vt.index] != opcodes.NEW {
+			return fmt.Errorf("the offset of an Uninitialized type, %d, is not that of a NEW", vt.index)
+		}
+	}
+	return nil
+}
+
+// the number of bytes of operands of each instruction, other than TABLESWITCH,
+// LOOKUPSWITCH, and WIDE, whose operands vary in length
+var operandBytes = func() [opcodes.JSR_W + 1]int {
+	var lengths [opcodes.JSR_W + 1]int
+	for _, op := range []int{opcodes.BIPUSH, opcodes.LDC, opcodes.ILOAD, opcodes.LLOAD, opcodes.FLOAD,
+		opcodes.DLOAD, opcodes.ALOAD, opcodes.ISTORE, opcodes.LSTORE, opcodes.FSTORE, opcodes.DSTORE,
+		opcodes.ASTORE, opcodes.RET, opcodes.NEWARRAY} {
+		lengths[op] = 1
+	}
+	for op := opcodes.IFEQ; op <= opcodes.JSR; op++ { // the conditional branches, GOTO, and JSR
+		lengths[op] = 2
+	}
+	for op := opcodes.GETSTATIC; op <= opcodes.INVOKESTATIC; op++ { // the field and method instructions
+		lengths[op] = 2
+	}
+	for _, op := range []int{opcodes.SIPUSH, opcodes.LDC_W, opcodes.LDC2_W, opcodes.IINC, opcodes.NEW,
+		opcodes.ANEWARRAY, opcodes.CHECKCAST, opcodes.INSTANCEOF, opcodes.IFNULL, opcodes.IFNONNULL} {
+		lengths[op] = 2
+	}
+	lengths[opcodes.MULTIANEWARRAY] = 3
+	for _, op := range []int{opcodes.INVOKEINTERFACE, opcodes.INVOKEDYNAMIC, opcodes.GOTO_W, opcodes.JSR_W} {
+		lengths[op] = 4
+	}
+	return lengths
+}()
+
+// scanBytecode walks through a method's bytecode, returning which offsets are the start of an
+// instruction and the offsets to which its instructions can branch
+func scanBytecode(code []byte) ([]bool, []int, error) {
+	instructions := make([]bool, len(code))
+	var targets []int
+	int32At := func(pos int) int {
+		return int(int32(uint32(code[pos])<<24 | uint32(code[pos+1])<<16 | uint32(code[pos+2])<<8 | uint32(code[pos+3])))
+	}
+
+	for pc := 0; pc < len(code); {
+		instructions[pc] = true
+		op := int(code[pc])
+		length := 1
+		switch {
+		case op > opcodes.JSR_W:
+			return nil, nil, fmt.Errorf("invalid opcode 0x%02X at offset %d", op, pc)
+		case op == opcodes.TABLESWITCH || op == opcodes.LOOKUPSWITCH:
+			pos := pc + 4 - pc%4 // the operands are 4-byte aligned
+			if pos+12 > len(code) {
+				return nil, nil, fmt.Errorf("truncated switch at offset %d", pc)
+			}
+			targets = append(targets, pc+int32At(pos)) // the default
+			var count, stride int
+			if op == opcodes.TABLESWITCH { // default, low, high, then the offsets
+				count, stride = int32At(pos+8)-int32At(pos+4)+1, 4
+				pos += 12
+			} else { // default, npairs, then the match-offset pairs
+				count, stride = int32At(pos+4), 8
+				pos += 8
+			}
+			if count < 0 || pos+count*stride > len(code) {
+				return nil, nil, fmt.Errorf("truncated switch at offset %d", pc)
+			}
+			for i := 0; i < count; i++ {
+				targets = append(targets, pc+int32At(pos+i*stride+stride-4))
+			}
+			length = pos + count*stride - pc
+		case op == opcodes.WIDE:
+			length = 4
+			if pc+1 < len(code) && code[pc+1] == opcodes.IINC {
+				length = 6
+			}
+		default:
+			length += operandBytes[op]
+		}
+		if pc+length > len(code) {
+			return nil, nil, fmt.Errorf("truncated instruction at offset %d", pc)
+		}
+
+		switch {
+		case op >= opcodes.IFEQ && op <= opcodes.JSR, op == opcodes.IFNULL, op == opcodes.IFNONNULL:
+			targets = append(targets, pc+int(int16(uint16(code[pc+1])<<8|uint16(code[pc+2]))))
+		case op == opcodes.GOTO_W || op == opcodes.JSR_W:
+			targets = append(targets, pc+int32At(pc+1))
+		}
+		pc += length
+	}
+
+	for _, target := range targets {
+		if target < 0 || target >= len(code) || !instructions[target] {
+			return nil, nil, fmt.Errorf("branch to offset %d, which is not the start of an instruction", target)
+		}
+	}
+	return instructions, targets, nil
+}
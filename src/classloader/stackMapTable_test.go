@@ -0,0 +1,169 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package classloader
+
+import (
+	"bytes"
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// the StackMapTable of Hello2.main(): 2 frames, a full_frame at offset 5 whose locals are
+// String[], Top, and int, and a same_frame at offset 23 (5 + 17 + 1)
+var hello2StackMapTable = []byte{0x00, 0x02, 0xFF, 0x00, 0x05, 0x00, 0x03, 0x07, 0x00, 0x26,
+	0x00, 0x01, 0x00, 0x00, 0x11}
+
+// hello2WithStackMapTableByte returns a copy of Hello2Bytes in which the byte at the given
+// position in main()'s StackMapTable is changed to the given value
+func hello2WithStackMapTableByte(t *testing.T, pos int, value byte) []byte {
+	start := bytes.Index(Hello2Bytes, hello2StackMapTable)
+	if start < 0 {
+		t.Fatal("Hello2Bytes does not contain the expected StackMapTable")
+	}
+	classBytes := append([]byte{}, Hello2Bytes...)
+	classBytes[start+pos] = value
+	return classBytes
+}
+
+func TestStackMapTableOfHello2(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+
+	klass, err := parse(Hello2Bytes)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing Hello2: %s", err.Error())
+	}
+	if err = verifyStackMaps(&klass); err != nil {
+		t.Errorf("Unexpected error verifying Hello2: %s", err.Error())
+	}
+
+	frames, err := decodeStackMapTable(hello2StackMapTable, []verificationType{{tag: vtObject, index: 0x26}})
+	if err != nil {
+		t.Fatalf("Unexpected error decoding the StackMapTable of Hello2.main(): %s", err.Error())
+	}
+	if len(frames) != 2 || frames[0].offset != 5 || frames[1].offset != 23 {
+		t.Fatalf("Expected frames at offsets 5 and 23, got: %v", frames)
+	}
+	if len(frames[1].locals) != 3 || frames[1].locals[2].tag != vtInteger || len(frames[1].stack) != 0 {
+		t.Errorf("Expected the same_frame to keep the full_frame's locals and an empty stack, got: %v",
+			frames[1])
+	}
+}
+
+func TestStackMapTableAppendAndChopFrames(t *testing.T) {
+	table := []byte{
+		0x00, 0x03,
+		0xFD, 0x00, 0x02, 0x01, 0x04, // append_frame at 2: adds an int and a long
+		0xFA, 0x00, 0x03, // chop_frame at 6: removes the long
+		0x41, 0x07, 0x00, 0x01, // same_locals_1_stack_item_frame at 8: an Object on the stack
+	}
+	frames, err := decodeStackMapTable(table, []verificationType{{tag: vtObject, index: 1}})
+	if err != nil {
+		t.Fatalf("Unexpected error decoding the StackMapTable: %s", err.Error())
+	}
+	if len(frames) != 3 || frames[0].offset != 2 || frames[1].offset != 6 || frames[2].offset != 8 {
+		t.Fatalf("Expected frames at offsets 2, 6, and 8, got: %v", frames)
+	}
+	if slotCount(frames[0].locals) != 4 || len(frames[1].locals) != 2 || len(frames[2].stack) != 1 {
+		t.Errorf("Unexpected locals or stack in the frames: %v", frames)
+	}
+
+	// chopping more locals than there are is an error
+	if _, err = decodeStackMapTable([]byte{0x00, 0x01, 0xF8, 0x00, 0x00}, nil); err == nil {
+		t.Error("Expected an error for a chop_frame that removes a missing local, but got none")
+	}
+}
+
+func TestCorruptedStackMapTableIsAVerifyError(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+
+	tests := []struct {
+		name  string
+		pos   int
+		value byte
+		want  string
+	}{
+		{"frame between instructions", 14, 0x13, "not at the start of an instruction"},
+		{"branch target without a frame", 14, 0x12, "no StackMapTable frame for the branch target at offset 23"},
+		{"invalid tag", 11, 0x09, "invalid verification type tag"},
+		{"too many frames", 1, 0x03, "truncated"},
+		{"too many locals", 11, vtDouble, "more locals than max locals"},
+		{"Object type that's not a class", 9, 0x25, "is not a ClassRef"},
+	}
+	for _, test := range tests {
+		klass, err := parse(hello2WithStackMapTableByte(t, test.pos, test.value))
+		if err != nil {
+			t.Fatalf("%s: unexpected error parsing the class: %s", test.name, err.Error())
+		}
+		err = verifyStackMaps(&klass)
+		if err == nil {
+			t.Errorf("%s: expected a VerifyError, but got none", test.name)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "VerifyError: Hello2.main") || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: expected a VerifyError containing %q, got: %s", test.name, test.want, err.Error())
+		}
+	}
+}
+
+// a class that fails verification is not loaded, and a VerifyError is thrown
+func TestCorruptedStackMapTableViaParseAndPostFunction(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	_ = log.SetLogLevel(log.WARNING)
+	InitMethodArea()
+
+	thrown := -1
+	globals.GetGlobalRef().FuncThrowException = func(which int, msg string) {
+		thrown = which
+	}
+
+	_, err := ParseAndPostClass(&AppCL, "Hello2", hello2WithStackMapTableByte(t, 14, 0x13))
+	if err == nil {
+		t.Fatal("Expected an error loading a class with a corrupted StackMapTable, but got none")
+	}
+	if thrown != excNames.VerifyError {
+		t.Errorf("Expected a VerifyError to be thrown, got exception: %d", thrown)
+	}
+	if MethAreaFetch("Hello2") != nil {
+		t.Error("Expected Hello2 not to be posted to the method area")
+	}
+}
+
+// the javac-compiled classes in testdata, whose StackMapTable frames keep the object and the
+// Object-typed parameters of a method as locals, load and verify
+func TestTestdataClassesVerifyViaParseAndPostFunction(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	_ = log.SetLogLevel(log.WARNING)
+	InitMethodArea()
+
+	globals.GetGlobalRef().FuncThrowException = func(which int, msg string) {
+		t.Errorf("Unexpected exception %d: %s", which, msg)
+	}
+
+	for _, name := range []string{"Hello", "Hello2", "Hello3", "ListTest", "lookupswitch", "tableswitch"} {
+		filename := filepath.Join("..", "..", "testdata", name+".class")
+		rawBytes, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("Unable to read %s: %s", filename, err.Error())
+		}
+		if _, err = parseAndPostClassFrom(&AppCL, filename, "", rawBytes); err != nil {
+			t.Errorf("Unexpected error loading %s: %s", name, err.Error())
+			continue
+		}
+		if klass := MethAreaFetch(name); klass == nil || klass.Status != 'V' {
+			t.Errorf("Expected %s to be posted to the method area as verified, got: %v", name, klass)
+		}
+	}
+}
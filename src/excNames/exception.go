@@ -51,6 +51,7 @@ const (
 	IllegalCallerException
 	IllegalFormatCodePointException
 	IllegalFormatConversionException
+	IllegalFormatFlagsException
 	IllegalMonitorStateException
 	IllegalPathStateException
 	IllegalStateException
@@ -76,6 +77,8 @@ const (
 	MalformedParameterizedTypeException
 	MalformedParametersException // for HotSpot reflection: param count wrong, CP index invalid, illegal flag combo
 	MirroredTypesException
+	MissingFormatArgumentException
+	MissingFormatWidthException
 	MissingResourceException
 	NativeMethodException
 	NegativeArraySizeException
@@ -85,6 +88,7 @@ const (
 	NullPointerException
 	NumberFormatException
 	ObjectCollectedException
+	PatternSyntaxException
 	ProfileDataException
 	ProviderException
 	ProviderNotFoundException
@@ -98,6 +102,7 @@ const (
 	UncheckedIOException
 	UndeclaredThrowableException
 	UnknownEntityException
+	UnknownFormatConversionException
 	UnmodifiableModuleException
 	UnmodifiableSetException
 	UnsupportedOperationException
@@ -159,6 +164,7 @@ const (
 	NamingException
 	NoninvertibleTransformException
 	NoSuchFieldException
+	NoSuchFileException
 	NoSuchMethodException
 	NotBoundException
 	ParseException
@@ -195,6 +201,7 @@ const (
 	XMLStreamException
 
 	// Java errors
+	AbstractMethodError // for invocations of a method that has no implementation
 	AnnotationFormatError
 	AssertionError
 	AWTError
@@ -272,6 +279,7 @@ var JVMexceptionNames = []string{
 	"java.lang.IllegalCallerException",                       // VERIFIED
 	"java.util.IllegalFormatCodePointException",              // VERIFIED
 	"java.util.IllegalFormatConversionException",             // VERIFIED ** got this far in java.util
+	"java.util.IllegalFormatFlagsException",                  // VERIFIED
 	"java.lang.IllegalMonitorStateException",                 // VERIFIED
 	"java.awt.geom.IllegalPathStateException",                // VERIFIED
 	"java.lang.IllegalStateException",                        // VERIFIED
@@ -297,6 +305,8 @@ var JVMexceptionNames = []string{
 	"java.lang.reflect.MalformedParameterizedTypeException",  // VERIFIED
 	"java.lang.reflect.MalformedParametersException",         // VERIFIED
 	"javax.lang.model.type.MirroredTypesException",           // VERIFIED
+	"java.util.MissingFormatArgumentException",               // VERIFIED
+	"java.util.MissingFormatWidthException",                  // VERIFIED
 	"java.util.MissingResourceException",                     // VERIFIED
 	"com.sun.jdi.NativeMethodException",                      // VERIFIED
 	"java.lang.NegativeArraySizeException",                   // VERIFIED
@@ -306,6 +316,7 @@ var JVMexceptionNames = []string{
 	"java.lang.NullPointerException",                         // VERIFIED
 	"java.lang.NumberFormatException",                        // VERIFIED
 	"com.sun.jdi.ObjectCollectedException",                   // VERIFIED
+	"java.util.regex.PatternSyntaxException",                 // VERIFIED
 	"java.awt.color.ProfileDataException",                    // VERIFIED
 	"java.security.ProviderException",                        // VERIFIED
 	"java.nio.file.ProviderNotFoundException",                // VERIFIED
@@ -319,6 +330,7 @@ var JVMexceptionNames = []string{
 	"java.io.UncheckedIOException",                           // VERIFIED
 	"java.lang.reflect.UndeclaredThrowableException",         // VERIFIED
 	"javax.lang.model.UnknownEntityException",                // VERIFIED
+	"java.util.UnknownFormatConversionException",             // VERIFIED
 	"java.lang.instrument.UnmodifiableModuleException",       // VERIFIED
 	"javax.print.attribute.UnmodifiableSetException",         // VERIFIED
 	"java.lang.UnsupportedOperationException",                // VERIFIED
@@ -380,6 +392,7 @@ var JVMexceptionNames = []string{
 	"javax.naming.NamingException",                              // VERIFIED
 	"java.awt.geom.NoninvertibleTransformException",             // VERIFIED
 	"java.lang.NoSuchFieldException",                            // VERIFIED
+	"java.nio.file.NoSuchFileException",                         // VERIFIED
 	"java.lang.NoSuchMethodException",                           // VERIFIED
 	"java.rmi.NotBoundException",                                // VERIFIED
 	"java.text.ParseException",                                  // VERIFIED
@@ -416,6 +429,7 @@ var JVMexceptionNames = []string{
 	"javax.xml.stream.XMLStreamException",                       // VERIFIED
 
 	// Java errors
+	"java.lang.AbstractMethodError",                            // VERIFIED
 	"java.lang.annotation.AnnotationFormatError",               // VERIFIED
 	"java.lang.AssertionError",                                 // VERIFIED
 	"java.awt.AWTError",                                        // VERIFIED
@@ -18,6 +18,7 @@ func details(t *testing.T, index int, expected string) {
 func TestExceptionTableAlignment(t *testing.T) {
 	details(t, IllegalArgumentException, "java.lang.IllegalArgumentException")
 	details(t, NoSuchDynamicMethodException, "jdk.dynalink.NoSuchDynamicMethodException")
+	details(t, PatternSyntaxException, "java.util.regex.PatternSyntaxException")
 	details(t, WrongMethodTypeException, "java.lang.invoke.WrongMethodTypeException")
 	details(t, ClassNotLoadedException, "com.sun.jdi.ClassNotLoadedException")
 	details(t, InvalidTypeException, "com.sun.jdi.InvalidTypeException")
@@ -26,4 +27,7 @@ func TestExceptionTableAlignment(t *testing.T) {
 	details(t, XMLParseException, "javax.management.modelmbean.XMLParseException")
 	details(t, VirtualMachineError, "java.lang.VirtualMachineError")
 	details(t, UTFDataFormatException, "java.io.UTFDataFormatException")
+	details(t, IllegalFormatFlagsException, "java.util.IllegalFormatFlagsException")
+	details(t, MissingFormatWidthException, "java.util.MissingFormatWidthException")
+	details(t, UnknownFormatConversionException, "java.util.UnknownFormatConversionException")
 }
@@ -106,6 +106,10 @@ func ThrowEx(which int, msg string, f *frames.Frame) bool {
 			if fr == catchFrame {
 				break
 			} else {
+				if fr.(*frames.Frame).GfunctionCall {
+					catchFrame.UnwoundGfunctionCalls++
+				}
+				frames.ReleaseMonitor(fr.(*frames.Frame))
 				fs.Remove(fs.Front())
 			}
 		}
@@ -10,6 +10,7 @@ import (
 	"container/list"
 	"fmt"
 	"jacobin/log"
+	"jacobin/object"
 	"unsafe"
 )
 
@@ -37,17 +38,25 @@ func ftag(f *Frame) string {
 // second stack entry for these data items.
 type Frame struct {
 	Thread      int
-	MethName    string        // method name
-	MethType    string        // method type (signature)
-	ClName      string        // class name
-	Meth        []byte        // bytecode of method
-	CP          interface{}   // will hold a *classloader.CPool (constant pool ptr) but due to circularity must be done this way
-	Locals      []interface{} // local variables
-	OpStack     []interface{} // operand stack
-	TOS         int           // top of the operand stack
-	PC          int           // program counter (index into the bytecode of the method)
-	Ftype       byte          // type of method in frame: 'J' = java, 'G' = Golang, 'N' = native
-	ExceptionPC int           // program counter at the moment the PC threw an exception
+	MethName    string         // method name
+	MethType    string         // method type (signature)
+	ClName      string         // class name
+	Meth        []byte         // bytecode of method
+	CP          interface{}    // will hold a *classloader.CPool (constant pool ptr) but due to circularity must be done this way
+	LineTable   interface{}    // will hold the method's []classloader.BytecodeToSourceLine, for the same reason
+	Locals      []interface{}  // local variables
+	OpStack     []interface{}  // operand stack
+	TOS         int            // top of the operand stack
+	PC          int            // program counter (index into the bytecode of the method)
+	Ftype       byte           // type of method in frame: 'J' = java, 'G' = Golang, 'N' = native
+	ExceptionPC int            // program counter at the moment the PC threw an exception
+	Monitor     *object.Object // object whose monitor a synchronized method holds; nil if none
+
+	// a frame whose method a gfunction called (see jvm.invokeMethodFromGfunction) is marked as
+	// GfunctionCall. If an exception unwinds such frames, the frame that catches it counts them
+	// in UnwoundGfunctionCalls, so that the gfunctions' calls can be abandoned before it resumes.
+	GfunctionCall         bool
+	UnwoundGfunctionCalls int
 }
 
 // CreateFrameStack creates a stack of frames. Implemented as a list in which
@@ -127,3 +136,13 @@ func PeekFrame(fs *list.List, which int) *Frame {
 	}
 	return e.Value.(*Frame)
 }
+
+// ReleaseMonitor releases the monitor that a synchronized method acquired
+// on entry, if any. It is called whenever a frame is discarded, whether
+// by a normal return or by an exception that unwinds the frame stack.
+func ReleaseMonitor(f *Frame) {
+	if f.Monitor != nil {
+		_ = f.Monitor.MonitorExit(f.Thread)
+		f.Monitor = nil
+	}
+}
@@ -8,6 +8,9 @@ package gfunction
 
 import (
 	"jacobin/excNames"
+	"jacobin/types"
+	"jacobin/util"
+	"strings"
 )
 
 func Load_Traps() {
@@ -72,12 +75,6 @@ func Load_Traps() {
 			GFunction:  trapClass,
 		}
 
-	MethodSignatures["java/io/StringReader.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapClass,
-		}
-
 	MethodSignatures["java/io/FilterInputStream.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -120,12 +117,6 @@ func Load_Traps() {
 			GFunction:  trapClass,
 		}
 
-	MethodSignatures["java/io/StringWriter.<clinit>()V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapClass,
-		}
-
 	MethodSignatures["java/lang/SecurityManager.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -180,24 +171,6 @@ func Load_Traps() {
 			GFunction:  trapFunction,
 		}
 
-	MethodSignatures["java/lang/StringBuilder.<init>(I)V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapFunction,
-		}
-
-	MethodSignatures["java/lang/StringBuilder.<init>(Ljava/lang/CharSequence;)V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapFunction,
-		}
-
-	MethodSignatures["java/lang/StringBuilder.<init>(Ljava/lang/String;)V"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  trapFunction,
-		}
-
 	MethodSignatures["java/nio/charset/Charset.<clinit>()V"] =
 		GMeth{
 			ParamSlots: 0,
@@ -260,8 +233,32 @@ func trapUndocumented([]interface{}) interface{} {
 	return getGErrBlk(excNames.UnsupportedOperationException, errMsg)
 }
 
-// Generic trap for functions
+// Generic trap for functions. Like the other traps, it throws an UnsupportedOperationException,
+// which the program can catch. runGfunction() adds the full signature of the trapped method to
+// the exception's message, e.g., "... method: java/io/DefaultFileSystem.getFileSystem()Ljava/io/FileSystem;".
 func trapFunction([]interface{}) interface{} {
 	errMsg := "The requested function is not yet supported"
 	return getGErrBlk(excNames.UnsupportedOperationException, errMsg)
 }
+
+// trapMethods traps each of the given methods of a class, whose signatures are the method
+// names followed by their descriptors, as in "toString()Ljava/lang/String;". It's used for
+// the methods of a class whose state is kept by gfunctions rather than in the JDK's fields,
+// which the JDK's bytecode for the methods would otherwise run against.
+func trapMethods(className string, signatures ...string) {
+	for _, signature := range signatures {
+		paramSlots := 0
+		desc := signature[strings.Index(signature, "("):]
+		for _, param := range util.ParseIncomingParamsFromMethTypeString(desc) {
+			paramSlots++
+			if types.UsesTwoSlots(param) {
+				paramSlots++
+			}
+		}
+		MethodSignatures[className+"."+signature] =
+			GMeth{
+				ParamSlots: paramSlots,
+				GFunction:  trapFunction,
+			}
+	}
+}
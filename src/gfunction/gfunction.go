@@ -82,6 +82,8 @@ func MTableLoadGFunctions(MTable *classloader.MT) {
 	Load_Io_OutputStreamWriter()
 	Load_Io_PrintStream()
 	Load_Io_RandomAccessFile()
+	Load_Io_StringReader()
+	Load_Io_StringWriter()
 
 	// java/lang/*
 	Load_Lang_Boolean()
@@ -91,9 +93,12 @@ func MTableLoadGFunctions(MTable *classloader.MT) {
 	Load_Lang_Double()
 	Load_Lang_Float()
 	Load_Lang_Integer()
+	Load_Lang_Invoke_MethodHandles()
 	Load_Lang_Long()
 	Load_Lang_Math()
 	Load_Lang_Object()
+	Load_Lang_ProcessBuilder()
+	Load_Lang_Ref_Reference()
 	Load_Lang_Short()
 	Load_Lang_String()
 	Load_Lang_StringBuilder()
@@ -108,17 +113,27 @@ func MTableLoadGFunctions(MTable *classloader.MT) {
 
 	// java/nio/*
 	Load_Nio_Charset_Charset()
+	Load_Nio_File_Files()
+	Load_Nio_File_Paths()
 
 	// java/security/*
 	Load_Security_SecureRandom()
 
 	// java/util/*
+	Load_Util_ArrayDeque()
+	Load_Util_ArrayList()
+	Load_Util_Arrays()
+	Load_Util_Collections()
 	Load_Util_Concurrent_Atomic_AtomicInteger()
 	Load_Util_Concurrent_Atomic_Atomic_Long()
+	Load_Util_Concurrent_ConcurrentHashMap()
 	Load_Util_HashMap()
 	Load_Util_HexFormat()
 	Load_Util_Locale()
+	Load_Util_Map()
+	Load_Util_Properties()
 	Load_Util_Random()
+	Load_Util_Stream()
 
 	// jdk/internal/misc/*
 	Load_Jdk_Internal_Misc_Unsafe()
@@ -7,6 +7,7 @@
 package gfunction
 
 import (
+	"container/list"
 	"fmt"
 	"golang.org/x/term"
 	"jacobin/classloader"
@@ -46,15 +47,17 @@ func Load_Io_Console() {
 	// Console format.
 	MethodSignatures["java/io/Console.format(Ljava/lang/String;[Ljava/lang/Object;)Ljava/io/Console;"] =
 		GMeth{
-			ParamSlots: 2, // the format string, the parameters (if any)
-			GFunction:  consolePrintf,
+			ParamSlots:   2, // the format string, the parameters (if any)
+			GFunction:    consolePrintf,
+			NeedsContext: true,
 		}
 
 	// Console Printf.
 	MethodSignatures["java/io/Console.printf(Ljava/lang/String;[Ljava/lang/Object;)Ljava/io/Console;"] =
 		GMeth{
-			ParamSlots: 2, // the format string, the parameters (if any)
-			GFunction:  consolePrintf,
+			ParamSlots:   2, // the format string, the parameters (if any)
+			GFunction:    consolePrintf,
+			NeedsContext: true,
 		}
 
 	// Retrieves the unique Reader object associated with this console.
@@ -74,8 +77,9 @@ func Load_Io_Console() {
 	// Provides a formatted prompt, then reads a single line of text from the console.
 	MethodSignatures["java/io/Console.readLine(Ljava/lang/String;[Ljava/lang/Object;)Ljava/lang/String;"] =
 		GMeth{
-			ParamSlots: 2,
-			GFunction:  consolePrintfReadLine,
+			ParamSlots:   2,
+			GFunction:    consolePrintfReadLine,
+			NeedsContext: true,
 		}
 
 	// Reads a password or passphrase from the console with echoing disabled.
@@ -88,8 +92,9 @@ func Load_Io_Console() {
 	// Provides a formatted prompt, then reads a password or passphrase from the console.
 	MethodSignatures["java/io/Console.readPassword(Ljava/lang/String;[Ljava/lang/Object;)[C"] =
 		GMeth{
-			ParamSlots: 2,
-			GFunction:  consolePrintfReadPassword,
+			ParamSlots:   2,
+			GFunction:    consolePrintfReadPassword,
+			NeedsContext: true,
 		}
 
 	// Retrieves the unique PrintWriter object associated with this console.
@@ -125,11 +130,13 @@ func consoleFlush([]interface{}) interface{} {
 
 // Printf -- handle the variable args and then call golang's own printf function
 // "java/io/Console.format(Ljava/lang/String;[Ljava/lang/Object;)Ljava/io/Console;"
+// params[0] = the frame stack, params[1] = the Console, params[2] = the format string,
+// params[3] = the arguments
 func consolePrintf(params []interface{}) interface{} {
 	var intfSprintf = new([]interface{})
-	*intfSprintf = append(*intfSprintf, params[1])
 	*intfSprintf = append(*intfSprintf, params[2])
-	retval := StringFormatter(*intfSprintf)
+	*intfSprintf = append(*intfSprintf, params[3])
+	retval := StringFormatter(params[0].(*list.List), *intfSprintf)
 	switch retval.(type) {
 	case *object.Object:
 	default:
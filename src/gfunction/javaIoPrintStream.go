@@ -7,12 +7,13 @@
 package gfunction
 
 import (
+	"container/list"
 	"fmt"
 	"jacobin/excNames"
 	"jacobin/object"
-	"jacobin/types"
 	"math"
 	"os"
+	"strings"
 )
 
 /*
@@ -88,8 +89,9 @@ func Load_Io_PrintStream() {
 
 	MethodSignatures["java/io/PrintStream.println(Ljava/lang/Object;)V"] = // println object
 		GMeth{
-			ParamSlots: 1, // 1 slot for the Object
-			GFunction:  PrintlnObject,
+			ParamSlots:   1, // 1 slot for the Object
+			GFunction:    PrintlnObject,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/io/PrintStream.print(Ljava/lang/String;)V"] = // print string
@@ -142,14 +144,29 @@ func Load_Io_PrintStream() {
 
 	MethodSignatures["java/io/PrintStream.print(Ljava/lang/Object;)V"] = // print object
 		GMeth{
-			ParamSlots: 1, // 1 slot for the Object
-			GFunction:  PrintObject,
+			ParamSlots:   1, // 1 slot for the Object
+			GFunction:    PrintObject,
+			NeedsContext: true,
 		}
 
 	MethodSignatures["java/io/PrintStream.printf(Ljava/lang/String;[Ljava/lang/Object;)Ljava/io/PrintStream;"] =
 		GMeth{
-			ParamSlots: 2, // the format string, the parameters (if any)
-			GFunction:  Printf,
+			ParamSlots:   2, // the format string, the parameters (if any)
+			GFunction:    Printf,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/io/PrintStream.printf(Ljava/util/Locale;Ljava/lang/String;[Ljava/lang/Object;)Ljava/io/PrintStream;"] =
+		GMeth{
+			ParamSlots:   3, // the locale, the format string, the parameters (if any)
+			GFunction:    PrintfLocale,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/io/PrintStream.flush()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  PrintStreamFlush,
 		}
 
 }
@@ -165,10 +182,10 @@ func PrintlnString(params []interface{}) interface{} {
 	// Handle null strings as well as []byte.
 	fld := param1.FieldTable["value"]
 	if fld.Fvalue == nil {
-		fmt.Fprintln(params[0].(*os.File), "")
+		fmt.Fprint(params[0].(*os.File), lineSeparator())
 	} else {
 		str := string(fld.Fvalue.([]byte))
-		fmt.Fprintln(params[0].(*os.File), str)
+		fmt.Fprint(params[0].(*os.File), str, lineSeparator())
 	}
 
 	return nil
@@ -177,14 +194,14 @@ func PrintlnString(params []interface{}) interface{} {
 // PrintlnV = java/io/Prinstream.println() -- println() prints a newline (V = void)
 // "java/io/PrintStream.println()V"
 func PrintlnV(params []interface{}) interface{} {
-	fmt.Fprintln(params[0].(*os.File), "")
+	fmt.Fprint(params[0].(*os.File), lineSeparator())
 	return nil
 }
 
 // "java/io/PrintStream.println(C)V"
 func PrintlnChar(params []interface{}) interface{} {
 	cc := fmt.Sprint(params[1].(int64))
-	fmt.Fprintln(params[0].(*os.File), cc)
+	fmt.Fprint(params[0].(*os.File), cc, lineSeparator())
 	return nil
 }
 
@@ -193,7 +210,7 @@ func PrintlnChar(params []interface{}) interface{} {
 // "java/io/PrintStream.println(S)V"
 func PrintlnBIS(params []interface{}) interface{} {
 	intToPrint := params[1].(int64) // contains an int
-	fmt.Fprintln(params[0].(*os.File), intToPrint)
+	fmt.Fprint(params[0].(*os.File), intToPrint, lineSeparator())
 	return nil
 }
 
@@ -206,14 +223,14 @@ func PrintlnBoolean(params []interface{}) interface{} {
 	} else {
 		boolToPrint = false
 	}
-	fmt.Fprintln(params[0].(*os.File), boolToPrint)
+	fmt.Fprint(params[0].(*os.File), boolToPrint, lineSeparator())
 	return nil
 }
 
 // "java/io/PrintStream.println(J)V"
 func PrintlnLong(params []interface{}) interface{} {
 	longToPrint := params[1].(int64) // contains to an int64--the equivalent of a Java long
-	fmt.Fprintln(params[0].(*os.File), longToPrint)
+	fmt.Fprint(params[0].(*os.File), longToPrint, lineSeparator())
 	return nil
 }
 
@@ -222,24 +239,19 @@ func PrintlnLong(params []interface{}) interface{} {
 // "java/io/PrintStream.println(F)V"
 func PrintlnDoubleFloat(params []interface{}) interface{} {
 	doubleToPrint := params[1].(float64) // contains to a float64--the equivalent of a Java double
-	fmt.Fprintf(params[0].(*os.File), getDoubleFormat(doubleToPrint)+"\n", doubleToPrint)
+	fmt.Fprintf(params[0].(*os.File), getDoubleFormat(doubleToPrint)+lineSeparator(), doubleToPrint)
 	return nil
 }
 
 // Println an Object's contents
 // "java/io/PrintStream.println(Ljava/lang/Object;)V"
+// params[0] = the frame stack, params[1] = the PrintStream, params[2] = the object
 func PrintlnObject(params []interface{}) interface{} {
-	if params[1] == nil {
-		errMsg := fmt.Sprintf("Expected params[1] of type *object.Object but observed type %T\n", params[1])
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
-	}
-	objPtr := params[1].(*object.Object)
-	fld := objPtr.FieldTable["value"]
-	if fld.Ftype == types.ByteArray {
-		fmt.Fprintln(params[0].(*os.File), string(fld.Fvalue.([]byte)))
-		return nil
+	str, errBlk := printableObjectString(params)
+	if errBlk != nil {
+		return errBlk
 	}
-	fmt.Fprintln(params[0].(*os.File), fld.Fvalue)
+	fmt.Fprint(params[1].(*os.File), str, lineSeparator())
 	return nil
 }
 
@@ -316,30 +328,66 @@ func PrintString(params []interface{}) interface{} {
 	return nil
 }
 
+// System.out and System.err are the golang streams os.Stdout and os.Stderr, which do not
+// buffer their output: everything printed is written at once, so output is never held back
+// waiting for a newline (as it is never held back in the JDK's autoflushing console streams).
+// So, flush() needs only ask the OS to commit the output. Consoles and pipes can't be
+// synced, and PrintStream.flush() never throws an exception, so any error is ignored.
+// "java/io/PrintStream.flush()V"
+func PrintStreamFlush(params []interface{}) interface{} {
+	if osFile, ok := params[0].(*os.File); ok {
+		_ = osFile.Sync()
+	}
+	return nil
+}
+
 // Print an Object's contents
 // "java/io/PrintStream.print(Ljava/lang/Object;)V"
+// params[0] = the frame stack, params[1] = the PrintStream, params[2] = the object
 func PrintObject(params []interface{}) interface{} {
-	if params[1] == nil {
-		errMsg := fmt.Sprintf("Expected params[1] of type *object.Object but observed type %T\n", params[1])
-		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
-	}
-	objPtr := params[1].(*object.Object)
-	fld := objPtr.FieldTable["value"]
-	if fld.Ftype == types.ByteArray {
-		fmt.Fprint(params[0].(*os.File), string(fld.Fvalue.([]byte)))
-		return nil
+	str, errBlk := printableObjectString(params)
+	if errBlk != nil {
+		return errBlk
 	}
-	fmt.Fprint(params[0].(*os.File), fld.Fvalue)
+	fmt.Fprint(params[1].(*os.File), str)
 	return nil
 }
 
+// printableObjectString returns the string that print(Object) and println(Object) print, as
+// String.valueOf() does: "null" for a null object, and otherwise the value of its toString().
+// Strings and boxed primitives are printed directly, without running their Java methods, as
+// their toString() would print them (see formatJavaToString).
+func printableObjectString(params []interface{}) (string, *GErrBlk) {
+	if params[2] == nil {
+		errMsg := fmt.Sprintf("Expected params[2] of type *object.Object but observed type %T\n", params[2])
+		return "", getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	objPtr := params[2].(*object.Object)
+	if object.IsNull(objPtr) {
+		return "null", nil
+	}
+	if value, ok := boxedValue(objPtr); ok {
+		className := strings.ReplaceAll(object.GoStringFromStringPoolIndex(objPtr.KlassName), "/", ".")
+		switch className {
+		case "java.lang.Boolean":
+			value = value.(int64) != 0
+		case "java.lang.Character":
+			return string(rune(value.(int64))), nil
+		}
+		return fmt.Sprint(formatJavaToString(value, className)), nil
+	}
+	return formatterToString(params[0].(*list.List), objPtr)
+}
+
 // Printf -- handle the variable args and then call golang's own printf function
 // "java/io/PrintStream.printf(Ljava/lang/String;[Ljava/lang/Object;)Ljava/io/PrintStream;"
+// params[0] = the frame stack, params[1] = the PrintStream, params[2] = the format string,
+// params[3] = the arguments
 func Printf(params []interface{}) interface{} {
 	var intfSprintf = new([]interface{})
-	*intfSprintf = append(*intfSprintf, params[1])
 	*intfSprintf = append(*intfSprintf, params[2])
-	retval := StringFormatter(*intfSprintf)
+	*intfSprintf = append(*intfSprintf, params[3])
+	retval := StringFormatter(params[0].(*list.List), *intfSprintf)
 	switch retval.(type) {
 	case *object.Object:
 	default:
@@ -347,9 +395,15 @@ func Printf(params []interface{}) interface{} {
 	}
 	objPtr := retval.(*object.Object)
 	str := object.GoStringFromStringObject(objPtr)
-	fmt.Fprint(params[0].(*os.File), str)
-	return params[0] // Return the PrintStream object
+	fmt.Fprint(params[1].(*os.File), str)
+	return params[1] // Return the PrintStream object
+
+}
 
+// PrintfLocale -- as Printf, but preceded by a locale, which is ignored, as in String.format()
+// "java/io/PrintStream.printf(Ljava/util/Locale;Ljava/lang/String;[Ljava/lang/Object;)Ljava/io/PrintStream;"
+func PrintfLocale(params []interface{}) interface{} {
+	return Printf([]interface{}{params[0], params[1], params[3], params[4]})
 }
 
 // Trying to approximate the exact formatting used in HotSpot JVM
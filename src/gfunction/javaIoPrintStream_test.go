@@ -0,0 +1,106 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"io"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"os"
+	"testing"
+	"time"
+)
+
+// A partial line (one without a trailing newline) appears on stdout once flush() is called.
+// The pipe is read while it's still open, so the output must have been written by then, rather
+// than when the stream is closed.
+func TestPrintStreamFlushPartialLine(t *testing.T) {
+	globals.InitGlobals("test")
+
+	normalStdout := os.Stdout
+	rout, wout, _ := os.Pipe()
+	os.Stdout = wout
+	defer func() {
+		os.Stdout = normalStdout
+		_ = wout.Close()
+		_ = rout.Close()
+	}()
+
+	ret := PrintString([]interface{}{os.Stdout, object.StringObjectFromGoString("partial line")})
+	if ret != nil {
+		t.Errorf("PrintStream.print returned an unexpected error: %v", ret)
+	}
+	ret = PrintStreamFlush([]interface{}{os.Stdout})
+	if ret != nil {
+		t.Errorf("PrintStream.flush returned an unexpected error: %v", ret)
+	}
+
+	_ = rout.SetReadDeadline(time.Now().Add(time.Second))
+	out := make([]byte, len("partial line"))
+	n, err := io.ReadFull(rout, out)
+	if err != nil || string(out) != "partial line" {
+		t.Errorf("Expected the partial line on stdout before the stream was closed, got: %q (%v)",
+			string(out[:n]), err)
+	}
+}
+
+func TestPrintfLocaleGrouping(t *testing.T) {
+	globals.InitGlobals("test")
+
+	normalStdout := os.Stdout
+	rout, wout, _ := os.Pipe()
+	os.Stdout = wout
+
+	localeClass := "java/util/Locale"
+	localeUS := object.MakeEmptyObjectWithClassName(&localeClass)
+	classStr := "[Ljava/lang/Object"
+	args := object.MakeEmptyObjectWithClassName(&classStr)
+	args.FieldTable["value"] = object.Field{Ftype: classStr,
+		Fvalue: []*object.Object{object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(1000))}}
+
+	ret := PrintfLocale([]interface{}{frames.CreateFrameStack(), os.Stdout, localeUS, object.StringObjectFromGoString("total: %,d"), args})
+
+	_ = wout.Close()
+	os.Stdout = normalStdout
+	out, _ := io.ReadAll(rout)
+
+	if ret != wout {
+		t.Errorf("Expected printf to return the PrintStream, got: %v", ret)
+	}
+	if string(out) != "total: 1,000" {
+		t.Errorf("Expected \"total: 1,000\" on stdout, got: %q", string(out))
+	}
+}
+
+// print(Object) prints Strings and boxed primitives as their toString() would
+func TestPrintableObjectStringOfBoxedValues(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := frames.CreateFrameStack()
+
+	for _, test := range []struct {
+		obj      *object.Object
+		expected string
+	}{
+		{object.StringObjectFromGoString("text"), "text"},
+		{object.MakePrimitiveObject("java/lang/Boolean", types.Bool, int64(1)), "true"},
+		{object.MakePrimitiveObject("java/lang/Boolean", types.Bool, int64(0)), "false"},
+		{object.MakePrimitiveObject("java/lang/Character", types.Char, int64('A')), "A"},
+		{object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(-42)), "-42"},
+		{object.MakePrimitiveObject("java/lang/Long", types.Long, int64(1)<<40), "1099511627776"},
+		{object.MakePrimitiveObject("java/lang/Double", types.Double, 1.0), "1.0"},
+		{object.MakePrimitiveObject("java/lang/Double", types.Double, 1e21), "1.0E21"},
+		{object.MakePrimitiveObject("java/lang/Float", types.Float, float64(float32(0.1))), "0.1"},
+		{object.Null, "null"},
+	} {
+		str, errBlk := printableObjectString([]interface{}{fs, os.Stdout, test.obj})
+		if errBlk != nil || str != test.expected {
+			t.Errorf("Expected %q, got: %q (%v)", test.expected, str, errBlk)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/types"
+)
+
+// Implementation of java/io/StringReader. The UTF-16 chars of the string being read are kept
+// in the reader's "value" field, and the index of the next char to read in its "next" field.
+// Closing the reader removes the chars, after which reading throws an IOException.
+
+func Load_Io_StringReader() {
+
+	MethodSignatures["java/io/StringReader.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/io/StringReader.<init>(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringReaderInit,
+		}
+
+	MethodSignatures["java/io/StringReader.close()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringReaderClose,
+		}
+
+	MethodSignatures["java/io/StringReader.read()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringReaderReadOneChar,
+		}
+
+	MethodSignatures["java/io/StringReader.read([CII)I"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  stringReaderReadCharBufferSubset,
+		}
+
+}
+
+// "java/io/StringReader.<init>(Ljava/lang/String;)V"
+func stringReaderInit(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "StringReader: string is null")
+	}
+	units := stringUTF16(params[1].(*object.Object))
+	chars := make([]int64, len(units))
+	for i, unit := range units {
+		chars[i] = int64(unit)
+	}
+
+	reader := params[0].(*object.Object)
+	reader.FieldTable["value"] = object.Field{Ftype: types.CharArray, Fvalue: chars}
+	reader.FieldTable["next"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	return nil
+}
+
+// stringReaderChars returns the chars of an open reader and the index of the next one to read
+func stringReaderChars(reader *object.Object) ([]int64, int64, *GErrBlk) {
+	chars, ok := reader.FieldTable["value"].Fvalue.([]int64)
+	if !ok {
+		return nil, 0, getGErrBlk(excNames.IOException, "StringReader: Stream closed")
+	}
+	next, _ := reader.FieldTable["next"].Fvalue.(int64)
+	return chars, next, nil
+}
+
+// "java/io/StringReader.close()V"
+func stringReaderClose(params []interface{}) interface{} {
+	delete(params[0].(*object.Object).FieldTable, "value")
+	return nil
+}
+
+// "java/io/StringReader.read()I" returns the next char, or -1 at the end of the string
+func stringReaderReadOneChar(params []interface{}) interface{} {
+	reader := params[0].(*object.Object)
+	chars, next, errBlk := stringReaderChars(reader)
+	if errBlk != nil {
+		return errBlk
+	}
+	if next >= int64(len(chars)) {
+		return int64(-1)
+	}
+	reader.FieldTable["next"] = object.Field{Ftype: types.Int, Fvalue: next + 1}
+	return chars[next]
+}
+
+// "java/io/StringReader.read([CII)I" reads up to length chars into the array, starting at
+// offset, and returns the number read, or -1 at the end of the string
+func stringReaderReadCharBufferSubset(params []interface{}) interface{} {
+	reader := params[0].(*object.Object)
+	chars, next, errBlk := stringReaderChars(reader)
+	if errBlk != nil {
+		return errBlk
+	}
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "StringReader.read: char array is null")
+	}
+	buffer := params[1].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	offset := params[2].(int64)
+	length := params[3].(int64)
+
+	if length < 0 || offset < 0 || length > int64(len(buffer))-offset {
+		errMsg := fmt.Sprintf("StringReader.read: offset=%d, length=%d, char.array.length=%d",
+			offset, length, len(buffer))
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	if length == 0 {
+		return int64(0)
+	}
+	if next >= int64(len(chars)) {
+		return int64(-1)
+	}
+
+	count := int64(copy(buffer[offset:offset+length], chars[next:]))
+	reader.FieldTable["next"] = object.Field{Ftype: types.Int, Fvalue: next + count}
+	return count
+}
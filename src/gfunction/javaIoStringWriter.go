@@ -0,0 +1,216 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/types"
+	"unicode/utf16"
+)
+
+// Implementation of java/io/StringWriter. As in the JDK, the characters written are
+// collected in a StringBuffer, which is kept in the writer's "buf" field and returned by
+// getBuffer(), so changes made to the buffer show up in the writer, and vice versa. The
+// buffer holds UTF-8, not UTF-16, so a surrogate pair must be written by a single call.
+
+const stringBufferClassName = "java/lang/StringBuffer"
+
+func Load_Io_StringWriter() {
+
+	MethodSignatures["java/io/StringWriter.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/io/StringWriter.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringWriterInit,
+		}
+
+	MethodSignatures["java/io/StringWriter.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringWriterInitSize,
+		}
+
+	MethodSignatures["java/io/StringWriter.append(C)Ljava/io/StringWriter;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringWriterAppendChar,
+		}
+
+	MethodSignatures["java/io/StringWriter.append(Ljava/lang/CharSequence;)Ljava/io/StringWriter;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringWriterAppendCharSequence,
+		}
+
+	MethodSignatures["java/io/StringWriter.close()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/io/StringWriter.flush()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/io/StringWriter.getBuffer()Ljava/lang/StringBuffer;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringWriterGetBuffer,
+		}
+
+	MethodSignatures["java/io/StringWriter.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringWriterToString,
+		}
+
+	MethodSignatures["java/io/StringWriter.write(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringWriterWriteOneChar,
+		}
+
+	MethodSignatures["java/io/StringWriter.write([CII)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  stringWriterWriteCharBuffer,
+		}
+
+	MethodSignatures["java/io/StringWriter.write(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringWriterWriteString,
+		}
+
+	MethodSignatures["java/io/StringWriter.write(Ljava/lang/String;II)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  stringWriterWriteStringSubset,
+		}
+
+}
+
+// "java/io/StringWriter.<init>()V"
+func stringWriterInit(params []interface{}) interface{} {
+	className := stringBufferClassName
+	buf := object.MakeEmptyObjectWithClassName(&className)
+	buf.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: []byte{}}
+	buf.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	params[0].(*object.Object).FieldTable["buf"] = object.Field{Ftype: "Ljava/lang/StringBuffer;", Fvalue: buf}
+	return nil
+}
+
+// "java/io/StringWriter.<init>(I)V" The initial size is only a hint, so it's checked but not used.
+func stringWriterInitSize(params []interface{}) interface{} {
+	if size := params[1].(int64); size < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "StringWriter: Negative buffer size")
+	}
+	return stringWriterInit(params)
+}
+
+// stringWriterWrite appends a string to the bytes of the writer's buffer, which grow as a Go
+// slice does, so the chars already written aren't copied on each write. The String is made
+// from them only by toString().
+func stringWriterWrite(writer *object.Object, str string) {
+	buf := writer.FieldTable["buf"].Fvalue.(*object.Object)
+	current, _ := buf.FieldTable["value"].Fvalue.([]byte)
+	if count, ok := buf.FieldTable["count"].Fvalue.(int64); ok && count >= 0 && count <= int64(len(current)) {
+		current = current[:count]
+	}
+	bytes := append(current, str...)
+	object.UpdateStringObjectFromBytes(buf, bytes)
+	buf.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(len(bytes))}
+}
+
+// "java/io/StringWriter.append(C)Ljava/io/StringWriter;"
+func stringWriterAppendChar(params []interface{}) interface{} {
+	stringWriterWriteOneChar(params)
+	return params[0]
+}
+
+// "java/io/StringWriter.append(Ljava/lang/CharSequence;)Ljava/io/StringWriter;" appends
+// "null" if the CharSequence is null
+func stringWriterAppendCharSequence(params []interface{}) interface{} {
+	str := "null"
+	if !object.IsNull(params[1]) {
+		var ok bool
+		if str, ok = object.CharSequenceToGoString(params[1].(*object.Object)); !ok {
+			return getGErrBlk(excNames.IllegalArgumentException, "StringWriter.append: unsupported CharSequence")
+		}
+	}
+	stringWriterWrite(params[0].(*object.Object), str)
+	return params[0]
+}
+
+// "java/io/StringWriter.getBuffer()Ljava/lang/StringBuffer;"
+func stringWriterGetBuffer(params []interface{}) interface{} {
+	return params[0].(*object.Object).FieldTable["buf"].Fvalue
+}
+
+// "java/io/StringWriter.toString()Ljava/lang/String;"
+func stringWriterToString(params []interface{}) interface{} {
+	str, _ := object.CharSequenceToGoString(params[0].(*object.Object).FieldTable["buf"].Fvalue.(*object.Object))
+	return object.StringObjectFromGoString(str)
+}
+
+// "java/io/StringWriter.write(I)V" writes the char in the low 16 bits of the int
+func stringWriterWriteOneChar(params []interface{}) interface{} {
+	stringWriterWrite(params[0].(*object.Object), charsToGoString([]int64{params[1].(int64) & 0xFFFF}))
+	return nil
+}
+
+// "java/io/StringWriter.write([CII)V"
+func stringWriterWriteCharBuffer(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "StringWriter.write: char array is null")
+	}
+	chars := params[1].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	offset := params[2].(int64)
+	length := params[3].(int64)
+	if length < 0 || offset < 0 || length > int64(len(chars))-offset {
+		errMsg := fmt.Sprintf("StringWriter.write: offset=%d, length=%d, char.array.length=%d",
+			offset, length, len(chars))
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	stringWriterWrite(params[0].(*object.Object), charsToGoString(chars[offset:offset+length]))
+	return nil
+}
+
+// "java/io/StringWriter.write(Ljava/lang/String;)V"
+func stringWriterWriteString(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "StringWriter.write: string is null")
+	}
+	stringWriterWrite(params[0].(*object.Object), object.GoStringFromStringObject(params[1].(*object.Object)))
+	return nil
+}
+
+// "java/io/StringWriter.write(Ljava/lang/String;II)V" The offset and length are in chars.
+func stringWriterWriteStringSubset(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "StringWriter.write: string is null")
+	}
+	units := stringUTF16(params[1].(*object.Object))
+	offset := params[2].(int64)
+	length := params[3].(int64)
+	if length < 0 || offset < 0 || length > int64(len(units))-offset {
+		errMsg := fmt.Sprintf("StringWriter.write: offset=%d, length=%d, string.length=%d",
+			offset, length, len(units))
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
+	stringWriterWrite(params[0].(*object.Object), string(utf16.Decode(units[offset:offset+length])))
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"testing"
+)
+
+func makeTestStringReader(str string) *object.Object {
+	className := "java/io/StringReader"
+	reader := object.MakeEmptyObjectWithClassName(&className)
+	stringReaderInit([]interface{}{reader, object.StringObjectFromGoString(str)})
+	return reader
+}
+
+func makeTestStringWriter() *object.Object {
+	className := "java/io/StringWriter"
+	writer := object.MakeEmptyObjectWithClassName(&className)
+	stringWriterInit([]interface{}{writer})
+	return writer
+}
+
+// the chars read out of a StringReader, one at a time and in blocks, and written into a
+// StringWriter, give back the string read
+func TestStringReaderWriterRoundTrip(t *testing.T) {
+	globals.InitGlobals("test")
+	text := "Jacobin reads \U0001F600 and writes"
+	reader := makeTestStringReader(text)
+	writer := makeTestStringWriter()
+
+	for i := 0; i < 5; i++ {
+		ch := stringReaderReadOneChar([]interface{}{reader}).(int64)
+		stringWriterWriteOneChar([]interface{}{writer, ch})
+	}
+	buffer := object.Make1DimArray(object.INT, 4) // char arrays hold int64s, as int arrays do
+	for {
+		count := stringReaderReadCharBufferSubset([]interface{}{reader, buffer, int64(1), int64(3)}).(int64)
+		if count == -1 {
+			break
+		}
+		stringWriterWriteCharBuffer([]interface{}{writer, buffer, int64(1), count})
+	}
+
+	result := object.GoStringFromStringObject(stringWriterToString([]interface{}{writer}).(*object.Object))
+	if result != text {
+		t.Errorf("StringReader to StringWriter: expected %q, got %q", text, result)
+	}
+	if ret := stringReaderReadOneChar([]interface{}{reader}); ret != int64(-1) {
+		t.Errorf("StringReader.read() at the end: expected -1, got %v", ret)
+	}
+
+	stringReaderClose([]interface{}{reader})
+	ret := stringReaderReadOneChar([]interface{}{reader})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IOException {
+		t.Errorf("StringReader.read() after close(): expected an IOException, got %v", ret)
+	}
+}
+
+// append() and write() add to the buffer returned by getBuffer()
+func TestStringWriterAppendAndGetBuffer(t *testing.T) {
+	globals.InitGlobals("test")
+	writer := makeTestStringWriter()
+
+	if ret := stringWriterAppendChar([]interface{}{writer, int64('<')}); ret != writer {
+		t.Errorf("StringWriter.append(char): expected the writer to be returned, got %v", ret)
+	}
+	stringWriterAppendCharSequence([]interface{}{writer, makeTestStringBuilder("tag")})
+	stringWriterAppendCharSequence([]interface{}{writer, object.Null})
+	stringWriterWriteStringSubset([]interface{}{writer, object.StringObjectFromGoString("/>!"), int64(0), int64(2)})
+
+	buf := stringWriterGetBuffer([]interface{}{writer}).(*object.Object)
+	if str, _ := object.CharSequenceToGoString(buf); str != "<tagnull/>" {
+		t.Errorf("StringWriter.getBuffer(): expected \"<tagnull/>\", got %q", str)
+	}
+
+	ret := stringWriterWriteStringSubset([]interface{}{writer, object.StringObjectFromGoString("abc"), int64(2), int64(2)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.StringIndexOutOfBoundsException {
+		t.Errorf("StringWriter.write(\"abc\", 2, 2): expected a StringIndexOutOfBoundsException, got %v", ret)
+	}
+}
+
+// the chars written are appended to the buffer in place, not copied into a new one each time
+func TestStringWriterWritesInPlace(t *testing.T) {
+	globals.InitGlobals("test")
+	writer := makeTestStringWriter()
+	buf := stringWriterGetBuffer([]interface{}{writer}).(*object.Object)
+
+	reallocations := 0
+	for i := 0; i < 1000; i++ {
+		before := buf.FieldTable["value"].Fvalue.([]byte)
+		stringWriterWriteOneChar([]interface{}{writer, int64('x')})
+		after := buf.FieldTable["value"].Fvalue.([]byte)
+		if cap(before) == 0 || &before[:1][0] != &after[0] {
+			reallocations++
+		}
+	}
+	if reallocations > 20 {
+		t.Errorf("StringWriter.write(): expected the buffer to grow a few times, but it was copied %d times", reallocations)
+	}
+
+	str := object.GoStringFromStringObject(stringWriterToString([]interface{}{writer}).(*object.Object))
+	if len(str) != 1000 || str[999] != 'x' {
+		t.Errorf("StringWriter.toString(): expected 1000 x's, got %d chars", len(str))
+	}
+}
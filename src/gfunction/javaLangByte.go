@@ -13,8 +13,21 @@ import (
 	"jacobin/types"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+// Byte boundaries:
+const (
+	minByteValue = -128
+	maxByteValue = 127
+)
+
+// As in the JDK, every byte value has a single cached Byte instance, so that
+// Byte.valueOf() (and hence autoboxing of bytes) always returns the same object
+// for the same value. The entries are created on first use.
+var byteCache [256]*object.Object
+var byteCacheLock sync.Mutex
+
 func Load_Lang_Byte() {
 
 	MethodSignatures["java/lang/Byte.<clinit>()V"] =
@@ -23,16 +36,24 @@ func Load_Lang_Byte() {
 			GFunction:  justReturn,
 		}
 
+	loadNumberValues("java/lang/Byte")
+
+	MethodSignatures["java/lang/Byte.compare(BB)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  byteCompare,
+		}
+
 	MethodSignatures["java/lang/Byte.decode(Ljava/lang/String;)Ljava/lang/Byte;"] =
 		GMeth{
 			ParamSlots: 1,
 			GFunction:  byteDecode,
 		}
 
-	MethodSignatures["java/lang/Byte.doubleValue()D"] =
+	MethodSignatures["java/lang/Byte.parseByte(Ljava/lang/String;)B"] =
 		GMeth{
-			ParamSlots: 0,
-			GFunction:  byteDoubleValue,
+			ParamSlots: 1,
+			GFunction:  byteParseByte,
 		}
 
 	MethodSignatures["java/lang/Byte.toString()Ljava/lang/String;"] =
@@ -41,12 +62,29 @@ func Load_Lang_Byte() {
 			GFunction:  byteToString,
 		}
 
+	MethodSignatures["java/lang/Byte.toString(B)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteToStringB,
+		}
+
 	MethodSignatures["java/lang/Byte.valueOf(B)Ljava/lang/Byte;"] =
 		GMeth{
 			ParamSlots: 1,
 			GFunction:  byteValueOf,
 		}
 
+	MethodSignatures["java/lang/Byte.valueOf(Ljava/lang/String;)Ljava/lang/Byte;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  byteValueOfString,
+		}
+
+}
+
+// "java/lang/Byte.compare(BB)I"
+func byteCompare(params []interface{}) interface{} {
+	return params[0].(int64) - params[1].(int64)
 }
 
 // "java/lang/Byte.decode(Ljava/lang/String;)Ljava/lang/Byte;"
@@ -81,14 +119,6 @@ func byteDecode(params []interface{}) interface{} {
 	return populator("java/lang/Byte", types.Byte, int64Value)
 }
 
-// "java/lang/Byte.doubleValue()D"
-func byteDoubleValue(params []interface{}) interface{} {
-	var bb int64
-	parmObj := params[0].(*object.Object)
-	bb = parmObj.FieldTable["value"].Fvalue.(int64)
-	return float64(bb)
-}
-
 // "java/lang/Byte.toString()Ljava/lang/String;"
 func byteToString(params []interface{}) interface{} {
 	var ii int64
@@ -99,8 +129,70 @@ func byteToString(params []interface{}) interface{} {
 	return outObjPtr
 }
 
+// "java/lang/Byte.parseByte(Ljava/lang/String;)B"
+func byteParseByte(params []interface{}) interface{} {
+	parmObj := params[0].(*object.Object)
+	output, errBlk := parseIntegralInRange(parmObj, minByteValue, maxByteValue)
+	if errBlk != nil {
+		return errBlk
+	}
+	return output
+}
+
+// "java/lang/Byte.toString(B)Ljava/lang/String;"
+func byteToStringB(params []interface{}) interface{} {
+	str := fmt.Sprintf("%d", params[0].(int64))
+	return object.StringObjectFromGoString(str)
+}
+
 // "java/lang/Byte.valueOf(B)Ljava/lang/Byte;"
 func byteValueOf(params []interface{}) interface{} {
 	int64Value := params[0].(int64)
-	return populator("java/lang/Byte", types.Byte, int64Value)
+	return byteFromCache(int64Value)
+}
+
+// "java/lang/Byte.valueOf(Ljava/lang/String;)Ljava/lang/Byte;"
+func byteValueOfString(params []interface{}) interface{} {
+	parmObj := params[0].(*object.Object)
+	output, errBlk := parseIntegralInRange(parmObj, minByteValue, maxByteValue)
+	if errBlk != nil {
+		return errBlk
+	}
+	return byteFromCache(output)
+}
+
+// byteFromCache returns the cached Byte object for the given value, creating it if need be.
+func byteFromCache(value int64) *object.Object {
+	index := uint8(value) // wraps -128..-1 to 128..255
+	byteCacheLock.Lock()
+	defer byteCacheLock.Unlock()
+	if byteCache[index] == nil {
+		byteCache[index] = populator("java/lang/Byte", types.Byte, int64(int8(value)))
+	}
+	return byteCache[index]
+}
+
+// parseIntegralInRange parses a decimal String object as Byte.parseByte() and
+// Short.parseShort() do: an optional sign followed by digits, with a value that
+// must lie between min and max inclusive. Otherwise, the returned GErrBlk holds
+// a NumberFormatException.
+func parseIntegralInRange(strObj *object.Object, min, max int64) (int64, *GErrBlk) {
+	if object.IsNull(strObj) {
+		return 0, getGErrBlk(excNames.NumberFormatException, "Cannot parse null string: null")
+	}
+	strArg := object.GoStringFromStringObject(strObj)
+	if len(strArg) < 1 {
+		return 0, getGErrBlk(excNames.NumberFormatException, "For input string: \"\"")
+	}
+
+	output, err := strconv.ParseInt(strArg, 10, 64)
+	if err != nil {
+		errMsg := fmt.Sprintf("For input string: \"%s\"", strArg)
+		return 0, getGErrBlk(excNames.NumberFormatException, errMsg)
+	}
+	if output < min || output > max {
+		errMsg := fmt.Sprintf("Value out of range. Value:\"%s\" Radix:10", strArg)
+		return 0, getGErrBlk(excNames.NumberFormatException, errMsg)
+	}
+	return output, nil
 }
@@ -0,0 +1,69 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"testing"
+)
+
+func TestByteParseByte(t *testing.T) {
+	globals.InitGlobals("test")
+
+	for _, tc := range []struct {
+		in   string
+		want int64
+	}{{"0", 0}, {"127", 127}, {"-128", -128}, {"+42", 42}} {
+		ret := byteParseByte([]interface{}{object.StringObjectFromGoString(tc.in)})
+		if val, ok := ret.(int64); !ok || val != tc.want {
+			t.Errorf("Byte.parseByte(%q): expected %d, got %v", tc.in, tc.want, ret)
+		}
+	}
+}
+
+func TestByteParseByteInvalid(t *testing.T) {
+	globals.InitGlobals("test")
+
+	for _, in := range []string{"128", "-129", "", "12x"} {
+		ret := byteParseByte([]interface{}{object.StringObjectFromGoString(in)})
+		errBlk, ok := ret.(*GErrBlk)
+		if !ok || errBlk.ExceptionType != excNames.NumberFormatException {
+			t.Errorf("Byte.parseByte(%q): expected NumberFormatException, got %v", in, ret)
+		}
+	}
+}
+
+func TestByteValueOfIsCached(t *testing.T) {
+	globals.InitGlobals("test")
+
+	for _, value := range []int64{-128, -1, 0, 127} {
+		first := byteValueOf([]interface{}{value}).(*object.Object)
+		second := byteValueOf([]interface{}{value}).(*object.Object)
+		if first != second {
+			t.Errorf("Byte.valueOf(%d): expected the same cached object on both calls", value)
+		}
+		if got := numberByteValue([]interface{}{first}).(int64); got != value {
+			t.Errorf("Byte.valueOf(%d).byteValue(): got %d", value, got)
+		}
+	}
+
+	str := byteToString([]interface{}{byteValueOf([]interface{}{int64(-5)})}).(*object.Object)
+	if object.GoStringFromStringObject(str) != "-5" {
+		t.Errorf("Byte.toString(): expected \"-5\", got %q", object.GoStringFromStringObject(str))
+	}
+}
+
+func TestByteCompare(t *testing.T) {
+	if ret := byteCompare([]interface{}{int64(-128), int64(127)}).(int64); ret >= 0 {
+		t.Errorf("Byte.compare(-128, 127): expected a negative result, got %d", ret)
+	}
+	if ret := byteCompare([]interface{}{int64(7), int64(7)}).(int64); ret != 0 {
+		t.Errorf("Byte.compare(7, 7): expected 0, got %d", ret)
+	}
+}
@@ -7,17 +7,26 @@
 package gfunction
 
 import (
+	"container/list"
 	"errors"
 	"fmt"
 	"jacobin/classloader"
 	"jacobin/excNames"
+	"jacobin/globals"
 	"jacobin/log"
 	"jacobin/object"
 	"jacobin/shutdown"
 	"jacobin/statics"
+	"jacobin/types"
+	"strings"
 )
 
 // Implementation of some of the functions in Java/lang/Class.
+//
+// The Class objects created here, by forName(), hold the class's name, in the dotted form
+// returned by getName(), in their "name" field, as in the JDK.
+
+const classClassName = "java/lang/Class"
 
 func Load_Lang_Class() {
 
@@ -45,12 +54,32 @@ func Load_Lang_Class() {
 			GFunction:  justReturn,
 		}
 
+	MethodSignatures["java/lang/Class.forName(Ljava/lang/String;)Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    classForName,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Class.forName(Ljava/lang/String;ZLjava/lang/ClassLoader;)Ljava/lang/Class;"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    classForNameInitialize,
+			NeedsContext: true,
+		}
+
 	MethodSignatures["java/lang/Class.getName()Ljava/lang/String;"] =
 		GMeth{
 			ParamSlots: 0,
 			GFunction:  getName,
 		}
 
+	MethodSignatures["java/lang/Class.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  classToString,
+		}
+
 }
 
 // getPrimitiveClass() takes a one-word descriptor of a primitive and
@@ -131,9 +160,84 @@ func getAssertionsEnabledStatus([]interface{}) interface{} {
 	return 1 - x // return the 0 if disabled, 1 if not.
 }
 
-// "java/lang/Class.getName()Ljava/lang/String;"
+// "java/lang/Class.forName(Ljava/lang/String;)Ljava/lang/Class;" loads and initializes the class
+// params[0] = the frame stack, params[1] = the class name
+func classForName(params []interface{}) interface{} {
+	return loadClassForName(params[0].(*list.List), params[1], true)
+}
+
+// "java/lang/Class.forName(Ljava/lang/String;ZLjava/lang/ClassLoader;)Ljava/lang/Class;"
+// params[0] = the frame stack, params[1] = the class name, params[2] = whether to initialize
+// the class. The class loader, params[3], is ignored: classes are loaded by the app loader.
+func classForNameInitialize(params []interface{}) interface{} {
+	return loadClassForName(params[0].(*list.List), params[1], params[2].(int64) != types.JavaBoolFalse)
+}
+
+// loadClassForName loads the class with the given (dotted) name, if it's not already loaded, and
+// optionally runs its initialization blocks. It returns the class's Class object, or the GErrBlk
+// for a ClassNotFoundException if the class can't be found.
+func loadClassForName(fs *list.List, nameObj interface{}, initialize bool) interface{} {
+	if object.IsNull(nameObj) {
+		return getGErrBlk(excNames.NullPointerException, "Class.forName: class name is null")
+	}
+	dottedName := object.GoStringFromStringObject(nameObj.(*object.Object))
+	className := strings.ReplaceAll(dottedName, ".", "/")
+
+	if classloader.MethAreaFetch(className) == nil {
+		// LoadClassFromNameOnly() throws an uncatchable ClassNotFoundException for a missing
+		// .class file, so check for the class first and let the exception be thrown here.
+		if !classloader.ClassExists(className) {
+			return getGErrBlk(excNames.ClassNotFoundException, dottedName)
+		}
+		if err := classloader.LoadClassFromNameOnly(className); err != nil {
+			return getGErrBlk(excNames.ClassNotFoundException, dottedName)
+		}
+		if classloader.MethAreaFetch(className) == nil {
+			return getGErrBlk(excNames.ClassNotFoundException, dottedName)
+		}
+	}
+
+	initializeClass := globals.GetGlobalRef().FuncInitializeClass
+	if initialize && initializeClass != nil {
+		if err := initializeClass(className, fs); err != nil {
+			errMsg := fmt.Sprintf("Class.forName: initializing %s: %s", dottedName, err.Error())
+			return getGErrBlk(excNames.ExceptionInInitializerError, errMsg)
+		}
+	}
+	return makeClassObject(dottedName)
+}
+
+// makeClassObject returns a java/lang/Class object for the class with the given dotted name
+func makeClassObject(dottedName string) *object.Object {
+	className := classClassName
+	classObj := object.MakeEmptyObjectWithClassName(&className)
+	classObj.FieldTable["name"] = object.Field{
+		Ftype: types.Ref, Fvalue: object.StringObjectFromGoString(dottedName)}
+	return classObj
+}
+
+// "java/lang/Class.getName()Ljava/lang/String;" returns the dotted name kept in the Class object
 func getName(params []interface{}) interface{} {
-	primitive := params[0].(*object.Object)
-	str := object.GoStringFromStringObject(primitive)
-	return str
+	classObj := params[0].(*object.Object)
+	name, ok := classObj.FieldTable["name"].Fvalue.(*object.Object)
+	if !ok {
+		return getGErrBlk(excNames.IllegalArgumentException, "Class.getName: Class object has no name")
+	}
+	return name
+}
+
+// "java/lang/Class.toString()Ljava/lang/String;" returns "class" and the dotted name, as in
+// "class java.lang.String", or "interface" and the name, if the class is a loaded interface.
+func classToString(params []interface{}) interface{} {
+	name, ok := getName(params).(*object.Object)
+	if !ok {
+		return getName(params)
+	}
+	dottedName := object.GoStringFromStringObject(name)
+	kind := "class "
+	klass := classloader.MethAreaFetch(strings.ReplaceAll(dottedName, ".", "/"))
+	if klass != nil && klass.Data != nil && klass.Data.Access.ClassIsInterface {
+		kind = "interface "
+	}
+	return object.StringObjectFromGoString(kind + dottedName)
 }
@@ -0,0 +1,78 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/log"
+	"jacobin/object"
+	"jacobin/types"
+	"testing"
+)
+
+func TestClassForNameGetNameRoundTrips(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+
+	// java.lang.String is already loaded, so forName() doesn't need the JDK to find it
+	clData := classloader.ClData{Name: "java/lang/String", Superclass: types.ObjectClassName}
+	classloader.MethAreaInsert("java/lang/String", &classloader.Klass{Status: 'X', Loader: "bootstrap", Data: &clData})
+
+	initialized := ""
+	globals.GetGlobalRef().FuncInitializeClass = func(className string, _ *list.List) error {
+		initialized = className
+		return nil
+	}
+	defer func() { globals.GetGlobalRef().FuncInitializeClass = nil }()
+
+	fs := frames.CreateFrameStack()
+	ret := classForName([]interface{}{fs, object.StringObjectFromGoString("java.lang.String")})
+	classObj, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected Class.forName to return a Class object, got: %T %v", ret, ret)
+	}
+	if initialized != "java/lang/String" {
+		t.Errorf("Expected java/lang/String to be initialized, got: %q", initialized)
+	}
+
+	name := getName([]interface{}{classObj})
+	if nameObj, ok := name.(*object.Object); !ok || object.GoStringFromStringObject(nameObj) != "java.lang.String" {
+		t.Errorf("Expected getName to return \"java.lang.String\", got: %v", name)
+	}
+
+	// the three-argument form initializes the class only if asked to
+	initialized = ""
+	ret = classForNameInitialize([]interface{}{fs, object.StringObjectFromGoString("java.lang.String"),
+		types.JavaBoolFalse, object.Null})
+	if _, ok := ret.(*object.Object); !ok || initialized != "" {
+		t.Errorf("Expected a Class object and no initialization, got: %v, initialized: %q", ret, initialized)
+	}
+}
+
+func TestClassForNameOfMissingClass(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.InitMethodArea()
+
+	fs := frames.CreateFrameStack()
+	ret := classForName([]interface{}{fs, object.StringObjectFromGoString("no.such.Klass")})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.ClassNotFoundException || errBlk.ErrMsg != "no.such.Klass" {
+		t.Errorf("Expected a ClassNotFoundException for no.such.Klass, got: %v", ret)
+	}
+
+	ret = classForName([]interface{}{fs, object.Null})
+	if errBlk, ok = ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected a NullPointerException for a null class name, got: %v", ret)
+	}
+}
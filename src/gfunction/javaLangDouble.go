@@ -25,11 +25,7 @@ func Load_Lang_Double() {
 			GFunction:  justReturn,
 		}
 
-	MethodSignatures["java/lang/Double.byteValue()B"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  doubleByteValue,
-		}
+	loadNumberValues("java/lang/Double")
 
 	MethodSignatures["java/lang/Double.compare(DD)I"] =
 		GMeth{
@@ -43,12 +39,6 @@ func Load_Lang_Double() {
 			GFunction:  doubleCompareTo,
 		}
 
-	MethodSignatures["java/lang/Double.doubleValue()D"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  doubleDoubleValue,
-		}
-
 	MethodSignatures["java/lang/Double.equals(Ljava/lang/Object;)Z"] =
 		GMeth{
 			ParamSlots: 1,
@@ -89,14 +79,6 @@ func Load_Lang_Double() {
 
 }
 
-// "java/lang/Double.byteValue()B"
-func doubleByteValue(params []interface{}) interface{} {
-	var dd float64
-	parmObj := params[0].(*object.Object)
-	dd = parmObj.FieldTable["value"].Fvalue.(float64)
-	return int64(byte(dd))
-}
-
 // "java/lang/Double.compare(DD)I"
 func doubleCompare(params []interface{}) interface{} {
 	dd1 := params[0].(float64)
@@ -132,12 +114,6 @@ func doubleCompareTo(params []interface{}) interface{} {
 	return int64(1)
 }
 
-// "java/lang/Double.doubleValue()D"
-func doubleDoubleValue(params []interface{}) interface{} {
-	parmObj := params[0].(*object.Object)
-	return parmObj.FieldTable["value"].Fvalue.(float64)
-}
-
 // "java/lang/Double.equals(Ljava/lang/Object;)Z"
 func doubleEquals(params []interface{}) interface{} {
 	var dd1, dd2 float64
@@ -19,6 +19,8 @@ func Load_Lang_Float() {
 			GFunction:  justReturn,
 		}
 
+	loadNumberValues("java/lang/Float")
+
 	// Native functions or caller to native functions
 
 	MethodSignatures["java/lang/Float.floatToIntBits(F)I"] =
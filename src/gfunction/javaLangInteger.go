@@ -13,6 +13,7 @@ import (
 	"jacobin/types"
 	"strconv"
 	"strings"
+	"unicode/utf16"
 )
 
 func Load_Lang_Integer() {
@@ -23,11 +24,7 @@ func Load_Lang_Integer() {
 			GFunction:  justReturn,
 		}
 
-	MethodSignatures["java/lang/Integer.byteValue()B"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  integerByteValue,
-		}
+	loadNumberValues("java/lang/Integer")
 
 	MethodSignatures["java/lang/Integer.decode(Ljava/lang/String;)Ljava/lang/Integer;"] =
 		GMeth{
@@ -35,30 +32,6 @@ func Load_Lang_Integer() {
 			GFunction:  integerDecode,
 		}
 
-	MethodSignatures["java/lang/Integer.doubleValue()D"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  integerFloatDoubleValue,
-		}
-
-	MethodSignatures["java/lang/Integer.floatValue()F"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  integerFloatDoubleValue,
-		}
-
-	MethodSignatures["java/lang/Integer.intValue()I"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  integerIntLongValue,
-		}
-
-	MethodSignatures["java/lang/Integer.longValue()J"] =
-		GMeth{
-			ParamSlots: 0,
-			GFunction:  integerIntLongValue,
-		}
-
 	MethodSignatures["java/lang/Integer.parseInt(Ljava/lang/String;)I"] =
 		GMeth{
 			ParamSlots: 1,
@@ -71,6 +44,12 @@ func Load_Lang_Integer() {
 			GFunction:  integerParseIntRadix,
 		}
 
+	MethodSignatures["java/lang/Integer.parseInt(Ljava/lang/CharSequence;III)I"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  integerParseIntSubrange,
+		}
+
 	MethodSignatures["java/lang/Integer.valueOf(I)Ljava/lang/Integer;"] =
 		GMeth{
 			ParamSlots: 1,
@@ -121,54 +100,52 @@ func Load_Lang_Integer() {
 
 }
 
-// "java/lang/Integer.byteValue()B"
-func integerByteValue(params []interface{}) interface{} {
-	var ii int64
-	parmObj := params[0].(*object.Object)
-	ii = parmObj.FieldTable["value"].Fvalue.(int64)
-	return ii
-}
-
 // "java/lang/Integer.decode(Ljava/lang/String;)Ljava/lang/Integer;"
 func integerDecode(params []interface{}) interface{} {
-	// Extract and validate the string argument.
-	parmObj := params[0].(*object.Object)
-	strArg := object.GoStringFromStringObject(parmObj)
-	if len(strArg) < 1 {
-		return getGErrBlk(excNames.NumberFormatException, "Byte array length is zero")
+	int64Value, errBlk := decodeInteger(params[0], 32)
+	if errBlk != nil {
+		return errBlk
 	}
+	return populator("java/lang/Integer", types.Int, int64Value)
+}
 
-	// Replace a leading "#" with "0x" in strArg.
-	if strings.HasPrefix(strArg, "#") {
-		strArg = strings.Replace(strArg, "#", "0x", 1)
+// decodeInteger does the work of Integer.decode() and Long.decode(): after an optional sign,
+// a leading 0x, 0X, or # means hexadecimal and a leading 0 octal; otherwise, the number is
+// decimal. The value must fit in bitSize bits.
+func decodeInteger(strObj interface{}, bitSize int) (int64, *GErrBlk) {
+	if object.IsNull(strObj) {
+		return 0, getGErrBlk(excNames.NullPointerException, "decode: string is null")
 	}
-
-	// Parse the input integer.
-	int64Value, err := strconv.ParseInt(strArg, 10, 64)
-	if err != nil {
-		errMsg := fmt.Sprintf("strconv.ParseInt(%s,10,64) failed, failed, reason: %s", strArg, err.Error())
-		return getGErrBlk(excNames.NumberFormatException, errMsg)
+	str := object.GoStringFromStringObject(strObj.(*object.Object))
+	if len(str) == 0 {
+		return 0, getGErrBlk(excNames.NumberFormatException, "Zero length string")
 	}
 
-	// Create Integer object.
-	return populator("java/lang/Integer", types.Int, int64Value)
-}
-
-// "java/lang/Integer.doubleValue()D"
-// "java/lang/Integer.floatValue()F"
-func integerFloatDoubleValue(params []interface{}) interface{} {
-	var ii int64
-	parmObj := params[0].(*object.Object)
-	ii = parmObj.FieldTable["value"].Fvalue.(int64)
-	return float64(ii)
-}
+	digits, sign := str, ""
+	if digits[0] == '-' || digits[0] == '+' {
+		digits, sign = digits[1:], digits[:1]
+	}
+	radix := 10
+	switch {
+	case strings.HasPrefix(digits, "0x"), strings.HasPrefix(digits, "0X"):
+		digits, radix = digits[2:], 16
+	case strings.HasPrefix(digits, "#"):
+		digits, radix = digits[1:], 16
+	case strings.HasPrefix(digits, "0") && len(digits) > 1:
+		digits, radix = digits[1:], 8
+	}
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		return 0, getGErrBlk(excNames.NumberFormatException, "Sign character in wrong position")
+	}
 
-// "java/lang/Integer.longValue()J"
-func integerIntLongValue(params []interface{}) interface{} {
-	var ii int64
-	parmObj := params[0].(*object.Object)
-	ii = parmObj.FieldTable["value"].Fvalue.(int64)
-	return ii
+	// the sign is parsed with the digits, so that MIN_VALUE, whose magnitude is too large to be
+	// positive, can be decoded
+	value, err := strconv.ParseInt(sign+digits, radix, bitSize)
+	if err != nil {
+		errMsg := fmt.Sprintf("For input string: \"%s\"", str)
+		return 0, getGErrBlk(excNames.NumberFormatException, errMsg)
+	}
+	return value, nil
 }
 
 // "java/lang/Integer.parseInt(Ljava/lang/String;)I"
@@ -242,6 +219,30 @@ func integerParseIntRadix(params []interface{}) interface{} {
 	return output
 }
 
+// "java/lang/Integer.parseInt(Ljava/lang/CharSequence;III)I"
+// Parses the characters from beginIndex up to (but not including) endIndex in the given radix.
+// The indices are of UTF-16 chars, as in Java.
+func integerParseIntSubrange(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "Integer.parseInt: CharSequence is null")
+	}
+	str, ok := object.CharSequenceToGoString(params[0].(*object.Object))
+	if !ok {
+		return getGErrBlk(excNames.IllegalArgumentException, "Integer.parseInt: argument is not a CharSequence")
+	}
+
+	units := utf16.Encode([]rune(str))
+	beginIndex := params[1].(int64)
+	endIndex := params[2].(int64)
+	if beginIndex < 0 || beginIndex > endIndex || endIndex > int64(len(units)) {
+		errMsg := fmt.Sprintf("Integer.parseInt: begin %d, end %d, length %d", beginIndex, endIndex, len(units))
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+
+	substr := object.StringObjectFromGoString(string(utf16.Decode(units[beginIndex:endIndex])))
+	return integerParseIntRadix([]interface{}{substr, params[3]})
+}
+
 // "java/lang/Integer.valueOf(I)Ljava/lang/Integer;"
 func integerValueOf(params []interface{}) interface{} {
 	int64Value := params[0].(int64)
@@ -0,0 +1,96 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"testing"
+)
+
+func TestIntegerParseIntSubrange(t *testing.T) {
+	globals.InitGlobals("test")
+	str := object.StringObjectFromGoString("id=-1234;mask=ff")
+
+	for _, tc := range []struct {
+		begin, end, radix int64
+		want              int64
+	}{{3, 8, 10, -1234}, {4, 6, 10, 12}, {14, 16, 16, 255}} {
+		ret := integerParseIntSubrange([]interface{}{str, tc.begin, tc.end, tc.radix})
+		if val, ok := ret.(int64); !ok || val != tc.want {
+			t.Errorf("Integer.parseInt(%d, %d, %d): expected %d, got %v", tc.begin, tc.end, tc.radix, tc.want, ret)
+		}
+	}
+
+	// the subrange can also come from a StringBuilder
+	builder := makeTestStringBuilder("x42")
+	if ret := integerParseIntSubrange([]interface{}{builder, int64(1), int64(3), int64(10)}); ret != int64(42) {
+		t.Errorf("Integer.parseInt of a StringBuilder subrange: expected 42, got %v", ret)
+	}
+
+	// the indices are of UTF-16 chars, so "é" and "😀" count as one and two chars, not as their UTF-8 bytes
+	accented := object.StringObjectFromGoString("é😀=77")
+	if ret := integerParseIntSubrange([]interface{}{accented, int64(4), int64(6), int64(10)}); ret != int64(77) {
+		t.Errorf("Integer.parseInt after non-ASCII chars: expected 77, got %v", ret)
+	}
+	ret := integerParseIntSubrange([]interface{}{accented, int64(4), int64(7), int64(10)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IndexOutOfBoundsException {
+		t.Errorf("Integer.parseInt past the last UTF-16 char: expected an IndexOutOfBoundsException, got %v", ret)
+	}
+
+	for _, tc := range []struct {
+		begin, end int64
+		want       int
+	}{
+		{0, 8, excNames.NumberFormatException},      // "id=-1234" is malformed
+		{3, 3, excNames.NumberFormatException},      // an empty subrange is malformed
+		{-1, 8, excNames.IndexOutOfBoundsException}, // begin < 0
+		{8, 3, excNames.IndexOutOfBoundsException},  // begin > end
+		{3, 17, excNames.IndexOutOfBoundsException}, // end > length
+	} {
+		ret := integerParseIntSubrange([]interface{}{str, tc.begin, tc.end, int64(10)})
+		errBlk, ok := ret.(*GErrBlk)
+		if !ok || errBlk.ExceptionType != tc.want {
+			t.Errorf("Integer.parseInt(%d, %d): expected exception %d, got %v", tc.begin, tc.end, tc.want, ret)
+		}
+	}
+}
+
+func TestIntegerDecode(t *testing.T) {
+	globals.InitGlobals("test")
+
+	for _, tc := range []struct {
+		str  string
+		want int64
+	}{
+		{"0x1F", 31},
+		{"010", 8},
+		{"-0xFF", -255},
+		{"#7f", 127},
+		{"+42", 42},
+		{"0", 0},
+		{"-0x80000000", MinIntValue},
+	} {
+		ret := integerDecode([]interface{}{object.StringObjectFromGoString(tc.str)})
+		obj, ok := ret.(*object.Object)
+		if !ok {
+			t.Errorf("Integer.decode(%q): expected an Integer, got %v", tc.str, ret)
+			continue
+		}
+		if value := obj.FieldTable["value"].Fvalue.(int64); value != tc.want {
+			t.Errorf("Integer.decode(%q): expected %d, got %d", tc.str, tc.want, value)
+		}
+	}
+
+	for _, str := range []string{"", "0x", "08", "0x-1", "12a", "0x80000000"} {
+		ret := integerDecode([]interface{}{object.StringObjectFromGoString(str)})
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NumberFormatException {
+			t.Errorf("Integer.decode(%q): expected a NumberFormatException, got %v", str, ret)
+		}
+	}
+}
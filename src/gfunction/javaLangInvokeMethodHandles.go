@@ -0,0 +1,284 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/types"
+	"strings"
+)
+
+// Implementation of the parts of java.lang.invoke that make method handles: the lookups of
+// MethodHandles, MethodType, and MethodHandles.Lookup.findVirtual().
+//
+// Jacobin's method handles are direct handles to virtual methods. A handle is an object of
+// class java/lang/invoke/DirectMethodHandle whose fields hold the class, name, and descriptor
+// of its target method. The JVM reads these fields when the handle's invoke() or
+// invokeExact() is run (see jvm/methodHandles.go). As in the JDK, the target method is
+// resolved when it is invoked, against the class of the receiver. A MethodType is an object
+// that holds the method descriptor it stands for in its "descriptor" field.
+//
+// Because the handles, lookups, and method types don't have the JDK's fields, the JDK's
+// bytecode for the other methods of these classes can't run against them, so the methods
+// that make other kinds of handles and the methods of MethodType that read its fields are
+// trapped.
+
+const (
+	directMethodHandleClassName = "java/lang/invoke/DirectMethodHandle"
+	lookupClassName             = "java/lang/invoke/MethodHandles$Lookup"
+	methodTypeClassName         = "java/lang/invoke/MethodType"
+	methodTypeDescriptor        = "descriptor"
+)
+
+func Load_Lang_Invoke_MethodHandles() {
+
+	for _, className := range []string{"java/lang/invoke/MethodHandles", lookupClassName, methodTypeClassName} {
+		MethodSignatures[className+".<clinit>()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  justReturn,
+			}
+	}
+
+	MethodSignatures["java/lang/invoke/MethodHandles.lookup()Ljava/lang/invoke/MethodHandles$Lookup;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodHandlesLookup,
+		}
+
+	MethodSignatures["java/lang/invoke/MethodHandles.publicLookup()Ljava/lang/invoke/MethodHandles$Lookup;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodHandlesLookup,
+		}
+
+	MethodSignatures[lookupClassName+".findVirtual(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/invoke/MethodType;)Ljava/lang/invoke/MethodHandle;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  lookupFindVirtual,
+		}
+
+	MethodSignatures[methodTypeClassName+".fromMethodDescriptorString(Ljava/lang/String;Ljava/lang/ClassLoader;)Ljava/lang/invoke/MethodType;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  methodTypeFromDescriptor,
+		}
+
+	MethodSignatures[methodTypeClassName+".methodType(Ljava/lang/Class;)Ljava/lang/invoke/MethodType;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  methodTypeOf,
+		}
+
+	MethodSignatures[methodTypeClassName+".methodType(Ljava/lang/Class;Ljava/lang/Class;)Ljava/lang/invoke/MethodType;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  methodTypeOf,
+		}
+
+	MethodSignatures[methodTypeClassName+".methodType(Ljava/lang/Class;[Ljava/lang/Class;)Ljava/lang/invoke/MethodType;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  methodTypeOf,
+		}
+
+	MethodSignatures[methodTypeClassName+".toMethodDescriptorString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  methodTypeToDescriptorString,
+		}
+
+	trapMethods(lookupClassName,
+		"bind(Ljava/lang/Object;Ljava/lang/String;Ljava/lang/invoke/MethodType;)Ljava/lang/invoke/MethodHandle;",
+		"findConstructor(Ljava/lang/Class;Ljava/lang/invoke/MethodType;)Ljava/lang/invoke/MethodHandle;",
+		"findGetter(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/Class;)Ljava/lang/invoke/MethodHandle;",
+		"findSetter(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/Class;)Ljava/lang/invoke/MethodHandle;",
+		"findSpecial(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/invoke/MethodType;Ljava/lang/Class;)Ljava/lang/invoke/MethodHandle;",
+		"findStatic(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/invoke/MethodType;)Ljava/lang/invoke/MethodHandle;",
+		"findStaticGetter(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/Class;)Ljava/lang/invoke/MethodHandle;",
+		"findStaticSetter(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/Class;)Ljava/lang/invoke/MethodHandle;",
+		"in(Ljava/lang/Class;)Ljava/lang/invoke/MethodHandles$Lookup;",
+		"lookupClass()Ljava/lang/Class;",
+		"toString()Ljava/lang/String;",
+		"unreflect(Ljava/lang/reflect/Method;)Ljava/lang/invoke/MethodHandle;",
+	)
+
+	trapMethods(methodTypeClassName,
+		"equals(Ljava/lang/Object;)Z",
+		"hashCode()I",
+		"parameterArray()[Ljava/lang/Class;",
+		"parameterCount()I",
+		"parameterList()Ljava/util/List;",
+		"parameterType(I)Ljava/lang/Class;",
+		"returnType()Ljava/lang/Class;",
+		"toString()Ljava/lang/String;",
+	)
+}
+
+// "java/lang/invoke/MethodHandles.lookup()Ljava/lang/invoke/MethodHandles$Lookup;" and
+// publicLookup(). As Jacobin doesn't check access to the target of a handle, the lookups
+// are the same.
+func methodHandlesLookup([]interface{}) interface{} {
+	className := lookupClassName
+	return object.MakeEmptyObjectWithClassName(&className)
+}
+
+// "java/lang/invoke/MethodHandles$Lookup.findVirtual(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/invoke/MethodType;)Ljava/lang/invoke/MethodHandle;"
+// returns a handle to the named virtual method of the class, with the given type.
+// params[0] = the lookup, params[1] = the class, params[2] = the method name, params[3] = the type
+func lookupFindVirtual(params []interface{}) interface{} {
+	if object.IsNull(params[1]) || object.IsNull(params[2]) || object.IsNull(params[3]) {
+		return getGErrBlk(excNames.NullPointerException, "MethodHandles.Lookup.findVirtual: argument is null")
+	}
+	className, ok := classInternalName(params[1])
+	if !ok || strings.HasPrefix(className, types.Array) {
+		return getGErrBlk(excNames.IllegalArgumentException, "MethodHandles.Lookup.findVirtual: not a class")
+	}
+	methodName := object.GoStringFromStringObject(params[2].(*object.Object))
+	if methodName == "<init>" || methodName == "<clinit>" {
+		errMsg := fmt.Sprintf("MethodHandles.Lookup.findVirtual: %s is not a virtual method", methodName)
+		return getGErrBlk(excNames.NoSuchMethodException, errMsg)
+	}
+	methodType, errBlk := methodTypeDescriptorOf(params[3].(*object.Object), "MethodHandles.Lookup.findVirtual")
+	if errBlk != nil {
+		return errBlk
+	}
+
+	handleClassName := directMethodHandleClassName
+	handle := object.MakeEmptyObjectWithClassName(&handleClassName)
+	handle.FieldTable["targetClass"] = object.Field{Ftype: types.GolangString, Fvalue: className}
+	handle.FieldTable["targetName"] = object.Field{Ftype: types.GolangString, Fvalue: methodName}
+	handle.FieldTable["targetType"] = object.Field{Ftype: types.GolangString, Fvalue: methodType}
+	return handle
+}
+
+// "java/lang/invoke/MethodType.fromMethodDescriptorString(Ljava/lang/String;Ljava/lang/ClassLoader;)Ljava/lang/invoke/MethodType;"
+// The class loader, params[1], is ignored: classes are loaded by the app loader.
+func methodTypeFromDescriptor(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "MethodType.fromMethodDescriptorString: descriptor is null")
+	}
+	descriptor := object.GoStringFromStringObject(params[0].(*object.Object))
+	end := strings.Index(descriptor, ")")
+	if !strings.HasPrefix(descriptor, "(") || end < 0 || end == len(descriptor)-1 {
+		errMsg := fmt.Sprintf("MethodType.fromMethodDescriptorString: not a method descriptor: %s", descriptor)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return makeMethodType(descriptor)
+}
+
+// "java/lang/invoke/MethodType.methodType(Ljava/lang/Class;)Ljava/lang/invoke/MethodType;" and
+// the overloads that take a parameter type or an array of them. params[0] = the return type,
+// params[1], if present, = the parameter type or the array of parameter types
+func methodTypeOf(params []interface{}) interface{} {
+	var paramClasses []any
+	if len(params) > 1 {
+		paramClasses = append(paramClasses, params[1])
+		if array, ok := params[1].(*object.Object); ok && !object.IsNull(array) {
+			if elements, ok := array.FieldTable["value"].Fvalue.([]*object.Object); ok {
+				paramClasses = nil
+				for _, element := range elements {
+					paramClasses = append(paramClasses, element)
+				}
+			}
+		}
+	}
+
+	returnType, ok := classDescriptor(params[0])
+	if !ok {
+		return getGErrBlk(excNames.NullPointerException, "MethodType.methodType: return type is null")
+	}
+	var descriptor strings.Builder
+	descriptor.WriteString("(")
+	for _, paramClass := range paramClasses {
+		paramType, ok := classDescriptor(paramClass)
+		if !ok {
+			return getGErrBlk(excNames.NullPointerException, "MethodType.methodType: parameter type is null")
+		}
+		if paramType == "V" {
+			return getGErrBlk(excNames.IllegalArgumentException, "MethodType.methodType: parameter type is void")
+		}
+		descriptor.WriteString(paramType)
+	}
+	descriptor.WriteString(")" + returnType)
+	return makeMethodType(descriptor.String())
+}
+
+// "java/lang/invoke/MethodType.toMethodDescriptorString()Ljava/lang/String;"
+func methodTypeToDescriptorString(params []interface{}) interface{} {
+	descriptor, errBlk := methodTypeDescriptorOf(params[0].(*object.Object), "MethodType.toMethodDescriptorString")
+	if errBlk != nil {
+		return errBlk
+	}
+	return object.StringObjectFromGoString(descriptor)
+}
+
+func makeMethodType(descriptor string) *object.Object {
+	className := methodTypeClassName
+	methodType := object.MakeEmptyObjectWithClassName(&className)
+	methodType.FieldTable[methodTypeDescriptor] = object.Field{Ftype: types.GolangString, Fvalue: descriptor}
+	return methodType
+}
+
+// methodTypeDescriptorOf returns the descriptor held by a MethodType made here. The caller
+// names the method for error messages.
+func methodTypeDescriptorOf(methodType *object.Object, caller string) (string, *GErrBlk) {
+	descriptor, ok := methodType.FieldTable[methodTypeDescriptor].Fvalue.(string)
+	if !ok {
+		return "", getGErrBlk(excNames.IllegalArgumentException, caller+": not a supported MethodType")
+	}
+	return descriptor, nil
+}
+
+// classInternalName returns the internal name, such as java/lang/String, of the class a
+// Class argument stands for: either a Class object, which holds the dotted name, or the
+// String that LDC pushes for a class literal, which holds the internal name
+func classInternalName(arg any) (string, bool) {
+	classObj, ok := arg.(*object.Object)
+	if !ok || object.IsNull(classObj) {
+		return "", false
+	}
+	switch object.GoStringFromStringPoolIndex(classObj.KlassName) {
+	case classClassName:
+		name, ok := classObj.FieldTable["name"].Fvalue.(*object.Object)
+		if !ok {
+			return "", false
+		}
+		return strings.ReplaceAll(object.GoStringFromStringObject(name), ".", "/"), true
+	case types.StringClassName:
+		return object.GoStringFromStringObject(classObj), true
+	}
+	return "", false
+}
+
+// the descriptors of the primitive types, by the classes that Class.getPrimitiveClass() returns
+var primitiveClassDescriptors = map[string]string{
+	"java/lang/Boolean": types.Bool, "java/lang/Byte": types.Byte, "java/lang/Character": types.Char,
+	"java/lang/Double": types.Double, "java/lang/Float": types.Float, "java/lang/Integer": types.Int,
+	"java/lang/Long": types.Long, "java/lang/Short": types.Short, "java/lang/Void": "V",
+}
+
+// classDescriptor returns the field descriptor, such as I or Ljava/lang/String;, of the type a
+// Class argument stands for. Besides the arguments classInternalName accepts, this can be the
+// class that Class.getPrimitiveClass() returns for a primitive type, such as Integer.TYPE.
+func classDescriptor(arg any) (string, bool) {
+	if klass, ok := arg.(*classloader.Klass); ok && klass != nil && klass.Data != nil {
+		primitive, ok := primitiveClassDescriptors[klass.Data.Name]
+		return primitive, ok
+	}
+	className, ok := classInternalName(arg)
+	if !ok {
+		return "", false
+	}
+	if strings.HasPrefix(className, types.Array) {
+		return className, true
+	}
+	return "L" + className + ";", true
+}
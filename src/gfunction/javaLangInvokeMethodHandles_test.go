@@ -0,0 +1,123 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"testing"
+)
+
+func checkMethodTypeDescriptor(t *testing.T, ret interface{}, expected, what string) {
+	methodType, ok := ret.(*object.Object)
+	if !ok {
+		t.Errorf("%s: expected a MethodType, got: %v", what, ret)
+		return
+	}
+	descriptor := methodTypeToDescriptorString([]interface{}{methodType})
+	if str, ok := descriptor.(*object.Object); !ok || object.GoStringFromStringObject(str) != expected {
+		t.Errorf("%s: expected descriptor %s, got: %v", what, expected, descriptor)
+	}
+}
+
+// The method types are made from the classes that Jacobin has for class literals: the Class
+// objects that forName() returns, the Strings that LDC pushes, and the primitive classes
+func TestMethodTypeOf(t *testing.T) {
+	globals.InitGlobals("test")
+
+	intClass := &classloader.Klass{Data: &classloader.ClData{Name: "java/lang/Integer"}}
+	voidClass := &classloader.Klass{Data: &classloader.ClData{Name: "java/lang/Void"}}
+	stringClass := makeClassObject("java.lang.String")
+	objectClass := object.StringObjectFromGoString("java/lang/Object")
+
+	checkMethodTypeDescriptor(t, methodTypeOf([]interface{}{intClass}), "()I", "methodType(int)")
+	checkMethodTypeDescriptor(t, methodTypeOf([]interface{}{voidClass, stringClass}),
+		"(Ljava/lang/String;)V", "methodType(void, String)")
+
+	className := "[Ljava/lang/Class;"
+	params := object.Make1DimRefArray(&className, 2)
+	params.FieldTable["value"].Fvalue.([]*object.Object)[0] = stringClass
+	params.FieldTable["value"].Fvalue.([]*object.Object)[1] = objectClass
+	checkMethodTypeDescriptor(t, methodTypeOf([]interface{}{stringClass, params}),
+		"(Ljava/lang/String;Ljava/lang/Object;)Ljava/lang/String;", "methodType(String, String, Object)")
+
+	ret := methodTypeOf([]interface{}{intClass, voidClass})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Expected IllegalArgumentException for a void parameter, got: %v", ret)
+	}
+	ret = methodTypeOf([]interface{}{object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException for a null return type, got: %v", ret)
+	}
+}
+
+func TestMethodTypeFromDescriptor(t *testing.T) {
+	globals.InitGlobals("test")
+
+	descriptor := object.StringObjectFromGoString("(IJ)Ljava/lang/String;")
+	checkMethodTypeDescriptor(t, methodTypeFromDescriptor([]interface{}{descriptor, object.Null}),
+		"(IJ)Ljava/lang/String;", "fromMethodDescriptorString")
+
+	for _, bad := range []string{"", "I", "(I", "(I)"} {
+		ret := methodTypeFromDescriptor([]interface{}{object.StringObjectFromGoString(bad), object.Null})
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+			t.Errorf("Expected IllegalArgumentException for descriptor %q, got: %v", bad, ret)
+		}
+	}
+}
+
+// findVirtual() returns a handle whose fields hold the target method, for the JVM to invoke
+func TestLookupFindVirtual(t *testing.T) {
+	globals.InitGlobals("test")
+
+	lookup := methodHandlesLookup(nil)
+	methodType := makeMethodType("(I)C")
+	ret := lookupFindVirtual([]interface{}{lookup, makeClassObject("java.lang.String"),
+		object.StringObjectFromGoString("charAt"), methodType})
+	handle, ok := ret.(*object.Object)
+	if !ok || object.GoStringFromStringPoolIndex(handle.KlassName) != directMethodHandleClassName {
+		t.Fatalf("Expected a DirectMethodHandle, got: %v", ret)
+	}
+	if handle.FieldTable["targetClass"].Fvalue != "java/lang/String" ||
+		handle.FieldTable["targetName"].Fvalue != "charAt" || handle.FieldTable["targetType"].Fvalue != "(I)C" {
+		t.Errorf("Expected a handle to java/lang/String.charAt(I)C, got: %v", handle.FieldTable)
+	}
+
+	for _, test := range []struct {
+		params    []interface{}
+		exception int
+	}{
+		{[]interface{}{lookup, object.Null, object.StringObjectFromGoString("charAt"), methodType},
+			excNames.NullPointerException},
+		{[]interface{}{lookup, makeClassObject("java.lang.String"), object.StringObjectFromGoString("<init>"), methodType},
+			excNames.NoSuchMethodException},
+		{[]interface{}{lookup, makeClassObject("java.lang.String"), object.StringObjectFromGoString("charAt"),
+			object.MakeEmptyObject()}, excNames.IllegalArgumentException},
+	} {
+		ret := lookupFindVirtual(test.params)
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != test.exception {
+			t.Errorf("Expected exception %d, got: %v", test.exception, ret)
+		}
+	}
+}
+
+// the lookups that make other kinds of handles are trapped
+func TestLookupTraps(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Lang_Invoke_MethodHandles()
+
+	gmeth, ok := MethodSignatures[lookupClassName+
+		".findStatic(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/invoke/MethodType;)Ljava/lang/invoke/MethodHandle;"]
+	if !ok || gmeth.ParamSlots != 3 {
+		t.Fatalf("Expected findStatic() to be trapped with 3 parameter slots, got: %v", gmeth)
+	}
+	if errBlk, ok := gmeth.GFunction(nil).(*GErrBlk); !ok || errBlk.ExceptionType != excNames.UnsupportedOperationException {
+		t.Errorf("Expected findStatic() to throw UnsupportedOperationException")
+	}
+}
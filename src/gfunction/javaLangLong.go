@@ -23,10 +23,12 @@ func Load_Lang_Long() {
 			GFunction:  justReturn,
 		}
 
-	MethodSignatures["java/lang/Long.doubleValue()D"] =
+	loadNumberValues("java/lang/Long")
+
+	MethodSignatures["java/lang/Long.decode(Ljava/lang/String;)Ljava/lang/Long;"] =
 		GMeth{
-			ParamSlots: 0,
-			GFunction:  longDoubleValue,
+			ParamSlots: 1,
+			GFunction:  longDecode,
 		}
 
 	MethodSignatures["java/lang/Long.parseLong(Ljava/lang/String;)J"] =
@@ -67,12 +69,13 @@ func Load_Lang_Long() {
 
 }
 
-// "java/lang/Long.doubleValue()D"
-func longDoubleValue(params []interface{}) interface{} {
-	var jj int64
-	parmObj := params[0].(*object.Object)
-	jj = parmObj.FieldTable["value"].Fvalue.(int64)
-	return float64(jj)
+// "java/lang/Long.decode(Ljava/lang/String;)Ljava/lang/Long;"
+func longDecode(params []interface{}) interface{} {
+	int64Value, errBlk := decodeInteger(params[0], 64)
+	if errBlk != nil {
+		return errBlk
+	}
+	return populator("java/lang/Long", types.Long, int64Value)
 }
 
 // "java/lang/Long.parseLong(Ljava/lang/String;)J"
@@ -0,0 +1,45 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"math"
+	"testing"
+)
+
+func TestLongDecode(t *testing.T) {
+	globals.InitGlobals("test")
+
+	for _, tc := range []struct {
+		str  string
+		want int64
+	}{
+		{"0x1F", 31},
+		{"010", 8},
+		{"-0xFF", -255},
+		{"0X7FFFFFFFFFFFFFFF", math.MaxInt64},
+		{"-9223372036854775808", math.MinInt64},
+	} {
+		ret := longDecode([]interface{}{object.StringObjectFromGoString(tc.str)})
+		obj, ok := ret.(*object.Object)
+		if !ok {
+			t.Errorf("Long.decode(%q): expected a Long, got %v", tc.str, ret)
+			continue
+		}
+		if value := obj.FieldTable["value"].Fvalue.(int64); value != tc.want {
+			t.Errorf("Long.decode(%q): expected %d, got %d", tc.str, tc.want, value)
+		}
+	}
+
+	ret := longDecode([]interface{}{object.StringObjectFromGoString("#")})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NumberFormatException {
+		t.Errorf("Long.decode(\"#\"): expected a NumberFormatException, got %v", ret)
+	}
+}
@@ -12,7 +12,10 @@ import (
 	"jacobin/log"
 	"math"
 	"math/big"
+	"math/bits"
 	"math/rand"
+	"sync"
+	"time"
 )
 
 /*
@@ -48,8 +51,8 @@ func Load_Lang_Math() {
 	MethodSignatures["java/lang/Math.abs(F)F"] = GMeth{ParamSlots: 1, GFunction: absFloat64}
 	MethodSignatures["java/lang/Math.abs(I)I"] = GMeth{ParamSlots: 1, GFunction: absInt64}
 	MethodSignatures["java/lang/Math.abs(J)J"] = GMeth{ParamSlots: 2, GFunction: absInt64}
-	MethodSignatures["java/lang/Math.absExact(I)I"] = GMeth{ParamSlots: 1, GFunction: absInt64}
-	MethodSignatures["java/lang/Math.absExact(J)J"] = GMeth{ParamSlots: 2, GFunction: absInt64}
+	MethodSignatures["java/lang/Math.absExact(I)I"] = GMeth{ParamSlots: 1, GFunction: absExactI}
+	MethodSignatures["java/lang/Math.absExact(J)J"] = GMeth{ParamSlots: 2, GFunction: absExactJ}
 	MethodSignatures["java/lang/Math.acos(D)D"] = GMeth{ParamSlots: 2, GFunction: acosFloat64}
 	MethodSignatures["java/lang/Math.addExact(II)I"] = GMeth{ParamSlots: 2, GFunction: addExactII}
 	MethodSignatures["java/lang/Math.addExact(JJ)J"] = GMeth{ParamSlots: 4, GFunction: addExactJJ}
@@ -62,8 +65,8 @@ func Load_Lang_Math() {
 	MethodSignatures["java/lang/Math.copySign(FF)F"] = GMeth{ParamSlots: 2, GFunction: copySignFF}
 	MethodSignatures["java/lang/Math.cos(D)D"] = GMeth{ParamSlots: 2, GFunction: cosFloat64}
 	MethodSignatures["java/lang/Math.cosh(D)D"] = GMeth{ParamSlots: 2, GFunction: coshFloat64}
-	MethodSignatures["java/lang/Math.decrementExact(I)I"] = GMeth{ParamSlots: 1, GFunction: decrementExactInt64}
-	MethodSignatures["java/lang/Math.decrementExact(J)J"] = GMeth{ParamSlots: 2, GFunction: decrementExactInt64}
+	MethodSignatures["java/lang/Math.decrementExact(I)I"] = GMeth{ParamSlots: 1, GFunction: decrementExactI}
+	MethodSignatures["java/lang/Math.decrementExact(J)J"] = GMeth{ParamSlots: 2, GFunction: decrementExactJ}
 	MethodSignatures["java/lang/Math.exp(D)D"] = GMeth{ParamSlots: 2, GFunction: expFloat64}
 	MethodSignatures["java/lang/Math.expm1(D)D"] = GMeth{ParamSlots: 2, GFunction: expm1Float64}
 	MethodSignatures["java/lang/Math.floor(D)D"] = GMeth{ParamSlots: 2, GFunction: floorFloat64}
@@ -79,8 +82,8 @@ func Load_Lang_Math() {
 	MethodSignatures["java/lang/Math.getExponent(F)I"] = GMeth{ParamSlots: 1, GFunction: getExponentFloat64}
 	MethodSignatures["java/lang/Math.hypot(DD)D"] = GMeth{ParamSlots: 4, GFunction: hypotFloat64}
 	MethodSignatures["java/lang/Math.IEEEremainder(DD)D"] = GMeth{ParamSlots: 4, GFunction: IEEEremainderFloat64}
-	MethodSignatures["java/lang/Math.incrementExact(I)I"] = GMeth{ParamSlots: 1, GFunction: incrementExactInt64}
-	MethodSignatures["java/lang/Math.incrementExact(J)J"] = GMeth{ParamSlots: 2, GFunction: incrementExactInt64}
+	MethodSignatures["java/lang/Math.incrementExact(I)I"] = GMeth{ParamSlots: 1, GFunction: incrementExactI}
+	MethodSignatures["java/lang/Math.incrementExact(J)J"] = GMeth{ParamSlots: 2, GFunction: incrementExactJ}
 	MethodSignatures["java/lang/Math.log(D)D"] = GMeth{ParamSlots: 2, GFunction: logFloat64}
 	MethodSignatures["java/lang/Math.log10(D)D"] = GMeth{ParamSlots: 2, GFunction: log10Float64}
 	MethodSignatures["java/lang/Math.log1p(D)D"] = GMeth{ParamSlots: 2, GFunction: log1pFloat64}
@@ -93,11 +96,11 @@ func Load_Lang_Math() {
 	MethodSignatures["java/lang/Math.min(II)I"] = GMeth{ParamSlots: 2, GFunction: minII}
 	MethodSignatures["java/lang/Math.min(JJ)J"] = GMeth{ParamSlots: 4, GFunction: minJJ}
 	MethodSignatures["java/lang/Math.multiplyExact(II)I"] = GMeth{ParamSlots: 2, GFunction: multiplyExactII}
-	MethodSignatures["java/lang/Math.multiplyExact(JI)I"] = GMeth{ParamSlots: 3, GFunction: multiplyExactJx}
+	MethodSignatures["java/lang/Math.multiplyExact(JI)J"] = GMeth{ParamSlots: 3, GFunction: multiplyExactJx}
 	MethodSignatures["java/lang/Math.multiplyExact(JJ)J"] = GMeth{ParamSlots: 4, GFunction: multiplyExactJx}
 	MethodSignatures["java/lang/Math.multiplyHigh(JJ)J"] = GMeth{ParamSlots: 4, GFunction: multiplyHighJJ}
-	MethodSignatures["java/lang/Math.negateExact(I)I"] = GMeth{ParamSlots: 1, GFunction: negateExactInt64}
-	MethodSignatures["java/lang/Math.negateExact(J)J"] = GMeth{ParamSlots: 2, GFunction: negateExactInt64}
+	MethodSignatures["java/lang/Math.negateExact(I)I"] = GMeth{ParamSlots: 1, GFunction: negateExactI}
+	MethodSignatures["java/lang/Math.negateExact(J)J"] = GMeth{ParamSlots: 2, GFunction: negateExactJ}
 	MethodSignatures["java/lang/Math.nextAfter(DD)D"] = GMeth{ParamSlots: 4, GFunction: nextAfterDD}
 	MethodSignatures["java/lang/Math.nextAfter(FD)F"] = GMeth{ParamSlots: 3, GFunction: nextAfterFD}
 	MethodSignatures["java/lang/Math.nextDown(D)D"] = GMeth{ParamSlots: 2, GFunction: nextDownFloat64}
@@ -130,8 +133,8 @@ func Load_Lang_Math() {
 	MethodSignatures["java/lang/StrictMath.abs(F)F"] = GMeth{ParamSlots: 1, GFunction: absFloat64}
 	MethodSignatures["java/lang/StrictMath.abs(I)I"] = GMeth{ParamSlots: 1, GFunction: absInt64}
 	MethodSignatures["java/lang/StrictMath.abs(J)J"] = GMeth{ParamSlots: 2, GFunction: absInt64}
-	MethodSignatures["java/lang/StrictMath.absExact(I)I"] = GMeth{ParamSlots: 1, GFunction: absInt64}
-	MethodSignatures["java/lang/StrictMath.absExact(J)J"] = GMeth{ParamSlots: 2, GFunction: absInt64}
+	MethodSignatures["java/lang/StrictMath.absExact(I)I"] = GMeth{ParamSlots: 1, GFunction: absExactI}
+	MethodSignatures["java/lang/StrictMath.absExact(J)J"] = GMeth{ParamSlots: 2, GFunction: absExactJ}
 	MethodSignatures["java/lang/StrictMath.acos(D)D"] = GMeth{ParamSlots: 2, GFunction: acosFloat64}
 	MethodSignatures["java/lang/StrictMath.addExact(II)I"] = GMeth{ParamSlots: 2, GFunction: addExactII}
 	MethodSignatures["java/lang/StrictMath.addExact(JJ)J"] = GMeth{ParamSlots: 4, GFunction: addExactJJ}
@@ -144,8 +147,8 @@ func Load_Lang_Math() {
 	MethodSignatures["java/lang/StrictMath.copySign(FF)F"] = GMeth{ParamSlots: 2, GFunction: copySignFF}
 	MethodSignatures["java/lang/StrictMath.cos(D)D"] = GMeth{ParamSlots: 2, GFunction: cosFloat64}
 	MethodSignatures["java/lang/StrictMath.cosh(D)D"] = GMeth{ParamSlots: 2, GFunction: coshFloat64}
-	MethodSignatures["java/lang/StrictMath.decrementExact(I)I"] = GMeth{ParamSlots: 1, GFunction: decrementExactInt64}
-	MethodSignatures["java/lang/StrictMath.decrementExact(J)J"] = GMeth{ParamSlots: 2, GFunction: decrementExactInt64}
+	MethodSignatures["java/lang/StrictMath.decrementExact(I)I"] = GMeth{ParamSlots: 1, GFunction: decrementExactI}
+	MethodSignatures["java/lang/StrictMath.decrementExact(J)J"] = GMeth{ParamSlots: 2, GFunction: decrementExactJ}
 	MethodSignatures["java/lang/StrictMath.exp(D)D"] = GMeth{ParamSlots: 2, GFunction: expFloat64}
 	MethodSignatures["java/lang/StrictMath.expm1(D)D"] = GMeth{ParamSlots: 2, GFunction: expm1Float64}
 	MethodSignatures["java/lang/StrictMath.floor(D)D"] = GMeth{ParamSlots: 2, GFunction: floorFloat64}
@@ -161,8 +164,8 @@ func Load_Lang_Math() {
 	MethodSignatures["java/lang/StrictMath.getExponent(F)I"] = GMeth{ParamSlots: 1, GFunction: getExponentFloat64}
 	MethodSignatures["java/lang/StrictMath.hypot(DD)D"] = GMeth{ParamSlots: 4, GFunction: hypotFloat64}
 	MethodSignatures["java/lang/StrictMath.IEEEremainder(DD)D"] = GMeth{ParamSlots: 4, GFunction: IEEEremainderFloat64}
-	MethodSignatures["java/lang/StrictMath.incrementExact(I)I"] = GMeth{ParamSlots: 1, GFunction: incrementExactInt64}
-	MethodSignatures["java/lang/StrictMath.incrementExact(J)J"] = GMeth{ParamSlots: 2, GFunction: incrementExactInt64}
+	MethodSignatures["java/lang/StrictMath.incrementExact(I)I"] = GMeth{ParamSlots: 1, GFunction: incrementExactI}
+	MethodSignatures["java/lang/StrictMath.incrementExact(J)J"] = GMeth{ParamSlots: 2, GFunction: incrementExactJ}
 	MethodSignatures["java/lang/StrictMath.log(D)D"] = GMeth{ParamSlots: 2, GFunction: logFloat64}
 	MethodSignatures["java/lang/StrictMath.log10(D)D"] = GMeth{ParamSlots: 2, GFunction: log10Float64}
 	MethodSignatures["java/lang/StrictMath.log1p(D)D"] = GMeth{ParamSlots: 2, GFunction: log1pFloat64}
@@ -175,11 +178,11 @@ func Load_Lang_Math() {
 	MethodSignatures["java/lang/StrictMath.min(II)I"] = GMeth{ParamSlots: 2, GFunction: minII}
 	MethodSignatures["java/lang/StrictMath.min(JJ)J"] = GMeth{ParamSlots: 4, GFunction: minJJ}
 	MethodSignatures["java/lang/StrictMath.multiplyExact(II)I"] = GMeth{ParamSlots: 2, GFunction: multiplyExactII}
-	MethodSignatures["java/lang/StrictMath.multiplyExact(JI)I"] = GMeth{ParamSlots: 3, GFunction: multiplyExactJx}
+	MethodSignatures["java/lang/StrictMath.multiplyExact(JI)J"] = GMeth{ParamSlots: 3, GFunction: multiplyExactJx}
 	MethodSignatures["java/lang/StrictMath.multiplyExact(JJ)J"] = GMeth{ParamSlots: 4, GFunction: multiplyExactJx}
 	MethodSignatures["java/lang/StrictMath.multiplyHigh(JJ)J"] = GMeth{ParamSlots: 4, GFunction: multiplyHighJJ}
-	MethodSignatures["java/lang/StrictMath.negateExact(I)I"] = GMeth{ParamSlots: 1, GFunction: negateExactInt64}
-	MethodSignatures["java/lang/StrictMath.negateExact(J)J"] = GMeth{ParamSlots: 2, GFunction: negateExactInt64}
+	MethodSignatures["java/lang/StrictMath.negateExact(I)I"] = GMeth{ParamSlots: 1, GFunction: negateExactI}
+	MethodSignatures["java/lang/StrictMath.negateExact(J)J"] = GMeth{ParamSlots: 2, GFunction: negateExactJ}
 	MethodSignatures["java/lang/StrictMath.nextAfter(DD)D"] = GMeth{ParamSlots: 4, GFunction: nextAfterDD}
 	MethodSignatures["java/lang/StrictMath.nextAfter(FD)F"] = GMeth{ParamSlots: 3, GFunction: nextAfterFD}
 	MethodSignatures["java/lang/StrictMath.nextDown(D)D"] = GMeth{ParamSlots: 2, GFunction: nextDownFloat64}
@@ -240,17 +243,49 @@ func absInt64(params []interface{}) interface{} {
 	return xx
 }
 
+// Absolute value of an int, throwing an ArithmeticException for Integer.MIN_VALUE, which has no positive counterpart
+func absExactI(params []interface{}) interface{} {
+	if params[0].(int64) == math.MinInt32 {
+		return getGErrBlk(excNames.ArithmeticException, "Overflow to represent absolute value of Integer.MIN_VALUE")
+	}
+	return absInt64(params)
+}
+
+// Absolute value of a long, throwing an ArithmeticException for Long.MIN_VALUE, which has no positive counterpart
+func absExactJ(params []interface{}) interface{} {
+	if params[0].(int64) == math.MinInt64 {
+		return getGErrBlk(excNames.ArithmeticException, "Overflow to represent absolute value of Long.MIN_VALUE")
+	}
+	return absInt64(params)
+}
+
+// exactInt returns the result of an int operation, computed as an int64, if it fits in
+// an int. Otherwise, it returns an ArithmeticException, as Java's xxxExact() methods do.
+func exactInt(result int64) interface{} {
+	if result < math.MinInt32 || result > math.MaxInt32 {
+		return getGErrBlk(excNames.ArithmeticException, "integer overflow")
+	}
+	return result
+}
+
 // Arc cosine of a value; the returned angle is in the range 0.0 through pi.
 func acosFloat64(params []interface{}) interface{} {
 	return math.Acos(params[0].(float64))
 }
 
-// Sum of its arguments
+// Sum of its arguments, throwing an ArithmeticException if the result overflows an int
 func addExactII(params []interface{}) interface{} {
-	return params[0].(int64) + params[1].(int64)
+	return exactInt(params[0].(int64) + params[1].(int64))
 }
+
+// Sum of its arguments, throwing an ArithmeticException if the result overflows a long
 func addExactJJ(params []interface{}) interface{} {
-	return params[0].(int64) + params[2].(int64)
+	xx, yy := params[0].(int64), params[2].(int64)
+	sum := xx + yy
+	if (xx^sum)&(yy^sum) < 0 { // both arguments have a sign different from the sum's
+		return getGErrBlk(excNames.ArithmeticException, "long overflow")
+	}
+	return sum
 }
 
 // Arc sine of a value; the returned angle is in the range -pi/2 through pi/2.
@@ -276,6 +311,8 @@ func cbrtFloat64(params []interface{}) interface{} {
 
 // Smallest (closest to negative infinity) double value that is
 // greater than or equal to the argument and is equal to a mathematical integer.
+// As in Java, an argument between -1.0 and -0.0 gives -0.0, and zeros, infinities,
+// and NaN are returned as they are.
 func ceilFloat64(params []interface{}) interface{} {
 	return math.Ceil(params[0].(float64))
 }
@@ -298,9 +335,18 @@ func coshFloat64(params []interface{}) interface{} {
 	return math.Cosh(params[0].(float64))
 }
 
-// Decrement the argument by 1
-func decrementExactInt64(params []interface{}) interface{} {
-	return params[0].(int64) - 1
+// Argument minus 1, throwing an ArithmeticException if the result overflows an int
+func decrementExactI(params []interface{}) interface{} {
+	return exactInt(params[0].(int64) - 1)
+}
+
+// Argument minus 1, throwing an ArithmeticException if the result overflows a long
+func decrementExactJ(params []interface{}) interface{} {
+	xx := params[0].(int64)
+	if xx == math.MinInt64 {
+		return getGErrBlk(excNames.ArithmeticException, "long overflow")
+	}
+	return xx - 1
 }
 
 // Euler's number e raised to the power of a double value.
@@ -314,30 +360,32 @@ func expm1Float64(params []interface{}) interface{} {
 }
 
 // Largest (closest to positive infinity) double value that is less than or equal to
-// the argument and is equal to a mathematical integer.
+// the argument and is equal to a mathematical integer. As in Java, zeros (keeping
+// their sign), infinities, and NaN are returned as they are.
 func floorFloat64(params []interface{}) interface{} {
 	return math.Floor(params[0].(float64))
 }
 
 // Largest (closest to positive infinity) int value that is less than or equal
-// to the algebraic quotient.
+// to the algebraic quotient. As with LDIV, MIN_VALUE / -1 overflows to MIN_VALUE.
 func floorDivInt64(dividend int64, divisor int64) interface{} {
 	if divisor == 0 {
-		return getGErrBlk(excNames.ArithmeticException, "Divide by zero")
-	}
-	if dividend <= math.MinInt64 && divisor == -1 {
-		return math.MinInt64
+		return getGErrBlk(excNames.ArithmeticException, "/ by zero")
 	}
-	if (dividend <= 0 && divisor < 00) || (dividend >= 0 && divisor > 00) {
-		return dividend / divisor
+	quotient := dividend / divisor // golang truncates toward zero
+	if dividend%divisor != 0 && (dividend < 0) != (divisor < 0) {
+		quotient--
 	}
-	// At this point, (a) x and y are nonzero and (b) they have opposite signs.
-	return (dividend / divisor) - 1
+	return quotient
 }
 func floorDivII(params []interface{}) interface{} {
 	dividend := params[0].(int64)
 	divisor := params[1].(int64)
-	return floorDivInt64(dividend, divisor)
+	quotient := floorDivInt64(dividend, divisor)
+	if q, ok := quotient.(int64); ok {
+		return int64(int32(q)) // as with IDIV, MIN_VALUE / -1 overflows to MIN_VALUE
+	}
+	return quotient
 }
 func floorDivJx(params []interface{}) interface{} {
 	dividend := params[0].(int64)
@@ -345,31 +393,23 @@ func floorDivJx(params []interface{}) interface{} {
 	return floorDivInt64(dividend, divisor)
 }
 
-// Largest (closest to positive infinity) int value that is less than or equal
-// to the algebraic quotient.
-// params[0]=dividend=x
-// params[1]=divisor=y
-// floorDiv(x, y) * y + floorMod(x, y) = x
-// Therefore, floorMod(x, y) = x - floorDiv(x, y) * y
-func floorModII(params []interface{}) interface{} {
-	fldiv := floorDivII(params)
-	switch fldiv.(type) {
-	case *GErrBlk:
-		// Return the G function error block
-		return fldiv
+// Floor modulus: the remainder of floorDiv(x, y), which has the sign of the divisor, y,
+// so that floorDiv(x, y) * y + floorMod(x, y) == x.
+func floorModInt64(dividend int64, divisor int64) interface{} {
+	if divisor == 0 {
+		return getGErrBlk(excNames.ArithmeticException, "/ by zero")
 	}
-	result := params[0].(int64) - fldiv.(int64)*params[1].(int64)
-	return result
+	mod := dividend % divisor // golang's remainder has the sign of the dividend
+	if mod != 0 && (mod < 0) != (divisor < 0) {
+		mod += divisor
+	}
+	return mod
+}
+func floorModII(params []interface{}) interface{} {
+	return floorModInt64(params[0].(int64), params[1].(int64))
 }
 func floorModJx(params []interface{}) interface{} {
-	fldiv := floorDivJx(params)
-	switch fldiv.(type) {
-	case *GErrBlk:
-		// Return the G function error block
-		return fldiv
-	}
-	result := params[0].(int64) - fldiv.(int64)*params[2].(int64)
-	return result
+	return floorModInt64(params[0].(int64), params[2].(int64))
 }
 
 // FMA (fused multiply add) the three arguments; that is, returns the exact product
@@ -414,9 +454,18 @@ func IEEEremainderFloat64(params []interface{}) interface{} {
 	return math.Remainder(params[0].(float64), params[2].(float64))
 }
 
-// Increment the argument by 1
-func incrementExactInt64(params []interface{}) interface{} {
-	return params[0].(int64) + 1
+// Argument plus 1, throwing an ArithmeticException if the result overflows an int
+func incrementExactI(params []interface{}) interface{} {
+	return exactInt(params[0].(int64) + 1)
+}
+
+// Argument plus 1, throwing an ArithmeticException if the result overflows a long
+func incrementExactJ(params []interface{}) interface{} {
+	xx := params[0].(int64)
+	if xx == math.MaxInt64 {
+		return getGErrBlk(excNames.ArithmeticException, "long overflow")
+	}
+	return xx + 1
 }
 
 // Natural logarithm (base e) of a double value.
@@ -482,12 +531,34 @@ func minJJ(params []interface{}) interface{} {
 	return yy
 }
 
-// Product of the arguments.
+// Product of the arguments, throwing an ArithmeticException if the result overflows an int
 func multiplyExactII(params []interface{}) interface{} {
-	return params[0].(int64) * params[1].(int64)
+	return exactInt(params[0].(int64) * params[1].(int64))
 }
+
+// Product of the arguments, throwing an ArithmeticException if the result overflows a long.
+// The second argument is a long or an int; either way, it is in params[2].
 func multiplyExactJx(params []interface{}) interface{} {
-	return params[0].(int64) * params[2].(int64)
+	xx, yy := params[0].(int64), params[2].(int64)
+
+	// multiply the magnitudes as unsigned, giving a 128-bit product in hi and lo
+	hi, lo := bits.Mul64(absUint64(xx), absUint64(yy))
+	limit := uint64(math.MaxInt64)
+	if (xx < 0) != (yy < 0) {
+		limit += 1 // a negative product can reach MinInt64
+	}
+	if hi != 0 || lo > limit {
+		return getGErrBlk(excNames.ArithmeticException, "long overflow")
+	}
+	return xx * yy
+}
+
+// absUint64 returns the magnitude of a long as an unsigned value, which is valid even for MinInt64
+func absUint64(xx int64) uint64 {
+	if xx < 0 {
+		return uint64(-(xx + 1)) + 1
+	}
+	return uint64(xx)
 }
 
 // Most significant 64 bits of the 128-bit product of two 64-bit factors.
@@ -500,9 +571,18 @@ func multiplyHighJJ(params []interface{}) interface{} {
 	return zz.Int64()
 }
 
-// Negation of the argument for int and long.
-func negateExactInt64(params []interface{}) interface{} {
-	return -params[0].(int64)
+// Negation of the argument, throwing an ArithmeticException if the result overflows an int
+func negateExactI(params []interface{}) interface{} {
+	return exactInt(-params[0].(int64))
+}
+
+// Negation of the argument, throwing an ArithmeticException if the result overflows a long
+func negateExactJ(params []interface{}) interface{} {
+	xx := params[0].(int64)
+	if xx == math.MinInt64 {
+		return getGErrBlk(excNames.ArithmeticException, "long overflow")
+	}
+	return -xx
 }
 
 // Next after double of float value.
@@ -528,9 +608,21 @@ func powFloat64(params []interface{}) interface{} {
 	return math.Pow(params[0].(float64), params[2].(float64))
 }
 
+// As in the JDK, Math.random() and StrictMath.random() share a single generator, which is
+// created and seeded on first use. A rand.Rand is not safe for concurrent use, so calls to
+// it are serialized.
+var mathRandom *rand.Rand
+var mathRandomOnce sync.Once
+var mathRandomLock sync.Mutex
+
 // Generate a random number >= 0.0 and < 1.0
 func randomFloat64(params []interface{}) interface{} {
-	return rand.Float64()
+	mathRandomOnce.Do(func() {
+		mathRandom = rand.New(rand.NewSource(time.Now().UnixNano()))
+	})
+	mathRandomLock.Lock()
+	defer mathRandomLock.Unlock()
+	return mathRandom.Float64()
 }
 
 // Computes a double-valued number that is closest in value to the argument and is equal to a mathematical integer.
@@ -597,12 +689,19 @@ func sqrtFloat64(params []interface{}) interface{} {
 	return math.Sqrt(params[0].(float64))
 }
 
-// Difference of its arguments
+// Difference of the arguments, throwing an ArithmeticException if the result overflows an int
 func subtractExactII(params []interface{}) interface{} {
-	return params[0].(int64) - params[1].(int64)
+	return exactInt(params[0].(int64) - params[1].(int64))
 }
+
+// Difference of the arguments, throwing an ArithmeticException if the result overflows a long
 func subtractExactJJ(params []interface{}) interface{} {
-	return params[0].(int64) - params[2].(int64)
+	xx, yy := params[0].(int64), params[2].(int64)
+	diff := xx - yy
+	if (xx^yy)&(xx^diff) < 0 { // the arguments differ in sign and the result's sign is not xx's
+		return getGErrBlk(excNames.ArithmeticException, "long overflow")
+	}
+	return diff
 }
 
 // Compute the tangent of an angle expressed in radians.
@@ -620,9 +719,9 @@ func toDegreesFloat64(params []interface{}) interface{} {
 	return params[0].(float64) * 180.0 / PI
 }
 
-// Not very interesting as long and its are both int64.
+// The long argument as an int, throwing an ArithmeticException if it does not fit
 func toIntExactInt64(params []interface{}) interface{} {
-	return params[0].(int64)
+	return exactInt(params[0].(int64))
 }
 
 // Convert degrees to radians.
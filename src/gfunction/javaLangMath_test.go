@@ -0,0 +1,208 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"math"
+	"sync"
+	"testing"
+)
+
+// checkArithmeticException fails the test unless ret is an ArithmeticException with the given message
+func checkArithmeticException(t *testing.T, what string, ret interface{}, msg string) {
+	t.Helper()
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.ArithmeticException || errBlk.ErrMsg != msg {
+		t.Errorf("%s: expected ArithmeticException(\"%s\"), got: %v", what, msg, ret)
+	}
+}
+
+func TestMathExactIntOperations(t *testing.T) {
+	maxInt, minInt := int64(math.MaxInt32), int64(math.MinInt32)
+
+	if ret := addExactII([]interface{}{int64(40), int64(2)}); ret != int64(42) {
+		t.Errorf("addExact(40, 2): expected 42, got: %v", ret)
+	}
+	if ret := addExactII([]interface{}{maxInt, int64(-1)}); ret != maxInt-1 {
+		t.Errorf("addExact(MAX_VALUE, -1): expected %d, got: %v", maxInt-1, ret)
+	}
+	checkArithmeticException(t, "addExact(MAX_VALUE, 1)", addExactII([]interface{}{maxInt, int64(1)}), "integer overflow")
+	checkArithmeticException(t, "subtractExact(MIN_VALUE, 1)", subtractExactII([]interface{}{minInt, int64(1)}), "integer overflow")
+	checkArithmeticException(t, "multiplyExact(65536, 32768)", multiplyExactII([]interface{}{int64(65536), int64(32768)}), "integer overflow")
+	checkArithmeticException(t, "incrementExact(MAX_VALUE)", incrementExactI([]interface{}{maxInt}), "integer overflow")
+	checkArithmeticException(t, "decrementExact(MIN_VALUE)", decrementExactI([]interface{}{minInt}), "integer overflow")
+	checkArithmeticException(t, "negateExact(MIN_VALUE)", negateExactI([]interface{}{minInt}), "integer overflow")
+	checkArithmeticException(t, "toIntExact(MAX_VALUE + 1)", toIntExactInt64([]interface{}{maxInt + 1, maxInt + 1}), "integer overflow")
+
+	if ret := multiplyExactII([]interface{}{int64(-65536), int64(32768)}); ret != minInt {
+		t.Errorf("multiplyExact(-65536, 32768): expected %d, got: %v", minInt, ret)
+	}
+	if ret := negateExactI([]interface{}{maxInt}); ret != -maxInt {
+		t.Errorf("negateExact(MAX_VALUE): expected %d, got: %v", -maxInt, ret)
+	}
+	if ret := toIntExactInt64([]interface{}{minInt, minInt}); ret != minInt {
+		t.Errorf("toIntExact(MIN_VALUE): expected %d, got: %v", minInt, ret)
+	}
+}
+
+func TestMathExactLongOperations(t *testing.T) {
+	maxLong, minLong := int64(math.MaxInt64), int64(math.MinInt64)
+
+	// long arguments take two parameter slots, so the second argument is in params[2]
+	if ret := addExactJJ([]interface{}{int64(1) << 40, int64(1) << 40, int64(1), int64(1)}); ret != int64(1)<<40+1 {
+		t.Errorf("addExact(2^40, 1): expected %d, got: %v", int64(1)<<40+1, ret)
+	}
+	checkArithmeticException(t, "addExact(MAX_VALUE, 1)", addExactJJ([]interface{}{maxLong, maxLong, int64(1), int64(1)}), "long overflow")
+	checkArithmeticException(t, "addExact(MIN_VALUE, -1)", addExactJJ([]interface{}{minLong, minLong, int64(-1), int64(-1)}), "long overflow")
+	checkArithmeticException(t, "subtractExact(MIN_VALUE, 1)", subtractExactJJ([]interface{}{minLong, minLong, int64(1), int64(1)}), "long overflow")
+	checkArithmeticException(t, "subtractExact(0, MIN_VALUE)", subtractExactJJ([]interface{}{int64(0), int64(0), minLong, minLong}), "long overflow")
+	checkArithmeticException(t, "incrementExact(MAX_VALUE)", incrementExactJ([]interface{}{maxLong, maxLong}), "long overflow")
+	checkArithmeticException(t, "decrementExact(MIN_VALUE)", decrementExactJ([]interface{}{minLong, minLong}), "long overflow")
+	checkArithmeticException(t, "negateExact(MIN_VALUE)", negateExactJ([]interface{}{minLong, minLong}), "long overflow")
+
+	// multiplication: the largest products that fit, and the smallest that do not
+	if ret := multiplyExactJx([]interface{}{int64(1) << 62, int64(1) << 62, int64(-2), int64(-2)}); ret != minLong {
+		t.Errorf("multiplyExact(2^62, -2): expected MIN_VALUE, got: %v", ret)
+	}
+	if ret := multiplyExactJx([]interface{}{minLong, minLong, int64(1), int64(1)}); ret != minLong {
+		t.Errorf("multiplyExact(MIN_VALUE, 1): expected MIN_VALUE, got: %v", ret)
+	}
+	if ret := multiplyExactJx([]interface{}{int64(-3037000499), int64(-3037000499), int64(-3037000499)}); ret != int64(9223372030926249001) {
+		t.Errorf("multiplyExact(-3037000499, -3037000499): expected 9223372030926249001, got: %v", ret)
+	}
+	checkArithmeticException(t, "multiplyExact(2^62, 2)", multiplyExactJx([]interface{}{int64(1) << 62, int64(1) << 62, int64(2), int64(2)}), "long overflow")
+	checkArithmeticException(t, "multiplyExact(MIN_VALUE, -1)", multiplyExactJx([]interface{}{minLong, minLong, int64(-1)}), "long overflow")
+	checkArithmeticException(t, "multiplyExact(2^32, 2^32)", multiplyExactJx([]interface{}{int64(1) << 32, int64(1) << 32, int64(1) << 32}), "long overflow")
+}
+
+func TestMathAbsExact(t *testing.T) {
+	if ret := absExactI([]interface{}{int64(-7)}); ret != int64(7) {
+		t.Errorf("absExact(-7): expected 7, got: %v", ret)
+	}
+	checkArithmeticException(t, "absExact(Integer.MIN_VALUE)", absExactI([]interface{}{int64(math.MinInt32)}),
+		"Overflow to represent absolute value of Integer.MIN_VALUE")
+	checkArithmeticException(t, "absExact(Long.MIN_VALUE)", absExactJ([]interface{}{int64(math.MinInt64), int64(math.MinInt64)}),
+		"Overflow to represent absolute value of Long.MIN_VALUE")
+}
+
+func TestMathFloorDivMod(t *testing.T) {
+	for _, test := range []struct{ x, y, div, mod int64 }{
+		{-7, 3, -3, 2}, {7, -3, -3, -2}, {-7, -3, 2, -1}, {7, 3, 2, 1}, {-6, 3, -2, 0},
+	} {
+		if ret := floorDivII([]interface{}{test.x, test.y}); ret != test.div {
+			t.Errorf("floorDiv(%d, %d): expected %d, got: %v", test.x, test.y, test.div, ret)
+		}
+		if ret := floorModII([]interface{}{test.x, test.y}); ret != test.mod {
+			t.Errorf("floorMod(%d, %d): expected %d, got: %v", test.x, test.y, test.mod, ret)
+		}
+		// long arguments take two parameter slots, so the second argument is in params[2]
+		if ret := floorDivJx([]interface{}{test.x, test.x, test.y, test.y}); ret != test.div {
+			t.Errorf("floorDiv(%dL, %dL): expected %d, got: %v", test.x, test.y, test.div, ret)
+		}
+		if ret := floorModJx([]interface{}{test.x, test.x, test.y, test.y}); ret != test.mod {
+			t.Errorf("floorMod(%dL, %dL): expected %d, got: %v", test.x, test.y, test.mod, ret)
+		}
+	}
+
+	// MIN_VALUE / -1 overflows to MIN_VALUE, leaving no remainder
+	minInt, minLong := int64(math.MinInt32), int64(math.MinInt64)
+	if ret := floorDivII([]interface{}{minInt, int64(-1)}); ret != minInt {
+		t.Errorf("floorDiv(Integer.MIN_VALUE, -1): expected %d, got: %v", minInt, ret)
+	}
+	if ret := floorDivJx([]interface{}{minLong, minLong, int64(-1), int64(-1)}); ret != minLong {
+		t.Errorf("floorDiv(Long.MIN_VALUE, -1): expected %d, got: %v", minLong, ret)
+	}
+	if ret := floorModJx([]interface{}{minLong, minLong, int64(-1), int64(-1)}); ret != int64(0) {
+		t.Errorf("floorMod(Long.MIN_VALUE, -1): expected 0, got: %v", ret)
+	}
+
+	checkArithmeticException(t, "floorDiv(1, 0)", floorDivII([]interface{}{int64(1), int64(0)}), "/ by zero")
+	checkArithmeticException(t, "floorMod(1, 0)", floorModII([]interface{}{int64(1), int64(0)}), "/ by zero")
+	checkArithmeticException(t, "floorDiv(1L, 0L)", floorDivJx([]interface{}{int64(1), int64(1), int64(0), int64(0)}), "/ by zero")
+	checkArithmeticException(t, "floorMod(1L, 0L)", floorModJx([]interface{}{int64(1), int64(1), int64(0), int64(0)}), "/ by zero")
+}
+
+// ceil and floor return doubles whose sign, including that of a zero, is as Java's:
+// the results are compared through Double.doubleToLongBits(), which tells -0.0 from 0.0
+func TestMathCeilFloorSignedZero(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	for _, test := range []struct {
+		name     string
+		function func([]interface{}) interface{}
+		arg      float64
+		want     float64
+	}{
+		{"floor", floorFloat64, negZero, negZero},
+		{"floor", floorFloat64, 0.0, 0.0},
+		{"floor", floorFloat64, 0.5, 0.0},
+		{"floor", floorFloat64, -0.5, -1.0},
+		{"floor", floorFloat64, -2.5, -3.0},
+		{"floor", floorFloat64, math.Inf(-1), math.Inf(-1)},
+		{"ceil", ceilFloat64, -0.5, negZero},
+		{"ceil", ceilFloat64, -0.999, negZero},
+		{"ceil", ceilFloat64, negZero, negZero},
+		{"ceil", ceilFloat64, 0.0, 0.0},
+		{"ceil", ceilFloat64, 0.5, 1.0},
+		{"ceil", ceilFloat64, -1.5, -1.0},
+		{"ceil", ceilFloat64, math.Inf(1), math.Inf(1)},
+	} {
+		ret := test.function([]interface{}{test.arg, test.arg})
+		gotBits := doubleToLongBits([]interface{}{ret, ret})
+		wantBits := doubleToLongBits([]interface{}{test.want, test.want})
+		if gotBits != wantBits {
+			t.Errorf("Math.%s(%v): expected %v (bits %x), got %v (bits %x)",
+				test.name, test.arg, test.want, wantBits, ret, gotBits)
+		}
+	}
+
+	for _, function := range []func([]interface{}) interface{}{floorFloat64, ceilFloat64} {
+		if ret := function([]interface{}{math.NaN(), math.NaN()}); !math.IsNaN(ret.(float64)) {
+			t.Errorf("Expected NaN to give NaN, got: %v", ret)
+		}
+	}
+}
+
+// Math.random() returns values in [0, 1) that vary and are uniformly distributed
+func TestMathRandom(t *testing.T) {
+	const draws = 10000
+	var sum float64
+	distinct := make(map[float64]bool)
+	for i := 0; i < draws; i++ {
+		value, ok := randomFloat64(nil).(float64)
+		if !ok || value < 0.0 || value >= 1.0 {
+			t.Fatalf("Math.random(): expected a double in [0, 1), got: %v", value)
+		}
+		sum += value
+		distinct[value] = true
+	}
+	if mean := sum / draws; math.Abs(mean-0.5) > 0.02 {
+		t.Errorf("Math.random(): expected a mean near 0.5, got: %f", mean)
+	}
+	if len(distinct) < draws-10 {
+		t.Errorf("Math.random(): expected varying values, got only %d distinct values in %d draws",
+			len(distinct), draws)
+	}
+}
+
+// the shared generator can be used from several goroutines at once
+func TestMathRandomConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if value := randomFloat64(nil).(float64); value < 0.0 || value >= 1.0 {
+					t.Errorf("Math.random(): expected a double in [0, 1), got: %v", value)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,124 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/object"
+	"math"
+)
+
+// The accessors of java.lang.Number: byteValue(), doubleValue(), floatValue(), intValue(),
+// longValue(), and shortValue(), which each of the boxed numeric classes (Byte, Short,
+// Integer, Long, Float, and Double) implements, so that code can work on a Number without
+// knowing its class. The value field of a boxed number is an int64 for the integral classes
+// and a float64 for Float and Double. The conversions are those of Java's casts:
+//   - an integral value is narrowed by keeping its low-order bits, as in (byte) 300 == 44
+//   - a floating-point value is converted to an integral value by truncating toward zero,
+//     with NaN converted to 0 and values out of range to the nearest int or long. It's
+//     converted first to an int for byteValue() and shortValue(), which then narrow it.
+//   - floatValue() rounds the value to the nearest float
+
+// loadNumberValues adds the Number accessors of one of the boxed numeric classes
+func loadNumberValues(className string) {
+
+	MethodSignatures[className+".byteValue()B"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  numberByteValue,
+		}
+
+	MethodSignatures[className+".doubleValue()D"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  numberDoubleValue,
+		}
+
+	MethodSignatures[className+".floatValue()F"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  numberFloatValue,
+		}
+
+	MethodSignatures[className+".intValue()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  numberIntValue,
+		}
+
+	MethodSignatures[className+".longValue()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  numberLongValue,
+		}
+
+	MethodSignatures[className+".shortValue()S"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  numberShortValue,
+		}
+}
+
+// "java/lang/Number.byteValue()B"
+func numberByteValue(params []interface{}) interface{} {
+	return int64(int8(numberIntValue(params).(int64)))
+}
+
+// "java/lang/Number.doubleValue()D"
+func numberDoubleValue(params []interface{}) interface{} {
+	switch value := params[0].(*object.Object).FieldTable["value"].Fvalue.(type) {
+	case int64:
+		return float64(value)
+	default:
+		return value.(float64)
+	}
+}
+
+// "java/lang/Number.floatValue()F"
+func numberFloatValue(params []interface{}) interface{} {
+	return float64(float32(numberDoubleValue(params).(float64)))
+}
+
+// "java/lang/Number.intValue()I"
+func numberIntValue(params []interface{}) interface{} {
+	switch value := params[0].(*object.Object).FieldTable["value"].Fvalue.(type) {
+	case int64:
+		return int64(int32(value))
+	default:
+		return floatToIntegral(value.(float64), math.MinInt32, math.MaxInt32)
+	}
+}
+
+// "java/lang/Number.longValue()J"
+func numberLongValue(params []interface{}) interface{} {
+	switch value := params[0].(*object.Object).FieldTable["value"].Fvalue.(type) {
+	case int64:
+		return value
+	default:
+		return floatToIntegral(value.(float64), math.MinInt64, math.MaxInt64)
+	}
+}
+
+// "java/lang/Number.shortValue()S"
+func numberShortValue(params []interface{}) interface{} {
+	return int64(int16(numberIntValue(params).(int64)))
+}
+
+// floatToIntegral converts a floating-point value to an integral value in the range from
+// min to max as Java does: NaN is 0, values out of range are min or max, and other values
+// are truncated toward zero.
+func floatToIntegral(value float64, min, max int64) int64 {
+	switch {
+	case math.IsNaN(value):
+		return 0
+	case value <= float64(min):
+		return min
+	case value >= float64(max):
+		return max
+	default:
+		return int64(value)
+	}
+}
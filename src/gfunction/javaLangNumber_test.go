@@ -0,0 +1,83 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"math"
+	"testing"
+)
+
+// callNumberMethod calls a Number accessor as Java code holding a Number reference would:
+// through the method of the object's own class.
+func callNumberMethod(t *testing.T, number *object.Object, methodName string) interface{} {
+	className := object.GoStringFromStringPoolIndex(number.KlassName)
+	gmeth, ok := MethodSignatures[className+"."+methodName]
+	if !ok {
+		t.Fatalf("%s does not implement %s", className, methodName)
+	}
+	return gmeth.GFunction([]interface{}{number})
+}
+
+func TestNumberIntValueOfDouble(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Lang_Double()
+
+	for _, tc := range []struct {
+		value float64
+		want  int64
+	}{
+		{3.99, 3}, {-3.99, -3}, {0.5, 0}, {math.NaN(), 0},
+		{1e10, math.MaxInt32}, {-1e10, math.MinInt32}, {math.Inf(1), math.MaxInt32},
+	} {
+		var number *object.Object = populator("java/lang/Double", types.Double, tc.value)
+		if got := callNumberMethod(t, number, "intValue()I"); got != tc.want {
+			t.Errorf("Double(%v).intValue(): expected %d, got %v", tc.value, tc.want, got)
+		}
+	}
+}
+
+func TestNumberAccessorsOfEveryClass(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Lang_Byte()
+	Load_Lang_Short()
+	Load_Lang_Integer()
+	Load_Lang_Long()
+	Load_Lang_Float()
+	Load_Lang_Double()
+
+	// byteValue, shortValue, intValue, longValue, floatValue, doubleValue
+	for _, tc := range []struct {
+		className string
+		fieldType string
+		value     interface{}
+		want      [6]interface{}
+	}{
+		{"java/lang/Byte", types.Byte, int64(-5),
+			[6]interface{}{int64(-5), int64(-5), int64(-5), int64(-5), float64(-5), float64(-5)}},
+		{"java/lang/Short", types.Short, int64(300),
+			[6]interface{}{int64(44), int64(300), int64(300), int64(300), float64(300), float64(300)}},
+		{"java/lang/Integer", types.Int, int64(70000),
+			[6]interface{}{int64(112), int64(4464), int64(70000), int64(70000), float64(70000), float64(70000)}},
+		{"java/lang/Long", types.Long, int64(1) << 33,
+			[6]interface{}{int64(0), int64(0), int64(0), int64(1) << 33, float64(1 << 33), float64(1 << 33)}},
+		{"java/lang/Float", types.Float, float64(-200.75),
+			[6]interface{}{int64(56), int64(-200), int64(-200), int64(-200), float64(-200.75), float64(-200.75)}},
+		{"java/lang/Double", types.Double, float64(0.1),
+			[6]interface{}{int64(0), int64(0), int64(0), int64(0), float64(float32(0.1)), float64(0.1)}},
+	} {
+		number := populator(tc.className, tc.fieldType, tc.value)
+		for i, methodName := range []string{"byteValue()B", "shortValue()S", "intValue()I",
+			"longValue()J", "floatValue()F", "doubleValue()D"} {
+			if got := callNumberMethod(t, number, methodName); got != tc.want[i] {
+				t.Errorf("%s(%v).%s: expected %v, got %v", tc.className, tc.value, methodName, tc.want[i], got)
+			}
+		}
+	}
+}
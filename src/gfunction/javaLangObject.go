@@ -7,7 +7,18 @@
 package gfunction
 
 import (
+	"container/list"
+	"fmt"
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/frames"
 	"jacobin/object"
+	"jacobin/types"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
 )
 
 // Implementation of some of the functions in Java/lang/Class.
@@ -20,18 +31,203 @@ func Load_Lang_Object() {
 			GFunction:  justReturn,
 		}
 
+	MethodSignatures["java/lang/Object.clone()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  objectClone,
+		}
+
 	MethodSignatures["java/lang/Object.getClass()Ljava/lang/Class;"] =
 		GMeth{
 			ParamSlots: 0,
 			GFunction:  objectGetClass,
 		}
 
+	MethodSignatures["java/lang/Object.hashCode()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  objectHashCode,
+		}
+
+	MethodSignatures["java/lang/Object.notify()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    objectNotify,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Object.notifyAll()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    objectNotifyAll,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Object.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  objectToString,
+		}
+
+	MethodSignatures["java/lang/Object.wait()V"] =
+		GMeth{
+			ParamSlots:   0,
+			GFunction:    objectWait,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Object.wait(J)V"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    objectWaitTimeout,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Object.wait(JI)V"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    objectWaitTimeoutNanos,
+			NeedsContext: true,
+		}
+
 }
 
-// "java/lang/Object.getClass()Ljava/lang/Class;"
+// "java/lang/Object.clone()Ljava/lang/Object;" makes a shallow copy of an object. The copy of
+// an array is a new array holding the same elements, so changes to one array's elements don't
+// affect the other. This is what lets an enum's values() return a copy of its $VALUES array
+// that callers can modify without changing the enum's own array. State that's kept in a golang
+// slice or map, rather than in Java fields, such as a Throwable's suppressed exceptions or the
+// entries of a Properties, is copied likewise, so changes made through the copy don't show up
+// in the original. As in Java, any array can be cloned, but another object only if its class
+// implements java.lang.Cloneable; otherwise, clone() throws a CloneNotSupportedException.
+func objectClone(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "Object.clone: object is null")
+	}
+	obj := params[0].(*object.Object)
+
+	className := object.GoStringFromStringPoolIndex(obj.KlassName)
+	if !strings.HasPrefix(className, types.Array) && !classloader.ImplementsInterface(className, "java/lang/Cloneable") {
+		return getGErrBlk(excNames.CloneNotSupportedException, strings.ReplaceAll(className, "/", "."))
+	}
+
+	clone := object.MakeEmptyObject()
+	clone.KlassName = obj.KlassName
+	object.TrackObject(clone)
+	for name, fld := range obj.FieldTable {
+		switch value := fld.Fvalue.(type) {
+		case []byte:
+			fld.Fvalue = slices.Clone(value)
+		case []int64:
+			fld.Fvalue = slices.Clone(value)
+		case []float64:
+			fld.Fvalue = slices.Clone(value)
+		case []*object.Object:
+			fld.Fvalue = slices.Clone(value)
+		case map[string]string:
+			fld.Fvalue = maps.Clone(value)
+		}
+		clone.FieldTable[name] = fld
+	}
+	return clone
+}
+
+// "java/lang/Object.getClass()Ljava/lang/Class;" -- the Class object, whose getName()
+// returns the dotted name of the object's class
 func objectGetClass(params []interface{}) interface{} {
 	obj := params[0].(*object.Object)
-	wint := obj.KlassName
-	name := object.GoStringFromStringPoolIndex(wint)
-	return object.StringObjectFromGoString("class " + name)
+	name := object.GoStringFromStringPoolIndex(obj.KlassName)
+	return makeClassObject(strings.ReplaceAll(name, "/", "."))
+}
+
+// "java/lang/Object.hashCode()I" -- the identity hash code, which is
+// kept in the object's mark word (see object/identityHash.go)
+func objectHashCode(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	return int64(int32(obj.Mark.Hash))
+}
+
+// "java/lang/Object.toString()Ljava/lang/String;" -- as in the JDK, the class name followed
+// by @ and the identity hash code in hex, e.g., com.example.Thing@1b6d3586
+// It's built as Object.toString() builds it, from getClass().getName() and the identity hash code.
+func objectToString(params []interface{}) interface{} {
+	classObj := objectGetClass(params)
+	className := object.GoStringFromStringObject(getName([]interface{}{classObj}).(*object.Object))
+	hash := uint32(objectHashCode(params).(int64))
+	return object.StringObjectFromGoString(fmt.Sprintf("%s@%x", className, hash))
+}
+
+// The wait and notify functions need the ID of the calling thread, which they get
+// from the frame at the top of the frame stack. So params[0] is the frame stack and
+// params[1] is the object whose monitor is used.
+func monitorThreadID(params []interface{}) int {
+	fs := params[0].(*list.List)
+	return fs.Front().Value.(*frames.Frame).Thread
+}
+
+// "java/lang/Object.notify()V"
+func objectNotify(params []interface{}) interface{} {
+	obj := params[1].(*object.Object)
+	if err := obj.MonitorNotify(monitorThreadID(params), false); err != nil {
+		return getGErrBlk(excNames.IllegalMonitorStateException, "Object.notify: "+err.Error())
+	}
+	return nil
+}
+
+// "java/lang/Object.notifyAll()V"
+func objectNotifyAll(params []interface{}) interface{} {
+	obj := params[1].(*object.Object)
+	if err := obj.MonitorNotify(monitorThreadID(params), true); err != nil {
+		return getGErrBlk(excNames.IllegalMonitorStateException, "Object.notifyAll: "+err.Error())
+	}
+	return nil
+}
+
+// "java/lang/Object.wait()V" waits until notified
+func objectWait(params []interface{}) interface{} {
+	return waitOnMonitor(params, 0)
+}
+
+// "java/lang/Object.wait(J)V" waits until notified or until the timeout (in milliseconds)
+// elapses. A timeout of zero means wait until notified.
+func objectWaitTimeout(params []interface{}) interface{} {
+	timeout := params[2].(int64)
+	if timeout < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "Object.wait: timeout value is negative")
+	}
+	return waitOnMonitor(params, millisToWaitDuration(timeout))
+}
+
+// "java/lang/Object.wait(JI)V" As in the JDK, any nanoseconds round the timeout up by a millisecond.
+// (The long occupies two parameter slots, so the nanoseconds are in params[4].)
+func objectWaitTimeoutNanos(params []interface{}) interface{} {
+	timeout := params[2].(int64)
+	nanos := params[4].(int64)
+	if timeout < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "Object.wait: timeout value is negative")
+	}
+	if nanos < 0 || nanos > 999999 {
+		return getGErrBlk(excNames.IllegalArgumentException, "Object.wait: nanosecond timeout value out of range")
+	}
+	if nanos > 0 && timeout < math.MaxInt64 {
+		timeout++
+	}
+	return waitOnMonitor(params, millisToWaitDuration(timeout))
+}
+
+// a timeout too long to express as a time.Duration (over 292 years) is treated as no timeout
+func millisToWaitDuration(millis int64) time.Duration {
+	if millis > math.MaxInt64/int64(time.Millisecond) {
+		return 0
+	}
+	return time.Duration(millis) * time.Millisecond
+}
+
+func waitOnMonitor(params []interface{}, timeout time.Duration) interface{} {
+	obj := params[1].(*object.Object)
+	if err := obj.MonitorWait(monitorThreadID(params), timeout); err != nil {
+		errMsg := fmt.Sprintf("Object.wait: %s", err.Error())
+		return getGErrBlk(excNames.IllegalMonitorStateException, errMsg)
+	}
+	return nil
 }
@@ -0,0 +1,225 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/stringPool"
+	"jacobin/types"
+	"testing"
+)
+
+func TestObjectWaitWithoutMonitor(t *testing.T) {
+	globals.InitGlobals("test")
+
+	f := frames.CreateFrame(1)
+	f.Thread = 1
+	fs := frames.CreateFrameStack()
+	_ = frames.PushFrame(fs, f)
+	obj := object.MakeEmptyObject()
+
+	ret := objectWait([]interface{}{fs, obj})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.IllegalMonitorStateException {
+		t.Errorf("Expected IllegalMonitorStateException from wait() without monitor, got: %v", ret)
+	}
+
+	ret = objectNotify([]interface{}{fs, obj})
+	errBlk, ok = ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.IllegalMonitorStateException {
+		t.Errorf("Expected IllegalMonitorStateException from notify() without monitor, got: %v", ret)
+	}
+}
+
+func TestObjectWaitTimeout(t *testing.T) {
+	globals.InitGlobals("test")
+
+	f := frames.CreateFrame(1)
+	f.Thread = 1
+	fs := frames.CreateFrameStack()
+	_ = frames.PushFrame(fs, f)
+	obj := object.MakeEmptyObject()
+
+	ret := objectWaitTimeout([]interface{}{fs, obj, int64(-1), int64(-1)})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Expected IllegalArgumentException from wait() with a negative timeout, got: %v", ret)
+	}
+
+	obj.MonitorEnter(1)
+	ret = objectWaitTimeout([]interface{}{fs, obj, int64(5), int64(5)})
+	if ret != nil {
+		t.Errorf("Expected wait(5) to time out normally, got: %v", ret)
+	}
+	_ = obj.MonitorExit(1)
+}
+
+// An enum's values() returns $VALUES.clone(), so each caller gets its own copy of the array
+func TestObjectCloneOfEnumValues(t *testing.T) {
+	globals.InitGlobals("test")
+
+	enumClass := "test/Color"
+	constants := []string{"RED", "GREEN", "BLUE"}
+	values := object.Make1DimRefArray(&enumClass, int64(len(constants)))
+	elements := values.FieldTable["value"].Fvalue.([]*object.Object)
+	for i, name := range constants {
+		elements[i] = object.MakeEmptyObjectWithClassName(&enumClass)
+		elements[i].FieldTable["name"] = object.Field{Ftype: types.Ref, Fvalue: object.StringObjectFromGoString(name)}
+	}
+
+	first := objectClone([]interface{}{values}).(*object.Object)
+	second := objectClone([]interface{}{values}).(*object.Object)
+	if first == values || first.KlassName != values.KlassName {
+		t.Fatalf("Expected clone() to return a new array of the same class")
+	}
+
+	firstElements := first.FieldTable["value"].Fvalue.([]*object.Object)
+	secondElements := second.FieldTable["value"].Fvalue.([]*object.Object)
+	for i := range elements {
+		if firstElements[i] != elements[i] || secondElements[i] != elements[i] {
+			t.Errorf("Expected element %d of the clones to be the same enum constant as the original", i)
+		}
+	}
+
+	// changing one copy leaves the other copy and $VALUES unchanged
+	firstElements[0] = firstElements[2]
+	if secondElements[0] != elements[0] {
+		t.Errorf("Changing one result of values() changed another")
+	}
+	if values.FieldTable["value"].Fvalue.([]*object.Object)[0] != elements[0] {
+		t.Errorf("Changing a result of values() changed $VALUES")
+	}
+	if object.ArrayLength(second) != int64(len(constants)) {
+		t.Errorf("Expected the clone to have %d elements, got %d", len(constants), object.ArrayLength(second))
+	}
+}
+
+func TestObjectClonePrimitiveArray(t *testing.T) {
+	globals.InitGlobals("test")
+
+	arr := object.Make1DimArray(object.INT, 3)
+	arr.FieldTable["value"].Fvalue.([]int64)[1] = 42
+
+	clone := objectClone([]interface{}{arr}).(*object.Object)
+	clone.FieldTable["value"].Fvalue.([]int64)[1] = 7
+	if arr.FieldTable["value"].Fvalue.([]int64)[1] != 42 {
+		t.Errorf("Changing the clone of an int array changed the original")
+	}
+
+	ret := objectClone([]interface{}{object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException from clone() of null, got: %v", ret)
+	}
+}
+
+// insertCloneTestClass adds a class that extends Object to the method area, with the named
+// interfaces
+func insertCloneTestClass(name string, interfaces ...string) {
+	superclass := types.ObjectClassName
+	var interfaceIndices []uint16
+	for _, iface := range interfaces {
+		interfaceIndices = append(interfaceIndices, uint16(stringPool.GetStringIndex(&iface)))
+	}
+	classloader.MethAreaInsert(name, &classloader.Klass{Status: 'X', Loader: "bootstrap",
+		Data: &classloader.ClData{Name: name, Superclass: superclass,
+			SuperclassIndex: stringPool.GetStringIndex(&superclass), Interfaces: interfaceIndices}})
+}
+
+// An object whose class implements Cloneable is copied field by field; the copy shares the
+// objects the original's fields refer to.
+func TestObjectCloneOfCloneable(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	insertCloneTestClass("java/lang/Cloneable")
+	insertCloneTestClass("test/Point", "java/lang/Cloneable")
+	insertCloneTestClass("test/Line", "java/lang/Cloneable")
+
+	className := "test/Point"
+	point := object.MakeEmptyObjectWithClassName(&className)
+	point.FieldTable["x"] = object.Field{Ftype: types.Int, Fvalue: int64(3)}
+	className = "test/Line"
+	line := object.MakeEmptyObjectWithClassName(&className)
+	line.FieldTable["end"] = object.Field{Ftype: "Ltest/Point;", Fvalue: point}
+	line.FieldTable["width"] = object.Field{Ftype: types.Int, Fvalue: int64(1)}
+
+	clone, ok := objectClone([]interface{}{line}).(*object.Object)
+	if !ok || clone == line || clone.KlassName != line.KlassName {
+		t.Fatalf("Expected clone() to return a new test/Line, got: %v", clone)
+	}
+	if clone.FieldTable["end"].Fvalue != point || clone.FieldTable["width"].Fvalue != int64(1) {
+		t.Errorf("Expected the clone to have the same field values, got: %v", clone.FieldTable)
+	}
+	clone.FieldTable["width"] = object.Field{Ftype: types.Int, Fvalue: int64(2)}
+	if line.FieldTable["width"].Fvalue != int64(1) {
+		t.Errorf("Changing a field of the clone changed the original")
+	}
+}
+
+// State kept in golang slices and maps is copied, so changes made through the clone don't
+// show up in the original
+func TestObjectCloneCopiesGolangState(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	insertCloneTestClass("java/lang/Cloneable")
+	insertCloneTestClass("test/Holder", "java/lang/Cloneable")
+
+	className := "test/Holder"
+	first := object.StringObjectFromGoString("first")
+	holder := object.MakeEmptyObjectWithClassName(&className)
+	holder.FieldTable["suppressedExceptions"] = object.Field{Ftype: types.RefArray,
+		Fvalue: make([]*object.Object, 1, 4)}
+	holder.FieldTable["suppressedExceptions"].Fvalue.([]*object.Object)[0] = first
+	holder.FieldTable["value"] = object.Field{Ftype: types.Properties, Fvalue: map[string]string{"key": "value"}}
+
+	clone := objectClone([]interface{}{holder}).(*object.Object)
+	suppressed := clone.FieldTable["suppressedExceptions"].Fvalue.([]*object.Object)
+	suppressed[0] = object.StringObjectFromGoString("second")
+	_ = append(suppressed, object.StringObjectFromGoString("third"))
+	clone.FieldTable["value"].Fvalue.(map[string]string)["key"] = "changed"
+
+	original := holder.FieldTable["suppressedExceptions"].Fvalue.([]*object.Object)
+	if original[0] != first || original[:2][1] != nil {
+		t.Errorf("Changing the clone's slice changed the original's")
+	}
+	if value := holder.FieldTable["value"].Fvalue.(map[string]string)["key"]; value != "value" {
+		t.Errorf("Changing the clone's map changed the original's: got %s", value)
+	}
+}
+
+func TestObjectCloneNotSupported(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	insertCloneTestClass("java/lang/Cloneable")
+	insertCloneTestClass("test/Plain")
+
+	className := "test/Plain"
+	plain := object.MakeEmptyObjectWithClassName(&className)
+	ret := objectClone([]interface{}{plain})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.CloneNotSupportedException ||
+		errBlk.ErrMsg != "test.Plain" {
+		t.Errorf("Expected CloneNotSupportedException for test.Plain, got: %v", ret)
+	}
+}
+
+func TestObjectToString(t *testing.T) {
+	globals.InitGlobals("test")
+	className := "com/example/Thing"
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.Mark.Hash = 0x1b6d3586
+
+	str := object.GoStringFromStringObject(objectToString([]interface{}{obj}).(*object.Object))
+	if str != "com.example.Thing@1b6d3586" {
+		t.Errorf("Expected com.example.Thing@1b6d3586, got: %s", str)
+	}
+}
@@ -0,0 +1,229 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// Implementation of java/lang/ProcessBuilder and java/lang/Process, using golang's os/exec.
+// Only the basics are supported: a ProcessBuilder is created with the command and its
+// arguments, as an array or a List of Strings, and start() runs it. The output of the process is read through the InputStream
+// returned by Process.getInputStream(), which is a FileInputStream on a pipe; the process's
+// standard error is the JVM's, and its standard input is empty. destroy() kills the process.
+//
+// Starting processes must be enabled with the -allowExec option, so that a sandboxed run
+// can't spawn them. If it isn't, start() throws a SecurityException.
+
+// the key of the field of a ProcessBuilder that holds its command, as an array of Strings,
+// and of the field of a Process that holds its processState. (The first is not the JDK's
+// "command" field, which is a List.)
+const processBuilderCommand = "commandArray"
+const processStateField = "process"
+
+// processState is what's kept of a running or finished process
+type processState struct {
+	cmd      *exec.Cmd
+	stdout   *os.File      // the end of the pipe from which the process's output is read
+	exited   chan struct{} // closed when the process exits
+	exitCode int64         // set when the process exits
+}
+
+func Load_Lang_ProcessBuilder() {
+
+	MethodSignatures["java/lang/ProcessBuilder.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/lang/ProcessBuilder.<init>([Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  processBuilderInit,
+		}
+
+	MethodSignatures["java/lang/ProcessBuilder.<init>(Ljava/util/List;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    processBuilderInitList,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/ProcessBuilder.start()Ljava/lang/Process;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  processBuilderStart,
+		}
+
+	MethodSignatures["java/lang/Process.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/lang/Process.destroy()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  processDestroy,
+		}
+
+	MethodSignatures["java/lang/Process.exitValue()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  processExitValue,
+		}
+
+	MethodSignatures["java/lang/Process.getInputStream()Ljava/io/InputStream;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  processGetInputStream,
+		}
+
+	MethodSignatures["java/lang/Process.waitFor()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  processWaitFor,
+		}
+
+}
+
+// "java/lang/ProcessBuilder.<init>([Ljava/lang/String;)V" which is ProcessBuilder(String... command)
+func processBuilderInit(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "ProcessBuilder: command is null")
+	}
+	params[0].(*object.Object).FieldTable[processBuilderCommand] =
+		object.Field{Ftype: "[Ljava/lang/String;", Fvalue: params[1]}
+	return nil
+}
+
+// "java/lang/ProcessBuilder.<init>(Ljava/util/List;)V" The strings in the list are copied
+// into the array that start() runs. params[0] = the frame stack, params[1] = the
+// ProcessBuilder, params[2] = the list
+func processBuilderInitList(params []interface{}) interface{} {
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "ProcessBuilder: command is null")
+	}
+	elements, errBlk := collectionElements(params[0].(*list.List), params[2].(*object.Object), "ProcessBuilder")
+	if errBlk != nil {
+		return errBlk
+	}
+	stringClassName := types.StringClassName
+	commandArray := object.Make1DimRefArray(&stringClassName, int64(len(elements)))
+	copy(commandArray.FieldTable["value"].Fvalue.([]*object.Object), elements)
+	return processBuilderInit([]interface{}{params[1], commandArray})
+}
+
+// "java/lang/ProcessBuilder.start()Ljava/lang/Process;"
+func processBuilderStart(params []interface{}) interface{} {
+	if !globals.GetGlobalRef().AllowExec {
+		return getGErrBlk(excNames.SecurityException,
+			"ProcessBuilder.start: starting processes is disabled; run with -allowExec to enable it")
+	}
+
+	var args []*object.Object
+	if commandArray, ok := params[0].(*object.Object).FieldTable[processBuilderCommand].Fvalue.(*object.Object); ok &&
+		!object.IsNull(commandArray) {
+		args, _ = commandArray.FieldTable["value"].Fvalue.([]*object.Object)
+	}
+	var command []string
+	for _, arg := range args {
+		if object.IsNull(arg) {
+			return getGErrBlk(excNames.NullPointerException, "ProcessBuilder.start: command contains null")
+		}
+		command = append(command, object.GoStringFromStringObject(arg))
+	}
+	if len(command) == 0 {
+		return getGErrBlk(excNames.IndexOutOfBoundsException, "ProcessBuilder.start: command is empty")
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		errMsg := fmt.Sprintf("ProcessBuilder.start: os.Pipe() failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdout = writer
+	cmd.Stderr = os.Stderr
+	err = cmd.Start()
+	_ = writer.Close() // the process has its own copy, so that reader sees EOF when it exits
+	if err != nil {
+		_ = reader.Close()
+		errMsg := fmt.Sprintf("Cannot run program \"%s\": %s", command[0], err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+
+	state := &processState{cmd: cmd, stdout: reader, exited: make(chan struct{})}
+	go func() {
+		_ = cmd.Wait()
+		state.exitCode = processExitCode(cmd.ProcessState)
+		close(state.exited)
+	}()
+
+	className := "java/lang/Process"
+	process := object.MakeEmptyObjectWithClassName(&className)
+	process.FieldTable[processStateField] = object.Field{Ftype: types.Struct, Fvalue: state}
+	return process
+}
+
+// processExitCode returns the exit code of a process. As in Java, a process ended by a
+// signal has an exit code of 128 plus the number of the signal.
+func processExitCode(ps *os.ProcessState) int64 {
+	if status, ok := ps.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return int64(128 + int(status.Signal()))
+	}
+	return int64(ps.ExitCode())
+}
+
+// "java/lang/Process.destroy()V"
+func processDestroy(params []interface{}) interface{} {
+	state := params[0].(*object.Object).FieldTable[processStateField].Fvalue.(*processState)
+	select {
+	case <-state.exited: // already exited, so there's nothing to do
+	default:
+		_ = state.cmd.Process.Kill()
+	}
+	return nil
+}
+
+// "java/lang/Process.exitValue()I"
+func processExitValue(params []interface{}) interface{} {
+	state := params[0].(*object.Object).FieldTable[processStateField].Fvalue.(*processState)
+	select {
+	case <-state.exited:
+		return state.exitCode
+	default:
+		return getGErrBlk(excNames.IllegalThreadStateException, "process hasn't exited")
+	}
+}
+
+// "java/lang/Process.getInputStream()Ljava/io/InputStream;" returns a FileInputStream that
+// reads the process's output
+func processGetInputStream(params []interface{}) interface{} {
+	state := params[0].(*object.Object).FieldTable[processStateField].Fvalue.(*processState)
+	className := "java/io/FileInputStream"
+	stream := object.MakeEmptyObjectWithClassName(&className)
+	stream.FieldTable[FilePath] = object.Field{Ftype: types.ByteArray, Fvalue: []byte(state.stdout.Name())}
+	stream.FieldTable[FileHandle] = object.Field{Ftype: types.FileHandle, Fvalue: state.stdout}
+	return stream
+}
+
+// "java/lang/Process.waitFor()I" waits for the process to exit and returns its exit code
+func processWaitFor(params []interface{}) interface{} {
+	state := params[0].(*object.Object).FieldTable[processStateField].Fvalue.(*processState)
+	<-state.exited
+	return state.exitCode
+}
@@ -0,0 +1,142 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"os/exec"
+	"testing"
+)
+
+func makeTestProcessBuilder(command ...string) *object.Object {
+	className := "java/lang/ProcessBuilder"
+	pb := object.MakeEmptyObjectWithClassName(&className)
+	stringClassName := "java/lang/String"
+	commandArray := object.Make1DimRefArray(&stringClassName, int64(len(command)))
+	for i, arg := range command {
+		commandArray.FieldTable["value"].Fvalue.([]*object.Object)[i] = object.StringObjectFromGoString(arg)
+	}
+	processBuilderInit([]interface{}{pb, commandArray})
+	return pb
+}
+
+// with -allowExec, an echo command is run and its output read through getInputStream()
+func TestProcessBuilderRunsEcho(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("no echo command on this system")
+	}
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().AllowExec = true
+
+	ret := processBuilderStart([]interface{}{makeTestProcessBuilder("echo", "graveyard", "smash")})
+	process, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("ProcessBuilder.start(): expected a Process, got %v", ret)
+	}
+
+	stream := processGetInputStream([]interface{}{process}).(*object.Object)
+	var output []byte
+	buffer := object.Make1DimArray(object.BYTE, 4)
+	for {
+		count, ok := fisReadByteArray([]interface{}{stream, buffer}).(int64)
+		if !ok || count == -1 {
+			break
+		}
+		output = append(output, buffer.FieldTable["value"].Fvalue.([]byte)[:count]...)
+	}
+	if string(output) != "graveyard smash\n" {
+		t.Errorf("Process.getInputStream(): expected \"graveyard smash\\n\", got %q", string(output))
+	}
+
+	if code := processWaitFor([]interface{}{process}); code != int64(0) {
+		t.Errorf("Process.waitFor(): expected an exit code of 0, got %v", code)
+	}
+	if code := processExitValue([]interface{}{process}); code != int64(0) {
+		t.Errorf("Process.exitValue(): expected an exit code of 0, got %v", code)
+	}
+	processDestroy([]interface{}{process}) // the process has exited, so this does nothing
+}
+
+// a process that's still running has no exit value until it's destroyed
+func TestProcessDestroy(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("no sleep command on this system")
+	}
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().AllowExec = true
+
+	process := processBuilderStart([]interface{}{makeTestProcessBuilder("sleep", "30")}).(*object.Object)
+	ret := processExitValue([]interface{}{process})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalThreadStateException {
+		t.Errorf("Process.exitValue() while running: expected an IllegalThreadStateException, got %v", ret)
+	}
+
+	processDestroy([]interface{}{process})
+	if code := processWaitFor([]interface{}{process}); code == int64(0) {
+		t.Error("Process.waitFor() after destroy(): expected a nonzero exit code, got 0")
+	}
+}
+
+func TestProcessBuilderStartDisabledByDefault(t *testing.T) {
+	globals.InitGlobals("test")
+
+	ret := processBuilderStart([]interface{}{makeTestProcessBuilder("echo", "hello")})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.SecurityException {
+		t.Errorf("ProcessBuilder.start() without -allowExec: expected a SecurityException, got %v", ret)
+	}
+
+	globals.GetGlobalRef().AllowExec = true
+	ret = processBuilderStart([]interface{}{makeTestProcessBuilder("jacobin-no-such-program")})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IOException {
+		t.Errorf("ProcessBuilder.start() of a missing program: expected an IOException, got %v", ret)
+	}
+}
+
+// ProcessBuilder(List<String>) copies the command from the list, and start() runs it
+func TestProcessBuilderOfList(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("no echo command on this system")
+	}
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	globals.GetGlobalRef().AllowExec = true
+
+	className := "java/lang/ProcessBuilder"
+	pb := object.MakeEmptyObjectWithClassName(&className)
+	commandList := makeTestArrayList(0, "echo", "from", "a", "list")
+	if ret := processBuilderInitList([]interface{}{frames.CreateFrameStack(), pb, commandList}); ret != nil {
+		t.Fatalf("ProcessBuilder(List): unexpected error %v", ret)
+	}
+
+	process, ok := processBuilderStart([]interface{}{pb}).(*object.Object)
+	if !ok {
+		t.Fatalf("ProcessBuilder.start(): expected a Process")
+	}
+	if code := processWaitFor([]interface{}{process}); code != int64(0) {
+		t.Errorf("Process.waitFor(): expected an exit code of 0, got %v", code)
+	}
+}
+
+// a ProcessBuilder without a command, such as one whose JDK "command" field holds a List, throws
+// an exception from start() rather than failing a type assertion
+func TestProcessBuilderStartWithoutCommand(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().AllowExec = true
+
+	className := "java/lang/ProcessBuilder"
+	pb := object.MakeEmptyObjectWithClassName(&className)
+	pb.FieldTable["command"] = object.Field{Ftype: "Ljava/util/List;", Fvalue: makeTestArrayList(0, "echo")}
+	ret := processBuilderStart([]interface{}{pb})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IndexOutOfBoundsException {
+		t.Errorf("ProcessBuilder.start() without a command: expected an IndexOutOfBoundsException, got %v", ret)
+	}
+}
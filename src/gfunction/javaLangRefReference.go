@@ -0,0 +1,92 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/object"
+	"jacobin/types"
+)
+
+// Implementation of java/lang/ref/WeakReference and java/lang/ref/SoftReference, so that
+// programs and libraries that create them can run. Jacobin's objects are collected by the
+// golang garbage collector, which knows nothing of Java's weak and soft references, so a
+// reference holds its referent strongly, in the referent field, as the JDK's Reference does.
+// get() returns the referent until clear() is called, after which it returns null. As the
+// referent is never collected, a reference is never enqueued, and a ReferenceQueue passed
+// to the constructor is ignored. The static initializers are skipped, as the JDK's start
+// the thread that enqueues references.
+
+const referenceReferent = "referent"
+
+func Load_Lang_Ref_Reference() {
+
+	for _, className := range []string{"java/lang/ref/Reference", "java/lang/ref/WeakReference",
+		"java/lang/ref/SoftReference"} {
+
+		MethodSignatures[className+".<clinit>()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  justReturn,
+			}
+
+		MethodSignatures[className+".clear()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  referenceClear,
+			}
+
+		MethodSignatures[className+".get()Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  referenceGet,
+			}
+	}
+
+	for _, className := range []string{"java/lang/ref/WeakReference", "java/lang/ref/SoftReference"} {
+
+		MethodSignatures[className+".<init>(Ljava/lang/Object;)V"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  referenceInit,
+			}
+
+		MethodSignatures[className+".<init>(Ljava/lang/Object;Ljava/lang/ref/ReferenceQueue;)V"] =
+			GMeth{
+				ParamSlots: 2,
+				GFunction:  referenceInit,
+			}
+	}
+}
+
+// java/lang/ref/WeakReference.<init>(Ljava/lang/Object;)V and the constructor that also
+// takes a ReferenceQueue, which is ignored. The same for SoftReference.
+func referenceInit(params []interface{}) interface{} {
+	ref := params[0].(*object.Object)
+	referent, ok := params[1].(*object.Object)
+	if !ok {
+		referent = object.Null
+	}
+	ref.FieldTable[referenceReferent] = object.Field{Ftype: types.Ref, Fvalue: referent}
+	return nil
+}
+
+// java/lang/ref/Reference.get()Ljava/lang/Object; -- the referent, or null once the
+// reference has been cleared
+func referenceGet(params []interface{}) interface{} {
+	referent, ok := params[0].(*object.Object).FieldTable[referenceReferent].Fvalue.(*object.Object)
+	if !ok {
+		return object.Null
+	}
+	return referent
+}
+
+// java/lang/ref/Reference.clear()V
+func referenceClear(params []interface{}) interface{} {
+	ref := params[0].(*object.Object)
+	ref.FieldTable[referenceReferent] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+	return nil
+}
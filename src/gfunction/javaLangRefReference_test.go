@@ -0,0 +1,57 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/globals"
+	"jacobin/object"
+	"testing"
+)
+
+func TestWeakReferenceGetAndClear(t *testing.T) {
+	globals.InitGlobals("test")
+
+	for _, className := range []string{"java/lang/ref/WeakReference", "java/lang/ref/SoftReference"} {
+		referent := object.StringObjectFromGoString("referent")
+		ref := object.MakeEmptyObjectWithClassName(&className)
+		if ret := referenceInit([]interface{}{ref, referent}); ret != nil {
+			t.Fatalf("%s.<init>: unexpected result: %v", className, ret)
+		}
+
+		if ret := referenceGet([]interface{}{ref}); ret != referent {
+			t.Errorf("%s.get(): expected the referent, got: %v", className, ret)
+		}
+		if ret := referenceGet([]interface{}{ref}); ret != referent {
+			t.Errorf("%s.get(): expected the referent again, got: %v", className, ret)
+		}
+
+		referenceClear([]interface{}{ref})
+		if ret := referenceGet([]interface{}{ref}); ret != object.Null {
+			t.Errorf("%s.get() after clear(): expected null, got: %v", className, ret)
+		}
+	}
+}
+
+// a reference created with a ReferenceQueue, which is ignored, or to null
+func TestWeakReferenceWithQueueOrNull(t *testing.T) {
+	globals.InitGlobals("test")
+
+	className := "java/lang/ref/WeakReference"
+	queueClassName := "java/lang/ref/ReferenceQueue"
+	referent := object.StringObjectFromGoString("referent")
+	ref := object.MakeEmptyObjectWithClassName(&className)
+	referenceInit([]interface{}{ref, referent, object.MakeEmptyObjectWithClassName(&queueClassName)})
+	if ret := referenceGet([]interface{}{ref}); ret != referent {
+		t.Errorf("get(): expected the referent, got: %v", ret)
+	}
+
+	ref = object.MakeEmptyObjectWithClassName(&className)
+	referenceInit([]interface{}{ref, object.Null})
+	if ret := referenceGet([]interface{}{ref}); ret != object.Null {
+		t.Errorf("get() of a reference to null: expected null, got: %v", ret)
+	}
+}
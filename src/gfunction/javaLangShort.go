@@ -7,10 +7,17 @@
 package gfunction
 
 import (
+	"fmt"
 	"jacobin/object"
 	"jacobin/types"
 )
 
+// Short boundaries:
+const (
+	minShortValue = -32768
+	maxShortValue = 32767
+)
+
 func Load_Lang_Short() {
 
 	MethodSignatures["java/lang/Short.<clinit>()V"] =
@@ -19,10 +26,30 @@ func Load_Lang_Short() {
 			GFunction:  justReturn,
 		}
 
-	MethodSignatures["java/lang/Short.doubleValue()D"] =
+	loadNumberValues("java/lang/Short")
+
+	MethodSignatures["java/lang/Short.compare(SS)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  shortCompare,
+		}
+
+	MethodSignatures["java/lang/Short.parseShort(Ljava/lang/String;)S"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  shortParseShort,
+		}
+
+	MethodSignatures["java/lang/Short.toString()Ljava/lang/String;"] =
 		GMeth{
 			ParamSlots: 0,
-			GFunction:  shortDoubleValue,
+			GFunction:  shortToString,
+		}
+
+	MethodSignatures["java/lang/Short.toString(S)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  shortToStringS,
 		}
 
 	MethodSignatures["java/lang/Short.valueOf(S)Ljava/lang/Short;"] =
@@ -31,14 +58,42 @@ func Load_Lang_Short() {
 			GFunction:  shortValueOf,
 		}
 
+	MethodSignatures["java/lang/Short.valueOf(Ljava/lang/String;)Ljava/lang/Short;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  shortValueOfString,
+		}
+
+}
+
+// "java/lang/Short.compare(SS)I"
+func shortCompare(params []interface{}) interface{} {
+	return params[0].(int64) - params[1].(int64)
+}
+
+// "java/lang/Short.parseShort(Ljava/lang/String;)S"
+func shortParseShort(params []interface{}) interface{} {
+	parmObj := params[0].(*object.Object)
+	output, errBlk := parseIntegralInRange(parmObj, minShortValue, maxShortValue)
+	if errBlk != nil {
+		return errBlk
+	}
+	return output
 }
 
-// "java/lang/Short.doubleValue()D"
-func shortDoubleValue(params []interface{}) interface{} {
+// "java/lang/Short.toString()Ljava/lang/String;"
+func shortToString(params []interface{}) interface{} {
 	var ii int64
 	parmObj := params[0].(*object.Object)
 	ii = parmObj.FieldTable["value"].Fvalue.(int64)
-	return float64(ii)
+	str := fmt.Sprintf("%d", ii)
+	return object.StringObjectFromGoString(str)
+}
+
+// "java/lang/Short.toString(S)Ljava/lang/String;"
+func shortToStringS(params []interface{}) interface{} {
+	str := fmt.Sprintf("%d", params[0].(int64))
+	return object.StringObjectFromGoString(str)
 }
 
 // "java/lang/Short.valueOf(S)Ljava/lang/Short;"
@@ -46,3 +101,13 @@ func shortValueOf(params []interface{}) interface{} {
 	int64Value := params[0].(int64)
 	return populator("java/lang/Short", types.Short, int64Value)
 }
+
+// "java/lang/Short.valueOf(Ljava/lang/String;)Ljava/lang/Short;"
+func shortValueOfString(params []interface{}) interface{} {
+	parmObj := params[0].(*object.Object)
+	output, errBlk := parseIntegralInRange(parmObj, minShortValue, maxShortValue)
+	if errBlk != nil {
+		return errBlk
+	}
+	return populator("java/lang/Short", types.Short, output)
+}
@@ -0,0 +1,52 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"testing"
+)
+
+func TestShortParseShort(t *testing.T) {
+	globals.InitGlobals("test")
+
+	for _, tc := range []struct {
+		in   string
+		want int64
+	}{{"32767", 32767}, {"-32768", -32768}, {"300", 300}} {
+		ret := shortParseShort([]interface{}{object.StringObjectFromGoString(tc.in)})
+		if val, ok := ret.(int64); !ok || val != tc.want {
+			t.Errorf("Short.parseShort(%q): expected %d, got %v", tc.in, tc.want, ret)
+		}
+	}
+
+	for _, in := range []string{"32768", "-32769", "abc"} {
+		ret := shortParseShort([]interface{}{object.StringObjectFromGoString(in)})
+		errBlk, ok := ret.(*GErrBlk)
+		if !ok || errBlk.ExceptionType != excNames.NumberFormatException {
+			t.Errorf("Short.parseShort(%q): expected NumberFormatException, got %v", in, ret)
+		}
+	}
+}
+
+func TestShortBoxing(t *testing.T) {
+	globals.InitGlobals("test")
+
+	boxed := shortValueOf([]interface{}{int64(-1234)}).(*object.Object)
+	if got := numberShortValue([]interface{}{boxed}).(int64); got != -1234 {
+		t.Errorf("Short.valueOf(-1234).shortValue(): got %d", got)
+	}
+	str := shortToString([]interface{}{boxed}).(*object.Object)
+	if object.GoStringFromStringObject(str) != "-1234" {
+		t.Errorf("Short.toString(): expected \"-1234\", got %q", object.GoStringFromStringObject(str))
+	}
+	if ret := shortCompare([]interface{}{int64(500), int64(-500)}).(int64); ret <= 0 {
+		t.Errorf("Short.compare(500, -500): expected a positive result, got %d", ret)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"jacobin/shutdown"
 	"jacobin/util"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -30,9 +31,8 @@ func Load_Lang_StackTraceELement() {
 
 	MethodSignatures["java/lang/StackTraceElement.of(Ljava/lang/Throwable;I)[Ljava/lang/StackTraceElement;"] =
 		GMeth{
-			ParamSlots:   2,
-			GFunction:    of,
-			NeedsContext: true,
+			ParamSlots: 2,
+			GFunction:  of,
 		}
 
 	MethodSignatures["java/lang/StackTraceElement.initStackTraceElements([Ljava/lang/StackTraceElement;Ljava/lang/Throwable;)V"] =
@@ -41,6 +41,30 @@ func Load_Lang_StackTraceELement() {
 			GFunction:  initStackTraceElements,
 		}
 
+	MethodSignatures["java/lang/StackTraceElement.getClassName()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  steGetClassName,
+		}
+
+	MethodSignatures["java/lang/StackTraceElement.getMethodName()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  steGetMethodName,
+		}
+
+	MethodSignatures["java/lang/StackTraceElement.getFileName()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  steGetFileName,
+		}
+
+	MethodSignatures["java/lang/StackTraceElement.getLineNumber()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  steGetLineNumber,
+		}
+
 }
 
 /*
@@ -152,6 +176,21 @@ func initStackTraceElement(ste *object.Object, frm *frames.Frame) {
 
 	addField("sourceLine", "") // the default if no source line data is available
 	if !util.IsFilePartOfJDK(&frame.MethName) && !strings.HasPrefix(frame.MethName, "<init>") {
+		if frame.ExceptionPC == -1 { // if the exception occurred in a different frame, exceptionPC = -1
+			frame.ExceptionPC = frame.PC
+		}
+
+		// frames of Java methods carry the method's line number table, which the
+		// classloader built from its LineNumberTable attribute
+		if lineTable, ok := frame.LineTable.([]classloader.BytecodeToSourceLine); ok && len(lineTable) > 0 {
+			line := classloader.SourceLineForPC(lineTable, frame.ExceptionPC)
+			if line != -1 { // -1 means not found
+				addField("sourceLine", fmt.Sprintf("%d", line))
+			}
+			return
+		}
+
+		// otherwise, look up the method and search its LineNumberTable attribute
 		rawMethod, _ := classloader.FetchMethodAndCP(frame.ClName, frame.MethName, frame.MethType)
 		if rawMethod.MType == 'G' { // nothing more to do if it's a native method
 			return
@@ -160,9 +199,6 @@ func initStackTraceElement(ste *object.Object, frm *frames.Frame) {
 		for i := 0; i < len(method.Attribs); i++ {
 			index := method.Attribs[i].AttrName
 			if method.Cp.Utf8Refs[index] == "LineNumberTable" {
-				if frame.ExceptionPC == -1 { // if the exception occurred in a different frame, exceptionPC = -1
-					frame.ExceptionPC = frame.PC
-				}
 				line := searchLineNumberTable(method.Attribs[i].AttrContent, frame.ExceptionPC)
 				if line != -1 { // -1 means not found
 					addField("sourceLine", fmt.Sprintf("%d", line))
@@ -172,6 +208,48 @@ func initStackTraceElement(ste *object.Object, frm *frames.Frame) {
 	}
 }
 
+// java/lang/StackTraceElement.getClassName()Ljava/lang/String;
+// Returns the fully qualified name of the class, in the dotted form.
+func steGetClassName(params []interface{}) interface{} {
+	ste := params[0].(*object.Object)
+	className := steStringField(ste, "declaringClass")
+	return object.StringObjectFromGoString(strings.ReplaceAll(className, "/", "."))
+}
+
+// java/lang/StackTraceElement.getMethodName()Ljava/lang/String;
+func steGetMethodName(params []interface{}) interface{} {
+	ste := params[0].(*object.Object)
+	return object.StringObjectFromGoString(steStringField(ste, "methodName"))
+}
+
+// java/lang/StackTraceElement.getFileName()Ljava/lang/String;
+// Returns null if the name of the source file is not available.
+func steGetFileName(params []interface{}) interface{} {
+	ste := params[0].(*object.Object)
+	fileName := steStringField(ste, "fileName")
+	if fileName == "" {
+		return object.Null
+	}
+	return object.StringObjectFromGoString(fileName)
+}
+
+// java/lang/StackTraceElement.getLineNumber()I
+// Returns the source line found in the LineNumberTable, or -1 if it is not available.
+func steGetLineNumber(params []interface{}) interface{} {
+	ste := params[0].(*object.Object)
+	line, err := strconv.ParseInt(steStringField(ste, "sourceLine"), 10, 64)
+	if err != nil {
+		return int64(-1)
+	}
+	return line
+}
+
+// returns the value of a field set by initStackTraceElement(), or "" if it was not set
+func steStringField(ste *object.Object, fieldName string) string {
+	value, _ := ste.FieldTable[fieldName].Fvalue.(string)
+	return value
+}
+
 // get the source line number from the location of the bytecode where exception occurred
 //
 // We first create a table of entries consisting of bytecode number and source line number
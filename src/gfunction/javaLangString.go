@@ -7,13 +7,22 @@
 package gfunction
 
 import (
+	"bytes"
+	"container/list"
 	"fmt"
 	"jacobin/classloader"
 	"jacobin/excNames"
+	"jacobin/globals"
 	"jacobin/object"
+	"jacobin/stringPool"
 	"jacobin/types"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // We don't run String's static initializer block because the initialization
@@ -94,21 +103,25 @@ func Load_Lang_String() {
 			GFunction:  trapFunction,
 		}
 
-	// String(byte[] bytes, Charset charset) ************************************** CHARSET
+	// String(char[] value)
 	MethodSignatures["java/lang/String.<init>([C)V"] =
 		GMeth{
 			ParamSlots: 1,
 			GFunction:  newStringFromChars,
 		}
 
-	// String(char[] value) ****************************** works fine with JDK libraries
-	// String(char[] value, int offset, int count) ******* works fine with JDK libraries
+	// String(char[] value, int offset, int count)
+	MethodSignatures["java/lang/String.<init>([CII)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  newStringFromCharSubarray,
+		}
 
-	// String(int[] codePoints, int offset, int count) ************************ CODEPOINTS
+	// String(int[] codePoints, int offset, int count)
 	MethodSignatures["java/lang/String.<init>([III)V"] =
 		GMeth{
 			ParamSlots: 3,
-			GFunction:  trapFunction,
+			GFunction:  newStringFromCodePoints,
 		}
 
 	// String(String original) - works fine in Java
@@ -136,6 +149,13 @@ func Load_Lang_String() {
 			GFunction:  stringEquals,
 		}
 
+	// Are 2 strings equal, ignoring case?
+	MethodSignatures["java/lang/String.equalsIgnoreCase(Ljava/lang/String;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringEqualsIgnoreCase,
+		}
+
 	// get the bytes from a string
 	MethodSignatures["java/lang/String.getBytes()[B"] =
 		GMeth{
@@ -177,6 +197,12 @@ func Load_Lang_String() {
 			GFunction:  stringCharAt,
 		}
 
+	MethodSignatures["java/lang/String.codePointCount(II)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringCodePointCount,
+		}
+
 	// Compare 2 strings lexicographically, case-sensitive (upper/lower).
 	// The return value is a negative integer, zero, or a positive integer
 	// as the String argument is greater than, equal to, or less than this String,
@@ -203,30 +229,82 @@ func Load_Lang_String() {
 			GFunction:  stringConcat,
 		}
 
+	// Tell whether or not this string contains the given sequence of characters (a literal, not a regex).
+	MethodSignatures["java/lang/String.contains(Ljava/lang/CharSequence;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringContains,
+		}
+
+	// Return a string representing a char array, as valueOf() does.
+	MethodSignatures["java/lang/String.copyValueOf([C)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  valueOfCharArray,
+		}
+
+	// Return a string representing a char subarray, as valueOf() does.
+	MethodSignatures["java/lang/String.copyValueOf([CII)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  valueOfCharSubarray,
+		}
+
 	// Return a formatted string using the reference object string as the format string
 	// and the supplied arguments as input object arguments.
 	// E.g. String string = String.format("%s %i", "ABC", 42);
 	MethodSignatures["java/lang/String.format(Ljava/lang/String;[Ljava/lang/Object;)Ljava/lang/String;"] =
 		GMeth{
-			ParamSlots: 2,
-			GFunction:  sprintf,
+			ParamSlots:   2,
+			GFunction:    sprintf,
+			NeedsContext: true,
 		}
 
 	// This method is equivalent to String.format(this, args).
 	MethodSignatures["java/lang/String.formatted([Ljava/lang/Object;)Ljava/lang/String;"] =
 		GMeth{
-			ParamSlots: 1,
-			GFunction:  sprintf,
+			ParamSlots:   1,
+			GFunction:    sprintf,
+			NeedsContext: true,
 		}
 
 	// Return a formatted string using the specified locale, format string, and arguments.
 	MethodSignatures["java/lang/String.format(Ljava/util/Locale;Ljava/lang/String;[Ljava/lang/Object;)Ljava/lang/String;"] =
 		GMeth{
-			ParamSlots: 3,
-			GFunction:  trapFunction,
+			ParamSlots:   3,
+			GFunction:    sprintfLocale,
+			NeedsContext: true,
 		}
 
-	// Return the length of a String.
+	// Return the hash code of a String.
+	MethodSignatures["java/lang/String.hashCode()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringHashCode,
+		}
+
+	// Return the index of the first occurrence of a character (code point), or -1.
+	MethodSignatures["java/lang/String.indexOf(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringIndexOfCodePoint,
+		}
+
+	// Return the index of the first occurrence of a character (code point) at or after the given index, or -1.
+	MethodSignatures["java/lang/String.indexOf(II)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringIndexOfCodePoint,
+		}
+
+	// Return whether a String is empty.
+	MethodSignatures["java/lang/String.isEmpty()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringIsEmpty,
+		}
+
+	// Tell whether the string is coded as LATIN1, which in Jacobin is always the case.
 	MethodSignatures["java/lang/String.isLatin1()Z"] =
 		GMeth{
 			ParamSlots: 0,
@@ -240,6 +318,54 @@ func Load_Lang_String() {
 			GFunction:  stringLength,
 		}
 
+	// Return the index of the last occurrence of a character (code point), or -1.
+	MethodSignatures["java/lang/String.lastIndexOf(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringLastIndexOfCodePoint,
+		}
+
+	// Return the index of the last occurrence of a character (code point) at or before the given index, or -1.
+	MethodSignatures["java/lang/String.lastIndexOf(II)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringLastIndexOfCodePoint,
+		}
+
+	// Return a stream of the lines in a String.
+	MethodSignatures["java/lang/String.lines()Ljava/util/stream/Stream;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringLines,
+		}
+
+	// Tell whether or not the whole string matches the given regular expression.
+	MethodSignatures["java/lang/String.matches(Ljava/lang/String;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringMatches,
+		}
+
+	MethodSignatures["java/lang/String.offsetByCodePoints(II)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringOffsetByCodePoints,
+		}
+
+	// Tell whether a region of this string matches a region of another string.
+	MethodSignatures["java/lang/String.regionMatches(ILjava/lang/String;II)Z"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  stringRegionMatches,
+		}
+
+	// Same as above, but optionally ignoring case.
+	MethodSignatures["java/lang/String.regionMatches(ZILjava/lang/String;II)Z"] =
+		GMeth{
+			ParamSlots: 5,
+			GFunction:  stringRegionMatchesIgnoreCase,
+		}
+
 	// Returns a string whose value is the concatenation of this string repeated the specified number of times.
 	MethodSignatures["java/lang/String.repeat(I)Ljava/lang/String;"] =
 		GMeth{
@@ -247,6 +373,13 @@ func Load_Lang_String() {
 			GFunction:  stringRepeat,
 		}
 
+	// Returns a string with each occurrence of the first CharSequence replaced by the second.
+	MethodSignatures["java/lang/String.replace(Ljava/lang/CharSequence;Ljava/lang/CharSequence;)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringReplaceCharSequence,
+		}
+
 	// Return a string in all lower case, using the reference object string as input.
 	MethodSignatures["java/lang/String.substring(I)Ljava/lang/String;"] =
 		GMeth{
@@ -365,20 +498,88 @@ func stringClinit([]interface{}) interface{} {
 	return nil
 }
 
-// Get character at the given index.
+// Get the UTF-16 char at the given index, so half of a surrogate pair for a supplementary character.
 // "java/lang/String.charAt(I)C"
 func stringCharAt(params []interface{}) interface{} {
-	// Unpack the reference string and convert it to a rune array.
-	ptrObj := params[0].(*object.Object)
-	str := object.GoStringFromStringObject(ptrObj)
-	runeArray := []rune(str)
+	units := stringUTF16(params[0].(*object.Object))
 
-	// Get index.
+	// Get and validate index.
 	index := params[1].(int64)
+	if index < 0 || index >= int64(len(units)) {
+		errMsg := fmt.Sprintf("index %d, length %d", index, len(units))
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
 
 	// Return indexed character.
-	runeValue := runeArray[index]
-	return int64(runeValue)
+	return int64(units[index])
+}
+
+// stringUTF16 returns the UTF-16 code units of a String, which is how Java represents a
+// string and so what the indices passed to the code point methods refer to
+func stringUTF16(obj *object.Object) []uint16 {
+	return utf16.Encode([]rune(object.GoStringFromStringObject(obj)))
+}
+
+// isSurrogatePair reports whether the code units at index and index+1 are a high surrogate
+// followed by a low surrogate, which together encode a single supplementary code point
+func isSurrogatePair(units []uint16, index int64) bool {
+	return index >= 0 && index+1 < int64(len(units)) &&
+		units[index] >= 0xD800 && units[index] <= 0xDBFF &&
+		units[index+1] >= 0xDC00 && units[index+1] <= 0xDFFF
+}
+
+// "java/lang/String.codePointCount(II)I" counts the code points between the begin index and
+// the end index (exclusive), counting a surrogate pair as one code point
+func stringCodePointCount(params []interface{}) interface{} {
+	units := stringUTF16(params[0].(*object.Object))
+	begin := params[1].(int64)
+	end := params[2].(int64)
+	if begin < 0 || end > int64(len(units)) || begin > end {
+		errMsg := fmt.Sprintf("String.codePointCount: begin %d, end %d, length %d", begin, end, len(units))
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
+
+	count := end - begin
+	for i := begin; i < end-1; i++ {
+		if isSurrogatePair(units, i) {
+			count--
+			i++
+		}
+	}
+	return count
+}
+
+// "java/lang/String.offsetByCodePoints(II)I" returns the index that is offset code points
+// from the given index, moving backwards if offset is negative
+func stringOffsetByCodePoints(params []interface{}) interface{} {
+	units := stringUTF16(params[0].(*object.Object))
+	index := params[1].(int64)
+	offset := params[2].(int64)
+	length := int64(len(units))
+	if index < 0 || index > length {
+		errMsg := fmt.Sprintf("String.offsetByCodePoints: index %d, length %d", index, length)
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
+
+	result := index
+	for ; offset > 0 && result < length; offset-- {
+		if isSurrogatePair(units, result) {
+			result++
+		}
+		result++
+	}
+	for ; offset < 0 && result > 0; offset++ {
+		if isSurrogatePair(units, result-2) {
+			result--
+		}
+		result--
+	}
+	if offset != 0 { // ran off the end or the start of the string
+		errMsg := fmt.Sprintf("String.offsetByCodePoints: index %d, offset %d, length %d",
+			index, params[2].(int64), length)
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
+	return result
 }
 
 // Are 2 strings equal?
@@ -398,6 +599,22 @@ func stringEquals(params []interface{}) interface{} {
 	return int64(0) // false
 }
 
+// Are 2 strings equal, ignoring case? A null argument is simply not equal.
+// "java/lang/String.equalsIgnoreCase(Ljava/lang/String;)Z"
+func stringEqualsIgnoreCase(params []interface{}) interface{} {
+	// params[0]: reference string object
+	// params[1]: compare-to string Object
+	if object.IsNull(params[1]) {
+		return int64(0) // false
+	}
+	chars1 := stringUTF16(params[0].(*object.Object))
+	chars2 := stringUTF16(params[1].(*object.Object))
+	if len(chars1) == len(chars2) && charsEqualIgnoreCase(chars1, chars2) {
+		return int64(1) // true
+	}
+	return int64(0) // false
+}
+
 // Instantiate a new empty string - "java/lang/String.<init>()V"
 func newEmptyString(params []interface{}) interface{} {
 	// params[0] = target object for string (updated)
@@ -448,17 +665,75 @@ func newStringFromBytesSubset(params []interface{}) interface{} {
 // "java/lang/String.<init>([C)V"
 func newStringFromChars(params []interface{}) interface{} {
 	// params[0] = reference string (to be updated with byte array)
-	// params[1] = byte array object
-	ints := params[1].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	// params[1] = char array object
+	ret := valueOfCharArray(params[1:])
+	if errBlk, ok := ret.(*GErrBlk); ok {
+		return errBlk
+	}
+	bytes := object.ByteArrayFromStringObject(ret.(*object.Object))
+	object.UpdateStringObjectFromBytes(params[0].(*object.Object), bytes)
+	return nil
+}
 
-	var bytes []byte
-	for _, ii := range ints {
-		bytes = append(bytes, byte(ii&0xFF))
+// Instantiate a new string object from part of a Java char array.
+// "java/lang/String.<init>([CII)V"
+func newStringFromCharSubarray(params []interface{}) interface{} {
+	// params[0] = reference string (to be updated with byte array)
+	// params[1] = char array object
+	// params[2] = offset of the first char
+	// params[3] = count of chars
+	ret := valueOfCharSubarray(params[1:])
+	if errBlk, ok := ret.(*GErrBlk); ok {
+		return errBlk
 	}
+	bytes := object.ByteArrayFromStringObject(ret.(*object.Object))
 	object.UpdateStringObjectFromBytes(params[0].(*object.Object), bytes)
 	return nil
 }
 
+// Instantiate a new string object from part of an array of Unicode code points, each of
+// which is one char of the string or, if it's a supplementary code point, two.
+// "java/lang/String.<init>([III)V"
+func newStringFromCodePoints(params []interface{}) interface{} {
+	// params[0] = reference string (to be updated with byte array)
+	// params[1] = int array object of code points
+	// params[2] = offset of the first code point
+	// params[3] = count of code points
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "String: code point array is null")
+	}
+	codePoints := params[1].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	offset := params[2].(int64)
+	count := params[3].(int64)
+
+	length := int64(len(codePoints))
+	if offset < 0 || count < 0 || offset > length-count {
+		errMsg := fmt.Sprintf("offset %d, count %d, length %d", offset, count, length)
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
+
+	// as in Java, an invalid code point is reported as its decimal value
+	runes := make([]rune, count)
+	for i, codePoint := range codePoints[offset : offset+count] {
+		if codePoint < 0 || codePoint > unicode.MaxRune {
+			return getGErrBlk(excNames.IllegalArgumentException, strconv.FormatInt(codePoint, 10))
+		}
+		runes[i] = rune(codePoint)
+	}
+	object.UpdateStringObjectFromBytes(params[0].(*object.Object), []byte(string(runes)))
+	return nil
+}
+
+// charsToGoString converts the UTF-16 code units of a Java char array to a Go string,
+// combining surrogate pairs into a single character
+func charsToGoString(chars []int64) string {
+	units := make([]uint16, len(chars))
+	for i, ch := range chars {
+		units[i] = uint16(ch)
+	}
+	return string(utf16.Decode(units))
+}
+
 // "java/lang/String.getBytes()[B"
 func getBytesFromString(params []interface{}) interface{} {
 	// params[0] = reference string with byte array to be returned
@@ -469,14 +744,30 @@ func getBytesFromString(params []interface{}) interface{} {
 // "java/lang/String.format(Ljava/lang/String;[Ljava/lang/Object;)Ljava/lang/String;"
 // "java/lang/String.formatted([Ljava/lang/Object;)Ljava/lang/String;"
 func sprintf(params []interface{}) interface{} {
-	// params[0]: format string
-	// params[1]: argument slice (array of object pointers)
-	return StringFormatter(params)
+	// params[0]: the frame stack
+	// params[1]: format string
+	// params[2]: argument slice (array of object pointers)
+	return StringFormatter(params[0].(*list.List), params[1:])
+}
+
+// Full locale support is out of scope, so the locale is accepted but the formatting is
+// always that of the root locale (which, for numbers, is the same as Locale.US).
+// "java/lang/String.format(Ljava/util/Locale;Ljava/lang/String;[Ljava/lang/Object;)Ljava/lang/String;"
+func sprintfLocale(params []interface{}) interface{} {
+	// params[0]: the frame stack
+	// params[1]: locale (ignored)
+	// params[2]: format string
+	// params[3]: argument slice (array of object pointers)
+	return StringFormatter(params[0].(*list.List), params[2:])
 }
 
 // String formatting given a format string and a slice of arguments.
 // Called by sprintf, javaIoConsole.go, and javaIoPrintStream.go.
-func StringFormatter(params []interface{}) interface{} {
+// As in Java, an argument that's not a String, a StringBuilder or StringBuffer, or a boxed
+// primitive is formatted as the string returned by its toString(), which can be a gfunction
+// or Java bytecode, so it's run through globals.FuncInvokeMethod on the frame stack fs.
+// A null argument is formatted as "null".
+func StringFormatter(fs *list.List, params []interface{}) interface{} {
 	// params[0]: format string
 	// params[1]: argument slice (array of object pointers)
 
@@ -521,15 +812,24 @@ func StringFormatter(params []interface{}) interface{} {
 	// valuesIn = the reference array
 	valuesIn := fld.Fvalue.([]*object.Object)
 
+	// the class names of the arguments, which translateFormat checks against the conversions
+	argClasses := make([]string, len(valuesIn))
+
 	// Main loop for reference array.
 	for ii := 0; ii < len(valuesIn); ii++ {
 
+		// A null is kept as a nil, which translateFormat formats as "null".
+		if object.IsNull(valuesIn[ii]) {
+			valuesOut = append(valuesOut, nil)
+			continue
+		}
+		argClasses[ii] = strings.ReplaceAll(*stringPool.GetStringPointer(valuesIn[ii].KlassName), "/", ".")
+
 		// Get the current object's value field.
 		fld := valuesIn[ii].FieldTable["value"]
 
-		// If type is string object, process it.
-		if fld.Ftype == types.ByteArray {
-			str := string(fld.Fvalue.([]byte))
+		// If it's a CharSequence (String, StringBuilder, or StringBuffer), process it.
+		if str, ok := object.CharSequenceToGoString(valuesIn[ii]); ok {
 			valuesOut = append(valuesOut, str)
 		} else {
 			// Not a string object.
@@ -538,7 +838,7 @@ func StringFormatter(params []interface{}) interface{} {
 				str := string(fld.Fvalue.([]byte))
 				valuesOut = append(valuesOut, str)
 			case types.Byte:
-				valuesOut = append(valuesOut, uint8(fld.Fvalue.(int64)))
+				valuesOut = append(valuesOut, fld.Fvalue.(int64))
 			case types.Bool:
 				var zz bool
 				if fld.Fvalue.(int64) == 0 {
@@ -548,8 +848,7 @@ func StringFormatter(params []interface{}) interface{} {
 				}
 				valuesOut = append(valuesOut, zz)
 			case types.Char:
-				cc := fmt.Sprint(fld.Fvalue.(int64))
-				valuesOut = append(valuesOut, cc)
+				valuesOut = append(valuesOut, string(rune(fld.Fvalue.(int64))))
 			case types.Double:
 				valuesOut = append(valuesOut, fld.Fvalue.(float64))
 			case types.Float:
@@ -561,31 +860,927 @@ func StringFormatter(params []interface{}) interface{} {
 			case types.Short:
 				valuesOut = append(valuesOut, fld.Fvalue.(int64))
 			default:
-				errMsg := fmt.Sprintf("StringFormatter: Invalid parameter %d is of type %s", ii+1, fld.Ftype)
-				return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+				str, errBlk := formatterToString(fs, valuesIn[ii])
+				if errBlk != nil {
+					return errBlk
+				}
+				valuesOut = append(valuesOut, str)
 			}
 		}
 	}
 
-	// Use golang fmt.Sprintf to do the heavy lifting.
+	// Use golang fmt.Sprintf to do the heavy lifting, once the format string and arguments
+	// are translated from Java's to golang's (see translateFormat).
+	hashCode := func(argIndex int) (int64, *GErrBlk) { return formatterHashCode(fs, valuesIn[argIndex]) }
+	formatString, valuesOut, errBlk := translateFormat(formatString, valuesOut, argClasses, hashCode)
+	if errBlk != nil {
+		return errBlk
+	}
 	str := fmt.Sprintf(formatString, valuesOut...)
 
 	// Return a pointer to an object.Object that wraps the string byte array.
 	return object.StringObjectFromGoString(str)
 }
 
+// formatterToString returns the string returned by an object's toString(), or "null" if
+// toString() returns null
+func formatterToString(fs *list.List, obj *object.Object) (string, *GErrBlk) {
+	ret, err := globals.GetGlobalRef().FuncInvokeMethod(fs, obj, "toString", "()Ljava/lang/String;")
+	if err != nil {
+		errMsg := fmt.Sprintf("StringFormatter: toString() failed: %s", err.Error())
+		return "", getGErrBlk(excNames.VirtualMachineError, errMsg)
+	}
+	if object.IsNull(ret) {
+		return "null", nil
+	}
+	return object.GoStringFromStringObject(ret.(*object.Object)), nil
+}
+
+// formatterHashCode returns an object's hashCode(), for %h. The hash codes of Strings and of
+// Booleans, Bytes, Shorts, Characters, Integers, Longs, Floats, and Doubles are computed here,
+// as the JDK computes them; any other object's hashCode() is called.
+func formatterHashCode(fs *list.List, obj *object.Object) (int64, *GErrBlk) {
+	value := obj.FieldTable["value"].Fvalue
+	switch *stringPool.GetStringPointer(obj.KlassName) {
+	case types.StringClassName:
+		return stringHashCode([]interface{}{obj}).(int64), nil
+	case "java/lang/Boolean":
+		if value.(int64) != 0 {
+			return 1231, nil
+		}
+		return 1237, nil
+	case "java/lang/Byte", "java/lang/Short", "java/lang/Character", "java/lang/Integer":
+		return int64(int32(value.(int64))), nil
+	case "java/lang/Long":
+		bits := uint64(value.(int64))
+		return int64(int32(bits ^ bits>>32)), nil
+	case "java/lang/Float":
+		return int64(int32(math.Float32bits(float32(value.(float64))))), nil
+	case "java/lang/Double":
+		number := value.(float64)
+		if math.IsNaN(number) {
+			number = math.NaN() // doubleToLongBits() gives all NaNs the same bits
+		}
+		bits := math.Float64bits(number)
+		return int64(int32(bits ^ bits>>32)), nil
+	}
+
+	ret, err := globals.GetGlobalRef().FuncInvokeMethod(fs, obj, "hashCode", "()I")
+	if err != nil {
+		errMsg := fmt.Sprintf("StringFormatter: hashCode() failed: %s", err.Error())
+		return 0, getGErrBlk(excNames.VirtualMachineError, errMsg)
+	}
+	return ret.(int64), nil
+}
+
+// translateFormat translates a Java format string and its arguments, whose class names are
+// in classes, into a golang format string and the arguments for it, in which each specifier
+// takes the next argument:
+//   - as in Java, a conversion that's not one of Java's is an UnknownFormatConversionException,
+//     as is a % at the end of the format string, or followed by only flags and a width. The
+//     date and time conversions (%t and %T) aren't supported, so they are too.
+//   - an argument whose class the conversion doesn't accept, such as a String for %d, is an
+//     IllegalFormatConversionException (see checkFormatConversion).
+//   - Java's argument indices are resolved: an explicit index (as in %2$s), the previous
+//     specifier's argument (%<s), or otherwise the argument after the last one taken by an
+//     ordinary specifier. As in Java, an explicit index doesn't affect the ordinary ones.
+//     The argument of each specifier is put into the golang arguments, so an argument that's
+//     used more than once appears more than once, and arguments that aren't used don't
+//     appear. A specifier whose argument is missing returns a MissingFormatArgumentException.
+//   - %n is replaced by the line separator, "\n", and %% is left as is, padded to its width.
+//   - a null argument (a nil value) is formatted, as in Java, as "null", and %b formats a
+//     Boolean as its value, a null as "false", and any other argument as "true". These are
+//     in upper case for the upper-case conversions, and their specifiers are replaced by a %s.
+//   - %s (and %S) formats a Boolean, Byte, Short, Integer, Long, Float, or Double as the text of
+//     its toString() (see formatJavaToString).
+//   - %h (and %H) formats the argument's hashCode(), as given by the hashCode function, in hex.
+//     The specifier is replaced by a %s.
+//   - %S is formatted as %s, with its width and precision, and then the whole result is put
+//     in upper case, as Java does for all the upper-case conversions. Its specifier is
+//     replaced by a %s.
+//   - %c (and %C) formats a Character as itself, and a Byte, Short, or Integer as the character
+//     whose code point it is, which can be a supplementary character. A number that's not a
+//     valid code point is an IllegalFormatCodePointException. The specifier is replaced by a %s.
+//   - the Byte, Short, Integer, and Long values of %o, %x, and %X are formatted by
+//     formatJavaRadix, which formats a negative number as Java does, as the unsigned number
+//     with the same bits, so %x of the int -1 is ffffffff. Their specifiers are replaced by a %s.
+//   - the integral and floating-point values of %d and %f are formatted by formatDecimalNumber,
+//     which applies Java's flags: the grouping flag (','), as in %,d, puts a comma between each
+//     group of three digits, and '(', '+', ' ', and '0' are applied as in signAndPadNumber, so
+//     %(d of -42 is (42). Their specifiers are replaced by a %s.
+//   - the numbers of the floating-point conversions %e, %g, and %a (and their upper-case
+//     forms), whose output in golang differs from Java's, are formatted by formatJavaFloat,
+//     and their specifiers are replaced by a %s.
+//
+// The other specifiers and values are left for fmt.Sprintf.
+func translateFormat(format string, values []any, classes []string,
+	hashCode func(argIndex int) (int64, *GErrBlk)) (string, []any, *GErrBlk) {
+	var out strings.Builder
+	valuesOut := []any{}
+	ordinaryIndex, lastIndex := 0, -1
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+
+		// a format specifier is: %[argument_index$][flags][width][.precision]conversion
+		start := i
+		i++
+		argIndex := -1
+		indexStart := i
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		if i > indexStart && i < len(format) && format[i] == '$' {
+			argIndex, _ = strconv.Atoi(format[indexStart:i])
+			argIndex-- // Java's argument indices start at 1
+			i++
+		} else {
+			i = indexStart // the digits are the flags and width
+		}
+		flagsStart := i
+		for i < len(format) && strings.IndexByte("-#+ 0,(<", format[i]) >= 0 {
+			i++
+		}
+		flags := format[flagsStart:i]
+		widthStart := i
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		width := format[widthStart:i]
+		precision := ""
+		if i < len(format) && format[i] == '.' {
+			precStart := i
+			i++
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+			precision = format[precStart:i]
+		}
+		if i >= len(format) { // as in Java, the conversion reported is the character after the %
+			conversion := byte('%')
+			if start+1 < len(format) {
+				conversion = format[start+1]
+			}
+			errMsg := fmt.Sprintf("Conversion = '%c'", conversion)
+			return "", nil, getGErrBlk(excNames.UnknownFormatConversionException, errMsg)
+		}
+		conversion := format[i]
+		switch conversion { // these don't use an argument
+		case '%': // padded to the width, if any, as a string is
+			percent := "%%"
+			if padding, _ := strconv.Atoi(width); padding > 1 {
+				if strings.IndexByte(flags, '-') >= 0 {
+					percent += strings.Repeat(" ", padding-1)
+				} else {
+					percent = strings.Repeat(" ", padding-1) + percent
+				}
+			}
+			out.WriteString(percent)
+			continue
+		case 'n':
+			out.WriteString("\n")
+			continue
+		}
+
+		if strings.IndexByte(javaFormatConversions, conversion) < 0 {
+			errMsg := fmt.Sprintf("Conversion = '%c'", conversion)
+			return "", nil, getGErrBlk(excNames.UnknownFormatConversionException, errMsg)
+		}
+
+		if errBlk := validateFormatFlags(format[start:i+1], flags, width, conversion); errBlk != nil {
+			return "", nil, errBlk
+		}
+
+		if strings.IndexByte(flags, '<') >= 0 {
+			argIndex = lastIndex
+			flags = strings.ReplaceAll(flags, "<", "")
+		} else if argIndex < 0 {
+			argIndex = ordinaryIndex
+			ordinaryIndex++
+		}
+		if argIndex < 0 || argIndex >= len(values) {
+			errMsg := fmt.Sprintf("Format specifier '%s'", format[start:i+1])
+			return "", nil, getGErrBlk(excNames.MissingFormatArgumentException, errMsg)
+		}
+		lastIndex = argIndex
+		value := values[argIndex]
+		if value != nil {
+			if errBlk := checkFormatConversion(conversion, classes[argIndex]); errBlk != nil {
+				return "", nil, errBlk
+			}
+		}
+
+		if value == nil || conversion == 'b' || conversion == 'B' {
+			var str string
+			switch {
+			case conversion == 'b' || conversion == 'B':
+				boolean, isBoolean := value.(bool)
+				str = strconv.FormatBool(value != nil && (!isBoolean || boolean))
+			default:
+				str = "null"
+			}
+			if conversion >= 'A' && conversion <= 'Z' {
+				str = strings.ToUpper(str)
+			}
+			leftJustify := ""
+			if strings.IndexByte(flags, '-') >= 0 {
+				leftJustify = "-"
+			}
+			valuesOut = append(valuesOut, str)
+			out.WriteString("%" + leftJustify + width + precision + "s")
+			continue
+		}
+
+		if conversion == 'h' || conversion == 'H' {
+			hash, errBlk := hashCode(argIndex)
+			if errBlk != nil {
+				return "", nil, errBlk
+			}
+			value = strconv.FormatUint(uint64(uint32(hash)), 16)
+			if conversion == 'H' {
+				conversion = 'S' // formatted as the upper-case string
+			} else {
+				conversion = 's'
+			}
+		}
+		if conversion == 's' || conversion == 'S' {
+			value = formatJavaToString(value, classes[argIndex])
+		}
+		if conversion == 'S' {
+			leftJustify := ""
+			if strings.IndexByte(flags, '-') >= 0 {
+				leftJustify = "-"
+			}
+			valuesOut = append(valuesOut, strings.ToUpper(fmt.Sprintf("%"+leftJustify+width+precision+"s", value)))
+			out.WriteString("%s")
+			continue
+		}
+
+		if conversion == 'c' || conversion == 'C' {
+			str, errBlk := formatJavaCharacter(value, classes[argIndex])
+			if errBlk != nil {
+				return "", nil, errBlk
+			}
+			if conversion == 'C' {
+				str = strings.ToUpper(str)
+			}
+			leftJustify := ""
+			if strings.IndexByte(flags, '-') >= 0 {
+				leftJustify = "-"
+			}
+			valuesOut = append(valuesOut, str)
+			out.WriteString("%" + leftJustify + width + "s")
+			continue
+		}
+
+		if strings.IndexByte("eEgGaA", conversion) >= 0 {
+			if number, ok := value.(float64); ok {
+				valuesOut = append(valuesOut, formatJavaFloat(number, flags, width, precision, conversion))
+				out.WriteString("%s")
+				continue
+			}
+		}
+
+		if conversion == 'd' || conversion == 'f' {
+			if number, ok := formatDecimalNumber(value, flags, width, precision); ok {
+				valuesOut = append(valuesOut, number)
+				out.WriteString("%s")
+				continue
+			}
+		}
+		if conversion == 'o' || conversion == 'x' || conversion == 'X' {
+			if number, ok := formatJavaRadix(unsignedForRadix(value, classes[argIndex]), flags, width, conversion); ok {
+				valuesOut = append(valuesOut, number)
+				out.WriteString("%s")
+				continue
+			}
+		}
+		valuesOut = append(valuesOut, value)
+		out.WriteString("%" + flags + width + precision + string(conversion))
+	}
+	return out.String(), valuesOut, nil
+}
+
+// the conversions of Java's Formatter that use an argument, except the date and time ones
+const javaFormatConversions = "bBhHsScCdoxXeEfgGaA"
+
+// checkFormatConversion returns an IllegalFormatConversionException if the conversion doesn't
+// accept an argument of the class, as Java's Formatter does. The general conversions (%s, %b,
+// %h) accept any argument; the others accept only these classes:
+//   - integral (%d, %o, %x): Byte, Short, Integer, Long, and BigInteger
+//   - floating point (%e, %f, %g): Float, Double, and BigDecimal, and %a just Float and Double
+//   - character (%c): Character, and the Byte, Short, and Integer that are code points
+func checkFormatConversion(conversion byte, className string) *GErrBlk {
+	var accepted []string
+	switch conversion | 0x20 { // the lower-case form of the conversion
+	case 'd', 'o', 'x':
+		accepted = []string{"java.lang.Byte", "java.lang.Short", "java.lang.Integer", "java.lang.Long",
+			"java.math.BigInteger"}
+	case 'e', 'f', 'g':
+		accepted = []string{"java.lang.Float", "java.lang.Double", "java.math.BigDecimal"}
+	case 'a':
+		accepted = []string{"java.lang.Float", "java.lang.Double"}
+	case 'c':
+		accepted = []string{"java.lang.Character", "java.lang.Byte", "java.lang.Short", "java.lang.Integer"}
+	default:
+		return nil
+	}
+	for _, name := range accepted {
+		if className == name {
+			return nil
+		}
+	}
+	errMsg := fmt.Sprintf("%c != %s", conversion, className)
+	return getGErrBlk(excNames.IllegalFormatConversionException, errMsg)
+}
+
+// validateFormatFlags checks the flags of a format specifier as Java's Formatter does, for
+// the general (%s, %b, %h), character, integer, and floating-point conversions:
+//   - a left-justified (-) or zero-padded (0) specifier needs a width, or it's a
+//     MissingFormatWidthException;
+//   - '+' with ' ', or '-' with '0', is an IllegalFormatFlagsException;
+//   - a flag that doesn't apply to the conversion, such as %+s or % x, is a
+//     FormatFlagsConversionMismatchException.
+func validateFormatFlags(specifier, flags, width string, conversion byte) *GErrBlk {
+	var badFlags string
+	zeroPadAllowed := true
+	switch conversion | 0x20 { // the lower-case form of the conversion
+	case 'b', 'h', 's':
+		badFlags, zeroPadAllowed = "+ 0,(", false
+	case 'c':
+		badFlags, zeroPadAllowed = "#+ 0,(", false
+	case 'd':
+		badFlags = "#"
+	case 'o', 'x':
+		badFlags = "+ ,("
+	case 'e', 'g', 'a', 'f':
+	default:
+		return nil
+	}
+
+	hasFlag := func(flag byte) bool { return strings.IndexByte(flags, flag) >= 0 }
+	if width == "" && (hasFlag('-') || (zeroPadAllowed && hasFlag('0'))) {
+		return getGErrBlk(excNames.MissingFormatWidthException, specifier)
+	}
+	if zeroPadAllowed && ((hasFlag('+') && hasFlag(' ')) || (hasFlag('-') && hasFlag('0'))) {
+		var javaFlags strings.Builder // in the order that Java's Formatter lists them
+		for _, flag := range []byte("-#+ 0,(<") {
+			if hasFlag(flag) {
+				javaFlags.WriteByte(flag)
+			}
+		}
+		return getGErrBlk(excNames.IllegalFormatFlagsException, fmt.Sprintf("Flags = '%s'", javaFlags.String()))
+	}
+	for _, flag := range []byte(badFlags) {
+		if hasFlag(flag) {
+			errMsg := fmt.Sprintf("Conversion = %c, Flags = %c", conversion, flag)
+			return getGErrBlk(excNames.FormatFlagsConversionMismatchException, errMsg)
+		}
+	}
+	return nil
+}
+
+// formatJavaFloat formats a floating-point value as Java does for the %e, %g, and %a
+// conversions (and their upper-case forms), with the given flags, width, and precision
+// (which includes its leading '.', if present) of the format specifier:
+//   - %e is scientific notation, with precision digits after the decimal point (6 by
+//     default) and an exponent of at least two digits, as in 1.234568e+04.
+//   - %g is the value rounded to precision significant digits (6 by default), which is
+//     shown in decimal notation if it's at least 10^-4 and less than 10^precision, and
+//     otherwise in scientific notation.
+//   - %a is hexadecimal floating point, as in Double.toHexString(), with precision hex
+//     digits after the point if a precision is given.
+func formatJavaFloat(value float64, flags, width, precision string, conversion byte) string {
+	prec := -1
+	if precision != "" {
+		prec, _ = strconv.Atoi(precision[1:])
+	}
+
+	var magnitude, prefix string
+	abs := math.Abs(value)
+	switch {
+	case math.IsNaN(value):
+		magnitude = "NaN"
+	case math.IsInf(value, 0):
+		magnitude = "Infinity"
+	case conversion == 'e' || conversion == 'E':
+		if prec < 0 {
+			prec = 6
+		}
+		magnitude = strconv.FormatFloat(abs, 'e', prec, 64)
+		if prec == 0 && strings.IndexByte(flags, '#') >= 0 { // '#' always shows the decimal point
+			magnitude = strings.Replace(magnitude, "e", ".e", 1)
+		}
+	case conversion == 'g' || conversion == 'G':
+		if prec < 0 {
+			prec = 6
+		} else if prec == 0 {
+			prec = 1
+		}
+		magnitude = strconv.FormatFloat(abs, 'e', prec-1, 64)
+		rounded, _ := strconv.ParseFloat(magnitude, 64)
+		if abs == 0 || rounded >= 1e-4 && rounded < math.Pow10(prec) {
+			exponent, _ := strconv.Atoi(magnitude[strings.IndexByte(magnitude, 'e')+1:])
+			magnitude = strconv.FormatFloat(abs, 'f', prec-1-exponent, 64)
+			if strings.IndexByte(flags, ',') >= 0 {
+				magnitude = groupDigits(magnitude)
+			}
+		}
+	default: // 'a' or 'A'
+		prefix = "0x"
+		magnitude = hexFloatDigits(abs, prec)
+	}
+
+	finite := !math.IsNaN(value) && !math.IsInf(value, 0)
+	str := signAndPadNumber(math.Signbit(value), prefix, magnitude, flags, width, finite)
+	if conversion == 'E' || conversion == 'G' || conversion == 'A' {
+		str = strings.ToUpper(str)
+	}
+	return str
+}
+
+// hexFloatDigits returns the digits of a non-negative, finite value in Java's hexadecimal
+// floating-point format, without the leading 0x: 1.0 is 1.0p0, and 12345.678 is
+// 1.81cd6c8b43958p13. Subnormal values are shown as 0.<digits>p-1022. If prec is at
+// least 0, the value is rounded to prec hex digits after the point (at least 1).
+func hexFloatDigits(abs float64, prec int) string {
+	if abs == 0 {
+		return "0.0p0"
+	}
+	if prec == 0 {
+		prec = 1
+	}
+
+	bits := math.Float64bits(abs)
+	mantissa := bits & (1<<52 - 1)
+	if abs < 0x1p-1022 && prec < 0 { // subnormal
+		digits := strings.TrimRight(fmt.Sprintf("%013x", mantissa), "0")
+		return "0." + digits + "p-1022"
+	}
+
+	// golang formats the value as 0x1.<digits>p<sign><exponent>
+	hex := strconv.FormatFloat(abs, 'x', min(prec, 13), 64)
+	mant, exp, _ := strings.Cut(hex[2:], "p")
+	whole, digits, _ := strings.Cut(mant, ".")
+	if prec < 0 {
+		digits = strings.TrimRight(digits, "0")
+	}
+	if digits == "" {
+		digits = "0"
+	}
+	exponent, _ := strconv.Atoi(exp)
+	return whole + "." + digits + "p" + strconv.Itoa(exponent)
+}
+
+// formatJavaCharacter returns the character that a %c argument of the given class stands for:
+// a Character, which StringFormatter has made a string, is itself, and a Byte, Short, or
+// Integer is the character whose code point it is. As in Java, a number that's not a valid
+// code point (from 0 to 0x10FFFF) is an IllegalFormatCodePointException.
+func formatJavaCharacter(value any, className string) (string, *GErrBlk) {
+	var codePoint int64
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case int64:
+		codePoint = value
+	default:
+		errMsg := fmt.Sprintf("c != %s", className)
+		return "", getGErrBlk(excNames.IllegalFormatConversionException, errMsg)
+	}
+	if codePoint < 0 || codePoint > unicode.MaxRune {
+		errMsg := fmt.Sprintf("Code point = %#x", uint32(codePoint))
+		return "", getGErrBlk(excNames.IllegalFormatCodePointException, errMsg)
+	}
+	return string(rune(codePoint)), nil
+}
+
+// formatJavaToString returns the value of a Boolean, Byte, Short, Integer, Long, Float, or
+// Double as the text that its toString() returns, which is what %s formats. Other values,
+// such as the strings of CharSequences and of other objects' toString(), are returned as is.
+func formatJavaToString(value any, className string) any {
+	switch value := value.(type) {
+	case bool:
+		return strconv.FormatBool(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		if className == "java.lang.Float" {
+			return javaFloatingToString(value, 32)
+		}
+		return javaFloatingToString(value, 64)
+	default:
+		return value
+	}
+}
+
+// javaFloatingToString formats a Double (bitSize 64) or Float (bitSize 32) as its toString()
+// does: with the fewest digits that identify the value, and always at least one digit after
+// the decimal point. A value from 10^-3 up to 10^7 has no exponent; others are in computerized
+// scientific notation, such as 1.0E7 or 2.5E-4.
+func javaFloatingToString(value float64, bitSize int) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "Infinity"
+	case math.IsInf(value, -1):
+		return "-Infinity"
+	}
+
+	abs := math.Abs(value)
+	if abs == 0 || (abs >= 1e-3 && abs < 1e7) {
+		str := strconv.FormatFloat(value, 'f', -1, bitSize)
+		if !strings.Contains(str, ".") {
+			str += ".0"
+		}
+		return str
+	}
+
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(value, 'E', -1, bitSize), "E")
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	exp, _ := strconv.Atoi(exponent)
+	return mantissa + "E" + strconv.Itoa(exp)
+}
+
+// unsignedForRadix returns a Short, Integer, or Long value as the unsigned number of the
+// same size with the same bits, which is how Java's %o and %x format a negative number of
+// those classes (and Byte). Other values, such as a BigInteger, which Java formats with a
+// sign, are returned as is.
+func unsignedForRadix(value any, className string) any {
+	number, ok := value.(int64)
+	if !ok {
+		return value
+	}
+	switch className {
+	case "java.lang.Byte":
+		return uint8(number)
+	case "java.lang.Short":
+		return uint16(number)
+	case "java.lang.Integer":
+		return uint32(number)
+	case "java.lang.Long":
+		return uint64(number)
+	default:
+		return value
+	}
+}
+
+// formatJavaRadix formats an unsigned value for the %o, %x, or %X conversion as Java does:
+// the '#' flag puts 0 before the octal digits or 0x before the hex ones, and the width
+// includes that prefix, which precedes any zero padding, so %#010x of 255 is 0x000000ff.
+// %X is in upper case, prefix and all. It returns false for a value of any other type.
+func formatJavaRadix(value any, flags, width string, conversion byte) (string, bool) {
+	var number uint64
+	switch value := value.(type) {
+	case uint8:
+		number = uint64(value)
+	case uint16:
+		number = uint64(value)
+	case uint32:
+		number = uint64(value)
+	case uint64:
+		number = value
+	default:
+		return "", false
+	}
+
+	base, prefix := 16, "0x"
+	if conversion == 'o' {
+		base, prefix = 8, "0"
+	}
+	if strings.IndexByte(flags, '#') < 0 {
+		prefix = ""
+	}
+	str := signAndPadNumber(false, prefix, strconv.FormatUint(number, base), flags, width, true)
+	if conversion == 'X' {
+		str = strings.ToUpper(str)
+	}
+	return str, true
+}
+
+// formatDecimalNumber formats an integral or floating-point value for a %d or %f conversion.
+// With the ',' flag, as in 1,234,567 and -1,234.50, the digits of the integer part are grouped
+// in threes, with the sign, if any, outside the groups. The other flags and the width are
+// applied as Java does. It returns false for a value of any other type.
+func formatDecimalNumber(value any, flags, width, precision string) (string, bool) {
+	grouped := func(number string) string {
+		if strings.IndexByte(flags, ',') < 0 {
+			return number
+		}
+		return groupDigits(number)
+	}
+
+	switch value := value.(type) {
+	case int64:
+		abs := uint64(value)
+		if value < 0 {
+			abs = -abs // also right for the most negative long, whose magnitude is 2^63
+		}
+		magnitude := grouped(strconv.FormatUint(abs, 10))
+		return signAndPadNumber(value < 0, "", magnitude, flags, width, true), true
+	case float64:
+		prec := 6
+		if precision != "" {
+			prec, _ = strconv.Atoi(precision[1:])
+		}
+		switch {
+		case math.IsNaN(value):
+			return signAndPadNumber(false, "", "NaN", flags, width, false), true
+		case math.IsInf(value, 0):
+			return signAndPadNumber(value < 0, "", "Infinity", flags, width, false), true
+		}
+		magnitude := grouped(strconv.FormatFloat(math.Abs(value), 'f', prec, 64))
+		return signAndPadNumber(math.Signbit(value), "", magnitude, flags, width, true), true
+	default:
+		return "", false
+	}
+}
+
+// signAndPadNumber adds the sign of a formatted number to its magnitude, as the flags of its
+// format specifier call for: a negative number is preceded by '-' or, with the '(' flag,
+// enclosed in parentheses, and a positive one is preceded by '+' or ' ' with those flags.
+// The number is then padded to the width, with spaces on the left, or on the right with the
+// '-' flag, or, with the '0' flag and if zeroPadAllowed, with zeros between the sign (and the
+// prefix, such as the 0x of %a) and the digits.
+func signAndPadNumber(negative bool, prefix, magnitude, flags, width string, zeroPadAllowed bool) string {
+	sign := ""
+	switch {
+	case magnitude == "NaN": // which has no sign
+	case negative && strings.IndexByte(flags, '(') >= 0:
+		sign, magnitude = "(", magnitude+")"
+	case negative:
+		sign = "-"
+	case strings.IndexByte(flags, '+') >= 0:
+		sign = "+"
+	case strings.IndexByte(flags, ' ') >= 0:
+		sign = " "
+	}
+
+	w, _ := strconv.Atoi(width)
+	padding := w - len(sign) - len(prefix) - len(magnitude)
+	str := sign + prefix + magnitude
+	if padding > 0 {
+		switch {
+		case strings.IndexByte(flags, '-') >= 0:
+			str += strings.Repeat(" ", padding)
+		case strings.IndexByte(flags, '0') >= 0 && zeroPadAllowed:
+			str = sign + prefix + strings.Repeat("0", padding) + magnitude
+		default:
+			str = strings.Repeat(" ", padding) + str
+		}
+	}
+	return str
+}
+
+// groupDigits inserts a comma between each group of three digits in the integer part of a
+// formatted number, which can be preceded by a sign and followed by a fraction.
+func groupDigits(number string) string {
+	first := 0
+	for first < len(number) && (number[first] < '0' || number[first] > '9') {
+		first++
+	}
+	last := first
+	for last < len(number) && number[last] >= '0' && number[last] <= '9' {
+		last++
+	}
+
+	digits := number[first:last]
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	return number[:first] + grouped.String() + number[last:]
+}
+
+// "java/lang/String.hashCode()I" is computed as in Java, over the UTF-16 code units:
+// s[0]*31^(n-1) + s[1]*31^(n-2) + ... + s[n-1], in int arithmetic. An empty String hashes to 0.
+func stringHashCode(params []interface{}) interface{} {
+	var hash int32
+	for _, unit := range stringUTF16(params[0].(*object.Object)) {
+		hash = 31*hash + int32(unit)
+	}
+	return int64(hash)
+}
+
+// "java/lang/String.indexOf(I)I" and "java/lang/String.indexOf(II)I" return the UTF-16 index
+// of the first occurrence of a code point, starting at fromIndex if given. As in the JDK, a
+// negative fromIndex searches the whole string, and a supplementary code point is found as a
+// surrogate pair, whose index is that of the high surrogate.
+func stringIndexOfCodePoint(params []interface{}) interface{} {
+	units := stringUTF16(params[0].(*object.Object))
+	codePoint := params[1].(int64)
+	fromIndex := int64(0)
+	if len(params) > 2 {
+		fromIndex = max(params[2].(int64), 0)
+	}
+
+	high, low, ok := codePointUnits(codePoint)
+	if !ok {
+		return int64(-1)
+	}
+	for i := fromIndex; i < int64(len(units)); i++ {
+		if units[i] == high && (low == 0 || (i+1 < int64(len(units)) && units[i+1] == low)) {
+			return i
+		}
+	}
+	return int64(-1)
+}
+
+// "java/lang/String.lastIndexOf(I)I" and "java/lang/String.lastIndexOf(II)I" return the UTF-16
+// index of the last occurrence of a code point, searching backward from fromIndex if given.
+// A fromIndex past the end searches the whole string; a negative one finds nothing.
+func stringLastIndexOfCodePoint(params []interface{}) interface{} {
+	units := stringUTF16(params[0].(*object.Object))
+	codePoint := params[1].(int64)
+	fromIndex := int64(len(units)) - 1
+	if len(params) > 2 {
+		fromIndex = min(params[2].(int64), fromIndex)
+	}
+
+	high, low, ok := codePointUnits(codePoint)
+	if !ok {
+		return int64(-1)
+	}
+	for i := fromIndex; i >= 0; i-- {
+		if units[i] == high && (low == 0 || (i+1 < int64(len(units)) && units[i+1] == low)) {
+			return i
+		}
+	}
+	return int64(-1)
+}
+
+// codePointUnits returns the UTF-16 code units a code point is searched for as: the char
+// itself for a BMP code point (with low set to 0), or the surrogate pair for a supplementary
+// one. ok is false for a value that is not a valid code point, which is never found.
+func codePointUnits(codePoint int64) (high, low uint16, ok bool) {
+	switch {
+	case codePoint >= 0 && codePoint < 0x10000:
+		return uint16(codePoint), 0, true
+	case codePoint >= 0x10000 && codePoint <= unicode.MaxRune:
+		r1, r2 := utf16.EncodeRune(rune(codePoint))
+		return uint16(r1), uint16(r2), true
+	}
+	return 0, 0, false
+}
+
+// "java/lang/String.isEmpty()Z"
+func stringIsEmpty(params []interface{}) interface{} {
+	if len(object.ByteArrayFromStringObject(params[0].(*object.Object))) == 0 {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
 // "java/lang/String.isLatin1()Z"
 func stringIsLatin1(params []interface{}) interface{} {
 	// TODO: Someday, the answer might be false.
 	return int64(1) // true
 }
 
-// "java/lang/String.length()I"
+// "java/lang/String.length()I" is the number of UTF-16 chars in the string, which is the
+// number of bytes in an ASCII string, but otherwise counts each supplementary character as
+// two chars and each other character as one. In the JDK, length() depends on the coder
+// field, as the value of a UTF16-coded string holds two bytes per char. In Jacobin, though,
+// the value is always the UTF-8 encoding of the string, whatever its characters, and the
+// coder is always LATIN1 (see isLatin1()), so the chars are counted from the UTF-8 bytes.
 func stringLength(params []interface{}) interface{} {
 	// params[0] = string object whose string length is to be measured
 	obj := params[0].(*object.Object)
 	bytes := object.ByteArrayFromStringObject(obj)
-	return int64(len(bytes))
+	length := int64(0)
+	for _, ch := range string(bytes) {
+		if ch > 0xFFFF {
+			length += 2
+		} else {
+			length++
+		}
+	}
+	return length
+}
+
+// "java/lang/String.lines()Ljava/util/stream/Stream;" As in the JDK, a line is ended by
+// "\n", "\r", or "\r\n", which is not part of the line. An empty string has no lines, and
+// a line terminator at the very end of the string does not begin another line.
+func stringLines(params []interface{}) interface{} {
+	str := object.GoStringFromStringObject(params[0].(*object.Object))
+
+	lines := []*object.Object{}
+	for len(str) > 0 {
+		end := strings.IndexAny(str, "\r\n")
+		if end < 0 {
+			lines = append(lines, object.StringObjectFromGoString(str))
+			break
+		}
+		lines = append(lines, object.StringObjectFromGoString(str[:end]))
+		if strings.HasPrefix(str[end:], "\r\n") {
+			end++
+		}
+		str = str[end+1:]
+	}
+	return makeStream(lines)
+}
+
+// "java/lang/String.matches(Ljava/lang/String;)Z"
+func stringMatches(params []interface{}) interface{} {
+	// params[0] = string to test
+	// params[1] = regular expression
+	str := object.GoStringFromStringObject(params[0].(*object.Object))
+	regex := object.GoStringFromStringObject(params[1].(*object.Object))
+
+	// unlike find(), matches() succeeds only if the regex matches the entire string
+	re, err := compileJavaRegex("^(?:" + regex + ")$")
+	if err != nil {
+		errMsg := fmt.Sprintf("String.matches: %s", err.Error())
+		return getGErrBlk(excNames.PatternSyntaxException, errMsg)
+	}
+	if re.MatchString(str) {
+		return int64(1) // true
+	}
+	return int64(0) // false
+}
+
+// "java/lang/String.regionMatches(ILjava/lang/String;II)Z"
+func stringRegionMatches(params []interface{}) interface{} {
+	// params[0] = this string
+	// params[1] = offset into this string
+	// params[2] = other string
+	// params[3] = offset into other string
+	// params[4] = number of characters to compare
+	return regionMatches(params[0], false, params[1], params[2], params[3], params[4])
+}
+
+// "java/lang/String.regionMatches(ZILjava/lang/String;II)Z"
+func stringRegionMatchesIgnoreCase(params []interface{}) interface{} {
+	// params[0] = this string
+	// params[1] = ignore case? (boolean)
+	// params[2..5] = as for stringRegionMatches above
+	ignoreCase := params[1].(int64) != 0
+	return regionMatches(params[0], ignoreCase, params[2], params[3], params[4], params[5])
+}
+
+// regionMatches does the work for both regionMatches() methods. As in Java, the offsets and
+// length count UTF-16 chars, which are compared one by one, and offsets or lengths that reach
+// outside either string result in false, not an exception.
+func regionMatches(thisArg any, ignoreCase bool, toffsetArg, otherArg, ooffsetArg, lenArg any) interface{} {
+	if object.IsNull(otherArg) {
+		errMsg := "String.regionMatches: null argument"
+		return getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+
+	chars1 := stringUTF16(thisArg.(*object.Object))
+	chars2 := stringUTF16(otherArg.(*object.Object))
+	toffset := toffsetArg.(int64)
+	ooffset := ooffsetArg.(int64)
+	length := lenArg.(int64)
+
+	if toffset < 0 || ooffset < 0 ||
+		toffset > int64(len(chars1))-length || ooffset > int64(len(chars2))-length {
+		return int64(0) // false
+	}
+	if length <= 0 {
+		return int64(1) // true
+	}
+
+	region1 := chars1[toffset : toffset+length]
+	region2 := chars2[ooffset : ooffset+length]
+	if ignoreCase {
+		if charsEqualIgnoreCase(region1, region2) {
+			return int64(1) // true
+		}
+		return int64(0) // false
+	}
+	for i := range region1 {
+		if region1[i] != region2[i] {
+			return int64(0) // false
+		}
+	}
+	return int64(1) // true
+}
+
+// charsEqualIgnoreCase compares two equal-length slices of UTF-16 chars the way Java's
+// String.equalsIgnoreCase() does: char by char, with two chars matching if they
+// are the same, or have the same upper case, or have the same lower case after
+// being upper-cased. (This is not a locale-aware, full case folding.)
+func charsEqualIgnoreCase(chars1, chars2 []uint16) bool {
+	for i := range chars1 {
+		c1, c2 := rune(chars1[i]), rune(chars2[i])
+		if c1 == c2 {
+			continue
+		}
+		u1, u2 := unicode.ToUpper(c1), unicode.ToUpper(c2)
+		if u1 == u2 || unicode.ToLower(u1) == unicode.ToLower(u2) {
+			continue
+		}
+		return false
+	}
+	return true
 }
 
 // "java/lang/String.(I)Ljava/lang/String;"
@@ -605,31 +1800,57 @@ func stringRepeat(params []interface{}) interface{} {
 
 }
 
-// "java/lang/String.substring(I)Ljava/lang/String;"
-func substringToTheEnd(params []interface{}) interface{} {
+// "java/lang/String.replace(Ljava/lang/CharSequence;Ljava/lang/CharSequence;)Ljava/lang/String;"
+// replaces the occurrences of the target from the start of the string to the end. The matches
+// are counted first, so that the new string is built in a single pass into a buffer of the
+// right size, which keeps the replacement linear in the length of large strings. As in Java,
+// an empty target matches before each char and at the end of the string.
+func stringReplaceCharSequence(params []interface{}) interface{} {
 	// params[0] = base string
-	// params[1] = start offset
-	str := object.GoStringFromStringObject(params[0].(*object.Object))
-
-	// Get substring start offset and compute end offset
-	ssStart := params[1].(int64)
-	ssEnd := int64(len(str))
-
-	// Validate boundaries.
-	totalLength := int64(len(str))
-	if totalLength < 1 || ssStart < 0 || ssEnd < 1 || ssStart > (totalLength-1) || ssEnd > totalLength {
-		errMsg1 := "Either: nil input byte array, invalid substring offset, or invalid substring length"
-		errMsg2 := fmt.Sprintf("\n\twhole='%s' wholelen=%d, offset=%d, sslen=%d\n\n", str, totalLength, ssStart, ssEnd)
-		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg1+errMsg2)
+	// params[1] = the CharSequence to replace (the target)
+	// params[2] = the CharSequence to replace it with
+	var args [2]string
+	for i := range args {
+		if object.IsNull(params[i+1]) {
+			return getGErrBlk(excNames.NullPointerException, "String.replace: null argument")
+		}
+		var ok bool
+		if args[i], ok = object.CharSequenceToGoString(params[i+1].(*object.Object)); !ok {
+			return getGErrBlk(excNames.IllegalArgumentException, "String.replace: argument is not a CharSequence")
+		}
 	}
+	target, replacement := []byte(args[0]), []byte(args[1])
 
-	// Compute substring.
-	str = str[ssStart:ssEnd]
+	src := object.ByteArrayFromStringObject(params[0].(*object.Object))
+	matches := bytes.Count(src, target) // for an empty target, the number of chars + 1
+	if matches == 0 {
+		return params[0] // nothing to replace, so Java returns the same string
+	}
 
-	// Return new string in an object.
-	obj := object.StringObjectFromGoString(str)
-	return obj
+	buf := make([]byte, 0, len(src)+matches*(len(replacement)-len(target)))
+	if len(target) == 0 {
+		for len(src) > 0 {
+			_, size := utf8.DecodeRune(src)
+			buf = append(append(buf, replacement...), src[:size]...)
+			src = src[size:]
+		}
+		buf = append(buf, replacement...)
+	} else {
+		for i := bytes.Index(src, target); i >= 0; i = bytes.Index(src, target) {
+			buf = append(append(buf, src[:i]...), replacement...)
+			src = src[i+len(target):]
+		}
+		buf = append(buf, src...)
+	}
+	return object.StringObjectFromByteArray(buf)
+}
 
+// "java/lang/String.substring(I)Ljava/lang/String;"
+func substringToTheEnd(params []interface{}) interface{} {
+	// params[0] = base string
+	// params[1] = start offset
+	units := stringUTF16(params[0].(*object.Object))
+	return substringOfUTF16(units, params[1].(int64), int64(len(units)))
 }
 
 // "java/lang/String.substring(II)Ljava/lang/String;"
@@ -637,36 +1858,32 @@ func substringStartEnd(params []interface{}) interface{} {
 	// params[0] = base string
 	// params[1] = start offset
 	// params[2] = end offset
-	str := object.GoStringFromStringObject(params[0].(*object.Object))
-
-	// Get substring start and end offset
-	ssStart := params[1].(int64)
-	ssEnd := params[2].(int64)
+	units := stringUTF16(params[0].(*object.Object))
+	return substringOfUTF16(units, params[1].(int64), params[2].(int64))
+}
 
-	// Validate boundaries.
-	totalLength := int64(len(str))
-	if totalLength < 1 || ssStart < 0 || ssEnd < 1 || ssStart > (totalLength-1) || ssEnd > totalLength {
-		errMsg1 := "Either: nil input byte array, invalid substring offset, or invalid substring length"
-		errMsg2 := fmt.Sprintf("\n\twhole='%s' wholelen=%d, offset=%d, sslen=%d\n\n", str, totalLength, ssStart, ssEnd)
-		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg1+errMsg2)
+// substringOfUTF16 returns the String of the UTF-16 chars from ssStart up to ssEnd, which,
+// as in Java, are indices of chars, not of the bytes of the string
+func substringOfUTF16(units []uint16, ssStart, ssEnd int64) interface{} {
+	// Validate boundaries. As in Java, the substring can be empty, even of an empty string.
+	totalLength := int64(len(units))
+	if ssStart < 0 || ssStart > ssEnd || ssEnd > totalLength {
+		errMsg := fmt.Sprintf("begin %d, end %d, length %d", ssStart, ssEnd, totalLength)
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
 	}
 
-	// Compute substring.
-	str = str[ssStart:ssEnd]
-
 	// Return new string in an object.
-	obj := object.StringObjectFromGoString(str)
-	return obj
+	return object.StringObjectFromGoString(string(utf16.Decode(units[ssStart:ssEnd])))
 }
 
-// "java/lang/String.toCharArray()[C"
+// "java/lang/String.toCharArray()[C" returns the UTF-16 chars of the string, in which a
+// supplementary character is a surrogate pair
 func toCharArray(params []interface{}) interface{} {
 	// params[0]: input string
-	obj := params[0].(*object.Object)
-	bytes := obj.FieldTable["value"].Fvalue.([]byte)
-	var iArray []int64
-	for _, bb := range bytes {
-		iArray = append(iArray, int64(bb))
+	units := stringUTF16(params[0].(*object.Object))
+	iArray := make([]int64, len(units))
+	for i, unit := range units {
+		iArray[i] = int64(unit)
 	}
 	return populator("[C", types.IntArray, iArray)
 }
@@ -719,45 +1936,38 @@ func valueOfChar(params []interface{}) interface{} {
 }
 
 // "java/lang/String.valueOf([C)Ljava/lang/String;"
+// "java/lang/String.copyValueOf([C)Ljava/lang/String;"
 func valueOfCharArray(params []interface{}) interface{} {
 	// params[0]: input char array
-	propObj := params[0].(*object.Object)
-	intArray := propObj.FieldTable["value"].Fvalue.([]int64)
-	var str string
-	for _, ch := range intArray {
-		str += fmt.Sprintf("%c", ch)
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "String.valueOf: char array is null")
 	}
-	obj := object.StringObjectFromGoString(str)
-	return obj
+	intArray := params[0].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	return object.StringObjectFromGoString(charsToGoString(intArray))
 }
 
 // "java/lang/String.valueOf([CII)Ljava/lang/String;"
+// "java/lang/String.copyValueOf([CII)Ljava/lang/String;"
 func valueOfCharSubarray(params []interface{}) interface{} {
 	// params[0]: input char array
 	// params[1]: input offset
 	// params[2]: input count
-	propObj := params[0].(*object.Object)
-	intArray := propObj.FieldTable["value"].Fvalue.([]int64)
-	var wholeString string
-	for _, ch := range intArray {
-		wholeString += fmt.Sprintf("%c", ch)
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "String.valueOf: char array is null")
 	}
-	// Get substring offset and count
+	intArray := params[0].(*object.Object).FieldTable["value"].Fvalue.([]int64)
 	ssOffset := params[1].(int64)
 	ssCount := params[2].(int64)
 
-	// Validate boundaries.
-	wholeLength := int64(len(wholeString))
-	if wholeLength < 1 || ssOffset < 0 || ssCount < 1 || ssOffset > (wholeLength-1) || (ssOffset+ssCount) > wholeLength {
-		errMsg := "Either: nil input byte array, invalid substring offset, or invalid substring length"
+	// Validate boundaries, which are in chars rather than in the bytes of the resulting string.
+	length := int64(len(intArray))
+	if ssOffset < 0 || ssCount < 0 || ssOffset > length-ssCount {
+		errMsg := fmt.Sprintf("offset %d, count %d, length %d", ssOffset, ssCount, length)
 		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
 	}
 
-	// Compute substring.
-	str := wholeString[ssOffset : ssOffset+ssCount]
-
-	obj := object.StringObjectFromGoString(str)
-	return obj
+	str := charsToGoString(intArray[ssOffset : ssOffset+ssCount])
+	return object.StringObjectFromGoString(str)
 }
 
 // "java/lang/String.valueOf(D)Ljava/lang/String;"
@@ -845,11 +2055,62 @@ func compareToIgnoreCase(params []interface{}) interface{} {
 func stringConcat(params []interface{}) interface{} {
 	var str1, str2 string
 
-	fld := params[0].(*object.Object).FieldTable["value"]
-	str1 = string(fld.Fvalue.([]byte))
-	fld = params[1].(*object.Object).FieldTable["value"]
-	str2 = string(fld.Fvalue.([]byte))
+	if object.IsNull(params[1]) {
+		errMsg := "String.concat: null argument"
+		return getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+
+	str1 = object.GoStringFromStringObject(params[0].(*object.Object))
+	str2 = object.GoStringFromStringObject(params[1].(*object.Object))
 	str := str1 + str2
 	obj := object.StringObjectFromGoString(str)
 	return obj
 }
+
+// "java/lang/String.contains(Ljava/lang/CharSequence;)Z"
+func stringContains(params []interface{}) interface{} {
+	// params[0] = string to search
+	// params[1] = the CharSequence (such as a String or StringBuilder) to look for
+	if object.IsNull(params[1]) {
+		errMsg := "String.contains: null argument"
+		return getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+
+	searchFor, ok := object.CharSequenceToGoString(params[1].(*object.Object))
+	if !ok {
+		errMsg := "String.contains: argument is not a CharSequence"
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+
+	str := object.GoStringFromStringObject(params[0].(*object.Object))
+	if strings.Contains(str, searchFor) {
+		return int64(1) // true
+	}
+	return int64(0) // false
+}
+
+// Java's regular expressions are largely the same as golang's, except for the
+// names of the POSIX character classes. So, translate those to golang classes.
+// (The translation applies only to classes outside of square brackets.)
+var javaRegexPosixClasses = strings.NewReplacer(
+	`\p{Lower}`, `[a-z]`,
+	`\p{Upper}`, `[A-Z]`,
+	`\p{ASCII}`, `[\x00-\x7F]`,
+	`\p{Alpha}`, `[a-zA-Z]`,
+	`\p{Digit}`, `[0-9]`,
+	`\p{Alnum}`, `[a-zA-Z0-9]`,
+	`\p{Punct}`, `[[:punct:]]`,
+	`\p{Graph}`, `[[:graph:]]`,
+	`\p{Print}`, `[[:print:]]`,
+	`\p{Blank}`, `[ \t]`,
+	`\p{Cntrl}`, `[[:cntrl:]]`,
+	`\p{XDigit}`, `[0-9a-fA-F]`,
+	`\p{Space}`, `\s`,
+)
+
+// compileJavaRegex compiles a Java regular expression into a golang regexp.
+// Java features that golang's RE2 engine lacks, such as backreferences and
+// lookaround, produce an error.
+func compileJavaRegex(regex string) (*regexp.Regexp, error) {
+	return regexp.Compile(javaRegexPosixClasses.Replace(regex))
+}
@@ -6,9 +6,25 @@
 
 package gfunction
 
-import "jacobin/object"
+import (
+	"container/list"
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"slices"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
 
-// Implementation of some of the functions in Java/lang/Class.
+// Implementation of some of the functions in java/lang/StringBuilder.
+//
+// The builder's buffer, in its value field, holds UTF-8, and its count field is the number of
+// bytes in use. Java indexes a builder by UTF-16 chars, which, for other than ASCII, the JDK's
+// bytecode would take to be indexes of the bytes. So every method that takes or returns an
+// index or a length is implemented here, on the UTF-16 chars of the buffer, or else trapped.
 
 func Load_Lang_StringBuilder() {
 
@@ -30,6 +46,243 @@ func Load_Lang_StringBuilder() {
 			GFunction:  justReturn,
 		}
 
+	MethodSignatures["java/lang/StringBuilder.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderInitCapacity,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.<init>(Ljava/lang/CharSequence;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    stringBuilderInitCharSequence,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.<init>(Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    stringBuilderInitCharSequence,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.append(Ljava/lang/Object;)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    stringBuilderAppendObject,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.append(Ljava/lang/CharSequence;)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    stringBuilderAppendObject,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.appendCodePoint(I)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderAppendCodePoint,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.charAt(I)C"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderCharAt,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.indexOf(Ljava/lang/String;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderIndexOf,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.indexOf(Ljava/lang/String;I)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderIndexOf,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.length()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringBuilderLength,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringBuilderToString,
+		}
+
+	// the methods that take or return an index
+	MethodSignatures["java/lang/StringBuilder.codePointAt(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderCodePointAt,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.codePointBefore(I)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderCodePointBefore,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.codePointCount(II)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderCodePointCount,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.delete(II)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderDelete,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.deleteCharAt(I)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderDeleteCharAt,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(IZ)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderInsertBoolean,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(IC)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderInsertChar,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(ID)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  stringBuilderInsertDouble,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(IF)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderInsertFloat,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(II)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderInsertInteger,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(IJ)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  stringBuilderInsertInteger,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(I[C)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderInsertChars,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(I[CII)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 4,
+			GFunction:  stringBuilderInsertChars,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(ILjava/lang/String;)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    stringBuilderInsertObject,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(ILjava/lang/Object;)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    stringBuilderInsertObject,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(ILjava/lang/CharSequence;)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    stringBuilderInsertObject,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.insert(ILjava/lang/CharSequence;II)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots:   4,
+			GFunction:    stringBuilderInsertObject,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.lastIndexOf(Ljava/lang/String;)I"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderLastIndexOf,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.lastIndexOf(Ljava/lang/String;I)I"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderLastIndexOf,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.replace(IILjava/lang/String;)Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  stringBuilderReplace,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.reverse()Ljava/lang/StringBuilder;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  stringBuilderReverse,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.setCharAt(IC)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderSetCharAt,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.setLength(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderSetLength,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.subSequence(II)Ljava/lang/CharSequence;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderSubstring,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.substring(I)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  stringBuilderSubstring,
+		}
+
+	MethodSignatures["java/lang/StringBuilder.substring(II)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  stringBuilderSubstring,
+		}
+
+	trapMethods("java/lang/StringBuilder",
+		"chars()Ljava/util/stream/IntStream;",
+		"codePoints()Ljava/util/stream/IntStream;",
+		"getChars(II[CI)V",
+		"offsetByCodePoints(II)I",
+	)
 }
 
 // Instantiate a new empty string - "java/lang/StringBuilder.<init>()V"
@@ -41,8 +294,468 @@ func stringBuilderInitEmpty(params []interface{}) interface{} {
 	return nil
 }
 
+// the extra capacity of a StringBuilder created from a CharSequence, as in the JDK
+const stringBuilderExtraCapacity = 16
+
+// stringBuilderSetValue sets the builder's buffer to the string, with room for capacity more bytes
+func stringBuilderSetValue(sb *object.Object, str string, capacity int) {
+	bytes := make([]byte, len(str), len(str)+capacity)
+	copy(bytes, str)
+	object.UpdateStringObjectFromBytes(sb, bytes)
+	sb.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(len(bytes))}
+}
+
+// the most bytes allocated up front for the capacity given to a StringBuilder. The buffer
+// grows as it's appended to, so a larger capacity is allocated only if it's needed.
+const stringBuilderMaxInitialCapacity = 8192
+
+// "java/lang/StringBuilder.<init>(I)V" creates an empty builder with the given capacity
+func stringBuilderInitCapacity(params []interface{}) interface{} {
+	capacity := params[1].(int64)
+	if capacity < 0 {
+		return getGErrBlk(excNames.NegativeArraySizeException, fmt.Sprintf("%d", capacity))
+	}
+	stringBuilderSetValue(params[0].(*object.Object), "", int(min(capacity, stringBuilderMaxInitialCapacity)))
+	return nil
+}
+
+// "java/lang/StringBuilder.<init>(Ljava/lang/CharSequence;)V" and
+// "java/lang/StringBuilder.<init>(Ljava/lang/String;)V" create a builder holding the chars of
+// the sequence. A CharSequence other than a String, StringBuilder, or StringBuffer is read
+// through its toString(), which is run through globals.FuncInvokeMethod.
+// params[0] = the frame stack, params[1] = the StringBuilder, params[2] = the CharSequence
+func stringBuilderInitCharSequence(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	sb := params[1].(*object.Object)
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "StringBuilder: CharSequence is null")
+	}
+
+	str, ok := object.CharSequenceToGoString(params[2].(*object.Object))
+	if !ok {
+		ret, err := globals.GetGlobalRef().FuncInvokeMethod(fs, params[2], "toString", "()Ljava/lang/String;")
+		if err != nil {
+			errMsg := fmt.Sprintf("StringBuilder: toString() failed: %s", err.Error())
+			return getGErrBlk(excNames.VirtualMachineError, errMsg)
+		}
+		if object.IsNull(ret) {
+			return getGErrBlk(excNames.NullPointerException, "StringBuilder: toString() returned null")
+		}
+		str = object.GoStringFromStringObject(ret.(*object.Object))
+	}
+
+	stringBuilderSetValue(sb, str, stringBuilderExtraCapacity)
+	return nil
+}
+
 // "java/lang/StringBuilder.isLatin1()Z"
 func isLatin1([]interface{}) interface{} {
 	// TODO: Someday, jacobin will need to discern between StringLatin1 and StringUTF16.
 	return int64(1)
 }
+
+// "java/lang/StringBuilder.append(Ljava/lang/Object;)Ljava/lang/StringBuilder;" and
+// "java/lang/StringBuilder.append(Ljava/lang/CharSequence;)Ljava/lang/StringBuilder;" append
+// the chars of a String, StringBuilder, or StringBuffer, or else the string returned by the
+// object's toString(), or "null" if the object is null. The toString() can be a gfunction or
+// Java bytecode, so it's run through globals.FuncInvokeMethod.
+// params[0] = the frame stack, params[1] = the StringBuilder, params[2] = the object
+func stringBuilderAppendObject(params []interface{}) interface{} {
+	sb := params[1].(*object.Object)
+	str, errBlk := stringBuilderStringOf(params[0].(*list.List), params[2], "StringBuilder.append")
+	if errBlk != nil {
+		return errBlk
+	}
+
+	current, _ := object.CharSequenceToGoString(sb)
+	bytes := []byte(current + str)
+	object.UpdateStringObjectFromBytes(sb, bytes)
+	sb.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(len(bytes))}
+	return sb
+}
+
+// stringBuilderStringOf returns the string that append() and insert() add for an object: the
+// chars of a String, StringBuilder, or StringBuffer, or else the string returned by the
+// object's toString(), or "null" if the object is null. The caller names the method for
+// error messages.
+func stringBuilderStringOf(fs *list.List, arg any, caller string) (string, *GErrBlk) {
+	if object.IsNull(arg) {
+		return "null", nil
+	}
+	if str, ok := object.CharSequenceToGoString(arg.(*object.Object)); ok {
+		return str, nil
+	}
+	ret, err := globals.GetGlobalRef().FuncInvokeMethod(fs, arg, "toString", "()Ljava/lang/String;")
+	if err != nil {
+		errMsg := fmt.Sprintf("%s: toString() failed: %s", caller, err.Error())
+		return "", getGErrBlk(excNames.VirtualMachineError, errMsg)
+	}
+	if object.IsNull(ret) {
+		return "null", nil
+	}
+	return object.GoStringFromStringObject(ret.(*object.Object)), nil
+}
+
+// "java/lang/StringBuilder.appendCodePoint(I)Ljava/lang/StringBuilder;" appends the char of a
+// code point, or, for a supplementary code point (above U+FFFF), the two chars of its surrogate
+// pair. The buffer holds UTF-8, so the code point is appended as its UTF-8 bytes, which
+// length() and charAt() then count as one or two UTF-16 chars. (A lone surrogate, which
+// UTF-8 can't hold, is appended as U+FFFD.)
+func stringBuilderAppendCodePoint(params []interface{}) interface{} {
+	sb := params[0].(*object.Object)
+	codePoint := params[1].(int64)
+	if codePoint < 0 || codePoint > unicode.MaxRune {
+		errMsg := fmt.Sprintf("Not a valid Unicode code point: 0x%X", uint32(codePoint))
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+
+	current, _ := object.CharSequenceToGoString(sb)
+	bytes := utf8.AppendRune([]byte(current), rune(codePoint))
+	object.UpdateStringObjectFromBytes(sb, bytes)
+	sb.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(len(bytes))}
+	return sb
+}
+
+// stringBuilderUTF16 returns the contents of the builder as Java sees them, as UTF-16 chars
+func stringBuilderUTF16(sb *object.Object) []uint16 {
+	current, _ := object.CharSequenceToGoString(sb)
+	return utf16.Encode([]rune(current))
+}
+
+// stringBuilderSetUTF16 sets the contents of the builder to the UTF-16 chars, which are stored
+// as UTF-8. (A lone surrogate, which UTF-8 can't hold, is stored as U+FFFD.)
+func stringBuilderSetUTF16(sb *object.Object, units []uint16) {
+	stringBuilderSetValue(sb, string(utf16.Decode(units)), 0)
+}
+
+// stringBuilderRangeError returns the StringIndexOutOfBoundsException for a range of chars
+// that isn't within the builder, with the JDK's message
+func stringBuilderRangeError(start, end int64, length int) *GErrBlk {
+	errMsg := fmt.Sprintf("start %d, end %d, length %d", start, end, length)
+	return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+}
+
+// stringBuilderIndexError returns the StringIndexOutOfBoundsException for an index that isn't
+// that of a char in the builder, with the message of charAt()
+func stringBuilderIndexError(index int64, length int) *GErrBlk {
+	errMsg := fmt.Sprintf("index %d,length %d", index, length)
+	return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+}
+
+// "java/lang/StringBuilder.indexOf(Ljava/lang/String;)I" and
+// "java/lang/StringBuilder.indexOf(Ljava/lang/String;I)I" return the UTF-16 index of the first
+// occurrence of the string in the builder, starting at fromIndex if given, or -1 if there is
+// none. As in the JDK, a negative fromIndex searches the whole builder.
+func stringBuilderIndexOf(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "StringBuilder.indexOf: string is null")
+	}
+	units := stringBuilderUTF16(params[0].(*object.Object))
+	target, _ := object.CharSequenceToGoString(params[1].(*object.Object))
+	targetUnits := utf16.Encode([]rune(target))
+	fromIndex := int64(0)
+	if len(params) > 2 {
+		fromIndex = max(params[2].(int64), 0)
+	}
+
+	for i := fromIndex; i+int64(len(targetUnits)) <= int64(len(units)); i++ {
+		if slices.Equal(units[i:i+int64(len(targetUnits))], targetUnits) {
+			return i
+		}
+	}
+	return int64(-1)
+}
+
+// "java/lang/StringBuilder.length()I" returns the number of UTF-16 chars in the builder
+func stringBuilderLength(params []interface{}) interface{} {
+	return int64(len(stringBuilderUTF16(params[0].(*object.Object))))
+}
+
+// "java/lang/StringBuilder.charAt(I)C" returns the UTF-16 char at the index, which, for a
+// supplementary code point, is one of the chars of its surrogate pair
+func stringBuilderCharAt(params []interface{}) interface{} {
+	chars := stringBuilderUTF16(params[0].(*object.Object))
+	index := params[1].(int64)
+	if index < 0 || index >= int64(len(chars)) {
+		errMsg := fmt.Sprintf("index %d,length %d", index, len(chars))
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
+	return int64(chars[index])
+}
+
+// "java/lang/StringBuilder.toString()Ljava/lang/String;" returns a new String holding a copy of
+// the first count bytes of the builder's buffer. The buffer is later appended to and modified
+// in place, so the String must not share it.
+func stringBuilderToString(params []interface{}) interface{} {
+	sb := params[0].(*object.Object)
+	str, ok := object.CharSequenceToGoString(sb)
+	if !ok {
+		return getGErrBlk(excNames.VirtualMachineError, "StringBuilder.toString: invalid StringBuilder")
+	}
+	return object.StringObjectFromGoString(str)
+}
+
+// "java/lang/StringBuilder.codePointAt(I)I" returns the code point that starts at the index:
+// that of a surrogate pair, if the index is that of its first char, or else the char
+func stringBuilderCodePointAt(params []interface{}) interface{} {
+	units := stringBuilderUTF16(params[0].(*object.Object))
+	index := params[1].(int64)
+	if index < 0 || index >= int64(len(units)) {
+		return stringBuilderIndexError(index, len(units))
+	}
+	if index+1 < int64(len(units)) && utf16.IsSurrogate(rune(units[index])) {
+		if r := utf16.DecodeRune(rune(units[index]), rune(units[index+1])); r != unicode.ReplacementChar {
+			return int64(r)
+		}
+	}
+	return int64(units[index])
+}
+
+// "java/lang/StringBuilder.codePointBefore(I)I" returns the code point that ends just before
+// the index: that of a surrogate pair, if the index follows one, or else the char
+func stringBuilderCodePointBefore(params []interface{}) interface{} {
+	units := stringBuilderUTF16(params[0].(*object.Object))
+	index := params[1].(int64)
+	if index < 1 || index > int64(len(units)) {
+		return stringBuilderIndexError(index-1, len(units))
+	}
+	if index >= 2 && utf16.IsSurrogate(rune(units[index-1])) {
+		if r := utf16.DecodeRune(rune(units[index-2]), rune(units[index-1])); r != unicode.ReplacementChar {
+			return int64(r)
+		}
+	}
+	return int64(units[index-1])
+}
+
+// "java/lang/StringBuilder.codePointCount(II)I" returns the number of code points in the chars
+// from beginIndex up to endIndex, in which a lone surrogate counts as one
+func stringBuilderCodePointCount(params []interface{}) interface{} {
+	units := stringBuilderUTF16(params[0].(*object.Object))
+	begin, end := params[1].(int64), params[2].(int64)
+	if begin < 0 || end > int64(len(units)) || begin > end {
+		return getGErrBlk(excNames.IndexOutOfBoundsException, "")
+	}
+	count := int64(0)
+	for i := begin; i < end; i++ {
+		if i+1 < end && utf16.DecodeRune(rune(units[i]), rune(units[i+1])) != unicode.ReplacementChar {
+			i++ // a surrogate pair
+		}
+		count++
+	}
+	return count
+}
+
+// "java/lang/StringBuilder.delete(II)Ljava/lang/StringBuilder;" removes the chars from start
+// up to end, or to the end of the builder, if end is past it
+func stringBuilderDelete(params []interface{}) interface{} {
+	sb := params[0].(*object.Object)
+	units := stringBuilderUTF16(sb)
+	start, end := params[1].(int64), min(params[2].(int64), int64(len(units)))
+	if start < 0 || start > end {
+		return stringBuilderRangeError(start, end, len(units))
+	}
+	stringBuilderSetUTF16(sb, slices.Delete(units, int(start), int(end)))
+	return sb
+}
+
+// "java/lang/StringBuilder.deleteCharAt(I)Ljava/lang/StringBuilder;"
+func stringBuilderDeleteCharAt(params []interface{}) interface{} {
+	sb := params[0].(*object.Object)
+	units := stringBuilderUTF16(sb)
+	index := params[1].(int64)
+	if index < 0 || index >= int64(len(units)) {
+		return stringBuilderIndexError(index, len(units))
+	}
+	stringBuilderSetUTF16(sb, slices.Delete(units, int(index), int(index+1)))
+	return sb
+}
+
+// stringBuilderInsert inserts the chars at the offset, which can be the length of the builder,
+// and returns the builder
+func stringBuilderInsert(sb *object.Object, offset int64, chars []uint16) interface{} {
+	units := stringBuilderUTF16(sb)
+	if offset < 0 || offset > int64(len(units)) {
+		errMsg := fmt.Sprintf("offset %d, length %d", offset, len(units))
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
+	stringBuilderSetUTF16(sb, slices.Insert(units, int(offset), chars...))
+	return sb
+}
+
+// "java/lang/StringBuilder.insert(IZ)Ljava/lang/StringBuilder;"
+func stringBuilderInsertBoolean(params []interface{}) interface{} {
+	str := "false"
+	if params[2].(int64) != types.JavaBoolFalse {
+		str = "true"
+	}
+	return stringBuilderInsert(params[0].(*object.Object), params[1].(int64), utf16.Encode([]rune(str)))
+}
+
+// "java/lang/StringBuilder.insert(IC)Ljava/lang/StringBuilder;"
+func stringBuilderInsertChar(params []interface{}) interface{} {
+	return stringBuilderInsert(params[0].(*object.Object), params[1].(int64), []uint16{uint16(params[2].(int64))})
+}
+
+// "java/lang/StringBuilder.insert(ID)Ljava/lang/StringBuilder;"
+func stringBuilderInsertDouble(params []interface{}) interface{} {
+	str := javaFloatingToString(params[2].(float64), 64)
+	return stringBuilderInsert(params[0].(*object.Object), params[1].(int64), utf16.Encode([]rune(str)))
+}
+
+// "java/lang/StringBuilder.insert(IF)Ljava/lang/StringBuilder;"
+func stringBuilderInsertFloat(params []interface{}) interface{} {
+	str := javaFloatingToString(params[2].(float64), 32)
+	return stringBuilderInsert(params[0].(*object.Object), params[1].(int64), utf16.Encode([]rune(str)))
+}
+
+// "java/lang/StringBuilder.insert(II)Ljava/lang/StringBuilder;" and
+// "java/lang/StringBuilder.insert(IJ)Ljava/lang/StringBuilder;"
+func stringBuilderInsertInteger(params []interface{}) interface{} {
+	str := fmt.Sprintf("%d", params[2].(int64))
+	return stringBuilderInsert(params[0].(*object.Object), params[1].(int64), utf16.Encode([]rune(str)))
+}
+
+// "java/lang/StringBuilder.insert(I[C)Ljava/lang/StringBuilder;" and
+// "java/lang/StringBuilder.insert(I[CII)Ljava/lang/StringBuilder;", which inserts len chars of
+// the array, starting at the array index offset. params[0] = the StringBuilder, params[1] =
+// the index at which to insert, params[2] = the char array, params[3] and params[4], if
+// present, = the offset and len
+func stringBuilderInsertChars(params []interface{}) interface{} {
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "StringBuilder.insert: char array is null")
+	}
+	chars := params[2].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	if len(params) > 3 {
+		offset, length := params[3].(int64), params[4].(int64)
+		if offset < 0 || length < 0 || offset > int64(len(chars))-length {
+			return stringBuilderRangeError(offset, offset+length, len(chars))
+		}
+		chars = chars[offset : offset+length]
+	}
+	units := make([]uint16, len(chars))
+	for i, ch := range chars {
+		units[i] = uint16(ch)
+	}
+	return stringBuilderInsert(params[0].(*object.Object), params[1].(int64), units)
+}
+
+// "java/lang/StringBuilder.insert(ILjava/lang/Object;)Ljava/lang/StringBuilder;" and the
+// overloads for a String and for a CharSequence, or the chars from start up to end of it.
+// The object is converted as append() does.
+// params[0] = the frame stack, params[1] = the StringBuilder, params[2] = the index at which to
+// insert, params[3] = the object, params[4] and params[5], if present, = start and end
+func stringBuilderInsertObject(params []interface{}) interface{} {
+	str, errBlk := stringBuilderStringOf(params[0].(*list.List), params[3], "StringBuilder.insert")
+	if errBlk != nil {
+		return errBlk
+	}
+	chars := utf16.Encode([]rune(str))
+	if len(params) > 4 {
+		start, end := params[4].(int64), params[5].(int64)
+		if start < 0 || start > end || end > int64(len(chars)) {
+			errMsg := fmt.Sprintf("start %d, end %d, length %d", start, end, len(chars))
+			return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+		}
+		chars = chars[start:end]
+	}
+	return stringBuilderInsert(params[1].(*object.Object), params[2].(int64), chars)
+}
+
+// "java/lang/StringBuilder.lastIndexOf(Ljava/lang/String;)I" and
+// "java/lang/StringBuilder.lastIndexOf(Ljava/lang/String;I)I" return the UTF-16 index of the
+// last occurrence of the string in the builder that starts at or before fromIndex, if given,
+// or -1 if there is none
+func stringBuilderLastIndexOf(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "StringBuilder.lastIndexOf: string is null")
+	}
+	units := stringBuilderUTF16(params[0].(*object.Object))
+	target, _ := object.CharSequenceToGoString(params[1].(*object.Object))
+	targetUnits := utf16.Encode([]rune(target))
+	fromIndex := int64(len(units) - len(targetUnits))
+	if len(params) > 2 {
+		fromIndex = min(params[2].(int64), fromIndex)
+	}
+
+	for i := fromIndex; i >= 0; i-- {
+		if slices.Equal(units[i:i+int64(len(targetUnits))], targetUnits) {
+			return i
+		}
+	}
+	return int64(-1)
+}
+
+// "java/lang/StringBuilder.replace(IILjava/lang/String;)Ljava/lang/StringBuilder;" replaces the
+// chars from start up to end, or to the end of the builder, if end is past it, with the string
+func stringBuilderReplace(params []interface{}) interface{} {
+	sb := params[0].(*object.Object)
+	units := stringBuilderUTF16(sb)
+	start, end := params[1].(int64), min(params[2].(int64), int64(len(units)))
+	if start < 0 || start > end {
+		return stringBuilderRangeError(start, end, len(units))
+	}
+	if object.IsNull(params[3]) {
+		return getGErrBlk(excNames.NullPointerException, "StringBuilder.replace: string is null")
+	}
+	str, _ := object.CharSequenceToGoString(params[3].(*object.Object))
+	stringBuilderSetUTF16(sb, slices.Replace(units, int(start), int(end), utf16.Encode([]rune(str))...))
+	return sb
+}
+
+// "java/lang/StringBuilder.reverse()Ljava/lang/StringBuilder;" reverses the code points of the
+// builder, so that, as in the JDK, the chars of a surrogate pair stay in order
+func stringBuilderReverse(params []interface{}) interface{} {
+	sb := params[0].(*object.Object)
+	current, _ := object.CharSequenceToGoString(sb)
+	runes := []rune(current)
+	slices.Reverse(runes)
+	stringBuilderSetValue(sb, string(runes), 0)
+	return sb
+}
+
+// "java/lang/StringBuilder.setCharAt(IC)V"
+func stringBuilderSetCharAt(params []interface{}) interface{} {
+	sb := params[0].(*object.Object)
+	units := stringBuilderUTF16(sb)
+	index := params[1].(int64)
+	if index < 0 || index >= int64(len(units)) {
+		return stringBuilderIndexError(index, len(units))
+	}
+	units[index] = uint16(params[2].(int64))
+	stringBuilderSetUTF16(sb, units)
+	return nil
+}
+
+// "java/lang/StringBuilder.setLength(I)V" truncates the builder to the length, or pads it to
+// the length with '\u0000' chars
+func stringBuilderSetLength(params []interface{}) interface{} {
+	sb := params[0].(*object.Object)
+	length := params[1].(int64)
+	if length < 0 {
+		errMsg := fmt.Sprintf("String index out of range: %d", length)
+		return getGErrBlk(excNames.StringIndexOutOfBoundsException, errMsg)
+	}
+	units := stringBuilderUTF16(sb)
+	if length <= int64(len(units)) {
+		stringBuilderSetUTF16(sb, units[:length])
+	} else {
+		stringBuilderSetUTF16(sb, append(units, make([]uint16, length-int64(len(units)))...))
+	}
+	return nil
+}
+
+// "java/lang/StringBuilder.substring(I)Ljava/lang/String;",
+// "java/lang/StringBuilder.substring(II)Ljava/lang/String;", and
+// "java/lang/StringBuilder.subSequence(II)Ljava/lang/CharSequence;" return a new String of the
+// chars from start up to end, if given, or else to the end of the builder
+func stringBuilderSubstring(params []interface{}) interface{} {
+	units := stringBuilderUTF16(params[0].(*object.Object))
+	start, end := params[1].(int64), int64(len(units))
+	if len(params) > 2 {
+		end = params[2].(int64)
+	}
+	if start < 0 || start > end || end > int64(len(units)) {
+		return stringBuilderRangeError(start, end, len(units))
+	}
+	return object.StringObjectFromGoString(string(utf16.Decode(units[start:end])))
+}
@@ -0,0 +1,286 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"math"
+	"testing"
+)
+
+// the String returned by toString() doesn't change when the StringBuilder does
+func TestStringBuilderToStringIsACopy(t *testing.T) {
+	globals.InitGlobals("test")
+	sb := makeTestStringBuilder("abc")
+
+	first := stringBuilderToString([]interface{}{sb}).(*object.Object)
+	if str := object.GoStringFromStringObject(first); str != "abc" {
+		t.Fatalf("StringBuilder.toString(): expected abc, got %s", str)
+	}
+
+	stringBuilderAppendObject([]interface{}{list.New(), sb, object.StringObjectFromGoString("d")})
+	second := stringBuilderToString([]interface{}{sb}).(*object.Object)
+	if str := object.GoStringFromStringObject(second); str != "abcd" {
+		t.Errorf("StringBuilder.toString() after append: expected abcd, got %s", str)
+	}
+
+	// modifying the buffer in place, as setCharAt() does, changes neither String
+	sb.FieldTable["value"].Fvalue.([]byte)[0] = 'x'
+	if str := object.GoStringFromStringObject(first); str != "abc" {
+		t.Errorf("StringBuilder.toString(): expected the first String to still be abc, got %s", str)
+	}
+	if str := object.GoStringFromStringObject(second); str != "abcd" {
+		t.Errorf("StringBuilder.toString(): expected the second String to still be abcd, got %s", str)
+	}
+}
+
+// a StringBuilder created from a CharSequence holds its chars, with room for more
+func TestStringBuilderInitFromCharSequence(t *testing.T) {
+	globals.InitGlobals("test")
+	className := "java/lang/StringBuilder"
+
+	sources := []*object.Object{object.StringObjectFromGoString("graveyard"), makeTestStringBuilder("graveyard")}
+	for _, source := range sources {
+		sb := object.MakeEmptyObjectWithClassName(&className)
+		if ret := stringBuilderInitCharSequence([]interface{}{list.New(), sb, source}); ret != nil {
+			t.Fatalf("StringBuilder(CharSequence): unexpected error: %v", ret)
+		}
+		if count := sb.FieldTable["count"].Fvalue.(int64); count != 9 {
+			t.Errorf("StringBuilder(CharSequence): expected a length of 9, got %d", count)
+		}
+		if str, _ := object.CharSequenceToGoString(sb); str != "graveyard" {
+			t.Errorf("StringBuilder(CharSequence): expected graveyard, got %s", str)
+		}
+		if extra := cap(sb.FieldTable["value"].Fvalue.([]byte)) - 9; extra < stringBuilderExtraCapacity {
+			t.Errorf("StringBuilder(CharSequence): expected room for %d more bytes, got %d",
+				stringBuilderExtraCapacity, extra)
+		}
+	}
+
+	sb := object.MakeEmptyObjectWithClassName(&className)
+	ret := stringBuilderInitCharSequence([]interface{}{list.New(), sb, object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("StringBuilder(null): expected a NullPointerException, got %v", ret)
+	}
+}
+
+func TestStringBuilderInitCapacity(t *testing.T) {
+	globals.InitGlobals("test")
+	className := "java/lang/StringBuilder"
+
+	sb := object.MakeEmptyObjectWithClassName(&className)
+	if ret := stringBuilderInitCapacity([]interface{}{sb, int64(32)}); ret != nil {
+		t.Fatalf("StringBuilder(32): unexpected error: %v", ret)
+	}
+	if count := sb.FieldTable["count"].Fvalue.(int64); count != 0 {
+		t.Errorf("StringBuilder(32): expected a length of 0, got %d", count)
+	}
+
+	// a huge capacity isn't allocated until it's needed
+	if ret := stringBuilderInitCapacity([]interface{}{sb, int64(math.MaxInt32)}); ret != nil {
+		t.Fatalf("StringBuilder(Integer.MAX_VALUE): unexpected error: %v", ret)
+	}
+	if size := cap(sb.FieldTable["value"].Fvalue.([]byte)); size > stringBuilderMaxInitialCapacity {
+		t.Errorf("StringBuilder(Integer.MAX_VALUE): expected at most %d bytes up front, got %d",
+			stringBuilderMaxInitialCapacity, size)
+	}
+
+	ret := stringBuilderInitCapacity([]interface{}{sb, int64(-1)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NegativeArraySizeException {
+		t.Errorf("StringBuilder(-1): expected a NegativeArraySizeException, got %v", ret)
+	}
+}
+
+// a supplementary code point, such as an emoji, is appended as a surrogate pair: two chars
+func TestStringBuilderAppendCodePoint(t *testing.T) {
+	globals.InitGlobals("test")
+	sb := makeTestStringBuilder("ab")
+
+	ret := stringBuilderAppendCodePoint([]interface{}{sb, int64(0x1F600)}) // 😀
+	if ret != sb {
+		t.Fatalf("StringBuilder.appendCodePoint: expected the builder to be returned, got %v", ret)
+	}
+	if length := stringBuilderLength([]interface{}{sb}); length != int64(4) {
+		t.Errorf("StringBuilder.length(): expected 4 after appending an emoji to ab, got %v", length)
+	}
+	if high := stringBuilderCharAt([]interface{}{sb, int64(2)}); high != int64(0xD83D) {
+		t.Errorf("StringBuilder.charAt(2): expected the high surrogate 0xD83D, got 0x%X", high)
+	}
+	if low := stringBuilderCharAt([]interface{}{sb, int64(3)}); low != int64(0xDE00) {
+		t.Errorf("StringBuilder.charAt(3): expected the low surrogate 0xDE00, got 0x%X", low)
+	}
+	if str := object.GoStringFromStringObject(stringBuilderToString([]interface{}{sb}).(*object.Object)); str != "ab😀" {
+		t.Errorf("StringBuilder.toString(): expected ab😀, got %s", str)
+	}
+
+	// a code point in the BMP is one char
+	_ = stringBuilderAppendCodePoint([]interface{}{sb, int64('é')})
+	if length := stringBuilderLength([]interface{}{sb}); length != int64(5) {
+		t.Errorf("StringBuilder.length(): expected 5 after appending é, got %v", length)
+	}
+
+	ret = stringBuilderCharAt([]interface{}{sb, int64(5)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.StringIndexOutOfBoundsException {
+		t.Errorf("StringBuilder.charAt(5): expected StringIndexOutOfBoundsException, got %v", ret)
+	}
+
+	for _, invalid := range []int64{-1, 0x110000} {
+		ret = stringBuilderAppendCodePoint([]interface{}{sb, invalid})
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+			t.Errorf("StringBuilder.appendCodePoint(0x%X): expected IllegalArgumentException, got %v", invalid, ret)
+		}
+	}
+}
+
+// a StringBuilder passed where a CharSequence is expected is read directly, not via toString()
+func TestStringBuilderAsCharSequence(t *testing.T) {
+	globals.InitGlobals("test")
+	sb := makeTestStringBuilder("grave")
+	arg := makeTestStringBuilder("yard")
+
+	stringBuilderAppendObject([]interface{}{list.New(), sb, arg})
+	if str, _ := object.CharSequenceToGoString(sb); str != "graveyard" {
+		t.Fatalf("StringBuilder.append(CharSequence): expected graveyard, got %s", str)
+	}
+
+	if ret := stringBuilderIndexOf([]interface{}{sb, arg}); ret != int64(5) {
+		t.Errorf("StringBuilder.indexOf(yard): expected 5, got %v", ret)
+	}
+	if ret := stringBuilderIndexOf([]interface{}{sb, object.StringObjectFromGoString("a"), int64(3)}); ret != int64(6) {
+		t.Errorf("StringBuilder.indexOf(a, 3): expected 6, got %v", ret)
+	}
+	if ret := stringBuilderIndexOf([]interface{}{sb, object.StringObjectFromGoString("a"), int64(-4)}); ret != int64(2) {
+		t.Errorf("StringBuilder.indexOf(a, -4): expected 2, got %v", ret)
+	}
+	if ret := stringBuilderIndexOf([]interface{}{sb, object.StringObjectFromGoString("x")}); ret != int64(-1) {
+		t.Errorf("StringBuilder.indexOf(x): expected -1, got %v", ret)
+	}
+}
+
+// the methods that take an index count UTF-16 chars, as length() and charAt() do, not the
+// bytes of the UTF-8 buffer: here, ñ is two bytes but one char, and 😀 four bytes but two chars
+func TestStringBuilderIndexesUTF16(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := list.New()
+	contents := func(sb *object.Object) string {
+		str, _ := object.CharSequenceToGoString(sb)
+		return str
+	}
+
+	for _, test := range []struct {
+		name     string
+		edit     func(sb *object.Object) interface{}
+		expected string
+	}{
+		{"deleteCharAt(2)", func(sb *object.Object) interface{} {
+			return stringBuilderDeleteCharAt([]interface{}{sb, int64(2)})
+		}, "añ😀c"},
+		{"delete(1, 5)", func(sb *object.Object) interface{} {
+			return stringBuilderDelete([]interface{}{sb, int64(1), int64(5)})
+		}, "ac"},
+		{"delete(3, 99)", func(sb *object.Object) interface{} {
+			return stringBuilderDelete([]interface{}{sb, int64(3), int64(99)})
+		}, "añb"},
+		{"insert(3, String)", func(sb *object.Object) interface{} {
+			return stringBuilderInsertObject([]interface{}{fs, sb, int64(3), object.StringObjectFromGoString("é")})
+		}, "añbé😀c"},
+		{"insert(6, null)", func(sb *object.Object) interface{} {
+			return stringBuilderInsertObject([]interface{}{fs, sb, int64(6), object.Null})
+		}, "añb😀cnull"},
+		{"insert(0, CharSequence, 2, 4)", func(sb *object.Object) interface{} {
+			return stringBuilderInsertObject([]interface{}{fs, sb, int64(0), makeTestStringBuilder("xñ😀"), int64(2), int64(4)})
+		}, "😀añb😀c"},
+		{"insert(1, char)", func(sb *object.Object) interface{} {
+			return stringBuilderInsertChar([]interface{}{sb, int64(1), int64('ü')})
+		}, "aüñb😀c"},
+		{"insert(5, int)", func(sb *object.Object) interface{} {
+			return stringBuilderInsertInteger([]interface{}{sb, int64(5), int64(-42)})
+		}, "añb😀-42c"},
+		{"insert(2, boolean)", func(sb *object.Object) interface{} {
+			return stringBuilderInsertBoolean([]interface{}{sb, int64(2), types.JavaBoolTrue})
+		}, "añtrueb😀c"},
+		{"insert(2, double)", func(sb *object.Object) interface{} {
+			return stringBuilderInsertDouble([]interface{}{sb, int64(2), 2.5})
+		}, "añ2.5b😀c"},
+		{"replace(1, 3, String)", func(sb *object.Object) interface{} {
+			return stringBuilderReplace([]interface{}{sb, int64(1), int64(3), object.StringObjectFromGoString("ö")})
+		}, "aö😀c"},
+		{"reverse()", func(sb *object.Object) interface{} {
+			return stringBuilderReverse([]interface{}{sb})
+		}, "c😀bña"},
+		{"setCharAt(1, char)", func(sb *object.Object) interface{} {
+			return stringBuilderSetCharAt([]interface{}{sb, int64(1), int64('n')})
+		}, "anb😀c"},
+		{"setLength(3)", func(sb *object.Object) interface{} {
+			return stringBuilderSetLength([]interface{}{sb, int64(3)})
+		}, "añb"},
+		{"setLength(8)", func(sb *object.Object) interface{} {
+			return stringBuilderSetLength([]interface{}{sb, int64(8)})
+		}, "añb😀c\x00\x00"},
+	} {
+		sb := makeTestStringBuilder("añb😀c")
+		ret := test.edit(sb)
+		if _, ok := ret.(*GErrBlk); ok {
+			t.Errorf("StringBuilder.%s: unexpected error: %v", test.name, ret)
+			continue
+		}
+		if str := contents(sb); str != test.expected {
+			t.Errorf("StringBuilder.%s: expected %q, got %q", test.name, test.expected, str)
+		}
+		if count := sb.FieldTable["count"].Fvalue.(int64); count != int64(len(test.expected)) {
+			t.Errorf("StringBuilder.%s: expected a count of %d bytes, got %d", test.name, len(test.expected), count)
+		}
+	}
+
+	sb := makeTestStringBuilder("añb😀cñ")
+	for _, test := range []struct {
+		name     string
+		ret      interface{}
+		expected interface{}
+	}{
+		{"substring(2)", stringBuilderSubstring([]interface{}{sb, int64(2)}), "b😀cñ"},
+		{"substring(3, 5)", stringBuilderSubstring([]interface{}{sb, int64(3), int64(5)}), "😀"},
+		{"lastIndexOf(ñ)", stringBuilderLastIndexOf([]interface{}{sb, object.StringObjectFromGoString("ñ")}), int64(6)},
+		{"lastIndexOf(ñ, 5)", stringBuilderLastIndexOf([]interface{}{sb, object.StringObjectFromGoString("ñ"), int64(5)}), int64(1)},
+		{"codePointAt(3)", stringBuilderCodePointAt([]interface{}{sb, int64(3)}), int64(0x1F600)},
+		{"codePointAt(4)", stringBuilderCodePointAt([]interface{}{sb, int64(4)}), int64(0xDE00)},
+		{"codePointBefore(5)", stringBuilderCodePointBefore([]interface{}{sb, int64(5)}), int64(0x1F600)},
+		{"codePointCount(0, 7)", stringBuilderCodePointCount([]interface{}{sb, int64(0), int64(7)}), int64(6)},
+		{"codePointCount(0, 4)", stringBuilderCodePointCount([]interface{}{sb, int64(0), int64(4)}), int64(4)},
+	} {
+		if str, ok := test.ret.(*object.Object); ok {
+			test.ret = object.GoStringFromStringObject(str)
+		}
+		if test.ret != test.expected {
+			t.Errorf("StringBuilder.%s: expected %v, got %v", test.name, test.expected, test.ret)
+		}
+	}
+
+	// indexes past the last char, counted in chars, are errors, though they're within the bytes
+	for _, test := range []struct {
+		name string
+		ret  interface{}
+	}{
+		{"deleteCharAt(7)", stringBuilderDeleteCharAt([]interface{}{sb, int64(7)})},
+		{"setCharAt(7, char)", stringBuilderSetCharAt([]interface{}{sb, int64(7), int64('x')})},
+		{"insert(8, char)", stringBuilderInsertChar([]interface{}{sb, int64(8), int64('x')})},
+		{"substring(2, 8)", stringBuilderSubstring([]interface{}{sb, int64(2), int64(8)})},
+		{"delete(4, 2)", stringBuilderDelete([]interface{}{sb, int64(4), int64(2)})},
+		{"setLength(-1)", stringBuilderSetLength([]interface{}{sb, int64(-1)})},
+	} {
+		if errBlk, ok := test.ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.StringIndexOutOfBoundsException {
+			t.Errorf("StringBuilder.%s: expected StringIndexOutOfBoundsException, got %v", test.name, test.ret)
+		}
+	}
+	if str := contents(sb); str != "añb😀cñ" {
+		t.Errorf("StringBuilder: expected the failed edits to leave the builder unchanged, got %q", str)
+	}
+}
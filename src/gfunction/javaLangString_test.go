@@ -7,13 +7,19 @@
 package gfunction
 
 import (
+	"container/list"
+	"fmt"
 	"jacobin/classloader"
 	"jacobin/excNames"
+	"jacobin/frames"
 	"jacobin/globals"
 	"jacobin/object"
 	"jacobin/types"
+	"math"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf16"
 )
 
 func TestStringClinit(t *testing.T) {
@@ -125,11 +131,105 @@ func TestStringLength_2(t *testing.T) {
 	}
 }
 
+// length() counts UTF-16 chars, not the bytes of the string's value: é is one byte in LATIN1
+// but two in UTF-8, and the non-LATIN1 α and € are one char each, though two and three bytes.
+func TestStringLengthOfNonLatin1(t *testing.T) {
+	globals.InitGlobals("test")
+	str := object.StringObjectFromGoString("café α€")
+	if coder := str.FieldTable["coder"].Fvalue; coder != byte(0) {
+		t.Errorf("TestStringLengthOfNonLatin1: expected the coder to be LATIN1 (0), got: %v", coder)
+	}
+
+	byteCount := len(object.ByteArrayFromStringObject(str))
+	length := stringLength([]interface{}{str}).(int64)
+	if length != 7 || int64(byteCount) == length {
+		t.Errorf("TestStringLengthOfNonLatin1: expected a length of 7 chars, not the %d bytes, got: %d",
+			byteCount, length)
+	}
+	if length != int64(len(stringUTF16(str))) {
+		t.Errorf("TestStringLengthOfNonLatin1: expected length() to match the UTF-16 chars, got: %d", length)
+	}
+	if stringIsEmpty([]interface{}{str}) != types.JavaBoolFalse {
+		t.Errorf("TestStringLengthOfNonLatin1: expected isEmpty() to be false")
+	}
+}
+
+// A supplementary character, such as an emoji, is two UTF-16 chars but one code point
+func TestStringCodePoints(t *testing.T) {
+	globals.InitGlobals("test")
+	str := object.StringObjectFromGoString("a\U0001F600b") // a, grinning face, b: 4 chars
+	length := int64(len(stringUTF16(str)))
+
+	count := stringCodePointCount([]interface{}{str, int64(0), length})
+	if count != int64(3) || count.(int64) >= stringLength([]interface{}{str}).(int64) {
+		t.Errorf("TestStringCodePoints: expected a codePointCount of 3, less than length(), got: %v", count)
+	}
+	if count = stringCodePointCount([]interface{}{str, int64(2), length}); count != int64(2) {
+		t.Errorf("TestStringCodePoints: expected 2 code points from the low surrogate on, got: %v", count)
+	}
+
+	for _, test := range []struct{ index, offset, expected int64 }{
+		{0, 2, 3}, {0, 3, 4}, {4, -2, 1}, {3, -1, 1}, {1, 0, 1},
+	} {
+		ret := stringOffsetByCodePoints([]interface{}{str, test.index, test.offset})
+		if ret != test.expected {
+			t.Errorf("TestStringCodePoints: offsetByCodePoints(%d, %d): expected %d, got: %v",
+				test.index, test.offset, test.expected, ret)
+		}
+	}
+
+	for _, ret := range []interface{}{
+		stringCodePointCount([]interface{}{str, int64(0), length + 1}),
+		stringOffsetByCodePoints([]interface{}{str, int64(0), int64(4)}),
+		stringOffsetByCodePoints([]interface{}{str, int64(1), int64(-2)}),
+	} {
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.StringIndexOutOfBoundsException {
+			t.Errorf("TestStringCodePoints: expected a StringIndexOutOfBoundsException, got: %v", ret)
+		}
+	}
+}
+
+// A string built from code points holds a supplementary one as a surrogate pair
+func TestNewStringFromCodePoints(t *testing.T) {
+	globals.InitGlobals("test")
+	codePoints := populator("[I", types.IntArray, []int64{'x', 'a', 0x1F600, 'b', 'y'})
+
+	str := object.StringObjectFromGoString("")
+	if ret := newStringFromCodePoints([]interface{}{str, codePoints, int64(1), int64(3)}); ret != nil {
+		t.Fatalf("TestNewStringFromCodePoints: unexpected error: %v", ret)
+	}
+	if length := stringLength([]interface{}{str}); length != int64(4) {
+		t.Errorf("TestNewStringFromCodePoints: expected a length of 4, got: %v", length)
+	}
+	chars := toCharArray([]interface{}{str}).(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	expected := []int64{'a', 0xD83D, 0xDE00, 'b'}
+	if len(chars) != len(expected) {
+		t.Fatalf("TestNewStringFromCodePoints: expected chars %x, got: %x", expected, chars)
+	}
+	for i := range expected {
+		if chars[i] != expected[i] {
+			t.Errorf("TestNewStringFromCodePoints: expected chars %x, got: %x", expected, chars)
+			break
+		}
+	}
+
+	invalid := populator("[I", types.IntArray, []int64{'a', 0x110000})
+	ret := newStringFromCodePoints([]interface{}{str, invalid, int64(0), int64(2)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("TestNewStringFromCodePoints: expected an IllegalArgumentException, got: %v", ret)
+	}
+
+	ret = newStringFromCodePoints([]interface{}{str, codePoints, int64(3), int64(3)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.StringIndexOutOfBoundsException {
+		t.Errorf("TestNewStringFromCodePoints: expected a StringIndexOutOfBoundsException, got: %v", ret)
+	}
+}
+
 func TestSprintf_1(t *testing.T) {
 	globals.InitGlobals("test")
 	aString := "Mary had a %s little lamb"
 	aObj := object.StringObjectFromGoString(aString)
-	params := []interface{}{aObj}
+	params := []interface{}{frames.CreateFrameStack(), aObj}
 	resultObj := (sprintf(params)).(*object.Object)
 	str := object.GoStringFromStringObject(resultObj)
 	if str != aString {
@@ -155,7 +255,7 @@ func TestSprintf_2(t *testing.T) {
 	lsObj.FieldTable["value"] = object.Field{Ftype: classStr, Fvalue: bArray}
 	lsObj.DumpObject("TestSprintf_2 lsObj", 0)
 
-	params := []interface{}{aObj, lsObj}
+	params := []interface{}{frames.CreateFrameStack(), aObj, lsObj}
 	t.Logf("#params = %d\n", len(params))
 	result := sprintf(params)
 
@@ -204,7 +304,7 @@ func TestSprintf_3(t *testing.T) {
 	lsObj.FieldTable["value"] = object.Field{Ftype: classStr, Fvalue: bArray}
 	lsObj.DumpObject("TestSprintf_2 lsObj", 0)
 
-	params := []interface{}{aObj, lsObj}
+	params := []interface{}{frames.CreateFrameStack(), aObj, lsObj}
 	t.Logf("#params = %d\n", len(params))
 	result := sprintf(params)
 
@@ -224,3 +324,908 @@ func TestSprintf_3(t *testing.T) {
 		t.Errorf("TestSprintf_2: result type %T makes no sense", result)
 	}
 }
+
+func TestStringMatchesWholeString(t *testing.T) {
+	globals.InitGlobals("test")
+	params := []interface{}{object.StringObjectFromGoString("12345"), object.StringObjectFromGoString(`\d+`)}
+	result := stringMatches(params).(int64)
+	if result != 1 {
+		t.Errorf("TestStringMatchesWholeString: expected: 1, observed: %d", result)
+	}
+}
+
+func TestStringMatchesPartialMatchIsFalse(t *testing.T) {
+	globals.InitGlobals("test")
+	params := []interface{}{object.StringObjectFromGoString("12345abc"), object.StringObjectFromGoString(`\d+`)}
+	result := stringMatches(params).(int64)
+	if result != 0 {
+		t.Errorf("TestStringMatchesPartialMatchIsFalse: expected: 0, observed: %d", result)
+	}
+
+	// alternation must also cover the whole string
+	params = []interface{}{object.StringObjectFromGoString("catfish"), object.StringObjectFromGoString("cat|dog")}
+	result = stringMatches(params).(int64)
+	if result != 0 {
+		t.Errorf("TestStringMatchesPartialMatchIsFalse (alternation): expected: 0, observed: %d", result)
+	}
+}
+
+func TestStringMatchesPosixClass(t *testing.T) {
+	globals.InitGlobals("test")
+	params := []interface{}{object.StringObjectFromGoString("Jacobin"), object.StringObjectFromGoString(`\p{Upper}\p{Lower}+`)}
+	result := stringMatches(params).(int64)
+	if result != 1 {
+		t.Errorf("TestStringMatchesPosixClass: expected: 1, observed: %d", result)
+	}
+}
+
+func TestStringMatchesInvalidRegex(t *testing.T) {
+	globals.InitGlobals("test")
+	params := []interface{}{object.StringObjectFromGoString("abc"), object.StringObjectFromGoString("a(b")}
+	result := stringMatches(params)
+	errBlk, ok := result.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.PatternSyntaxException {
+		t.Errorf("TestStringMatchesInvalidRegex: expected PatternSyntaxException, observed: %v", result)
+	}
+}
+
+func TestStringConcat(t *testing.T) {
+	globals.InitGlobals("test")
+	aObj := object.StringObjectFromGoString("Jaco")
+	bObj := object.StringObjectFromGoString("bin")
+	result := stringConcat([]interface{}{aObj, bObj}).(*object.Object)
+	if result == aObj || result == bObj {
+		t.Errorf("TestStringConcat: expected a new String object")
+	}
+	if object.GoStringFromStringObject(result) != "Jacobin" {
+		t.Errorf("TestStringConcat: expected: Jacobin, observed: %s", object.GoStringFromStringObject(result))
+	}
+	if object.GoStringFromStringObject(aObj) != "Jaco" {
+		t.Errorf("TestStringConcat: original string was modified to: %s", object.GoStringFromStringObject(aObj))
+	}
+}
+
+func TestStringConcatNull(t *testing.T) {
+	globals.InitGlobals("test")
+	result := stringConcat([]interface{}{object.StringObjectFromGoString("Jaco"), object.Null})
+	errBlk, ok := result.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("TestStringConcatNull: expected NullPointerException, observed: %v", result)
+	}
+}
+
+func TestStringContains(t *testing.T) {
+	globals.InitGlobals("test")
+	str := object.StringObjectFromGoString("It was a graveyard smash!")
+
+	result := stringContains([]interface{}{str, object.StringObjectFromGoString("graveyard")}).(int64)
+	if result != 1 {
+		t.Errorf("TestStringContains (present): expected: 1, observed: %d", result)
+	}
+
+	result = stringContains([]interface{}{str, object.StringObjectFromGoString("monster")}).(int64)
+	if result != 0 {
+		t.Errorf("TestStringContains (absent): expected: 0, observed: %d", result)
+	}
+
+	// the argument is a literal, not a regex
+	result = stringContains([]interface{}{str, object.StringObjectFromGoString("g.*d")}).(int64)
+	if result != 0 {
+		t.Errorf("TestStringContains (regex chars): expected: 0, observed: %d", result)
+	}
+}
+
+// makeTestStringBuilder returns a StringBuilder holding str, with unused capacity after it
+func makeTestStringBuilder(str string) *object.Object {
+	className := "java/lang/StringBuilder"
+	sb := object.MakeEmptyObjectWithClassName(&className)
+	sb.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: append([]byte(str), 0, 0, 0, 0)}
+	sb.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(len(str))}
+	return sb
+}
+
+func TestStringContainsStringBuilder(t *testing.T) {
+	globals.InitGlobals("test")
+	str := object.StringObjectFromGoString("It was a graveyard smash!")
+
+	result := stringContains([]interface{}{str, makeTestStringBuilder("smash!")})
+	if result != int64(1) {
+		t.Errorf("TestStringContainsStringBuilder (present): expected: 1, observed: %v", result)
+	}
+
+	result = stringContains([]interface{}{str, makeTestStringBuilder("monster")})
+	if result != int64(0) {
+		t.Errorf("TestStringContainsStringBuilder (absent): expected: 0, observed: %v", result)
+	}
+
+	notCharSeq := object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(42))
+	result = stringContains([]interface{}{str, notCharSeq})
+	if errBlk, ok := result.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("TestStringContainsStringBuilder (Integer): expected IllegalArgumentException, observed: %v", result)
+	}
+}
+
+func TestSprintfStringBuilderArg(t *testing.T) {
+	globals.InitGlobals("test")
+	checkSprintf(t, "Mary had a %s lamb", "Mary had a little lamb", makeTestStringBuilder("little"))
+}
+
+// makeTestFormatArgs returns the Object[] of arguments for a format string
+func makeTestFormatArgs(args ...*object.Object) *object.Object {
+	classStr := "[Ljava/lang/Object"
+	argsObj := object.MakeEmptyObjectWithClassName(&classStr)
+	argsObj.FieldTable["value"] = object.Field{Ftype: classStr, Fvalue: args}
+	return argsObj
+}
+
+func TestSprintfLocaleGrouping(t *testing.T) {
+	globals.InitGlobals("test")
+	localeClass := "java/util/Locale"
+	localeUS := object.MakeEmptyObjectWithClassName(&localeClass)
+
+	tests := []struct {
+		format   string
+		arg      *object.Object
+		expected string
+	}{
+		{"%,d", object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(1000)), "1,000"},
+		{"%,d", object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(999)), "999"},
+		{"[%,d]", object.MakePrimitiveObject("java/lang/Long", types.Long, int64(-1234567)), "[-1,234,567]"},
+		{"%,10d|", object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(1000)), "     1,000|"},
+		{"%-,8d|", object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(1000)), "1,000   |"},
+		{"%,.2f", object.MakePrimitiveObject("java/lang/Double", types.Double, 1234567.891), "1,234,567.89"},
+		{"%,d", object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(1234567)), "1,234,567"},
+		{"%,d", object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(-999999)), "-999,999"},
+		{"%,d", object.MakePrimitiveObject("java/lang/Long", types.Long, int64(math.MinInt64)), "-9,223,372,036,854,775,808"},
+		{"%,(d", object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(-1234567)), "(1,234,567)"},
+		{"%+,d", object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(1234)), "+1,234"},
+		{"%,010d", object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(-1234)), "-00001,234"},
+		{"%,f", object.MakePrimitiveObject("java/lang/Double", types.Double, -1234.5), "-1,234.500000"},
+	}
+
+	for _, test := range tests {
+		checkSprintfLocale(t, localeUS, test.format, test.expected, test.arg)
+	}
+
+	// a spec that doesn't use an argument, such as %%, is skipped over when matching specs to arguments
+	checkSprintfLocale(t, localeUS, "%d%% of %,d", "50% of 20,000",
+		object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(50)),
+		object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(20000)))
+}
+
+// checkSprintfLocale formats the arguments with String.format(locale, format, args) and
+// checks that the result is the expected string
+func checkSprintfLocale(t *testing.T, locale *object.Object, format, expected string, args ...*object.Object) {
+	t.Helper()
+	result := sprintfLocale([]interface{}{frames.CreateFrameStack(), locale,
+		object.StringObjectFromGoString(format), makeTestFormatArgs(args...)})
+	checkFormattedString(t, fmt.Sprintf("String.format(Locale.US, %q)", format), result, expected)
+}
+
+// checkSprintf formats the arguments with String.format(format, args) and checks that the
+// result is the expected string
+func checkSprintf(t *testing.T, format, expected string, args ...*object.Object) {
+	t.Helper()
+	result := sprintf([]interface{}{frames.CreateFrameStack(), object.StringObjectFromGoString(format),
+		makeTestFormatArgs(args...)})
+	checkFormattedString(t, fmt.Sprintf("String.format(%q)", format), result, expected)
+}
+
+func checkFormattedString(t *testing.T, call string, result interface{}, expected string) {
+	t.Helper()
+	obj, ok := result.(*object.Object)
+	if !ok {
+		t.Errorf("%s: unexpected result: %v", call, result)
+		return
+	}
+	if str := object.GoStringFromStringObject(obj); str != expected {
+		t.Errorf("%s: expected %q, observed %q", call, expected, str)
+	}
+}
+
+// sprintfTest is a format with one argument, and either the string it formats to or, if
+// wantExc isn't 0, the exception it throws and the exception's message
+type sprintfTest struct {
+	format  string
+	arg     *object.Object
+	want    string
+	wantExc int
+}
+
+func checkSprintfTests(t *testing.T, tests []sprintfTest) {
+	t.Helper()
+	for _, test := range tests {
+		if test.wantExc == 0 {
+			checkSprintf(t, test.format, test.want, test.arg)
+			continue
+		}
+		result := sprintf([]interface{}{frames.CreateFrameStack(), object.StringObjectFromGoString(test.format),
+			makeTestFormatArgs(test.arg)})
+		errBlk, ok := result.(*GErrBlk)
+		if !ok || errBlk.ExceptionType != test.wantExc || errBlk.ErrMsg != test.want {
+			t.Errorf("String.format(%q): expected %s %q, got %v", test.format,
+				excNames.JVMexceptionNames[test.wantExc], test.want, result)
+		}
+	}
+}
+
+// the expected strings are the output of the JDK's String.format()
+func TestSprintfScientific(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		format   string
+		value    float64
+		expected string
+	}{
+		{"%e", 12345.678, "1.234568e+04"},
+		{"%E", 12345.678, "1.234568E+04"},
+		{"%.2e", 0.000123, "1.23e-04"},
+		{"%e", 1e100, "1.000000e+100"},
+		{"%.0e", 5.0, "5e+00"},
+		{"%#.0e", 5.0, "5.e+00"},
+		{"%12.3e|", 12345.678, "   1.235e+04|"},
+		{"%-12.3e|", 12345.678, "1.235e+04   |"},
+		{"%012.3e", -12345.678, "-001.235e+04"},
+		{"%+e", 12345.678, "+1.234568e+04"},
+		{"%(e", -1.0, "(1.000000e+00)"},
+		{"%e", math.Inf(1), "Infinity"},
+		{"%e", math.NaN(), "NaN"},
+		{"%g", 12345.678, "12345.7"},
+		{"%g", 0.0001, "0.000100000"},
+		{"%g", 0.00001, "1.00000e-05"},
+		{"%G", 1.0e10, "1.00000E+10"},
+		{"%.3g", 1234567.0, "1.23e+06"},
+		{"%g", 0.0, "0.00000"},
+		{"%,g", 123456.0, "123,456"},
+		{"%a", 1.0, "0x1.0p0"},
+		{"%a", 12345.678, "0x1.81cd6c8b43958p13"},
+		{"%a", -0.5, "-0x1.0p-1"},
+		{"%A", 255.0, "0X1.FEP7"},
+		{"%.1a", 1.0, "0x1.0p0"},
+		{"%a", math.SmallestNonzeroFloat64, "0x0.0000000000001p-1022"},
+		{"%a", 0.0, "0x0.0p0"},
+	}
+
+	for _, test := range tests {
+		checkSprintf(t, test.format, test.expected, object.MakePrimitiveObject("java/lang/Double", types.Double, test.value))
+	}
+}
+
+func TestSprintfArgumentIndices(t *testing.T) {
+	globals.InitGlobals("test")
+	x := object.StringObjectFromGoString("x")
+	y := object.StringObjectFromGoString("y")
+	fortyTwo := object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(42))
+
+	tests := []struct {
+		format   string
+		args     []*object.Object
+		expected string
+	}{
+		{"%1$s=%1$s", []*object.Object{x}, "x=x"},
+		{"100%%", []*object.Object{}, "100%"},
+		{"%d%%", []*object.Object{fortyTwo}, "42%"},
+		{"%2$s %1$s", []*object.Object{x, y}, "y x"},
+		{"%s %<s %s", []*object.Object{x, y}, "x x y"},
+		{"%2$s %s %s", []*object.Object{x, y}, "y x y"}, // an explicit index doesn't affect the others
+		{"%1$5d|%<-5d|%<,d", []*object.Object{fortyTwo}, "   42|42   |42"},
+		{"a%nb", []*object.Object{}, "a\nb"},
+		{"%s", []*object.Object{x, y}, "x"}, // as in Java, extra arguments are ignored
+	}
+
+	for _, test := range tests {
+		checkSprintf(t, test.format, test.expected, test.args...)
+	}
+
+	for _, format := range []string{"%s %s", "%2$s", "%<s"} {
+		result := sprintf([]interface{}{frames.CreateFrameStack(), object.StringObjectFromGoString(format), makeTestFormatArgs(x)})
+		if errBlk, ok := result.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.MissingFormatArgumentException {
+			t.Errorf("String.format(%q, \"x\"): expected a MissingFormatArgumentException, got %v", format, result)
+		}
+	}
+}
+
+func TestStringEqualsIgnoreCase(t *testing.T) {
+	globals.InitGlobals("test")
+
+	upper := object.StringObjectFromGoString("HELLO")
+	lower := object.StringObjectFromGoString("hello")
+	if stringEqualsIgnoreCase([]interface{}{upper, lower}) != int64(1) {
+		t.Errorf("Expected \"HELLO\".equalsIgnoreCase(\"hello\") to be true")
+	}
+
+	other := object.StringObjectFromGoString("hellO!")
+	if stringEqualsIgnoreCase([]interface{}{upper, other}) != int64(0) {
+		t.Errorf("Expected \"HELLO\".equalsIgnoreCase(\"hellO!\") to be false")
+	}
+
+	if stringEqualsIgnoreCase([]interface{}{upper, object.Null}) != int64(0) {
+		t.Errorf("Expected \"HELLO\".equalsIgnoreCase(null) to be false")
+	}
+}
+
+func TestStringRegionMatches(t *testing.T) {
+	globals.InitGlobals("test")
+
+	str := object.StringObjectFromGoString("Hello, World")
+	other := object.StringObjectFromGoString("the world is round")
+
+	// "World" at 7 vs "world" at 4: differs in case only
+	ret := stringRegionMatches([]interface{}{str, int64(7), other, int64(4), int64(5)})
+	if ret != int64(0) {
+		t.Errorf("Expected case-sensitive regionMatches to be false, got %v", ret)
+	}
+	ret = stringRegionMatchesIgnoreCase([]interface{}{str, int64(1), int64(7), other, int64(4), int64(5)})
+	if ret != int64(1) {
+		t.Errorf("Expected case-insensitive regionMatches to be true, got %v", ret)
+	}
+
+	// mismatched regions
+	ret = stringRegionMatchesIgnoreCase([]interface{}{str, int64(1), int64(0), other, int64(4), int64(5)})
+	if ret != int64(0) {
+		t.Errorf("Expected regionMatches of \"Hello\" and \"world\" to be false, got %v", ret)
+	}
+
+	// regions that reach past the end of a string, or start before it, never match
+	ret = stringRegionMatches([]interface{}{str, int64(10), other, int64(0), int64(5)})
+	if ret != int64(0) {
+		t.Errorf("Expected regionMatches past end of string to be false, got %v", ret)
+	}
+	ret = stringRegionMatches([]interface{}{str, int64(-1), other, int64(0), int64(1)})
+	if ret != int64(0) {
+		t.Errorf("Expected regionMatches with negative offset to be false, got %v", ret)
+	}
+
+	// a zero-length region always matches
+	ret = stringRegionMatches([]interface{}{str, int64(3), other, int64(9), int64(0)})
+	if ret != int64(1) {
+		t.Errorf("Expected zero-length regionMatches to be true, got %v", ret)
+	}
+
+	ret = stringRegionMatches([]interface{}{str, int64(0), object.Null, int64(0), int64(1)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException for null other string, got %v", ret)
+	}
+
+	// the offsets and length count UTF-16 chars: in "a😀b", 😀 is two chars, so "b" is at 3
+	emoji := object.StringObjectFromGoString("a😀b")
+	ret = stringRegionMatches([]interface{}{emoji, int64(3), object.StringObjectFromGoString("xb"), int64(1), int64(1)})
+	if ret != int64(1) {
+		t.Errorf("Expected regionMatches of \"b\" at UTF-16 index 3 to be true, got %v", ret)
+	}
+	ret = stringRegionMatchesIgnoreCase([]interface{}{emoji, int64(1), int64(1), object.StringObjectFromGoString("😀B"), int64(0), int64(3)})
+	if ret != int64(1) {
+		t.Errorf("Expected case-insensitive regionMatches of \"😀b\" and \"😀B\" to be true, got %v", ret)
+	}
+	ret = stringRegionMatches([]interface{}{emoji, int64(2), emoji, int64(2), int64(3)})
+	if ret != int64(0) {
+		t.Errorf("Expected regionMatches past the last UTF-16 char to be false, got %v", ret)
+	}
+}
+
+func TestStringLines(t *testing.T) {
+	globals.InitGlobals("test")
+
+	stream := stringLines([]interface{}{object.StringObjectFromGoString("a\nb\nc")})
+	iterator := streamIterator([]interface{}{stream}).(*object.Object)
+
+	var lines []string
+	for streamIteratorHasNext([]interface{}{iterator}) == types.JavaBoolTrue {
+		line := streamIteratorNext([]interface{}{iterator}).(*object.Object)
+		lines = append(lines, object.GoStringFromStringObject(line))
+	}
+	if strings.Join(lines, ",") != "a,b,c" {
+		t.Errorf("Expected the lines a, b, and c, got: %q", lines)
+	}
+
+	// an exhausted iterator throws NoSuchElementException
+	errBlk, ok := streamIteratorNext([]interface{}{iterator}).(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.NoSuchElementException {
+		t.Errorf("Expected NoSuchElementException after the last line, got: %v", errBlk)
+	}
+
+	// each of the line terminators ends a line, and a terminator at the end adds no line
+	for str, want := range map[string]int64{"": 0, "x": 1, "x\n": 1, "x\r\ny\rz\n\n": 4} {
+		stream = stringLines([]interface{}{object.StringObjectFromGoString(str)})
+		if count := streamCount([]interface{}{stream}); count != want {
+			t.Errorf("Expected %q to have %d lines, got: %d", str, want, count)
+		}
+	}
+}
+
+func makeTestCharArray(str string) *object.Object {
+	var chars []int64
+	for _, unit := range utf16.Encode([]rune(str)) {
+		chars = append(chars, int64(unit))
+	}
+	return populator("[C", types.IntArray, chars)
+}
+
+func TestStringFromCharArraySlice(t *testing.T) {
+	globals.InitGlobals("test")
+	chars := makeTestCharArray("héllo, wörld")
+
+	// the constructors update the String object passed to them
+	str := object.NewStringObject()
+	if ret := newStringFromCharSubarray([]interface{}{str, chars, int64(7), int64(5)}); ret != nil {
+		t.Fatalf("String(char[], 7, 5): unexpected error: %v", ret)
+	}
+	if got := object.GoStringFromStringObject(str); got != "wörld" {
+		t.Errorf("String(char[], 7, 5): expected \"wörld\", got %q", got)
+	}
+
+	str = object.NewStringObject()
+	_ = newStringFromChars([]interface{}{str, chars})
+	if got := object.GoStringFromStringObject(str); got != "héllo, wörld" {
+		t.Errorf("String(char[]): expected \"héllo, wörld\", got %q", got)
+	}
+
+	for _, test := range []struct {
+		offset, count int64
+		want          string
+	}{{0, 5, "héllo"}, {12, 0, ""}, {0, 12, "héllo, wörld"}} {
+		ret := valueOfCharSubarray([]interface{}{chars, test.offset, test.count})
+		if got := object.GoStringFromStringObject(ret.(*object.Object)); got != test.want {
+			t.Errorf("String.valueOf(char[], %d, %d): expected %q, got %q", test.offset, test.count, test.want, got)
+		}
+	}
+
+	// a surrogate pair makes a single character
+	emoji := makeTestCharArray("a😀")
+	if got := object.GoStringFromStringObject(valueOfCharArray([]interface{}{emoji}).(*object.Object)); got != "a😀" {
+		t.Errorf("String.copyValueOf(char[]): expected \"a😀\", got %q", got)
+	}
+}
+
+func TestStringFromCharArrayOutOfRange(t *testing.T) {
+	globals.InitGlobals("test")
+	chars := makeTestCharArray("hello")
+
+	for _, test := range [][2]int64{{-1, 2}, {2, -1}, {4, 2}, {6, 0}} {
+		ret := valueOfCharSubarray([]interface{}{chars, test[0], test[1]})
+		errBlk, ok := ret.(*GErrBlk)
+		if !ok || errBlk.ExceptionType != excNames.StringIndexOutOfBoundsException {
+			t.Errorf("String.valueOf(char[], %d, %d): expected StringIndexOutOfBoundsException, got %v",
+				test[0], test[1], ret)
+		}
+		ret = newStringFromCharSubarray([]interface{}{object.NewStringObject(), chars, test[0], test[1]})
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.StringIndexOutOfBoundsException {
+			t.Errorf("String(char[], %d, %d): expected StringIndexOutOfBoundsException, got %v",
+				test[0], test[1], ret)
+		}
+	}
+
+	ret := valueOfCharArray([]interface{}{object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("String.valueOf(null char[]): expected NullPointerException, got %v", ret)
+	}
+}
+
+func TestStringReplaceCharSequence(t *testing.T) {
+	globals.InitGlobals("test")
+
+	for _, tc := range []struct {
+		str, target, replacement, want string
+	}{
+		{"a-b-c", "-", "+=", "a+=b+=c"},
+		{"aaaa", "aa", "b", "bb"},
+		{"graveyard", "yard", "", "grave"},
+		{"über", "", "|", "|ü|b|e|r|"},
+		{"", "", "x", "x"},
+	} {
+		ret := stringReplaceCharSequence([]interface{}{object.StringObjectFromGoString(tc.str),
+			object.StringObjectFromGoString(tc.target), makeTestStringBuilder(tc.replacement)})
+		if str := object.GoStringFromStringObject(ret.(*object.Object)); str != tc.want {
+			t.Errorf("%q.replace(%q, %q): expected %q, got %q", tc.str, tc.target, tc.replacement, tc.want, str)
+		}
+	}
+
+	// with nothing to replace, the same string is returned
+	str := object.StringObjectFromGoString("smash")
+	if ret := stringReplaceCharSequence([]interface{}{str, object.StringObjectFromGoString("x"),
+		object.StringObjectFromGoString("y")}); ret != str {
+		t.Errorf("String.replace() with no match: expected the same string to be returned")
+	}
+}
+
+// replacing a char throughout a 1MB string takes a single pass, not time that grows with the
+// square of the length
+func TestStringReplaceCharSequenceLargeString(t *testing.T) {
+	globals.InitGlobals("test")
+	const size = 1 << 20
+	str := object.StringObjectFromGoString(strings.Repeat("ab", size/2))
+
+	start := time.Now()
+	ret := stringReplaceCharSequence([]interface{}{str, object.StringObjectFromGoString("b"),
+		object.StringObjectFromGoString("cd")})
+	elapsed := time.Since(start)
+
+	result := object.GoStringFromStringObject(ret.(*object.Object))
+	if len(result) != size+size/2 || result != strings.Repeat("acd", size/2) {
+		t.Errorf("String.replace() on a 1MB string: got a wrong result of length %d", len(result))
+	}
+	if elapsed > time.Second {
+		t.Errorf("String.replace() on a 1MB string: expected it to take well under a second, took %s", elapsed)
+	}
+}
+
+// both an empty String and one whose value field holds nil must act as the empty string
+func TestStringEmptyAndNilValue(t *testing.T) {
+	globals.InitGlobals("test")
+
+	nilValued := object.NewStringObject()
+	nilValued.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: nil}
+	cases := map[string]*object.Object{
+		"empty":     object.StringObjectFromGoString(""),
+		"nil value": nilValued,
+	}
+
+	for name, str := range cases {
+		if ret := stringLength([]interface{}{str}); ret != int64(0) {
+			t.Errorf("%s: length(): expected 0, got %v", name, ret)
+		}
+		if ret := stringIsEmpty([]interface{}{str}); ret != types.JavaBoolTrue {
+			t.Errorf("%s: isEmpty(): expected true, got %v", name, ret)
+		}
+		ret := stringCharAt([]interface{}{str, int64(0)})
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.StringIndexOutOfBoundsException {
+			t.Errorf("%s: charAt(0): expected a StringIndexOutOfBoundsException, got %v", name, ret)
+		}
+		ret = substringToTheEnd([]interface{}{str, int64(0)})
+		if sub, ok := ret.(*object.Object); !ok || object.GoStringFromStringObject(sub) != "" {
+			t.Errorf("%s: substring(0): expected an empty String, got %v", name, ret)
+		}
+		ret = substringStartEnd([]interface{}{str, int64(0), int64(0)})
+		if sub, ok := ret.(*object.Object); !ok || object.GoStringFromStringObject(sub) != "" {
+			t.Errorf("%s: substring(0, 0): expected an empty String, got %v", name, ret)
+		}
+		ret = substringToTheEnd([]interface{}{str, int64(1)})
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.StringIndexOutOfBoundsException {
+			t.Errorf("%s: substring(1): expected a StringIndexOutOfBoundsException, got %v", name, ret)
+		}
+		if ret := stringHashCode([]interface{}{str}); ret != int64(0) {
+			t.Errorf("%s: hashCode(): expected 0, got %v", name, ret)
+		}
+	}
+}
+
+func TestStringHashCodeAndIsEmpty(t *testing.T) {
+	globals.InitGlobals("test")
+
+	if ret := stringHashCode([]interface{}{object.StringObjectFromGoString("hello")}); ret != int64(99162322) {
+		t.Errorf("hashCode() of \"hello\": expected 99162322, got %v", ret)
+	}
+	// long enough to overflow an int, which must wrap as in Java
+	if ret := stringHashCode([]interface{}{object.StringObjectFromGoString("hello world")}); ret != int64(1794106052) {
+		t.Errorf("hashCode() of \"hello world\": expected 1794106052, got %v", ret)
+	}
+	if ret := stringIsEmpty([]interface{}{object.StringObjectFromGoString(" ")}); ret != types.JavaBoolFalse {
+		t.Errorf("isEmpty() of \" \": expected false, got %v", ret)
+	}
+	ret := substringToTheEnd([]interface{}{object.StringObjectFromGoString("abc"), int64(3)})
+	if sub, ok := ret.(*object.Object); !ok || object.GoStringFromStringObject(sub) != "" {
+		t.Errorf("substring(3) of \"abc\": expected an empty String, got %v", ret)
+	}
+}
+
+// as in Java, a null argument is formatted as "null", or as "false" by %b
+func TestSprintfNullArgument(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%s", "null"},
+		{"%S", "NULL"},
+		{"[%6s]", "[  null]"},
+		{"[%-6s]", "[null  ]"},
+		{"%.2s", "nu"},
+		{"%d", "null"},
+		{"%b", "false"},
+		{"%s %s", "null x"},
+	}
+	for _, test := range tests {
+		checkSprintf(t, test.format, test.want, object.Null, object.StringObjectFromGoString("x"))
+	}
+}
+
+// as in Java, %b is "false" for null and Boolean.FALSE, and "true" for any other argument
+func TestSprintfBoolean(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, _, _ string, _ ...any) (any, error) {
+		return object.StringObjectFromGoString("an object"), nil
+	}
+	className := "java/lang/Object"
+	arbitrary := object.MakeEmptyObjectWithClassName(&className)
+
+	tests := []struct {
+		format string
+		arg    *object.Object
+		want   string
+	}{
+		{"%b", object.Null, "false"},
+		{"%b", populator("java/lang/Boolean", types.Bool, types.JavaBoolTrue), "true"},
+		{"%b", populator("java/lang/Boolean", types.Bool, types.JavaBoolFalse), "false"},
+		{"%B", populator("java/lang/Boolean", types.Bool, types.JavaBoolFalse), "FALSE"},
+		{"%b", arbitrary, "true"},
+		{"%b", object.StringObjectFromGoString("false"), "true"},
+		{"%b", populator("java/lang/Integer", types.Int, int64(0)), "true"},
+		{"[%-6b]", populator("java/lang/Boolean", types.Bool, types.JavaBoolTrue), "[true  ]"},
+		{"[%.1b]", arbitrary, "[t]"},
+	}
+	for _, test := range tests {
+		checkSprintf(t, test.format, test.want, test.arg)
+	}
+}
+
+// the width, left-justify, zero-pad, and sign flags, and the combinations of them that Java rejects
+func TestSprintfWidthAndFlags(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []sprintfTest{
+		{"%-10s|", object.StringObjectFromGoString("x"), "x         |", 0},
+		{"%10s|", object.StringObjectFromGoString("x"), "         x|", 0},
+		{"%+d", populator("java/lang/Integer", types.Int, int64(5)), "+5", 0},
+		{"%+d", populator("java/lang/Integer", types.Int, int64(-5)), "-5", 0},
+		{"% d", populator("java/lang/Integer", types.Int, int64(5)), " 5", 0},
+		{"%05d", populator("java/lang/Integer", types.Int, int64(-42)), "-0042", 0},
+		{"%-6d|", populator("java/lang/Integer", types.Int, int64(42)), "42    |", 0},
+		{"%+08.2f", populator("java/lang/Double", types.Double, 3.14159), "+0003.14", 0},
+		{"%-d", populator("java/lang/Integer", types.Int, int64(5)), "%-d", excNames.MissingFormatWidthException},
+		{"%0d", populator("java/lang/Integer", types.Int, int64(5)), "%0d", excNames.MissingFormatWidthException},
+		{"%+ d", populator("java/lang/Integer", types.Int, int64(5)), "Flags = '+ '", excNames.IllegalFormatFlagsException},
+		{"%-05d", populator("java/lang/Integer", types.Int, int64(5)), "Flags = '-0'", excNames.IllegalFormatFlagsException},
+		{"%+s", object.StringObjectFromGoString("x"), "Conversion = s, Flags = +",
+			excNames.FormatFlagsConversionMismatchException},
+		{"%05s", object.StringObjectFromGoString("x"), "Conversion = s, Flags = 0",
+			excNames.FormatFlagsConversionMismatchException},
+	}
+	checkSprintfTests(t, tests)
+}
+
+// as in Java, a conversion that doesn't accept the argument's class, or that isn't a conversion
+// at all, is an exception rather than output
+func TestSprintfConversionMismatch(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []sprintfTest{
+		{"%d", object.StringObjectFromGoString("x"), "d != java.lang.String", excNames.IllegalFormatConversionException},
+		{"%x", populator("java/lang/Double", types.Double, 1.5), "x != java.lang.Double", excNames.IllegalFormatConversionException},
+		{"%f", populator("java/lang/Integer", types.Int, int64(1)), "f != java.lang.Integer", excNames.IllegalFormatConversionException},
+		{"%c", populator("java/lang/Boolean", types.Bool, types.JavaBoolTrue), "c != java.lang.Boolean",
+			excNames.IllegalFormatConversionException},
+		{"%q", object.StringObjectFromGoString("x"), "Conversion = 'q'", excNames.UnknownFormatConversionException},
+		{"%D", populator("java/lang/Integer", types.Int, int64(1)), "Conversion = 'D'", excNames.UnknownFormatConversionException},
+		{"%tY", populator("java/lang/Long", types.Long, int64(0)), "Conversion = 't'", excNames.UnknownFormatConversionException},
+		{"abc%", object.StringObjectFromGoString("x"), "Conversion = '%'", excNames.UnknownFormatConversionException},
+		{"abc%-5", object.StringObjectFromGoString("x"), "Conversion = '-'", excNames.UnknownFormatConversionException},
+		{"%d", object.Null, "null", 0},
+		{"%x", populator("java/lang/Long", types.Long, int64(255)), "ff", 0},
+	}
+	checkSprintfTests(t, tests)
+}
+
+// the expected strings and exceptions are those of the JDK's String.format()
+func TestSprintfCharacter(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []sprintfTest{
+		{"%c", populator("java/lang/Integer", types.Int, int64(65)), "A", 0},
+		{"%c", populator("java/lang/Character", types.Char, int64('z')), "z", 0},
+		{"%C", populator("java/lang/Character", types.Char, int64('z')), "Z", 0},
+		{"%s", populator("java/lang/Character", types.Char, int64('z')), "z", 0},
+		{"%c", populator("java/lang/Integer", types.Int, int64(0x1F600)), "\U0001F600", 0},
+		{"%c", populator("java/lang/Short", types.Short, int64(0x00E9)), "é", 0},
+		{"%c", populator("java/lang/Byte", types.Byte, int64(0x41)), "A", 0},
+		{"[%3c]", populator("java/lang/Integer", types.Int, int64(65)), "[  A]", 0},
+		{"[%-3c]", populator("java/lang/Integer", types.Int, int64(65)), "[A  ]", 0},
+		{"%c", object.Null, "null", 0},
+		{"%c", populator("java/lang/Integer", types.Int, int64(0x110000)), "Code point = 0x110000",
+			excNames.IllegalFormatCodePointException},
+		{"%c", populator("java/lang/Integer", types.Int, int64(-1)), "Code point = 0xffffffff",
+			excNames.IllegalFormatCodePointException},
+		{"%c", populator("java/lang/Byte", types.Byte, int64(-1)), "Code point = 0xffffffff",
+			excNames.IllegalFormatCodePointException},
+	}
+	checkSprintfTests(t, tests)
+}
+
+// As in Java, %x and %o format a negative number as the unsigned number of its size with the
+// same bits
+func TestSprintfHexOctal(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		format string
+		arg    *object.Object
+		want   string
+	}{
+		{"%08x", populator("java/lang/Integer", types.Int, int64(-1)), "ffffffff"},
+		{"%x", populator("java/lang/Long", types.Long, int64(-1)), "ffffffffffffffff"},
+		{"%X", populator("java/lang/Long", types.Long, int64(math.MinInt64)), "8000000000000000"},
+		{"%x", populator("java/lang/Short", types.Short, int64(-2)), "fffe"},
+		{"%x", populator("java/lang/Byte", types.Byte, int64(-1)), "ff"},
+		{"%o", populator("java/lang/Integer", types.Int, int64(-1)), "37777777777"},
+		{"%08x", populator("java/lang/Integer", types.Int, int64(255)), "000000ff"},
+		{"%#x", populator("java/lang/Integer", types.Int, int64(255)), "0xff"},
+		{"%#X", populator("java/lang/Integer", types.Int, int64(255)), "0XFF"},
+		{"%#010x", populator("java/lang/Integer", types.Int, int64(255)), "0x000000ff"},
+		{"%#o", populator("java/lang/Integer", types.Int, int64(8)), "010"},
+		{"[%6x]", populator("java/lang/Integer", types.Int, int64(255)), "[    ff]"},
+		{"[%-6x]", populator("java/lang/Integer", types.Int, int64(255)), "[ff    ]"},
+	}
+	for _, test := range tests {
+		checkSprintf(t, test.format, test.want, test.arg)
+	}
+}
+
+// The upper-case conversions put the whole formatted result in upper case
+func TestSprintfUpperCaseConversions(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		format string
+		arg    *object.Object
+		want   string
+	}{
+		{"%S", object.StringObjectFromGoString("abc"), "ABC"},
+		{"[%-5S]", object.StringObjectFromGoString("abc"), "[ABC  ]"},
+		{"[%5.2S]", object.StringObjectFromGoString("abc"), "[   AB]"},
+		{"%S", object.Null, "NULL"},
+		{"%X", populator("java/lang/Integer", types.Int, int64(48879)), "BEEF"},
+		{"%#X", populator("java/lang/Integer", types.Int, int64(-1)), "0XFFFFFFFF"},
+		{"%B", populator("java/lang/Boolean", types.Bool, int64(1)), "TRUE"},
+		{"%C", populator("java/lang/Character", types.Char, int64('q')), "Q"},
+		{"%E", populator("java/lang/Double", types.Double, 12345.678), "1.234568E+04"},
+		{"%G", populator("java/lang/Double", types.Double, 0.00001234), "1.23400E-05"},
+	}
+	for _, test := range tests {
+		checkSprintf(t, test.format, test.want, test.arg)
+	}
+}
+
+// %s formats a boxed value as the text of its toString(), and %d formats a Byte as a signed number
+func TestSprintfBoxedValues(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		format string
+		arg    *object.Object
+		want   string
+	}{
+		{"%s", populator("java/lang/Integer", types.Int, int64(5)), "5"},
+		{"[%4s]", populator("java/lang/Integer", types.Int, int64(-5)), "[  -5]"},
+		{"%S", populator("java/lang/Long", types.Long, int64(5)), "5"},
+		{"%s", populator("java/lang/Double", types.Double, 2.5), "2.5"},
+		{"%s", populator("java/lang/Double", types.Double, 3.0), "3.0"},
+		{"%s", populator("java/lang/Double", types.Double, 1.0e7), "1.0E7"},
+		{"%S", populator("java/lang/Double", types.Double, 2.5e-4), "2.5E-4"},
+		{"%s", populator("java/lang/Float", types.Float, float64(float32(0.1))), "0.1"},
+		{"%s", populator("java/lang/Boolean", types.Bool, int64(1)), "true"},
+		{"%S", populator("java/lang/Boolean", types.Bool, int64(0)), "FALSE"},
+		{"%s", populator("java/lang/Byte", types.Byte, int64(-7)), "-7"},
+		{"%d", populator("java/lang/Byte", types.Byte, int64(-7)), "-7"},
+	}
+	for _, test := range tests {
+		checkSprintf(t, test.format, test.want, test.arg)
+	}
+}
+
+// the flags '(', '+', ' ', and '0' apply to every %d and %f, and %% is padded to its width
+func TestSprintfNumberFlags(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		format string
+		arg    *object.Object
+		want   string
+	}{
+		{"%(d", populator("java/lang/Integer", types.Int, int64(-42)), "(42)"},
+		{"%(d", populator("java/lang/Integer", types.Int, int64(42)), "42"},
+		{"[%(6d]", populator("java/lang/Long", types.Long, int64(-42)), "[  (42)]"},
+		{"%+d", populator("java/lang/Integer", types.Int, int64(7)), "+7"},
+		{"[% d]", populator("java/lang/Integer", types.Int, int64(7)), "[ 7]"},
+		{"%05d", populator("java/lang/Integer", types.Int, int64(-7)), "-0007"},
+		{"%(.2f", populator("java/lang/Double", types.Double, -3.14159), "(3.14)"},
+		{"%+08.2f", populator("java/lang/Double", types.Double, 3.14159), "+0003.14"},
+		{"%f", populator("java/lang/Double", types.Double, math.Inf(-1)), "-Infinity"},
+		{"[%5%]", object.Null, "[    %]"},
+		{"[%-3%]", object.Null, "[%  ]"},
+	}
+	for _, test := range tests {
+		checkSprintf(t, test.format, test.want, test.arg)
+	}
+}
+
+// %h formats the argument's hash code in hex, computed as the JDK computes it
+func TestSprintfHashCode(t *testing.T) {
+	globals.InitGlobals("test")
+
+	tests := []struct {
+		format string
+		arg    *object.Object
+		want   string
+	}{
+		{"%h", populator("java/lang/Integer", types.Int, int64(255)), "ff"},
+		{"%h", populator("java/lang/Integer", types.Int, int64(-1)), "ffffffff"},
+		{"%h", object.StringObjectFromGoString("hi"), "d01"},
+		{"[%-5H]", populator("java/lang/Boolean", types.Bool, int64(1)), "[4CF  ]"},
+		{"%h", populator("java/lang/Long", types.Long, int64(1)<<32), "1"},
+		{"%h", populator("java/lang/Double", types.Double, 1.0), "3ff00000"},
+		{"%h", object.Null, "null"},
+	}
+	for _, test := range tests {
+		checkSprintf(t, test.format, test.want, test.arg)
+	}
+}
+
+func TestStringIndexOfCodePoint(t *testing.T) {
+	globals.InitGlobals("test")
+
+	// "a😀b😀a": 😀 (U+1F600) is a surrogate pair, so the UTF-16 indices are a=0, 😀=1, b=3, 😀=4, a=6
+	str := object.StringObjectFromGoString("a😀b😀a")
+	tests := []struct {
+		name     string
+		gfunc    func([]interface{}) interface{}
+		params   []interface{}
+		expected int64
+	}{
+		{"indexOf('a')", stringIndexOfCodePoint, []interface{}{str, int64('a')}, 0},
+		{"indexOf('b')", stringIndexOfCodePoint, []interface{}{str, int64('b')}, 3},
+		{"indexOf('z')", stringIndexOfCodePoint, []interface{}{str, int64('z')}, -1},
+		{"indexOf('a', 1)", stringIndexOfCodePoint, []interface{}{str, int64('a'), int64(1)}, 6},
+		{"indexOf('a', -5)", stringIndexOfCodePoint, []interface{}{str, int64('a'), int64(-5)}, 0},
+		{"indexOf('a', 99)", stringIndexOfCodePoint, []interface{}{str, int64('a'), int64(99)}, -1},
+		{"indexOf(U+1F600)", stringIndexOfCodePoint, []interface{}{str, int64(0x1F600)}, 1},
+		{"indexOf(U+1F600, 2)", stringIndexOfCodePoint, []interface{}{str, int64(0x1F600), int64(2)}, 4},
+		{"indexOf(high surrogate)", stringIndexOfCodePoint, []interface{}{str, int64(0xD83D)}, 1},
+		{"indexOf(invalid)", stringIndexOfCodePoint, []interface{}{str, int64(0x110000)}, -1},
+		{"lastIndexOf('a')", stringLastIndexOfCodePoint, []interface{}{str, int64('a')}, 6},
+		{"lastIndexOf('a', 5)", stringLastIndexOfCodePoint, []interface{}{str, int64('a'), int64(5)}, 0},
+		{"lastIndexOf('a', 99)", stringLastIndexOfCodePoint, []interface{}{str, int64('a'), int64(99)}, 6},
+		{"lastIndexOf('a', -1)", stringLastIndexOfCodePoint, []interface{}{str, int64('a'), int64(-1)}, -1},
+		{"lastIndexOf(U+1F600)", stringLastIndexOfCodePoint, []interface{}{str, int64(0x1F600)}, 4},
+		{"lastIndexOf(U+1F600, 3)", stringLastIndexOfCodePoint, []interface{}{str, int64(0x1F600), int64(3)}, 1},
+		{"lastIndexOf(U+1F601)", stringLastIndexOfCodePoint, []interface{}{str, int64(0x1F601)}, -1},
+	}
+	for _, test := range tests {
+		if ret := test.gfunc(test.params); ret != test.expected {
+			t.Errorf("%s: expected %d, got %v", test.name, test.expected, ret)
+		}
+	}
+}
+
+// charAt() and substring() index the UTF-16 chars, as the length does, not the runes or bytes
+func TestStringCharAtAndSubstringUTF16(t *testing.T) {
+	globals.InitGlobals("test")
+
+	// "a😀b": 😀 (U+1F600) is the surrogate pair D83D DE00, so the length is 4
+	str := object.StringObjectFromGoString("a😀b")
+	for index, expected := range []int64{'a', 0xD83D, 0xDE00, 'b'} {
+		if ret := stringCharAt([]interface{}{str, int64(index)}); ret != expected {
+			t.Errorf("charAt(%d): expected %#x, got %v", index, expected, ret)
+		}
+	}
+	if _, ok := stringCharAt([]interface{}{str, int64(4)}).(*GErrBlk); !ok {
+		t.Errorf("charAt(4): expected a StringIndexOutOfBoundsException")
+	}
+
+	substrings := []struct {
+		ret      interface{}
+		expected string
+	}{
+		{substringToTheEnd([]interface{}{str, int64(3)}), "b"},
+		{substringStartEnd([]interface{}{str, int64(1), int64(3)}), "😀"},
+		{substringStartEnd([]interface{}{str, int64(0), int64(4)}), "a😀b"},
+	}
+	for _, sub := range substrings {
+		if obj, ok := sub.ret.(*object.Object); !ok || object.GoStringFromStringObject(obj) != sub.expected {
+			t.Errorf("substring: expected %q, got %v", sub.expected, sub.ret)
+		}
+	}
+	if _, ok := substringStartEnd([]interface{}{str, int64(0), int64(5)}).(*GErrBlk); !ok {
+		t.Errorf("substring(0, 5): expected a StringIndexOutOfBoundsException")
+	}
+}
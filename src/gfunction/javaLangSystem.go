@@ -73,18 +73,48 @@ func Load_Lang_System() {
 			GFunction:  forceGC,
 		}
 
+	MethodSignatures["java/lang/Runtime.gc()V"] = // System.gc() calls this in the JDK
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  forceGC,
+		}
+
 	MethodSignatures["java/lang/System.getProperty(Ljava/lang/String;)Ljava/lang/String;"] =
 		GMeth{
 			ParamSlots: 1,
 			GFunction:  getProperty,
 		}
 
+	MethodSignatures["java/lang/System.getSecurityManager()Ljava/lang/SecurityManager;"] = // there is none
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  getSecurityManager,
+		}
+
+	MethodSignatures["java/lang/System.identityHashCode(Ljava/lang/Object;)I"] = // Object.hashCode(), even if overridden
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  identityHashCode,
+		}
+
+	MethodSignatures["java/lang/System.lineSeparator()Ljava/lang/String;"] = // the same as the line.separator property
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  getLineSeparator,
+		}
+
 	MethodSignatures["java/lang/System.registerNatives()V"] =
 		GMeth{
 			ParamSlots: 0,
 			GFunction:  justReturn,
 		}
 
+	MethodSignatures["java/lang/System.setSecurityManager(Ljava/lang/SecurityManager;)V"] = // removed, as in recent JDKs
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapDeprecated,
+		}
+
 	MethodSignatures["java/lang/System.console()Ljava/io/Console;"] =
 		GMeth{
 			ParamSlots: 0,
@@ -292,12 +322,45 @@ func exitI(params []interface{}) interface{} {
 	return 0 // this code is not executed as previous line ends Jacobin
 }
 
-// Force a garbage collection cycle.
+// Force a garbage collection cycle, for System.gc() and Runtime.gc(). As Go manages the
+// memory of Java objects, runtime.GC() reclaims the unreachable ones. It blocks until the
+// cycle completes, which is more than Java promises, but gc() is rarely called, and programs
+// that call it expect the garbage to have been collected.
+// "java/lang/System.gc()V" and "java/lang/Runtime.gc()V"
 func forceGC([]interface{}) interface{} {
 	runtime.GC()
 	return nil
 }
 
+// Jacobin has no security manager, so getSecurityManager() always returns null, and
+// setSecurityManager() throws an UnsupportedOperationException, as it does in recent JDKs
+// "java/lang/System.getSecurityManager()Ljava/lang/SecurityManager;"
+func getSecurityManager([]interface{}) interface{} {
+	return object.Null
+}
+
+// lineSeparator returns the platform's line separator: \r\n on Windows, \n elsewhere.
+// It is the value of the line.separator property and what println() writes.
+func lineSeparator() string {
+	if runtime.GOOS == "windows" {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// "java/lang/System.identityHashCode(Ljava/lang/Object;)I" -- 0 for null
+func identityHashCode(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return int64(0)
+	}
+	return objectHashCode(params)
+}
+
+// "java/lang/System.lineSeparator()Ljava/lang/String;"
+func getLineSeparator([]interface{}) interface{} {
+	return object.StringObjectFromGoString(lineSeparator())
+}
+
 // Get a property
 func getProperty(params []interface{}) interface{} {
 	propObj := params[0].(*object.Object) // string
@@ -307,6 +370,11 @@ func getProperty(params []interface{}) interface{} {
 	g := globals.GetGlobalRef()
 	operSys := runtime.GOOS
 
+	// a property set with -D on the command line takes precedence
+	if value, ok := g.SystemProperties[propStr]; ok {
+		return object.StringObjectFromGoString(value)
+	}
+
 	switch propStr {
 	case "file.encoding":
 		value = g.FileEncoding
@@ -344,11 +412,7 @@ func getProperty(params []interface{}) interface{} {
 	case "java.vm.version":
 		value = strconv.Itoa(g.MaxJavaVersion)
 	case "line.separator":
-		if operSys == "windows" {
-			value = "\\r\\n"
-		} else {
-			value = "\\n"
-		}
+		value = lineSeparator()
 	case "native.encoding": // hard to find out what this is, so hard-coding to UTF8
 		value = "UTF8"
 	case "os.arch":
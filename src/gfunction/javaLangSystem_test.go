@@ -7,9 +7,13 @@
 package gfunction
 
 import (
+	"io"
+	"jacobin/excNames"
 	"jacobin/globals"
 	"jacobin/object"
 	"jacobin/stringPool"
+	"os"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -244,3 +248,85 @@ func TestArrayCopyInvalidLength(t *testing.T) {
 		t.Errorf("Expected error re invalid length, got %s", errMsg)
 	}
 }
+
+func TestLineSeparator(t *testing.T) {
+	globals.InitGlobals("test")
+
+	expected := "\n"
+	if runtime.GOOS == "windows" {
+		expected = "\r\n"
+	}
+
+	sep := object.GoStringFromStringObject(getLineSeparator(nil).(*object.Object))
+	if sep != expected {
+		t.Errorf("Expected System.lineSeparator() to return %q, got %q", expected, sep)
+	}
+
+	prop := getProperty([]interface{}{object.StringObjectFromGoString("line.separator")})
+	if object.GoStringFromStringObject(prop.(*object.Object)) != expected {
+		t.Errorf("Expected line.separator property to be %q, got %q",
+			expected, object.GoStringFromStringObject(prop.(*object.Object)))
+	}
+
+	// println() should end its output with the same separator
+	r, w, _ := os.Pipe()
+	PrintlnBIS([]interface{}{w, int64(42)})
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	if string(out) != "42"+expected {
+		t.Errorf("Expected println(42) to write %q, got %q", "42"+expected, string(out))
+	}
+}
+
+// there is no security manager, and one can't be set
+func TestSecurityManager(t *testing.T) {
+	globals.InitGlobals("test")
+
+	if ret := getSecurityManager(nil); !object.IsNull(ret) {
+		t.Errorf("Expected System.getSecurityManager() to return null, got: %v", ret)
+	}
+
+	Load_Lang_System()
+	gm := MethodSignatures["java/lang/System.setSecurityManager(Ljava/lang/SecurityManager;)V"]
+	ret := gm.GFunction([]interface{}{object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.UnsupportedOperationException {
+		t.Errorf("Expected System.setSecurityManager() to throw UnsupportedOperationException, got: %v", ret)
+	}
+}
+
+// System.gc() and Runtime.gc() run a garbage collection. How much memory it frees depends on
+// what else is allocated while the tests run, so only the collection itself is checked.
+func TestGC(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Lang_System()
+
+	for _, methodName := range []string{"java/lang/System.gc()V", "java/lang/Runtime.gc()V"} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		if ret := MethodSignatures[methodName].GFunction(nil); ret != nil {
+			t.Errorf("%s: expected no return value, got: %v", methodName, ret)
+		}
+		runtime.ReadMemStats(&after)
+
+		if after.NumGC <= before.NumGC {
+			t.Errorf("%s: expected a garbage collection, but the count stayed at %d", methodName, after.NumGC)
+		}
+	}
+}
+
+// A property set with -D is returned by getProperty(), even if it's one of the standard ones
+func TestGetPropertySetOnCommandLine(t *testing.T) {
+	g := globals.InitGlobals("test")
+	g.SystemProperties["foo"] = "bar baz"
+	g.SystemProperties["file.encoding"] = "ISO-8859-1"
+
+	for name, expected := range map[string]string{"foo": "bar baz", "file.encoding": "ISO-8859-1"} {
+		ret := getProperty([]interface{}{object.StringObjectFromGoString(name)})
+		if str, ok := ret.(*object.Object); !ok || object.GoStringFromStringObject(str) != expected {
+			t.Errorf("System.getProperty(%q): expected %q, got %v", name, expected, ret)
+		}
+	}
+	if ret := getProperty([]interface{}{object.StringObjectFromGoString("not.set")}); !object.IsNull(ret) {
+		t.Errorf("System.getProperty(\"not.set\"): expected null, got %v", ret)
+	}
+}
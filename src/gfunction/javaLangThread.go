@@ -7,7 +7,11 @@
 package gfunction
 
 import (
+	"fmt"
 	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/thread"
+	"jacobin/types"
 	"time"
 )
 
@@ -29,12 +33,36 @@ import (
 
 func Load_Lang_Thread() {
 
+	MethodSignatures["java/lang/Thread.getPriority()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  threadGetPriority,
+		}
+
+	MethodSignatures["java/lang/Thread.isDaemon()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  threadIsDaemon,
+		}
+
 	MethodSignatures["java/lang/Thread.registerNatives()V"] =
 		GMeth{
 			ParamSlots: 0,
 			GFunction:  justReturn,
 		}
 
+	MethodSignatures["java/lang/Thread.setDaemon(Z)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  threadSetDaemon,
+		}
+
+	MethodSignatures["java/lang/Thread.setPriority(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  threadSetPriority,
+		}
+
 	MethodSignatures["java/lang/Thread.sleep(J)V"] =
 		GMeth{
 			ParamSlots: 2,
@@ -53,3 +81,48 @@ func threadSleep(params []interface{}) interface{} {
 	time.Sleep(time.Duration(sleepTime) * time.Millisecond)
 	return nil
 }
+
+// The priority and daemon status of a Thread are kept in its "priority" and "daemon" fields,
+// as in the JDK. Jacobin doesn't yet start Threads, so neither one affects execution yet.
+// As in the JDK, a Thread's "threadStatus" field is 0 until the Thread is started.
+
+// "java/lang/Thread.getPriority()I"
+func threadGetPriority(params []interface{}) interface{} {
+	priority, ok := params[0].(*object.Object).FieldTable["priority"].Fvalue.(int64)
+	if !ok {
+		return int64(thread.NormPriority)
+	}
+	return priority
+}
+
+// "java/lang/Thread.isDaemon()Z"
+func threadIsDaemon(params []interface{}) interface{} {
+	daemon, ok := params[0].(*object.Object).FieldTable["daemon"].Fvalue.(int64)
+	if !ok {
+		return types.JavaBoolFalse
+	}
+	return daemon
+}
+
+// "java/lang/Thread.setDaemon(Z)V" can't change the daemon status of a started Thread
+func threadSetDaemon(params []interface{}) interface{} {
+	th := params[0].(*object.Object)
+	if status, ok := th.FieldTable["threadStatus"].Fvalue.(int64); ok && status != 0 {
+		errMsg := "Thread.setDaemon: the thread has already been started"
+		return getGErrBlk(excNames.IllegalThreadStateException, errMsg)
+	}
+	th.FieldTable["daemon"] = object.Field{Ftype: types.Bool, Fvalue: params[1].(int64)}
+	return nil
+}
+
+// "java/lang/Thread.setPriority(I)V"
+func threadSetPriority(params []interface{}) interface{} {
+	priority := params[1].(int64)
+	if priority < thread.MinPriority || priority > thread.MaxPriority {
+		errMsg := fmt.Sprintf("Thread.setPriority: priority %d is not from %d to %d",
+			priority, thread.MinPriority, thread.MaxPriority)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	params[0].(*object.Object).FieldTable["priority"] = object.Field{Ftype: types.Int, Fvalue: priority}
+	return nil
+}
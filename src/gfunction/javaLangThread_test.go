@@ -0,0 +1,52 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/thread"
+	"jacobin/types"
+	"testing"
+)
+
+func TestThreadPriorityAndDaemon(t *testing.T) {
+	globals.InitGlobals("test")
+	className := "java/lang/Thread"
+	th := object.MakeEmptyObjectWithClassName(&className)
+
+	if priority := threadGetPriority([]interface{}{th}); priority != int64(thread.NormPriority) {
+		t.Errorf("Thread.getPriority(): expected the default of %d, got %v", thread.NormPriority, priority)
+	}
+	if daemon := threadIsDaemon([]interface{}{th}); daemon != types.JavaBoolFalse {
+		t.Errorf("Thread.isDaemon(): expected false by default, got %v", daemon)
+	}
+
+	threadSetPriority([]interface{}{th, int64(thread.MaxPriority)})
+	threadSetDaemon([]interface{}{th, types.JavaBoolTrue})
+	if priority := threadGetPriority([]interface{}{th}); priority != int64(thread.MaxPriority) {
+		t.Errorf("Thread.getPriority(): expected %d, got %v", thread.MaxPriority, priority)
+	}
+	if daemon := threadIsDaemon([]interface{}{th}); daemon != types.JavaBoolTrue {
+		t.Errorf("Thread.isDaemon(): expected true, got %v", daemon)
+	}
+
+	ret := threadSetPriority([]interface{}{th, int64(thread.MaxPriority + 1)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Thread.setPriority(11): expected an IllegalArgumentException, got %v", ret)
+	}
+
+	th.FieldTable["threadStatus"] = object.Field{Ftype: types.Int, Fvalue: int64(5)} // RUNNABLE
+	ret = threadSetDaemon([]interface{}{th, types.JavaBoolFalse})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalThreadStateException {
+		t.Errorf("Thread.setDaemon() of a started thread: expected an IllegalThreadStateException, got %v", ret)
+	}
+	if daemon := threadIsDaemon([]interface{}{th}); daemon != types.JavaBoolTrue {
+		t.Errorf("Thread.isDaemon(): expected a started thread to remain a daemon, got %v", daemon)
+	}
+}
@@ -10,11 +10,15 @@ import (
 	"container/list"
 	"errors"
 	"fmt"
+	"jacobin/excNames"
 	"jacobin/log"
 	"jacobin/object"
 	"jacobin/shutdown"
 	"jacobin/statics"
+	"jacobin/stringPool"
 	"jacobin/types"
+	"os"
+	"strings"
 )
 
 func Load_Lang_Throwable() {
@@ -39,6 +43,56 @@ func Load_Lang_Throwable() {
 			NeedsContext: true,
 		}
 
+	MethodSignatures["java/lang/Throwable.getStackTrace()[Ljava/lang/StackTraceElement;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  throwableGetStackTrace,
+		}
+
+	MethodSignatures["java/lang/Throwable.<init>(Ljava/lang/String;Ljava/lang/Throwable;)V"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    throwableInitStringThrowable,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Throwable.<init>(Ljava/lang/Throwable;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    throwableInitThrowable,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/lang/Throwable.getCause()Ljava/lang/Throwable;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  throwableGetCause,
+		}
+
+	MethodSignatures["java/lang/Throwable.initCause(Ljava/lang/Throwable;)Ljava/lang/Throwable;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  throwableInitCause,
+		}
+
+	MethodSignatures["java/lang/Throwable.addSuppressed(Ljava/lang/Throwable;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  throwableAddSuppressed,
+		}
+
+	MethodSignatures["java/lang/Throwable.getSuppressed()[Ljava/lang/Throwable;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  throwableGetSuppressed,
+		}
+
+	MethodSignatures["java/lang/Throwable.printStackTrace()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  throwablePrintStackTrace,
+		}
+
 }
 
 // This method duplicates the following bytecode, with these exceptions:
@@ -168,3 +222,270 @@ func GetStackTraces(params []interface{}) *object.Object {
 	retVal := of(args) // this is javaLangStackTraceElement.of()
 	return retVal.(*object.Object)
 }
+
+// java/lang/Throwable.<init>(Ljava/lang/String;Ljava/lang/Throwable;)V
+// Sets the detail message and the cause, then fills in the stack trace.
+// params[0] is the frame stack, params[1] is this Throwable.
+func throwableInitStringThrowable(params []interface{}) interface{} {
+	if len(params) != 4 {
+		errMsg := fmt.Sprintf("Throwable.<init>: expected four parameters, got: %d", len(params))
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	frameStack := params[0].(*list.List)
+	this := params[1].(*object.Object)
+	message, _ := params[2].(*object.Object)
+	cause, _ := params[3].(*object.Object)
+
+	this.FieldTable["detailMessage"] = object.Field{Ftype: types.Ref, Fvalue: message}
+	this.FieldTable["cause"] = object.Field{Ftype: types.Ref, Fvalue: cause}
+	FillInStackTrace([]interface{}{frameStack, this})
+	return nil
+}
+
+// java/lang/Throwable.<init>(Ljava/lang/Throwable;)V
+// As in the JDK, the detail message is cause.toString(), or null if the cause is null.
+func throwableInitThrowable(params []interface{}) interface{} {
+	if len(params) != 3 {
+		errMsg := fmt.Sprintf("Throwable.<init>: expected three parameters, got: %d", len(params))
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	cause, _ := params[2].(*object.Object)
+
+	var message *object.Object
+	if !object.IsNull(cause) {
+		message = object.StringObjectFromGoString(throwableToString(cause))
+	}
+	return throwableInitStringThrowable([]interface{}{params[0], params[1], message, cause})
+}
+
+// java/lang/Throwable.getCause()Ljava/lang/Throwable;
+// Returns the cause, or null if the cause is nonexistent or unknown.
+func throwableGetCause(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	if causeIsUnset(this) {
+		return object.Null
+	}
+	return this.FieldTable["cause"].Fvalue.(*object.Object)
+}
+
+// java/lang/Throwable.initCause(Ljava/lang/Throwable;)Ljava/lang/Throwable;
+// The cause can be set only once, either here or in the constructor, and a
+// Throwable cannot be its own cause.
+func throwableInitCause(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	cause, _ := params[1].(*object.Object)
+
+	if !causeIsUnset(this) {
+		causeName := "a null"
+		if !object.IsNull(cause) {
+			causeName = throwableToString(cause)
+		}
+		errMsg := fmt.Sprintf("Throwable.initCause: Can't overwrite cause with %s", causeName)
+		return getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+
+	if cause == this {
+		errMsg := "Throwable.initCause: Self-causation not permitted"
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+
+	this.FieldTable["cause"] = object.Field{Ftype: types.Ref, Fvalue: cause}
+	return this
+}
+
+// java/lang/Throwable.printStackTrace()V
+// Prints this Throwable, its stack trace, and its chain of causes to stderr.
+func throwablePrintStackTrace(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	_, _ = fmt.Fprintln(os.Stderr, throwableToString(this))
+	for _, line := range StackTraceLines(this) {
+		_, _ = fmt.Fprintln(os.Stderr, line)
+	}
+	for _, line := range SuppressedLines(this) {
+		_, _ = fmt.Fprintln(os.Stderr, line)
+	}
+	for _, line := range CauseChainLines(this) {
+		_, _ = fmt.Fprintln(os.Stderr, line)
+	}
+	return nil
+}
+
+// java/lang/Throwable.addSuppressed(Ljava/lang/Throwable;)V
+// try-with-resources calls this when close() throws after the body has already
+// thrown: the exception from close() is recorded on the one from the body, which
+// is the exception that propagates.
+func throwableAddSuppressed(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	exception, _ := params[1].(*object.Object)
+
+	if exception == this {
+		errMsg := "Throwable.addSuppressed: Self-suppression not permitted"
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	if object.IsNull(exception) {
+		errMsg := "Throwable.addSuppressed: Cannot suppress a null exception."
+		return getGErrBlk(excNames.NullPointerException, errMsg)
+	}
+
+	suppressed := append(suppressedExceptions(this), exception)
+	this.FieldTable["suppressedExceptions"] = object.Field{Ftype: types.RefArray, Fvalue: suppressed}
+	return nil
+}
+
+// java/lang/Throwable.getSuppressed()[Ljava/lang/Throwable;
+// Returns a new array holding the suppressed exceptions in the order they were
+// added, or an empty array if there are none.
+func throwableGetSuppressed(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	suppressed := suppressedExceptions(this)
+
+	throwableClassName := "java/lang/Throwable"
+	array := object.Make1DimRefArray(&throwableClassName, int64(len(suppressed)))
+	copy(array.FieldTable["value"].Fvalue.([]*object.Object), suppressed)
+	return array
+}
+
+// returns the exceptions added by addSuppressed(), if any
+func suppressedExceptions(throwable *object.Object) []*object.Object {
+	suppressedField, ok := throwable.FieldTable["suppressedExceptions"]
+	if !ok {
+		return nil
+	}
+	suppressed, _ := suppressedField.Fvalue.([]*object.Object)
+	return suppressed
+}
+
+// java/lang/Throwable.getStackTrace()[Ljava/lang/StackTraceElement;
+// Returns a new array holding the stack trace elements captured by FillInStackTrace(),
+// so writes to the returned array don't affect later calls. As in printStackTrace(),
+// the frames of the constructors and of Throwable itself are left out, so the first
+// element is the method that created the Throwable.
+func throwableGetStackTrace(params []interface{}) interface{} {
+	this := params[0].(*object.Object)
+	elements := visibleStackTraceElements(this)
+
+	stackTraceElementClassName := "java/lang/StackTraceElement"
+	stackTrace := object.Make1DimRefArray(&stackTraceElementClassName, int64(len(elements)))
+	copy(stackTrace.FieldTable["value"].Fvalue.([]*object.Object), elements)
+	return stackTrace
+}
+
+// In Throwable.java, cause is initialized to the Throwable itself to indicate
+// that it has not yet been set. A Throwable that Jacobin creates without running
+// its constructor has no cause at all. Both are treated as unset. A cause set
+// explicitly to null, however, is set.
+func causeIsUnset(this *object.Object) bool {
+	causeField, ok := this.FieldTable["cause"]
+	if !ok || causeField.Fvalue == nil {
+		return true
+	}
+	return causeField.Fvalue.(*object.Object) == this
+}
+
+// returns the class name and detail message in the format of Throwable.toString()
+func throwableToString(throwable *object.Object) string {
+	className := strings.ReplaceAll(*stringPool.GetStringPointer(throwable.KlassName), "/", ".")
+	msgField, ok := throwable.FieldTable["detailMessage"]
+	if !ok || msgField.Fvalue == nil {
+		return className
+	}
+
+	switch msg := msgField.Fvalue.(type) {
+	case []uint8:
+		return fmt.Sprintf("%s: %s", className, string(msg))
+	case *object.Object:
+		if object.IsNull(msg) {
+			return className
+		}
+		switch value := msg.FieldTable["value"].Fvalue.(type) {
+		case []byte:
+			return fmt.Sprintf("%s: %s", className, string(value))
+		case uint32:
+			return fmt.Sprintf("%s: %s", className, *stringPool.GetStringPointer(value))
+		}
+	}
+	return className
+}
+
+// StackTraceLines returns the "at class.method(file:line)" lines for the
+// stack trace elements of a Throwable, skipping constructors and Throwable's
+// own methods. A Throwable without a stack trace yields no lines.
+func StackTraceLines(throwable *object.Object) []string {
+	var lines []string
+	for _, ste := range visibleStackTraceElements(throwable) {
+		methodName := ste.FieldTable["methodName"].Fvalue.(string)
+		className := strings.Replace(ste.FieldTable["declaringClass"].Fvalue.(string), "/", ".", -1)
+
+		sourceLine := ste.FieldTable["sourceLine"].Fvalue.(string)
+		if sourceLine != "" {
+			lines = append(lines, fmt.Sprintf("\tat %s.%s(%s:%s)", className,
+				methodName, ste.FieldTable["fileName"].Fvalue, sourceLine))
+		} else {
+			lines = append(lines, fmt.Sprintf("\tat %s.%s(%s)", className,
+				methodName, ste.FieldTable["fileName"].Fvalue))
+		}
+	}
+	return lines
+}
+
+// visibleStackTraceElements returns the stack trace elements of a Throwable,
+// top of the stack first, leaving out constructors and Throwable's own methods.
+func visibleStackTraceElements(throwable *object.Object) []*object.Object {
+	var elements []*object.Object
+	steField, ok := throwable.FieldTable["stackTrace"]
+	if !ok {
+		return elements
+	}
+	steArrayPtr, ok := steField.Fvalue.(*object.Object)
+	if !ok || object.IsNull(steArrayPtr) {
+		return elements
+	}
+
+	rawSteArray := steArrayPtr.FieldTable["value"].Fvalue.([]*object.Object) // each of which is an STE
+	for _, ste := range rawSteArray {
+		if ste.FieldTable["methodName"].Fvalue.(string) == "<init>" { // don't show constructors
+			continue
+		}
+		if ste.FieldTable["declaringClass"].Fvalue.(string) == "java/lang/Throwable" { // don't show Throwable methods
+			continue
+		}
+		elements = append(elements, ste)
+	}
+	return elements
+}
+
+// SuppressedLines returns the "Suppressed:" lines, each followed by its stack
+// trace lines indented one more tab, for the suppressed exceptions of a Throwable.
+func SuppressedLines(throwable *object.Object) []string {
+	var lines []string
+	for _, suppressed := range suppressedExceptions(throwable) {
+		lines = append(lines, "\tSuppressed: "+throwableToString(suppressed))
+		for _, line := range StackTraceLines(suppressed) {
+			lines = append(lines, "\t"+line)
+		}
+	}
+	return lines
+}
+
+// CauseChainLines returns the "Caused by:" lines, each followed by its stack
+// trace lines, for every cause in the chain of a Throwable. As in the JDK,
+// a cause that has already been printed is flagged as a circular reference.
+func CauseChainLines(throwable *object.Object) []string {
+	var lines []string
+	seen := map[*object.Object]bool{throwable: true}
+	for current := throwable; !causeIsUnset(current); {
+		cause := current.FieldTable["cause"].Fvalue.(*object.Object)
+		if object.IsNull(cause) {
+			break
+		}
+		if seen[cause] {
+			lines = append(lines, fmt.Sprintf("\t[CIRCULAR REFERENCE: %s]", throwableToString(cause)))
+			break
+		}
+		seen[cause] = true
+		lines = append(lines, "Caused by: "+throwableToString(cause))
+		lines = append(lines, StackTraceLines(cause)...)
+		current = cause
+	}
+	return lines
+}
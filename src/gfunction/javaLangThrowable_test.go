@@ -8,13 +8,16 @@ package gfunction
 
 import (
 	"container/list"
+	"io"
 	"jacobin/classloader"
+	"jacobin/excNames"
 	"jacobin/frames"
 	"jacobin/globals"
 	"jacobin/log"
 	"jacobin/object"
 	"jacobin/statics"
 	"jacobin/stringPool"
+	"os"
 	"strings"
 	"testing"
 )
@@ -190,3 +193,224 @@ func InstantiateFillIn(name string, _ *list.List) (any, error) {
 	o.KlassName = stringPool.GetStringIndex(&name)
 	return o, nil
 }
+
+func TestJavaLangThrowableCauseChain(t *testing.T) {
+	globals.InitGlobals("test")
+
+	innerName := "java/lang/IllegalStateException"
+	inner := object.MakeEmptyObjectWithClassName(&innerName)
+	inner.FieldTable["detailMessage"] = object.Field{Ftype: "L", Fvalue: object.StringObjectFromGoString("inner")}
+
+	outerName := "java/lang/RuntimeException"
+	outer := object.MakeEmptyObjectWithClassName(&outerName)
+	outer.FieldTable["detailMessage"] = object.Field{Ftype: "L", Fvalue: object.StringObjectFromGoString("outer")}
+
+	if throwableGetCause([]interface{}{outer}) != object.Null {
+		t.Errorf("Expected a nil cause before initCause()")
+	}
+
+	ret := throwableInitCause([]interface{}{outer, inner})
+	if ret != outer {
+		t.Errorf("Expected initCause() to return the Throwable itself, got: %v", ret)
+	}
+
+	cause := throwableGetCause([]interface{}{outer})
+	if cause != inner {
+		t.Errorf("Expected getCause() to return the inner exception, got: %v", cause)
+	}
+
+	lines := CauseChainLines(outer)
+	if len(lines) != 1 || lines[0] != "Caused by: java.lang.IllegalStateException: inner" {
+		t.Errorf("Unexpected cause chain: %v", lines)
+	}
+
+	// printStackTrace() should print the outer exception followed by the chain of causes
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	throwablePrintStackTrace([]interface{}{outer})
+
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stderr = normalStderr
+
+	expected := "java.lang.RuntimeException: outer\nCaused by: java.lang.IllegalStateException: inner\n"
+	if string(out) != expected {
+		t.Errorf("Expected printStackTrace() output %q, got: %q", expected, string(out))
+	}
+}
+
+func TestJavaLangThrowableInitCauseTwice(t *testing.T) {
+	globals.InitGlobals("test")
+
+	name := "java/lang/Exception"
+	outer := object.MakeEmptyObjectWithClassName(&name)
+	inner := object.MakeEmptyObjectWithClassName(&name)
+
+	_ = throwableInitCause([]interface{}{outer, inner})
+	ret := throwableInitCause([]interface{}{outer, inner})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.IllegalStateException {
+		t.Errorf("Expected IllegalStateException on second initCause(), got: %v", ret)
+	}
+
+	// a cause explicitly set to null in the constructor cannot be overwritten either
+	other := object.MakeEmptyObjectWithClassName(&name)
+	other.FieldTable["cause"] = object.Field{Ftype: "L", Fvalue: object.Null}
+	ret = throwableInitCause([]interface{}{other, inner})
+	errBlk, ok = ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.IllegalStateException {
+		t.Errorf("Expected IllegalStateException on initCause() after null cause, got: %v", ret)
+	}
+}
+
+func TestJavaLangThrowableSelfCausation(t *testing.T) {
+	globals.InitGlobals("test")
+
+	name := "java/lang/Exception"
+	throwable := object.MakeEmptyObjectWithClassName(&name)
+
+	ret := throwableInitCause([]interface{}{throwable, throwable})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Expected IllegalArgumentException on self-causation, got: %v", ret)
+	}
+}
+
+func TestJavaLangThrowableGetStackTrace(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	_ = log.SetLogLevel(log.SEVERE)
+	classloader.InitMethodArea()
+	globals.GetGlobalRef().FuncInstantiateClass = InstantiateFillIn
+
+	// the class holding the method that throws, whose LineNumberTable maps PC 0 to line 10
+	// and PC 4 to line 11
+	appClass := classloader.Klass{Loader: "app", Data: &classloader.ClData{SourceFile: "Thrower.java"}}
+	classloader.MethAreaInsert("app/Thrower", &appClass)
+	cp := classloader.CPool{Utf8Refs: []string{"LineNumberTable"}}
+	lineNumberTable := []byte{0, 2, 0, 0, 0, 10, 0, 4, 0, 11}
+	classloader.MTable["app/Thrower.thrower()V"] = classloader.MTentry{
+		Meth: classloader.JmEntry{
+			Attribs: []classloader.Attr{{AttrName: 0, AttrSize: len(lineNumberTable), AttrContent: lineNumberTable}},
+			Cp:      &cp,
+		},
+		MType: 'J',
+	}
+	throwableClass := classloader.Klass{Loader: "bootstrap", Data: &classloader.ClData{SourceFile: "Throwable.java"}}
+	classloader.MethAreaInsert("java/lang/Throwable", &throwableClass)
+
+	// thrower() is running at PC 6 and has called the Throwable's constructor
+	jvmStack := frames.CreateFrameStack()
+	thrower := frames.CreateFrame(2)
+	thrower.ClName = "app/Thrower"
+	thrower.MethName = "thrower"
+	thrower.MethType = "()V"
+	thrower.PC = 6
+	_ = frames.PushFrame(jvmStack, thrower)
+	ctor := frames.CreateFrame(2)
+	ctor.ClName = "java/lang/Throwable"
+	ctor.MethName = "<init>"
+	ctor.MethType = "(Ljava/lang/String;Ljava/lang/Throwable;)V"
+	_ = frames.PushFrame(jvmStack, ctor)
+
+	name := "java/lang/Throwable"
+	throwable := object.MakeEmptyObjectWithClassName(&name)
+	throwableInitStringThrowable([]interface{}{jvmStack, throwable, object.StringObjectFromGoString("boom"), object.Null})
+
+	stackTrace := throwableGetStackTrace([]interface{}{throwable}).(*object.Object)
+	elements := stackTrace.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(elements) != 1 {
+		t.Fatalf("Expected one stack trace element, got: %d", len(elements))
+	}
+
+	top := []interface{}{elements[0]}
+	if className := object.GoStringFromStringObject(steGetClassName(top).(*object.Object)); className != "app.Thrower" {
+		t.Errorf("Expected getClassName() to return app.Thrower, got: %s", className)
+	}
+	if methodName := object.GoStringFromStringObject(steGetMethodName(top).(*object.Object)); methodName != "thrower" {
+		t.Errorf("Expected getMethodName() to return thrower, got: %s", methodName)
+	}
+	if fileName := object.GoStringFromStringObject(steGetFileName(top).(*object.Object)); fileName != "Thrower.java" {
+		t.Errorf("Expected getFileName() to return Thrower.java, got: %s", fileName)
+	}
+	if line := steGetLineNumber(top).(int64); line != 11 {
+		t.Errorf("Expected getLineNumber() to return 11, got: %d", line)
+	}
+
+	// writes to the returned array do not affect later calls
+	elements[0] = nil
+	again := throwableGetStackTrace([]interface{}{throwable}).(*object.Object)
+	if again.FieldTable["value"].Fvalue.([]*object.Object)[0] == nil {
+		t.Errorf("Expected getStackTrace() to return a new array on each call")
+	}
+}
+
+// In try-with-resources, when both the body and close() throw, the exception from close()
+// is added as suppressed to the one from the body, which is the one that propagates.
+func TestJavaLangThrowableSuppressedByClose(t *testing.T) {
+	globals.InitGlobals("test")
+
+	bodyName := "java/lang/IllegalStateException"
+	body := object.MakeEmptyObjectWithClassName(&bodyName)
+	body.FieldTable["detailMessage"] = object.Field{Ftype: "L", Fvalue: object.StringObjectFromGoString("body")}
+
+	closeName := "java/io/IOException"
+	closeExc := object.MakeEmptyObjectWithClassName(&closeName)
+	closeExc.FieldTable["detailMessage"] = object.Field{Ftype: "L", Fvalue: object.StringObjectFromGoString("close")}
+
+	empty := throwableGetSuppressed([]interface{}{body}).(*object.Object)
+	if len(empty.FieldTable["value"].Fvalue.([]*object.Object)) != 0 {
+		t.Errorf("Expected no suppressed exceptions before addSuppressed()")
+	}
+
+	if ret := throwableAddSuppressed([]interface{}{body, closeExc}); ret != nil {
+		t.Fatalf("Unexpected error from addSuppressed(): %v", ret)
+	}
+	suppressed := throwableGetSuppressed([]interface{}{body}).(*object.Object)
+	elements := suppressed.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(elements) != 1 || elements[0] != closeExc {
+		t.Errorf("Expected getSuppressed() to return the exception from close(), got: %v", elements)
+	}
+
+	// the returned array is a copy
+	elements[0] = body
+	if suppressedExceptions(body)[0] != closeExc {
+		t.Errorf("Writing to the array from getSuppressed() changed the suppressed exceptions")
+	}
+
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	throwablePrintStackTrace([]interface{}{body})
+
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stderr = normalStderr
+
+	expected := "java.lang.IllegalStateException: body\n\tSuppressed: java.io.IOException: close\n"
+	if string(out) != expected {
+		t.Errorf("Expected printStackTrace() output %q, got: %q", expected, string(out))
+	}
+}
+
+func TestJavaLangThrowableAddSuppressedInvalid(t *testing.T) {
+	globals.InitGlobals("test")
+
+	name := "java/lang/Exception"
+	throwable := object.MakeEmptyObjectWithClassName(&name)
+
+	ret := throwableAddSuppressed([]interface{}{throwable, throwable})
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Expected IllegalArgumentException on self-suppression, got: %v", ret)
+	}
+
+	ret = throwableAddSuppressed([]interface{}{throwable, object.Null})
+	errBlk, ok = ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException on suppressing null, got: %v", ret)
+	}
+}
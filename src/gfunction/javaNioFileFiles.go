@@ -0,0 +1,149 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/types"
+	"os"
+)
+
+// Implementation of some of the methods of java/nio/file/Files, using golang's os.ReadFile()
+// and os.WriteFile(). The files are read and written whole. The OpenOptions and LinkOptions
+// passed to the methods are ignored: files are written as with the default options, which
+// create the file or truncate it, and exists() follows symbolic links.
+
+func Load_Nio_File_Files() {
+
+	MethodSignatures["java/nio/file/Files.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/nio/file/Files.exists(Ljava/nio/file/Path;[Ljava/nio/file/LinkOption;)Z"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  filesExists,
+		}
+
+	MethodSignatures["java/nio/file/Files.readAllBytes(Ljava/nio/file/Path;)[B"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  filesReadAllBytes,
+		}
+
+	MethodSignatures["java/nio/file/Files.readString(Ljava/nio/file/Path;)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  filesReadString,
+		}
+
+	MethodSignatures["java/nio/file/Files.write(Ljava/nio/file/Path;[B[Ljava/nio/file/OpenOption;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  filesWrite,
+		}
+
+	MethodSignatures["java/nio/file/Files.writeString(Ljava/nio/file/Path;Ljava/lang/CharSequence;[Ljava/nio/file/OpenOption;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  filesWriteString,
+		}
+
+}
+
+// filesReadFile reads the whole of the file at a Path. As in Java, a missing file is a
+// NoSuchFileException, whose message is the path.
+func filesReadFile(pathObj interface{}) ([]byte, *GErrBlk) {
+	path, errBlk := pathFromObject(pathObj)
+	if errBlk != nil {
+		return nil, errBlk
+	}
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, getGErrBlk(excNames.NoSuchFileException, path)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("os.ReadFile(%s) failed, reason: %s", path, err.Error())
+		return nil, getGErrBlk(excNames.IOException, errMsg)
+	}
+	return bytes, nil
+}
+
+// filesWriteFile replaces the contents of the file at a Path, creating it if need be, and
+// returns the Path
+func filesWriteFile(pathObj interface{}, bytes []byte) interface{} {
+	path, errBlk := pathFromObject(pathObj)
+	if errBlk != nil {
+		return errBlk
+	}
+	err := os.WriteFile(path, bytes, 0666)
+	if errors.Is(err, fs.ErrNotExist) { // the directory doesn't exist
+		return getGErrBlk(excNames.NoSuchFileException, path)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("os.WriteFile(%s) failed, reason: %s", path, err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return pathObj
+}
+
+// "java/nio/file/Files.exists(Ljava/nio/file/Path;[Ljava/nio/file/LinkOption;)Z"
+func filesExists(params []interface{}) interface{} {
+	path, errBlk := pathFromObject(params[0])
+	if errBlk != nil {
+		return errBlk
+	}
+	if _, err := os.Stat(path); err != nil {
+		return types.JavaBoolFalse
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/nio/file/Files.readAllBytes(Ljava/nio/file/Path;)[B"
+func filesReadAllBytes(params []interface{}) interface{} {
+	bytes, errBlk := filesReadFile(params[0])
+	if errBlk != nil {
+		return errBlk
+	}
+	return object.MakeArrayFromRawArray(bytes)
+}
+
+// "java/nio/file/Files.readString(Ljava/nio/file/Path;)Ljava/lang/String;" The file must
+// be UTF-8, as golang strings are.
+func filesReadString(params []interface{}) interface{} {
+	bytes, errBlk := filesReadFile(params[0])
+	if errBlk != nil {
+		return errBlk
+	}
+	return object.StringObjectFromGoString(string(bytes))
+}
+
+// "java/nio/file/Files.write(Ljava/nio/file/Path;[B[Ljava/nio/file/OpenOption;)Ljava/nio/file/Path;"
+func filesWrite(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Files.write: byte array is null")
+	}
+	return filesWriteFile(params[0], params[1].(*object.Object).FieldTable["value"].Fvalue.([]byte))
+}
+
+// "java/nio/file/Files.writeString(Ljava/nio/file/Path;Ljava/lang/CharSequence;[Ljava/nio/file/OpenOption;)Ljava/nio/file/Path;"
+func filesWriteString(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Files.writeString: CharSequence is null")
+	}
+	str, ok := object.CharSequenceToGoString(params[1].(*object.Object))
+	if !ok {
+		return getGErrBlk(excNames.IllegalArgumentException, "Files.writeString: argument is not a CharSequence")
+	}
+	return filesWriteFile(params[0], []byte(str))
+}
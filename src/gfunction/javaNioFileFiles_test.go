@@ -0,0 +1,72 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"path/filepath"
+	"testing"
+)
+
+// a string written to a temp file with Files.writeString() is read back by Files.readString()
+// and, as bytes, by Files.readAllBytes()
+func TestFilesWriteAndReadRoundTrip(t *testing.T) {
+	globals.InitGlobals("test")
+	dir := t.TempDir()
+	text := "It was a graveyard smash! é\U0001F600"
+
+	stringClassName := "java/lang/String"
+	more := object.Make1DimRefArray(&stringClassName, 1)
+	more.FieldTable["value"].Fvalue.([]*object.Object)[0] = object.StringObjectFromGoString("monster.txt")
+	path := pathOf([]interface{}{object.StringObjectFromGoString(dir), more}).(*object.Object)
+	want := filepath.Join(dir, "monster.txt")
+	if str := object.GoStringFromStringObject(pathToString([]interface{}{path}).(*object.Object)); str != want {
+		t.Fatalf("Paths.get(): expected %s, got %s", want, str)
+	}
+
+	// a Path is an object of a concrete class, on which toString() is registered
+	Load_Nio_File_Paths()
+	className := object.GoStringFromStringPoolIndex(path.KlassName)
+	if _, ok := MethodSignatures[className+".toString()Ljava/lang/String;"]; !ok || className == "java/nio/file/Path" {
+		t.Errorf("Paths.get(): expected a Path of a class with a toString() gfunction, got %s", className)
+	}
+
+	if ret := filesExists([]interface{}{path, object.Null}); ret != types.JavaBoolFalse {
+		t.Errorf("Files.exists() before writing: expected false, got %v", ret)
+	}
+	if ret := filesWriteString([]interface{}{path, object.StringObjectFromGoString(text), object.Null}); ret != path {
+		t.Fatalf("Files.writeString(): expected the Path to be returned, got %v", ret)
+	}
+	if ret := filesExists([]interface{}{path, object.Null}); ret != types.JavaBoolTrue {
+		t.Errorf("Files.exists() after writing: expected true, got %v", ret)
+	}
+
+	ret := filesReadString([]interface{}{path})
+	if str := object.GoStringFromStringObject(ret.(*object.Object)); str != text {
+		t.Errorf("Files.readString(): expected %q, got %q", text, str)
+	}
+
+	filesWrite([]interface{}{path, object.MakeArrayFromRawArray([]byte{1, 2, 3}), object.Null})
+	ret = filesReadAllBytes([]interface{}{path})
+	if bytes := ret.(*object.Object).FieldTable["value"].Fvalue.([]byte); string(bytes) != "\x01\x02\x03" {
+		t.Errorf("Files.readAllBytes(): expected [1 2 3], got %v", bytes)
+	}
+}
+
+func TestFilesReadMissingFile(t *testing.T) {
+	globals.InitGlobals("test")
+	path := makePathObject(filepath.Join(t.TempDir(), "no-such-file"))
+
+	for _, ret := range []interface{}{filesReadString([]interface{}{path}), filesReadAllBytes([]interface{}{path})} {
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NoSuchFileException {
+			t.Errorf("reading a missing file: expected a NoSuchFileException, got %v", ret)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/types"
+	"path/filepath"
+	"runtime"
+)
+
+// Implementation of java/nio/file/Paths and a minimal java/nio/file/Path. As Path is an
+// interface, a Path is an object of the class that implements it in the JDK's default file
+// system: sun/nio/fs/UnixPath or, on Windows, sun/nio/fs/WindowsPath. Like a java/io/File,
+// it holds its path string in its FilePath field. This is enough for the methods of
+// java/nio/file/Files in javaNioFileFiles.go.
+
+const unixPathClassName = "sun/nio/fs/UnixPath"
+const windowsPathClassName = "sun/nio/fs/WindowsPath"
+
+func Load_Nio_File_Paths() {
+
+	MethodSignatures["java/nio/file/Paths.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/nio/file/Paths.get(Ljava/lang/String;[Ljava/lang/String;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  pathOf,
+		}
+
+	MethodSignatures["java/nio/file/Path.of(Ljava/lang/String;[Ljava/lang/String;)Ljava/nio/file/Path;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  pathOf,
+		}
+
+	for _, className := range []string{unixPathClassName, windowsPathClassName} {
+		MethodSignatures[className+".toString()Ljava/lang/String;"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  pathToString,
+			}
+	}
+
+}
+
+// makePathObject returns a Path holding the given path string
+func makePathObject(path string) *object.Object {
+	className := unixPathClassName
+	if runtime.GOOS == "windows" {
+		className = windowsPathClassName
+	}
+	obj := object.MakeEmptyObjectWithClassName(&className)
+	obj.FieldTable[FilePath] = object.Field{Ftype: types.ByteArray, Fvalue: []byte(path)}
+	return obj
+}
+
+// pathFromObject returns the path string held by a Path
+func pathFromObject(pathObj interface{}) (string, *GErrBlk) {
+	if object.IsNull(pathObj) {
+		return "", getGErrBlk(excNames.NullPointerException, "Path is null")
+	}
+	bytes, ok := pathObj.(*object.Object).FieldTable[FilePath].Fvalue.([]byte)
+	if !ok {
+		return "", getGErrBlk(excNames.IllegalArgumentException, "Path object lacks a FilePath field")
+	}
+	return string(bytes), nil
+}
+
+// "java/nio/file/Paths.get(Ljava/lang/String;[Ljava/lang/String;)Ljava/nio/file/Path;" and
+// "java/nio/file/Path.of(Ljava/lang/String;[Ljava/lang/String;)Ljava/nio/file/Path;" join the
+// first string and the more strings into a path
+func pathOf(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "Paths.get: first is null")
+	}
+	elements := []string{object.GoStringFromStringObject(params[0].(*object.Object))}
+	if !object.IsNull(params[1]) {
+		for _, more := range params[1].(*object.Object).FieldTable["value"].Fvalue.([]*object.Object) {
+			if object.IsNull(more) {
+				return getGErrBlk(excNames.NullPointerException, "Paths.get: more contains null")
+			}
+			elements = append(elements, object.GoStringFromStringObject(more))
+		}
+	}
+
+	var path string
+	for _, element := range elements {
+		if element != "" { // as in Java, empty strings are ignored
+			path = filepath.Join(path, element)
+		}
+	}
+	return makePathObject(path)
+}
+
+// "sun/nio/fs/UnixPath.toString()Ljava/lang/String;" and the same method of WindowsPath
+func pathToString(params []interface{}) interface{} {
+	path, errBlk := pathFromObject(params[0])
+	if errBlk != nil {
+		return errBlk
+	}
+	return object.StringObjectFromGoString(path)
+}
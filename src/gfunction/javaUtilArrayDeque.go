@@ -0,0 +1,433 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/types"
+	"math"
+)
+
+// Implementation of java.util.ArrayDeque, which can be used as a stack (push, pop, peek)
+// or as a queue (offer, poll, peek), or as both ends of a double-ended queue.
+//
+// The elements are kept as in the JDK: in the deque object's "elements" field, an array
+// used as a ring buffer, of which "head" is the index of the first element and "tail" the
+// index at which the next element will be added at the back. The array always has at least
+// one empty (null) slot, so the deque is empty when head equals tail, and it grows as the
+// JDK's does when an insertion fills it. Because the state is the JDK's, the methods that
+// are not implemented here (such as iterator(), contains(), remove(Object), forEach(), and
+// toString()) run as the JDK's bytecode and see the same elements. As in the JDK, null
+// elements are not allowed; the poll and peek methods return null when the deque is empty,
+// while the remove, get, and pop methods throw a NoSuchElementException.
+
+const arrayDequeClassName = "java/util/ArrayDeque"
+const arrayDequeMinCapacity = 16
+
+func Load_Util_ArrayDeque() {
+
+	MethodSignatures[arrayDequeClassName+".<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures[arrayDequeClassName+".<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeInit,
+		}
+
+	MethodSignatures[arrayDequeClassName+".<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arrayDequeInitCapacity,
+		}
+
+	MethodSignatures[arrayDequeClassName+".add(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arrayDequeOfferLast,
+		}
+
+	MethodSignatures[arrayDequeClassName+".addFirst(Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arrayDequeAddFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".addLast(Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arrayDequeAddLast,
+		}
+
+	MethodSignatures[arrayDequeClassName+".clear()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeClear,
+		}
+
+	MethodSignatures[arrayDequeClassName+".element()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeGetFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".getFirst()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeGetFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".getLast()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeGetLast,
+		}
+
+	MethodSignatures[arrayDequeClassName+".isEmpty()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeIsEmpty,
+		}
+
+	MethodSignatures[arrayDequeClassName+".offer(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arrayDequeOfferLast,
+		}
+
+	MethodSignatures[arrayDequeClassName+".offerFirst(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arrayDequeOfferFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".offerLast(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arrayDequeOfferLast,
+		}
+
+	MethodSignatures[arrayDequeClassName+".peek()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequePeekFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".peekFirst()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequePeekFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".peekLast()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequePeekLast,
+		}
+
+	MethodSignatures[arrayDequeClassName+".poll()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequePollFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".pollFirst()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequePollFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".pollLast()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequePollLast,
+		}
+
+	MethodSignatures[arrayDequeClassName+".pop()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeRemoveFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".push(Ljava/lang/Object;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arrayDequeAddFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".remove()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeRemoveFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".removeFirst()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeRemoveFirst,
+		}
+
+	MethodSignatures[arrayDequeClassName+".removeLast()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeRemoveLast,
+		}
+
+	MethodSignatures[arrayDequeClassName+".size()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayDequeSize,
+		}
+}
+
+// "java/util/ArrayDeque.<init>()V" As in the JDK, the array has room for 16 elements,
+// plus the slot that is always empty.
+func arrayDequeInit(params []interface{}) interface{} {
+	arrayDequeSetElements(params[0].(*object.Object), arrayDequeMinCapacity+1, nil)
+	return nil
+}
+
+// "java/util/ArrayDeque.<init>(I)V" As in the JDK, a negative or zero capacity is treated
+// as a capacity of one slot.
+func arrayDequeInitCapacity(params []interface{}) interface{} {
+	capacity := params[1].(int64)
+	switch {
+	case capacity < 1:
+		capacity = 1
+	case capacity < math.MaxInt32:
+		capacity++
+	}
+	arrayDequeSetElements(params[0].(*object.Object), capacity, nil)
+	return nil
+}
+
+// arrayDequeSetElements gives a deque a new array of the given length, holding the given
+// elements from its start, and sets head and tail accordingly
+func arrayDequeSetElements(deque *object.Object, length int64, elements []*object.Object) {
+	elementType := "java/lang/Object"
+	array := object.Make1DimRefArray(&elementType, length)
+	copy(array.FieldTable["value"].Fvalue.([]*object.Object), elements)
+	deque.FieldTable["elements"] = object.Field{Ftype: types.RefArray + "java/lang/Object", Fvalue: array}
+	deque.FieldTable["head"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	deque.FieldTable["tail"] = object.Field{Ftype: types.Int, Fvalue: int64(len(elements))}
+}
+
+// arrayDequeFields returns the array, head, and tail of a deque
+func arrayDequeFields(deque *object.Object) ([]*object.Object, int64, int64) {
+	array := deque.FieldTable["elements"].Fvalue.(*object.Object)
+	head, _ := deque.FieldTable["head"].Fvalue.(int64)
+	tail, _ := deque.FieldTable["tail"].Fvalue.(int64)
+	return array.FieldTable["value"].Fvalue.([]*object.Object), head, tail
+}
+
+// arrayDequeLength returns the number of elements in a deque
+func arrayDequeLength(deque *object.Object) int64 {
+	es, head, tail := arrayDequeFields(deque)
+	return arrayDequeSub(tail, head, int64(len(es)))
+}
+
+// the JDK's inc(), dec(), and sub(): index arithmetic modulo the length of the array
+func arrayDequeInc(i, length int64) int64 {
+	if i++; i >= length {
+		i = 0
+	}
+	return i
+}
+
+func arrayDequeDec(i, length int64) int64 {
+	if i--; i < 0 {
+		i = length - 1
+	}
+	return i
+}
+
+func arrayDequeSub(i, j, length int64) int64 {
+	if i -= j; i < 0 {
+		i += length
+	}
+	return i
+}
+
+// arrayDequeGrow is called when an insertion has filled every slot of the array. As in the
+// JDK, the capacity grows by half (or, while it is small, doubles); the elements are moved
+// to the start of the new array.
+func arrayDequeGrow(deque *object.Object) {
+	es, head, _ := arrayDequeFields(deque)
+	length := int64(len(es))
+	jump := length >> 1
+	if length < 64 {
+		jump = length + 2
+	}
+	elements := make([]*object.Object, 0, length)
+	elements = append(elements, es[head:]...)
+	elements = append(elements, es[:head]...)
+	arrayDequeSetElements(deque, length+jump, elements)
+}
+
+// arrayDequeInsert adds an element at the front or the back of a deque. Returns an error
+// block if the element is null, as ArrayDeque does not allow null elements.
+func arrayDequeInsert(params []interface{}, method string, atFront bool) *GErrBlk {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "ArrayDeque."+method+": element is null")
+	}
+	deque := params[0].(*object.Object)
+	es, head, tail := arrayDequeFields(deque)
+	length := int64(len(es))
+	if atFront {
+		head = arrayDequeDec(head, length)
+		es[head] = params[1].(*object.Object)
+		deque.FieldTable["head"] = object.Field{Ftype: types.Int, Fvalue: head}
+	} else {
+		es[tail] = params[1].(*object.Object)
+		tail = arrayDequeInc(tail, length)
+		deque.FieldTable["tail"] = object.Field{Ftype: types.Int, Fvalue: tail}
+	}
+	if head == tail {
+		arrayDequeGrow(deque)
+	}
+	return nil
+}
+
+// arrayDequeTake returns the element at the front or the back of a deque, removing it if
+// remove is true, or nil if the deque is empty.
+func arrayDequeTake(params []interface{}, atFront bool, remove bool) *object.Object {
+	deque := params[0].(*object.Object)
+	es, head, tail := arrayDequeFields(deque)
+	if head == tail {
+		return nil
+	}
+	length := int64(len(es))
+	index := arrayDequeDec(tail, length)
+	if atFront {
+		index = head
+	}
+	element := es[index]
+	if remove {
+		es[index] = nil
+		if atFront {
+			deque.FieldTable["head"] = object.Field{Ftype: types.Int, Fvalue: arrayDequeInc(head, length)}
+		} else {
+			deque.FieldTable["tail"] = object.Field{Ftype: types.Int, Fvalue: index}
+		}
+	}
+	return element
+}
+
+// "java/util/ArrayDeque.addFirst(Ljava/lang/Object;)V" also push()
+func arrayDequeAddFirst(params []interface{}) interface{} {
+	if errBlk := arrayDequeInsert(params, "addFirst", true); errBlk != nil {
+		return errBlk
+	}
+	return nil
+}
+
+// "java/util/ArrayDeque.addLast(Ljava/lang/Object;)V"
+func arrayDequeAddLast(params []interface{}) interface{} {
+	if errBlk := arrayDequeInsert(params, "addLast", false); errBlk != nil {
+		return errBlk
+	}
+	return nil
+}
+
+// "java/util/ArrayDeque.offerFirst(Ljava/lang/Object;)Z"
+func arrayDequeOfferFirst(params []interface{}) interface{} {
+	if errBlk := arrayDequeInsert(params, "offerFirst", true); errBlk != nil {
+		return errBlk
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/ArrayDeque.offerLast(Ljava/lang/Object;)Z" also add() and offer()
+func arrayDequeOfferLast(params []interface{}) interface{} {
+	if errBlk := arrayDequeInsert(params, "offerLast", false); errBlk != nil {
+		return errBlk
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/ArrayDeque.peekFirst()Ljava/lang/Object;" also peek()
+func arrayDequePeekFirst(params []interface{}) interface{} {
+	return arrayDequeOrNull(arrayDequeTake(params, true, false))
+}
+
+// "java/util/ArrayDeque.peekLast()Ljava/lang/Object;"
+func arrayDequePeekLast(params []interface{}) interface{} {
+	return arrayDequeOrNull(arrayDequeTake(params, false, false))
+}
+
+// "java/util/ArrayDeque.pollFirst()Ljava/lang/Object;" also poll()
+func arrayDequePollFirst(params []interface{}) interface{} {
+	return arrayDequeOrNull(arrayDequeTake(params, true, true))
+}
+
+// "java/util/ArrayDeque.pollLast()Ljava/lang/Object;"
+func arrayDequePollLast(params []interface{}) interface{} {
+	return arrayDequeOrNull(arrayDequeTake(params, false, true))
+}
+
+// "java/util/ArrayDeque.getFirst()Ljava/lang/Object;" also element()
+func arrayDequeGetFirst(params []interface{}) interface{} {
+	return arrayDequeOrThrow(arrayDequeTake(params, true, false), "getFirst")
+}
+
+// "java/util/ArrayDeque.getLast()Ljava/lang/Object;"
+func arrayDequeGetLast(params []interface{}) interface{} {
+	return arrayDequeOrThrow(arrayDequeTake(params, false, false), "getLast")
+}
+
+// "java/util/ArrayDeque.removeFirst()Ljava/lang/Object;" also pop() and remove()
+func arrayDequeRemoveFirst(params []interface{}) interface{} {
+	return arrayDequeOrThrow(arrayDequeTake(params, true, true), "removeFirst")
+}
+
+// "java/util/ArrayDeque.removeLast()Ljava/lang/Object;"
+func arrayDequeRemoveLast(params []interface{}) interface{} {
+	return arrayDequeOrThrow(arrayDequeTake(params, false, true), "removeLast")
+}
+
+func arrayDequeOrNull(element *object.Object) interface{} {
+	if element == nil {
+		return object.Null
+	}
+	return element
+}
+
+func arrayDequeOrThrow(element *object.Object, method string) interface{} {
+	if element == nil {
+		return getGErrBlk(excNames.NoSuchElementException, "ArrayDeque."+method+": deque is empty")
+	}
+	return element
+}
+
+// "java/util/ArrayDeque.clear()V"
+func arrayDequeClear(params []interface{}) interface{} {
+	deque := params[0].(*object.Object)
+	es, _, _ := arrayDequeFields(deque)
+	for i := range es {
+		es[i] = nil
+	}
+	deque.FieldTable["head"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	deque.FieldTable["tail"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	return nil
+}
+
+// "java/util/ArrayDeque.isEmpty()Z"
+func arrayDequeIsEmpty(params []interface{}) interface{} {
+	if arrayDequeLength(params[0].(*object.Object)) == 0 {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/ArrayDeque.size()I"
+func arrayDequeSize(params []interface{}) interface{} {
+	return arrayDequeLength(params[0].(*object.Object))
+}
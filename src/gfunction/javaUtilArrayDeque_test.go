@@ -0,0 +1,172 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"testing"
+)
+
+func makeTestArrayDeque() *object.Object {
+	className := arrayDequeClassName
+	deque := object.MakeEmptyObjectWithClassName(&className)
+	arrayDequeInit([]interface{}{deque})
+	return deque
+}
+
+// makeTestDequeElements returns n distinct String elements: "e0", "e1", ...
+func makeTestDequeElements(n int) []*object.Object {
+	var elements []*object.Object
+	for i := 0; i < n; i++ {
+		elements = append(elements, object.StringObjectFromGoString(fmt.Sprintf("e%d", i)))
+	}
+	return elements
+}
+
+// as a stack: push and pop are last in, first out
+func TestArrayDequeAsStack(t *testing.T) {
+	globals.InitGlobals("test")
+	deque := makeTestArrayDeque()
+	elements := makeTestDequeElements(40) // more than the initial capacity, so the deque grows
+
+	for _, e := range elements {
+		if ret := arrayDequeAddFirst([]interface{}{deque, e}); ret != nil {
+			t.Fatalf("Expected push to succeed, got: %v", ret)
+		}
+	}
+	if size := arrayDequeSize([]interface{}{deque}); size != int64(40) {
+		t.Errorf("Expected size 40 after the pushes, got: %v", size)
+	}
+	if top := arrayDequePeekFirst([]interface{}{deque}); top != elements[39] {
+		t.Errorf("Expected peek to return the last element pushed, got: %v", top)
+	}
+
+	for i := 39; i >= 0; i-- {
+		if ret := arrayDequeRemoveFirst([]interface{}{deque}); ret != elements[i] {
+			t.Fatalf("Expected pop to return element %d, got: %v", i, ret)
+		}
+	}
+	if arrayDequeIsEmpty([]interface{}{deque}) != types.JavaBoolTrue {
+		t.Errorf("Expected the deque to be empty after popping every element")
+	}
+
+	ret := arrayDequeRemoveFirst([]interface{}{deque})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NoSuchElementException {
+		t.Errorf("Expected pop of an empty deque to throw NoSuchElementException, got: %v", ret)
+	}
+}
+
+// as a queue: offerLast and pollFirst are first in, first out, also when the ring wraps around
+func TestArrayDequeAsQueue(t *testing.T) {
+	globals.InitGlobals("test")
+	deque := makeTestArrayDeque()
+	elements := makeTestDequeElements(50)
+
+	next := 0 // the next element expected from pollFirst
+	for i, e := range elements {
+		if ret := arrayDequeOfferLast([]interface{}{deque, e}); ret != types.JavaBoolTrue {
+			t.Fatalf("Expected offerLast to return true, got: %v", ret)
+		}
+		if i%3 == 2 { // take one out for every three put in
+			if ret := arrayDequePollFirst([]interface{}{deque}); ret != elements[next] {
+				t.Fatalf("Expected pollFirst to return element %d, got: %v", next, ret)
+			}
+			next++
+		}
+	}
+	if last := arrayDequePeekLast([]interface{}{deque}); last != elements[49] {
+		t.Errorf("Expected peekLast to return the last element offered, got: %v", last)
+	}
+	for ; next < len(elements); next++ {
+		if ret := arrayDequePollFirst([]interface{}{deque}); ret != elements[next] {
+			t.Fatalf("Expected pollFirst to return element %d, got: %v", next, ret)
+		}
+	}
+
+	if ret := arrayDequePollFirst([]interface{}{deque}); ret != object.Null {
+		t.Errorf("Expected pollFirst of an empty deque to return null, got: %v", ret)
+	}
+	if ret := arrayDequePeekFirst([]interface{}{deque}); ret != object.Null {
+		t.Errorf("Expected peekFirst of an empty deque to return null, got: %v", ret)
+	}
+}
+
+func TestArrayDequeBothEnds(t *testing.T) {
+	globals.InitGlobals("test")
+	deque := makeTestArrayDeque()
+	e := makeTestDequeElements(4)
+
+	arrayDequeAddLast([]interface{}{deque, e[1]})
+	arrayDequeOfferFirst([]interface{}{deque, e[0]})
+	arrayDequeAddLast([]interface{}{deque, e[2]})
+	arrayDequeOfferLast([]interface{}{deque, e[3]}) // e0 e1 e2 e3
+
+	if ret := arrayDequePollLast([]interface{}{deque}); ret != e[3] {
+		t.Errorf("Expected pollLast to return e3, got: %v", ret)
+	}
+	if ret := arrayDequeRemoveLast([]interface{}{deque}); ret != e[2] {
+		t.Errorf("Expected removeLast to return e2, got: %v", ret)
+	}
+	if ret := arrayDequeGetFirst([]interface{}{deque}); ret != e[0] {
+		t.Errorf("Expected getFirst to return e0, got: %v", ret)
+	}
+	if ret := arrayDequeGetLast([]interface{}{deque}); ret != e[1] {
+		t.Errorf("Expected getLast to return e1, got: %v", ret)
+	}
+
+	ret := arrayDequeAddFirst([]interface{}{deque, object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected addFirst of null to throw NullPointerException, got: %v", ret)
+	}
+
+	arrayDequeClear([]interface{}{deque})
+	if size := arrayDequeSize([]interface{}{deque}); size != int64(0) {
+		t.Errorf("Expected size 0 after clear, got: %v", size)
+	}
+	ret = arrayDequeGetLast([]interface{}{deque})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NoSuchElementException {
+		t.Errorf("Expected getLast of an empty deque to throw NoSuchElementException, got: %v", ret)
+	}
+}
+
+// The elements are kept in the JDK's fields, so that the JDK's own methods, such as iterator(),
+// which walks the array from head to tail, see them in order, also once the ring wraps around.
+func TestArrayDequeKeepsJDKFields(t *testing.T) {
+	globals.InitGlobals("test")
+	deque := makeTestArrayDeque()
+	e := makeTestDequeElements(20)
+
+	arrayDequeAddFirst([]interface{}{deque, e[0]})
+	es, head, tail := arrayDequeFields(deque)
+	if len(es) != arrayDequeMinCapacity+1 || head != int64(arrayDequeMinCapacity) || tail != 0 || es[head] != e[0] {
+		t.Fatalf("Expected e0 at the end of an array of 17, with tail 0, got: head %d, tail %d, %v", head, tail, es)
+	}
+
+	for _, element := range e[1:] { // fills the array, so it grows
+		arrayDequeAddLast([]interface{}{deque, element})
+	}
+	es, head, tail = arrayDequeFields(deque)
+	var walked []*object.Object
+	for i := head; i != tail; i = arrayDequeInc(i, int64(len(es))) {
+		walked = append(walked, es[i])
+	}
+	if len(walked) != len(e) {
+		t.Fatalf("Expected %d elements from head to tail, got: %d", len(e), len(walked))
+	}
+	for i := range e {
+		if walked[i] != e[i] {
+			t.Errorf("Expected element %d from head to tail to be e%d, got: %v", i, i, walked[i])
+		}
+	}
+	if es[tail] != nil {
+		t.Errorf("Expected the slot at tail to be empty, got: %v", es[tail])
+	}
+}
@@ -0,0 +1,299 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/stringPool"
+	"jacobin/types"
+	"strings"
+)
+
+// Implementation of some of the functions in java/util/ArrayList.
+//
+// The ArrayList itself is the JDK's Java implementation, so the functions here work directly
+// on its fields: the elements are the first size entries of the elementData array, and
+// modCount is bumped each time the list is structurally modified. A subclass of ArrayList
+// inherits these fields, so the functions work on its instances too. The collection passed to
+// addAll() can be any Collection (see collectionElements). The methods that search the list
+// compare elements as Java does, with equals().
+
+const arrayListClassName = "java/util/ArrayList"
+
+func Load_Util_ArrayList() {
+
+	MethodSignatures[arrayListClassName+".addAll(Ljava/util/Collection;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arrayListAddAll,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[arrayListClassName+".addAll(ILjava/util/Collection;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    arrayListAddAllAt,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[arrayListClassName+".contains(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arrayListContains,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[arrayListClassName+".indexOf(Ljava/lang/Object;)I"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arrayListIndexOf,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[arrayListClassName+".lastIndexOf(Ljava/lang/Object;)I"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arrayListLastIndexOf,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[arrayListClassName+".remove(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    arrayListRemoveObject,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[arrayListClassName+".toArray()[Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  arrayListToArray,
+		}
+
+	MethodSignatures[arrayListClassName+".toArray([Ljava/lang/Object;)[Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arrayListToTypedArray,
+		}
+}
+
+// isArrayList reports whether an object is an ArrayList or an instance of a subclass of it
+func isArrayList(obj *object.Object) bool {
+	for name := object.GoStringFromStringPoolIndex(obj.KlassName); name != types.ObjectClassName; {
+		if name == arrayListClassName {
+			return true
+		}
+		k := classloader.MethAreaFetch(name)
+		if k == nil || k.Data == nil {
+			return false
+		}
+		name = *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+	}
+	return false
+}
+
+// arrayListElements returns the elements of an ArrayList: the first size entries of its
+// elementData array
+func arrayListElements(arrayList *object.Object) []*object.Object {
+	elementData, _ := arrayList.FieldTable["elementData"].Fvalue.(*object.Object)
+	size, _ := arrayList.FieldTable["size"].Fvalue.(int64)
+	if elementData == nil || object.IsNull(elementData) {
+		return []*object.Object{}
+	}
+	return elementData.FieldTable["value"].Fvalue.([]*object.Object)[:size]
+}
+
+// "java/util/ArrayList.addAll(Ljava/util/Collection;)Z"
+// params[0] = the frame stack, params[1] = the list, params[2] = the collection
+func arrayListAddAll(params []interface{}) interface{} {
+	arrayList := params[1].(*object.Object)
+	size, _ := arrayList.FieldTable["size"].Fvalue.(int64)
+	return arrayListInsert(params[0].(*list.List), arrayList, size, params[2])
+}
+
+// "java/util/ArrayList.addAll(ILjava/util/Collection;)Z"
+func arrayListAddAllAt(params []interface{}) interface{} {
+	arrayList := params[1].(*object.Object)
+	index := params[2].(int64)
+	size, _ := arrayList.FieldTable["size"].Fvalue.(int64)
+	if index < 0 || index > size {
+		errMsg := fmt.Sprintf("ArrayList.addAll: Index: %d, Size: %d", index, size)
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	return arrayListInsert(params[0].(*list.List), arrayList, index, params[3])
+}
+
+// arrayListInsert inserts the elements of a collection into a list at the given index.
+// Returns whether the list changed, that is, whether the collection had any elements.
+func arrayListInsert(fs *list.List, arrayList *object.Object, index int64, collection interface{}) interface{} {
+	if object.IsNull(collection) {
+		return getGErrBlk(excNames.NullPointerException, "ArrayList.addAll: collection is null")
+	}
+	added, errBlk := collectionElements(fs, collection.(*object.Object), "ArrayList.addAll")
+	if errBlk != nil {
+		return errBlk
+	}
+	return arrayListInsertElements(arrayList, index, added)
+}
+
+// arrayListInsertElements inserts the elements into a list at the given index, growing the
+// elementData array if it's too small to hold them. Returns whether the list changed, that
+// is, whether there were any elements.
+func arrayListInsertElements(list *object.Object, index int64, added []*object.Object) interface{} {
+	if len(added) == 0 {
+		return types.JavaBoolFalse
+	}
+	added = append([]*object.Object{}, added...) // in case the list is being added to itself
+
+	current := arrayListElements(list)
+	newSize := int64(len(current) + len(added))
+	elementData, _ := list.FieldTable["elementData"].Fvalue.(*object.Object)
+	var data []*object.Object
+	if elementData != nil && !object.IsNull(elementData) {
+		data = elementData.FieldTable["value"].Fvalue.([]*object.Object)
+	}
+
+	if int64(len(data)) < newSize { // as in the JDK, grow the array by half, or more if needed
+		capacity := int64(len(data)) + int64(len(data))/2
+		if capacity < newSize {
+			capacity = newSize
+		}
+		elementType := "java/lang/Object"
+		elementData = object.Make1DimRefArray(&elementType, capacity)
+		copy(elementData.FieldTable["value"].Fvalue.([]*object.Object), current)
+		data = elementData.FieldTable["value"].Fvalue.([]*object.Object)
+		list.FieldTable["elementData"] = object.Field{Ftype: types.RefArray + "java/lang/Object", Fvalue: elementData}
+	}
+
+	copy(data[index+int64(len(added)):newSize], data[index:len(current)])
+	copy(data[index:], added)
+
+	modCount, _ := list.FieldTable["modCount"].Fvalue.(int64)
+	list.FieldTable["modCount"] = object.Field{Ftype: types.Int, Fvalue: modCount + 1}
+	list.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: newSize}
+	return types.JavaBoolTrue
+}
+
+// arrayListFind returns the index of the first element of the list, or of the last if fromEnd
+// is true, that equals the object, or -1 if there is none. As in the JDK, a null object matches
+// a null element; otherwise, the object's equals() is called with the element.
+func arrayListFind(fs *list.List, arrayList *object.Object, obj interface{}, fromEnd bool) (int64, *GErrBlk) {
+	elements := arrayListElements(arrayList)
+	for i := range elements {
+		index := i
+		if fromEnd {
+			index = len(elements) - 1 - i
+		}
+		element := elements[index]
+		if object.IsNull(obj) {
+			if object.IsNull(element) {
+				return int64(index), nil
+			}
+			continue
+		}
+		equal, errBlk := objectsEqual(fs, obj.(*object.Object), element)
+		if errBlk != nil {
+			return -1, errBlk
+		}
+		if equal {
+			return int64(index), nil
+		}
+	}
+	return -1, nil
+}
+
+// "java/util/ArrayList.contains(Ljava/lang/Object;)Z"
+// params[0] = the frame stack, params[1] = the list, params[2] = the object
+func arrayListContains(params []interface{}) interface{} {
+	index, errBlk := arrayListFind(params[0].(*list.List), params[1].(*object.Object), params[2], false)
+	if errBlk != nil {
+		return errBlk
+	}
+	if index < 0 {
+		return types.JavaBoolFalse
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/ArrayList.indexOf(Ljava/lang/Object;)I"
+func arrayListIndexOf(params []interface{}) interface{} {
+	index, errBlk := arrayListFind(params[0].(*list.List), params[1].(*object.Object), params[2], false)
+	if errBlk != nil {
+		return errBlk
+	}
+	return index
+}
+
+// "java/util/ArrayList.lastIndexOf(Ljava/lang/Object;)I"
+func arrayListLastIndexOf(params []interface{}) interface{} {
+	index, errBlk := arrayListFind(params[0].(*list.List), params[1].(*object.Object), params[2], true)
+	if errBlk != nil {
+		return errBlk
+	}
+	return index
+}
+
+// "java/util/ArrayList.remove(Ljava/lang/Object;)Z" removes the first element that equals the
+// object, shifting the elements after it down by one. Returns whether an element was removed.
+func arrayListRemoveObject(params []interface{}) interface{} {
+	arrayList := params[1].(*object.Object)
+	index, errBlk := arrayListFind(params[0].(*list.List), arrayList, params[2], false)
+	if errBlk != nil {
+		return errBlk
+	}
+	if index < 0 {
+		return types.JavaBoolFalse
+	}
+
+	size := arrayList.FieldTable["size"].Fvalue.(int64)
+	elementData := arrayList.FieldTable["elementData"].Fvalue.(*object.Object)
+	data := elementData.FieldTable["value"].Fvalue.([]*object.Object)
+	copy(data[index:size-1], data[index+1:size])
+	data[size-1] = object.Null // as in the JDK, so that the removed element can be collected
+
+	modCount, _ := arrayList.FieldTable["modCount"].Fvalue.(int64)
+	arrayList.FieldTable["modCount"] = object.Field{Ftype: types.Int, Fvalue: modCount + 1}
+	arrayList.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: size - 1}
+	return types.JavaBoolTrue
+}
+
+// "java/util/ArrayList.toArray()[Ljava/lang/Object;"
+func arrayListToArray(params []interface{}) interface{} {
+	elements := arrayListElements(params[0].(*object.Object))
+	elementType := "java/lang/Object"
+	array := object.Make1DimRefArray(&elementType, int64(len(elements)))
+	copy(array.FieldTable["value"].Fvalue.([]*object.Object), elements)
+	return array
+}
+
+// "java/util/ArrayList.toArray([Ljava/lang/Object;)[Ljava/lang/Object;" If the array passed
+// is large enough, the elements are copied into it, followed by a null if there's room;
+// otherwise, a new array of the same component type is returned.
+func arrayListToTypedArray(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "ArrayList.toArray: array is null")
+	}
+	elements := arrayListElements(params[0].(*object.Object))
+	array := params[1].(*object.Object)
+	arrayField := array.FieldTable["value"]
+	data := arrayField.Fvalue.([]*object.Object)
+
+	if len(data) < len(elements) {
+		elementType := strings.TrimPrefix(arrayField.Ftype, types.RefArray)
+		array = object.Make1DimRefArray(&elementType, int64(len(elements)))
+		data = array.FieldTable["value"].Fvalue.([]*object.Object)
+	}
+	copy(data, elements)
+	if len(data) > len(elements) {
+		data[len(elements)] = object.Null
+	}
+	return array
+}
@@ -0,0 +1,313 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/stringPool"
+	"jacobin/types"
+	"testing"
+)
+
+// makeTestArrayList returns an ArrayList with the fields that the JDK's constructor sets up,
+// holding the given strings, with room for extra more elements
+func makeTestArrayList(extra int, strs ...string) *object.Object {
+	className := arrayListClassName
+	list := object.MakeEmptyObjectWithClassName(&className)
+	elementType := "java/lang/Object"
+	elementData := object.Make1DimRefArray(&elementType, int64(len(strs)+extra))
+	for i, str := range strs {
+		elementData.FieldTable["value"].Fvalue.([]*object.Object)[i] = object.StringObjectFromGoString(str)
+	}
+	list.FieldTable["elementData"] = object.Field{Ftype: types.RefArray + "java/lang/Object", Fvalue: elementData}
+	list.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(len(strs))}
+	list.FieldTable["modCount"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	return list
+}
+
+// arrayListStrings returns the elements of a list of strings as golang strings
+func arrayListStrings(t *testing.T, list *object.Object) []string {
+	elements, errBlk := immutableListElements(list)
+	if errBlk != nil {
+		t.Fatalf("Unexpected error getting the elements of the list: %s", errBlk.ErrMsg)
+	}
+	var strs []string
+	for _, element := range elements {
+		strs = append(strs, object.GoStringFromStringObject(element))
+	}
+	return strs
+}
+
+func checkStrings(t *testing.T, what string, got, want []string) {
+	if len(got) != len(want) {
+		t.Errorf("%s: expected %v, got %v", what, want, got)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s: expected %v, got %v", what, want, got)
+			return
+		}
+	}
+}
+
+func TestArrayListToArrayRoundTrip(t *testing.T) {
+	globals.InitGlobals("test")
+	strs := []string{"alpha", "beta", "gamma"}
+	list := makeTestArrayList(5, strs...) // the backing array is larger than the list
+
+	array := arrayListToArray([]interface{}{list}).(*object.Object)
+	if array.FieldTable["value"].Ftype != "[Ljava/lang/Object" {
+		t.Errorf("ArrayList.toArray(): expected an Object[], got %s", array.FieldTable["value"].Ftype)
+	}
+
+	// putting the array's elements into a new list gives a list equal to the original
+	copied := makeTestArrayList(0)
+	fs := frames.CreateFrameStack()
+	elementType := "java/lang/Object"
+	asList := makeTestArrayList(0)
+	asList.FieldTable["elementData"] = object.Field{Ftype: types.RefArray + elementType, Fvalue: array}
+	asList.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: object.ArrayLength(array)}
+	arrayListAddAll([]interface{}{fs, copied, asList})
+	checkStrings(t, "ArrayList.toArray() round trip", arrayListStrings(t, copied), strs)
+}
+
+func TestArrayListToTypedArray(t *testing.T) {
+	globals.InitGlobals("test")
+	list := makeTestArrayList(0, "alpha", "beta", "gamma")
+	elementType := "java/lang/String"
+
+	// an array that's too small gets replaced by a new one of the same type
+	small := object.Make1DimRefArray(&elementType, 0)
+	array := arrayListToTypedArray([]interface{}{list, small}).(*object.Object)
+	if array == small || array.FieldTable["value"].Ftype != "[Ljava/lang/String" ||
+		object.ArrayLength(array) != 3 {
+		t.Errorf("ArrayList.toArray(String[0]): expected a new String[3], got %s of length %d",
+			array.FieldTable["value"].Ftype, object.ArrayLength(array))
+	}
+
+	// an array that's large enough is filled, followed by a null
+	large := object.Make1DimRefArray(&elementType, 5)
+	filler := object.StringObjectFromGoString("filler")
+	large.FieldTable["value"].Fvalue.([]*object.Object)[3] = filler
+	large.FieldTable["value"].Fvalue.([]*object.Object)[4] = filler
+	array = arrayListToTypedArray([]interface{}{list, large}).(*object.Object)
+	data := array.FieldTable["value"].Fvalue.([]*object.Object)
+	if array != large || object.GoStringFromStringObject(data[2]) != "gamma" ||
+		data[3] != object.Null || data[4] != filler {
+		t.Errorf("ArrayList.toArray(String[5]): expected the array passed, filled and null-terminated")
+	}
+
+	ret := arrayListToTypedArray([]interface{}{list, object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("ArrayList.toArray(null): expected a NullPointerException, got %v", ret)
+	}
+}
+
+func TestArrayListAddAll(t *testing.T) {
+	globals.InitGlobals("test")
+	list := makeTestArrayList(1, "a", "b")
+	other := makeTestArrayList(0, "c", "d", "e")
+	fs := frames.CreateFrameStack()
+
+	if ret := arrayListAddAll([]interface{}{fs, list, other}); ret != types.JavaBoolTrue {
+		t.Errorf("ArrayList.addAll(): expected true, got %v", ret)
+	}
+	checkStrings(t, "ArrayList.addAll()", arrayListStrings(t, list), []string{"a", "b", "c", "d", "e"})
+	checkStrings(t, "ArrayList.addAll() source", arrayListStrings(t, other), []string{"c", "d", "e"})
+	if modCount := list.FieldTable["modCount"].Fvalue.(int64); modCount != 1 {
+		t.Errorf("ArrayList.addAll(): expected modCount of 1, got %d", modCount)
+	}
+
+	// insert at an index, then add the list to itself
+	arrayListAddAllAt([]interface{}{fs, list, int64(1), collectionsSingletonListOf([]interface{}{object.StringObjectFromGoString("x")})})
+	checkStrings(t, "ArrayList.addAll(1, [x])", arrayListStrings(t, list), []string{"a", "x", "b", "c", "d", "e"})
+	arrayListAddAllAt([]interface{}{fs, list, int64(0), list})
+	checkStrings(t, "ArrayList.addAll(0, list)", arrayListStrings(t, list),
+		[]string{"a", "x", "b", "c", "d", "e", "a", "x", "b", "c", "d", "e"})
+
+	// adding an empty collection leaves the list unchanged
+	if ret := arrayListAddAll([]interface{}{fs, list, collectionsEmptyListOf(nil)}); ret != types.JavaBoolFalse {
+		t.Errorf("ArrayList.addAll(emptyList): expected false, got %v", ret)
+	}
+
+	ret := arrayListAddAllAt([]interface{}{fs, list, int64(13), other})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IndexOutOfBoundsException {
+		t.Errorf("ArrayList.addAll(13, list): expected an IndexOutOfBoundsException, got %v", ret)
+	}
+	ret = arrayListAddAll([]interface{}{fs, list, object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("ArrayList.addAll(null): expected a NullPointerException, got %v", ret)
+	}
+}
+
+// The elements of a collection other than a list, such as a HashSet, come from its toArray()
+func TestArrayListAddAllOfOtherCollection(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	arrayList := makeTestArrayList(0, "a")
+	elementType := "java/lang/Object"
+	array := object.Make1DimRefArray(&elementType, 2)
+	array.FieldTable["value"].Fvalue.([]*object.Object)[0] = object.StringObjectFromGoString("b")
+	array.FieldTable["value"].Fvalue.([]*object.Object)[1] = object.StringObjectFromGoString("c")
+	var invoked string
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, methodName, methodType string, _ ...any) (any, error) {
+		invoked = methodName + methodType
+		return array, nil
+	}
+	className := "java/util/HashSet"
+	set := object.MakeEmptyObjectWithClassName(&className)
+
+	if ret := arrayListAddAll([]interface{}{frames.CreateFrameStack(), arrayList, set}); ret != types.JavaBoolTrue {
+		t.Errorf("ArrayList.addAll(set): expected true, got %v", ret)
+	}
+	if invoked != "toArray()[Ljava/lang/Object;" {
+		t.Errorf("ArrayList.addAll(set): expected the set's toArray() to be called, got %q", invoked)
+	}
+	checkStrings(t, "ArrayList.addAll(set)", arrayListStrings(t, arrayList), []string{"a", "b", "c"})
+}
+
+// An instance of a subclass of ArrayList keeps its elements in the fields it inherits
+func TestArrayListSubclass(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	name, superclass := "test/MyList", arrayListClassName
+	classloader.MethAreaInsert(name, &classloader.Klass{Status: 'X', Loader: "app",
+		Data: &classloader.ClData{Name: name, Superclass: superclass,
+			SuperclassIndex: stringPool.GetStringIndex(&superclass)}})
+
+	subclassList := makeTestArrayList(0, "a", "b")
+	subclassList.KlassName = stringPool.GetStringIndex(&name)
+	fs := frames.CreateFrameStack()
+
+	if ret := arrayListAddAll([]interface{}{fs, subclassList, makeTestArrayList(0, "c")}); ret != types.JavaBoolTrue {
+		t.Errorf("MyList.addAll(): expected true, got %v", ret)
+	}
+	checkStrings(t, "MyList.addAll()", arrayListStrings(t, subclassList), []string{"a", "b", "c"})
+	if length := object.ArrayLength(arrayListToArray([]interface{}{subclassList}).(*object.Object)); length != 3 {
+		t.Errorf("MyList.toArray(): expected an array of length 3, got %d", length)
+	}
+
+	list := makeTestArrayList(0, "x")
+	arrayListAddAll([]interface{}{fs, list, subclassList})
+	checkStrings(t, "ArrayList.addAll(myList)", arrayListStrings(t, list), []string{"x", "a", "b", "c"})
+}
+
+// The searches work on an instance of a subclass of ArrayList, as the JDK's do
+func TestArrayListSubclassIndexOfAndRemove(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	name, superclass := "test/MyList", arrayListClassName
+	classloader.MethAreaInsert(name, &classloader.Klass{Status: 'X', Loader: "app",
+		Data: &classloader.ClData{Name: name, Superclass: superclass,
+			SuperclassIndex: stringPool.GetStringIndex(&superclass)}})
+
+	subclassList := makeTestArrayList(0, "a", "b", "a")
+	subclassList.KlassName = stringPool.GetStringIndex(&name)
+	fs := frames.CreateFrameStack()
+	a := object.StringObjectFromGoString("a")
+
+	if ret := arrayListContains([]interface{}{fs, subclassList, a}); ret != types.JavaBoolTrue {
+		t.Errorf("MyList.contains(a): expected true, got %v", ret)
+	}
+	if index := arrayListLastIndexOf([]interface{}{fs, subclassList, a}); index != int64(2) {
+		t.Errorf("MyList.lastIndexOf(a): expected 2, got %v", index)
+	}
+	if ret := arrayListRemoveObject([]interface{}{fs, subclassList, a}); ret != types.JavaBoolTrue {
+		t.Errorf("MyList.remove(a): expected true, got %v", ret)
+	}
+	checkStrings(t, "MyList.remove(a)", arrayListStrings(t, subclassList), []string{"b", "a"})
+}
+
+func TestArrayListIndexOfAndContains(t *testing.T) {
+	globals.InitGlobals("test")
+	list := makeTestArrayList(2, "a", "b", "a", "c", "b")
+	fs := frames.CreateFrameStack()
+	b := object.StringObjectFromGoString("b") // equal to, but not the same object as, the elements
+
+	if index := arrayListIndexOf([]interface{}{fs, list, b}); index != int64(1) {
+		t.Errorf("ArrayList.indexOf(b): expected 1, got %v", index)
+	}
+	if index := arrayListLastIndexOf([]interface{}{fs, list, b}); index != int64(4) {
+		t.Errorf("ArrayList.lastIndexOf(b): expected 4, got %v", index)
+	}
+	if ret := arrayListContains([]interface{}{fs, list, b}); ret != types.JavaBoolTrue {
+		t.Errorf("ArrayList.contains(b): expected true, got %v", ret)
+	}
+
+	// the unused entries of elementData, which are null, are not part of the list
+	if index := arrayListIndexOf([]interface{}{fs, list, object.Null}); index != int64(-1) {
+		t.Errorf("ArrayList.indexOf(null): expected -1, got %v", index)
+	}
+	z := object.StringObjectFromGoString("z")
+	if index := arrayListLastIndexOf([]interface{}{fs, list, z}); index != int64(-1) {
+		t.Errorf("ArrayList.lastIndexOf(z): expected -1, got %v", index)
+	}
+	if ret := arrayListContains([]interface{}{fs, list, z}); ret != types.JavaBoolFalse {
+		t.Errorf("ArrayList.contains(z): expected false, got %v", ret)
+	}
+
+	// boxed values are equal only if they're of the same class
+	ints := makeTestIntegerList(7, 42)
+	fortyTwo := object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(42))
+	longFortyTwo := object.MakePrimitiveObject("java/lang/Long", types.Long, int64(42))
+	if index := arrayListIndexOf([]interface{}{fs, ints, fortyTwo}); index != int64(1) {
+		t.Errorf("ArrayList.indexOf(42): expected 1, got %v", index)
+	}
+	if index := arrayListIndexOf([]interface{}{fs, ints, longFortyTwo}); index != int64(-1) {
+		t.Errorf("ArrayList.indexOf(42L): expected -1, got %v", index)
+	}
+}
+
+func TestArrayListRemoveObject(t *testing.T) {
+	globals.InitGlobals("test")
+	list := makeTestArrayList(0, "a", "b", "a", "c", "b")
+	fs := frames.CreateFrameStack()
+
+	// only the first of the duplicates is removed, and the rest are shifted down
+	if ret := arrayListRemoveObject([]interface{}{fs, list, object.StringObjectFromGoString("b")}); ret != types.JavaBoolTrue {
+		t.Errorf("ArrayList.remove(b): expected true, got %v", ret)
+	}
+	checkStrings(t, "ArrayList.remove(b)", arrayListStrings(t, list), []string{"a", "a", "c", "b"})
+	data := list.FieldTable["elementData"].Fvalue.(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+	if !object.IsNull(data[4]) {
+		t.Errorf("ArrayList.remove(b): expected the vacated entry to be null, got %v", data[4])
+	}
+	if modCount := list.FieldTable["modCount"].Fvalue.(int64); modCount != 1 {
+		t.Errorf("ArrayList.remove(b): expected modCount of 1, got %d", modCount)
+	}
+
+	arrayListRemoveObject([]interface{}{fs, list, object.StringObjectFromGoString("b")})
+	arrayListRemoveObject([]interface{}{fs, list, object.StringObjectFromGoString("a")})
+	checkStrings(t, "ArrayList.remove(b), remove(a)", arrayListStrings(t, list), []string{"a", "c"})
+
+	// removing a missing element leaves the list unchanged
+	if ret := arrayListRemoveObject([]interface{}{fs, list, object.StringObjectFromGoString("b")}); ret != types.JavaBoolFalse {
+		t.Errorf("ArrayList.remove(b) of [a, c]: expected false, got %v", ret)
+	}
+	if modCount := list.FieldTable["modCount"].Fvalue.(int64); modCount != 3 {
+		t.Errorf("ArrayList.remove() of a missing element: expected modCount of 3, got %d", modCount)
+	}
+
+	// null elements are removed by remove(null)
+	withNull := makeTestArrayList(0)
+	arrayListAddAll([]interface{}{fs, withNull, collectionsNCopies([]interface{}{int64(2), object.Null})})
+	if ret := arrayListRemoveObject([]interface{}{fs, withNull, object.Null}); ret != types.JavaBoolTrue {
+		t.Errorf("ArrayList.remove(null): expected true, got %v", ret)
+	}
+	if size := withNull.FieldTable["size"].Fvalue.(int64); size != 1 {
+		t.Errorf("ArrayList.remove(null): expected a size of 1, got %d", size)
+	}
+}
@@ -0,0 +1,34 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/excNames"
+	"jacobin/object"
+)
+
+// Implementation of some of the functions in java/util/Arrays. The rest of the class is
+// the JDK's Java implementation.
+
+func Load_Util_Arrays() {
+
+	MethodSignatures["java/util/Arrays.stream([I)Ljava/util/stream/IntStream;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  arraysStreamInt,
+		}
+}
+
+// "java/util/Arrays.stream([I)Ljava/util/stream/IntStream;" The stream holds the array's
+// backing slice, as the JDK's stream reads the array itself rather than a copy of it.
+func arraysStreamInt(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "Arrays.stream: array is null")
+	}
+	ints := params[0].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+	return makeIntStream(ints)
+}
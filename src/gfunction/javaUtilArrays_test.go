@@ -0,0 +1,76 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"testing"
+)
+
+func TestArraysStreamIntTerminalOps(t *testing.T) {
+	globals.InitGlobals("test")
+	array := object.Make1DimArray(object.INT, 6)
+	ints := array.FieldTable["value"].Fvalue.([]int64)
+	copy(ints, []int64{7, -3, 12, 0, 5, 9})
+
+	manualSum := int64(0)
+	for _, i := range ints {
+		manualSum += i
+	}
+
+	stream := arraysStreamInt([]interface{}{array}).(*object.Object)
+	if sum := intStreamSum([]interface{}{stream}); sum != manualSum {
+		t.Errorf("IntStream.sum(): expected %d, got %v", manualSum, sum)
+	}
+	if count := intStreamCount([]interface{}{stream}); count != int64(6) {
+		t.Errorf("IntStream.count(): expected 6, got %v", count)
+	}
+
+	max := intStreamMax([]interface{}{stream}).(*object.Object)
+	if max.FieldTable["isPresent"].Fvalue != types.JavaBoolTrue || max.FieldTable["value"].Fvalue != int64(12) {
+		t.Errorf("IntStream.max(): expected 12, got %v", max.FieldTable)
+	}
+	min := intStreamMin([]interface{}{stream}).(*object.Object)
+	if min.FieldTable["isPresent"].Fvalue != types.JavaBoolTrue || min.FieldTable["value"].Fvalue != int64(-3) {
+		t.Errorf("IntStream.min(): expected -3, got %v", min.FieldTable)
+	}
+	avg := intStreamAverage([]interface{}{stream}).(*object.Object)
+	if avg.FieldTable["isPresent"].Fvalue != types.JavaBoolTrue || avg.FieldTable["value"].Fvalue != float64(manualSum)/6 {
+		t.Errorf("IntStream.average(): expected %f, got %v", float64(manualSum)/6, avg.FieldTable)
+	}
+}
+
+func TestArraysStreamIntEmptyAndOverflow(t *testing.T) {
+	globals.InitGlobals("test")
+	empty := arraysStreamInt([]interface{}{object.Make1DimArray(object.INT, 0)}).(*object.Object)
+	for name, optional := range map[string]interface{}{
+		"max": intStreamMax([]interface{}{empty}), "min": intStreamMin([]interface{}{empty}),
+		"average": intStreamAverage([]interface{}{empty})} {
+		if optional.(*object.Object).FieldTable["isPresent"].Fvalue != types.JavaBoolFalse {
+			t.Errorf("IntStream.%s() of an empty stream: expected an empty optional", name)
+		}
+	}
+	if sum := intStreamSum([]interface{}{empty}); sum != int64(0) {
+		t.Errorf("IntStream.sum() of an empty stream: expected 0, got %v", sum)
+	}
+
+	// as in Java, sum() wraps around, while average() does not
+	big := makeIntStream([]int64{2147483647, 1})
+	if sum := intStreamSum([]interface{}{big}); sum != int64(-2147483648) {
+		t.Errorf("IntStream.sum(): expected -2147483648, got %v", sum)
+	}
+	avg := intStreamAverage([]interface{}{big}).(*object.Object)
+	if avg.FieldTable["value"].Fvalue != float64(1073741824) {
+		t.Errorf("IntStream.average(): expected 1073741824, got %v", avg.FieldTable["value"].Fvalue)
+	}
+
+	if _, ok := arraysStreamInt([]interface{}{object.Null}).(*GErrBlk); !ok {
+		t.Errorf("Arrays.stream(null): expected an error block")
+	}
+}
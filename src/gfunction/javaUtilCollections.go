@@ -0,0 +1,819 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/statics"
+	"jacobin/types"
+	"math"
+)
+
+// Implementation of the immutable lists returned by java.util.Collections: emptyList(),
+// singletonList(), nCopies(), and unmodifiableList(). Each is an object of the JDK's class
+// for that kind of list, with the JDK's fields, so that the JDK's own methods of the class
+// work on it: a SingletonList holds its element in "element", and a CopiesList holds its
+// length in "n" and its element in "element". An unmodifiable list is a view of another
+// list, which is kept in its "c" and "list" fields, so that it reflects later changes to that
+// list. The list viewed can be one of these lists or a java.util.ArrayList. All of these
+// lists throw an UnsupportedOperationException if an attempt is made to modify them. As in
+// the JDK, emptyList() returns the list in the static field Collections.EMPTY_LIST, which is
+// set, along with EMPTY_SET and EMPTY_MAP, by Collections.<clinit>.
+
+const (
+	collectionsEmptyList        = "java/util/Collections$EmptyList"
+	collectionsSingletonList    = "java/util/Collections$SingletonList"
+	collectionsCopiesList       = "java/util/Collections$CopiesList"
+	collectionsUnmodifiableList = "java/util/Collections$UnmodifiableList"
+	collectionsEmptySet         = "java/util/Collections$EmptySet"
+	collectionsEmptyMap         = "java/util/Collections$EmptyMap"
+)
+
+func Load_Util_Collections() {
+
+	MethodSignatures["java/util/Collections.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  collectionsClinit,
+		}
+
+	MethodSignatures["java/util/Collections.addAll(Ljava/util/Collection;[Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    collectionsAddAll,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.binarySearch(Ljava/util/List;Ljava/lang/Object;)I"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    collectionsBinarySearch,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.binarySearch(Ljava/util/List;Ljava/lang/Object;Ljava/util/Comparator;)I"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    collectionsBinarySearch,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.disjoint(Ljava/util/Collection;Ljava/util/Collection;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    collectionsDisjoint,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.frequency(Ljava/util/Collection;Ljava/lang/Object;)I"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    collectionsFrequency,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.max(Ljava/util/Collection;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    collectionsMax,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.max(Ljava/util/Collection;Ljava/util/Comparator;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    collectionsMax,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.min(Ljava/util/Collection;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    collectionsMin,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.min(Ljava/util/Collection;Ljava/util/Comparator;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    collectionsMin,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.emptyList()Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  collectionsEmptyListOf,
+		}
+
+	MethodSignatures["java/util/Collections.nCopies(ILjava/lang/Object;)Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  collectionsNCopies,
+		}
+
+	MethodSignatures["java/util/Collections.singletonList(Ljava/lang/Object;)Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  collectionsSingletonListOf,
+		}
+
+	MethodSignatures["java/util/Collections.swap(Ljava/util/List;II)V"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    collectionsSwap,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Collections.unmodifiableList(Ljava/util/List;)Ljava/util/List;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  collectionsUnmodifiableListOf,
+		}
+
+	// the methods of the lists themselves, which are the same for each kind of list
+	for _, className := range []string{collectionsEmptyList, collectionsSingletonList,
+		collectionsCopiesList, collectionsUnmodifiableList} {
+
+		MethodSignatures[className+".get(I)Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots:   1,
+				GFunction:    immutableListGet,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".isEmpty()Z"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    immutableListIsEmpty,
+				NeedsContext: true,
+			}
+
+		MethodSignatures[className+".size()I"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    immutableListSize,
+				NeedsContext: true,
+			}
+
+		// methods that would modify the list
+		MethodSignatures[className+".add(Ljava/lang/Object;)Z"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  immutableListModify,
+			}
+
+		MethodSignatures[className+".add(ILjava/lang/Object;)V"] =
+			GMeth{
+				ParamSlots: 2,
+				GFunction:  immutableListModify,
+			}
+
+		MethodSignatures[className+".clear()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  immutableListModify,
+			}
+
+		MethodSignatures[className+".remove(I)Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  immutableListModify,
+			}
+
+		MethodSignatures[className+".remove(Ljava/lang/Object;)Z"] =
+			GMeth{
+				ParamSlots: 1,
+				GFunction:  immutableListModify,
+			}
+
+		MethodSignatures[className+".set(ILjava/lang/Object;)Ljava/lang/Object;"] =
+			GMeth{
+				ParamSlots: 2,
+				GFunction:  immutableListModify,
+			}
+	}
+}
+
+// makeImmutableList returns an object of the given list class, with the given length and
+// element in the fields the JDK's class has: none for an EmptyList, the element for a
+// SingletonList, and both for a CopiesList.
+func makeImmutableList(className string, length int64, element *object.Object) *object.Object {
+	theList := object.MakeEmptyObjectWithClassName(&className)
+	switch className {
+	case collectionsCopiesList:
+		theList.FieldTable["n"] = object.Field{Ftype: types.Int, Fvalue: length}
+		fallthrough
+	case collectionsSingletonList:
+		theList.FieldTable["element"] = object.Field{Ftype: types.Ref, Fvalue: element}
+	}
+	return theList
+}
+
+// "java/util/Collections.<clinit>()V" Sets the static fields that hold the empty set, list,
+// and map, as the JDK's <clinit> does.
+func collectionsClinit([]interface{}) interface{} {
+	for _, field := range []struct{ name, fieldType, className string }{
+		{"EMPTY_SET", "Ljava/util/Set;", collectionsEmptySet},
+		{"EMPTY_LIST", "Ljava/util/List;", collectionsEmptyList},
+		{"EMPTY_MAP", "Ljava/util/Map;", collectionsEmptyMap},
+	} {
+		className := field.className
+		_ = statics.AddStatic("java/util/Collections."+field.name, statics.Static{
+			Type:  field.fieldType,
+			Value: object.MakeEmptyObjectWithClassName(&className),
+		})
+	}
+	return nil
+}
+
+// "java/util/Collections.max(Ljava/util/Collection;)Ljava/lang/Object;" and the overload that
+// takes a Comparator. params[0] = the frame stack, params[1] = the collection, params[2], if
+// present, = the comparator, which can be null.
+func collectionsMax(params []interface{}) interface{} {
+	return collectionsExtreme(params, "max", 1)
+}
+
+// "java/util/Collections.min(Ljava/util/Collection;)Ljava/lang/Object;" and the overload that
+// takes a Comparator. The params are as for collectionsMax.
+func collectionsMin(params []interface{}) interface{} {
+	return collectionsExtreme(params, "min", -1)
+}
+
+// collectionsExtreme returns the first element of the collection that no other element
+// compares greater than (if sign is 1) or less than (if sign is -1)
+func collectionsExtreme(params []interface{}, methodName string, sign int64) interface{} {
+	fs := params[0].(*list.List)
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Collections."+methodName+": collection is null")
+	}
+	var comparator *object.Object
+	if len(params) > 2 && !object.IsNull(params[2]) {
+		comparator = params[2].(*object.Object)
+	}
+
+	elements, errBlk := collectionElements(fs, params[1].(*object.Object), "Collections."+methodName)
+	if errBlk != nil {
+		return errBlk
+	}
+	if len(elements) == 0 {
+		return getGErrBlk(excNames.NoSuchElementException, "Collections."+methodName+": collection is empty")
+	}
+
+	extreme := elements[0]
+	for _, element := range elements[1:] {
+		result, errBlk := compareObjects(fs, element, extreme, comparator)
+		if errBlk != nil {
+			return errBlk
+		}
+		if result*sign > 0 {
+			extreme = element
+		}
+	}
+	return extreme
+}
+
+// "java/util/Collections.frequency(Ljava/util/Collection;Ljava/lang/Object;)I" counts the
+// elements that equal the object, using its equals(), or that are null if it's null.
+// params[0] = the frame stack, params[1] = the collection, params[2] = the object
+func collectionsFrequency(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Collections.frequency: collection is null")
+	}
+	elements, errBlk := collectionElements(fs, params[1].(*object.Object), "Collections.frequency")
+	if errBlk != nil {
+		return errBlk
+	}
+
+	var count int64
+	for _, element := range elements {
+		if object.IsNull(params[2]) {
+			if object.IsNull(element) {
+				count++
+			}
+			continue
+		}
+		equal, errBlk := objectsEqual(fs, params[2].(*object.Object), element)
+		if errBlk != nil {
+			return errBlk
+		}
+		if equal {
+			count++
+		}
+	}
+	return count
+}
+
+// "java/util/Collections.addAll(Ljava/util/Collection;[Ljava/lang/Object;)Z" adds each of the
+// elements to the collection and returns whether the collection changed. The elements are
+// appended directly to an ArrayList (or an instance of a subclass of it); to any other
+// collection, they're added with its add(), which is run through globals.FuncInvokeMethod.
+// params[0] = the frame stack, params[1] = the collection, params[2] = the array of elements
+func collectionsAddAll(params []interface{}) interface{} {
+	if object.IsNull(params[1]) || object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "Collections.addAll: collection or elements is null")
+	}
+	collection := params[1].(*object.Object)
+	elements := params[2].(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+
+	if isArrayList(collection) {
+		size, _ := collection.FieldTable["size"].Fvalue.(int64)
+		return arrayListInsertElements(collection, size, elements)
+	}
+
+	changed := types.JavaBoolFalse
+	for _, element := range elements {
+		ret, err := globals.GetGlobalRef().FuncInvokeMethod(params[0].(*list.List), collection,
+			"add", "(Ljava/lang/Object;)Z", element)
+		if err != nil {
+			errMsg := fmt.Sprintf("Collections.addAll: add() failed: %s", err.Error())
+			return getGErrBlk(excNames.VirtualMachineError, errMsg)
+		}
+		if ret == types.JavaBoolTrue {
+			changed = types.JavaBoolTrue
+		}
+	}
+	return changed
+}
+
+// "java/util/Collections.binarySearch(Ljava/util/List;Ljava/lang/Object;)I" and the overload that
+// takes a Comparator return the index of an element of the list that compares equal to the key,
+// or, if there is none, -(insertion point)-1, where the insertion point is the index at which
+// the key would be inserted to keep the list sorted. The list can be any List (see
+// listAccessors). As in the JDK, the list must be sorted in ascending order (by the
+// comparator, if there is one), or the result is undefined, and each element is compared to
+// the key, rather than the key to the element.
+// params[0] = the frame stack, params[1] = the list, params[2] = the key, params[3], if
+// present, = the comparator, which can be null for the natural ordering.
+func collectionsBinarySearch(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Collections.binarySearch: list is null")
+	}
+	key, _ := params[2].(*object.Object)
+	var comparator *object.Object
+	if len(params) > 3 && !object.IsNull(params[3]) {
+		comparator = params[3].(*object.Object)
+	}
+
+	size, get, errBlk := listAccessors(fs, params[1].(*object.Object), "Collections.binarySearch")
+	if errBlk != nil {
+		return errBlk
+	}
+
+	low, high := 0, int(size)-1
+	for low <= high {
+		mid := int(uint(low+high) >> 1)
+		element, errBlk := get(int64(mid))
+		if errBlk != nil {
+			return errBlk
+		}
+		result, errBlk := compareObjects(fs, element, key, comparator)
+		if errBlk != nil {
+			return errBlk
+		}
+		switch {
+		case result < 0:
+			low = mid + 1
+		case result > 0:
+			high = mid - 1
+		default:
+			return int64(mid)
+		}
+	}
+	return int64(-(low + 1))
+}
+
+// "java/util/Collections.disjoint(Ljava/util/Collection;Ljava/util/Collection;)Z" returns
+// whether no element of the first collection equals an element of the second, as by equals(),
+// with a null element equal only to another null. Either can be any Collection (see
+// collectionElements).
+// params[0] = the frame stack, params[1] and params[2] = the collections
+func collectionsDisjoint(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	if object.IsNull(params[1]) || object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "Collections.disjoint: collection is null")
+	}
+	first, errBlk := collectionElements(fs, params[1].(*object.Object), "Collections.disjoint")
+	if errBlk != nil {
+		return errBlk
+	}
+	second, errBlk := collectionElements(fs, params[2].(*object.Object), "Collections.disjoint")
+	if errBlk != nil {
+		return errBlk
+	}
+
+	for _, a := range first {
+		for _, b := range second {
+			var equal bool
+			if object.IsNull(a) {
+				equal = object.IsNull(b)
+			} else if equal, errBlk = objectsEqual(fs, a, b); errBlk != nil {
+				return errBlk
+			}
+			if equal {
+				return types.JavaBoolFalse
+			}
+		}
+	}
+	return types.JavaBoolTrue
+}
+
+// "java/util/Collections.swap(Ljava/util/List;II)V" swaps the elements at the two indices.
+// The elements of an ArrayList are swapped directly, and the immutable lists throw an
+// UnsupportedOperationException. Any other list is changed, as in the JDK, with its get() and
+// set(), which are run through globals.FuncInvokeMethod.
+// params[0] = the frame stack, params[1] = the list, params[2] and params[3] = the indices
+func collectionsSwap(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Collections.swap: list is null")
+	}
+	fs := params[0].(*list.List)
+	theList := params[1].(*object.Object)
+	i, j := params[2].(int64), params[3].(int64)
+
+	if !isDirectList(theList) {
+		var element any
+		if errBlk := mapInvoke(fs, theList, "Collections.swap", "get", "(I)Ljava/lang/Object;", &element, i); errBlk != nil {
+			return errBlk
+		}
+		if errBlk := mapInvoke(fs, theList, "Collections.swap", "set",
+			"(ILjava/lang/Object;)Ljava/lang/Object;", &element, j, element); errBlk != nil {
+			return errBlk
+		}
+		if errBlk := mapInvoke(fs, theList, "Collections.swap", "set",
+			"(ILjava/lang/Object;)Ljava/lang/Object;", nil, i, element); errBlk != nil {
+			return errBlk
+		}
+		return nil
+	}
+
+	elements, errBlk := immutableListElements(theList)
+	if errBlk != nil {
+		return errBlk
+	}
+	for _, index := range []int64{i, j} {
+		if index < 0 || index >= int64(len(elements)) {
+			errMsg := fmt.Sprintf("Index %d out of bounds for length %d", index, len(elements))
+			return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+		}
+	}
+	if !isArrayList(theList) {
+		return immutableListModify([]interface{}{theList})
+	}
+	elements[i], elements[j] = elements[j], elements[i]
+	return nil
+}
+
+// boxedValue returns the value of a String or of a boxed primitive, such as an Integer, so
+// that these can be compared without running their Java methods
+func boxedValue(obj *object.Object) (any, bool) {
+	if object.IsStringObject(obj) {
+		return object.GoStringFromStringObject(obj), true
+	}
+	switch object.GoStringFromStringPoolIndex(obj.KlassName) {
+	case "java/lang/Boolean", "java/lang/Byte", "java/lang/Character", "java/lang/Double",
+		"java/lang/Float", "java/lang/Integer", "java/lang/Long", "java/lang/Short":
+		return obj.FieldTable["value"].Fvalue, true
+	}
+	return nil, false
+}
+
+// boxedValuesEqual reports whether two values returned by boxedValue are equal. Doubles and
+// Floats are compared by their bits, as Double.equals() and Float.equals() do, so NaN equals
+// NaN (all NaNs have the same bits in doubleToLongBits()), and 0.0 doesn't equal -0.0.
+func boxedValuesEqual(a, b any) bool {
+	aNumber, okA := a.(float64)
+	bNumber, okB := b.(float64)
+	if !okA || !okB {
+		return a == b
+	}
+	if math.IsNaN(aNumber) || math.IsNaN(bNumber) {
+		return math.IsNaN(aNumber) && math.IsNaN(bNumber)
+	}
+	return math.Float64bits(aNumber) == math.Float64bits(bNumber)
+}
+
+// compareObjects returns a negative number, zero, or a positive number as a is less than,
+// equal to, or greater than b. If the comparator is nil, the objects are compared by their
+// natural ordering, with their compareTo(); otherwise, with the comparator's compare().
+// Either method can be a gfunction or Java bytecode, so it's run through
+// globals.FuncInvokeMethod, except for Strings and boxed primitives of the same class,
+// which are compared directly.
+func compareObjects(fs *list.List, a, b, comparator *object.Object) (int64, *GErrBlk) {
+	var ret any
+	var err error
+	if comparator != nil {
+		ret, err = globals.GetGlobalRef().FuncInvokeMethod(fs, comparator, "compare",
+			"(Ljava/lang/Object;Ljava/lang/Object;)I", a, b)
+	} else {
+		if object.IsNull(a) || object.IsNull(b) {
+			return 0, getGErrBlk(excNames.NullPointerException, "compareTo: cannot compare null")
+		}
+		if a.KlassName == b.KlassName {
+			aValue, okA := boxedValue(a)
+			bValue, okB := boxedValue(b)
+			if okA && okB {
+				return compareValues(aValue, bValue), nil
+			}
+		}
+		ret, err = globals.GetGlobalRef().FuncInvokeMethod(fs, a, "compareTo", "(Ljava/lang/Object;)I", b)
+	}
+
+	if err != nil {
+		errMsg := fmt.Sprintf("compare failed: %s", err.Error())
+		return 0, getGErrBlk(excNames.VirtualMachineError, errMsg)
+	}
+	result, ok := ret.(int64)
+	if !ok {
+		return 0, getGErrBlk(excNames.VirtualMachineError, "compare did not return an int")
+	}
+	return result, nil
+}
+
+// compareValues compares the values of two Strings or boxed primitives of the same class.
+// As in Double.compare(), NaN is greater than all other doubles, and equal to itself, and
+// -0.0 is less than 0.0.
+func compareValues(a, b any) int64 {
+	switch a := a.(type) {
+	case string:
+		b := b.(string)
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+	case int64:
+		b := b.(int64)
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+	case float64:
+		b := b.(float64)
+		switch {
+		case math.IsNaN(a) && math.IsNaN(b):
+			return 0
+		case math.IsNaN(a):
+			return 1
+		case math.IsNaN(b) || a < b:
+			return -1
+		case a > b:
+			return 1
+		case math.Signbit(a) && !math.Signbit(b): // -0.0 and 0.0
+			return -1
+		case !math.Signbit(a) && math.Signbit(b):
+			return 1
+		}
+	}
+	return 0
+}
+
+// objectsEqual reports whether a.equals(b). Strings and boxed primitives are compared
+// directly; for other objects, equals() is run through globals.FuncInvokeMethod.
+func objectsEqual(fs *list.List, a, b *object.Object) (bool, *GErrBlk) {
+	if a == b {
+		return true, nil
+	}
+	if object.IsNull(b) {
+		return false, nil
+	}
+	aValue, okA := boxedValue(a)
+	bValue, okB := boxedValue(b)
+	if okA || okB {
+		return okA && okB && a.KlassName == b.KlassName && boxedValuesEqual(aValue, bValue), nil
+	}
+
+	ret, err := globals.GetGlobalRef().FuncInvokeMethod(fs, a, "equals", "(Ljava/lang/Object;)Z", b)
+	if err != nil {
+		errMsg := fmt.Sprintf("equals() failed: %s", err.Error())
+		return false, getGErrBlk(excNames.VirtualMachineError, errMsg)
+	}
+	return ret == types.JavaBoolTrue, nil
+}
+
+// "java/util/Collections.emptyList()Ljava/util/List;" Returns Collections.EMPTY_LIST, or, if
+// Collections.<clinit> has not set it, a new empty list.
+func collectionsEmptyListOf([]interface{}) interface{} {
+	if emptyList, ok := statics.Statics["java/util/Collections.EMPTY_LIST"].Value.(*object.Object); ok {
+		return emptyList
+	}
+	return makeImmutableList(collectionsEmptyList, 0, nil)
+}
+
+// "java/util/Collections.singletonList(Ljava/lang/Object;)Ljava/util/List;"
+func collectionsSingletonListOf(params []interface{}) interface{} {
+	element, _ := params[0].(*object.Object) // the element can be null
+	return makeImmutableList(collectionsSingletonList, 1, element)
+}
+
+// "java/util/Collections.nCopies(ILjava/lang/Object;)Ljava/util/List;"
+func collectionsNCopies(params []interface{}) interface{} {
+	count := params[0].(int64)
+	if count < 0 {
+		errMsg := fmt.Sprintf("Collections.nCopies: List length = %d", count)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	element, _ := params[1].(*object.Object)
+	return makeImmutableList(collectionsCopiesList, count, element)
+}
+
+// "java/util/Collections.unmodifiableList(Ljava/util/List;)Ljava/util/List;" The list can be
+// of any class: the elements of one that immutableListElements can't read directly are read
+// with its own size() and get() (see listAccessors).
+func collectionsUnmodifiableListOf(params []interface{}) interface{} {
+	if object.IsNull(params[0]) {
+		return getGErrBlk(excNames.NullPointerException, "Collections.unmodifiableList: list is null")
+	}
+	backing := params[0].(*object.Object)
+
+	// as in the JDK, a list that is already unmodifiable is returned as is
+	if object.GoStringFromStringPoolIndex(backing.KlassName) == collectionsUnmodifiableList {
+		return backing
+	}
+
+	className := collectionsUnmodifiableList
+	theList := object.MakeEmptyObjectWithClassName(&className)
+	theList.FieldTable["c"] = object.Field{Ftype: "Ljava/util/Collection;", Fvalue: backing}
+	theList.FieldTable["list"] = object.Field{Ftype: "Ljava/util/List;", Fvalue: backing}
+	return theList
+}
+
+// immutableListElements returns the elements of a list: either one of the lists above or an
+// ArrayList (or a subclass of it), whose elements are the first size entries of its
+// elementData array.
+func immutableListElements(list *object.Object) ([]*object.Object, *GErrBlk) {
+	switch object.GoStringFromStringPoolIndex(list.KlassName) {
+	case collectionsEmptyList, collectionsSingletonList, collectionsCopiesList:
+		length, _ := immutableListLength(list)
+		elements := make([]*object.Object, length)
+		for i := range elements {
+			elements[i], _ = list.FieldTable["element"].Fvalue.(*object.Object)
+		}
+		return elements, nil
+	case collectionsUnmodifiableList:
+		return immutableListElements(list.FieldTable["list"].Fvalue.(*object.Object))
+	}
+	if isArrayList(list) {
+		return arrayListElements(list), nil
+	}
+	return nil, unsupportedList(list)
+}
+
+// immutableListLength returns the length of a list that immutableListElements can read,
+// without making a slice of its elements (which, for a CopiesList, can be very long)
+func immutableListLength(list *object.Object) (int64, *GErrBlk) {
+	switch object.GoStringFromStringPoolIndex(list.KlassName) {
+	case collectionsEmptyList:
+		return 0, nil
+	case collectionsSingletonList:
+		return 1, nil
+	case collectionsCopiesList:
+		return list.FieldTable["n"].Fvalue.(int64), nil
+	case collectionsUnmodifiableList:
+		return immutableListLength(list.FieldTable["list"].Fvalue.(*object.Object))
+	}
+	if isArrayList(list) {
+		return int64(len(arrayListElements(list))), nil
+	}
+	return 0, unsupportedList(list)
+}
+
+// immutableListAt returns the element at an index, which must be less than the length, of a
+// list that immutableListElements can read
+func immutableListAt(list *object.Object, index int64) *object.Object {
+	switch object.GoStringFromStringPoolIndex(list.KlassName) {
+	case collectionsSingletonList, collectionsCopiesList:
+		element, _ := list.FieldTable["element"].Fvalue.(*object.Object)
+		return element
+	case collectionsUnmodifiableList:
+		return immutableListAt(list.FieldTable["list"].Fvalue.(*object.Object), index)
+	}
+	return arrayListElements(list)[index]
+}
+
+func unsupportedList(list *object.Object) *GErrBlk {
+	className := object.GoStringFromStringPoolIndex(list.KlassName)
+	errMsg := fmt.Sprintf("Collections: lists of class %s are not supported", className)
+	return getGErrBlk(excNames.UnsupportedOperationException, errMsg)
+}
+
+// isDirectList reports whether immutableListElements can read the elements of a list directly
+func isDirectList(list *object.Object) bool {
+	switch object.GoStringFromStringPoolIndex(list.KlassName) {
+	case collectionsEmptyList, collectionsSingletonList, collectionsCopiesList:
+		return true
+	case collectionsUnmodifiableList:
+		return isDirectList(list.FieldTable["list"].Fvalue.(*object.Object))
+	}
+	return isArrayList(list)
+}
+
+// listAccessors returns the size of a list and a function that gets its element at an index.
+// The elements of the lists above and of an ArrayList are read directly; those of any other
+// list, such as a LinkedList, with its size() and get(), which are run through
+// globals.FuncInvokeMethod. The caller names the method for error messages.
+func listAccessors(fs *list.List, theList *object.Object, caller string) (int64, func(int64) (*object.Object, *GErrBlk), *GErrBlk) {
+	if object.GoStringFromStringPoolIndex(theList.KlassName) == collectionsUnmodifiableList {
+		theList = theList.FieldTable["list"].Fvalue.(*object.Object) // read the list it wraps
+	}
+	if isDirectList(theList) {
+		get := func(index int64) (*object.Object, *GErrBlk) { return immutableListAt(theList, index), nil }
+		length, errBlk := immutableListLength(theList)
+		return length, get, errBlk
+	}
+
+	var size any
+	if errBlk := mapInvoke(fs, theList, caller, "size", "()I", &size); errBlk != nil {
+		return 0, nil, errBlk
+	}
+	get := func(index int64) (*object.Object, *GErrBlk) {
+		var element any
+		if errBlk := mapInvoke(fs, theList, caller, "get", "(I)Ljava/lang/Object;", &element, index); errBlk != nil {
+			return nil, errBlk
+		}
+		obj, _ := element.(*object.Object) // the element can be null
+		return obj, nil
+	}
+	count, _ := size.(int64)
+	return count, get, nil
+}
+
+// collectionElements returns the elements of any Collection. Those of the lists above and of
+// an ArrayList are read directly; those of any other collection, such as a HashSet or a
+// LinkedList, come from its toArray(), which is run through globals.FuncInvokeMethod, so that
+// the collection can be implemented in Java. The caller names the method for error messages.
+func collectionElements(fs *list.List, collection *object.Object, caller string) ([]*object.Object, *GErrBlk) {
+	if isDirectList(collection) {
+		return immutableListElements(collection)
+	}
+	var ret any
+	if errBlk := mapInvoke(fs, collection, caller, "toArray", "()[Ljava/lang/Object;", &ret); errBlk != nil {
+		return nil, errBlk
+	}
+	if array, ok := ret.(*object.Object); ok && !object.IsNull(array) {
+		if elements, ok := array.FieldTable["value"].Fvalue.([]*object.Object); ok {
+			return elements, nil
+		}
+	}
+	return nil, getGErrBlk(excNames.VirtualMachineError, caller+": toArray() did not return an array")
+}
+
+// "java/util/Collections$...List.get(I)Ljava/lang/Object;"
+// params[0] = the frame stack, params[1] = the list, params[2] = the index
+func immutableListGet(params []interface{}) interface{} {
+	length, get, err := listAccessors(params[0].(*list.List), params[1].(*object.Object), "List.get")
+	if err != nil {
+		return err
+	}
+	index := params[2].(int64)
+	if index < 0 || index >= length {
+		errMsg := fmt.Sprintf("Index %d out of bounds for length %d", index, length)
+		return getGErrBlk(excNames.IndexOutOfBoundsException, errMsg)
+	}
+	element, err := get(index)
+	if err != nil {
+		return err
+	}
+	return element
+}
+
+// "java/util/Collections$...List.isEmpty()Z"
+// params[0] = the frame stack, params[1] = the list
+func immutableListIsEmpty(params []interface{}) interface{} {
+	length, _, err := listAccessors(params[0].(*list.List), params[1].(*object.Object), "List.isEmpty")
+	if err != nil {
+		return err
+	}
+	if length == 0 {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/Collections$...List.size()I"
+// params[0] = the frame stack, params[1] = the list
+func immutableListSize(params []interface{}) interface{} {
+	length, _, err := listAccessors(params[0].(*list.List), params[1].(*object.Object), "List.size")
+	if err != nil {
+		return err
+	}
+	return length
+}
+
+// add(), clear(), remove(), and set() of an immutable list
+func immutableListModify(params []interface{}) interface{} {
+	className := object.GoStringFromStringPoolIndex(params[0].(*object.Object).KlassName)
+	errMsg := fmt.Sprintf("%s cannot be modified", className)
+	return getGErrBlk(excNames.UnsupportedOperationException, errMsg)
+}
@@ -0,0 +1,546 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"errors"
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/statics"
+	"jacobin/types"
+	"math"
+	"testing"
+)
+
+func checkUnsupportedOperation(t *testing.T, ret interface{}, what string) {
+	errBlk, ok := ret.(*GErrBlk)
+	if !ok || errBlk.ExceptionType != excNames.UnsupportedOperationException {
+		t.Errorf("%s: expected UnsupportedOperationException, got: %v", what, ret)
+	}
+}
+
+func TestCollectionsSingletonList(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := frames.CreateFrameStack()
+
+	element := object.StringObjectFromGoString("only")
+	list := collectionsSingletonListOf([]interface{}{element}).(*object.Object)
+
+	if ret := immutableListGet([]interface{}{fs, list, int64(0)}); ret != element {
+		t.Errorf("Expected get(0) to return the element, got: %v", ret)
+	}
+	if size := immutableListSize([]interface{}{fs, list}); size != int64(1) {
+		t.Errorf("Expected size() of 1, got: %v", size)
+	}
+
+	ret := immutableListModify([]interface{}{list, object.StringObjectFromGoString("another")})
+	checkUnsupportedOperation(t, ret, "singletonList.add")
+
+	ret = immutableListGet([]interface{}{fs, list, int64(1)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IndexOutOfBoundsException {
+		t.Errorf("Expected IndexOutOfBoundsException from get(1), got: %v", ret)
+	}
+}
+
+func TestCollectionsEmptyList(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := frames.CreateFrameStack()
+
+	list := collectionsEmptyListOf(nil).(*object.Object)
+	if immutableListIsEmpty([]interface{}{fs, list}) != types.JavaBoolTrue {
+		t.Errorf("Expected emptyList() to be empty")
+	}
+	if size := immutableListSize([]interface{}{fs, list}); size != int64(0) {
+		t.Errorf("Expected size() of 0, got: %v", size)
+	}
+	checkUnsupportedOperation(t, immutableListModify([]interface{}{list}), "emptyList.clear")
+}
+
+func TestCollectionsClinit(t *testing.T) {
+	globals.InitGlobals("test")
+	defer func() {
+		for _, name := range []string{"EMPTY_SET", "EMPTY_LIST", "EMPTY_MAP"} {
+			delete(statics.Statics, "java/util/Collections."+name)
+		}
+	}()
+
+	if ret := collectionsClinit(nil); ret != nil {
+		t.Fatalf("Unexpected error from Collections.<clinit>: %v", ret)
+	}
+	for name, className := range map[string]string{"EMPTY_SET": collectionsEmptySet,
+		"EMPTY_LIST": collectionsEmptyList, "EMPTY_MAP": collectionsEmptyMap} {
+		obj, ok := statics.Statics["java/util/Collections."+name].Value.(*object.Object)
+		if !ok || object.GoStringFromStringPoolIndex(obj.KlassName) != className {
+			t.Errorf("Expected Collections.%s to be a %s, got: %v", name, className, obj)
+		}
+	}
+
+	// emptyList() returns EMPTY_LIST, as in the JDK
+	if ret := collectionsEmptyListOf(nil); ret != statics.Statics["java/util/Collections.EMPTY_LIST"].Value {
+		t.Errorf("Expected emptyList() to return Collections.EMPTY_LIST, got: %v", ret)
+	}
+}
+
+func TestCollectionsNCopies(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := frames.CreateFrameStack()
+
+	element := object.StringObjectFromGoString("copy")
+	list := collectionsNCopies([]interface{}{int64(3), element}).(*object.Object)
+	if size := immutableListSize([]interface{}{fs, list}); size != int64(3) {
+		t.Errorf("Expected size() of 3, got: %v", size)
+	}
+	if ret := immutableListGet([]interface{}{fs, list, int64(2)}); ret != element {
+		t.Errorf("Expected get(2) to return the element, got: %v", ret)
+	}
+	checkUnsupportedOperation(t, immutableListModify([]interface{}{list, int64(0), element}), "nCopies.set")
+
+	// the list holds the JDK's fields, not a copy of the element for each entry
+	if list.FieldTable["n"].Fvalue != int64(3) || list.FieldTable["element"].Fvalue != element {
+		t.Errorf("Expected the fields n = 3 and element, got: %v", list.FieldTable)
+	}
+	huge := collectionsNCopies([]interface{}{int64(1) << 40, element}).(*object.Object)
+	if size := immutableListSize([]interface{}{fs, huge}); size != int64(1)<<40 {
+		t.Errorf("Expected size() of 2^40, got: %v", size)
+	}
+
+	ret := collectionsNCopies([]interface{}{int64(-1), element})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("Expected IllegalArgumentException from nCopies(-1), got: %v", ret)
+	}
+}
+
+func TestCollectionsUnmodifiableListOfArrayList(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := frames.CreateFrameStack()
+
+	// an ArrayList with capacity for 4 elements, 2 of which are in use
+	className := "java/util/ArrayList"
+	arrayList := object.MakeEmptyObjectWithClassName(&className)
+	objClass := "java/lang/Object"
+	elementData := object.Make1DimRefArray(&objClass, 4)
+	elements := elementData.FieldTable["value"].Fvalue.([]*object.Object)
+	elements[0] = object.StringObjectFromGoString("first")
+	elements[1] = object.StringObjectFromGoString("second")
+	arrayList.FieldTable["elementData"] = object.Field{Ftype: types.RefArray + "java/lang/Object", Fvalue: elementData}
+	arrayList.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(2)}
+
+	list := collectionsUnmodifiableListOf([]interface{}{arrayList}).(*object.Object)
+	if list.FieldTable["c"].Fvalue != arrayList || list.FieldTable["list"].Fvalue != arrayList {
+		t.Errorf("Expected the fields c and list to hold the ArrayList, got: %v", list.FieldTable)
+	}
+	if size := immutableListSize([]interface{}{fs, list}); size != int64(2) {
+		t.Errorf("Expected size() of 2, got: %v", size)
+	}
+	if ret := immutableListGet([]interface{}{fs, list, int64(1)}); ret != elements[1] {
+		t.Errorf("Expected get(1) to return the second element, got: %v", ret)
+	}
+	checkUnsupportedOperation(t, immutableListModify([]interface{}{list, elements[0]}), "unmodifiableList.add")
+
+	// the unmodifiable list is a view, so it reflects changes to the ArrayList
+	elements[2] = object.StringObjectFromGoString("third")
+	arrayList.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(3)}
+	if ret := immutableListGet([]interface{}{fs, list, int64(2)}); ret != elements[2] {
+		t.Errorf("Expected get(2) to return the element added to the ArrayList, got: %v", ret)
+	}
+
+	// an unmodifiable list of an unmodifiable list is the same list
+	if again := collectionsUnmodifiableListOf([]interface{}{list}); again != list {
+		t.Errorf("Expected unmodifiableList() of an unmodifiable list to return that list")
+	}
+
+	ret := collectionsUnmodifiableListOf([]interface{}{object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException from unmodifiableList(null), got: %v", ret)
+	}
+}
+
+// makeTestIntegerList returns an ArrayList of Integers holding the given values
+func makeTestIntegerList(values ...int64) *object.Object {
+	list := makeTestArrayList(len(values))
+	data := list.FieldTable["elementData"].Fvalue.(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+	for i, value := range values {
+		data[i] = object.MakePrimitiveObject("java/lang/Integer", types.Int, value)
+	}
+	list.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(len(values))}
+	return list
+}
+
+func TestCollectionsMaxMin(t *testing.T) {
+	globals.InitGlobals("test")
+	list := makeTestIntegerList(3, -7, 42, 0, 42, 5)
+	fs := frames.CreateFrameStack()
+
+	max := collectionsMax([]interface{}{fs, list}).(*object.Object)
+	if value := max.FieldTable["value"].Fvalue; value != int64(42) {
+		t.Errorf("Collections.max(): expected 42, got %v", value)
+	}
+	min := collectionsMin([]interface{}{fs, list}).(*object.Object)
+	if value := min.FieldTable["value"].Fvalue; value != int64(-7) {
+		t.Errorf("Collections.min(): expected -7, got %v", value)
+	}
+
+	// a null comparator means natural ordering
+	max = collectionsMax([]interface{}{fs, list, object.Null}).(*object.Object)
+	if value := max.FieldTable["value"].Fvalue; value != int64(42) {
+		t.Errorf("Collections.max(list, null): expected 42, got %v", value)
+	}
+
+	strs := makeTestArrayList(0, "pear", "apple", "quince")
+	if str := object.GoStringFromStringObject(collectionsMin([]interface{}{fs, strs}).(*object.Object)); str != "apple" {
+		t.Errorf("Collections.min() of strings: expected apple, got %s", str)
+	}
+
+	ret := collectionsMax([]interface{}{fs, makeTestIntegerList()})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NoSuchElementException {
+		t.Errorf("Collections.max() of an empty list: expected a NoSuchElementException, got %v", ret)
+	}
+}
+
+func TestCollectionsBinarySearch(t *testing.T) {
+	globals.InitGlobals("test")
+	sorted := makeTestIntegerList(-7, 0, 3, 5, 42)
+	fs := frames.CreateFrameStack()
+
+	for _, test := range []struct {
+		key, expected int64
+	}{
+		{-7, 0}, {3, 2}, {42, 4}, // present
+		{-10, -1}, {1, -3}, {4, -4}, {100, -6}, // absent: -(insertion point)-1
+	} {
+		key := object.MakePrimitiveObject("java/lang/Integer", types.Int, test.key)
+		if ret := collectionsBinarySearch([]interface{}{fs, sorted, key}); ret != test.expected {
+			t.Errorf("Collections.binarySearch(list, %d): expected %d, got %v", test.key, test.expected, ret)
+		}
+	}
+
+	key := object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(1))
+	if ret := collectionsBinarySearch([]interface{}{fs, makeTestIntegerList(), key}); ret != int64(-1) {
+		t.Errorf("Collections.binarySearch() of an empty list: expected -1, got %v", ret)
+	}
+	ret := collectionsBinarySearch([]interface{}{fs, object.Null, key})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Collections.binarySearch(null, 1): expected a NullPointerException, got %v", ret)
+	}
+}
+
+// With a comparator, the list is sorted, and searched, in the comparator's order: here, descending
+func TestCollectionsBinarySearchWithComparator(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, _, _ string, args ...any) (any, error) {
+		a := args[0].(*object.Object).FieldTable["value"].Fvalue.(int64)
+		b := args[1].(*object.Object).FieldTable["value"].Fvalue.(int64)
+		return b - a, nil
+	}
+	className := "test/Descending"
+	comparator := object.MakeEmptyObjectWithClassName(&className)
+	descending := makeTestIntegerList(42, 5, 3, 0, -7)
+	fs := frames.CreateFrameStack()
+
+	for _, test := range []struct {
+		key, expected int64
+	}{{42, 0}, {0, 3}, {-7, 4}, {50, -1}, {4, -3}, {-10, -6}} {
+		key := object.MakePrimitiveObject("java/lang/Integer", types.Int, test.key)
+		if ret := collectionsBinarySearch([]interface{}{fs, descending, key, comparator}); ret != test.expected {
+			t.Errorf("Collections.binarySearch(list, %d, descending): expected %d, got %v",
+				test.key, test.expected, ret)
+		}
+	}
+}
+
+// stubToArray makes globals.FuncInvokeMethod return, for toArray(), an Object[] of the given
+// values (and fail for any other method). Returns a collection of the given class.
+func stubToArray(className string, values ...*object.Object) *object.Object {
+	elementType := "java/lang/Object"
+	array := object.Make1DimRefArray(&elementType, int64(len(values)))
+	copy(array.FieldTable["value"].Fvalue.([]*object.Object), values)
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, methodName, _ string, _ ...any) (any, error) {
+		if methodName != "toArray" {
+			return nil, errors.New("unexpected call of " + methodName)
+		}
+		return array, nil
+	}
+	return object.MakeEmptyObjectWithClassName(&className)
+}
+
+// Collections.max(), min(), and frequency() take any Collection, such as a HashSet
+func TestCollectionsMaxMinFrequencyOfSet(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	fs := frames.CreateFrameStack()
+	set := stubToArray("java/util/HashSet", object.StringObjectFromGoString("pear"),
+		object.StringObjectFromGoString("apple"), object.StringObjectFromGoString("quince"))
+
+	if str := object.GoStringFromStringObject(collectionsMax([]interface{}{fs, set}).(*object.Object)); str != "quince" {
+		t.Errorf("Collections.max() of a set: expected quince, got %s", str)
+	}
+	if str := object.GoStringFromStringObject(collectionsMin([]interface{}{fs, set}).(*object.Object)); str != "apple" {
+		t.Errorf("Collections.min() of a set: expected apple, got %s", str)
+	}
+	if count := collectionsFrequency([]interface{}{fs, set, object.StringObjectFromGoString("pear")}); count != int64(1) {
+		t.Errorf("Collections.frequency(set, pear): expected 1, got %v", count)
+	}
+}
+
+// As with Double.compareTo(), -0.0 is less than 0.0
+func TestCollectionsMaxMinOfSignedZeros(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := frames.CreateFrameStack()
+	zeros := makeTestArrayList(2)
+	data := zeros.FieldTable["elementData"].Fvalue.(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+	data[0] = object.MakePrimitiveObject("java/lang/Double", types.Double, 0.0)
+	data[1] = object.MakePrimitiveObject("java/lang/Double", types.Double, math.Copysign(0, -1))
+	zeros.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(2)}
+
+	if max := collectionsMax([]interface{}{fs, zeros}).(*object.Object); max != data[0] {
+		t.Errorf("Collections.max(0.0, -0.0): expected 0.0, got %v", max.FieldTable["value"].Fvalue)
+	}
+	if min := collectionsMin([]interface{}{fs, zeros}).(*object.Object); min != data[1] {
+		t.Errorf("Collections.min(0.0, -0.0): expected -0.0, got %v", min.FieldTable["value"].Fvalue)
+	}
+}
+
+// As with Double.equals(), NaN equals NaN and -0.0 doesn't equal 0.0
+func TestCollectionsFrequencyOfNaNsAndSignedZeros(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := frames.CreateFrameStack()
+	doubles := makeTestArrayList(3)
+	data := doubles.FieldTable["elementData"].Fvalue.(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+	data[0] = object.MakePrimitiveObject("java/lang/Double", types.Double, math.NaN())
+	data[1] = object.MakePrimitiveObject("java/lang/Double", types.Double, math.NaN())
+	data[2] = object.MakePrimitiveObject("java/lang/Double", types.Double, 0.0)
+	doubles.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(3)}
+
+	nan := object.MakePrimitiveObject("java/lang/Double", types.Double, math.NaN())
+	if count := collectionsFrequency([]interface{}{fs, doubles, nan}); count != int64(2) {
+		t.Errorf("Collections.frequency(list, NaN): expected 2, got %v", count)
+	}
+	negativeZero := object.MakePrimitiveObject("java/lang/Double", types.Double, math.Copysign(0, -1))
+	if count := collectionsFrequency([]interface{}{fs, doubles, negativeZero}); count != int64(0) {
+		t.Errorf("Collections.frequency(list, -0.0): expected 0, got %v", count)
+	}
+	floatNaN := object.MakePrimitiveObject("java/lang/Float", types.Float, math.NaN())
+	if equal, _ := objectsEqual(fs, floatNaN, object.MakePrimitiveObject("java/lang/Float", types.Float, math.NaN())); !equal {
+		t.Errorf("Float.NaN.equals(Float.NaN): expected true")
+	}
+}
+
+func TestCollectionsFrequency(t *testing.T) {
+	globals.InitGlobals("test")
+	list := makeTestIntegerList(3, 42, 7, 42, 42)
+	fs := frames.CreateFrameStack()
+
+	fortyTwo := object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(42))
+	if count := collectionsFrequency([]interface{}{fs, list, fortyTwo}); count != int64(3) {
+		t.Errorf("Collections.frequency(list, 42): expected 3, got %v", count)
+	}
+	longFortyTwo := object.MakePrimitiveObject("java/lang/Long", types.Long, int64(42))
+	if count := collectionsFrequency([]interface{}{fs, list, longFortyTwo}); count != int64(0) {
+		t.Errorf("Collections.frequency(list, 42L): expected 0, got %v", count)
+	}
+
+	withNulls := collectionsNCopies([]interface{}{int64(4), object.Null}).(*object.Object)
+	if count := collectionsFrequency([]interface{}{fs, withNulls, object.Null}); count != int64(4) {
+		t.Errorf("Collections.frequency(nCopies(4, null), null): expected 4, got %v", count)
+	}
+}
+
+func TestCollectionsAddAll(t *testing.T) {
+	globals.InitGlobals("test")
+	list := makeTestArrayList(0, "a")
+	fs := frames.CreateFrameStack()
+
+	elementType := "java/lang/Object"
+	elements := object.Make1DimRefArray(&elementType, 3)
+	for i, str := range []string{"b", "c", "d"} {
+		elements.FieldTable["value"].Fvalue.([]*object.Object)[i] = object.StringObjectFromGoString(str)
+	}
+	if ret := collectionsAddAll([]interface{}{fs, list, elements}); ret != types.JavaBoolTrue {
+		t.Errorf("Collections.addAll(list, b, c, d): expected true, got %v", ret)
+	}
+	checkStrings(t, "Collections.addAll(list, b, c, d)", arrayListStrings(t, list), []string{"a", "b", "c", "d"})
+
+	empty := object.Make1DimRefArray(&elementType, 0)
+	if ret := collectionsAddAll([]interface{}{fs, list, empty}); ret != types.JavaBoolFalse {
+		t.Errorf("Collections.addAll(list) with no elements: expected false, got %v", ret)
+	}
+
+	ret := collectionsAddAll([]interface{}{fs, object.Null, elements})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected NullPointerException from addAll(null, ...), got: %v", ret)
+	}
+}
+
+func TestCollectionsDisjoint(t *testing.T) {
+	globals.InitGlobals("test")
+	fs := frames.CreateFrameStack()
+
+	if ret := collectionsDisjoint([]interface{}{fs, makeTestIntegerList(1, 2, 3), makeTestIntegerList(4, 5)}); ret != types.JavaBoolTrue {
+		t.Errorf("Collections.disjoint([1, 2, 3], [4, 5]): expected true, got %v", ret)
+	}
+	if ret := collectionsDisjoint([]interface{}{fs, makeTestIntegerList(1, 2, 3), makeTestIntegerList(5, 3)}); ret != types.JavaBoolFalse {
+		t.Errorf("Collections.disjoint([1, 2, 3], [5, 3]): expected false, got %v", ret)
+	}
+	if ret := collectionsDisjoint([]interface{}{fs, makeTestIntegerList(1), collectionsEmptyListOf(nil)}); ret != types.JavaBoolTrue {
+		t.Errorf("Collections.disjoint([1], []): expected true, got %v", ret)
+	}
+
+	// null elements are equal only to each other
+	nulls := collectionsNCopies([]interface{}{int64(2), object.Null})
+	if ret := collectionsDisjoint([]interface{}{fs, nulls, makeTestIntegerList(1)}); ret != types.JavaBoolTrue {
+		t.Errorf("Collections.disjoint([null, null], [1]): expected true, got %v", ret)
+	}
+	if ret := collectionsDisjoint([]interface{}{fs, makeTestIntegerList(1), nulls}); ret != types.JavaBoolTrue {
+		t.Errorf("Collections.disjoint([1], [null, null]): expected true, got %v", ret)
+	}
+	if ret := collectionsDisjoint([]interface{}{fs, nulls, nulls}); ret != types.JavaBoolFalse {
+		t.Errorf("Collections.disjoint([null, null], [null, null]): expected false, got %v", ret)
+	}
+}
+
+func TestCollectionsSwap(t *testing.T) {
+	globals.InitGlobals("test")
+	list := makeTestArrayList(2, "a", "b", "c")
+	fs := frames.CreateFrameStack()
+
+	if ret := collectionsSwap([]interface{}{fs, list, int64(0), int64(2)}); ret != nil {
+		t.Fatalf("Collections.swap(list, 0, 2): unexpected error %v", ret)
+	}
+	checkStrings(t, "Collections.swap(list, 0, 2)", arrayListStrings(t, list), []string{"c", "b", "a"})
+
+	ret := collectionsSwap([]interface{}{fs, list, int64(1), int64(3)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IndexOutOfBoundsException {
+		t.Errorf("Expected IndexOutOfBoundsException from swap(list, 1, 3), got: %v", ret)
+	}
+
+	singleton := collectionsSingletonListOf([]interface{}{object.StringObjectFromGoString("x")})
+	checkUnsupportedOperation(t, collectionsSwap([]interface{}{fs, singleton, int64(0), int64(0)}), "swap of a singletonList")
+}
+
+// A list other than an ArrayList or an immutable list, such as a LinkedList, is swapped with
+// its get() and set()
+func TestCollectionsSwapOfOtherList(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	elements := []*object.Object{object.StringObjectFromGoString("a"),
+		object.StringObjectFromGoString("b"), object.StringObjectFromGoString("c")}
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, methodName, _ string, args ...any) (any, error) {
+		index := args[0].(int64)
+		previous := elements[index]
+		if methodName == "set" {
+			elements[index] = args[1].(*object.Object)
+		}
+		return previous, nil
+	}
+	className := "java/util/LinkedList"
+	linkedList := object.MakeEmptyObjectWithClassName(&className)
+
+	if ret := collectionsSwap([]interface{}{frames.CreateFrameStack(), linkedList, int64(0), int64(2)}); ret != nil {
+		t.Fatalf("Collections.swap(linkedList, 0, 2): unexpected error %v", ret)
+	}
+	var got []string
+	for _, element := range elements {
+		got = append(got, object.GoStringFromStringObject(element))
+	}
+	checkStrings(t, "Collections.swap(linkedList, 0, 2)", got, []string{"c", "b", "a"})
+}
+
+// Collections.disjoint() takes any Collection, such as a HashSet
+func TestCollectionsDisjointOfSet(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	fs := frames.CreateFrameStack()
+	set := stubToArray("java/util/HashSet", object.StringObjectFromGoString("a"),
+		object.StringObjectFromGoString("b"))
+
+	if ret := collectionsDisjoint([]interface{}{fs, set, makeTestArrayList(0, "c")}); ret != types.JavaBoolTrue {
+		t.Errorf("Collections.disjoint(set, [c]): expected true, got %v", ret)
+	}
+	if ret := collectionsDisjoint([]interface{}{fs, makeTestArrayList(0, "c", "b"), set}); ret != types.JavaBoolFalse {
+		t.Errorf("Collections.disjoint([c, b], set): expected false, got %v", ret)
+	}
+}
+
+// Collections.binarySearch() reads a list other than an ArrayList, such as a LinkedList, with
+// its size() and get()
+func TestCollectionsBinarySearchOfOtherList(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	elements := makeTestIntegerList(-7, 0, 3, 5, 42)
+	gets := 0
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, methodName, _ string, args ...any) (any, error) {
+		if methodName == "size" {
+			return int64(5), nil
+		}
+		gets++
+		return arrayListElements(elements)[args[0].(int64)], nil
+	}
+	className := "java/util/LinkedList"
+	linkedList := object.MakeEmptyObjectWithClassName(&className)
+	fs := frames.CreateFrameStack()
+
+	key := object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(5))
+	if ret := collectionsBinarySearch([]interface{}{fs, linkedList, key}); ret != int64(3) {
+		t.Errorf("Collections.binarySearch(linkedList, 5): expected 3, got %v", ret)
+	}
+	if gets > 3 {
+		t.Errorf("Collections.binarySearch(linkedList, 5): expected at most 3 calls of get(), got %d", gets)
+	}
+	key = object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(4))
+	if ret := collectionsBinarySearch([]interface{}{fs, linkedList, key}); ret != int64(-4) {
+		t.Errorf("Collections.binarySearch(linkedList, 4): expected -4, got %v", ret)
+	}
+}
+
+// Collections.unmodifiableList() accepts a list other than an ArrayList, such as a LinkedList,
+// and reads it with its size() and get()
+func TestCollectionsUnmodifiableListOfOtherList(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	elements := makeTestIntegerList(10, 20, 30)
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, methodName, _ string, args ...any) (any, error) {
+		switch methodName {
+		case "size":
+			return int64(3), nil
+		case "get":
+			return arrayListElements(elements)[args[0].(int64)], nil
+		}
+		return nil, errors.New("unexpected call of " + methodName)
+	}
+	className := "java/util/LinkedList"
+	linkedList := object.MakeEmptyObjectWithClassName(&className)
+	fs := frames.CreateFrameStack()
+
+	ret := collectionsUnmodifiableListOf([]interface{}{linkedList})
+	theList, ok := ret.(*object.Object)
+	if !ok || theList.FieldTable["list"].Fvalue != linkedList {
+		t.Fatalf("Expected an unmodifiable list of the LinkedList, got: %v", ret)
+	}
+	if size := immutableListSize([]interface{}{fs, theList}); size != int64(3) {
+		t.Errorf("Expected size() of 3, got: %v", size)
+	}
+	if immutableListIsEmpty([]interface{}{fs, theList}) != types.JavaBoolFalse {
+		t.Errorf("Expected isEmpty() to be false")
+	}
+	if ret := immutableListGet([]interface{}{fs, theList, int64(2)}); ret != arrayListElements(elements)[2] {
+		t.Errorf("Expected get(2) to return the third element, got: %v", ret)
+	}
+	ret = immutableListGet([]interface{}{fs, theList, int64(3)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IndexOutOfBoundsException {
+		t.Errorf("Expected IndexOutOfBoundsException from get(3), got: %v", ret)
+	}
+	checkUnsupportedOperation(t, immutableListModify([]interface{}{theList, int64(0)}), "unmodifiableList.remove")
+}
@@ -0,0 +1,601 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/types"
+	"math"
+	"sync"
+)
+
+// Implementation of the basic operations of java.util.concurrent.ConcurrentHashMap.
+//
+// Rather than the JDK's lock-free table, the entries are kept in a golang map guarded by
+// a mutex, which is held in the map object's "entries" field. This makes each operation
+// atomic with respect to the others, across Jacobin threads. As in the JDK, neither keys
+// nor values can be null. A String or boxed primitive key is matched by its class and value,
+// directly. Any other key is matched as in the JDK: the golang map holds a bucket of entries
+// for each hashCode() of their keys, and the key is compared with the keys in its bucket
+// with equals(). Both methods are run through globals.FuncInvokeMethod (while the mutex is
+// held, as the JDK holds a bin's lock while it calls equals()).
+//
+// Because the entries aren't in the JDK's "table" field, none of the JDK's bytecode for the
+// class can run against them. So every public constructor and method that's not implemented
+// here, such as the views (keySet(), values(), entrySet()), the methods that take a function
+// (compute(), merge(), forEach(), and the bulk operations), and toString(), is trapped.
+
+const concurrentHashMapEntries = "entries"
+
+type concurrentHashMapTable struct {
+	sync.Mutex
+	buckets map[any][]concurrentHashMapEntry
+	size    int64
+}
+
+type concurrentHashMapEntry struct {
+	key   *object.Object
+	value *object.Object
+}
+
+// concurrentHashMapValueKey is the golang map key of a string or boxed primitive key
+type concurrentHashMapValueKey struct {
+	className uint32
+	value     any
+}
+
+func Load_Util_Concurrent_ConcurrentHashMap() {
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  concurrentHashMapInit,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  concurrentHashMapInitCapacity,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.<init>(IF)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  concurrentHashMapInitLoadFactor,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.<init>(IFI)V"] =
+		GMeth{
+			ParamSlots: 3,
+			GFunction:  concurrentHashMapInitLoadFactor,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.clear()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  concurrentHashMapClear,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.contains(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    concurrentHashMapContainsValue,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.containsKey(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    concurrentHashMapContainsKey,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.containsValue(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    concurrentHashMapContainsValue,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.get(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    concurrentHashMapGet,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    concurrentHashMapGetOrDefault,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.isEmpty()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  concurrentHashMapIsEmpty,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.mappingCount()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  concurrentHashMapSize,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    concurrentHashMapPut,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.putIfAbsent(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    concurrentHashMapPutIfAbsent,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.remove(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    concurrentHashMapRemove,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.remove(Ljava/lang/Object;Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    concurrentHashMapRemoveValue,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.replace(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    concurrentHashMapReplace,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.replace(Ljava/lang/Object;Ljava/lang/Object;Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   3,
+			GFunction:    concurrentHashMapReplaceValue,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/concurrent/ConcurrentHashMap.size()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  concurrentHashMapSize,
+		}
+
+	// the constructors and methods that would run the JDK's bytecode against its own table
+	trapMethods("java/util/concurrent/ConcurrentHashMap",
+		"<init>(Ljava/util/Map;)V",
+		"compute(Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"computeIfAbsent(Ljava/lang/Object;Ljava/util/function/Function;)Ljava/lang/Object;",
+		"computeIfPresent(Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"elements()Ljava/util/Enumeration;",
+		"entrySet()Ljava/util/Set;",
+		"equals(Ljava/lang/Object;)Z",
+		"forEach(JLjava/util/function/BiConsumer;)V",
+		"forEach(JLjava/util/function/BiFunction;Ljava/util/function/Consumer;)V",
+		"forEach(Ljava/util/function/BiConsumer;)V",
+		"forEachEntry(JLjava/util/function/Consumer;)V",
+		"forEachEntry(JLjava/util/function/Function;Ljava/util/function/Consumer;)V",
+		"forEachKey(JLjava/util/function/Consumer;)V",
+		"forEachKey(JLjava/util/function/Function;Ljava/util/function/Consumer;)V",
+		"forEachValue(JLjava/util/function/Consumer;)V",
+		"forEachValue(JLjava/util/function/Function;Ljava/util/function/Consumer;)V",
+		"hashCode()I",
+		"keySet()Ljava/util/concurrent/ConcurrentHashMap$KeySetView;",
+		"keySet()Ljava/util/Set;",
+		"keySet(Ljava/lang/Object;)Ljava/util/concurrent/ConcurrentHashMap$KeySetView;",
+		"keys()Ljava/util/Enumeration;",
+		"merge(Ljava/lang/Object;Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"newKeySet()Ljava/util/concurrent/ConcurrentHashMap$KeySetView;",
+		"newKeySet(I)Ljava/util/concurrent/ConcurrentHashMap$KeySetView;",
+		"putAll(Ljava/util/Map;)V",
+		"reduce(JLjava/util/function/BiFunction;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"reduceEntries(JLjava/util/function/BiFunction;)Ljava/util/Map$Entry;",
+		"reduceEntries(JLjava/util/function/Function;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"reduceEntriesToDouble(JLjava/util/function/ToDoubleFunction;DLjava/util/function/DoubleBinaryOperator;)D",
+		"reduceEntriesToInt(JLjava/util/function/ToIntFunction;ILjava/util/function/IntBinaryOperator;)I",
+		"reduceEntriesToLong(JLjava/util/function/ToLongFunction;JLjava/util/function/LongBinaryOperator;)J",
+		"reduceKeys(JLjava/util/function/BiFunction;)Ljava/lang/Object;",
+		"reduceKeys(JLjava/util/function/Function;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"reduceKeysToDouble(JLjava/util/function/ToDoubleFunction;DLjava/util/function/DoubleBinaryOperator;)D",
+		"reduceKeysToInt(JLjava/util/function/ToIntFunction;ILjava/util/function/IntBinaryOperator;)I",
+		"reduceKeysToLong(JLjava/util/function/ToLongFunction;JLjava/util/function/LongBinaryOperator;)J",
+		"reduceToDouble(JLjava/util/function/ToDoubleBiFunction;DLjava/util/function/DoubleBinaryOperator;)D",
+		"reduceToInt(JLjava/util/function/ToIntBiFunction;ILjava/util/function/IntBinaryOperator;)I",
+		"reduceToLong(JLjava/util/function/ToLongBiFunction;JLjava/util/function/LongBinaryOperator;)J",
+		"reduceValues(JLjava/util/function/BiFunction;)Ljava/lang/Object;",
+		"reduceValues(JLjava/util/function/Function;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"reduceValuesToDouble(JLjava/util/function/ToDoubleFunction;DLjava/util/function/DoubleBinaryOperator;)D",
+		"reduceValuesToInt(JLjava/util/function/ToIntFunction;ILjava/util/function/IntBinaryOperator;)I",
+		"reduceValuesToLong(JLjava/util/function/ToLongFunction;JLjava/util/function/LongBinaryOperator;)J",
+		"replaceAll(Ljava/util/function/BiFunction;)V",
+		"search(JLjava/util/function/BiFunction;)Ljava/lang/Object;",
+		"searchEntries(JLjava/util/function/Function;)Ljava/lang/Object;",
+		"searchKeys(JLjava/util/function/Function;)Ljava/lang/Object;",
+		"searchValues(JLjava/util/function/Function;)Ljava/lang/Object;",
+		"toString()Ljava/lang/String;",
+		"values()Ljava/util/Collection;",
+	)
+}
+
+// "java/util/concurrent/ConcurrentHashMap.<init>()V"
+func concurrentHashMapInit(params []interface{}) interface{} {
+	table := &concurrentHashMapTable{buckets: make(map[any][]concurrentHashMapEntry)}
+	params[0].(*object.Object).FieldTable[concurrentHashMapEntries] = object.Field{Ftype: types.Ref, Fvalue: table}
+	return nil
+}
+
+// "java/util/concurrent/ConcurrentHashMap.<init>(I)V" The golang map grows as needed,
+// so the initial capacity is only validated.
+func concurrentHashMapInitCapacity(params []interface{}) interface{} {
+	if params[1].(int64) < 0 {
+		return getGErrBlk(excNames.IllegalArgumentException, "ConcurrentHashMap: initial capacity is negative")
+	}
+	return concurrentHashMapInit(params)
+}
+
+// "java/util/concurrent/ConcurrentHashMap.<init>(IF)V" and
+// "java/util/concurrent/ConcurrentHashMap.<init>(IFI)V" The load factor and concurrency level
+// are validated, as in the JDK, but not otherwise used.
+func concurrentHashMapInitLoadFactor(params []interface{}) interface{} {
+	loadFactor := params[2].(float64)
+	if !(loadFactor > 0) || params[1].(int64) < 0 || (len(params) > 3 && params[3].(int64) <= 0) {
+		return getGErrBlk(excNames.IllegalArgumentException, "ConcurrentHashMap: illegal initial capacity, load factor, or concurrency level")
+	}
+	return concurrentHashMapInit(params)
+}
+
+// concurrentHashMapEntriesOf returns the table of entries of a map, or an error block if the
+// map wasn't created by one of the constructors here
+func concurrentHashMapEntriesOf(chm *object.Object, method string) (*concurrentHashMapTable, *GErrBlk) {
+	table, ok := chm.FieldTable[concurrentHashMapEntries].Fvalue.(*concurrentHashMapTable)
+	if !ok {
+		errMsg := "ConcurrentHashMap." + method + ": the map has no table of entries"
+		return nil, getGErrBlk(excNames.IllegalStateException, errMsg)
+	}
+	return table, nil
+}
+
+// concurrentHashMapTableOf returns the table of entries of a map and the golang map key of
+// the bucket for the given key, or an error block if the key is null or its hashCode() fails.
+// params[0] = the frame stack, params[1] = the map, params[2] = the key
+func concurrentHashMapTableOf(params []interface{}, method string) (*concurrentHashMapTable, any, *GErrBlk) {
+	if object.IsNull(params[2]) {
+		return nil, nil, getGErrBlk(excNames.NullPointerException, "ConcurrentHashMap."+method+": key is null")
+	}
+	table, errBlk := concurrentHashMapEntriesOf(params[1].(*object.Object), method)
+	if errBlk != nil {
+		return nil, nil, errBlk
+	}
+	mapKey, errBlk := concurrentHashMapKey(params[0].(*list.List), params[2].(*object.Object), method)
+	return table, mapKey, errBlk
+}
+
+// concurrentHashMapKey returns the golang map key of the bucket for a Java key: for a String
+// or a boxed primitive, its class and value (a double by its bits, as Double.equals() compares
+// them); for any other object, its hashCode()
+func concurrentHashMapKey(fs *list.List, key *object.Object, method string) (any, *GErrBlk) {
+	if value, ok := boxedValue(key); ok {
+		if number, ok := value.(float64); ok {
+			if math.IsNaN(number) {
+				number = math.NaN() // doubleToLongBits() gives all NaNs the same bits
+			}
+			value = math.Float64bits(number)
+		}
+		return concurrentHashMapValueKey{key.KlassName, value}, nil
+	}
+	var hash any
+	if errBlk := mapInvoke(fs, key, "ConcurrentHashMap."+method, "hashCode", "()I", &hash); errBlk != nil {
+		return nil, errBlk
+	}
+	return hash, nil
+}
+
+// find returns the index of the key's entry in the bucket, or -1 if the key is not in the
+// map. The table must be locked.
+func (table *concurrentHashMapTable) find(fs *list.List, mapKey any, key *object.Object) (int, *GErrBlk) {
+	for i, entry := range table.buckets[mapKey] {
+		if _, ok := mapKey.(concurrentHashMapValueKey); ok {
+			return i, nil // each value key has a bucket of its own
+		}
+		equal, errBlk := objectsEqual(fs, key, entry.key)
+		if errBlk != nil {
+			return -1, errBlk
+		}
+		if equal {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// "java/util/concurrent/ConcurrentHashMap.containsKey(Ljava/lang/Object;)Z"
+func concurrentHashMapContainsKey(params []interface{}) interface{} {
+	table, mapKey, errBlk := concurrentHashMapTableOf(params, "containsKey")
+	if errBlk != nil {
+		return errBlk
+	}
+	table.Lock()
+	defer table.Unlock()
+	index, errBlk := table.find(params[0].(*list.List), mapKey, params[2].(*object.Object))
+	if errBlk != nil {
+		return errBlk
+	}
+	if index >= 0 {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/concurrent/ConcurrentHashMap.get(Ljava/lang/Object;)Ljava/lang/Object;"
+func concurrentHashMapGet(params []interface{}) interface{} {
+	table, mapKey, errBlk := concurrentHashMapTableOf(params, "get")
+	if errBlk != nil {
+		return errBlk
+	}
+	table.Lock()
+	defer table.Unlock()
+	index, errBlk := table.find(params[0].(*list.List), mapKey, params[2].(*object.Object))
+	if errBlk != nil {
+		return errBlk
+	}
+	if index >= 0 {
+		return table.buckets[mapKey][index].value
+	}
+	return object.Null
+}
+
+// "java/util/concurrent/ConcurrentHashMap.getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+func concurrentHashMapGetOrDefault(params []interface{}) interface{} {
+	value := concurrentHashMapGet(params)
+	if object.IsNull(value) {
+		return params[3]
+	}
+	return value
+}
+
+// "java/util/concurrent/ConcurrentHashMap.isEmpty()Z"
+func concurrentHashMapIsEmpty(params []interface{}) interface{} {
+	size := concurrentHashMapSize(params)
+	if errBlk, ok := size.(*GErrBlk); ok {
+		return errBlk
+	}
+	if size.(int64) == 0 {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/concurrent/ConcurrentHashMap.clear()V"
+func concurrentHashMapClear(params []interface{}) interface{} {
+	table, errBlk := concurrentHashMapEntriesOf(params[0].(*object.Object), "clear")
+	if errBlk != nil {
+		return errBlk
+	}
+	table.Lock()
+	defer table.Unlock()
+	table.buckets = make(map[any][]concurrentHashMapEntry)
+	table.size = 0
+	return nil
+}
+
+// "java/util/concurrent/ConcurrentHashMap.containsValue(Ljava/lang/Object;)Z" and the legacy
+// "java/util/concurrent/ConcurrentHashMap.contains(Ljava/lang/Object;)Z" compare the value
+// with each value in the map with equals()
+func concurrentHashMapContainsValue(params []interface{}) interface{} {
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "ConcurrentHashMap.containsValue: value is null")
+	}
+	table, errBlk := concurrentHashMapEntriesOf(params[1].(*object.Object), "containsValue")
+	if errBlk != nil {
+		return errBlk
+	}
+	table.Lock()
+	defer table.Unlock()
+	for _, bucket := range table.buckets {
+		for _, entry := range bucket {
+			equal, errBlk := objectsEqual(params[0].(*list.List), params[2].(*object.Object), entry.value)
+			if errBlk != nil {
+				return errBlk
+			}
+			if equal {
+				return types.JavaBoolTrue
+			}
+		}
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/concurrent/ConcurrentHashMap.put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+// Returns the previous value for the key, or null if there was none.
+func concurrentHashMapPut(params []interface{}) interface{} {
+	return concurrentHashMapStore(params, "put", true)
+}
+
+// "java/util/concurrent/ConcurrentHashMap.putIfAbsent(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+// Returns the current value for the key, which is left unchanged, or null if there was none.
+func concurrentHashMapPutIfAbsent(params []interface{}) interface{} {
+	return concurrentHashMapStore(params, "putIfAbsent", false)
+}
+
+// params[0] = the frame stack, params[1] = the map, params[2] = the key, params[3] = the value
+func concurrentHashMapStore(params []interface{}, method string, replace bool) interface{} {
+	table, mapKey, errBlk := concurrentHashMapTableOf(params, method)
+	if errBlk != nil {
+		return errBlk
+	}
+	if object.IsNull(params[3]) {
+		return getGErrBlk(excNames.NullPointerException, "ConcurrentHashMap."+method+": value is null")
+	}
+	key, value := params[2].(*object.Object), params[3].(*object.Object)
+
+	table.Lock()
+	defer table.Unlock()
+	index, errBlk := table.find(params[0].(*list.List), mapKey, key)
+	if errBlk != nil {
+		return errBlk
+	}
+	if index < 0 {
+		table.buckets[mapKey] = append(table.buckets[mapKey], concurrentHashMapEntry{key: key, value: value})
+		table.size++
+		return object.Null
+	}
+	previous := table.buckets[mapKey][index].value
+	if replace {
+		table.buckets[mapKey][index].value = value
+	}
+	return previous
+}
+
+// "java/util/concurrent/ConcurrentHashMap.remove(Ljava/lang/Object;)Ljava/lang/Object;"
+// Returns the value that was removed, or null if the key was not in the map.
+func concurrentHashMapRemove(params []interface{}) interface{} {
+	table, mapKey, errBlk := concurrentHashMapTableOf(params, "remove")
+	if errBlk != nil {
+		return errBlk
+	}
+	table.Lock()
+	defer table.Unlock()
+	index, errBlk := table.find(params[0].(*list.List), mapKey, params[2].(*object.Object))
+	if errBlk != nil {
+		return errBlk
+	}
+	if index < 0 {
+		return object.Null
+	}
+	bucket := table.buckets[mapKey]
+	value := bucket[index].value
+	if len(bucket) == 1 {
+		delete(table.buckets, mapKey)
+	} else {
+		table.buckets[mapKey] = append(bucket[:index:index], bucket[index+1:]...)
+	}
+	table.size--
+	return value
+}
+
+// "java/util/concurrent/ConcurrentHashMap.size()I" and
+// "java/util/concurrent/ConcurrentHashMap.mappingCount()J"
+func concurrentHashMapSize(params []interface{}) interface{} {
+	table, errBlk := concurrentHashMapEntriesOf(params[0].(*object.Object), "size")
+	if errBlk != nil {
+		return errBlk
+	}
+	table.Lock()
+	defer table.Unlock()
+	return table.size
+}
+
+// "java/util/concurrent/ConcurrentHashMap.remove(Ljava/lang/Object;Ljava/lang/Object;)Z" removes
+// the key's entry only if its value equals the given one
+func concurrentHashMapRemoveValue(params []interface{}) interface{} {
+	if object.IsNull(params[3]) {
+		return types.JavaBoolFalse // as in the JDK, no entry has a null value
+	}
+	table, mapKey, errBlk := concurrentHashMapTableOf(params, "remove")
+	if errBlk != nil {
+		return errBlk
+	}
+	table.Lock()
+	defer table.Unlock()
+	index, errBlk := table.findValue(params[0].(*list.List), mapKey, params[2].(*object.Object), params[3].(*object.Object))
+	if errBlk != nil {
+		return errBlk
+	}
+	if index < 0 {
+		return types.JavaBoolFalse
+	}
+	bucket := table.buckets[mapKey]
+	if len(bucket) == 1 {
+		delete(table.buckets, mapKey)
+	} else {
+		table.buckets[mapKey] = append(bucket[:index:index], bucket[index+1:]...)
+	}
+	table.size--
+	return types.JavaBoolTrue
+}
+
+// "java/util/concurrent/ConcurrentHashMap.replace(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+// replaces the value of a key that's in the map. Returns the previous value, or null if the
+// key was not in the map, which is left unchanged.
+func concurrentHashMapReplace(params []interface{}) interface{} {
+	table, mapKey, errBlk := concurrentHashMapTableOf(params, "replace")
+	if errBlk != nil {
+		return errBlk
+	}
+	if object.IsNull(params[3]) {
+		return getGErrBlk(excNames.NullPointerException, "ConcurrentHashMap.replace: value is null")
+	}
+	table.Lock()
+	defer table.Unlock()
+	index, errBlk := table.find(params[0].(*list.List), mapKey, params[2].(*object.Object))
+	if errBlk != nil {
+		return errBlk
+	}
+	if index < 0 {
+		return object.Null
+	}
+	previous := table.buckets[mapKey][index].value
+	table.buckets[mapKey][index].value = params[3].(*object.Object)
+	return previous
+}
+
+// "java/util/concurrent/ConcurrentHashMap.replace(Ljava/lang/Object;Ljava/lang/Object;Ljava/lang/Object;)Z"
+// replaces the value of a key only if its current value equals the given old value
+// params[0] = the frame stack, params[1] = the map, params[2] = the key,
+// params[3] = the old value, params[4] = the new value
+func concurrentHashMapReplaceValue(params []interface{}) interface{} {
+	table, mapKey, errBlk := concurrentHashMapTableOf(params, "replace")
+	if errBlk != nil {
+		return errBlk
+	}
+	if object.IsNull(params[3]) || object.IsNull(params[4]) {
+		return getGErrBlk(excNames.NullPointerException, "ConcurrentHashMap.replace: value is null")
+	}
+	table.Lock()
+	defer table.Unlock()
+	index, errBlk := table.findValue(params[0].(*list.List), mapKey, params[2].(*object.Object), params[3].(*object.Object))
+	if errBlk != nil {
+		return errBlk
+	}
+	if index < 0 {
+		return types.JavaBoolFalse
+	}
+	table.buckets[mapKey][index].value = params[4].(*object.Object)
+	return types.JavaBoolTrue
+}
+
+// findValue returns the index of the key's entry in the bucket if the entry's value equals the
+// given value, or -1 otherwise. The table must be locked.
+func (table *concurrentHashMapTable) findValue(fs *list.List, mapKey any, key, value *object.Object) (int, *GErrBlk) {
+	index, errBlk := table.find(fs, mapKey, key)
+	if errBlk != nil || index < 0 {
+		return -1, errBlk
+	}
+	equal, errBlk := objectsEqual(fs, value, table.buckets[mapKey][index].value)
+	if errBlk != nil || !equal {
+		return -1, errBlk
+	}
+	return index, nil
+}
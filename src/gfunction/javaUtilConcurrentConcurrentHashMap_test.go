@@ -0,0 +1,238 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"sync"
+	"testing"
+)
+
+func makeTestConcurrentHashMap() *object.Object {
+	className := "java/util/concurrent/ConcurrentHashMap"
+	chm := object.MakeEmptyObjectWithClassName(&className)
+	concurrentHashMapInit([]interface{}{chm})
+	return chm
+}
+
+func TestConcurrentHashMapOperations(t *testing.T) {
+	globals.InitGlobals("test")
+	chm := makeTestConcurrentHashMap()
+	fs := frames.CreateFrameStack()
+
+	one := object.StringObjectFromGoString("one")
+	uno := object.StringObjectFromGoString("uno")
+	eins := object.StringObjectFromGoString("eins")
+
+	if ret := concurrentHashMapPut([]interface{}{fs, chm, object.StringObjectFromGoString("one"), uno}); ret != object.Null {
+		t.Errorf("Expected put of a new key to return null, got: %v", ret)
+	}
+	// a different String object with the same value is the same key
+	if ret := concurrentHashMapPutIfAbsent([]interface{}{fs, chm, one, eins}); ret != uno {
+		t.Errorf("Expected putIfAbsent of an existing key to return its value, got: %v", ret)
+	}
+	if ret := concurrentHashMapGet([]interface{}{fs, chm, one}); ret != uno {
+		t.Errorf("Expected putIfAbsent to leave the value unchanged, got: %v", ret)
+	}
+	if ret := concurrentHashMapPut([]interface{}{fs, chm, one, eins}); ret != uno {
+		t.Errorf("Expected put of an existing key to return the previous value, got: %v", ret)
+	}
+	if concurrentHashMapContainsKey([]interface{}{fs, chm, one}) != types.JavaBoolTrue ||
+		concurrentHashMapSize([]interface{}{chm}) != int64(1) {
+		t.Errorf("Expected the map to hold just the key \"one\"")
+	}
+
+	if ret := concurrentHashMapRemove([]interface{}{fs, chm, one}); ret != eins {
+		t.Errorf("Expected remove to return the removed value, got: %v", ret)
+	}
+	if ret := concurrentHashMapGet([]interface{}{fs, chm, one}); ret != object.Null {
+		t.Errorf("Expected get of a removed key to return null, got: %v", ret)
+	}
+	if concurrentHashMapIsEmpty([]interface{}{chm}) != types.JavaBoolTrue {
+		t.Errorf("Expected the map to be empty after the remove")
+	}
+}
+
+func TestConcurrentHashMapNulls(t *testing.T) {
+	globals.InitGlobals("test")
+	chm := makeTestConcurrentHashMap()
+	fs := frames.CreateFrameStack()
+	key := object.StringObjectFromGoString("key")
+
+	for _, ret := range []interface{}{
+		concurrentHashMapPut([]interface{}{fs, chm, object.Null, key}),
+		concurrentHashMapPut([]interface{}{fs, chm, key, object.Null}),
+		concurrentHashMapPutIfAbsent([]interface{}{fs, chm, key, object.Null}),
+		concurrentHashMapGet([]interface{}{fs, chm, object.Null}),
+		concurrentHashMapContainsKey([]interface{}{fs, chm, object.Null}),
+		concurrentHashMapRemove([]interface{}{fs, chm, object.Null}),
+	} {
+		errBlk, ok := ret.(*GErrBlk)
+		if !ok || errBlk.ExceptionType != excNames.NullPointerException {
+			t.Errorf("Expected NullPointerException for a null key or value, got: %v", ret)
+		}
+	}
+}
+
+// Run with -race to check that the map is safe to use from several threads at once.
+func TestConcurrentHashMapConcurrentPuts(t *testing.T) {
+	globals.InitGlobals("test")
+	chm := makeTestConcurrentHashMap()
+	fs := frames.CreateFrameStack()
+
+	const goroutines = 8
+	const keysEach = 500
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < keysEach; i++ {
+				key := object.StringObjectFromGoString(fmt.Sprintf("%d-%d", g, i))
+				concurrentHashMapPut([]interface{}{fs, chm, key, key})
+				concurrentHashMapGet([]interface{}{fs, chm, key})
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if size := concurrentHashMapSize([]interface{}{chm}); size != int64(goroutines*keysEach) {
+		t.Errorf("Expected %d entries after the concurrent puts, got: %v", goroutines*keysEach, size)
+	}
+}
+
+// Keys other than strings and boxed primitives are matched with hashCode() and equals(), not by
+// their "value" fields: here, all the keys have the same hash code, and are equal if their ids are.
+func TestConcurrentHashMapKeysMatchedByEquals(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, obj any, methodName, _ string, args ...any) (any, error) {
+		if methodName == "hashCode" {
+			return int64(1), nil
+		}
+		id := obj.(*object.Object).FieldTable["id"].Fvalue
+		if id == args[0].(*object.Object).FieldTable["id"].Fvalue {
+			return types.JavaBoolTrue, nil
+		}
+		return types.JavaBoolFalse, nil
+	}
+	chm := makeTestConcurrentHashMap()
+	fs := frames.CreateFrameStack()
+	makeKey := func(id int64, value string) *object.Object {
+		className := "java/lang/StringBuilder"
+		key := object.MakeEmptyObjectWithClassName(&className)
+		key.FieldTable["id"] = object.Field{Ftype: types.Int, Fvalue: id}
+		key.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: []byte(value)}
+		return key
+	}
+	first, second := makeKey(1, "same"), makeKey(2, "same")
+	one, two := object.StringObjectFromGoString("one"), object.StringObjectFromGoString("two")
+
+	concurrentHashMapPut([]interface{}{fs, chm, first, one})
+	concurrentHashMapPut([]interface{}{fs, chm, second, two})
+	if size := concurrentHashMapSize([]interface{}{chm}); size != int64(2) {
+		t.Errorf("Expected keys with the same value field but unequal to be distinct, got size: %v", size)
+	}
+	if ret := concurrentHashMapGet([]interface{}{fs, chm, makeKey(1, "other")}); ret != one {
+		t.Errorf("Expected get with an equal key to return its value, got: %v", ret)
+	}
+	if ret := concurrentHashMapRemove([]interface{}{fs, chm, makeKey(2, "")}); ret != two {
+		t.Errorf("Expected remove with an equal key to return its value, got: %v", ret)
+	}
+	if ret := concurrentHashMapGet([]interface{}{fs, chm, first}); ret != one {
+		t.Errorf("Expected the other key in the bucket to remain, got: %v", ret)
+	}
+}
+
+func TestConcurrentHashMapReplaceAndValues(t *testing.T) {
+	globals.InitGlobals("test")
+	chm := makeTestConcurrentHashMap()
+	fs := frames.CreateFrameStack()
+	key := object.StringObjectFromGoString("key")
+	first := object.StringObjectFromGoString("first")
+	second := object.StringObjectFromGoString("second")
+	other := object.StringObjectFromGoString("other")
+
+	if ret := concurrentHashMapReplace([]interface{}{fs, chm, key, first}); ret != object.Null ||
+		concurrentHashMapSize([]interface{}{chm}) != int64(0) {
+		t.Errorf("Expected replace of a missing key to return null and add nothing, got: %v", ret)
+	}
+	concurrentHashMapPut([]interface{}{fs, chm, key, first})
+	if ret := concurrentHashMapReplaceValue([]interface{}{fs, chm, key, other, second}); ret != types.JavaBoolFalse {
+		t.Errorf("Expected replace with the wrong old value to fail, got: %v", ret)
+	}
+	if ret := concurrentHashMapReplaceValue([]interface{}{fs, chm, key, object.StringObjectFromGoString("first"), second}); ret != types.JavaBoolTrue {
+		t.Errorf("Expected replace with an equal old value to succeed, got: %v", ret)
+	}
+	if ret := concurrentHashMapContainsValue([]interface{}{fs, chm, object.StringObjectFromGoString("second")}); ret != types.JavaBoolTrue {
+		t.Errorf("Expected containsValue of the new value to be true, got: %v", ret)
+	}
+	if ret := concurrentHashMapGetOrDefault([]interface{}{fs, chm, other, first}); ret != first {
+		t.Errorf("Expected getOrDefault of a missing key to return the default, got: %v", ret)
+	}
+	if ret := concurrentHashMapRemoveValue([]interface{}{fs, chm, key, first}); ret != types.JavaBoolFalse {
+		t.Errorf("Expected remove with the wrong value to fail, got: %v", ret)
+	}
+	if ret := concurrentHashMapRemoveValue([]interface{}{fs, chm, key, second}); ret != types.JavaBoolTrue ||
+		concurrentHashMapSize([]interface{}{chm}) != int64(0) {
+		t.Errorf("Expected remove with the current value to remove the entry, got: %v", ret)
+	}
+
+	concurrentHashMapPut([]interface{}{fs, chm, key, first})
+	concurrentHashMapClear([]interface{}{chm})
+	if concurrentHashMapIsEmpty([]interface{}{chm}) != types.JavaBoolTrue {
+		t.Errorf("Expected the map to be empty after clear()")
+	}
+}
+
+// every constructor either sets up the table of entries or is trapped, and so are the methods
+// that would run the JDK's bytecode against the JDK's own table
+func TestConcurrentHashMapConstructorsAndTraps(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Util_Concurrent_ConcurrentHashMap()
+	className := "java/util/concurrent/ConcurrentHashMap"
+
+	chm := object.MakeEmptyObjectWithClassName(&className)
+	if ret := concurrentHashMapInitLoadFactor([]interface{}{chm, int64(16), 0.75, int64(4)}); ret != nil {
+		t.Fatalf("ConcurrentHashMap(16, 0.75, 4): unexpected error: %v", ret)
+	}
+	if ret := concurrentHashMapSize([]interface{}{chm}); ret != int64(0) {
+		t.Errorf("ConcurrentHashMap(16, 0.75, 4): expected an empty map, got size %v", ret)
+	}
+	ret := concurrentHashMapInitLoadFactor([]interface{}{chm, int64(16), 0.0})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalArgumentException {
+		t.Errorf("ConcurrentHashMap(16, 0.0): expected an IllegalArgumentException, got %v", ret)
+	}
+
+	// a map whose table was never set up throws an exception rather than panicking
+	ret = concurrentHashMapSize([]interface{}{object.MakeEmptyObjectWithClassName(&className)})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.IllegalStateException {
+		t.Errorf("size() of a map without a table: expected an IllegalStateException, got %v", ret)
+	}
+
+	for signature, paramSlots := range map[string]int{
+		"<init>(Ljava/util/Map;)V": 1,
+		"merge(Ljava/lang/Object;Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;": 3,
+		"keySet()Ljava/util/concurrent/ConcurrentHashMap$KeySetView;":                                  0,
+		"forEach(JLjava/util/function/BiConsumer;)V":                                                   3,
+	} {
+		gmeth, ok := MethodSignatures[className+"."+signature]
+		if !ok || gmeth.ParamSlots != paramSlots {
+			t.Errorf("%s: expected a trap with %d parameter slots, got %v", signature, paramSlots, gmeth)
+			continue
+		}
+		ret := gmeth.GFunction(nil)
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.UnsupportedOperationException {
+			t.Errorf("%s: expected an UnsupportedOperationException, got %v", signature, ret)
+		}
+	}
+}
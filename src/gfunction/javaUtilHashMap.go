@@ -7,35 +7,161 @@
 package gfunction
 
 import (
+	"cmp"
+	"container/list"
 	"crypto/md5"
 	"encoding/binary"
 	"fmt"
 	"jacobin/excNames"
+	"jacobin/globals"
 	"jacobin/object"
+	"jacobin/stringPool"
 	"jacobin/types"
+	"math"
+	"math/bits"
+	"slices"
 )
 
-// Implementation of some of the functions in in Java/lang/Class.
+// Implementation of some of the functions in java/util/HashMap.
+//
+// The HashMap itself is the JDK's Java implementation, so the functions here work directly
+// on its fields: table is an array of HashMap$Node objects, each of which holds the
+// hash, key, value, and next fields of one entry in a bucket. Methods that take a
+// functional-interface argument (compute, computeIfAbsent, merge, forEach) are left to the
+// JDK's bytecode, which works on the same fields and calls the function directly. (A
+// gfunction could call it through globals.FuncInvokeMethod, as the Map default methods in
+// javaUtilMap.go do, but would gain nothing over the JDK's code.)
+//
+// A LinkedHashMap, also the JDK's Java implementation, is a HashMap whose nodes are
+// LinkedHashMap$Entry objects, which are also linked, by their before and after fields, in
+// a doubly linked list that runs from the map's head to its tail. The functions here that
+// add an entry to a LinkedHashMap add it to the tail of that list, as the JDK's newNode()
+// does, and those that access an entry of a LinkedHashMap created in access order move it
+// to the tail, as afterNodeAccess() does. The JDK's iterators over a LinkedHashMap's keys,
+// values, and entries then walk the list, and so return them in insertion (or access) order.
+//
+// The iterators over a HashMap's keySet(), values(), and entrySet() views are the JDK's
+// KeyIterator, ValueIterator, and EntryIterator, whose next() methods call nextNode() in their
+// superclass, HashMap$HashIterator. Its constructor, hasNext(), and nextNode() are implemented
+// here, on its fields (next, current, expectedModCount, and index), so that the iterators walk
+// the map's table as it is, rather than a copy of it, and nextNode() throws a
+// ConcurrentModificationException if the map has been structurally modified (modCount has
+// changed) other than by the iterator's own remove(), which is the JDK's.
+//
+// With the -deterministicHash option, the iterators instead return the entries sorted by their
+// hash, and those with the same hash in the order they were added, which is their order in
+// their bucket. So a map's order depends only on its keys' hash codes and the order in which
+// they were added, not on the size of its table. (The option also makes the identity hash
+// codes of keys that don't override hashCode() the same on every run.) The order is fixed when
+// the iterator is created and kept in the iterator's deterministicOrder field.
+
+const hashMapNodeClassName = "java/util/HashMap$Node"
+const hashMapIteratorClassName = "java/util/HashMap$HashIterator"
+const linkedHashMapClassName = "java/util/LinkedHashMap"
+const linkedHashMapEntryClassName = "java/util/LinkedHashMap$Entry"
+const hashMapMaximumCapacity = 1 << 30 // HashMap.MAXIMUM_CAPACITY
 
 func Load_Util_HashMap() {
 
+	MethodSignatures["java/util/HashMap.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  hashMapInitCapacity,
+		}
+
+	MethodSignatures["java/util/HashMap.<init>(IF)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  hashMapInitCapacityLoadFactor,
+		}
+
+	MethodSignatures["java/util/HashMap.clear()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  hashMapClear,
+		}
+
+	MethodSignatures["java/util/HashMap.containsValue(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    hashMapContainsValue,
+			NeedsContext: true,
+		}
+
 	MethodSignatures["java/util/HashMap.hash(Ljava/lang/Object;)I"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    hashMapHash,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/HashMap.getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    hashMapGetOrDefault,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/HashMap.isEmpty()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  hashMapIsEmpty,
+		}
+
+	MethodSignatures["java/util/HashMap.putIfAbsent(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    hashMapPutIfAbsent,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/HashMap.size()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  hashMapSize,
+		}
+
+	MethodSignatures[hashMapIteratorClassName+".<init>(Ljava/util/HashMap;)V"] =
 		GMeth{
 			ParamSlots: 1,
-			GFunction:  hashMapHash,
+			GFunction:  hashMapIteratorInit,
+		}
+
+	MethodSignatures[hashMapIteratorClassName+".hasNext()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  hashMapIteratorHasNext,
+		}
+
+	MethodSignatures[hashMapIteratorClassName+".nextNode()Ljava/util/HashMap$Node;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  hashMapIteratorNextNode,
 		}
 
 }
 
 // hashMapHash accepts a pointer to an object and returns
-// a uint64 MD5 hash value of the pointed-to thing
+// a uint64 MD5 hash value of the pointed-to thing (or, for an
+// object that holds no value, the hash that HashMap.hash() makes
+// of its hashCode(), which can be overridden, so it's run through
+// globals.FuncInvokeMethod).
+// params[0] = the frame stack, params[1] = the key
 func hashMapHash(params []interface{}) interface{} {
 	var hashValue uint64 = 0
 	var bytes []byte
-	switch params[0].(type) {
+	switch params[1].(type) {
 	case *object.Object:
-		obj := params[0].(*object.Object) // force golang to treat it as the object we know it to be
-		fld := obj.FieldTable["value"]
+		obj := params[1].(*object.Object) // force golang to treat it as the object we know it to be
+		fld, ok := obj.FieldTable["value"]
+		if !ok { // not a string or boxed primitive, so use its hashCode(), as HashMap.hash() does
+			var hashCode any
+			if errBlk := mapInvoke(params[0].(*list.List), obj, "HashMap.hash", "hashCode", "()I", &hashCode); errBlk != nil {
+				return errBlk
+			}
+			h := uint32(hashCode.(int64))
+			return int64(int32(h ^ h>>16))
+		}
 		switch fld.Ftype {
 		case types.ByteArray:
 			bytes = obj.FieldTable["value"].Fvalue.([]byte)
@@ -54,8 +180,470 @@ func hashMapHash(params []interface{}) interface{} {
 		uHash := binary.BigEndian.Uint64(hash) // convert slice to a uint64
 		return int64(uHash)                    // return an int64
 	default:
-		str := fmt.Sprintf("hashMapHash: unrecognized parameter type: %T", params[0])
+		str := fmt.Sprintf("hashMapHash: unrecognized parameter type: %T", params[1])
 		return getGErrBlk(excNames.VirtualMachineError, str)
 	}
 	return hashValue
 }
+
+// java/util/HashMap.<init>(I)V -- an empty map with the given initial capacity and the default load factor
+func hashMapInitCapacity(params []interface{}) interface{} {
+	return hashMapInit(params[0].(*object.Object), params[1].(int64), 0.75)
+}
+
+// java/util/HashMap.<init>(IF)V -- an empty map with the given initial capacity and load factor
+func hashMapInitCapacityLoadFactor(params []interface{}) interface{} {
+	return hashMapInit(params[0].(*object.Object), params[1].(int64), params[2].(float64))
+}
+
+// hashMapInit duplicates the HashMap(int, float) constructor. As in the JDK, the table is not
+// created until the first entry is added: until then, threshold holds the initial capacity,
+// rounded up to a power of two, which hashMapResize() uses as the size of the table.
+func hashMapInit(hashMap *object.Object, initialCapacity int64, loadFactor float64) interface{} {
+	if initialCapacity < 0 {
+		errMsg := fmt.Sprintf("Illegal initial capacity: %d", initialCapacity)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	if loadFactor <= 0 || math.IsNaN(loadFactor) {
+		errMsg := fmt.Sprintf("Illegal load factor: %v", loadFactor)
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	if initialCapacity > hashMapMaximumCapacity {
+		initialCapacity = hashMapMaximumCapacity
+	}
+
+	hashMap.FieldTable["table"] = object.Field{Ftype: types.RefArray + hashMapNodeClassName, Fvalue: object.Null}
+	hashMap.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	hashMap.FieldTable["modCount"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	hashMap.FieldTable["loadFactor"] = object.Field{Ftype: types.Float, Fvalue: loadFactor}
+	hashMap.FieldTable["threshold"] = object.Field{Ftype: types.Int, Fvalue: hashMapTableSizeFor(initialCapacity)}
+	return nil
+}
+
+// hashMapTableSizeFor duplicates HashMap.tableSizeFor(): the smallest power of two that
+// is at least the given capacity, up to the maximum capacity
+func hashMapTableSizeFor(capacity int64) int64 {
+	if capacity <= 1 {
+		return 1
+	}
+	size := int64(1) << bits.Len64(uint64(capacity-1))
+	if size > hashMapMaximumCapacity {
+		return hashMapMaximumCapacity
+	}
+	return size
+}
+
+// java/util/HashMap.size()I
+func hashMapSize(params []interface{}) interface{} {
+	size, _ := params[0].(*object.Object).FieldTable["size"].Fvalue.(int64)
+	return size
+}
+
+// java/util/HashMap.isEmpty()Z
+func hashMapIsEmpty(params []interface{}) interface{} {
+	size, _ := params[0].(*object.Object).FieldTable["size"].Fvalue.(int64)
+	if size == 0 {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// java/util/HashMap.clear()V -- removes all the entries, but keeps the table at its present size
+func hashMapClear(params []interface{}) interface{} {
+	hashMap := params[0].(*object.Object)
+	modCount, _ := hashMap.FieldTable["modCount"].Fvalue.(int64)
+	hashMap.FieldTable["modCount"] = object.Field{Ftype: types.Int, Fvalue: modCount + 1}
+
+	table := hashMapTable(hashMap)
+	if len(table) > 0 {
+		hashMap.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+		for i := range table {
+			table[i] = object.Null
+		}
+	}
+	return nil
+}
+
+// java/util/HashMap.containsValue(Ljava/lang/Object;)Z
+// params[0] = the frame stack, params[1] = the map, params[2] = the value. As in the JDK,
+// a null value matches a null one; otherwise, the value's equals() is called with each one.
+func hashMapContainsValue(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	value := params[2]
+	for _, node := range hashMapTable(params[1].(*object.Object)) {
+		for ; !object.IsNull(node); node = hashMapNextNode(node) {
+			nodeValue, _ := node.FieldTable["value"].Fvalue.(*object.Object)
+			if object.IsNull(value) {
+				if object.IsNull(nodeValue) {
+					return types.JavaBoolTrue
+				}
+				continue
+			}
+			equal, errBlk := objectsEqual(fs, value.(*object.Object), nodeValue)
+			if errBlk != nil {
+				return errBlk
+			}
+			if equal {
+				return types.JavaBoolTrue
+			}
+		}
+	}
+	return types.JavaBoolFalse
+}
+
+// java/util/HashMap.getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;
+// Returns the value to which the key is mapped, or the default value if there is no mapping.
+// params[0] = the frame stack, params[1] = the map, params[2] = the key, params[3] = the default
+func hashMapGetOrDefault(params []interface{}) interface{} {
+	hashMap := params[1].(*object.Object)
+	key, _ := params[2].(*object.Object)
+
+	node, errBlk := hashMapGetNode(params[0].(*list.List), hashMap, key)
+	if errBlk != nil {
+		return errBlk
+	}
+	if node == nil {
+		return params[3]
+	}
+	linkedHashMapAfterNodeAccess(hashMap, node)
+	return node.FieldTable["value"].Fvalue
+}
+
+// java/util/HashMap.putIfAbsent(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;
+// If the key is not mapped to a value (or is mapped to null), maps it to the given value
+// and returns null. Otherwise, returns the current value, which is left unchanged.
+// params[0] = the frame stack, params[1] = the map, params[2] = the key, params[3] = the value
+func hashMapPutIfAbsent(params []interface{}) interface{} {
+	hashMap := params[1].(*object.Object)
+	key, _ := params[2].(*object.Object)
+	value := params[3]
+
+	node, errBlk := hashMapGetNode(params[0].(*list.List), hashMap, key)
+	if errBlk != nil {
+		return errBlk
+	}
+	if node != nil {
+		current := node.FieldTable["value"].Fvalue
+		if !object.IsNull(current) {
+			linkedHashMapAfterNodeAccess(hashMap, node)
+			return current
+		}
+		node.FieldTable["value"] = object.Field{Ftype: types.Ref, Fvalue: value}
+		linkedHashMapAfterNodeAccess(hashMap, node)
+		return object.Null
+	}
+
+	// other subclasses of HashMap create their own kind of node, so we can only add to a
+	// plain HashMap or a LinkedHashMap
+	className := *stringPool.GetStringPointer(hashMap.KlassName)
+	if className != "java/util/HashMap" && className != linkedHashMapClassName {
+		errMsg := fmt.Sprintf("HashMap.putIfAbsent: adding an entry to a %s is not yet supported", className)
+		return getGErrBlk(excNames.UnsupportedOperationException, errMsg)
+	}
+
+	hash, errBlk := hashMapKeyHash(params[0].(*list.List), key)
+	if errBlk != nil {
+		return errBlk
+	}
+
+	table := hashMapTable(hashMap)
+	if len(table) == 0 {
+		table = hashMapResize(hashMap)
+	}
+
+	nodeClassName := hashMapNodeClassName
+	if className == linkedHashMapClassName {
+		nodeClassName = linkedHashMapEntryClassName
+	}
+	newNode := object.MakeEmptyObjectWithClassName(&nodeClassName)
+	newNode.FieldTable["hash"] = object.Field{Ftype: types.Int, Fvalue: hash}
+	newNode.FieldTable["key"] = object.Field{Ftype: types.Ref, Fvalue: key}
+	newNode.FieldTable["value"] = object.Field{Ftype: types.Ref, Fvalue: value}
+	newNode.FieldTable["next"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+	hashMapAppendNode(table, newNode)
+	if className == linkedHashMapClassName {
+		linkedHashMapLinkNodeLast(hashMap, newNode)
+	}
+
+	modCount, _ := hashMap.FieldTable["modCount"].Fvalue.(int64)
+	hashMap.FieldTable["modCount"] = object.Field{Ftype: types.Int, Fvalue: modCount + 1}
+	size, _ := hashMap.FieldTable["size"].Fvalue.(int64)
+	hashMap.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: size + 1}
+	threshold, _ := hashMap.FieldTable["threshold"].Fvalue.(int64)
+	if size+1 > threshold {
+		hashMapResize(hashMap)
+	}
+	return object.Null
+}
+
+// java/util/HashMap$HashIterator.<init>(Ljava/util/HashMap;)V -- params[0] = the iterator,
+// params[1] = the map. The iterator starts at the first node of the first non-empty bucket
+// and expects the map's present modCount.
+func hashMapIteratorInit(params []interface{}) interface{} {
+	iterator := params[0].(*object.Object)
+	hashMap := params[1].(*object.Object)
+	modCount, _ := hashMap.FieldTable["modCount"].Fvalue.(int64)
+
+	iterator.FieldTable["this$0"] = object.Field{Ftype: types.Ref, Fvalue: hashMap}
+	iterator.FieldTable["expectedModCount"] = object.Field{Ftype: types.Int, Fvalue: modCount}
+	iterator.FieldTable["current"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+	iterator.FieldTable["next"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+	iterator.FieldTable["index"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	if globals.GetGlobalRef().DeterministicHash {
+		order := hashMapNodesInHashOrder(hashMapTable(hashMap))
+		iterator.FieldTable[hashMapIteratorOrder] = object.Field{Ftype: types.RefArray, Fvalue: order}
+		hashMapIteratorAdvanceInOrder(iterator)
+		return nil
+	}
+	if size, _ := hashMap.FieldTable["size"].Fvalue.(int64); size > 0 {
+		hashMapIteratorAdvance(iterator, hashMapTable(hashMap))
+	}
+	return nil
+}
+
+// the iterator field that holds the nodes that remain to be returned, with -deterministicHash
+const hashMapIteratorOrder = "deterministicOrder"
+
+// hashMapNodesInHashOrder returns the nodes of a table sorted by hash. The sort is stable, so
+// nodes with the same hash, which are in the same bucket, stay in the bucket's order.
+func hashMapNodesInHashOrder(table []*object.Object) []*object.Object {
+	var nodes []*object.Object
+	for _, node := range table {
+		for ; !object.IsNull(node); node = hashMapNextNode(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	slices.SortStableFunc(nodes, func(a, b *object.Object) int {
+		return cmp.Compare(a.FieldTable["hash"].Fvalue.(int64), b.FieldTable["hash"].Fvalue.(int64))
+	})
+	return nodes
+}
+
+// hashMapIteratorAdvanceInOrder sets the iterator's next node to the first of the nodes that
+// remain in its deterministic order, and removes it from them
+func hashMapIteratorAdvanceInOrder(iterator *object.Object) {
+	order, _ := iterator.FieldTable[hashMapIteratorOrder].Fvalue.([]*object.Object)
+	if len(order) == 0 {
+		iterator.FieldTable["next"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+		return
+	}
+	iterator.FieldTable["next"] = object.Field{Ftype: types.Ref, Fvalue: order[0]}
+	iterator.FieldTable[hashMapIteratorOrder] = object.Field{Ftype: types.RefArray, Fvalue: order[1:]}
+}
+
+// java/util/HashMap$HashIterator.hasNext()Z
+func hashMapIteratorHasNext(params []interface{}) interface{} {
+	next, _ := params[0].(*object.Object).FieldTable["next"].Fvalue.(*object.Object)
+	return types.ConvertGoBoolToJavaBool(!object.IsNull(next))
+}
+
+// java/util/HashMap$HashIterator.nextNode()Ljava/util/HashMap$Node; -- returns the next node,
+// which becomes the current one, the one the iterator's remove() would remove
+func hashMapIteratorNextNode(params []interface{}) interface{} {
+	iterator := params[0].(*object.Object)
+	hashMap := iterator.FieldTable["this$0"].Fvalue.(*object.Object)
+
+	modCount, _ := hashMap.FieldTable["modCount"].Fvalue.(int64)
+	expectedModCount, _ := iterator.FieldTable["expectedModCount"].Fvalue.(int64)
+	if modCount != expectedModCount {
+		return getGErrBlk(excNames.ConcurrentModificationException, "")
+	}
+	node, _ := iterator.FieldTable["next"].Fvalue.(*object.Object)
+	if object.IsNull(node) {
+		return getGErrBlk(excNames.NoSuchElementException, "")
+	}
+
+	iterator.FieldTable["current"] = object.Field{Ftype: types.Ref, Fvalue: node}
+	if _, ok := iterator.FieldTable[hashMapIteratorOrder]; ok {
+		hashMapIteratorAdvanceInOrder(iterator)
+		return node
+	}
+	next := hashMapNextNode(node)
+	iterator.FieldTable["next"] = object.Field{Ftype: types.Ref, Fvalue: next}
+	if object.IsNull(next) {
+		hashMapIteratorAdvance(iterator, hashMapTable(hashMap))
+	}
+	return node
+}
+
+// hashMapIteratorAdvance sets the iterator's next node to the head of the next non-empty
+// bucket, starting at the one its index selects, and moves the index past that bucket.
+// If there are no more non-empty buckets, next is left null.
+func hashMapIteratorAdvance(iterator *object.Object, table []*object.Object) {
+	index, _ := iterator.FieldTable["index"].Fvalue.(int64)
+	for index < int64(len(table)) {
+		bucket := table[index]
+		index++
+		if !object.IsNull(bucket) {
+			iterator.FieldTable["next"] = object.Field{Ftype: types.Ref, Fvalue: bucket}
+			break
+		}
+	}
+	iterator.FieldTable["index"] = object.Field{Ftype: types.Int, Fvalue: index}
+}
+
+// hashMapGetNode duplicates HashMap.getNode(): it returns the node holding the key,
+// or nil if the key is not in the map.
+func hashMapGetNode(fs *list.List, hashMap *object.Object, key *object.Object) (*object.Object, *GErrBlk) {
+	table := hashMapTable(hashMap)
+	if len(table) == 0 {
+		return nil, nil
+	}
+
+	hash, errBlk := hashMapKeyHash(fs, key)
+	if errBlk != nil {
+		return nil, errBlk
+	}
+
+	for node := table[int64(len(table)-1)&hash]; !object.IsNull(node); node = hashMapNextNode(node) {
+		if node.FieldTable["hash"].Fvalue.(int64) != hash {
+			continue
+		}
+		equal, errBlk := hashMapKeysEqual(fs, node.FieldTable["key"].Fvalue, key)
+		if errBlk != nil {
+			return nil, errBlk
+		}
+		if equal {
+			return node, nil
+		}
+	}
+	return nil, nil
+}
+
+// hashMapResize duplicates HashMap.resize(): it creates the table if there is none, otherwise
+// doubles its size, and redistributes the existing nodes. Returns the new table.
+func hashMapResize(hashMap *object.Object) []*object.Object {
+	oldTable := hashMapTable(hashMap)
+	threshold, _ := hashMap.FieldTable["threshold"].Fvalue.(int64)
+	loadFactor, ok := hashMap.FieldTable["loadFactor"].Fvalue.(float64)
+	if !ok || loadFactor <= 0 {
+		loadFactor = 0.75 // HashMap.DEFAULT_LOAD_FACTOR
+	}
+
+	var newCap int64
+	switch {
+	case len(oldTable) > 0:
+		newCap = int64(len(oldTable)) * 2
+	case threshold > 0: // the initial capacity was placed in threshold
+		newCap = threshold
+	default:
+		newCap = 16 // HashMap.DEFAULT_INITIAL_CAPACITY
+	}
+
+	nodeClassName := hashMapNodeClassName
+	newTableObj := object.Make1DimRefArray(&nodeClassName, newCap)
+	newTable := newTableObj.FieldTable["value"].Fvalue.([]*object.Object)
+	for _, node := range oldTable {
+		for !object.IsNull(node) {
+			next := hashMapNextNode(node)
+			node.FieldTable["next"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+			hashMapAppendNode(newTable, node)
+			node = next
+		}
+	}
+
+	hashMap.FieldTable["table"] = object.Field{Ftype: types.RefArray + hashMapNodeClassName, Fvalue: newTableObj}
+	hashMap.FieldTable["threshold"] = object.Field{Ftype: types.Int, Fvalue: int64(float64(newCap) * loadFactor)}
+	return newTable
+}
+
+// linkedHashMapLinkNodeLast duplicates LinkedHashMap.linkNodeLast(): it adds a new entry to
+// the tail of the map's list of entries
+func linkedHashMapLinkNodeLast(hashMap *object.Object, entry *object.Object) {
+	last, _ := hashMap.FieldTable["tail"].Fvalue.(*object.Object)
+	hashMap.FieldTable["tail"] = object.Field{Ftype: types.Ref, Fvalue: entry}
+	entry.FieldTable["after"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+	if object.IsNull(last) {
+		entry.FieldTable["before"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+		hashMap.FieldTable["head"] = object.Field{Ftype: types.Ref, Fvalue: entry}
+		return
+	}
+	entry.FieldTable["before"] = object.Field{Ftype: types.Ref, Fvalue: last}
+	last.FieldTable["after"] = object.Field{Ftype: types.Ref, Fvalue: entry}
+}
+
+// linkedHashMapAfterNodeAccess duplicates LinkedHashMap.afterNodeAccess(): if the map is a
+// LinkedHashMap in access order, the entry that was accessed is moved to the tail of the list.
+// For any other map, it does nothing.
+func linkedHashMapAfterNodeAccess(hashMap *object.Object, entry *object.Object) {
+	if *stringPool.GetStringPointer(hashMap.KlassName) != linkedHashMapClassName ||
+		hashMap.FieldTable["accessOrder"].Fvalue != types.JavaBoolTrue {
+		return
+	}
+	last, _ := hashMap.FieldTable["tail"].Fvalue.(*object.Object)
+	if last == entry {
+		return
+	}
+
+	// unlink the entry, then link it after the tail
+	before, _ := entry.FieldTable["before"].Fvalue.(*object.Object)
+	after, _ := entry.FieldTable["after"].Fvalue.(*object.Object)
+	if object.IsNull(before) {
+		hashMap.FieldTable["head"] = object.Field{Ftype: types.Ref, Fvalue: after}
+	} else {
+		before.FieldTable["after"] = object.Field{Ftype: types.Ref, Fvalue: after}
+	}
+	if !object.IsNull(after) {
+		after.FieldTable["before"] = object.Field{Ftype: types.Ref, Fvalue: before}
+	}
+	linkedHashMapLinkNodeLast(hashMap, entry)
+
+	modCount, _ := hashMap.FieldTable["modCount"].Fvalue.(int64)
+	hashMap.FieldTable["modCount"] = object.Field{Ftype: types.Int, Fvalue: modCount + 1}
+}
+
+// hashMapAppendNode adds a node to the end of the bucket its hash selects
+func hashMapAppendNode(table []*object.Object, node *object.Object) {
+	index := int64(len(table)-1) & node.FieldTable["hash"].Fvalue.(int64)
+	if object.IsNull(table[index]) {
+		table[index] = node
+		return
+	}
+
+	last := table[index]
+	for !object.IsNull(hashMapNextNode(last)) {
+		last = hashMapNextNode(last)
+	}
+	last.FieldTable["next"] = object.Field{Ftype: types.Ref, Fvalue: node}
+}
+
+// hashMapTable returns the raw array of buckets, which is nil until the first entry is added
+func hashMapTable(hashMap *object.Object) []*object.Object {
+	tableObj, ok := hashMap.FieldTable["table"].Fvalue.(*object.Object)
+	if !ok || object.IsNull(tableObj) {
+		return nil
+	}
+	return tableObj.FieldTable["value"].Fvalue.([]*object.Object)
+}
+
+func hashMapNextNode(node *object.Object) *object.Object {
+	next, _ := node.FieldTable["next"].Fvalue.(*object.Object)
+	return next
+}
+
+// hashMapKeyHash returns the same hash value as HashMap.hash(), which is 0 for a null key
+func hashMapKeyHash(fs *list.List, key *object.Object) (int64, *GErrBlk) {
+	if object.IsNull(key) {
+		return 0, nil
+	}
+	switch ret := hashMapHash([]interface{}{fs, key}).(type) {
+	case int64:
+		return ret, nil
+	case *GErrBlk:
+		return 0, ret
+	default:
+		return 0, getGErrBlk(excNames.VirtualMachineError,
+			fmt.Sprintf("hashMapKeyHash: unexpected hash value of type: %T", ret))
+	}
+}
+
+// hashMapKeysEqual stands in for the test in HashMap.getNode(): two keys are equal if they are
+// the same object (or both null), or if key.equals(nodeKey). Strings and boxed primitives are
+// compared by class and value; other keys' equals() is run through globals.FuncInvokeMethod.
+func hashMapKeysEqual(fs *list.List, nodeKey any, key *object.Object) (bool, *GErrBlk) {
+	other, _ := nodeKey.(*object.Object)
+	if object.IsNull(other) || object.IsNull(key) {
+		return object.IsNull(other) && object.IsNull(key), nil
+	}
+	return objectsEqual(fs, key, other)
+}
@@ -0,0 +1,502 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/stringPool"
+	"jacobin/types"
+	"math"
+	"testing"
+)
+
+// creates an empty HashMap as the JDK's HashMap() constructor leaves it: no table and the default load factor
+func makeTestHashMap() *object.Object {
+	return makeTestHashMapOfClass("java/util/HashMap")
+}
+
+// creates an empty LinkedHashMap, in insertion order or, if accessOrder is true, in access order
+func makeTestLinkedHashMap(accessOrder bool) *object.Object {
+	hashMap := makeTestHashMapOfClass(linkedHashMapClassName)
+	hashMap.FieldTable["head"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+	hashMap.FieldTable["tail"] = object.Field{Ftype: types.Ref, Fvalue: object.Null}
+	hashMap.FieldTable["accessOrder"] = object.Field{Ftype: types.Bool, Fvalue: types.ConvertGoBoolToJavaBool(accessOrder)}
+	return hashMap
+}
+
+func makeTestHashMapOfClass(className string) *object.Object {
+	hashMap := object.MakeEmptyObjectWithClassName(&className)
+	hashMap.FieldTable["table"] = object.Field{Ftype: types.RefArray + hashMapNodeClassName, Fvalue: object.Null}
+	hashMap.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	hashMap.FieldTable["modCount"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	hashMap.FieldTable["threshold"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	hashMap.FieldTable["loadFactor"] = object.Field{Ftype: types.Float, Fvalue: 0.75}
+	return hashMap
+}
+
+func TestHashMapGetOrDefault(t *testing.T) {
+	globals.InitGlobals("test")
+
+	hashMap := makeTestHashMap()
+	fs := frames.CreateFrameStack()
+	key := object.StringObjectFromGoString("key")
+	value := object.StringObjectFromGoString("value")
+	deflt := object.StringObjectFromGoString("default")
+
+	// empty map
+	ret := hashMapGetOrDefault([]interface{}{fs, hashMap, key, deflt})
+	if ret != deflt {
+		t.Errorf("Expected default value from empty map, got: %v", ret)
+	}
+
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, key, value})
+
+	// present key, looked up with a different but equal String object
+	ret = hashMapGetOrDefault([]interface{}{fs, hashMap, object.StringObjectFromGoString("key"), deflt})
+	if ret != value {
+		t.Errorf("Expected mapped value for present key, got: %v", ret)
+	}
+
+	// absent key
+	ret = hashMapGetOrDefault([]interface{}{fs, hashMap, object.StringObjectFromGoString("other"), deflt})
+	if ret != deflt {
+		t.Errorf("Expected default value for absent key, got: %v", ret)
+	}
+}
+
+func TestHashMapPutIfAbsentDoesNotOverwrite(t *testing.T) {
+	globals.InitGlobals("test")
+
+	hashMap := makeTestHashMap()
+	fs := frames.CreateFrameStack()
+	key := object.StringObjectFromGoString("key")
+	first := object.StringObjectFromGoString("first")
+	second := object.StringObjectFromGoString("second")
+
+	ret := hashMapPutIfAbsent([]interface{}{fs, hashMap, key, first})
+	if !object.IsNull(ret) {
+		t.Errorf("Expected null from putIfAbsent on absent key, got: %v", ret)
+	}
+
+	ret = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("key"), second})
+	if ret != first {
+		t.Errorf("Expected putIfAbsent to return the existing value, got: %v", ret)
+	}
+
+	ret = hashMapGetOrDefault([]interface{}{fs, hashMap, key, object.Null})
+	if ret != first {
+		t.Errorf("Expected putIfAbsent not to overwrite the existing value, got: %v", ret)
+	}
+
+	if hashMap.FieldTable["size"].Fvalue.(int64) != 1 {
+		t.Errorf("Expected size of 1, got: %d", hashMap.FieldTable["size"].Fvalue.(int64))
+	}
+}
+
+// Keys other than Strings and boxed primitives are compared with their equals(), not by their value
+// fields: two StringBuilders that hold the same characters are different keys.
+func TestHashMapKeysComparedWithEquals(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, obj any, methodName, _ string, args ...any) (any, error) {
+		if methodName != "equals" {
+			return nil, errors.New("unexpected call of " + methodName)
+		}
+		return types.ConvertGoBoolToJavaBool(obj == args[0]), nil
+	}
+
+	hashMap := makeTestHashMap()
+	fs := frames.CreateFrameStack()
+	className := "java/lang/StringBuilder"
+	first := object.MakeEmptyObjectWithClassName(&className)
+	first.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: []byte("key")}
+	second := object.MakeEmptyObjectWithClassName(&className)
+	second.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: []byte("key")}
+	one := object.StringObjectFromGoString("one")
+	two := object.StringObjectFromGoString("two")
+
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, first, one})
+	if ret := hashMapPutIfAbsent([]interface{}{fs, hashMap, second, two}); !object.IsNull(ret) {
+		t.Errorf("Expected putIfAbsent of an unequal key with the same value to return null, got: %v", ret)
+	}
+	if ret := hashMapGetOrDefault([]interface{}{fs, hashMap, second, object.Null}); ret != two {
+		t.Errorf("Expected the second key's own value, got: %v", ret)
+	}
+	if hashMap.FieldTable["size"].Fvalue.(int64) != 2 {
+		t.Errorf("Expected size of 2, got: %d", hashMap.FieldTable["size"].Fvalue.(int64))
+	}
+}
+
+func TestHashMapPutIfAbsentResizes(t *testing.T) {
+	globals.InitGlobals("test")
+
+	hashMap := makeTestHashMap()
+	fs := frames.CreateFrameStack()
+	for i := 0; i < 20; i++ {
+		key := object.StringObjectFromGoString(fmt.Sprintf("key%d", i))
+		_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, key, object.StringObjectFromGoString(fmt.Sprintf("value%d", i))})
+	}
+
+	if len(hashMapTable(hashMap)) != 32 {
+		t.Errorf("Expected table to grow to 32 buckets, got: %d", len(hashMapTable(hashMap)))
+	}
+
+	for i := 0; i < 20; i++ {
+		key := object.StringObjectFromGoString(fmt.Sprintf("key%d", i))
+		ret := hashMapGetOrDefault([]interface{}{fs, hashMap, key, object.Null})
+		if object.IsNull(ret) || object.GoStringFromStringObject(ret.(*object.Object)) != fmt.Sprintf("value%d", i) {
+			t.Errorf("Expected value%d for key%d after resize, got: %v", i, i, ret)
+		}
+	}
+}
+
+// stubHashCode makes globals.FuncInvokeMethod run hashCode() and equals() as they are in the
+// class of a key in the tests: hashCode() returns the identity hash code, unless hashCodes
+// holds one for the key, and equals() compares identities
+func stubHashCode(hashCodes map[*object.Object]int64) {
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, obj any, methodName, _ string, args ...any) (any, error) {
+		switch methodName {
+		case "hashCode":
+			if hashCode, ok := hashCodes[obj.(*object.Object)]; ok {
+				return hashCode, nil
+			}
+			return objectHashCode([]interface{}{obj}), nil
+		case "equals":
+			return types.ConvertGoBoolToJavaBool(obj == args[0]), nil
+		}
+		return nil, errors.New("unexpected call of " + methodName)
+	}
+}
+
+// hashMapIteratorValues returns the Integer values of a map in the order that its iterator
+// returns them
+func hashMapIteratorValues(hashMap *object.Object) []int64 {
+	var order []int64
+	iterator := makeTestHashMapIterator(hashMap)
+	for hashMapIteratorHasNext([]interface{}{iterator}) == types.JavaBoolTrue {
+		node := hashMapIteratorNextNode([]interface{}{iterator}).(*object.Object)
+		value := node.FieldTable["value"].Fvalue.(*object.Object)
+		order = append(order, value.FieldTable["value"].Fvalue.(int64))
+	}
+	return order
+}
+
+// hashMapIterationOrder fills a new HashMap with n keys that have identity hash codes and
+// returns the values in the order that iterating over the map returns them.
+func hashMapIterationOrder(n int) []int64 {
+	object.ResetHashSequence()
+	stubHashCode(nil)
+	hashMap := makeTestHashMap()
+	fs := frames.CreateFrameStack()
+	for i := 0; i < n; i++ {
+		key := object.MakeEmptyObject()
+		_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, key, populator("java/lang/Integer", types.Int, int64(i))})
+	}
+	return hashMapIteratorValues(hashMap)
+}
+
+func TestHashMapDeterministicHashOrder(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().DeterministicHash = true
+	defer func() { globals.GetGlobalRef().DeterministicHash = false }()
+
+	// identity hash codes are assigned in sequence
+	object.ResetHashSequence()
+	first := object.MakeEmptyObject()
+	second := object.MakeEmptyObject()
+	if objectHashCode([]interface{}{first}) != int64(1) || objectHashCode([]interface{}{second}) != int64(2) {
+		t.Errorf("Expected identity hash codes 1 and 2, got: %v and %v",
+			objectHashCode([]interface{}{first}), objectHashCode([]interface{}{second}))
+	}
+
+	// the keys' hash codes are 1 to 50, so the map iterates in the order they were added
+	firstRun := hashMapIterationOrder(50)
+	secondRun := hashMapIterationOrder(50)
+	if len(firstRun) != 50 {
+		t.Fatalf("Expected 50 entries in the map, got: %d", len(firstRun))
+	}
+	for i := range firstRun {
+		if firstRun[i] != int64(i) || secondRun[i] != int64(i) {
+			t.Fatalf("Expected iteration in hash order on both runs, got:\n%v\n%v", firstRun, secondRun)
+		}
+	}
+}
+
+// with -deterministicHash, keys whose hashCode() is overridden are iterated in order of their
+// hash, and keys with the same hash in the order they were added
+func TestHashMapDeterministicHashOrderOfEqualHashes(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().DeterministicHash = true
+	defer func() { globals.GetGlobalRef().DeterministicHash = false }()
+
+	hashCodes := map[*object.Object]int64{}
+	stubHashCode(hashCodes)
+	hashMap := makeTestHashMap()
+	fs := frames.CreateFrameStack()
+	for i, hashCode := range []int64{300, 17, 300, -5, 17, 300} {
+		key := object.MakeEmptyObject()
+		hashCodes[key] = hashCode
+		_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, key, populator("java/lang/Integer", types.Int, int64(i))})
+	}
+
+	want := []int64{3, 1, 4, 0, 2, 5}
+	got := hashMapIteratorValues(hashMap)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected iteration order %v, got: %v", want, got)
+	}
+}
+
+// a key that overrides hashCode() is hashed as HashMap.hash() does, from its hashCode()
+func TestHashMapHashCallsHashCode(t *testing.T) {
+	globals.InitGlobals("test")
+
+	key := object.MakeEmptyObject()
+	other := object.MakeEmptyObject()
+	stubHashCode(map[*object.Object]int64{key: 0x12345678, other: 0x12345678})
+	fs := frames.CreateFrameStack()
+	if ret := hashMapHash([]interface{}{fs, key}); ret != int64(0x12345678^0x1234) {
+		t.Errorf("Expected the hash of the key's hashCode(), got: %v", ret)
+	}
+
+	// keys with the same hashCode() but which are not equal are different keys in the same bucket
+	hashMap := makeTestHashMap()
+	one := object.StringObjectFromGoString("one")
+	two := object.StringObjectFromGoString("two")
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, key, one})
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, other, two})
+	if ret := hashMapGetOrDefault([]interface{}{fs, hashMap, key, object.Null}); ret != one {
+		t.Errorf("Expected the first key's value, got: %v", ret)
+	}
+	if ret := hashMapGetOrDefault([]interface{}{fs, hashMap, other, object.Null}); ret != two {
+		t.Errorf("Expected the second key's value, got: %v", ret)
+	}
+
+	// an exception thrown by hashCode() is passed on
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, _, _ string, _ ...any) (any, error) {
+		return nil, errors.New("hashCode failed")
+	}
+	if _, ok := hashMapHash([]interface{}{fs, key}).(*GErrBlk); !ok {
+		t.Error("Expected an error block when hashCode() fails")
+	}
+}
+
+func TestHashMapConstructorValidation(t *testing.T) {
+	globals.InitGlobals("test")
+
+	className := "java/util/HashMap"
+	tests := []struct {
+		name       string
+		capacity   int64
+		loadFactor float64
+		wantErr    string
+	}{
+		{"negative capacity", -1, 0.75, "Illegal initial capacity: -1"},
+		{"NaN load factor", 16, math.NaN(), "Illegal load factor: NaN"},
+		{"zero load factor", 16, 0, "Illegal load factor: 0"},
+	}
+	for _, test := range tests {
+		hashMap := object.MakeEmptyObjectWithClassName(&className)
+		ret := hashMapInitCapacityLoadFactor([]interface{}{hashMap, test.capacity, test.loadFactor})
+		errBlk, ok := ret.(*GErrBlk)
+		if !ok || errBlk.ExceptionType != excNames.IllegalArgumentException || errBlk.ErrMsg != test.wantErr {
+			t.Errorf("%s: expected IllegalArgumentException %q, got: %v", test.name, test.wantErr, ret)
+		}
+	}
+
+	// the capacity is rounded up to a power of two, which becomes the size of the table
+	hashMap := object.MakeEmptyObjectWithClassName(&className)
+	if ret := hashMapInitCapacity([]interface{}{hashMap, int64(20)}); ret != nil {
+		t.Fatalf("Unexpected error from HashMap(20): %v", ret)
+	}
+	_ = hashMapPutIfAbsent([]interface{}{frames.CreateFrameStack(), hashMap, object.StringObjectFromGoString("key"), object.Null})
+	if len(hashMapTable(hashMap)) != 32 {
+		t.Errorf("Expected a table of 32 buckets for an initial capacity of 20, got: %d", len(hashMapTable(hashMap)))
+	}
+}
+
+func TestHashMapContainsValueSizeAndClear(t *testing.T) {
+	globals.InitGlobals("test")
+
+	hashMap := makeTestHashMap()
+	fs := frames.CreateFrameStack()
+	if hashMapIsEmpty([]interface{}{hashMap}) != types.JavaBoolTrue {
+		t.Error("Expected a new map to be empty")
+	}
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("a"), object.StringObjectFromGoString("one")})
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("b"), object.Null})
+
+	if size := hashMapSize([]interface{}{hashMap}); size != int64(2) {
+		t.Errorf("Expected size of 2, got: %v", size)
+	}
+	if hashMapIsEmpty([]interface{}{hashMap}) != types.JavaBoolFalse {
+		t.Error("Expected a map with entries not to be empty")
+	}
+
+	// a different but equal String object, a null value, and a value that's not in the map
+	if hashMapContainsValue([]interface{}{fs, hashMap, object.StringObjectFromGoString("one")}) != types.JavaBoolTrue {
+		t.Error("Expected containsValue(\"one\") to be true")
+	}
+	if hashMapContainsValue([]interface{}{fs, hashMap, object.Null}) != types.JavaBoolTrue {
+		t.Error("Expected containsValue(null) to be true")
+	}
+	if hashMapContainsValue([]interface{}{fs, hashMap, object.StringObjectFromGoString("two")}) != types.JavaBoolFalse {
+		t.Error("Expected containsValue(\"two\") to be false")
+	}
+
+	_ = hashMapClear([]interface{}{hashMap})
+	if hashMapIsEmpty([]interface{}{hashMap}) != types.JavaBoolTrue {
+		t.Error("Expected the map to be empty after clear()")
+	}
+	if hashMapContainsValue([]interface{}{fs, hashMap, object.StringObjectFromGoString("one")}) != types.JavaBoolFalse {
+		t.Error("Expected containsValue(\"one\") to be false after clear()")
+	}
+	if len(hashMapTable(hashMap)) != 16 {
+		t.Errorf("Expected clear() to keep the table, got %d buckets", len(hashMapTable(hashMap)))
+	}
+}
+
+// linkedHashMapKeyOrder returns the keys of a LinkedHashMap in the order its iterators return
+// them, from the head of its list of entries to the tail
+func linkedHashMapKeyOrder(hashMap *object.Object) []string {
+	var keys []string
+	entry, _ := hashMap.FieldTable["head"].Fvalue.(*object.Object)
+	for ; !object.IsNull(entry); entry, _ = entry.FieldTable["after"].Fvalue.(*object.Object) {
+		keys = append(keys, object.GoStringFromStringObject(entry.FieldTable["key"].Fvalue.(*object.Object)))
+	}
+	return keys
+}
+
+func TestLinkedHashMapInsertionOrder(t *testing.T) {
+	globals.InitGlobals("test")
+
+	hashMap := makeTestLinkedHashMap(false)
+	fs := frames.CreateFrameStack()
+	var want []string
+	for i := 20; i > 0; i-- { // enough keys to resize the table, in an order unlike that of their buckets
+		key := fmt.Sprintf("key%d", i)
+		want = append(want, key)
+		_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString(key), object.Null})
+	}
+	// a key that's already present keeps its place
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("key20"), object.Null})
+
+	got := linkedHashMapKeyOrder(hashMap)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected the keys in insertion order %v, got: %v", want, got)
+	}
+	for _, node := range hashMapTable(hashMap) {
+		if !object.IsNull(node) && *stringPool.GetStringPointer(node.KlassName) != linkedHashMapEntryClassName {
+			t.Errorf("Expected the nodes to be %s, got: %s", linkedHashMapEntryClassName,
+				*stringPool.GetStringPointer(node.KlassName))
+		}
+	}
+}
+
+func TestLinkedHashMapAccessOrder(t *testing.T) {
+	globals.InitGlobals("test")
+
+	hashMap := makeTestLinkedHashMap(true)
+	fs := frames.CreateFrameStack()
+	for _, key := range []string{"a", "b", "c"} {
+		_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString(key), object.StringObjectFromGoString(key)})
+	}
+
+	// accessing an entry moves it to the end
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("a"), object.Null})
+	_ = hashMapGetOrDefault([]interface{}{fs, hashMap, object.StringObjectFromGoString("b"), object.Null})
+	if got := fmt.Sprint(linkedHashMapKeyOrder(hashMap)); got != "[c a b]" {
+		t.Errorf("Expected the keys in access order [c a b], got: %s", got)
+	}
+	if tail := hashMap.FieldTable["tail"].Fvalue.(*object.Object); object.GoStringFromStringObject(tail.FieldTable["key"].Fvalue.(*object.Object)) != "b" {
+		t.Error("Expected the last entry accessed to be the tail")
+	}
+}
+
+// makeTestHashMapIterator creates an iterator over the map, as the constructors of the JDK's
+// HashMap$EntryIterator (for entrySet()) and its superclass, HashMap$HashIterator, do
+func makeTestHashMapIterator(hashMap *object.Object) *object.Object {
+	className := "java/util/HashMap$EntryIterator"
+	iterator := object.MakeEmptyObjectWithClassName(&className)
+	_ = hashMapIteratorInit([]interface{}{iterator, hashMap})
+	return iterator
+}
+
+// iterating over the entrySet() of a map visits every entry once, across all the buckets
+func TestHashMapEntrySetIteration(t *testing.T) {
+	globals.InitGlobals("test")
+
+	hashMap := makeTestHashMap()
+	fs := frames.CreateFrameStack()
+	iterator := makeTestHashMapIterator(hashMap)
+	if hashMapIteratorHasNext([]interface{}{iterator}) != types.JavaBoolFalse {
+		t.Error("Expected hasNext() to be false for an empty map")
+	}
+
+	var want int64
+	for i := int64(1); i <= 40; i++ { // enough entries for the table to be resized
+		key := object.StringObjectFromGoString(fmt.Sprintf("key%d", i))
+		_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, key, populator("java/lang/Integer", types.Int, i)})
+		want += i
+	}
+
+	var sum, count int64
+	iterator = makeTestHashMapIterator(hashMap)
+	for hashMapIteratorHasNext([]interface{}{iterator}) == types.JavaBoolTrue {
+		ret := hashMapIteratorNextNode([]interface{}{iterator})
+		entry, ok := ret.(*object.Object)
+		if !ok {
+			t.Fatalf("Expected nextNode() to return an entry, got: %v", ret)
+		}
+		if iterator.FieldTable["current"].Fvalue != entry {
+			t.Errorf("Expected the entry returned to be the iterator's current one")
+		}
+		value := entry.FieldTable["value"].Fvalue.(*object.Object)
+		sum += value.FieldTable["value"].Fvalue.(int64)
+		count++
+	}
+	if count != 40 || sum != want {
+		t.Errorf("Expected 40 entries whose values sum to %d, got %d entries summing to %d", want, count, sum)
+	}
+
+	ret := hashMapIteratorNextNode([]interface{}{iterator})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NoSuchElementException {
+		t.Errorf("Expected nextNode() past the last entry to throw NoSuchElementException, got: %v", ret)
+	}
+}
+
+// adding an entry to a map while iterating over it makes the iterator's next nextNode() throw
+// a ConcurrentModificationException; replacing a value is not a structural modification
+func TestHashMapIteratorConcurrentModification(t *testing.T) {
+	globals.InitGlobals("test")
+
+	hashMap := makeTestHashMap()
+	fs := frames.CreateFrameStack()
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("a"), object.Null})
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("b"), object.Null})
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("c"), object.Null})
+
+	iterator := makeTestHashMapIterator(hashMap)
+	if _, ok := hashMapIteratorNextNode([]interface{}{iterator}).(*object.Object); !ok {
+		t.Fatalf("Expected the first nextNode() to return an entry")
+	}
+
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("a"), object.StringObjectFromGoString("A")})
+	if _, ok := hashMapIteratorNextNode([]interface{}{iterator}).(*object.Object); !ok {
+		t.Fatalf("Expected nextNode() to return an entry after a value was replaced")
+	}
+
+	_ = hashMapPutIfAbsent([]interface{}{fs, hashMap, object.StringObjectFromGoString("d"), object.Null})
+	ret := hashMapIteratorNextNode([]interface{}{iterator})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.ConcurrentModificationException {
+		t.Errorf("Expected nextNode() after an entry was added to throw ConcurrentModificationException, got: %v", ret)
+	}
+}
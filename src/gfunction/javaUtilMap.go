@@ -0,0 +1,191 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+)
+
+// Implementation of some of the default methods of the java/util/Map interface.
+//
+// These run on any Map whose class doesn't override them: FetchDefaultMethod() finds them
+// when it searches the class's interfaces. They work only through the map's own get(), put(),
+// containsKey(), and entrySet(), and the entries' getKey(), getValue(), and setValue(), all of
+// which are called through globals.FuncInvokeMethod, so they can be implemented in Java. As in
+// the JDK, containsKey() is called only when get() returns null, to tell a key mapped to null
+// from an absent one.
+
+func Load_Util_Map() {
+
+	MethodSignatures["java/util/Map.forEach(Ljava/util/function/BiConsumer;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    mapForEach,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Map.getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    mapGetOrDefault,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Map.replace(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots:   2,
+			GFunction:    mapReplace,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Map.replaceAll(Ljava/util/function/BiFunction;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    mapReplaceAll,
+			NeedsContext: true,
+		}
+
+}
+
+// "java/util/Map.forEach(Ljava/util/function/BiConsumer;)V" passes the key and value of each
+// entry, in the order of the map's entrySet(), to the action's accept().
+// params[0] = the frame stack, params[1] = the map, params[2] = the action
+func mapForEach(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "Map.forEach: action is null")
+	}
+
+	return mapVisitEntries(fs, params[1], "Map.forEach", func(entry, key, value any) *GErrBlk {
+		return mapInvoke(fs, params[2], "Map.forEach", "accept",
+			"(Ljava/lang/Object;Ljava/lang/Object;)V", nil, key, value)
+	})
+}
+
+// "java/util/Map.getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+// params[0] = the frame stack, params[1] = the map, params[2] = the key, params[3] = the default
+func mapGetOrDefault(params []interface{}) interface{} {
+	value, present, errBlk := mapGetIfPresent(params[0].(*list.List), params[1], "Map.getOrDefault", params[2])
+	if errBlk != nil {
+		return errBlk
+	}
+	if !present {
+		return params[3]
+	}
+	return value
+}
+
+// "java/util/Map.replace(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;" puts the
+// value only if the key is already in the map, and returns the value it replaced (or null).
+// params[0] = the frame stack, params[1] = the map, params[2] = the key, params[3] = the value
+func mapReplace(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	current, present, errBlk := mapGetIfPresent(fs, params[1], "Map.replace", params[2])
+	if errBlk != nil {
+		return errBlk
+	}
+	if !present {
+		return object.Null
+	}
+
+	if errBlk := mapInvoke(fs, params[1], "Map.replace", "put",
+		"(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;", &current, params[2], params[3]); errBlk != nil {
+		return errBlk
+	}
+	return current
+}
+
+// "java/util/Map.replaceAll(Ljava/util/function/BiFunction;)V" sets the value of each entry to
+// the result of the function's apply() on the entry's key and value.
+// params[0] = the frame stack, params[1] = the map, params[2] = the function
+func mapReplaceAll(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "Map.replaceAll: function is null")
+	}
+
+	return mapVisitEntries(fs, params[1], "Map.replaceAll", func(entry, key, value any) *GErrBlk {
+		var newValue any
+		if errBlk := mapInvoke(fs, params[2], "Map.replaceAll", "apply",
+			"(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;", &newValue, key, value); errBlk != nil {
+			return errBlk
+		}
+		return mapInvoke(fs, entry, "Map.replaceAll", "setValue",
+			"(Ljava/lang/Object;)Ljava/lang/Object;", nil, newValue)
+	})
+}
+
+// mapGetIfPresent returns the value that the map's get() returns for the key and whether the
+// key is in the map. As in the JDK, a null value is checked with the map's containsKey().
+func mapGetIfPresent(fs *list.List, mapObj any, caller string, key any) (any, bool, *GErrBlk) {
+	var value, contains any
+	if errBlk := mapInvoke(fs, mapObj, caller, "get",
+		"(Ljava/lang/Object;)Ljava/lang/Object;", &value, key); errBlk != nil {
+		return nil, false, errBlk
+	}
+	if !object.IsNull(value) {
+		return value, true, nil
+	}
+	if errBlk := mapInvoke(fs, mapObj, caller, "containsKey",
+		"(Ljava/lang/Object;)Z", &contains, key); errBlk != nil {
+		return nil, false, errBlk
+	}
+	return value, contains == types.JavaBoolTrue, nil
+}
+
+// mapVisitEntries iterates over the map's entrySet() and calls visit with each entry and its
+// key and value. It stops at the first error, which it returns; otherwise, it returns nil.
+func mapVisitEntries(fs *list.List, mapObj any, caller string, visit func(entry, key, value any) *GErrBlk) interface{} {
+	var entrySet, iterator any
+	if errBlk := mapInvoke(fs, mapObj, caller, "entrySet", "()Ljava/util/Set;", &entrySet); errBlk != nil {
+		return errBlk
+	}
+	if errBlk := mapInvoke(fs, entrySet, caller, "iterator", "()Ljava/util/Iterator;", &iterator); errBlk != nil {
+		return errBlk
+	}
+
+	for {
+		var hasNext, entry, key, value any
+		if errBlk := mapInvoke(fs, iterator, caller, "hasNext", "()Z", &hasNext); errBlk != nil {
+			return errBlk
+		}
+		if hasNext != types.JavaBoolTrue {
+			return nil
+		}
+		if errBlk := mapInvoke(fs, iterator, caller, "next", "()Ljava/lang/Object;", &entry); errBlk != nil {
+			return errBlk
+		}
+		if errBlk := mapInvoke(fs, entry, caller, "getKey", "()Ljava/lang/Object;", &key); errBlk != nil {
+			return errBlk
+		}
+		if errBlk := mapInvoke(fs, entry, caller, "getValue", "()Ljava/lang/Object;", &value); errBlk != nil {
+			return errBlk
+		}
+		if errBlk := visit(entry, key, value); errBlk != nil {
+			return errBlk
+		}
+	}
+}
+
+// mapInvoke runs a method on obj through globals.FuncInvokeMethod and, if ret isn't nil,
+// stores the method's return value in it. A failure is returned as a VirtualMachineError.
+func mapInvoke(fs *list.List, obj any, caller, methodName, methodType string, ret *any, args ...any) *GErrBlk {
+	value, err := globals.GetGlobalRef().FuncInvokeMethod(fs, obj, methodName, methodType, args...)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s: %s() failed: %s", caller, methodName, err.Error())
+		return getGErrBlk(excNames.VirtualMachineError, errMsg)
+	}
+	if ret != nil {
+		*ret = value
+	}
+	return nil
+}
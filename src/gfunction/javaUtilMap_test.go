@@ -0,0 +1,166 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"slices"
+	"testing"
+)
+
+// stubMapMethods makes FuncInvokeMethod run the methods of a map, its entry set, iterator, and
+// entries, and of the functions passed to the Map methods, on the given keys and values. A key
+// whose value is "" is mapped to null. The functions record their calls in calls. apply()
+// returns the value with "!" appended.
+func stubMapMethods(keys []string, values map[string]string, calls *[]string) {
+	index := 0
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, obj any, methodName, _ string, args ...any) (any, error) {
+		str := func(arg any) string { return object.GoStringFromStringObject(arg.(*object.Object)) }
+		switch methodName {
+		case "get":
+			if value, ok := values[str(args[0])]; ok && value != "" {
+				return object.StringObjectFromGoString(value), nil
+			}
+			return object.Null, nil
+		case "containsKey":
+			_, ok := values[str(args[0])]
+			return types.ConvertGoBoolToJavaBool(ok), nil
+		case "put":
+			old := values[str(args[0])]
+			values[str(args[0])] = str(args[1])
+			if old == "" {
+				return object.Null, nil
+			}
+			return object.StringObjectFromGoString(old), nil
+		case "entrySet", "iterator":
+			return obj, nil
+		case "hasNext":
+			return types.ConvertGoBoolToJavaBool(index < len(keys)), nil
+		case "next":
+			index++
+			return object.StringObjectFromGoString(keys[index-1]), nil // the entry is its key
+		case "getKey":
+			return obj, nil
+		case "getValue":
+			return object.StringObjectFromGoString(values[str(obj)]), nil
+		case "setValue":
+			values[str(obj)] = str(args[0])
+			return object.Null, nil
+		case "accept":
+			*calls = append(*calls, str(args[0])+"="+str(args[1]))
+			return nil, nil
+		case "apply":
+			*calls = append(*calls, str(args[0])+"="+str(args[1]))
+			return object.StringObjectFromGoString(str(args[1]) + "!"), nil
+		}
+		return nil, nil
+	}
+}
+
+func TestMapForEach(t *testing.T) {
+	globals.InitGlobals("test")
+	var calls []string
+	stubMapMethods([]string{"a", "b"}, map[string]string{"a": "1", "b": "2"}, &calls)
+
+	className := "test/UserMap"
+	userMap := object.MakeEmptyObjectWithClassName(&className)
+	action := object.MakeEmptyObjectWithClassName(&className)
+	if ret := mapForEach([]interface{}{frames.CreateFrameStack(), userMap, action}); ret != nil {
+		t.Fatalf("Unexpected error from forEach(): %v", ret)
+	}
+	if !slices.Equal(calls, []string{"a=1", "b=2"}) {
+		t.Errorf("Expected the action to accept a=1, then b=2, got: %v", calls)
+	}
+
+	ret := mapForEach([]interface{}{frames.CreateFrameStack(), userMap, object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected a NullPointerException for a null action, got: %v", ret)
+	}
+}
+
+func TestMapReplace(t *testing.T) {
+	globals.InitGlobals("test")
+	values := map[string]string{"a": "1"}
+	stubMapMethods(nil, values, nil)
+
+	className := "test/UserMap"
+	userMap := object.MakeEmptyObjectWithClassName(&className)
+	ret := mapReplace([]interface{}{frames.CreateFrameStack(), userMap,
+		object.StringObjectFromGoString("a"), object.StringObjectFromGoString("9")})
+	if str, ok := ret.(*object.Object); !ok || object.GoStringFromStringObject(str) != "1" || values["a"] != "9" {
+		t.Errorf("Expected replace() to return 1 and set a to 9, got: %v and %q", ret, values["a"])
+	}
+
+	// a key that's not in the map is not added
+	ret = mapReplace([]interface{}{frames.CreateFrameStack(), userMap,
+		object.StringObjectFromGoString("z"), object.StringObjectFromGoString("9")})
+	if _, added := values["z"]; !object.IsNull(ret) || added {
+		t.Errorf("Expected replace() of a missing key to return null and not add it, got: %v", ret)
+	}
+
+	// a key mapped to null is in the map, so its value is replaced
+	values["n"] = ""
+	ret = mapReplace([]interface{}{frames.CreateFrameStack(), userMap,
+		object.StringObjectFromGoString("n"), object.StringObjectFromGoString("9")})
+	if !object.IsNull(ret) || values["n"] != "9" {
+		t.Errorf("Expected replace() of a key mapped to null to return null and set it to 9, got: %v and %q",
+			ret, values["n"])
+	}
+}
+
+func TestMapGetOrDefault(t *testing.T) {
+	globals.InitGlobals("test")
+	stubMapMethods(nil, map[string]string{"a": "1", "n": ""}, nil)
+
+	className := "test/UserMap"
+	userMap := object.MakeEmptyObjectWithClassName(&className)
+	tests := []struct {
+		key      string
+		expected *object.Object
+	}{
+		{"a", object.StringObjectFromGoString("1")},
+		{"z", object.StringObjectFromGoString("default")},
+		{"n", object.Null}, // as in the JDK, a key mapped to null returns null, not the default
+	}
+	for _, test := range tests {
+		ret := mapGetOrDefault([]interface{}{frames.CreateFrameStack(), userMap,
+			object.StringObjectFromGoString(test.key), object.StringObjectFromGoString("default")})
+		if object.IsNull(test.expected) {
+			if !object.IsNull(ret) {
+				t.Errorf("getOrDefault(%q): expected null, got: %v", test.key, ret)
+			}
+			continue
+		}
+		str, ok := ret.(*object.Object)
+		if !ok || object.GoStringFromStringObject(str) != object.GoStringFromStringObject(test.expected) {
+			t.Errorf("getOrDefault(%q): expected %q, got: %v", test.key,
+				object.GoStringFromStringObject(test.expected), ret)
+		}
+	}
+}
+
+func TestMapReplaceAll(t *testing.T) {
+	globals.InitGlobals("test")
+	var calls []string
+	values := map[string]string{"a": "1", "b": "2"}
+	stubMapMethods([]string{"a", "b"}, values, &calls)
+
+	className := "test/UserMap"
+	userMap := object.MakeEmptyObjectWithClassName(&className)
+	function := object.MakeEmptyObjectWithClassName(&className)
+	if ret := mapReplaceAll([]interface{}{frames.CreateFrameStack(), userMap, function}); ret != nil {
+		t.Fatalf("Unexpected error from replaceAll(): %v", ret)
+	}
+	if !slices.Equal(calls, []string{"a=1", "b=2"}) || values["a"] != "1!" || values["b"] != "2!" {
+		t.Errorf("Expected replaceAll() to apply the function to a=1 and b=2, got: %v, %v", calls, values)
+	}
+}
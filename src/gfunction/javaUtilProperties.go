@@ -0,0 +1,691 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"jacobin/excNames"
+	"jacobin/object"
+	"jacobin/types"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Implementation of java.util.Properties. Rather than run the JDK's Hashtable-based
+// bytecode, the properties are kept in a golang map[string]string in the "value" field
+// of the Properties object, in the same way that BigInteger keeps its *big.Int.
+// So, only Strings can be keys and values. The map methods that Properties inherits
+// from Hashtable (get(), put(), remove(), and so on) work on the golang map, and those
+// that return a view of it, such as keySet(), entrySet(), and stringPropertyNames(),
+// are trapped, as is every other method that would run the JDK's bytecode on a map
+// that the Properties object doesn't have.
+// Streams are those that Jacobin implements in golang: objects with a FileHandle
+// field (such as FileInputStream and FileOutputStream) and System.out/System.err.
+
+func Load_Util_Properties() {
+
+	MethodSignatures["java/util/Properties.<clinit>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  justReturn,
+		}
+
+	MethodSignatures["java/util/Properties.<init>()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  propertiesInit,
+		}
+
+	MethodSignatures["java/util/Properties.clear()V"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  propertiesClear,
+		}
+
+	MethodSignatures["java/util/Properties.clone()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  propertiesClone,
+		}
+
+	MethodSignatures["java/util/Properties.contains(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertiesContainsValue,
+		}
+
+	MethodSignatures["java/util/Properties.containsKey(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertiesContainsKey,
+		}
+
+	MethodSignatures["java/util/Properties.containsValue(Ljava/lang/Object;)Z"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertiesContainsValue,
+		}
+
+	MethodSignatures["java/util/Properties.forEach(Ljava/util/function/BiConsumer;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    propertiesForEach,
+			NeedsContext: true,
+		}
+
+	MethodSignatures["java/util/Properties.get(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertiesGet,
+		}
+
+	MethodSignatures["java/util/Properties.getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  propertiesGet,
+		}
+
+	MethodSignatures["java/util/Properties.getProperty(Ljava/lang/String;)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertiesGetProperty,
+		}
+
+	MethodSignatures["java/util/Properties.getProperty(Ljava/lang/String;Ljava/lang/String;)Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  propertiesGetProperty,
+		}
+
+	MethodSignatures["java/util/Properties.isEmpty()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  propertiesIsEmpty,
+		}
+
+	MethodSignatures["java/util/Properties.load(Ljava/io/InputStream;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertiesLoad,
+		}
+
+	MethodSignatures["java/util/Properties.put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  propertiesPut,
+		}
+
+	MethodSignatures["java/util/Properties.remove(Ljava/lang/Object;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  propertiesRemove,
+		}
+
+	MethodSignatures["java/util/Properties.setProperty(Ljava/lang/String;Ljava/lang/String;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  propertiesSetProperty,
+		}
+
+	MethodSignatures["java/util/Properties.size()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  propertiesSize,
+		}
+
+	MethodSignatures["java/util/Properties.store(Ljava/io/OutputStream;Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  propertiesStore,
+		}
+
+	MethodSignatures["java/util/Properties.toString()Ljava/lang/String;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  propertiesToString,
+		}
+
+	// -----------------------------------------
+	// Traps that do nothing but return an error
+	// -----------------------------------------
+
+	MethodSignatures["java/util/Properties.<init>(I)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/Properties.<init>(Ljava/util/Properties;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/Properties.load(Ljava/io/Reader;)V"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  trapFunction,
+		}
+
+	MethodSignatures["java/util/Properties.store(Ljava/io/Writer;Ljava/lang/String;)V"] =
+		GMeth{
+			ParamSlots: 2,
+			GFunction:  trapFunction,
+		}
+
+	trapMethods("java/util/Properties",
+		"compute(Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"computeIfAbsent(Ljava/lang/Object;Ljava/util/function/Function;)Ljava/lang/Object;",
+		"computeIfPresent(Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"elements()Ljava/util/Enumeration;",
+		"entrySet()Ljava/util/Set;",
+		"equals(Ljava/lang/Object;)Z",
+		"hashCode()I",
+		"keySet()Ljava/util/Set;",
+		"keys()Ljava/util/Enumeration;",
+		"list(Ljava/io/PrintStream;)V",
+		"list(Ljava/io/PrintWriter;)V",
+		"loadFromXML(Ljava/io/InputStream;)V",
+		"merge(Ljava/lang/Object;Ljava/lang/Object;Ljava/util/function/BiFunction;)Ljava/lang/Object;",
+		"propertyNames()Ljava/util/Enumeration;",
+		"putAll(Ljava/util/Map;)V",
+		"putIfAbsent(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;",
+		"remove(Ljava/lang/Object;Ljava/lang/Object;)Z",
+		"replace(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;",
+		"replace(Ljava/lang/Object;Ljava/lang/Object;Ljava/lang/Object;)Z",
+		"replaceAll(Ljava/util/function/BiFunction;)V",
+		"save(Ljava/io/OutputStream;Ljava/lang/String;)V",
+		"storeToXML(Ljava/io/OutputStream;Ljava/lang/String;)V",
+		"storeToXML(Ljava/io/OutputStream;Ljava/lang/String;Ljava/lang/String;)V",
+		"storeToXML(Ljava/io/OutputStream;Ljava/lang/String;Ljava/nio/charset/Charset;)V",
+		"stringPropertyNames()Ljava/util/Set;",
+		"values()Ljava/util/Collection;",
+	)
+}
+
+// "java/util/Properties.<init>()V"
+func propertiesInit(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	obj.FieldTable["value"] = object.Field{Ftype: types.Properties, Fvalue: make(map[string]string)}
+	return nil
+}
+
+// "java/util/Properties.getProperty(Ljava/lang/String;)Ljava/lang/String;"
+// "java/util/Properties.getProperty(Ljava/lang/String;Ljava/lang/String;)Ljava/lang/String;"
+func propertiesGetProperty(params []interface{}) interface{} {
+	// params[0] = the Properties object
+	// params[1] = the key
+	// params[2] = the default value, if any
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.getProperty: null key")
+	}
+	props := propertiesMap(params[0].(*object.Object))
+	key := object.GoStringFromStringObject(params[1].(*object.Object))
+
+	value, ok := props[key]
+	if ok {
+		return object.StringObjectFromGoString(value)
+	}
+	if len(params) > 2 && !object.IsNull(params[2]) {
+		return params[2]
+	}
+	return object.Null
+}
+
+// propertiesKey returns the string of a key or value passed as an Object, and whether it is a
+// String, which is all that the golang map can hold
+func propertiesKey(arg interface{}) (string, bool) {
+	if object.IsNull(arg) || !object.IsStringObject(arg.(*object.Object)) {
+		return "", false
+	}
+	return object.GoStringFromStringObject(arg.(*object.Object)), true
+}
+
+// "java/util/Properties.get(Ljava/lang/Object;)Ljava/lang/Object;" and
+// "java/util/Properties.getOrDefault(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+// As in Hashtable, a null key is a NullPointerException. A key that's not a String can't be
+// in the map, so its value is null (or the default).
+func propertiesGet(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.get: null key")
+	}
+	if key, ok := propertiesKey(params[1]); ok {
+		if value, ok := propertiesMap(params[0].(*object.Object))[key]; ok {
+			return object.StringObjectFromGoString(value)
+		}
+	}
+	if len(params) > 2 {
+		return params[2]
+	}
+	return object.Null
+}
+
+// "java/util/Properties.put(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;" Only a
+// String key and value can be put in the golang map, so any other is an
+// UnsupportedOperationException.
+func propertiesPut(params []interface{}) interface{} {
+	if object.IsNull(params[1]) || object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.put: null key or value")
+	}
+	if _, ok := propertiesKey(params[1]); !ok {
+		return getGErrBlk(excNames.UnsupportedOperationException, "Properties.put: key is not a String")
+	}
+	if _, ok := propertiesKey(params[2]); !ok {
+		return getGErrBlk(excNames.UnsupportedOperationException, "Properties.put: value is not a String")
+	}
+	return propertiesSetProperty(params)
+}
+
+// "java/util/Properties.remove(Ljava/lang/Object;)Ljava/lang/Object;" returns the value
+// removed, or null if the key was not in the map
+func propertiesRemove(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.remove: null key")
+	}
+	props := propertiesMap(params[0].(*object.Object))
+	key, _ := propertiesKey(params[1])
+	value, ok := props[key]
+	if !ok {
+		return object.Null
+	}
+	delete(props, key)
+	return object.StringObjectFromGoString(value)
+}
+
+// "java/util/Properties.containsKey(Ljava/lang/Object;)Z"
+func propertiesContainsKey(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.containsKey: null key")
+	}
+	key, isString := propertiesKey(params[1])
+	if _, ok := propertiesMap(params[0].(*object.Object))[key]; ok && isString {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/Properties.containsValue(Ljava/lang/Object;)Z" and
+// "java/util/Properties.contains(Ljava/lang/Object;)Z"
+func propertiesContainsValue(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.containsValue: null value")
+	}
+	if value, ok := propertiesKey(params[1]); ok {
+		for _, v := range propertiesMap(params[0].(*object.Object)) {
+			if v == value {
+				return types.JavaBoolTrue
+			}
+		}
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/Properties.isEmpty()Z"
+func propertiesIsEmpty(params []interface{}) interface{} {
+	if len(propertiesMap(params[0].(*object.Object))) == 0 {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/Properties.clear()V"
+func propertiesClear(params []interface{}) interface{} {
+	clear(propertiesMap(params[0].(*object.Object)))
+	return nil
+}
+
+// "java/util/Properties.clone()Ljava/lang/Object;" returns a Properties holding a copy of the map
+func propertiesClone(params []interface{}) interface{} {
+	obj := params[0].(*object.Object)
+	clone := object.MakeEmptyObject()
+	clone.KlassName = obj.KlassName
+	object.TrackObject(clone)
+	clone.FieldTable["value"] = object.Field{Ftype: types.Properties, Fvalue: maps.Clone(propertiesMap(obj))}
+	return clone
+}
+
+// "java/util/Properties.forEach(Ljava/util/function/BiConsumer;)V" passes each key and value,
+// in the order of the keys, to the action's accept(), which is run through
+// globals.FuncInvokeMethod.
+// params[0] = the frame stack, params[1] = the Properties, params[2] = the action
+func propertiesForEach(params []interface{}) interface{} {
+	fs := params[0].(*list.List)
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.forEach: action is null")
+	}
+	props := propertiesMap(params[1].(*object.Object))
+	for _, key := range propertiesSortedKeys(props) {
+		if errBlk := mapInvoke(fs, params[2], "Properties.forEach", "accept",
+			"(Ljava/lang/Object;Ljava/lang/Object;)V", nil,
+			object.StringObjectFromGoString(key), object.StringObjectFromGoString(props[key])); errBlk != nil {
+			return errBlk
+		}
+	}
+	return nil
+}
+
+// "java/util/Properties.toString()Ljava/lang/String;" returns the entries in the form that
+// Hashtable gives them, {key1=value1, key2=value2}, in the order of the keys
+func propertiesToString(params []interface{}) interface{} {
+	props := propertiesMap(params[0].(*object.Object))
+	entries := make([]string, 0, len(props))
+	for _, key := range propertiesSortedKeys(props) {
+		entries = append(entries, key+"="+props[key])
+	}
+	return object.StringObjectFromGoString("{" + strings.Join(entries, ", ") + "}")
+}
+
+// propertiesSortedKeys returns the keys of the map, sorted
+func propertiesSortedKeys(props map[string]string) []string {
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// "java/util/Properties.load(Ljava/io/InputStream;)V"
+func propertiesLoad(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.load: null input stream")
+	}
+	reader, ok := streamFile(params[1])
+	if !ok {
+		errMsg := "Properties.load: input stream lacks a FileHandle field"
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+
+	bytes, err := io.ReadAll(reader)
+	if err != nil {
+		errMsg := fmt.Sprintf("Properties.load: read failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+
+	// A byte stream holds ISO 8859-1 characters, each of which maps to the same code point.
+	chars := make([]rune, len(bytes))
+	for i, b := range bytes {
+		chars[i] = rune(b)
+	}
+
+	props := propertiesMap(params[0].(*object.Object))
+	errMsg := parseProperties(chars, props)
+	if errMsg != "" {
+		return getGErrBlk(excNames.IllegalArgumentException, errMsg)
+	}
+	return nil
+}
+
+// "java/util/Properties.setProperty(Ljava/lang/String;Ljava/lang/String;)Ljava/lang/Object;"
+func propertiesSetProperty(params []interface{}) interface{} {
+	if object.IsNull(params[1]) || object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.setProperty: null key or value")
+	}
+	props := propertiesMap(params[0].(*object.Object))
+	key := object.GoStringFromStringObject(params[1].(*object.Object))
+	value := object.GoStringFromStringObject(params[2].(*object.Object))
+
+	previous, ok := props[key]
+	props[key] = value
+	if ok {
+		return object.StringObjectFromGoString(previous)
+	}
+	return object.Null
+}
+
+// "java/util/Properties.size()I"
+func propertiesSize(params []interface{}) interface{} {
+	return int64(len(propertiesMap(params[0].(*object.Object))))
+}
+
+// "java/util/Properties.store(Ljava/io/OutputStream;Ljava/lang/String;)V"
+// The JDK writes the entries in hash-table order; here they are sorted by key,
+// which is an order that the JDK could also produce.
+func propertiesStore(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Properties.store: null output stream")
+	}
+	writer, ok := streamFile(params[1])
+	if !ok {
+		errMsg := "Properties.store: output stream lacks a FileHandle field"
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+
+	var sb strings.Builder
+	if !object.IsNull(params[2]) {
+		comments := object.GoStringFromStringObject(params[2].(*object.Object))
+		for _, line := range strings.Split(strings.ReplaceAll(comments, "\r\n", "\n"), "\n") {
+			sb.WriteString("#" + line + lineSeparator())
+		}
+	}
+	sb.WriteString("#" + time.Now().Format("Mon Jan 02 15:04:05 MST 2006") + lineSeparator())
+
+	props := propertiesMap(params[0].(*object.Object))
+	for _, key := range propertiesSortedKeys(props) {
+		sb.WriteString(escapeProperty(key, true) + "=" +
+			escapeProperty(props[key], false) + lineSeparator())
+	}
+
+	if _, err := io.WriteString(writer, sb.String()); err != nil {
+		errMsg := fmt.Sprintf("Properties.store: write failed, reason: %s", err.Error())
+		return getGErrBlk(excNames.IOException, errMsg)
+	}
+	return nil
+}
+
+// propertiesMap returns the map holding the properties, creating it if the
+// Properties object was not constructed by propertiesInit.
+func propertiesMap(obj *object.Object) map[string]string {
+	props, ok := obj.FieldTable["value"].Fvalue.(map[string]string)
+	if !ok {
+		props = make(map[string]string)
+		obj.FieldTable["value"] = object.Field{Ftype: types.Properties, Fvalue: props}
+	}
+	return props
+}
+
+// streamFile returns the golang file that underlies a stream argument: either System.out
+// or System.err, which are represented by their *os.File, or a stream object whose
+// FileHandle field holds the *os.File.
+func streamFile(stream interface{}) (*os.File, bool) {
+	switch stream.(type) {
+	case *os.File:
+		return stream.(*os.File), true
+	case *object.Object:
+		osFile, ok := stream.(*object.Object).FieldTable[FileHandle].Fvalue.(*os.File)
+		return osFile, ok
+	}
+	return nil, false
+}
+
+// parseProperties parses the contents of a .properties file into props, following
+// the rules of java.util.Properties.load(): comment lines begin with # or !, a line
+// ending with an odd number of backslashes continues on the next line, and the key
+// ends at the first unescaped =, :, or white space. Returns an error message if an
+// escape sequence is malformed, else "".
+func parseProperties(chars []rune, props map[string]string) string {
+	isWhite := func(c rune) bool { return c == ' ' || c == '\t' || c == '\f' }
+	isEol := func(c rune) bool { return c == '\n' || c == '\r' }
+
+	pos := 0
+	for pos < len(chars) {
+		// skip white space at the start of the natural line, and blank lines
+		for pos < len(chars) && (isWhite(chars[pos]) || isEol(chars[pos])) {
+			pos++
+		}
+		if pos >= len(chars) {
+			break
+		}
+
+		// skip comment lines
+		if chars[pos] == '#' || chars[pos] == '!' {
+			for pos < len(chars) && !isEol(chars[pos]) {
+				pos++
+			}
+			continue
+		}
+
+		// gather the logical line, joining continuation lines
+		var line []rune
+		for pos < len(chars) && !isEol(chars[pos]) {
+			if chars[pos] == '\\' && pos+1 < len(chars) && isEol(chars[pos+1]) {
+				// skip the line terminator and the leading white space of the next line
+				pos++
+				if chars[pos] == '\r' && pos+1 < len(chars) && chars[pos+1] == '\n' {
+					pos++
+				}
+				pos++
+				for pos < len(chars) && isWhite(chars[pos]) {
+					pos++
+				}
+				continue
+			}
+			if chars[pos] == '\\' && pos+1 < len(chars) {
+				line = append(line, chars[pos], chars[pos+1]) // keep escapes for unescapeProperty
+				pos += 2
+				continue
+			}
+			line = append(line, chars[pos])
+			pos++
+		}
+
+		// the key ends at the first unescaped separator
+		keyEnd := 0
+		for keyEnd < len(line) {
+			c := line[keyEnd]
+			if c == '\\' {
+				keyEnd += 2
+				continue
+			}
+			if c == '=' || c == ':' || isWhite(c) {
+				break
+			}
+			keyEnd++
+		}
+		if keyEnd > len(line) {
+			keyEnd = len(line)
+		}
+
+		// the value starts after white space, at most one = or :, and more white space
+		valueStart := keyEnd
+		for valueStart < len(line) && isWhite(line[valueStart]) {
+			valueStart++
+		}
+		if valueStart < len(line) && (line[valueStart] == '=' || line[valueStart] == ':') {
+			valueStart++
+		}
+		for valueStart < len(line) && isWhite(line[valueStart]) {
+			valueStart++
+		}
+
+		key, errMsg := unescapeProperty(line[:keyEnd])
+		if errMsg != "" {
+			return errMsg
+		}
+		value, errMsg := unescapeProperty(line[valueStart:])
+		if errMsg != "" {
+			return errMsg
+		}
+		props[key] = value
+	}
+	return ""
+}
+
+// unescapeProperty converts the escape sequences in a key or value: \t, \n, \r, \f,
+// \uXXXX, and a backslash before any other character, which yields that character.
+func unescapeProperty(chars []rune) (string, string) {
+	var sb strings.Builder
+	for i := 0; i < len(chars); i++ {
+		c := chars[i]
+		if c != '\\' {
+			sb.WriteRune(c)
+			continue
+		}
+		i++
+		if i >= len(chars) {
+			break // a trailing backslash is dropped, as in the JDK
+		}
+		switch chars[i] {
+		case 't':
+			sb.WriteRune('\t')
+		case 'n':
+			sb.WriteRune('\n')
+		case 'r':
+			sb.WriteRune('\r')
+		case 'f':
+			sb.WriteRune('\f')
+		case 'u':
+			if i+4 >= len(chars) {
+				return "", "Malformed \\uxxxx encoding."
+			}
+			var code rune
+			for _, h := range chars[i+1 : i+5] {
+				switch {
+				case h >= '0' && h <= '9':
+					code = code<<4 + h - '0'
+				case h >= 'a' && h <= 'f':
+					code = code<<4 + h - 'a' + 10
+				case h >= 'A' && h <= 'F':
+					code = code<<4 + h - 'A' + 10
+				default:
+					return "", "Malformed \\uxxxx encoding."
+				}
+			}
+			sb.WriteRune(code)
+			i += 4
+		default:
+			sb.WriteRune(chars[i])
+		}
+	}
+	return sb.String(), ""
+}
+
+// escapeProperty escapes a key or value for writing by Properties.store(). Spaces are
+// escaped throughout a key but only at the start of a value; characters outside of
+// printable ASCII are written as \uXXXX, since the output is ISO 8859-1.
+func escapeProperty(str string, isKey bool) string {
+	var sb strings.Builder
+	for i, c := range []rune(str) {
+		switch c {
+		case ' ':
+			if isKey || i == 0 {
+				sb.WriteString("\\ ")
+			} else {
+				sb.WriteRune(c)
+			}
+		case '\t':
+			sb.WriteString("\\t")
+		case '\n':
+			sb.WriteString("\\n")
+		case '\r':
+			sb.WriteString("\\r")
+		case '\f':
+			sb.WriteString("\\f")
+		case '\\', '=', ':', '#', '!':
+			sb.WriteRune('\\')
+			sb.WriteRune(c)
+		default:
+			if c < 0x20 || c > 0x7e {
+				if c > 0xFFFF { // written as a surrogate pair, as Java chars are 16 bits
+					high, low := utf16.EncodeRune(c)
+					sb.WriteString(fmt.Sprintf("\\u%04X\\u%04X", high, low))
+				} else {
+					sb.WriteString(fmt.Sprintf("\\u%04X", c))
+				}
+			} else {
+				sb.WriteRune(c)
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,240 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"os"
+	"strings"
+	"testing"
+)
+
+// makeTestStream returns a stream object whose FileHandle is a temporary file holding contents.
+func makeTestStream(t *testing.T, contents string) *object.Object {
+	osFile, err := os.CreateTemp(t.TempDir(), "props")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %s", err.Error())
+	}
+	t.Cleanup(func() { _ = osFile.Close() })
+	if _, err = osFile.WriteString(contents); err != nil {
+		t.Fatalf("Could not write temporary file: %s", err.Error())
+	}
+	_, _ = osFile.Seek(0, 0)
+
+	stream := object.MakeEmptyObject()
+	stream.FieldTable[FileHandle] = object.Field{Ftype: types.FileHandle, Fvalue: osFile}
+	return stream
+}
+
+func getTestProperty(props *object.Object, key string) interface{} {
+	return propertiesGetProperty([]interface{}{props, object.StringObjectFromGoString(key)})
+}
+
+func TestPropertiesLoad(t *testing.T) {
+	globals.InitGlobals("test")
+
+	props := object.MakeEmptyObject()
+	propertiesInit([]interface{}{props})
+
+	contents := "# a comment\n" +
+		"! another comment\n" +
+		"\n" +
+		"name = Jacobin\n" +
+		"url:https://jacobin.org\n" +
+		"a\\=b = equals in key\n" +
+		"   indented    value with spaces  \n" +
+		"list = one, \\\n" +
+		"       two, \\\n" +
+		"       three\n" +
+		"tab\\tchar = \\u0041\\n\n" +
+		"empty\n"
+	ret := propertiesLoad([]interface{}{props, makeTestStream(t, contents)})
+	if ret != nil {
+		t.Fatalf("Properties.load returned an unexpected error: %v", ret)
+	}
+
+	expected := map[string]string{
+		"name":      "Jacobin",
+		"url":       "https://jacobin.org",
+		"a=b":       "equals in key",
+		"indented":  "value with spaces  ",
+		"list":      "one, two, three",
+		"tab\tchar": "A\n",
+		"empty":     "",
+	}
+	for key, want := range expected {
+		ret = getTestProperty(props, key)
+		if object.IsNull(ret) {
+			t.Errorf("Expected key %q to be present", key)
+			continue
+		}
+		if got := object.GoStringFromStringObject(ret.(*object.Object)); got != want {
+			t.Errorf("Key %q: expected %q, got %q", key, want, got)
+		}
+	}
+	if size := propertiesSize([]interface{}{props}).(int64); size != int64(len(expected)) {
+		t.Errorf("Expected %d properties, got %d", len(expected), size)
+	}
+
+	if !object.IsNull(getTestProperty(props, "a comment")) {
+		t.Errorf("Comment line was loaded as a property")
+	}
+}
+
+func TestPropertiesGetSetProperty(t *testing.T) {
+	globals.InitGlobals("test")
+
+	props := object.MakeEmptyObject()
+	propertiesInit([]interface{}{props})
+
+	key := object.StringObjectFromGoString("color")
+	ret := propertiesSetProperty([]interface{}{props, key, object.StringObjectFromGoString("red")})
+	if !object.IsNull(ret) {
+		t.Errorf("Expected null from first setProperty, got %v", ret)
+	}
+	ret = propertiesSetProperty([]interface{}{props, key, object.StringObjectFromGoString("blue")})
+	if object.GoStringFromStringObject(ret.(*object.Object)) != "red" {
+		t.Errorf("Expected previous value \"red\" from setProperty, got %v", ret)
+	}
+
+	ret = getTestProperty(props, "color")
+	if object.GoStringFromStringObject(ret.(*object.Object)) != "blue" {
+		t.Errorf("Expected \"blue\", got %v", ret)
+	}
+
+	// missing keys return null, or the default if one is given
+	if !object.IsNull(getTestProperty(props, "size")) {
+		t.Errorf("Expected null for a missing key")
+	}
+	ret = propertiesGetProperty([]interface{}{props, object.StringObjectFromGoString("size"),
+		object.StringObjectFromGoString("large")})
+	if object.GoStringFromStringObject(ret.(*object.Object)) != "large" {
+		t.Errorf("Expected default value \"large\", got %v", ret)
+	}
+}
+
+func TestPropertiesStoreThenLoad(t *testing.T) {
+	globals.InitGlobals("test")
+
+	props := object.MakeEmptyObject()
+	propertiesInit([]interface{}{props})
+	for key, value := range map[string]string{"a=b": " leading space", "path": "c:\\temp", "é": "ü"} {
+		propertiesSetProperty([]interface{}{props, object.StringObjectFromGoString(key),
+			object.StringObjectFromGoString(value)})
+	}
+
+	out := makeTestStream(t, "")
+	ret := propertiesStore([]interface{}{props, out, object.StringObjectFromGoString("test settings")})
+	if ret != nil {
+		t.Fatalf("Properties.store returned an unexpected error: %v", ret)
+	}
+
+	osFile := out.FieldTable[FileHandle].Fvalue.(*os.File)
+	stored, _ := os.ReadFile(osFile.Name())
+	text := string(stored)
+	for _, want := range []string{"#test settings", "a\\=b=\\ leading space", "path=c\\:\\\\temp", "\\u00E9=\\u00FC"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected stored properties to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	// reading back what was stored gives the original properties
+	reloaded := object.MakeEmptyObject()
+	propertiesInit([]interface{}{reloaded})
+	propertiesLoad([]interface{}{reloaded, makeTestStream(t, text)})
+	for key, want := range map[string]string{"a=b": " leading space", "path": "c:\\temp", "é": "ü"} {
+		ret := getTestProperty(reloaded, key)
+		if object.IsNull(ret) || object.GoStringFromStringObject(ret.(*object.Object)) != want {
+			t.Errorf("Key %q: expected %q after store and load, got %v", key, want, ret)
+		}
+	}
+}
+
+// Properties used as a map, through the methods it inherits from Hashtable
+func TestPropertiesAsMap(t *testing.T) {
+	globals.InitGlobals("test")
+	props := object.MakeEmptyObject()
+	propertiesInit([]interface{}{props})
+	key := object.StringObjectFromGoString("color")
+	red := object.StringObjectFromGoString("red")
+
+	if ret := propertiesPut([]interface{}{props, key, red}); !object.IsNull(ret) {
+		t.Errorf("Properties.put(): expected null for a new key, got %v", ret)
+	}
+	propertiesPut([]interface{}{props, object.StringObjectFromGoString("size"), object.StringObjectFromGoString("large")})
+	if ret := propertiesGet([]interface{}{props, object.StringObjectFromGoString("color")}); object.GoStringFromStringObject(ret.(*object.Object)) != "red" {
+		t.Errorf("Properties.get(): expected \"red\", got %v", ret)
+	}
+	if propertiesContainsKey([]interface{}{props, key}) != types.JavaBoolTrue ||
+		propertiesContainsValue([]interface{}{props, object.StringObjectFromGoString("large")}) != types.JavaBoolTrue {
+		t.Errorf("Properties.containsKey()/containsValue(): expected both to be true")
+	}
+	if str := object.GoStringFromStringObject(propertiesToString([]interface{}{props}).(*object.Object)); str != "{color=red, size=large}" {
+		t.Errorf("Properties.toString(): expected {color=red, size=large}, got %s", str)
+	}
+
+	clone := propertiesClone([]interface{}{props}).(*object.Object)
+	if ret := propertiesRemove([]interface{}{props, key}); object.GoStringFromStringObject(ret.(*object.Object)) != "red" {
+		t.Errorf("Properties.remove(): expected the removed value \"red\", got %v", ret)
+	}
+	if propertiesContainsKey([]interface{}{props, key}) != types.JavaBoolFalse ||
+		propertiesContainsKey([]interface{}{clone, key}) != types.JavaBoolTrue {
+		t.Errorf("Properties.remove(): expected the key to be removed from the original only")
+	}
+
+	// only Strings can be put in the map
+	ret := propertiesPut([]interface{}{props, key, object.MakePrimitiveObject("java/lang/Integer", types.Int, int64(1))})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.UnsupportedOperationException {
+		t.Errorf("Properties.put() of an Integer: expected an UnsupportedOperationException, got %v", ret)
+	}
+
+	propertiesClear([]interface{}{props})
+	if propertiesIsEmpty([]interface{}{props}) != types.JavaBoolTrue {
+		t.Errorf("Properties.clear(): expected the map to be empty")
+	}
+}
+
+// forEach() passes each entry to the action, and the views of the map are trapped
+func TestPropertiesForEachAndTraps(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Util_Properties()
+	props := object.MakeEmptyObject()
+	propertiesInit([]interface{}{props})
+	propertiesSetProperty([]interface{}{props, object.StringObjectFromGoString("b"), object.StringObjectFromGoString("2")})
+	propertiesSetProperty([]interface{}{props, object.StringObjectFromGoString("a"), object.StringObjectFromGoString("1")})
+
+	var visited []string
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, methodName, _ string, args ...any) (any, error) {
+		visited = append(visited, methodName+":"+object.GoStringFromStringObject(args[0].(*object.Object))+
+			"="+object.GoStringFromStringObject(args[1].(*object.Object)))
+		return nil, nil
+	}
+	if ret := propertiesForEach([]interface{}{frames.CreateFrameStack(), props, object.MakeEmptyObject()}); ret != nil {
+		t.Fatalf("Properties.forEach(): unexpected error: %v", ret)
+	}
+	if strings.Join(visited, " ") != "accept:a=1 accept:b=2" {
+		t.Errorf("Properties.forEach(): expected accept(a, 1) then accept(b, 2), got %v", visited)
+	}
+
+	for _, signature := range []string{"keySet()Ljava/util/Set;", "entrySet()Ljava/util/Set;",
+		"stringPropertyNames()Ljava/util/Set;", "propertyNames()Ljava/util/Enumeration;"} {
+		gmeth, ok := MethodSignatures["java/util/Properties."+signature]
+		if !ok || gmeth.ParamSlots != 0 {
+			t.Errorf("Properties.%s: expected a trap, got %v", signature, gmeth)
+			continue
+		}
+		ret := gmeth.GFunction(nil)
+		if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.UnsupportedOperationException {
+			t.Errorf("Properties.%s: expected an UnsupportedOperationException, got %v", signature, ret)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/types"
+	"slices"
+)
+
+// Implementation of a minimal sequential java.util.stream.Stream, for the streams that
+// Jacobin creates in golang, such as the one returned by String.lines() or by the stream()
+// of a collection. Because lambdas are not yet supported, only the stream operations that don't
+// need one are implemented: count(), iterator(), and toArray(); forEach(), whose Consumer
+// must be an object of a class that implements accept(); and collect(), for the collector
+// returned by Collectors.toList(), which gathers the elements into a new ArrayList. The
+// elements of the stream are kept in a reference array in its "value" field. Each stream and
+// iterator is an object of the JDK class for it, so that the interface methods invoked on it
+// resolve to the functions here. The objects have none of the JDK's fields, so the methods
+// of those classes that aren't implemented here are trapped, rather than left to run the
+// JDK's bytecode. close() does nothing, as no stream here has a close handler.
+//
+// Likewise, an IntStream, such as the one returned by Arrays.stream(int[]), keeps its ints in
+// its "value" field, and supports the terminal operations average(), count(), max(), min(),
+// and sum(). The OptionalInt and OptionalDouble they return are objects of the JDK classes,
+// with the fields that the JDK's methods of those classes read.
+
+const (
+	streamClassName         = "java/util/stream/ReferencePipeline$Head"
+	streamIteratorClassName = "java/util/Spliterators$1Adapter"
+	intStreamClassName      = "java/util/stream/IntPipeline$Head"
+	toListCollectorName     = "java/util/stream/Collectors$CollectorImpl"
+)
+
+func Load_Util_Stream() {
+
+	// the default method of the Collection interface, and the lists of java.util.Collections,
+	// some of which override it in the JDK
+	for _, className := range []string{"java/util/Collection", collectionsEmptyList, collectionsSingletonList,
+		collectionsCopiesList, collectionsUnmodifiableList} {
+
+		MethodSignatures[className+".stream()Ljava/util/stream/Stream;"] =
+			GMeth{
+				ParamSlots:   0,
+				GFunction:    collectionStream,
+				NeedsContext: true,
+			}
+	}
+
+	MethodSignatures["java/util/stream/Collectors.toList()Ljava/util/stream/Collector;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  collectorsToList,
+		}
+
+	MethodSignatures[streamClassName+".collect(Ljava/util/stream/Collector;)Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 1,
+			GFunction:  streamCollect,
+		}
+
+	MethodSignatures[streamClassName+".count()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  streamCount,
+		}
+
+	MethodSignatures[streamClassName+".forEach(Ljava/util/function/Consumer;)V"] =
+		GMeth{
+			ParamSlots:   1,
+			GFunction:    streamForEach,
+			NeedsContext: true,
+		}
+
+	MethodSignatures[streamClassName+".iterator()Ljava/util/Iterator;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  streamIterator,
+		}
+
+	MethodSignatures[streamClassName+".toArray()[Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  streamToArray,
+		}
+
+	MethodSignatures[intStreamClassName+".average()Ljava/util/OptionalDouble;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  intStreamAverage,
+		}
+
+	MethodSignatures[intStreamClassName+".count()J"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  intStreamCount,
+		}
+
+	MethodSignatures[intStreamClassName+".max()Ljava/util/OptionalInt;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  intStreamMax,
+		}
+
+	MethodSignatures[intStreamClassName+".min()Ljava/util/OptionalInt;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  intStreamMin,
+		}
+
+	MethodSignatures[intStreamClassName+".sum()I"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  intStreamSum,
+		}
+
+	MethodSignatures[streamIteratorClassName+".hasNext()Z"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  streamIteratorHasNext,
+		}
+
+	MethodSignatures[streamIteratorClassName+".next()Ljava/lang/Object;"] =
+		GMeth{
+			ParamSlots: 0,
+			GFunction:  streamIteratorNext,
+		}
+
+	for _, className := range []string{streamClassName, intStreamClassName} {
+		MethodSignatures[className+".close()V"] =
+			GMeth{
+				ParamSlots: 0,
+				GFunction:  justReturn,
+			}
+
+		trapMethods(className,
+			"isParallel()Z",
+			"onClose(Ljava/lang/Runnable;)Ljava/util/stream/BaseStream;",
+			"unordered()Ljava/util/stream/BaseStream;",
+		)
+	}
+
+	trapMethods(streamClassName,
+		"allMatch(Ljava/util/function/Predicate;)Z",
+		"anyMatch(Ljava/util/function/Predicate;)Z",
+		"collect(Ljava/util/function/Supplier;Ljava/util/function/BiConsumer;Ljava/util/function/BiConsumer;)Ljava/lang/Object;",
+		"distinct()Ljava/util/stream/Stream;",
+		"dropWhile(Ljava/util/function/Predicate;)Ljava/util/stream/Stream;",
+		"filter(Ljava/util/function/Predicate;)Ljava/util/stream/Stream;",
+		"findAny()Ljava/util/Optional;",
+		"findFirst()Ljava/util/Optional;",
+		"flatMap(Ljava/util/function/Function;)Ljava/util/stream/Stream;",
+		"flatMapToDouble(Ljava/util/function/Function;)Ljava/util/stream/DoubleStream;",
+		"flatMapToInt(Ljava/util/function/Function;)Ljava/util/stream/IntStream;",
+		"flatMapToLong(Ljava/util/function/Function;)Ljava/util/stream/LongStream;",
+		"forEachOrdered(Ljava/util/function/Consumer;)V",
+		"limit(J)Ljava/util/stream/Stream;",
+		"map(Ljava/util/function/Function;)Ljava/util/stream/Stream;",
+		"mapMulti(Ljava/util/function/BiConsumer;)Ljava/util/stream/Stream;",
+		"mapToDouble(Ljava/util/function/ToDoubleFunction;)Ljava/util/stream/DoubleStream;",
+		"mapToInt(Ljava/util/function/ToIntFunction;)Ljava/util/stream/IntStream;",
+		"mapToLong(Ljava/util/function/ToLongFunction;)Ljava/util/stream/LongStream;",
+		"max(Ljava/util/Comparator;)Ljava/util/Optional;",
+		"min(Ljava/util/Comparator;)Ljava/util/Optional;",
+		"noneMatch(Ljava/util/function/Predicate;)Z",
+		"parallel()Ljava/util/stream/BaseStream;",
+		"peek(Ljava/util/function/Consumer;)Ljava/util/stream/Stream;",
+		"reduce(Ljava/lang/Object;Ljava/util/function/BiFunction;Ljava/util/function/BinaryOperator;)Ljava/lang/Object;",
+		"reduce(Ljava/lang/Object;Ljava/util/function/BinaryOperator;)Ljava/lang/Object;",
+		"reduce(Ljava/util/function/BinaryOperator;)Ljava/util/Optional;",
+		"sequential()Ljava/util/stream/BaseStream;",
+		"skip(J)Ljava/util/stream/Stream;",
+		"sorted()Ljava/util/stream/Stream;",
+		"sorted(Ljava/util/Comparator;)Ljava/util/stream/Stream;",
+		"spliterator()Ljava/util/Spliterator;",
+		"takeWhile(Ljava/util/function/Predicate;)Ljava/util/stream/Stream;",
+		"toArray(Ljava/util/function/IntFunction;)[Ljava/lang/Object;",
+		"toList()Ljava/util/List;",
+	)
+
+	trapMethods(intStreamClassName,
+		"allMatch(Ljava/util/function/IntPredicate;)Z",
+		"anyMatch(Ljava/util/function/IntPredicate;)Z",
+		"asDoubleStream()Ljava/util/stream/DoubleStream;",
+		"asLongStream()Ljava/util/stream/LongStream;",
+		"boxed()Ljava/util/stream/Stream;",
+		"collect(Ljava/util/function/Supplier;Ljava/util/function/ObjIntConsumer;Ljava/util/function/BiConsumer;)Ljava/lang/Object;",
+		"distinct()Ljava/util/stream/IntStream;",
+		"dropWhile(Ljava/util/function/IntPredicate;)Ljava/util/stream/IntStream;",
+		"filter(Ljava/util/function/IntPredicate;)Ljava/util/stream/IntStream;",
+		"findAny()Ljava/util/OptionalInt;",
+		"findFirst()Ljava/util/OptionalInt;",
+		"flatMap(Ljava/util/function/IntFunction;)Ljava/util/stream/IntStream;",
+		"forEach(Ljava/util/function/IntConsumer;)V",
+		"forEachOrdered(Ljava/util/function/IntConsumer;)V",
+		"iterator()Ljava/util/PrimitiveIterator$OfInt;",
+		"limit(J)Ljava/util/stream/IntStream;",
+		"map(Ljava/util/function/IntUnaryOperator;)Ljava/util/stream/IntStream;",
+		"mapMulti(Ljava/util/stream/IntStream$IntMapMultiConsumer;)Ljava/util/stream/IntStream;",
+		"mapToDouble(Ljava/util/function/IntToDoubleFunction;)Ljava/util/stream/DoubleStream;",
+		"mapToLong(Ljava/util/function/IntToLongFunction;)Ljava/util/stream/LongStream;",
+		"mapToObj(Ljava/util/function/IntFunction;)Ljava/util/stream/Stream;",
+		"noneMatch(Ljava/util/function/IntPredicate;)Z",
+		"parallel()Ljava/util/stream/IntStream;",
+		"peek(Ljava/util/function/IntConsumer;)Ljava/util/stream/IntStream;",
+		"reduce(ILjava/util/function/IntBinaryOperator;)I",
+		"reduce(Ljava/util/function/IntBinaryOperator;)Ljava/util/OptionalInt;",
+		"sequential()Ljava/util/stream/IntStream;",
+		"skip(J)Ljava/util/stream/IntStream;",
+		"sorted()Ljava/util/stream/IntStream;",
+		"spliterator()Ljava/util/Spliterator$OfInt;",
+		"summaryStatistics()Ljava/util/IntSummaryStatistics;",
+		"takeWhile(Ljava/util/function/IntPredicate;)Ljava/util/stream/IntStream;",
+		"toArray()[I",
+	)
+
+	trapMethods(streamIteratorClassName,
+		"forEachRemaining(Ljava/util/function/Consumer;)V",
+	)
+
+	trapMethods(toListCollectorName,
+		"accumulator()Ljava/util/function/BiConsumer;",
+		"characteristics()Ljava/util/Set;",
+		"combiner()Ljava/util/function/BinaryOperator;",
+		"finisher()Ljava/util/function/Function;",
+		"supplier()Ljava/util/function/Supplier;",
+	)
+}
+
+// makeStream returns a stream of the given elements
+func makeStream(elements []*object.Object) *object.Object {
+	className := streamClassName
+	stream := object.MakeEmptyObjectWithClassName(&className)
+	stream.FieldTable["value"] = object.Field{Ftype: types.RefArray + "java/lang/Object", Fvalue: elements}
+	return stream
+}
+
+// "java/util/Collection.stream()Ljava/util/stream/Stream;" The stream holds the elements the
+// collection has when stream() is called, in the order of the collection's toArray() (see
+// collectionElements). params[0] = the frame stack, params[1] = the collection
+func collectionStream(params []interface{}) interface{} {
+	elements, errBlk := collectionElements(params[0].(*list.List), params[1].(*object.Object), "Collection.stream")
+	if errBlk != nil {
+		return errBlk
+	}
+	return makeStream(append([]*object.Object{}, elements...))
+}
+
+// "java/util/stream/Collectors.toList()Ljava/util/stream/Collector;" The collector does no
+// work itself: collect() recognizes it by its class and builds the list.
+func collectorsToList(_ []interface{}) interface{} {
+	className := toListCollectorName
+	return object.MakeEmptyObjectWithClassName(&className)
+}
+
+// "java/util/stream/Stream.collect(Ljava/util/stream/Collector;)Ljava/lang/Object;" returns
+// a new ArrayList of the stream's elements, with the fields the JDK's constructor sets up.
+func streamCollect(params []interface{}) interface{} {
+	if object.IsNull(params[1]) {
+		return getGErrBlk(excNames.NullPointerException, "Stream.collect: collector is null")
+	}
+	collector := params[1].(*object.Object)
+	if object.GoStringFromStringPoolIndex(collector.KlassName) != toListCollectorName {
+		return getGErrBlk(excNames.UnsupportedOperationException,
+			"Stream.collect: only the collector of Collectors.toList() is supported")
+	}
+
+	elements := params[0].(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+	elementType := "java/lang/Object"
+	elementData := object.Make1DimRefArray(&elementType, int64(len(elements)))
+	copy(elementData.FieldTable["value"].Fvalue.([]*object.Object), elements)
+
+	className := arrayListClassName
+	arrayList := object.MakeEmptyObjectWithClassName(&className)
+	arrayList.FieldTable["elementData"] = object.Field{Ftype: types.RefArray + elementType, Fvalue: elementData}
+	arrayList.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(len(elements))}
+	arrayList.FieldTable["modCount"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	return arrayList
+}
+
+// "java/util/stream/Stream.count()J"
+func streamCount(params []interface{}) interface{} {
+	elements := params[0].(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+	return int64(len(elements))
+}
+
+// "java/util/stream/Stream.forEach(Ljava/util/function/Consumer;)V" passes each element, in
+// order, to the consumer's accept(), which is run through globals.FuncInvokeMethod.
+// params[0] = the frame stack, params[1] = the stream, params[2] = the consumer
+func streamForEach(params []interface{}) interface{} {
+	if object.IsNull(params[2]) {
+		return getGErrBlk(excNames.NullPointerException, "Stream.forEach: action is null")
+	}
+	elements := params[1].(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+	for _, element := range elements {
+		_, err := globals.GetGlobalRef().FuncInvokeMethod(params[0].(*list.List), params[2],
+			"accept", "(Ljava/lang/Object;)V", element)
+		if err != nil {
+			errMsg := fmt.Sprintf("Stream.forEach: accept() failed: %s", err.Error())
+			return getGErrBlk(excNames.VirtualMachineError, errMsg)
+		}
+	}
+	return nil
+}
+
+// "java/util/stream/Stream.iterator()Ljava/util/Iterator;" The iterator walks the
+// stream's elements in order, keeping the index of the next one in its "index" field.
+func streamIterator(params []interface{}) interface{} {
+	className := streamIteratorClassName
+	iterator := object.MakeEmptyObjectWithClassName(&className)
+	iterator.FieldTable["value"] = params[0].(*object.Object).FieldTable["value"]
+	iterator.FieldTable["index"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	return iterator
+}
+
+// "java/util/stream/Stream.toArray()[Ljava/lang/Object;"
+func streamToArray(params []interface{}) interface{} {
+	elements := params[0].(*object.Object).FieldTable["value"].Fvalue.([]*object.Object)
+	elementType := "java/lang/Object"
+	array := object.Make1DimRefArray(&elementType, int64(len(elements)))
+	copy(array.FieldTable["value"].Fvalue.([]*object.Object), elements)
+	return array
+}
+
+// "java/util/Iterator.hasNext()Z"
+func streamIteratorHasNext(params []interface{}) interface{} {
+	iterator := params[0].(*object.Object)
+	elements := iterator.FieldTable["value"].Fvalue.([]*object.Object)
+	if iterator.FieldTable["index"].Fvalue.(int64) < int64(len(elements)) {
+		return types.JavaBoolTrue
+	}
+	return types.JavaBoolFalse
+}
+
+// "java/util/Iterator.next()Ljava/lang/Object;"
+func streamIteratorNext(params []interface{}) interface{} {
+	iterator := params[0].(*object.Object)
+	elements := iterator.FieldTable["value"].Fvalue.([]*object.Object)
+	index := iterator.FieldTable["index"].Fvalue.(int64)
+	if index >= int64(len(elements)) {
+		return getGErrBlk(excNames.NoSuchElementException, "Iterator.next: no more elements")
+	}
+	iterator.FieldTable["index"] = object.Field{Ftype: types.Int, Fvalue: index + 1}
+	return elements[index]
+}
+
+// makeIntStream returns an IntStream of the given ints
+func makeIntStream(ints []int64) *object.Object {
+	className := intStreamClassName
+	stream := object.MakeEmptyObjectWithClassName(&className)
+	stream.FieldTable["value"] = object.Field{Ftype: types.IntArray, Fvalue: ints}
+	return stream
+}
+
+func intStreamValues(params []interface{}) []int64 {
+	return params[0].(*object.Object).FieldTable["value"].Fvalue.([]int64)
+}
+
+// makeOptional returns an OptionalInt or OptionalDouble, which is empty if present is false
+func makeOptional(className, valueType string, value interface{}, present bool) *object.Object {
+	optional := object.MakeEmptyObjectWithClassName(&className)
+	optional.FieldTable["isPresent"] = object.Field{Ftype: types.Bool, Fvalue: types.ConvertGoBoolToJavaBool(present)}
+	optional.FieldTable["value"] = object.Field{Ftype: valueType, Fvalue: value}
+	return optional
+}
+
+// "java/util/stream/IntStream.average()Ljava/util/OptionalDouble;"
+func intStreamAverage(params []interface{}) interface{} {
+	ints := intStreamValues(params)
+	if len(ints) == 0 {
+		return makeOptional("java/util/OptionalDouble", types.Double, float64(0), false)
+	}
+	var total int64 // as in the JDK, the ints are summed as a long, so the sum doesn't overflow
+	for _, i := range ints {
+		total += i
+	}
+	return makeOptional("java/util/OptionalDouble", types.Double, float64(total)/float64(len(ints)), true)
+}
+
+// "java/util/stream/IntStream.count()J"
+func intStreamCount(params []interface{}) interface{} {
+	return int64(len(intStreamValues(params)))
+}
+
+// "java/util/stream/IntStream.max()Ljava/util/OptionalInt;"
+func intStreamMax(params []interface{}) interface{} {
+	ints := intStreamValues(params)
+	if len(ints) == 0 {
+		return makeOptional("java/util/OptionalInt", types.Int, int64(0), false)
+	}
+	return makeOptional("java/util/OptionalInt", types.Int, slices.Max(ints), true)
+}
+
+// "java/util/stream/IntStream.min()Ljava/util/OptionalInt;"
+func intStreamMin(params []interface{}) interface{} {
+	ints := intStreamValues(params)
+	if len(ints) == 0 {
+		return makeOptional("java/util/OptionalInt", types.Int, int64(0), false)
+	}
+	return makeOptional("java/util/OptionalInt", types.Int, slices.Min(ints), true)
+}
+
+// "java/util/stream/IntStream.sum()I" As in Java, the sum wraps around if it overflows an int.
+func intStreamSum(params []interface{}) interface{} {
+	var total int32
+	for _, i := range intStreamValues(params) {
+		total += int32(i)
+	}
+	return int64(total)
+}
@@ -0,0 +1,138 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package gfunction
+
+import (
+	"container/list"
+	"jacobin/classloader"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/object"
+	"slices"
+	"testing"
+)
+
+func TestCollectionStreamCount(t *testing.T) {
+	globals.InitGlobals("test")
+
+	stream := collectionStream([]interface{}{frames.CreateFrameStack(), makeTestArrayList(2, "a", "b", "c")}).(*object.Object)
+	if count := streamCount([]interface{}{stream}); count != int64(3) {
+		t.Errorf("Expected a count of 3, got: %v", count)
+	}
+
+	stream = collectionStream([]interface{}{frames.CreateFrameStack(), collectionsEmptyListOf(nil)}).(*object.Object)
+	if count := streamCount([]interface{}{stream}); count != int64(0) {
+		t.Errorf("Expected the stream of an empty list to have a count of 0, got: %v", count)
+	}
+}
+
+// The stream holds the elements the list had when stream() was called.
+func TestCollectionStreamToArray(t *testing.T) {
+	globals.InitGlobals("test")
+
+	arrayList := makeTestArrayList(0, "a", "b", "c")
+	stream := collectionStream([]interface{}{frames.CreateFrameStack(), arrayList}).(*object.Object)
+	arrayListInsertElements(arrayList, 3, []*object.Object{object.StringObjectFromGoString("d")})
+
+	array := streamToArray([]interface{}{stream}).(*object.Object)
+	var strs []string
+	for _, element := range array.FieldTable["value"].Fvalue.([]*object.Object) {
+		strs = append(strs, object.GoStringFromStringObject(element))
+	}
+	if !slices.Equal(strs, []string{"a", "b", "c"}) {
+		t.Errorf("Expected toArray() to return [a b c], got: %v", strs)
+	}
+}
+
+// The stream of a collection other than a list, such as a HashSet, holds its toArray() elements
+func TestCollectionStreamOfSet(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	set := stubToArray("java/util/HashSet", object.StringObjectFromGoString("a"),
+		object.StringObjectFromGoString("b"))
+
+	stream := collectionStream([]interface{}{frames.CreateFrameStack(), set}).(*object.Object)
+	if count := streamCount([]interface{}{stream}); count != int64(2) {
+		t.Errorf("Expected the stream of a set of 2 to have a count of 2, got: %v", count)
+	}
+}
+
+func TestStreamForEach(t *testing.T) {
+	globals.InitGlobals("test")
+	var accepted []string
+	globals.GetGlobalRef().FuncInvokeMethod = func(_ *list.List, _ any, methodName, _ string, args ...any) (any, error) {
+		accepted = append(accepted, methodName+" "+object.GoStringFromStringObject(args[0].(*object.Object)))
+		return nil, nil
+	}
+
+	className := "test/Printer"
+	consumer := object.MakeEmptyObjectWithClassName(&className)
+	stream := collectionStream([]interface{}{frames.CreateFrameStack(), makeTestArrayList(0, "x", "y")})
+	if ret := streamForEach([]interface{}{frames.CreateFrameStack(), stream, consumer}); ret != nil {
+		t.Fatalf("Unexpected error from forEach(): %v", ret)
+	}
+	if !slices.Equal(accepted, []string{"accept x", "accept y"}) {
+		t.Errorf("Expected the consumer to accept x, then y, got: %v", accepted)
+	}
+
+	ret := streamForEach([]interface{}{frames.CreateFrameStack(), stream, object.Null})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.NullPointerException {
+		t.Errorf("Expected a NullPointerException for a null consumer, got: %v", ret)
+	}
+}
+
+func TestStreamCollectToList(t *testing.T) {
+	globals.InitGlobals("test")
+
+	stream := collectionStream([]interface{}{frames.CreateFrameStack(), makeTestArrayList(0, "a", "b")})
+	collected := streamCollect([]interface{}{stream, collectorsToList(nil)}).(*object.Object)
+	if className := object.GoStringFromStringPoolIndex(collected.KlassName); className != arrayListClassName {
+		t.Fatalf("Expected collect() to return an ArrayList, got a %s", className)
+	}
+	if strs := arrayListStrings(t, collected); !slices.Equal(strs, []string{"a", "b"}) {
+		t.Errorf("Expected the collected list to be [a b], got: %v", strs)
+	}
+
+	className := "test/OtherCollector"
+	other := object.MakeEmptyObjectWithClassName(&className)
+	ret := streamCollect([]interface{}{stream, other})
+	if errBlk, ok := ret.(*GErrBlk); !ok || errBlk.ExceptionType != excNames.UnsupportedOperationException {
+		t.Errorf("Expected an UnsupportedOperationException for another collector, got: %v", ret)
+	}
+}
+
+// the methods of the JDK's stream classes that aren't implemented are trapped, as the stream
+// objects don't have the fields that the JDK's bytecode for them would use
+func TestStreamTraps(t *testing.T) {
+	globals.InitGlobals("test")
+	Load_Util_Stream()
+
+	for _, test := range []struct {
+		method     string
+		paramSlots int
+	}{
+		{streamClassName + ".map(Ljava/util/function/Function;)Ljava/util/stream/Stream;", 1},
+		{streamClassName + ".limit(J)Ljava/util/stream/Stream;", 2},
+		{intStreamClassName + ".reduce(ILjava/util/function/IntBinaryOperator;)I", 2},
+		{toListCollectorName + ".supplier()Ljava/util/function/Supplier;", 0},
+	} {
+		gmeth, ok := MethodSignatures[test.method]
+		if !ok || gmeth.ParamSlots != test.paramSlots {
+			t.Errorf("Expected %s to be trapped with %d parameter slots, got: %v", test.method, test.paramSlots, gmeth)
+			continue
+		}
+		if errBlk, ok := gmeth.GFunction(nil).(*GErrBlk); !ok || errBlk.ExceptionType != excNames.UnsupportedOperationException {
+			t.Errorf("Expected %s to throw UnsupportedOperationException", test.method)
+		}
+	}
+
+	if gmeth := MethodSignatures[streamClassName+".close()V"]; gmeth.GFunction == nil || gmeth.GFunction(nil) != nil {
+		t.Errorf("Expected Stream.close() to do nothing")
+	}
+}
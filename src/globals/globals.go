@@ -44,10 +44,13 @@ type Globals struct {
 	AppArgs       []string
 	Options       map[string]Option
 
+	SystemProperties map[string]string // the properties set with -D on the command line
+
 	// ---- classloading items ----
 	MaxJavaVersion    int // the Java version as commonly known, i.e. Java 11
 	MaxJavaVersionRaw int // the Java version as it appears in bytecode i.e., 55 (= Java 11)
 	VerifyLevel       int
+	VerboseClass      bool // -verbose:class: print each class as it is loaded
 
 	// ---- Java Home and Version ----
 	JavaHome    string
@@ -67,7 +70,11 @@ type Globals struct {
 	JacobinBuildData map[string]string
 
 	// ---- special switches ----
-	StrictJDK bool // hew closely to actions and error messages of the JDK
+	StrictJDK         bool // hew closely to actions and error messages of the JDK
+	DeterministicHash bool // sequential identity hash codes and HashMap iteration in hash order
+	DumpObjects       bool // keep track of the objects created and print a summary at shutdown
+	DumpBytecodes     bool // keep track of the opcodes executed and print them at shutdown
+	AllowExec         bool // let the program start OS processes with ProcessBuilder
 
 	// ---- list of addresses of arrays, see jvm/arrays.go for info ----
 	ArrayAddressList *list.List
@@ -97,6 +104,10 @@ type Globals struct {
 	FuncInstantiateClass func(string, *list.List) (any, error)
 	FuncThrowException   func(int, string)
 	FuncFillInStackTrace func([]any) any
+	FuncDumpObjects      func() // prints the -Xdump:objects summary at shutdown
+	FuncDumpBytecodes    func() // prints the -Xdump:bytecodes summary at shutdown
+	FuncInvokeMethod     func(*list.List, any, string, string, ...any) (any, error)
+	FuncInitializeClass  func(string, *list.List) error // runs <clinit>(), if not yet run
 }
 
 // ----- String Pool
@@ -122,6 +133,7 @@ func InitGlobals(progName string) Globals {
 		JavaHome:          "",
 		JavaVersion:       "",
 		Options:           make(map[string]Option),
+		SystemProperties:  make(map[string]string),
 		StartingClass:     "",
 		StartingJar:       "",
 		MaxJavaVersion:    17, // this value and MaxJavaVersionRaw must *always* be in sync
@@ -130,6 +142,10 @@ func InitGlobals(progName string) Globals {
 		ThreadNumber:         0, // first thread will be numbered 1, as increment occurs prior
 		JacobinBuildData:     nil,
 		StrictJDK:            false,
+		DeterministicHash:    false,
+		DumpObjects:          false,
+		DumpBytecodes:        false,
+		AllowExec:            false,
 		ArrayAddressList:     InitArrayAddressList(),
 		JmodBaseBytes:        nil,
 		ErrorGoStack:         "",
@@ -139,6 +155,7 @@ func InitGlobals(progName string) Globals {
 		GoStackShown:         false,
 		FuncInstantiateClass: fakeInstantiateClass,
 		FuncThrowException:   fakeThrowEx,
+		FuncInvokeMethod:     fakeInvokeMethod,
 	}
 
 	// ----- String Pool and other values
@@ -289,6 +306,14 @@ func fakeInstantiateClass(classname string, frameStack *list.List) (any, error)
 	return nil, errors.New(errMsg)
 }
 
+// Fake invocation of a method from a gfunction (see jvm/gfunctionExec.go)
+func fakeInvokeMethod(fs *list.List, objRef any, methodName, methodType string, args ...any) (any, error) {
+	errMsg := fmt.Sprintf("\n*Attempt to access uninitialized InvokeMethod pointer func: method=%s%s\n",
+		methodName, methodType)
+	fmt.Fprint(os.Stderr, errMsg)
+	return nil, errors.New(errMsg)
+}
+
 // Fake ThrowEx() in exceptions.go
 func fakeThrowEx(whichEx int, msg string) {
 	errMsg := fmt.Sprintf("\n*Attempt to access uninitialized ThrowEx pointer func")
@@ -0,0 +1,187 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"io"
+	"jacobin/classloader"
+	"jacobin/frames"
+	"jacobin/gfunction"
+	"jacobin/globals"
+	"jacobin/log"
+	"jacobin/thread"
+	"os"
+	"strings"
+	"testing"
+)
+
+// These tests use the bytes of Hello2.class (see TestHexHello2Class_test.go) with its addTwo()
+// method annotated @Deprecated. As javac does, the annotation adds two attributes to the method:
+// Deprecated and RuntimeVisibleAnnotations. The JVM doesn't use the latter, so the parser must
+// skip it without misaligning the parse of the rest of the class. The attributes' names and
+// the annotation's type, Ljava/lang/Deprecated;, are added to the end of the constant pool.
+
+var DeprecatedHello2Bytes = []byte{
+	0xCA, 0xFE, 0xBA, 0xBE, 0x00, 0x00, 0x00, 0x37, 0x00, 0x2E, 0x07, 0x00, 0x02, 0x01, 0x00, 0x06,
+	0x48, 0x65, 0x6C, 0x6C, 0x6F, 0x32, 0x07, 0x00, 0x04, 0x01, 0x00, 0x10, 0x6A, 0x61, 0x76, 0x61,
+	0x2F, 0x6C, 0x61, 0x6E, 0x67, 0x2F, 0x4F, 0x62, 0x6A, 0x65, 0x63, 0x74, 0x01, 0x00, 0x06, 0x3C,
+	0x69, 0x6E, 0x69, 0x74, 0x3E, 0x01, 0x00, 0x03, 0x28, 0x29, 0x56, 0x01, 0x00, 0x04, 0x43, 0x6F,
+	0x64, 0x65, 0x0A, 0x00, 0x03, 0x00, 0x09, 0x0C, 0x00, 0x05, 0x00, 0x06, 0x01, 0x00, 0x0F, 0x4C,
+	0x69, 0x6E, 0x65, 0x4E, 0x75, 0x6D, 0x62, 0x65, 0x72, 0x54, 0x61, 0x62, 0x6C, 0x65, 0x01, 0x00,
+	0x12, 0x4C, 0x6F, 0x63, 0x61, 0x6C, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6C, 0x65, 0x54, 0x61,
+	0x62, 0x6C, 0x65, 0x01, 0x00, 0x04, 0x74, 0x68, 0x69, 0x73, 0x01, 0x00, 0x08, 0x4C, 0x48, 0x65,
+	0x6C, 0x6C, 0x6F, 0x32, 0x3B, 0x01, 0x00, 0x04, 0x6D, 0x61, 0x69, 0x6E, 0x01, 0x00, 0x16, 0x28,
+	0x5B, 0x4C, 0x6A, 0x61, 0x76, 0x61, 0x2F, 0x6C, 0x61, 0x6E, 0x67, 0x2F, 0x53, 0x74, 0x72, 0x69,
+	0x6E, 0x67, 0x3B, 0x29, 0x56, 0x0A, 0x00, 0x01, 0x00, 0x11, 0x0C, 0x00, 0x12, 0x00, 0x13, 0x01,
+	0x00, 0x06, 0x61, 0x64, 0x64, 0x54, 0x77, 0x6F, 0x01, 0x00, 0x05, 0x28, 0x49, 0x49, 0x29, 0x49,
+	0x09, 0x00, 0x15, 0x00, 0x17, 0x07, 0x00, 0x16, 0x01, 0x00, 0x10, 0x6A, 0x61, 0x76, 0x61, 0x2F,
+	0x6C, 0x61, 0x6E, 0x67, 0x2F, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6D, 0x0C, 0x00, 0x18, 0x00, 0x19,
+	0x01, 0x00, 0x03, 0x6F, 0x75, 0x74, 0x01, 0x00, 0x15, 0x4C, 0x6A, 0x61, 0x76, 0x61, 0x2F, 0x69,
+	0x6F, 0x2F, 0x50, 0x72, 0x69, 0x6E, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6D, 0x3B, 0x0A, 0x00,
+	0x1B, 0x00, 0x1D, 0x07, 0x00, 0x1C, 0x01, 0x00, 0x13, 0x6A, 0x61, 0x76, 0x61, 0x2F, 0x69, 0x6F,
+	0x2F, 0x50, 0x72, 0x69, 0x6E, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6D, 0x0C, 0x00, 0x1E, 0x00,
+	0x1F, 0x01, 0x00, 0x07, 0x70, 0x72, 0x69, 0x6E, 0x74, 0x6C, 0x6E, 0x01, 0x00, 0x04, 0x28, 0x49,
+	0x29, 0x56, 0x01, 0x00, 0x04, 0x61, 0x72, 0x67, 0x73, 0x01, 0x00, 0x13, 0x5B, 0x4C, 0x6A, 0x61,
+	0x76, 0x61, 0x2F, 0x6C, 0x61, 0x6E, 0x67, 0x2F, 0x53, 0x74, 0x72, 0x69, 0x6E, 0x67, 0x3B, 0x01,
+	0x00, 0x01, 0x78, 0x01, 0x00, 0x01, 0x49, 0x01, 0x00, 0x01, 0x69, 0x01, 0x00, 0x0D, 0x53, 0x74,
+	0x61, 0x63, 0x6B, 0x4D, 0x61, 0x70, 0x54, 0x61, 0x62, 0x6C, 0x65, 0x07, 0x00, 0x21, 0x01, 0x00,
+	0x01, 0x6A, 0x01, 0x00, 0x01, 0x6B, 0x01, 0x00, 0x0A, 0x53, 0x6F, 0x75, 0x72, 0x63, 0x65, 0x46,
+	0x69, 0x6C, 0x65, 0x01, 0x00, 0x0B, 0x48, 0x65, 0x6C, 0x6C, 0x6F, 0x32, 0x2E, 0x6A, 0x61, 0x76,
+	0x61, 0x01, 0x00, 0x0A, 0x44, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x65, 0x64, 0x01, 0x00,
+	0x19, 0x52, 0x75, 0x6E, 0x74, 0x69, 0x6D, 0x65, 0x56, 0x69, 0x73, 0x69, 0x62, 0x6C, 0x65, 0x41,
+	0x6E, 0x6E, 0x6F, 0x74, 0x61, 0x74, 0x69, 0x6F, 0x6E, 0x73, 0x01, 0x00, 0x16, 0x4C, 0x6A, 0x61,
+	0x76, 0x61, 0x2F, 0x6C, 0x61, 0x6E, 0x67, 0x2F, 0x44, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x3B, 0x00, 0x20, 0x00, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00,
+	0x00, 0x00, 0x05, 0x00, 0x06, 0x00, 0x01, 0x00, 0x07, 0x00, 0x00, 0x00, 0x2F, 0x00, 0x01, 0x00,
+	0x01, 0x00, 0x00, 0x00, 0x05, 0x2A, 0xB7, 0x00, 0x08, 0xB1, 0x00, 0x00, 0x00, 0x02, 0x00, 0x0A,
+	0x00, 0x00, 0x00, 0x06, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x0B, 0x00, 0x00, 0x00, 0x0C,
+	0x00, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x0C, 0x00, 0x0D, 0x00, 0x00, 0x00, 0x09, 0x00, 0x0E,
+	0x00, 0x0F, 0x00, 0x01, 0x00, 0x07, 0x00, 0x00, 0x00, 0x81, 0x00, 0x03, 0x00, 0x03, 0x00, 0x00,
+	0x00, 0x1E, 0x03, 0x3D, 0xA7, 0x00, 0x15, 0x1C, 0x1C, 0x04, 0x64, 0xB8, 0x00, 0x10, 0x3C, 0xB2,
+	0x00, 0x14, 0x1B, 0xB6, 0x00, 0x1A, 0x84, 0x02, 0x01, 0x1C, 0x10, 0x0A, 0xA1, 0xFF, 0xEB, 0xB1,
+	0x00, 0x00, 0x00, 0x03, 0x00, 0x0A, 0x00, 0x00, 0x00, 0x16, 0x00, 0x05, 0x00, 0x00, 0x00, 0x06,
+	0x00, 0x05, 0x00, 0x07, 0x00, 0x0D, 0x00, 0x08, 0x00, 0x14, 0x00, 0x06, 0x00, 0x1D, 0x00, 0x0A,
+	0x00, 0x0B, 0x00, 0x00, 0x00, 0x20, 0x00, 0x03, 0x00, 0x00, 0x00, 0x1E, 0x00, 0x20, 0x00, 0x21,
+	0x00, 0x00, 0x00, 0x0D, 0x00, 0x0A, 0x00, 0x22, 0x00, 0x23, 0x00, 0x01, 0x00, 0x02, 0x00, 0x1B,
+	0x00, 0x24, 0x00, 0x23, 0x00, 0x02, 0x00, 0x25, 0x00, 0x00, 0x00, 0x0F, 0x00, 0x02, 0xFF, 0x00,
+	0x05, 0x00, 0x03, 0x07, 0x00, 0x26, 0x00, 0x01, 0x00, 0x00, 0x11, 0x00, 0x08, 0x00, 0x12, 0x00,
+	0x13, 0x00, 0x03, 0x00, 0x07, 0x00, 0x00, 0x00, 0x38, 0x00, 0x02, 0x00, 0x02, 0x00, 0x00, 0x00,
+	0x04, 0x1A, 0x1B, 0x60, 0xAC, 0x00, 0x00, 0x00, 0x02, 0x00, 0x0A, 0x00, 0x00, 0x00, 0x06, 0x00,
+	0x01, 0x00, 0x00, 0x00, 0x0D, 0x00, 0x0B, 0x00, 0x00, 0x00, 0x16, 0x00, 0x02, 0x00, 0x00, 0x00,
+	0x04, 0x00, 0x27, 0x00, 0x23, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04, 0x00, 0x28, 0x00, 0x23, 0x00,
+	0x01, 0x00, 0x2B, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2C, 0x00, 0x00, 0x00, 0x06, 0x00, 0x01, 0x00,
+	0x2D, 0x00, 0x00, 0x00, 0x01, 0x00, 0x29, 0x00, 0x00, 0x00, 0x02, 0x00, 0x2A,
+}
+
+// the annotated class loads, and its methods and attributes are parsed as in Hello2
+func TestHexDeprecatedHello2Loads(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	_ = log.SetLogLevel(log.WARNING)
+	classloader.InitMethodArea()
+
+	_, err := classloader.ParseAndPostClass(&classloader.AppCL, "Hello2.class", DeprecatedHello2Bytes)
+	if err != nil {
+		t.Fatalf("Got error from classloader.ParseAndPostClass: %s", err.Error())
+	}
+
+	klass := classloader.MethAreaFetch("Hello2")
+	if klass == nil {
+		t.Fatal("Hello2 was not posted to the method area")
+	}
+	addTwo, ok := klass.Data.MethodTable["addTwo(II)I"]
+	if !ok {
+		t.Fatal("Hello2.addTwo(II)I is not in the method table")
+	}
+	if !addTwo.Deprecated {
+		t.Error("Expected Hello2.addTwo() to be deprecated")
+	}
+	if len(addTwo.Attributes) != 3 || addTwo.Attributes[2].AttrSize != 6 {
+		t.Errorf("Expected Code, Deprecated, and a 6-byte RuntimeVisibleAnnotations attribute, got: %v",
+			addTwo.Attributes)
+	}
+	if len(addTwo.CodeAttr.Code) != 4 {
+		t.Errorf("Expected 4 bytes of code in Hello2.addTwo(), got %d", len(addTwo.CodeAttr.Code))
+	}
+	if klass.Data.SourceFile != "Hello2.java" {
+		t.Errorf("Expected a source file of Hello2.java, got %q", klass.Data.SourceFile)
+	}
+
+	// run the deprecated method: addTwo(7, -8) returns -1 to the calling frame
+	f0 := newFrame(0)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f0)
+	f1 := frames.CreateFrame(addTwo.CodeAttr.MaxStack)
+	f1.Ftype = 'J'
+	f1.Meth = addTwo.CodeAttr.Code
+	f1.Locals = []interface{}{int64(7), int64(-8)}
+	fs.PushFront(f1)
+	_ = runFrame(fs)
+	_ = frames.PopFrame(fs)
+	if ret := pop(fs.Front().Value.(*frames.Frame)); ret != int64(-1) {
+		t.Errorf("Expected Hello2.addTwo(7, -8) to return -1, got: %v", ret)
+	}
+}
+
+// the annotated class runs as Hello2 does
+func TestHexDeprecatedHello2Runs(t *testing.T) {
+
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		t.Skip()
+	}
+
+	// redirect stderr & stdout to capture results
+	normalStderr := os.Stderr
+	rerr, werr, _ := os.Pipe()
+	os.Stderr = werr
+	normalStdout := os.Stdout
+	rout, wout, _ := os.Pipe()
+	os.Stdout = wout
+
+	globals.InitGlobals("test")
+	log.Init()
+	_ = log.SetLogLevel(log.WARNING)
+
+	err := classloader.Init()
+	if err != nil {
+		t.Errorf("classloader.Init returned an error: %s\n", err.Error())
+		return
+	}
+	classloader.LoadBaseClasses()
+
+	eKI := classloader.Klass{
+		Status: 'I', // I = initializing the load
+		Loader: "",
+		Data:   nil,
+	}
+	classloader.MethAreaInsert("Hello2", &eKI)
+	_, err = classloader.ParseAndPostClass(&classloader.BootstrapCL, "Hello2.class", DeprecatedHello2Bytes)
+	if err != nil {
+		t.Errorf("Got error from classloader.ParseAndPostClass: %s", err.Error())
+		return
+	}
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+	mainThread := thread.CreateThread()
+	err = StartExec("Hello2", &mainThread, globals.GetGlobalRef())
+
+	_ = werr.Close()
+	_ = wout.Close()
+	msgStderr, _ := io.ReadAll(rerr)
+	msgStdout, _ := io.ReadAll(rout)
+	os.Stderr = normalStderr
+	os.Stdout = normalStdout
+
+	if err != nil {
+		t.Errorf("Got error from StartExec(): %s", err.Error())
+	}
+	if !strings.Contains(string(msgStdout), "-1") {
+		t.Errorf("Error in output: expected to contain in part '-1', but saw stdout & stderr as follows:\nstdout: %s\nstderr: %s\n",
+			string(msgStdout), string(msgStderr))
+	}
+}
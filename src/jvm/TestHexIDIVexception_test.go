@@ -7,11 +7,13 @@
 package jvm
 
 import (
+	"container/list"
 	"io"
 	"jacobin/classloader"
 	"jacobin/gfunction"
 	"jacobin/globals"
 	"jacobin/log"
+	"jacobin/object"
 	"jacobin/thread"
 	"os"
 	"strings"
@@ -145,3 +147,48 @@ func TestHexIDIVException(t *testing.T) {
 			msgExpected, string(msgStderr))
 	}
 }
+
+// The uncaught exception's stack trace shows the source line of the IDIV, which
+// the LineNumberTable of main() maps to line 6 of ThrowIDIVexception.java
+func TestHexIDIVExceptionLineNumber(t *testing.T) {
+	normalStderr := os.Stderr
+	rerr, werr, _ := os.Pipe()
+	os.Stderr = werr
+
+	normalStdout := os.Stdout
+	_, wout, _ := os.Pipe()
+	os.Stdout = wout
+
+	globals.InitGlobals("testWithoutShutdown")
+	log.Init()
+	_ = log.SetLogLevel(log.WARNING)
+	globPtr = globals.GetGlobalRef()
+	globPtr.FuncInstantiateClass = func(name string, _ *list.List) (any, error) {
+		return object.MakeEmptyObjectWithClassName(&name), nil
+	}
+	globPtr.FuncFillInStackTrace = gfunction.FillInStackTrace
+	globPtr.StrictJDK = true // don't show the golang stack
+
+	classloader.InitMethodArea()
+	classloader.MethAreaInsert("ThrowIDIVexception", &classloader.Klass{Status: 'I'})
+	_, err := classloader.ParseAndPostClass(&classloader.BootstrapCL, "ThrowIDIVexception.class", ThrowIDIVexceptionBytes)
+	if err != nil {
+		t.Fatalf("Got error from classloader.ParseAndPostCLass: %s", err.Error())
+	}
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	mainThread := thread.CreateThread()
+	mainThread.AddThreadToTable(globPtr)
+	_ = StartExec("ThrowIDIVexception", &mainThread, globPtr)
+
+	_ = werr.Close()
+	_ = wout.Close()
+	msgStderr, _ := io.ReadAll(rerr)
+	os.Stderr = normalStderr
+	os.Stdout = normalStdout
+
+	msgExpected := "ThrowIDIVexception.main(ThrowIDIVexception.java:6)"
+	if !strings.Contains(string(msgStderr), msgExpected) {
+		t.Errorf("Expected the stack trace to contain \"%s\", got: \"%s\"", msgExpected, string(msgStderr))
+	}
+}
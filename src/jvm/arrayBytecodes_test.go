@@ -7,6 +7,7 @@
 package jvm
 
 import (
+	"container/list"
 	"io"
 	"jacobin/classloader"
 	"jacobin/frames"
@@ -16,6 +17,7 @@ import (
 	"jacobin/opcodes"
 	"jacobin/statics"
 	"jacobin/stringPool"
+	"jacobin/thread"
 	"jacobin/types"
 	"os"
 	"strings"
@@ -135,6 +137,122 @@ func TestAaloadWithNil(t *testing.T) {
 	}
 }
 
+// AASTORE and AALOAD: a null stored in an Object[] element is read back as null, and an
+// array reference that's object.Null, as ACONST_NULL pushes it, is a null array
+func TestAastoreAndAaloadNull(t *testing.T) {
+	globals.InitGlobals("test")
+	_ = log.SetLogLevel(log.WARNING)
+
+	elementType := "java/lang/Object"
+	array := object.Make1DimRefArray(&elementType, 3)
+	elements := array.FieldTable["value"].Fvalue.([]*object.Object)
+	elements[1] = object.StringObjectFromGoString("replaced")
+
+	f := frames.CreateFrame(4)
+	f.Meth = []byte{opcodes.ALOAD_0, opcodes.ICONST_1, opcodes.ACONST_NULL, opcodes.AASTORE,
+		opcodes.ALOAD_0, opcodes.ICONST_1, opcodes.AALOAD}
+	f.Locals = []interface{}{array}
+	fs := frames.CreateFrameStack()
+	fs.PushFront(f)
+	if err := runFrame(fs); err != nil {
+		t.Fatalf("AASTORE: unexpected error storing null: %s", err.Error())
+	}
+	if elements[1] != object.Null {
+		t.Errorf("AASTORE: expected array[1] to be null, got: %v", elements[1])
+	}
+	if f.TOS != 0 || pop(f) != object.Null {
+		t.Errorf("AALOAD: expected null to be read back from array[1]")
+	}
+
+	g := newFrame(opcodes.AALOAD)
+	push(&g, object.Null)
+	push(&g, int64(0))
+	fs = frames.CreateFrameStack()
+	fs.PushFront(&g)
+	err := runFrame(fs)
+	if err == nil || !strings.Contains(err.Error(), "Invalid (null) reference") {
+		t.Errorf("AALOAD: expected a null reference error for an object.Null array, got: %v", err)
+	}
+}
+
+// AALOAD and AASTORE: when the NullPointerException for a null array is caught, execution
+// resumes at the handler, here: POP (the exception); ICONST_5; RETURN
+func TestAaloadAastoreNullArrayCaught(t *testing.T) {
+	globals.InitGlobals("testWithoutShutdown")
+	log.Init()
+	gl := globals.GetGlobalRef()
+	gl.FuncInstantiateClass = func(name string, _ *list.List) (any, error) {
+		return object.MakeEmptyObjectWithClassName(&name), nil
+	}
+
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() {
+		_ = w.Close()
+		os.Stderr = normalStderr
+	}()
+
+	npeName := "java/lang/NullPointerException"
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{{Type: 0, Slot: 0}, {Type: classloader.ClassRef, Slot: 0}}
+	CP.ClassRefs = []uint32{stringPool.GetStringIndex(&npeName)}
+
+	for _, opcode := range []byte{opcodes.AALOAD, opcodes.AASTORE} {
+		code := []byte{opcode, opcodes.POP, opcodes.ICONST_5, opcodes.RETURN}
+		classloader.MTable = make(map[string]classloader.MTentry)
+		classloader.MTable["test/Caller.call()V"] = classloader.MTentry{
+			Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 3, MaxLocals: 1, Code: code, Cp: &CP,
+				Exceptions: []classloader.CodeException{{StartPc: 0, EndPc: 1, HandlerPc: 1, CatchType: 1}}},
+			MType: 'J',
+		}
+
+		th := thread.CreateThread()
+		th.AddThreadToTable(gl)
+		f := frames.CreateFrame(3)
+		f.ClName, f.MethName, f.MethType, f.CP, f.Meth = "test/Caller", "call", "()V", &CP, code
+		f.Thread = th.ID
+		push(f, object.Null) // the array
+		push(f, int64(0))    // the index
+		if opcode == opcodes.AASTORE {
+			push(f, object.StringObjectFromGoString("value"))
+		}
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(f)
+		th.Stack = fs
+		if err := runFrame(fs); err != nil {
+			t.Errorf("%s: Unexpected error: %s", opcodes.BytecodeNames[opcode], err.Error())
+			continue
+		}
+		if f.OpStack[0] != int64(5) {
+			t.Errorf("%s: Expected the handler to run, got stack=%v", opcodes.BytecodeNames[opcode], f.OpStack)
+		}
+	}
+}
+
+// IF_ACMPEQ and IF_ACMPNE: nil and object.Null are both null, so they're equal
+func TestIfAcmpOfNulls(t *testing.T) {
+	globals.InitGlobals("test")
+	for _, test := range []struct {
+		opcode byte
+		jumps  bool
+	}{{opcodes.IF_ACMPEQ, true}, {opcodes.IF_ACMPNE, false}} {
+		f := newFrame(test.opcode)
+		f.Meth = append(f.Meth, 0x00, 0x04)                     // jump to 4
+		f.Meth = append(f.Meth, opcodes.RETURN, opcodes.RETURN) // or fall through to 3
+		push(&f, nil)
+		push(&f, object.Null)
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f)
+		_ = runFrame(fs)
+		if jumped := f.PC == 4; jumped != test.jumps {
+			t.Errorf("%s of nil and object.Null: expected a jump to be %t, but the PC is %d",
+				opcodes.BytecodeNames[test.opcode], test.jumps, f.PC)
+		}
+	}
+}
+
 // ANEWARRAY: create an array of T_REF.
 // AASTORE: store a value in the array.
 //
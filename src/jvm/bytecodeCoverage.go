@@ -0,0 +1,73 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"io"
+	"jacobin/opcodes"
+	"strings"
+	"sync/atomic"
+)
+
+// The -Xdump:bytecodes option has the interpreter keep track of the opcodes it executes, so
+// that contributors can see which opcodes a program exercises and, more usefully, which
+// ones remain untested. runFrame() counts each opcode as it executes it, and the counts are
+// printed by DumpBytecodeCoverage() when the VM shuts down. ExecutedOpcodes() and
+// ResetBytecodeCoverage() give tests access to the same record.
+
+var opcodeCounts [256]atomic.Uint64
+
+// recordOpcode counts one execution of an opcode. It's called for every instruction when
+// the -Xdump:bytecodes option is in effect.
+func recordOpcode(opcode byte) {
+	opcodeCounts[opcode].Add(1)
+}
+
+// ExecutedOpcodes returns the opcodes that have been executed since the start of the run
+// or the last call to ResetBytecodeCoverage(), in numerical order.
+func ExecutedOpcodes() []byte {
+	var executed []byte
+	for opcode := range opcodeCounts {
+		if opcodeCounts[opcode].Load() > 0 {
+			executed = append(executed, byte(opcode))
+		}
+	}
+	return executed
+}
+
+// ResetBytecodeCoverage clears the record of executed opcodes.
+func ResetBytecodeCoverage() {
+	for opcode := range opcodeCounts {
+		opcodeCounts[opcode].Store(0)
+	}
+}
+
+// DumpBytecodeCoverage prints the number of times each executed opcode was executed,
+// followed by the names of the opcodes that were not executed. The opcodes are those
+// of the JVM specification, 0x00 (NOP) through 0xC9 (JSR_W); the reserved ones aren't counted.
+func DumpBytecodeCoverage(w io.Writer) {
+	var executed int
+	var notExecuted []string
+	for opcode := opcodes.NOP; opcode <= opcodes.JSR_W; opcode++ {
+		if opcodeCounts[opcode].Load() > 0 {
+			executed++
+		} else {
+			notExecuted = append(notExecuted, opcodes.BytecodeNames[opcode])
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "Bytecode coverage: %d of %d opcodes executed\n", executed, opcodes.JSR_W+1)
+	for opcode := opcodes.NOP; opcode <= opcodes.JSR_W; opcode++ {
+		if count := opcodeCounts[opcode].Load(); count > 0 {
+			_, _ = fmt.Fprintf(w, "%10d  %s\n", count, opcodes.BytecodeNames[opcode])
+		}
+	}
+	if len(notExecuted) > 0 {
+		_, _ = fmt.Fprintf(w, "Not executed: %s\n", strings.Join(notExecuted, " "))
+	}
+}
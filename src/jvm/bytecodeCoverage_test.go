@@ -0,0 +1,82 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"bytes"
+	"io"
+	"jacobin/classloader"
+	"jacobin/gfunction"
+	"jacobin/globals"
+	"jacobin/log"
+	"jacobin/opcodes"
+	"jacobin/statics"
+	"jacobin/thread"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// runs Hello2.class, whose main() loops over a call to println(), with -Xdump:bytecodes
+// in effect, and checks the opcodes that were recorded
+func TestBytecodeCoverageOfHello2(t *testing.T) {
+	normalStdout := os.Stdout
+	rout, wout, _ := os.Pipe()
+	os.Stdout = wout
+
+	globals.InitGlobals("testWithoutShutdown")
+	log.Init()
+	_ = log.SetLogLevel(log.WARNING)
+	globPtr = globals.GetGlobalRef()
+	globPtr.DumpBytecodes = true
+	defer func() { globPtr.DumpBytecodes = false }()
+
+	classloader.InitMethodArea()
+	_, err := classloader.ParseAndPostClass(&classloader.BootstrapCL, "Hello2.class", Hello2Bytes)
+	if err != nil {
+		t.Fatalf("Got error from classloader.ParseAndPostClass: %s", err.Error())
+	}
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+	statics.Statics = make(map[string]statics.Static)
+	_ = statics.AddStatic("java/lang/System.out", statics.Static{Type: "GS", Value: os.Stdout})
+
+	ResetBytecodeCoverage()
+	mainThread := thread.CreateThread()
+	mainThread.AddThreadToTable(globPtr)
+	err = StartExec("Hello2", &mainThread, globPtr)
+
+	_ = wout.Close()
+	output, _ := io.ReadAll(rout)
+	os.Stdout = normalStdout
+
+	if err != nil {
+		t.Fatalf("Unexpected error running Hello2: %s, output: %s", err.Error(), string(output))
+	}
+	executed := ExecutedOpcodes()
+	for _, opcode := range []byte{opcodes.GETSTATIC, opcodes.INVOKEVIRTUAL, opcodes.GOTO,
+		opcodes.INVOKESTATIC, opcodes.IF_ICMPLT, opcodes.RETURN} {
+		if !slices.Contains(executed, opcode) {
+			t.Errorf("Expected %s to be among the executed opcodes, got: %v", opcodes.BytecodeNames[opcode], executed)
+		}
+	}
+	if slices.Contains(executed, opcodes.NEW) {
+		t.Errorf("Expected NEW, which Hello2 doesn't use, not to be among the executed opcodes")
+	}
+
+	var dump bytes.Buffer
+	DumpBytecodeCoverage(&dump)
+	if !strings.Contains(dump.String(), "  GOTO\n") || !strings.Contains(dump.String(), "Not executed: NOP ") {
+		t.Errorf("Unexpected bytecode coverage dump: %s", dump.String())
+	}
+
+	ResetBytecodeCoverage()
+	if executed = ExecutedOpcodes(); len(executed) != 0 {
+		t.Errorf("Expected no executed opcodes after ResetBytecodeCoverage(), got: %v", executed)
+	}
+}
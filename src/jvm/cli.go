@@ -91,6 +91,12 @@ func getOptionRootAndArgs(option string) (string, string, error) {
 		return "", "", errors.New("empty option error")
 	}
 
+	// -Dname=value defines a system property, whose name and value can themselves contain
+	// a : or an =, so everything after the -D is the option's arg
+	if strings.HasPrefix(option, "-D") {
+		return "-D", option[2:], nil
+	}
+
 	// if the option has an embedded arg value, it'll come after a : or an =
 	argMarker := strings.Index(option, ":")
 	if argMarker == -1 {
@@ -151,6 +157,10 @@ are passed as the arguments to main class.
 
 where options include:
 	-client       to select the "client" VM
+	-ea -enableassertions
+	              enable assertions
+	-da -disableassertions
+	              disable assertions (the default)
 	-verbose:[class|info|fine|finest]  enable verbose output
                   info, fine, finest are Jacobin-specific options providing
                     increasing amounts of detail. The finest level is used
@@ -164,7 +174,17 @@ where options include:
 				  print product version to the output stream and continue
 
 Jacobin-specific options:
+	-allowExec    let the program start OS processes with ProcessBuilder
+	                (off by default)
+	-deterministicHash
+	              assign object hash codes in sequence and iterate HashMaps in
+	                hash order, so that hash-based collections iterate in the
+	                same order on every run
 	-strictJDK    make user messages conform closely to the JDK's format
+	-Xdump:bytecodes
+	              at shutdown, print which opcodes were executed and which were not
+	-Xdump:objects
+	              at shutdown, print the number of objects created in each class
 	-trace:inst   display instruction-level tracing data to the console`
 
 	_, _ = fmt.Fprintln(outStream, userMessage)
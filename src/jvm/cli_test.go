@@ -10,6 +10,8 @@ import (
 	"io"
 	"jacobin/globals"
 	"jacobin/log"
+	"jacobin/statics"
+	"jacobin/types"
 	"os"
 	"strings"
 	"testing"
@@ -273,6 +275,9 @@ func TestChangeLoggingLevels(t *testing.T) {
 	if log.Level != log.CLASS {
 		t.Error("Setting log level to CLASS via command line failed")
 	}
+	if !global.VerboseClass {
+		t.Error("-verbose:class did not enable tracing of loaded classes")
+	}
 }
 
 func TestInvalidLoggingLevel(t *testing.T) {
@@ -541,3 +546,139 @@ func TestEmptyOptionForEmbeddedArg(t *testing.T) {
 		t.Error("Empty option should fail test for embedded args, but did not.")
 	}
 }
+
+// -ea and -da toggle the global assertion status; the last one on the command line wins
+func TestEnableAndDisableAssertions(t *testing.T) {
+	global := globals.InitGlobals("test")
+	_ = log.SetLogLevel(log.WARNING)
+	statics.Statics = make(map[string]statics.Static)
+	statics.LoadProgramStatics()
+	LoadOptionsTable(global)
+
+	args := []string{"jacobin", "-enableassertions", "main.class"}
+	_ = HandleCli(args, &global)
+	if statics.Statics["main.$assertionsDisabled"].Value != types.JavaBoolFalse {
+		t.Errorf("-enableassertions did not enable assertions, got: %v",
+			statics.Statics["main.$assertionsDisabled"].Value)
+	}
+	if !global.Options["-ea"].Set {
+		t.Errorf("-enableassertions did not mark -ea as set")
+	}
+
+	args = []string{"jacobin", "-ea", "-da", "main.class"}
+	_ = HandleCli(args, &global)
+	if statics.Statics["main.$assertionsDisabled"].Value != types.JavaBoolTrue {
+		t.Errorf("-da following -ea did not disable assertions, got: %v",
+			statics.Statics["main.$assertionsDisabled"].Value)
+	}
+
+	args = []string{"jacobin", "-ea:com.example...", "main.class"}
+	_ = HandleCli(args, &global)
+	if statics.Statics["main.$assertionsDisabled"].Value != types.JavaBoolFalse {
+		t.Errorf("-ea with a package qualifier did not enable assertions, got: %v",
+			statics.Statics["main.$assertionsDisabled"].Value)
+	}
+}
+
+func TestAllowExecOption(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(global)
+	if global.AllowExec {
+		t.Error("starting OS processes should be disabled by default")
+	}
+
+	args := []string{"jacobin", "-allowExec", "Hello.class"}
+	_ = HandleCli(args, &global)
+
+	if !global.AllowExec {
+		t.Error("-allowExec did not enable starting OS processes")
+	}
+	if !global.Options["-allowExec"].Set {
+		t.Error("-allowExec was not marked as set")
+	}
+}
+
+func TestDeterministicHashOption(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(global)
+
+	args := []string{"jacobin", "-deterministicHash", "Hello.class"}
+	_ = HandleCli(args, &global)
+
+	if !global.DeterministicHash {
+		t.Error("-deterministicHash did not enable deterministic hash codes")
+	}
+	if !global.Options["-deterministicHash"].Set {
+		t.Error("-deterministicHash was not marked as set")
+	}
+}
+
+func TestDumpObjectsOption(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(global)
+
+	args := []string{"jacobin", "-Xdump:objects", "Hello.class"}
+	_ = HandleCli(args, &global)
+
+	if !global.DumpObjects {
+		t.Error("-Xdump:objects did not enable the object dump")
+	}
+	if !global.Options["-Xdump"].Set {
+		t.Error("-Xdump was not marked as set")
+	}
+}
+
+func TestDumpBytecodesOption(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(global)
+
+	args := []string{"jacobin", "-Xdump:bytecodes", "Hello.class"}
+	_ = HandleCli(args, &global)
+
+	if !global.DumpBytecodes {
+		t.Error("-Xdump:bytecodes did not enable the bytecode coverage dump")
+	}
+	if global.DumpObjects {
+		t.Error("-Xdump:bytecodes should not enable the object dump")
+	}
+}
+
+func TestDumpOptionInvalidValue(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(global)
+
+	if _, err := dumpOption(1, "heap", &global); err == nil {
+		t.Error("Expected an error for -Xdump:heap, got none")
+	}
+	if global.DumpObjects {
+		t.Error("-Xdump:heap should not enable the object dump")
+	}
+}
+
+// -D options set system properties: the value follows the first =, so it can contain = and :,
+// and a later -D for the same name replaces the earlier value
+func TestDefineSystemPropertyOption(t *testing.T) {
+	global := globals.InitGlobals("test")
+	LoadOptionsTable(global)
+
+	args := []string{"jacobin", "-Dfoo=bar baz", "-Dexpr=a=b", "-Durl=http://jacobin.org",
+		"-Dempty", "-Dtwice=1", "-Dtwice=2", "Hello.class", "-Dapp=arg"}
+	_ = HandleCli(args, &global)
+
+	for name, expected := range map[string]string{"foo": "bar baz", "expr": "a=b",
+		"url": "http://jacobin.org", "empty": "", "twice": "2"} {
+		if value, ok := global.SystemProperties[name]; !ok || value != expected {
+			t.Errorf("Expected -D to set %s to %q, got %q (set: %t)", name, expected, value, ok)
+		}
+	}
+	if _, ok := global.SystemProperties["app"]; ok {
+		t.Error("A -D after the class name is an app arg, but it set a system property")
+	}
+	if len(global.AppArgs) != 1 || global.AppArgs[0] != "-Dapp=arg" {
+		t.Errorf("Expected the app args to be [-Dapp=arg], got %v", global.AppArgs)
+	}
+
+	if _, err := defineSystemProperty(1, "=value", &global); err == nil {
+		t.Error("Expected an error for a -D without a property name, got none")
+	}
+}
@@ -16,11 +16,17 @@ import (
 	"jacobin/frames"
 	"jacobin/gfunction"
 	"jacobin/log"
+	"jacobin/types"
 	"slices"
+	"strings"
 )
 
 var CaughtGfunctionException = errors.New("caugh gfunction exception")
 
+// errGfunctionCallUnwound is returned by runFrame() to invokeMethodFromGfunction() when an
+// exception thrown in the Java method a gfunction called was caught below the gfunction's frame.
+var errGfunctionCallUnwound = errors.New("gfunction call unwound by a caught exception")
+
 // Execution of gfunctions (that is, Java functions ported to golang).
 // As part of JACOBIN-519, this code seeks to replace the previous set of
 // functions (e.g., runGframe() and runGmethod()) with a simpler streamlined
@@ -32,6 +38,12 @@ var CaughtGfunctionException = errors.New("caugh gfunction exception")
 // is a boolean indicating whether a pointer to the object whose method is
 // being called was pushed on to the stack (true) or not (false)
 //
+// The INVOKE bytecodes pop the arguments off the operand stack one slot at a
+// time, ParamSlots of them, so a long or double, which occupies two slots that
+// both hold its value, appears twice in params. For instance, the params of a
+// static gfunction of type (JD)J are: the long, the long, the double, the double.
+// The gfunction reads each such argument from the first of its two entries.
+//
 // Returns an errorBlock if an exception occured, an error if the gfunction
 // returned an error but did not throw an exception, or a value if the
 // gfunction returned a value.
@@ -60,6 +72,21 @@ func runGfunction(mt classloader.MTentry, fs *list.List,
 		slices.Reverse(*params)
 	}
 
+	// if the gfunction needs access to the JVM frame stack, pass a pointer to the
+	// frame stack as the first parameter, ahead of the object reference, if any.
+	if mt.Meth.(gfunction.GMeth).NeedsContext {
+		if params == nil {
+			params = &[]interface{}{fs}
+		} else {
+			*params = append([]interface{}{fs}, *params...)
+		}
+		paramCount += 1
+	}
+
+	// if the gfunction calls a Java method that throws an exception, the handler
+	// might be in this frame or below it. See invokeMethodFromGfunction().
+	callerElement, callerPC := fs.Front(), f.PC
+
 	var ret any
 	// call the function, passing it a pointer to the slice of arguments
 	if paramCount == 0 {
@@ -68,6 +95,12 @@ func runGfunction(mt classloader.MTentry, fs *list.List,
 		ret = mt.Meth.(gfunction.GMeth).GFunction(*params)
 	}
 
+	// if such an exception was caught, the exception has already been handled: the catching
+	// frame is atop the frame stack, pointing to its handler. The gfunction's result is moot.
+	if fs.Front() != callerElement || f.PC != callerPC {
+		return CaughtGfunctionException
+	}
+
 	// if an error occured
 	switch ret.(type) {
 	case *gfunction.GErrBlk:
@@ -104,3 +137,81 @@ func runGfunction(mt classloader.MTentry, fs *list.List,
 	// return value, so return it.
 	return ret
 }
+
+// invokeMethodFromGfunction runs a method on an object, on behalf of a gfunction that needs
+// the result of a method that might be implemented in Java, such as the toString() called by
+// StringBuilder.append(Object). Gfunctions reach it through globals.FuncInvokeMethod. The
+// arguments, if any, must each occupy a single slot (so, no longs or doubles). The method is
+// selected as INVOKEVIRTUAL selects it. A Java method runs in a new frame pushed atop the frame
+// stack, and the frames are executed (as in runThread) until that frame returns. Its return
+// value, which the return bytecode leaves on the gfunction caller's operand stack, is popped
+// off and returned here.
+func invokeMethodFromGfunction(fs *list.List, objRef any, methodName, methodType string, args ...any) (any, error) {
+	f := fs.Front().Value.(*frames.Frame)
+	className := resolveVirtualMethod(types.ObjectClassName, methodName, methodType, objRef)
+	mtEntry, err := classloader.FetchMethodAndCP(className, methodName, methodType)
+	if err != nil || mtEntry.Meth == nil {
+		return nil, fmt.Errorf("invokeMethodFromGfunction: method %s.%s%s not found",
+			className, methodName, methodType)
+	}
+
+	if mtEntry.MType == 'G' {
+		params := []interface{}{objRef}
+		params = append(params, args...)
+		slices.Reverse(params) // runGfunction() expects them as popped off the operand stack
+		ret := runGfunction(mtEntry, fs, className, methodName, methodType, &params, true)
+		if err, ok := ret.(error); ok {
+			return nil, err
+		}
+		return ret, nil
+	}
+
+	m := mtEntry.Meth.(classloader.JmEntry)
+	push(f, objRef) // createAndInitNewFrame() pops the objRef and args into the new frame's locals
+	for _, arg := range args {
+		push(f, arg)
+	}
+	fram, err := createAndInitNewFrame(className, methodName, methodType, &m, true, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// if the method throws an exception that is caught in a frame below fram, the frames up
+	// to the catching frame are removed, and the catching frame (as it counts fram in its
+	// UnwoundGfunctionCalls) makes runFrame() return errGfunctionCallUnwound. The gfunction's
+	// call is then abandoned, and runGfunction() returns CaughtGfunctionException.
+	depth := fs.Len()
+	fram.GfunctionCall = true
+	fs.PushFront(fram)
+	for fs.Len() > depth {
+		err = runFrame(fs)
+		if errors.Is(err, errGfunctionCallUnwound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		top := fs.Front().Value.(*frames.Frame)
+		frames.ReleaseMonitor(top)
+		fs.Remove(fs.Front())
+		if top == fram {
+			if strings.HasSuffix(methodType, "V") {
+				return nil, nil
+			}
+			ret := pop(f)
+			if strings.HasSuffix(methodType, "D") || strings.HasSuffix(methodType, "J") {
+				pop(f) // longs and doubles occupy two slots
+			}
+			return ret, nil
+		}
+	}
+
+	if fs.Len() > 0 {
+		catchFrame := fs.Front().Value.(*frames.Frame)
+		if catchFrame.UnwoundGfunctionCalls > 0 {
+			catchFrame.UnwoundGfunctionCalls--
+		}
+	}
+	return nil, fmt.Errorf("invokeMethodFromGfunction: %s.%s%s was unwound by an exception",
+		className, methodName, methodType)
+}
@@ -13,9 +13,12 @@ import (
 	"jacobin/gfunction"
 	"jacobin/globals"
 	"jacobin/log"
+	"jacobin/object"
 	"jacobin/opcodes"
 	"jacobin/stringPool"
+	"jacobin/types"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -216,3 +219,394 @@ func TestGfuncINVOKEVIRTUALwith1stringArgtemplate(t *testing.T) {
 		t.Errorf("gfunctionExec: Did not get expected msg, got: %s", outMsg)
 	}
 }
+
+// An enum's values() method calls clone() on its $VALUES array. The method ref names the
+// array class, [Ltest/Color;, which INVOKEVIRTUAL must resolve to java.lang.Object.clone()
+func TestGfunctionExecArrayClone(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+
+	arrayClassName := "[Ltest/Color;"
+	CP := classloader.CPool{}
+	CP.CpIndex = make([]classloader.CpEntry, 10)
+	CP.CpIndex[0] = classloader.CpEntry{Type: 0, Slot: 0}
+	CP.CpIndex[1] = classloader.CpEntry{Type: classloader.MethodRef, Slot: 0}
+	CP.CpIndex[2] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 0}
+	CP.CpIndex[3] = classloader.CpEntry{Type: classloader.NameAndType, Slot: 0}
+	CP.CpIndex[4] = classloader.CpEntry{Type: classloader.UTF8, Slot: 0}
+	CP.CpIndex[5] = classloader.CpEntry{Type: classloader.UTF8, Slot: 1}
+
+	CP.MethodRefs = []classloader.MethodRefEntry{{ClassIndex: 2, NameAndType: 3}}
+	CP.ClassRefs = append(CP.ClassRefs, stringPool.GetStringIndex(&arrayClassName))
+	CP.Utf8Refs = append(CP.Utf8Refs, "clone", "()Ljava/lang/Object;")
+	CP.NameAndTypes = append(CP.NameAndTypes, classloader.NameAndTypeEntry{NameIndex: 4, DescIndex: 5})
+
+	enumClass := "test/Color"
+	values := object.Make1DimRefArray(&enumClass, 2)
+	elements := values.FieldTable["value"].Fvalue.([]*object.Object)
+	elements[0] = object.MakeEmptyObjectWithClassName(&enumClass)
+	elements[1] = object.MakeEmptyObjectWithClassName(&enumClass)
+
+	f := newFrame(opcodes.INVOKEVIRTUAL)
+	f.Meth = append(f.Meth, 0x00)
+	f.Meth = append(f.Meth, 0x01) // Go to method referred to in 0x0001 of the CP
+	f.CP = &CP
+	push(&f, values)
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	if err := runFrame(fs); err != nil {
+		t.Fatalf("TestGfunctionExecArrayClone: unexpected error: %s", err.Error())
+	}
+
+	clone, ok := pop(&f).(*object.Object)
+	if !ok || clone == values {
+		t.Fatalf("TestGfunctionExecArrayClone: expected a new array, got: %v", clone)
+	}
+	cloneElements := clone.FieldTable["value"].Fvalue.([]*object.Object)
+	if len(cloneElements) != 2 || cloneElements[0] != elements[0] || cloneElements[1] != elements[1] {
+		t.Errorf("TestGfunctionExecArrayClone: expected the clone to hold the same enum constants")
+	}
+	cloneElements[0] = nil
+	if elements[0] == nil {
+		t.Errorf("TestGfunctionExecArrayClone: changing the clone changed $VALUES")
+	}
+}
+
+// StringBuilder.append(Object) must run the object's toString(), here a Java method that
+// returns "X", and append "null" for a null object.
+func TestGfunctionExecAppendObjectCallsToString(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	globals.GetGlobalRef().FuncInvokeMethod = invokeMethodFromGfunction
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+
+	// test/Thing's toString() is: LDC "X"; ARETURN
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{
+		{Type: 0, Slot: 0},
+		{Type: classloader.StringConst, Slot: 2},
+		{Type: classloader.UTF8, Slot: 0},
+	}
+	CP.Utf8Refs = []string{"X"}
+	thingClass := "test/Thing"
+	makeTestClass(thingClass, types.ObjectClassName, map[string]int{"toString()Ljava/lang/String;": 0x0001})
+	classloader.MTable[thingClass+".toString()Ljava/lang/String;"] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 1, MaxLocals: 1,
+			Code: []byte{opcodes.LDC, 0x01, opcodes.ARETURN}, Cp: &CP},
+		MType: 'J',
+	}
+
+	sbClass := "java/lang/StringBuilder"
+	sb := object.MakeEmptyObjectWithClassName(&sbClass)
+	sb.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: []byte{}}
+	sb.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+
+	f := frames.CreateFrame(4)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(f)
+
+	methodType := "(Ljava/lang/Object;)Ljava/lang/StringBuilder;"
+	mt := classloader.MTable[sbClass+".append"+methodType]
+	for _, arg := range []*object.Object{object.MakeEmptyObjectWithClassName(&thingClass), object.Null} {
+		params := []interface{}{arg, sb} // as popped off the operand stack
+		ret := runGfunction(mt, fs, sbClass, "append", methodType, &params, true)
+		if ret != sb {
+			t.Fatalf("Expected append() to return the StringBuilder, got: %v", ret)
+		}
+	}
+
+	if str, _ := object.CharSequenceToGoString(sb); str != "Xnull" {
+		t.Errorf("Expected the StringBuilder to hold \"Xnull\", got: %q", str)
+	}
+	if fs.Len() != 1 || f.TOS != -1 {
+		t.Errorf("Expected only the calling frame, with an empty stack, to remain: frames=%d, TOS=%d",
+			fs.Len(), f.TOS)
+	}
+}
+
+// if the toString() that StringBuilder.append(Object) calls throws an exception, and the caller
+// of the method that called append() catches it, the frames of toString() and of that method are
+// unwound, and the catching frame resumes at its handler with the exception atop its stack.
+func TestGfunctionExecAppendObjectToStringThrows(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	globals.GetGlobalRef().FuncInvokeMethod = invokeMethodFromGfunction
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() { os.Stderr = normalStderr }()
+
+	// test/Boom's toString() is: ALOAD_0; ATHROW
+	boomClass := "test/Boom"
+	makeTestClass(boomClass, "java/lang/Exception", map[string]int{"toString()Ljava/lang/String;": 0x0001})
+	classloader.MTable[boomClass+".toString()Ljava/lang/String;"] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 1, MaxLocals: 1,
+			Code: []byte{opcodes.ALOAD_0, opcodes.ATHROW}, Cp: &classloader.CPool{}},
+		MType: 'J',
+	}
+
+	// test/Caller.run() calls append(); test/Caller.outer() calls run() and catches any Exception
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{
+		{Type: 0, Slot: 0},
+		{Type: classloader.ClassRef, Slot: 0},
+	}
+	excName := "java/lang/Exception"
+	CP.ClassRefs = []uint32{stringPool.GetStringIndex(&excName)}
+
+	callerClass := "test/Caller"
+	code := []byte{opcodes.NOP, opcodes.NOP, opcodes.NOP, opcodes.POP, opcodes.RETURN}
+	classloader.MTable[callerClass+".run()V"] = classloader.MTentry{
+		Meth:  classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 2, MaxLocals: 1, Code: code, Cp: &CP},
+		MType: 'J',
+	}
+	classloader.MTable[callerClass+".outer()V"] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 2, MaxLocals: 1, Code: code, Cp: &CP,
+			Exceptions: []classloader.CodeException{{StartPc: 0, EndPc: 3, HandlerPc: 3, CatchType: 1}}},
+		MType: 'J',
+	}
+
+	fs := frames.CreateFrameStack()
+	outer := frames.CreateFrame(2)
+	outer.ClName, outer.MethName, outer.MethType, outer.CP, outer.PC = callerClass, "outer", "()V", &CP, 1
+	fs.PushFront(outer)
+	f := frames.CreateFrame(2)
+	f.ClName, f.MethName, f.MethType, f.CP, f.PC = callerClass, "run", "()V", &CP, 1
+	fs.PushFront(f)
+
+	sbClass := "java/lang/StringBuilder"
+	sb := object.MakeEmptyObjectWithClassName(&sbClass)
+	sb.FieldTable["value"] = object.Field{Ftype: types.ByteArray, Fvalue: []byte{}}
+	sb.FieldTable["count"] = object.Field{Ftype: types.Int, Fvalue: int64(0)}
+	boom := object.MakeEmptyObjectWithClassName(&boomClass)
+
+	methodType := "(Ljava/lang/Object;)Ljava/lang/StringBuilder;"
+	mt := classloader.MTable[sbClass+".append"+methodType]
+	params := []interface{}{boom, sb} // as popped off the operand stack
+	ret := runGfunction(mt, fs, sbClass, "append", methodType, &params, true)
+	if ret != CaughtGfunctionException {
+		t.Fatalf("Expected CaughtGfunctionException, got: %v", ret)
+	}
+
+	if fs.Len() != 1 || fs.Front().Value.(*frames.Frame) != outer {
+		t.Fatalf("Expected only the catching frame to remain, got %d frames", fs.Len())
+	}
+	if outer.PC != 3 || outer.TOS != 0 || outer.OpStack[0] != boom {
+		t.Errorf("Expected the catching frame at its handler with the exception on its stack: PC=%d, TOS=%d",
+			outer.PC, outer.TOS)
+	}
+	if outer.UnwoundGfunctionCalls != 0 {
+		t.Errorf("Expected no unwound gfunction calls to remain, got: %d", outer.UnwoundGfunctionCalls)
+	}
+
+	// the catching frame then runs its handler (POP; RETURN) normally
+	if err := runFrame(fs); err != nil {
+		t.Errorf("Expected the handler to run, got: %v", err)
+	}
+}
+
+// String.format() must format a %s argument that's not a String as the string returned by its
+// toString(), here a Java method, and a null argument as "null"
+func TestGfunctionExecFormatCallsToString(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	globals.GetGlobalRef().FuncInvokeMethod = invokeMethodFromGfunction
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+
+	// test/Custom's toString() is: LDC "CUSTOM"; ARETURN
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{
+		{Type: 0, Slot: 0},
+		{Type: classloader.StringConst, Slot: 2},
+		{Type: classloader.UTF8, Slot: 0},
+	}
+	CP.Utf8Refs = []string{"CUSTOM"}
+	customClass := "test/Custom"
+	makeTestClass(customClass, types.ObjectClassName, map[string]int{"toString()Ljava/lang/String;": 0x0001})
+	classloader.MTable[customClass+".toString()Ljava/lang/String;"] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 1, MaxLocals: 1,
+			Code: []byte{opcodes.LDC, 0x01, opcodes.ARETURN}, Cp: &CP},
+		MType: 'J',
+	}
+
+	elementType := "java/lang/Object"
+	args := object.Make1DimRefArray(&elementType, 2)
+	args.FieldTable["value"].Fvalue.([]*object.Object)[0] = object.MakeEmptyObjectWithClassName(&customClass)
+	args.FieldTable["value"].Fvalue.([]*object.Object)[1] = object.Null
+
+	f := frames.CreateFrame(4)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(f)
+
+	stringClass := types.StringClassName
+	methodType := "(Ljava/lang/String;[Ljava/lang/Object;)Ljava/lang/String;"
+	mt := classloader.MTable[stringClass+".format"+methodType]
+	params := []interface{}{args, object.StringObjectFromGoString("[%s] [%6s]")} // as popped off the operand stack
+	ret := runGfunction(mt, fs, stringClass, "format", methodType, &params, false)
+
+	str, ok := ret.(*object.Object)
+	if !ok {
+		t.Fatalf("Expected String.format() to return a String, got: %v", ret)
+	}
+	if object.GoStringFromStringObject(str) != "[CUSTOM] [  null]" {
+		t.Errorf("Expected \"[CUSTOM] [  null]\", got: %q", object.GoStringFromStringObject(str))
+	}
+	if fs.Len() != 1 || f.TOS != -1 {
+		t.Errorf("Expected only the calling frame, with an empty stack, to remain: frames=%d, TOS=%d",
+			fs.Len(), f.TOS)
+	}
+}
+
+// println(Object) of a plain Object prints its default toString(): the class name, @, and
+// the identity hash code in hex
+func TestGfunctionExecPrintlnPlainObject(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	globals.GetGlobalRef().FuncInvokeMethod = invokeMethodFromGfunction
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+	makeTestClass(types.ObjectClassName, "", map[string]int{})
+
+	f := frames.CreateFrame(4)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(f)
+
+	r, w, _ := os.Pipe()
+	objectClass := types.ObjectClassName
+	methodType := "(Ljava/lang/Object;)V"
+	mt := classloader.MTable["java/io/PrintStream.println"+methodType]
+	params := []interface{}{object.MakeEmptyObjectWithClassName(&objectClass), w} // as popped off the operand stack
+	ret := runGfunction(mt, fs, "java/io/PrintStream", "println", methodType, &params, false)
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+
+	if ret != nil {
+		t.Fatalf("Unexpected return from println(Object): %v", ret)
+	}
+	if !regexp.MustCompile(`^java\.lang\.Object@[0-9a-f]+\n$`).Match(out) {
+		t.Errorf("Expected java.lang.Object@<hex hash code>, got: %q", string(out))
+	}
+}
+
+// Collections.max(Collection, Comparator) must run the comparator's compare(), here a Java
+// method that orders Integers in reverse, passing it the two elements being compared.
+func TestGfunctionExecMaxWithJavaComparator(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	globals.GetGlobalRef().FuncInvokeMethod = invokeMethodFromGfunction
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+
+	// test/Reverse's compare(a, b) is: ALOAD_2; GETFIELD value; ALOAD_1; GETFIELD value; ISUB; IRETURN
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{
+		{Type: 0, Slot: 0},
+		{Type: classloader.FieldRef, Slot: 0},
+		{Type: classloader.NameAndType, Slot: 0},
+		{Type: classloader.UTF8, Slot: 0},
+		{Type: classloader.UTF8, Slot: 1},
+	}
+	CP.FieldRefs = []classloader.FieldRefEntry{{ClassIndex: 0, NameAndType: 2}}
+	CP.NameAndTypes = []classloader.NameAndTypeEntry{{NameIndex: 3, DescIndex: 4}}
+	CP.Utf8Refs = []string{"value", types.Int}
+	compareType := "(Ljava/lang/Object;Ljava/lang/Object;)I"
+	reverseClass := "test/Reverse"
+	makeTestClass(reverseClass, types.ObjectClassName, map[string]int{"compare" + compareType: 0x0001})
+	classloader.MTable[reverseClass+".compare"+compareType] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 2, MaxLocals: 3, Cp: &CP,
+			Code: []byte{opcodes.ALOAD_2, opcodes.GETFIELD, 0x00, 0x01, opcodes.ALOAD_1,
+				opcodes.GETFIELD, 0x00, 0x01, opcodes.ISUB, opcodes.IRETURN}},
+		MType: 'J',
+	}
+
+	elementType := "java/lang/Object"
+	elementData := object.Make1DimRefArray(&elementType, 3)
+	for i, value := range []int64{5, -3, 9} {
+		elementData.FieldTable["value"].Fvalue.([]*object.Object)[i] =
+			object.MakePrimitiveObject("java/lang/Integer", types.Int, value)
+	}
+	listClass := "java/util/ArrayList"
+	list := object.MakeEmptyObjectWithClassName(&listClass)
+	list.FieldTable["elementData"] = object.Field{Ftype: types.RefArray + elementType, Fvalue: elementData}
+	list.FieldTable["size"] = object.Field{Ftype: types.Int, Fvalue: int64(3)}
+
+	f := frames.CreateFrame(4)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(f)
+
+	methodType := "(Ljava/util/Collection;Ljava/util/Comparator;)Ljava/lang/Object;"
+	mt := classloader.MTable["java/util/Collections.max"+methodType]
+	params := []interface{}{object.MakeEmptyObjectWithClassName(&reverseClass), list} // as popped off the operand stack
+	ret := runGfunction(mt, fs, "java/util/Collections", "max", methodType, &params, false)
+
+	max, ok := ret.(*object.Object)
+	if !ok || max.FieldTable["value"].Fvalue != int64(-3) {
+		t.Fatalf("Expected max() with a reversing comparator to return -3, got: %v", ret)
+	}
+	if fs.Len() != 1 || f.TOS != -1 {
+		t.Errorf("Expected only the calling frame, with an empty stack, to remain: frames=%d, TOS=%d",
+			fs.Len(), f.TOS)
+	}
+}
+
+// A trapped method throws an UnsupportedOperationException whose message names the method.
+func TestGfunctionExecTrapNamesMethod(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	className := "java/io/DefaultFileSystem"
+	signature := className + ".getFileSystem()Ljava/io/FileSystem;"
+	mt := classloader.MTable[signature]
+	f := frames.CreateFrame(2)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(f)
+	params := []interface{}{}
+	ret := runGfunction(mt, fs, className, "getFileSystem", "()Ljava/io/FileSystem;", &params, true)
+
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stderr = normalStderr
+
+	err, ok := ret.(error)
+	if !ok {
+		t.Fatalf("Expected DefaultFileSystem.getFileSystem() to be trapped, got: %v", ret)
+	}
+	if !strings.Contains(err.Error(), signature) {
+		t.Errorf("Expected the trap's message to contain the method's signature, got: %s", err.Error())
+	}
+	if !strings.Contains(string(out), "java.lang.UnsupportedOperationException") ||
+		!strings.Contains(string(out), signature) {
+		t.Errorf("Expected an UnsupportedOperationException naming the method, got: %s", string(out))
+	}
+}
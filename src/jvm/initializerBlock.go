@@ -21,59 +21,77 @@ import (
 // Java compiler into a method called <clinit>, which must be run at class instantiation--that is,
 // before any constructor. Because that code might well call other methods, it will need to be run
 // just like a regular method with stack frames and depending on the interpreter in run.go
-// In addition, we have to make sure that the initialization blocks of superclasses have been
-// previously executed.
-func runInitializationBlock(k *classloader.Klass, superClasses []string, fs *list.List) error {
-	if superClasses == nil || len(superClasses) == 0 {
-		// show we're running <clinit>. This prevents circularity errors.
-		k.Data.ClInit = types.ClInitInProgress
-
-		// if no superclasses were previously looked up
-		// get list of the superclasses up to but not including java.lang.Object
-		var superclasses []string
-
-		// put the present class at the bottom of the list of superclasses,
-		// because we'll need to run its clinit() code, if any
-		superclasses = append(superclasses, k.Data.Name)
-
-		superclass := *stringPool.GetStringPointer(k.Data.SuperclassIndex)
-		for {
-			if superclass == types.ObjectClassName {
-				break
-			}
-
-			err := loadThisClass(superclass) // load the superclass
-			if err != nil {                  // error message will have been displayed
-				return err
-			}
+// In addition, per the JVMS (5.5), a class's superclasses must be initialized before the class
+// itself, from the top of the hierarchy down, so that a class's <clinit> can rely on the statics
+// set by the <clinit> of its superclasses. A class that has no <clinit> of its own still has its
+// superclasses initialized.
+func runInitializationBlock(k *classloader.Klass, fs *list.List) error {
+	// gather the class and its superclasses, up to but not including java.lang.Object,
+	// that have not been initialized. The class is at the bottom of the list.
+	var classes []*classloader.Klass
+	for klass := k; ; {
+		if klass.Data.ClInit == types.ClInitNotRun || klass.Data.ClInit == types.NoClinit {
+			classes = append(classes, klass)
+		}
 
-			// load only superclasses that have a clInit block that has not been run
-			loadedSuperclass := classloader.MethAreaFetch(superclass)
-			if loadedSuperclass.Data.ClInit == types.ClInitNotRun {
-				superclasses = append(superclasses, superclass)
-			}
+		superclass := *stringPool.GetStringPointer(klass.Data.SuperclassIndex)
+		if superclass == types.ObjectClassName || superclass == "" {
+			break
+		}
+		err := loadThisClass(superclass) // load the superclass
+		if err != nil {                  // error message will have been displayed
+			return err
+		}
+		klass = classloader.MethAreaFetch(superclass)
+	}
 
-			// now loop to see whether this superclass has a superclass
-			superclass = *stringPool.GetStringPointer(loadedSuperclass.Data.SuperclassIndex)
+	// show that these classes are being initialized. This prevents circularity errors.
+	for _, klass := range classes {
+		if klass.Data.ClInit == types.ClInitNotRun {
+			klass.Data.ClInit = types.ClInitInProgress
 		}
-		superClasses = superclasses
 	}
 
-	// now execute any encountered <clinit> code in this class
-	for i := len(superClasses) - 1; i >= 0; i-- {
-		className := superClasses[i]
-		me, err := classloader.FetchMethodAndCP(className, "<clinit>", "()V")
+	// now execute any encountered <clinit> code, starting with the topmost superclass
+	for i := len(classes) - 1; i >= 0; i-- {
+		klass := classes[i]
+		if klass.Data.ClInit == types.NoClinit { // nothing to run, other than the superclasses'
+			klass.Data.ClInit = types.ClInitRun
+			continue
+		}
+		me, err := classloader.FetchMethodAndCP(klass.Data.Name, "<clinit>", "()V")
 		if err == nil {
 			switch me.MType {
 			case 'J': // it's a Java initializer (the most common case)
-				err = runJavaInitializer(me.Meth, k, fs)
+				err = runJavaInitializer(me.Meth, klass, fs)
 			case 'G': // it's a golang implementation of the initializer
-				err = runNativeInitializer(me, k, fs)
+				err = runNativeInitializer(me, klass, fs)
 			}
 			if err != nil {
 				return err
 			}
-		} // if no <clinit> method, then skip that superclass
+		}
+		klass.Data.ClInit = types.ClInitRun
+	}
+	return nil
+}
+
+// needsInitialization reports whether a class, or one of its superclasses, might not yet have
+// been initialized, and so runInitializationBlock() should be called for it.
+func needsInitialization(k *classloader.Klass) bool {
+	return k.Data.ClInit == types.ClInitNotRun || k.Data.ClInit == types.NoClinit
+}
+
+// initializeClassFromGfunction runs the initialization blocks of a loaded class, and those of its
+// superclasses, if they have not yet been run. Gfunctions, such as Class.forName(), reach it
+// through globals.FuncInitializeClass.
+func initializeClassFromGfunction(className string, fs *list.List) error {
+	k := classloader.MethAreaFetch(className)
+	if k == nil {
+		return fmt.Errorf("initializeClassFromGfunction: class %s is not loaded", className)
+	}
+	if needsInitialization(k) {
+		return runInitializationBlock(k, fs)
 	}
 	return nil
 }
@@ -90,6 +108,7 @@ func runJavaInitializer(m classloader.MData, k *classloader.Klass, fs *list.List
 	f.MethName = "<clinit>"
 	f.ClName = k.Data.Name
 	f.CP = meth.Cp                        // add its pointer to the class CP
+	f.LineTable = meth.LineTable          // for the source line numbers in stack traces
 	f.Meth = append(f.Meth, meth.Code...) // copy the bytecodes over
 
 	// allocate the local variables
@@ -0,0 +1,174 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"jacobin/classloader"
+	"jacobin/frames"
+	"jacobin/globals"
+	"jacobin/log"
+	"jacobin/opcodes"
+	"jacobin/statics"
+	"jacobin/stringPool"
+	"jacobin/types"
+	"testing"
+)
+
+// makeInitTestClasses sets up the classes test/A, whose <clinit> sets its static x to 42 and
+// whose static getX() returns x, and test/B, which extends A and whose <clinit> sets its static
+// y to A.x + 1. If bHasClinit is false, B has no <clinit>, and so no static y. Entry 10 of the
+// CP the classes share is the method reference to A.getX().
+func makeInitTestClasses(bHasClinit bool) {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.InitMethodArea()
+	classloader.MTable = make(map[string]classloader.MTentry)
+	statics.Statics = make(map[string]statics.Static)
+
+	classA, classB := "test/A", "test/B"
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{
+		{Type: 0, Slot: 0},
+		{Type: classloader.FieldRef, Slot: 0}, // A.x
+		{Type: classloader.FieldRef, Slot: 1}, // B.y
+		{Type: classloader.ClassRef, Slot: 0},
+		{Type: classloader.ClassRef, Slot: 1},
+		{Type: classloader.NameAndType, Slot: 0},
+		{Type: classloader.NameAndType, Slot: 1},
+		{Type: classloader.UTF8, Slot: 0},
+		{Type: classloader.UTF8, Slot: 1},
+		{Type: classloader.UTF8, Slot: 2},
+		{Type: classloader.MethodRef, Slot: 0}, // A.getX()
+		{Type: classloader.NameAndType, Slot: 2},
+		{Type: classloader.UTF8, Slot: 3},
+		{Type: classloader.UTF8, Slot: 4},
+	}
+	CP.FieldRefs = []classloader.FieldRefEntry{{ClassIndex: 3, NameAndType: 5}, {ClassIndex: 4, NameAndType: 6}}
+	CP.ClassRefs = []uint32{stringPool.GetStringIndex(&classA), stringPool.GetStringIndex(&classB)}
+	CP.MethodRefs = []classloader.MethodRefEntry{{ClassIndex: 3, NameAndType: 11}}
+	CP.NameAndTypes = []classloader.NameAndTypeEntry{{NameIndex: 7, DescIndex: 9}, {NameIndex: 8, DescIndex: 9},
+		{NameIndex: 12, DescIndex: 13}}
+	CP.Utf8Refs = []string{"x", "y", types.Int, "getX", "()I"}
+
+	makeTestClass(classA, types.ObjectClassName, map[string]int{"<clinit>()V": 0x0008, "getX()I": 0x0008})
+	classloader.MethAreaFetch(classA).Data.ClInit = types.ClInitNotRun
+	classloader.MTable[classA+".<clinit>()V"] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0008, MaxStack: 2, MaxLocals: 0, Cp: &CP,
+			Code: []byte{opcodes.BIPUSH, 42, opcodes.PUTSTATIC, 0, 1, opcodes.RETURN}},
+		MType: 'J',
+	}
+	classloader.MTable[classA+".getX()I"] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0008, MaxStack: 1, MaxLocals: 0, Cp: &CP,
+			Code: []byte{opcodes.GETSTATIC, 0, 1, opcodes.IRETURN}},
+		MType: 'J',
+	}
+	_ = statics.AddStatic(classA+".x", statics.Static{Type: types.Int, Value: int64(0)})
+
+	if !bHasClinit {
+		makeTestClass(classB, classA, map[string]int{})
+		return
+	}
+	makeTestClass(classB, classA, map[string]int{"<clinit>()V": 0x0008})
+	classloader.MethAreaFetch(classB).Data.ClInit = types.ClInitNotRun
+	classloader.MTable[classB+".<clinit>()V"] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0008, MaxStack: 2, MaxLocals: 0, Cp: &CP,
+			Code: []byte{opcodes.GETSTATIC, 0, 1, opcodes.ICONST_1, opcodes.IADD, opcodes.PUTSTATIC, 0, 2, opcodes.RETURN}},
+		MType: 'J',
+	}
+	_ = statics.AddStatic(classB+".y", statics.Static{Type: types.Int, Value: int64(0)})
+}
+
+// The superclass's <clinit> runs before the subclass's, so B.y is A.x + 1.
+func TestInitializationRunsSuperclassFirst(t *testing.T) {
+	makeInitTestClasses(true)
+	defer classloader.InitMethodArea()
+
+	fs := frames.CreateFrameStack()
+	if _, err := InstantiateClass("test/B", fs); err != nil {
+		t.Fatalf("Unexpected error instantiating test/B: %s", err.Error())
+	}
+
+	if x := statics.Statics["test/A.x"].Value; x != int64(42) {
+		t.Errorf("Expected A.x to be 42, got: %v", x)
+	}
+	if y := statics.Statics["test/B.y"].Value; y != int64(43) {
+		t.Errorf("Expected B.y to be 43 (A.x + 1), got: %v", y)
+	}
+	for _, className := range []string{"test/A", "test/B"} {
+		if clInit := classloader.MethAreaFetch(className).Data.ClInit; clInit != types.ClInitRun {
+			t.Errorf("Expected %s to be marked as initialized, got status: %d", className, clInit)
+		}
+	}
+
+	// initialization happens once, so instantiating B again doesn't rerun either <clinit>
+	statics.Statics["test/A.x"] = statics.Static{Type: types.Int, Value: int64(0)}
+	if _, err := InstantiateClass("test/B", fs); err != nil {
+		t.Fatalf("Unexpected error instantiating test/B again: %s", err.Error())
+	}
+	if x := statics.Statics["test/A.x"].Value; x != int64(0) {
+		t.Errorf("Expected A.<clinit> not to be rerun, but A.x is: %v", x)
+	}
+}
+
+// A class that has no <clinit> of its own still has its superclass initialized.
+func TestInitializationOfClassWithoutClinit(t *testing.T) {
+	makeInitTestClasses(false)
+	defer classloader.InitMethodArea()
+
+	fs := frames.CreateFrameStack()
+	if _, err := InstantiateClass("test/B", fs); err != nil {
+		t.Fatalf("Unexpected error instantiating test/B: %s", err.Error())
+	}
+	if x := statics.Statics["test/A.x"].Value; x != int64(42) {
+		t.Errorf("Expected A.<clinit> to have set A.x to 42, got: %v", x)
+	}
+}
+
+// Gfunctions such as Class.forName() initialize a class through globals.FuncInitializeClass.
+func TestInitializeClassFromGfunction(t *testing.T) {
+	makeInitTestClasses(true)
+	defer classloader.InitMethodArea()
+
+	fs := frames.CreateFrameStack()
+	if err := initializeClassFromGfunction("test/B", fs); err != nil {
+		t.Fatalf("Unexpected error initializing test/B: %s", err.Error())
+	}
+	if y := statics.Statics["test/B.y"].Value; y != int64(43) {
+		t.Errorf("Expected B.y to be 43 (A.x + 1), got: %v", y)
+	}
+
+	if err := initializeClassFromGfunction("test/NotLoaded", fs); err == nil {
+		t.Error("Expected an error initializing a class that is not loaded, but got none")
+	}
+}
+
+// INVOKESTATIC initializes the class of the method it calls, so a static method that reads a
+// static set by the class's <clinit> sees the initialized value, even as the first use of the class.
+func TestInvokestaticInitializesClass(t *testing.T) {
+	makeInitTestClasses(true)
+	defer classloader.InitMethodArea()
+
+	getX := classloader.MTable["test/A.getX()I"].Meth.(classloader.JmEntry)
+	f := frames.CreateFrame(2)
+	f.Meth = []byte{opcodes.INVOKESTATIC, 0x00, 0x0A, opcodes.RETURN}
+	f.CP = getX.Cp
+	fs := frames.CreateFrameStack()
+	fs.PushFront(f)
+	if err := runFrame(fs); err != nil {
+		t.Fatalf("Unexpected error running INVOKESTATIC: %s", err.Error())
+	}
+
+	if x := f.OpStack[0]; x != int64(42) {
+		t.Errorf("Expected A.getX() to return 42, as set by A.<clinit>, got: %v", x)
+	}
+	if clInit := classloader.MethAreaFetch("test/A").Data.ClInit; clInit != types.ClInitRun {
+		t.Errorf("Expected test/A to be marked as initialized, got status: %d", clInit)
+	}
+	if clInit := classloader.MethAreaFetch("test/B").Data.ClInit; clInit != types.ClInitNotRun {
+		t.Errorf("Expected test/B, which was not used, to be left uninitialized, got status: %d", clInit)
+	}
+}
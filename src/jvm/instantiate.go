@@ -89,8 +89,8 @@ func InstantiateClass(classname string, frameStack *list.List) (any, error) {
 
 	// the object's mark field contains the lower 32-bits of the object's
 	// address, which serves as the hash code for the object
-	uintp := uintptr(unsafe.Pointer(&obj))
-	obj.Mark.Hash = uint32(uintp)
+	obj.Mark.Hash = object.IdentityHash(uintptr(unsafe.Pointer(&obj)))
+	object.TrackObject(&obj)
 
 	// handle the fields. If the object has no superclass other than Object,
 	// the fields are in an array in the order they're declared in the CP.
@@ -164,10 +164,9 @@ func InstantiateClass(classname string, frameStack *list.List) (any, error) {
 	} // end of handling fields for classes with superclasses other than Object
 
 runInitializer:
-	// run intialization blocks
-	_, ok := k.Data.MethodTable["<clinit>()V"]
-	if ok && k.Data.ClInit == types.ClInitNotRun {
-		err := runInitializationBlock(k, superclasses, frameStack)
+	// run intialization blocks, those of the superclasses first
+	if needsInitialization(k) {
+		err := runInitializationBlock(k, frameStack)
 		if err != nil {
 			errMsg := fmt.Sprintf("error encountered running %s.<clinit>()", classname)
 			_ = log.Log(errMsg, log.SEVERE)
@@ -14,11 +14,11 @@ import (
 	"jacobin/globals"
 	"jacobin/log"
 	"jacobin/native"
+	"jacobin/object"
 	"jacobin/shutdown"
 	"jacobin/statics"
 	"jacobin/stringPool"
 	"jacobin/thread"
-	"jacobin/types"
 	"os"
 )
 
@@ -63,6 +63,10 @@ func JVMrun() int {
 	globPtr.FuncInstantiateClass = InstantiateClass
 	globPtr.FuncThrowException = exceptions.ThrowExNil
 	globPtr.FuncFillInStackTrace = gfunction.FillInStackTrace
+	globPtr.FuncDumpObjects = func() { object.DumpObjects(os.Stderr) }
+	globPtr.FuncDumpBytecodes = func() { DumpBytecodeCoverage(os.Stderr) }
+	globPtr.FuncInvokeMethod = invokeMethodFromGfunction
+	globPtr.FuncInitializeClass = initializeClassFromGfunction
 
 	_ = log.Log("running program: "+globPtr.JacobinName, log.FINE)
 
@@ -91,6 +95,11 @@ func JVMrun() int {
 
 	var mainClassNameIndex uint32
 	if globPtr.StartingJar != "" {
+		// as with -jar in HotSpot, the jar is the classpath and its manifest names the main class
+		if _, err := os.Stat(globPtr.StartingJar); err != nil {
+			_ = log.Log(fmt.Sprintf("Error: Unable to access jarfile %s", globPtr.StartingJar), log.SEVERE)
+			return shutdown.Exit(shutdown.JVM_EXCEPTION)
+		}
 		manifestClass, err := classloader.GetMainClassFromJar(classloader.BootstrapCL, globPtr.StartingJar)
 
 		if err != nil {
@@ -117,13 +126,8 @@ func JVMrun() int {
 		return shutdown.Exit(shutdown.APP_EXCEPTION)
 	}
 
-	// if assertions were enable on the command line for the program, then make sure
-	// that it's set in the Statics table w/ an entry corresponding to the main class
-	// Otherwise, it was previously set to disabled
-	if globPtr.Options["-ea"].Set {
-		_ = statics.AddStatic("main.$assertionsDisabled",
-			statics.Static{Type: types.Int, Value: types.JavaBoolFalse})
-	}
+	// Note: the assertion status (-ea/-da) was set in the Statics table during CLI
+	// processing, which follows the preloading of statics. So it's not reset here.
 
 	// the following was commented out per JACOBIN-327.
 	// Likely to be reinstated at some later point
@@ -145,6 +149,9 @@ func JVMrun() int {
 	_ = log.Log("Starting execution with: "+*mainClass, log.INFO)
 	status = StartExec(*mainClass, &MainThread, globPtr)
 
+	// Threads aren't yet started, so the main thread is the only one to run, and the VM exits
+	// when it ends. When Threads can be started, the VM is to wait here for any that are not
+	// daemons, as set by Thread.setDaemon().
 	if status != nil {
 		return shutdown.Exit(shutdown.APP_EXCEPTION)
 	}
@@ -0,0 +1,79 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"jacobin/excNames"
+	"jacobin/frames"
+	"jacobin/object"
+	"jacobin/stringPool"
+	"strings"
+)
+
+// MethodHandle.invoke() and invokeExact() are signature polymorphic: the descriptor in the
+// method ref of the INVOKEVIRTUAL that calls them is that of the call site, not of a method
+// declared in MethodHandle. So, rather than being resolved by that descriptor, the call is
+// dispatched to the target of the handle, using the arguments on the operand stack.
+//
+// Jacobin's method handles are direct handles to virtual methods, which are made by
+// MethodHandles.Lookup.findVirtual() (see gfunction/javaLangInvokeMethodHandles.go). A handle
+// is an object of class java/lang/invoke/DirectMethodHandle whose fields hold the class, name,
+// and type of its target method. The type of the handle is that of the target method with the
+// class prepended as the first parameter, as that's the receiver of the method.
+
+const (
+	methodHandleClassName       = "java/lang/invoke/MethodHandle"
+	directMethodHandleClassName = "java/lang/invoke/DirectMethodHandle"
+)
+
+// isSignaturePolymorphic reports whether the method ref of an INVOKEVIRTUAL is to one of the
+// signature-polymorphic methods of MethodHandle
+func isSignaturePolymorphic(className, methodName string) bool {
+	return className == methodHandleClassName && (methodName == "invoke" || methodName == "invokeExact")
+}
+
+// dispatchMethodHandle prepares the operand stack for the invocation of the target of a method
+// handle, given the method name and descriptor at the call site. The handle, which is below
+// the arguments on the stack, is removed, so that the receiver and parameters of the target
+// method are left in place. The class, name, and type of the target method are returned.
+// If the handle is null, has no target, or if its type doesn't match the call site (exactly for invokeExact()
+// and, as conversions of the arguments are not yet supported, in the number of parameter slots
+// for invoke()), the exception to throw and its message are returned instead.
+func dispatchMethodHandle(f *frames.Frame, methodName, callSiteType string) (string, string, string, int, string) {
+	handleSlot := f.TOS - paramSlotCount(callSiteType)
+	var handle *object.Object
+	if handleSlot >= 0 {
+		handle, _ = f.OpStack[handleSlot].(*object.Object)
+	}
+	if handle == nil || object.IsNull(handle) {
+		return "", "", "", excNames.NullPointerException, "MethodHandle." + methodName + ": method handle is null"
+	}
+	if *stringPool.GetStringPointer(handle.KlassName) != directMethodHandleClassName {
+		return "", "", "", excNames.UnsupportedOperationException,
+			"MethodHandle." + methodName + ": unsupported kind of method handle"
+	}
+
+	targetClass, okClass := handle.FieldTable["targetClass"].Fvalue.(string)
+	targetName, okName := handle.FieldTable["targetName"].Fvalue.(string)
+	targetType, okType := handle.FieldTable["targetType"].Fvalue.(string)
+	if !okClass || !okName || !okType {
+		return "", "", "", excNames.InternalError, "MethodHandle." + methodName + ": method handle has no target"
+	}
+	handleType := "(L" + targetClass + ";" + strings.TrimPrefix(targetType, "(")
+
+	if methodName == "invokeExact" && callSiteType != handleType ||
+		paramSlotCount(callSiteType) != paramSlotCount(handleType) {
+		return "", "", "", excNames.WrongMethodTypeException,
+			fmt.Sprintf("MethodHandle.%s: expected %s but found %s", methodName, handleType, callSiteType)
+	}
+
+	copy(f.OpStack[handleSlot:], f.OpStack[handleSlot+1:f.TOS+1])
+	f.OpStack[f.TOS] = nil
+	f.TOS -= 1
+	return targetClass, targetName, targetType, 0, ""
+}
@@ -0,0 +1,213 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"container/list"
+	"io"
+	"jacobin/classloader"
+	"jacobin/frames"
+	"jacobin/gfunction"
+	"jacobin/globals"
+	"jacobin/log"
+	"jacobin/object"
+	"jacobin/opcodes"
+	"jacobin/stringPool"
+	"jacobin/thread"
+	"jacobin/types"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newVirtualMethodHandle returns a handle to the virtual method methodName with the type
+// methodType, declared in or inherited by className
+func newVirtualMethodHandle(className, methodName, methodType string) *object.Object {
+	handleClassName := directMethodHandleClassName
+	handle := object.MakeEmptyObjectWithClassName(&handleClassName)
+	handle.FieldTable["targetClass"] = object.Field{Ftype: types.GolangString, Fvalue: className}
+	handle.FieldTable["targetName"] = object.Field{Ftype: types.GolangString, Fvalue: methodName}
+	handle.FieldTable["targetType"] = object.Field{Ftype: types.GolangString, Fvalue: methodType}
+	return handle
+}
+
+// newMethodHandleInvokeFrame returns a frame whose method is an INVOKEVIRTUAL of the given
+// MethodHandle method, with the given call-site descriptor
+func newMethodHandleInvokeFrame(methodName, callSiteType string) frames.Frame {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+
+	className := methodHandleClassName
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{
+		{Type: 0, Slot: 0},
+		{Type: classloader.MethodRef, Slot: 0},
+		{Type: classloader.ClassRef, Slot: 0},
+		{Type: classloader.NameAndType, Slot: 0},
+		{Type: classloader.UTF8, Slot: 0},
+		{Type: classloader.UTF8, Slot: 1},
+	}
+	CP.MethodRefs = []classloader.MethodRefEntry{{ClassIndex: 2, NameAndType: 3}}
+	CP.ClassRefs = append(CP.ClassRefs, stringPool.GetStringIndex(&className))
+	CP.NameAndTypes = []classloader.NameAndTypeEntry{{NameIndex: 4, DescIndex: 5}}
+	CP.Utf8Refs = []string{methodName, callSiteType}
+
+	f := newFrame(opcodes.INVOKEVIRTUAL)
+	f.Meth = append(f.Meth, 0x00, 0x01)
+	f.CP = &CP
+	return f
+}
+
+// MethodHandle.invokeExact() and invoke() on a handle to String.length() run length() on
+// the string passed to them
+func TestMethodHandleInvokeStringLength(t *testing.T) {
+	for _, methodName := range []string{"invokeExact", "invoke"} {
+		f := newMethodHandleInvokeFrame(methodName, "(Ljava/lang/String;)I")
+		push(&f, newVirtualMethodHandle("java/lang/String", "length", "()I"))
+		push(&f, object.StringObjectFromGoString("hello, world"))
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		if err := runFrame(fs); err != nil {
+			t.Fatalf("MethodHandle.%s: Got unexpected error: %s", methodName, err.Error())
+		}
+
+		if f.TOS != 0 {
+			t.Fatalf("MethodHandle.%s: Expected only the length on the stack, got TOS of: %d", methodName, f.TOS)
+		}
+		if length := pop(&f); length != int64(12) {
+			t.Errorf("MethodHandle.%s: Expected a length of 12, got: %v", methodName, length)
+		}
+	}
+}
+
+// A handle made by MethodHandles.Lookup.findVirtual() runs its target: here, String.length(),
+// found with the type made by MethodType.methodType(int.class)
+func TestMethodHandleFromFindVirtual(t *testing.T) {
+	f := newMethodHandleInvokeFrame("invokeExact", "(Ljava/lang/String;)I")
+	gfunctionOf := func(signature string) func([]interface{}) interface{} {
+		return classloader.MTable[signature].Meth.(gfunction.GMeth).GFunction
+	}
+
+	intClass := &classloader.Klass{Data: &classloader.ClData{Name: "java/lang/Integer"}} // as Integer.TYPE is
+	methodType := gfunctionOf("java/lang/invoke/MethodType.methodType(Ljava/lang/Class;)Ljava/lang/invoke/MethodType;")(
+		[]interface{}{intClass})
+	lookup := gfunctionOf("java/lang/invoke/MethodHandles.lookup()Ljava/lang/invoke/MethodHandles$Lookup;")(nil)
+	handle := gfunctionOf("java/lang/invoke/MethodHandles$Lookup.findVirtual(Ljava/lang/Class;Ljava/lang/String;Ljava/lang/invoke/MethodType;)Ljava/lang/invoke/MethodHandle;")(
+		[]interface{}{lookup, object.StringObjectFromGoString("java/lang/String"), // as LDC pushes String.class
+			object.StringObjectFromGoString("length"), methodType})
+	if _, ok := handle.(*object.Object); !ok {
+		t.Fatalf("Expected findVirtual() to return a handle, got: %v", handle)
+	}
+
+	push(&f, handle)
+	push(&f, object.StringObjectFromGoString("hello"))
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	if err := runFrame(fs); err != nil {
+		t.Fatalf("MethodHandle.invokeExact: Got unexpected error: %s", err.Error())
+	}
+	if length := pop(&f); length != int64(5) {
+		t.Errorf("MethodHandle.invokeExact: Expected a length of 5, got: %v", length)
+	}
+}
+
+// invokeExact() throws a WrongMethodTypeException if the call site's type isn't the handle's,
+// and both methods throw a NullPointerException if the handle is null and an InternalError if
+// the handle has no target
+func TestMethodHandleInvokeErrors(t *testing.T) {
+	lengthHandle := func() *object.Object { return newVirtualMethodHandle("java/lang/String", "length", "()I") }
+	nullHandle := func() *object.Object { return object.Null }
+	handleWithoutTarget := func() *object.Object {
+		handleClassName := directMethodHandleClassName
+		return object.MakeEmptyObjectWithClassName(&handleClassName)
+	}
+	for _, test := range []struct {
+		methodName, callSiteType string
+		handle                   func() *object.Object // called after the frame is set up
+		exception, message       string
+	}{
+		{"invokeExact", "(Ljava/lang/Object;)I", lengthHandle,
+			"java.lang.invoke.WrongMethodTypeException", "expected (Ljava/lang/String;)I but found (Ljava/lang/Object;)I"},
+		{"invoke", "(Ljava/lang/String;)I", nullHandle,
+			"java.lang.NullPointerException", "method handle is null"},
+		{"invoke", "(Ljava/lang/String;)I", handleWithoutTarget,
+			"java.lang.InternalError", "method handle has no target"},
+	} {
+		f := newMethodHandleInvokeFrame(test.methodName, test.callSiteType)
+		push(&f, test.handle())
+		push(&f, object.StringObjectFromGoString("hello, world"))
+
+		normalStderr := os.Stderr
+		r, w, _ := os.Pipe()
+		os.Stderr = w
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		err := runFrame(fs)
+
+		_ = w.Close()
+		msg, _ := io.ReadAll(r)
+		os.Stderr = normalStderr
+
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("MethodHandle.%s: Expected error containing %q, got: %v", test.methodName, test.message, err)
+		}
+		if !strings.Contains(string(msg), test.exception) {
+			t.Errorf("MethodHandle.%s: Expected %s, got: %s", test.methodName, test.exception, string(msg))
+		}
+	}
+}
+
+// When the NullPointerException for a null handle is caught, execution resumes at the handler,
+// here: POP (the exception); ICONST_5; RETURN
+func TestMethodHandleInvokeNullHandleCaught(t *testing.T) {
+	f := newMethodHandleInvokeFrame("invoke", "(Ljava/lang/String;)I")
+	gl := globals.GetGlobalRef()
+	gl.JacobinName = "testWithoutShutdown" // so that the exception can be caught
+	gl.FuncInstantiateClass = func(name string, _ *list.List) (any, error) {
+		return object.MakeEmptyObjectWithClassName(&name), nil
+	}
+
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() {
+		_ = w.Close()
+		os.Stderr = normalStderr
+	}()
+
+	npeName := "java/lang/NullPointerException"
+	CP := f.CP.(*classloader.CPool)
+	CP.CpIndex = append(CP.CpIndex, classloader.CpEntry{Type: classloader.ClassRef, Slot: 1})
+	CP.ClassRefs = append(CP.ClassRefs, stringPool.GetStringIndex(&npeName))
+	f.Meth = append(f.Meth, opcodes.POP, opcodes.ICONST_5, opcodes.RETURN)
+	f.ClName, f.MethName, f.MethType = "test/Caller", "call", "()V"
+	classloader.MTable["test/Caller.call()V"] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 3, MaxLocals: 1, Code: f.Meth, Cp: CP,
+			Exceptions: []classloader.CodeException{{StartPc: 0, EndPc: 3, HandlerPc: 3, CatchType: 6}}},
+		MType: 'J',
+	}
+
+	th := thread.CreateThread()
+	th.AddThreadToTable(gl)
+	f.Thread = th.ID
+	push(&f, object.Null)
+	push(&f, object.StringObjectFromGoString("hello, world"))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	th.Stack = fs
+	if err := runFrame(fs); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if f.OpStack[0] != int64(5) {
+		t.Errorf("Expected the handler to run, got stack=%v", f.OpStack)
+	}
+}
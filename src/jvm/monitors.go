@@ -0,0 +1,33 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package jvm
+
+import (
+	"jacobin/object"
+	"sync"
+)
+
+// A static synchronized method locks the monitor of its class's Class object.
+// Jacobin does not yet create a Class object for every class, so each class
+// instead gets a stand-in object that exists only to hold that monitor.
+var classMonitorObjects = make(map[string]*object.Object)
+var classMonitorObjectsLock sync.Mutex
+
+// getClassMonitorObject returns the object whose monitor is locked by the
+// static synchronized methods of the named class, creating it if need be.
+func getClassMonitorObject(className string) *object.Object {
+	classMonitorObjectsLock.Lock()
+	defer classMonitorObjectsLock.Unlock()
+
+	obj, ok := classMonitorObjects[className]
+	if !ok {
+		name := className
+		obj = object.MakeEmptyObjectWithClassName(&name)
+		classMonitorObjects[className] = obj
+	}
+	return obj
+}
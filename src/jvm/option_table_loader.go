@@ -15,6 +15,7 @@ import (
 	"jacobin/statics"
 	"jacobin/types"
 	"os"
+	"strings"
 )
 
 // This set of routines loads the globPtr.Options table with the various
@@ -64,16 +65,30 @@ import (
 // LoadOptionsTable loads the table with all the options Jacobin recognizes.
 func LoadOptionsTable(Global globals.Globals) {
 
+	allowExec := globals.Option{true, false, 0, enableExec}
+	Global.Options["-allowExec"] = allowExec
+
 	client := globals.Option{true, false, 0, clientVM}
 	Global.Options["-client"] = client
 	client.Set = true
 
+	deterministicHash := globals.Option{true, false, 0, enableDeterministicHash}
+	Global.Options["-deterministicHash"] = deterministicHash
+
 	dryRun := globals.Option{false, false, 0, notSupported}
 	Global.Options["--dry-run"] = dryRun
 	dryRun.Set = true
 
-	ea := globals.Option{false, false, 0, enableAssertions}
+	defineProperty := globals.Option{true, false, 2, defineSystemProperty}
+	Global.Options["-D"] = defineProperty
+
+	da := globals.Option{true, false, 0, disableAssertions}
+	Global.Options["-da"] = da
+	Global.Options["-disableassertions"] = da
+
+	ea := globals.Option{true, false, 0, enableAssertions}
 	Global.Options["-ea"] = ea
+	Global.Options["-enableassertions"] = ea
 
 	help := globals.Option{true, false, 0, showHelpStderrAndExit}
 	Global.Options["-h"] = help
@@ -102,6 +117,9 @@ func LoadOptionsTable(Global globals.Globals) {
 	verboseClass := globals.Option{true, false, 1, verbosityLevel}
 	Global.Options["-verbose"] = verboseClass
 
+	dump := globals.Option{true, false, 1, dumpOption}
+	Global.Options["-Xdump"] = dump
+
 	version := globals.Option{true, false, 1, versionStderrThenExit}
 	Global.Options["-version"] = version
 
@@ -191,13 +209,77 @@ func enableTraceInstructions(pos int, argValue string, gl *globals.Globals) (int
 	return pos, nil
 }
 
+// -ea and -enableassertions. Any :<package> or :<class> qualifier is accepted
+// but, for the nonce, assertions are enabled for all classes. The setting is
+// consulted by Class.desiredAssertionStatus(), which is what each class's
+// <clinit> calls to set its own $assertionsDisabled static.
 func enableAssertions(pos int, name string, gl *globals.Globals) (int, error) {
 	setOptionToSeen("-ea", gl)
-	statics.AddStatic("main.$assertionsDisabled",
+	_ = statics.AddStatic("main.$assertionsDisabled",
 		statics.Static{Type: types.Int, Value: types.JavaBoolFalse})
 	return pos, nil
 }
 
+// -da and -disableassertions. As with -ea, any qualifier applies to all classes.
+// Because options are processed in order, the last of -ea/-da on the command line wins.
+func disableAssertions(pos int, name string, gl *globals.Globals) (int, error) {
+	setOptionToSeen("-da", gl)
+	_ = statics.AddStatic("main.$assertionsDisabled",
+		statics.Static{Type: types.Int, Value: types.JavaBoolTrue})
+	return pos, nil
+}
+
+// -allowExec: let the program start OS processes with java.lang.ProcessBuilder. This is off
+// by default, so that a sandboxed run can't spawn processes. See gfunction/javaLangProcessBuilder.go.
+func enableExec(pos int, name string, gl *globals.Globals) (int, error) {
+	gl.AllowExec = true
+	setOptionToSeen("-allowExec", gl)
+	return pos, nil
+}
+
+// -deterministicHash: assign identity hash codes in sequence rather than from object
+// addresses, and iterate HashMaps in order of their keys' hashes (then in insertion order),
+// so that hash-based collections iterate in the same order on every run.
+// This is a debugging aid, used chiefly for tests that compare against fixed output.
+func enableDeterministicHash(pos int, name string, gl *globals.Globals) (int, error) {
+	gl.DeterministicHash = true
+	setOptionToSeen("-deterministicHash", gl)
+	return pos, nil
+}
+
+// -Dname=value sets the system property name to value, which System.getProperty() returns.
+// The value is everything after the first =, so it can contain other = signs, and without
+// an =, as in -Dname, the value is the empty string. The option can be repeated, and a
+// later -D for the same name replaces the earlier value.
+func defineSystemProperty(pos int, argValue string, gl *globals.Globals) (int, error) {
+	name, value, _ := strings.Cut(argValue, "=")
+	if name == "" {
+		log.Log("Error: -D"+argValue+" does not name a system property. Ignored.", log.WARNING)
+		return pos, errors.New("Invalid -D option specified: -D" + argValue)
+	}
+	gl.SystemProperties[name] = value
+	setOptionToSeen("-D", gl)
+	return pos, nil
+}
+
+// -Xdump:objects keeps track of the objects created during the run and, at shutdown,
+// prints the number of them in each class. See object/objectDump.go.
+// -Xdump:bytecodes keeps track of the opcodes the interpreter executes and, at shutdown,
+// prints those that were executed and those that were not. See jvm/bytecodeCoverage.go.
+func dumpOption(pos int, argValue string, gl *globals.Globals) (int, error) {
+	switch argValue {
+	case "objects":
+		gl.DumpObjects = true
+	case "bytecodes":
+		gl.DumpBytecodes = true
+	default:
+		log.Log("Error: "+argValue+" is not a valid -Xdump option. Ignored.", log.WARNING)
+		return pos, errors.New("Invalid -Xdump option specified: " + argValue)
+	}
+	setOptionToSeen("-Xdump", gl)
+	return pos, nil
+}
+
 // set verbosity level. Note Jacobin starts up at WARNING level, so there is no
 // need to set it to that level. You cannot set the level to coarser than WARNING
 // which is why there is no way to set the verbosity to SEVERE only.
@@ -205,6 +287,7 @@ func verbosityLevel(pos int, argValue string, gl *globals.Globals) (int, error)
 	switch argValue {
 	case "class":
 		log.Level = log.CLASS
+		gl.VerboseClass = true
 		log.Log("Logging level set to CLASS", log.INFO)
 	case "info":
 		log.Level = log.INFO
@@ -64,6 +64,7 @@ func StartExec(className string, mainThread *thread.ExecThread, globals *globals
 	f.MethType = "([Ljava/lang/String;)V"
 	f.ClName = className
 	f.CP = m.Cp                        // add its pointer to the class CP
+	f.LineTable = m.LineTable          // for the source line numbers in stack traces
 	f.Meth = append(f.Meth, m.Code...) // copy the bytecodes over
 
 	// allocate the local variables
@@ -147,6 +148,9 @@ func runThread(t *thread.ExecThread) error {
 			return err
 		}
 
+		// a synchronized method releases its monitor when it returns
+		frames.ReleaseMonitor(t.Stack.Front().Value.(*frames.Frame))
+
 		if t.Stack.Len() == 1 { // true when the last executed frame was main()
 			return nil
 		} else {
@@ -169,6 +173,12 @@ frameInterpreter:
 	// the next statement converts the address of that frame to the more readable 'f'
 	f := fs.Front().Value.(*frames.Frame)
 
+	// if an exception thrown in a Java method that a gfunction called was caught here, below
+	// the gfunction's call, that call is abandoned first (see invokeMethodFromGfunction)
+	if f.UnwoundGfunctionCalls > 0 {
+		return errGfunctionCallUnwound
+	}
+
 	// the frame's method is not a golang method, so it's Java bytecode, which
 	// is interpreted in the rest of this function.
 	for f.PC < len(f.Meth) {
@@ -178,6 +188,9 @@ frameInterpreter:
 		}
 
 		opcode := f.Meth[f.PC]
+		if glob.DumpBytecodes {
+			recordOpcode(opcode)
+		}
 		switch opcode { // cases listed in numerical value of opcode
 		case opcodes.NOP:
 			break
@@ -315,6 +328,12 @@ frameInterpreter:
 				index = int(f.Meth[f.PC+1])
 				f.PC += 1
 			}
+			if index+1 > len(f.Locals) {
+				if err := throwLocalsIndexError(f, opcode, index, 1); err != nil {
+					return err // applies only if in test
+				}
+				goto frameInterpreter
+			}
 			push(f, f.Locals[index])
 		case opcodes.LLOAD: // 0x16 (push long from local var, using next byte as index)
 			var index int
@@ -326,6 +345,12 @@ frameInterpreter:
 				index = int(f.Meth[f.PC+1])
 				f.PC += 1
 			}
+			if index+1 > len(f.Locals) {
+				if err := throwLocalsIndexError(f, opcode, index, 1); err != nil {
+					return err // applies only if in test
+				}
+				goto frameInterpreter
+			}
 			val := f.Locals[index].(int64)
 			push(f, val)
 			push(f, val) // push twice due to item being 64 bits wide
@@ -339,6 +364,12 @@ frameInterpreter:
 				index = int(f.Meth[f.PC+1])
 				f.PC += 1
 			}
+			if index+1 > len(f.Locals) {
+				if err := throwLocalsIndexError(f, opcode, index, 1); err != nil {
+					return err // applies only if in test
+				}
+				goto frameInterpreter
+			}
 			val := f.Locals[index].(float64)
 			push(f, val)
 			push(f, val) // push twice due to item being 64 bits wide
@@ -488,13 +519,14 @@ frameInterpreter:
 		case opcodes.AALOAD: // 0x32    (push contents of a reference array element)
 			index := pop(f).(int64)
 			rAref := pop(f) // the array object. Can't be cast to *Object b/c might be nil
-			if rAref == nil {
+			if object.IsNull(rAref) {
 				errMsg := fmt.Sprintf("in %s.%s, AALOAD: Invalid (null) reference to an array",
 					util.ConvertInternalClassNameToUserFormat(f.ClName), f.MethName)
 				status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, f)
 				if status != exceptions.Caught {
 					return errors.New(errMsg) // applies only if in test
 				}
+				goto frameInterpreter // the exception was caught, so execute the catch block
 			}
 
 			fvalue := (rAref.(*object.Object)).FieldTable["value"].Fvalue
@@ -571,6 +603,16 @@ frameInterpreter:
 				index = int(f.Meth[f.PC+1])
 				f.PC += 1
 			}
+			width := 1
+			if opcode == opcodes.LSTORE {
+				width = 2
+			}
+			if index+width > len(f.Locals) {
+				if err := throwLocalsIndexError(f, opcode, index, width); err != nil {
+					return err // applies only if in test
+				}
+				goto frameInterpreter
+			}
 			f.Locals[index] = pop(f).(int64)
 			// longs and doubles are stored in localvar[x] and again in localvar[x+1]
 			if opcode == opcodes.LSTORE {
@@ -586,6 +628,12 @@ frameInterpreter:
 				index = int(f.Meth[f.PC+1])
 				f.PC += 1
 			}
+			if index+1 > len(f.Locals) {
+				if err := throwLocalsIndexError(f, opcode, index, 1); err != nil {
+					return err // applies only if in test
+				}
+				goto frameInterpreter
+			}
 			f.Locals[index] = pop(f).(float64)
 
 		case opcodes.DSTORE: //  0x39 (store popped top of stack double into local[index])
@@ -598,6 +646,12 @@ frameInterpreter:
 				index = int(f.Meth[f.PC+1])
 				f.PC += 1
 			}
+			if index+2 > len(f.Locals) {
+				if err := throwLocalsIndexError(f, opcode, index, 2); err != nil {
+					return err // applies only if in test
+				}
+				goto frameInterpreter
+			}
 			f.Locals[index] = pop(f).(float64)
 			// longs and doubles are stored in localvar[x] and again in localvar[x+1]
 			f.Locals[index+1] = pop(f).(float64)
@@ -611,6 +665,12 @@ frameInterpreter:
 				index = int(f.Meth[f.PC+1])
 				f.PC += 1
 			}
+			if index+1 > len(f.Locals) {
+				if err := throwLocalsIndexError(f, opcode, index, 1); err != nil {
+					return err // applies only if in test
+				}
+				goto frameInterpreter
+			}
 			f.Locals[index] = pop(f)
 		case opcodes.ISTORE_0: //   0x3B    (store popped top of stack int into local 0)
 			popped := pop(f)
@@ -840,11 +900,11 @@ frameInterpreter:
 			array[index] = value
 
 		case opcodes.AASTORE: // 0x53   (store a reference in a reference array)
-			value := pop(f).(*object.Object)    // reference we're inserting
-			index := pop(f).(int64)             // index into the array
-			arrayRef := pop(f).(*object.Object) // ptr to the array object
+			valueRef := pop(f)      // reference we're inserting
+			index := pop(f).(int64) // index into the array
+			arrayRef := pop(f)      // the array object. Can't be cast to *Object b/c might be nil
 
-			if arrayRef == nil {
+			if object.IsNull(arrayRef) {
 				glob.ErrorGoStack = string(debug.Stack())
 				errMsg := fmt.Sprintf("in %s.%s, AASTORE: Invalid (null) reference to an array",
 					util.ConvertInternalClassNameToUserFormat(f.ClName), f.MethName)
@@ -852,9 +912,16 @@ frameInterpreter:
 				if status != exceptions.Caught {
 					return errors.New(errMsg) // applies only if in test
 				}
+				goto frameInterpreter // the exception was caught, so execute the catch block
+			}
+
+			// a null reference, whether nil or object.Null, is stored as object.Null
+			value := object.Null
+			if !object.IsNull(valueRef) {
+				value = valueRef.(*object.Object)
 			}
 
-			arrayObj := *arrayRef
+			arrayObj := *(arrayRef.(*object.Object))
 			rawArrayObj := arrayObj.FieldTable["value"]
 
 			if !strings.HasPrefix(rawArrayObj.Ftype, types.RefArray) {
@@ -960,6 +1027,9 @@ frameInterpreter:
 			push(f, next)
 			push(f, top)
 		case opcodes.DUP2: // 0x5C			(Duplicate the top two stack values)
+			// DUP2 duplicates either two category-1 values (ints, floats, refs...) or one
+			// category-2 value (a long or double). A long or double occupies two slots on
+			// the operand stack, so in both cases the top two slots are duplicated.
 			top := pop(f)
 			next := peek(f)
 			push(f, top)
@@ -1085,7 +1155,9 @@ frameInterpreter:
 					return errors.New(errMsg) // applies only if in test
 				}
 			} else {
-				push(f, val2/val1)
+				// the quotient is truncated to an int, so that MIN_VALUE / -1 wraps to MIN_VALUE.
+				// (For longs, and for the remainders, golang already gives the Java results.)
+				push(f, int64(int32(val2/val1)))
 			}
 		case opcodes.LDIV: //  0x6D   (long divide tos-2 by tos)
 			val1 := pop(f).(int64)
@@ -1304,12 +1376,20 @@ frameInterpreter:
 				increment = byteToInt64(f.Meth[f.PC+2])
 				f.PC += 2
 			}
+			if index+1 > len(f.Locals) {
+				if err := throwLocalsIndexError(f, opcode, index, 1); err != nil {
+					return err // applies only if in test
+				}
+				goto frameInterpreter
+			}
 			orig := f.Locals[index].(int64)
 			f.Locals[index] = orig + increment
 
 		case opcodes.I2F: //	0x86 	( convert int to float)
+			// ints with more than 24 significant bits don't fit in a float, so they
+			// are rounded to the nearest float value, as the JVM spec requires
 			intVal := pop(f).(int64)
-			push(f, float64(intVal))
+			push(f, float64(float32(intVal)))
 		case opcodes.I2L: // 	0x85     (convert int to long)
 			// 	ints are already 64-bits, so this just pushes a second instance
 			val := peek(f).(int64) // look without popping
@@ -1514,9 +1594,7 @@ frameInterpreter:
 			val2 := convertIntegralValueToInt64(popValue)
 			popValue = pop(f)
 			val1 := convertIntegralValueToInt64(popValue)
-			val1a := val1
-			val2a := val2
-			if val1a < val2a { // if comp succeeds, next 2 bytes hold instruction index
+			if int32(val1) < int32(val2) { // if comp succeeds, next 2 bytes hold instruction index
 				jumpTo := (int16(f.Meth[f.PC+1]) * 256) + int16(f.Meth[f.PC+2])
 				f.PC = f.PC + int(jumpTo) - 1 // -1 b/c on the next iteration, pc is bumped by 1
 			} else {
@@ -1527,7 +1605,7 @@ frameInterpreter:
 			val2 := convertIntegralValueToInt64(popValue)
 			popValue = pop(f)
 			val1 := convertIntegralValueToInt64(popValue)
-			if val1 >= val2 { // if comp succeeds, next 2 bytes hold instruction index
+			if int32(val1) >= int32(val2) { // if comp succeeds, next 2 bytes hold instruction index
 				jumpTo := (int16(f.Meth[f.PC+1]) * 256) + int16(f.Meth[f.PC+2])
 				f.PC = f.PC + int(jumpTo) - 1 // -1 b/c on the next iteration, pc is bumped by 1
 			} else {
@@ -1549,7 +1627,7 @@ frameInterpreter:
 			val2 := convertIntegralValueToInt64(popValue)
 			popValue = pop(f)
 			val1 := convertIntegralValueToInt64(popValue)
-			if val1 <= val2 { // if comp succeeds, next 2 bytes hold instruction index
+			if int32(val1) <= int32(val2) { // if comp succeeds, next 2 bytes hold instruction index
 				jumpTo := (int16(f.Meth[f.PC+1]) * 256) + int16(f.Meth[f.PC+2])
 				f.PC = f.PC + int(jumpTo) - 1 // -1 b/c on the next iteration, pc is bumped by 1
 			} else {
@@ -1558,7 +1636,9 @@ frameInterpreter:
 		case opcodes.IF_ACMPEQ: // 0xA5		(jump if two addresses are equal)
 			val2 := pop(f)
 			val1 := pop(f)
-			if val1 == val2 { // if comp succeeds, next 2 bytes hold instruction index
+			// null is either nil or object.Null, so two nulls are equal even if they differ.
+			// If comp succeeds, next 2 bytes hold instruction index
+			if val1 == val2 || (object.IsNull(val1) && object.IsNull(val2)) {
 				jumpTo := (int16(f.Meth[f.PC+1]) * 256) + int16(f.Meth[f.PC+2])
 				f.PC = f.PC + int(jumpTo) - 1 // -1 b/c on the next iteration, pc is bumped by 1
 			} else {
@@ -1567,7 +1647,8 @@ frameInterpreter:
 		case opcodes.IF_ACMPNE: // 0xA6		(jump if two addresses are note equal)
 			val2 := pop(f)
 			val1 := pop(f)
-			if val1 != val2 { // if comp succeeds, next 2 bytes hold instruction index
+			// as in IF_ACMPEQ, two nulls are equal. If comp succeeds, next 2 bytes hold instruction index
+			if val1 != val2 && !(object.IsNull(val1) && object.IsNull(val2)) {
 				jumpTo := (int16(f.Meth[f.PC+1]) * 256) + int16(f.Meth[f.PC+2])
 				f.PC = f.PC + int(jumpTo) - 1 // -1 b/c on the next iteration, pc is bumped by 1
 			} else {
@@ -1577,6 +1658,13 @@ frameInterpreter:
 			jumpTo := (int16(f.Meth[f.PC+1]) * 256) + int16(f.Meth[f.PC+2])
 			f.PC = f.PC + int(jumpTo) - 1 // -1 because this loop will increment f.PC by 1
 
+		case opcodes.JSR: // 0xA8     (jump to subroutine at a two-byte offset from the current PC)
+			jumpTo := (int16(f.Meth[f.PC+1]) * 256) + int16(f.Meth[f.PC+2])
+			// the return address is the last byte of this instruction, because RET jumps
+			// to it and then this loop increments f.PC to the instruction after the JSR.
+			push(f, int64(f.PC+2))
+			f.PC = f.PC + int(jumpTo) - 1 // -1 because this loop will increment f.PC by 1
+
 		case opcodes.RET: // 0xA9     (return by jumping to a return address--used mostly with JSR)
 			var index int
 			if wideInEffect { // if wide is in effect, index is two bytes wide, otherwise one byte
@@ -1587,6 +1675,12 @@ frameInterpreter:
 				index = int(f.Meth[f.PC+1])
 				f.PC += 1
 			}
+			if index+1 > len(f.Locals) {
+				if err := throwLocalsIndexError(f, opcode, index, 1); err != nil {
+					return err // applies only if in test
+				}
+				goto frameInterpreter
+			}
 			newPC := f.Locals[index].(int64)
 			f.PC = int(newPC)
 		case opcodes.TABLESWITCH: // 0xAA (switch based on table of offsets)
@@ -1896,6 +1990,7 @@ frameInterpreter:
 					kPtr := value.(*classloader.Klass)
 					obj := object.MakeEmptyObject()
 					obj.KlassName = stringPool.GetStringIndex(&kPtr.Data.Name)
+					object.TrackObject(obj)
 					objField := object.Field{
 						Ftype:  "L" + kPtr.Data.Name + ";",
 						Fvalue: kPtr,
@@ -2105,6 +2200,12 @@ frameInterpreter:
 			classNamePtr := stringPool.GetStringPointer(classNameIndex)
 			className := *classNamePtr
 
+			// the methods of an array class are those of java.lang.Object. (This is how
+			// an enum's values() method calls clone() to copy its $VALUES array.)
+			if strings.HasPrefix(className, types.Array) {
+				className = "java/lang/Object"
+			}
+
 			// get the method name for this method
 			nAndTindex := method.NameAndType
 			nAndTentry := CP.CpIndex[nAndTindex]
@@ -2117,6 +2218,21 @@ frameInterpreter:
 			methodSigIndex := nAndT.DescIndex
 			methodType := classloader.FetchUTF8stringFromCPEntryNumber(CP, methodSigIndex)
 
+			// MethodHandle.invoke() and invokeExact() run the target of the handle instead
+			if isSignaturePolymorphic(className, methodName) {
+				var excName int
+				var errMsg string
+				className, methodName, methodType, excName, errMsg = dispatchMethodHandle(f, methodName, methodType)
+				if errMsg != "" {
+					glob.ErrorGoStack = string(debug.Stack())
+					status := exceptions.ThrowEx(excName, errMsg, f)
+					if status != exceptions.Caught {
+						return errors.New(errMsg) // applies only if in test
+					}
+					goto frameInterpreter // the exception was caught, so execute the catch block
+				}
+			}
+
 			if native.IsUnsupportedNativeMethod(className + "." + methodName) {
 				errMsg := fmt.Sprintf("%s() in %s is an unsupported native function",
 					methodName, className)
@@ -2127,11 +2243,24 @@ frameInterpreter:
 				}
 			}
 
+			// the method that's run is that of the receiver's runtime class, which might
+			// override the method of the class in the method ref. The receiver is on the
+			// op stack below the method's parameters.
+			runtimeClassName := ""
+			if receiverSlot := f.TOS - paramSlotCount(methodType); receiverSlot >= 0 {
+				runtimeClassName = runtimeClassOf(f.OpStack[receiverSlot])
+				className = resolveVirtualMethod(className, methodName, methodType, f.OpStack[receiverSlot])
+			}
+
 			mtEntry := classloader.MTable[className+"."+methodName+methodType]
 			if mtEntry.Meth == nil { // if the method is not in the method table, find it
 				mtEntry, err = classloader.FetchMethodAndCP(className, methodName, methodType)
+				if (err != nil || mtEntry.Meth == nil) && runtimeClassName != "" {
+					// no class implements the method, so use the default method of an interface, if any
+					mtEntry, _, err = classloader.FetchDefaultMethod(runtimeClassName, methodName, methodType)
+				}
 				if err != nil || mtEntry.Meth == nil {
-					// TODO: search the superclasses, then the classpath and retry
+					// TODO: search the classpath and retry
 					glob.ErrorGoStack = string(debug.Stack())
 					errMsg := "INVOKEVIRTUAL: Class method not found: " + className + "." + methodName + methodType
 					status := exceptions.ThrowEx(excNames.UnsupportedOperationException, errMsg, f)
@@ -2142,6 +2271,16 @@ frameInterpreter:
 				}
 			}
 
+			if isAbstractMethod(mtEntry) {
+				glob.ErrorGoStack = string(debug.Stack())
+				errMsg := "INVOKEVIRTUAL: Method has no implementation: " + className + "." + methodName + methodType
+				status := exceptions.ThrowEx(excNames.AbstractMethodError, errMsg, f)
+				if status != exceptions.Caught {
+					return errors.New(errMsg) // applies only if in test
+				}
+				goto frameInterpreter // the error was caught, so execute the catch block
+			}
+
 			// if we have a native function (here, one implemented in golang, rather than Java),
 			// then follow the JVM spec and push the objectRef and the parameters to the function
 			// as parameters. Consult:
@@ -2170,9 +2309,7 @@ frameInterpreter:
 							return errRet
 						}
 						if errors.Is(ret.(error), CaughtGfunctionException) {
-							f.ExceptionPC = f.PC // in the event of an exception, here's where we were
-							// f.PC += 2 // due to the PC value extracted at the start of this bytecode
-							f.PC += 1
+							// the catching frame's PC already points to its handler
 							goto frameInterpreter
 						}
 					default: // if it's not an error, then it's a legitimate return value, which we simply push
@@ -2264,9 +2401,7 @@ frameInterpreter:
 							return errRet
 						}
 						if errors.Is(ret.(error), CaughtGfunctionException) {
-							f.ExceptionPC = f.PC // in the event of an exception, here's where we were
-							// f.PC += 2 // for the two bytes used by CP entry
-							f.PC += 1 // point to the next executable bytecode
+							// the catching frame's PC already points to its handler
 							goto frameInterpreter
 						}
 					default: // if it's not an error, then it's a legitimate return value, which we simply push
@@ -2350,8 +2485,8 @@ frameInterpreter:
 			// make sure that its static intializer block (if any) has been run. At this point,
 			// all we know the class exists and has been loaded.
 			k := classloader.MethAreaFetch(className)
-			if k.Data.ClInit == types.ClInitNotRun {
-				err = runInitializationBlock(k, nil, fs)
+			if needsInitialization(k) {
+				err = runInitializationBlock(k, fs)
 				if err != nil {
 					glob.ErrorGoStack = string(debug.Stack())
 					errMsg := fmt.Sprintf("INVOKESTATIC: error running initializer block in %s",
@@ -2381,7 +2516,7 @@ frameInterpreter:
 							errRet := ret.(error)
 							return errRet
 						} else if errors.Is(ret.(error), CaughtGfunctionException) {
-							f.PC += 1
+							// the catching frame's PC already points to its handler
 							goto frameInterpreter
 						}
 					default: // if it's not an error, then it's a legitimate return value, which we simply push
@@ -2469,15 +2604,14 @@ frameInterpreter:
 			interfaceMethodType := classloader.FetchUTF8stringFromCPEntryNumber(
 				CP, interfaceMethodSigIndex)
 
-			var args []any
-			for i := 0; i < int(count)-1; i++ {
-				args = append(args, pop(f))
+			// the objRef, which has previously been instantiated and its constructor called,
+			// is on the op stack below the count-1 slots of the method's arguments. Both are
+			// left on the stack until the selected method is invoked.
+			var objRef interface{}
+			if objRefSlot := f.TOS - int(count-1); objRefSlot >= 0 {
+				objRef = f.OpStack[objRefSlot]
 			}
-			// now get the objRef pointing to the class containing the call to the method
-			// described just previously.
-			// The objRef object has previously been instantiated and its constructor called.
-			objRef := pop(f)
-			if objRef == nil {
+			if object.IsNull(objRef) {
 				errMsg := fmt.Sprintf("INVOKEINTERFACE: object whose method, %s, is invoked is null",
 					interfaceName+interfaceMethodName+interfaceMethodType)
 				status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, f)
@@ -2505,32 +2639,7 @@ frameInterpreter:
 				}
 			}
 
-			// Now find the interface method. Section 5.4.3.4 of the JVM spec lists the order in which
-			// the steps are taken, where C is the interface:
-			//
-			// 1) If C is not an interface, interface method resolution throws an IncompatibleClassChangeError.
-			//
-			// 2) Otherwise, if C declares a method with the name and descriptor specified by the
-			// interface method reference, method lookup succeeds.
-			//
-			// 3) Otherwise, if the class Object declares a method with the name and descriptor specified by the
-			// interface method reference, which has its ACC_PUBLIC flag set and does not have its ACC_STATIC flag set,
-			// method lookup succeeds.
-			//
-			// 4) Otherwise, if the maximally-specific superinterface methods (§5.4.3.3) of C for the name and descriptor
-			// specified by the method reference include exactly one method that does not have its ACC_ABSTRACT flag set,
-			// then this method is chosen and method lookup succeeds.
-			//
-			// 5) Otherwise, if any superinterface of C declares a method with the name and descriptor specified by the
-			// method reference that has neither its ACC_PRIVATE flag nor its ACC_STATIC flag set, one of these is
-			// arbitrarily chosen and method lookup succeeds.
-			//
-			// 6) Otherwise, method lookup fails.
-			//
-			// For more info: https://docs.oracle.com/javase/specs/jvms/se17/html/jvms-5.html#jvms-5.4.3.4
-
-			clData := *class.Data
-			if len(clData.Interfaces) == 0 {
+			if !classloader.ImplementsInterface(objRefClassName, interfaceName) {
 				errMsg := fmt.Sprintf("INVOKEINTERFACE: class %s does not implement interface %s",
 					objRefClassName, interfaceName)
 				status := exceptions.ThrowEx(excNames.IncompatibleClassChangeError, errMsg, f)
@@ -2539,95 +2648,86 @@ frameInterpreter:
 				}
 			}
 
-			var foundIntfaceName = ""
-			var mtEntry classloader.MTentry
-			var ok bool
-			for i := 0; i < len(clData.Interfaces); i++ {
-				index := uint32(clData.Interfaces[i])
-				foundIntfaceName = *stringPool.GetStringPointer(index)
-				if foundIntfaceName == interfaceName {
-					// at this point we know that clData's class implements the required interface.
-					// Now, check whether clData contains the desired method.
-					if _, ok = clData.MethodTable[interfaceMethodName+interfaceMethodType]; ok {
-						goto executeInterfaceMethod
-					}
-
-					if err := classloader.LoadClassFromNameOnly(interfaceName); err != nil {
-						// in this case, LoadClassFromNameOnly() will have already thrown the exception
-						if globals.JacobinHome() == "test" {
-							return err // applies only if in test
-						}
-					}
-					mtEntry, _ = classloader.FetchMethodAndCP(
-						interfaceName, interfaceMethodName, interfaceMethodType)
-					if mtEntry.Meth == nil {
-						glob.ErrorGoStack = string(debug.Stack())
-						errMsg := fmt.Sprintf("INVOKEINTERFACE: Interface method not found: %s.%s%s"+
-							interfaceName, interfaceMethodName, interfaceMethodType)
-						status := exceptions.ThrowEx(excNames.ClassNotLoadedException, errMsg, f)
-						if status != exceptions.Caught {
-							return errors.New(errMsg) // applies only if in test
-						}
-					}
-					goto executeInterfaceMethod // method found, move on to execution
-				} else { // TODO: check for superclasses, after checking Object
-					foundIntfaceName = ""
-				}
+			// Now select the method to run. Section 5.4.6 of the JVM spec gives the order:
+			// 1) the method declared in the objRef's class or, failing that, the closest of
+			// its superclasses to declare it; 2) otherwise, a default method of one of the
+			// interfaces of the class and its superclasses. If the selected method is abstract,
+			// or no method is found, an AbstractMethodError is thrown.
+			// For more info: https://docs.oracle.com/javase/specs/jvms/se17/html/jvms-5.html#jvms-5.4.6
+			mtEntry, err := classloader.FetchMethodAndCP(objRefClassName, interfaceMethodName, interfaceMethodType)
+			if err != nil || mtEntry.Meth == nil {
+				mtEntry, _, err = classloader.FetchDefaultMethod(objRefClassName, interfaceMethodName, interfaceMethodType)
 			}
-
-			if foundIntfaceName == "" { // no interface was found, check java.lang.Object()
-				errMsg := fmt.Sprintf("INVOKEINTERFACE: class %s does not implement interface %s",
-					objRefClassName, interfaceName)
-				status := exceptions.ThrowEx(excNames.IncompatibleClassChangeError, errMsg, f)
+			if err != nil || mtEntry.Meth == nil || isAbstractMethod(mtEntry) {
+				glob.ErrorGoStack = string(debug.Stack())
+				errMsg := fmt.Sprintf("INVOKEINTERFACE: class %s has no implementation of %s.%s%s",
+					objRefClassName, interfaceName, interfaceMethodName, interfaceMethodType)
+				status := exceptions.ThrowEx(excNames.AbstractMethodError, errMsg, f)
 				if status != exceptions.Caught {
 					return errors.New(errMsg) // applies only if in test
 				}
+				goto frameInterpreter // the error was caught, so execute the catch block
 			}
 
-			// CURR: get a pointer to the method area
-			// CURR: extract the parameters to the function
-			// CURR: execute the function in the G and J variants
+			if mtEntry.MType == 'G' { // a native golang function
+				gmethData := mtEntry.Meth.(gfunction.GMeth)
+				var params []interface{}
+				for i := 0; i < gmethData.ParamSlots; i++ {
+					params = append(params, pop(f))
+				}
+				params = append(params, pop(f)) // the objRef
+
+				ret := runGfunction(mtEntry, fs, objRefClassName, interfaceMethodName, interfaceMethodType, &params, true)
+				if ret != nil {
+					switch ret.(type) {
+					case error: // only occurs in testing
+						if glob.JacobinName == "test" {
+							return ret.(error)
+						}
+						if errors.Is(ret.(error), CaughtGfunctionException) {
+							// the catching frame's PC already points to its handler
+							goto frameInterpreter
+						}
+					default: // if it's not an error, then it's a legitimate return value, which we simply push
+						push(f, ret)
+						if strings.HasSuffix(interfaceMethodType, "D") || strings.HasSuffix(interfaceMethodType, "J") {
+							push(f, ret) // push twice if long or double
+						}
+					}
+				}
+				break
+			}
 
-		executeInterfaceMethod:
 			if mtEntry.MType == 'J' {
 				m := mtEntry.Meth.(classloader.JmEntry)
 				if m.AccessFlags&0x0100 > 0 {
 					// Native code
 					glob.ErrorGoStack = string(debug.Stack())
 					errMsg := "INVOKEINTERFACE: Native method requested: " +
-						clData.Name + "." + interfaceMethodName + interfaceMethodType
+						objRefClassName + "." + interfaceMethodName + interfaceMethodType
 					status := exceptions.ThrowEx(excNames.UnsupportedOperationException, errMsg, f)
 					if status != exceptions.Caught {
 						return errors.New(errMsg) // applies only if in test
 					}
 				}
-				/*
-					// CURR: un comment following block, then load parameters into the frame's locals
-					fram, err := createAndInitNewFrame(
-						clData.Name, interfaceMethodName, interfaceMethodType, &m, false, f)
-					if err != nil {
-						glob.ErrorGoStack = string(debug.Stack())
-						errMsg := "INVOKEINTERFACE: Error creating frame in: " + clData.Name + "." +
-							interfaceMethodName + interfaceMethodType
-						status := exceptions.ThrowEx(excNames.InvalidStackFrameException, errMsg, f)
-						if status != exceptions.Caught {
-							return errors.New(errMsg) // applies only if in test
-						}
-					}
 
-					if f.ExceptionPC == -1 {
-						f.ExceptionPC = f.PC // in the event of an exception, here's where we were
+				fram, err := createAndInitNewFrame(
+					objRefClassName, interfaceMethodName, interfaceMethodType, &m, true, f)
+				if err != nil {
+					glob.ErrorGoStack = string(debug.Stack())
+					errMsg := "INVOKEINTERFACE: Error creating frame in: " + objRefClassName + "." +
+						interfaceMethodName + interfaceMethodType
+					status := exceptions.ThrowEx(excNames.InvalidStackFrameException, errMsg, f)
+					if status != exceptions.Caught {
+						return errors.New(errMsg) // applies only if in test
 					}
-					// f.PC += 2                            // 2 == initial PC advance in this bytecode (see above)
-					f.PC += 1                            // to point to the next bytecode before exiting
-					fs.PushFront(fram)                   // push the new frame
-					f = fs.Front().Value.(*frames.Frame) // point f to the new head
-					goto frameInterpreter
+				}
 
-				*/
-				// for the nonce
-				errMsg := "INVOKEINTERFACE: WIP, forcing an error, for the nonce"
-				exceptions.ThrowEx(excNames.WrongMethodTypeException, errMsg, f)
+				f.ExceptionPC = f.PC                 // in the event of an exception, here's where we were
+				f.PC += 1                            // to point to the next bytecode before exiting
+				fs.PushFront(fram)                   // push the new frame
+				f = fs.Front().Value.(*frames.Frame) // point f to the new head
+				return runFrame(fs)
 			}
 		case opcodes.NEW: // 0xBB 	new: create and instantiate a new object
 			CPslot := (int(f.Meth[f.PC+1]) * 256) + int(f.Meth[f.PC+2]) // next 2 bytes point to CP entry
@@ -2845,30 +2945,17 @@ frameInterpreter:
 				}
 				_ = log.Log(msg, log.SEVERE)
 
-				steArrayPtr := objectRef.FieldTable["stackTrace"].Fvalue.(*object.Object)
-				rawSteArray := steArrayPtr.FieldTable["value"].Fvalue.([]*object.Object) // []*object.Object (each of which is an STE)
-				for i := 0; i < len(rawSteArray); i++ {
-					ste := rawSteArray[i]
-					methodName := ste.FieldTable["methodName"].Fvalue.(string)
-					if methodName == "<init>" { // don't show constructors
-						continue
-					}
-					rawClassName := ste.FieldTable["declaringClass"].Fvalue.(string)
-					if rawClassName == "java/lang/Throwable" { // don't show Throwable methods
-						continue
-					}
-					className := strings.Replace(rawClassName, "/", ".", -1)
+				for _, s := range gfunction.StackTraceLines(objectRef) {
+					_ = log.Log(s, log.SEVERE)
+				}
 
-					sourceLine := ste.FieldTable["sourceLine"].Fvalue.(string)
+				// print the exceptions suppressed by try-with-resources, if any
+				for _, s := range gfunction.SuppressedLines(objectRef) {
+					_ = log.Log(s, log.SEVERE)
+				}
 
-					var s string
-					if sourceLine != "" {
-						s = fmt.Sprintf("\tat %s.%s(%s:%s)", className,
-							methodName, ste.FieldTable["fileName"].Fvalue, sourceLine)
-					} else {
-						s = fmt.Sprintf("\tat %s.%s(%s)", className,
-							methodName, ste.FieldTable["fileName"].Fvalue)
-					}
+				// print the chain of causes, if any, each with its own stack trace
+				for _, s := range gfunction.CauseChainLines(objectRef) {
 					_ = log.Log(s, log.SEVERE)
 				}
 
@@ -2886,20 +2973,21 @@ frameInterpreter:
 				shutdown.Exit(shutdown.APP_EXCEPTION)
 
 			} else { // perform the catch operation. We know the frame and the starting bytecode for the handler
-				for fr := fs.Front(); fr != nil; fr = fr.Next() {
-					var frm = fr.Value.(*frames.Frame)
-					// f.ExceptionTable = &m.Exceptions
-					if frm == catchFrame {
-						// frm.Meth = f.Meth[handlerBytecode:]
-						frm.TOS = -1
-						push(frm, objectRef)
-						// frm.PC = 0
-						frm.PC = handlerBytecode
-						// make the frame with the catch block active
-						fs.Front().Value = frm
-						goto frameInterpreter
+				// pop the frames the exception unwinds, so that the frame with the catch block is
+				// active. As in ThrowEx(), a synchronized method gives up its monitor.
+				for fs.Front().Value.(*frames.Frame) != catchFrame {
+					frm := fs.Front().Value.(*frames.Frame)
+					if frm.GfunctionCall {
+						catchFrame.UnwoundGfunctionCalls++
 					}
+					frames.ReleaseMonitor(frm)
+					fs.Remove(fs.Front())
 				}
+				catchFrame.TOS = -1
+				push(catchFrame, objectRef)
+				catchFrame.PC = handlerBytecode
+				catchFrame.ExceptionPC = -1
+				goto frameInterpreter
 			}
 		case opcodes.CHECKCAST: // 0xC0 same as INSTANCEOF but throws exception on null
 			// because this uses the same logic as INSTANCEOF, any change here should
@@ -2995,7 +3083,7 @@ frameInterpreter:
 
 					if classPtr != classloader.MethAreaFetch(*(stringPool.GetStringPointer(obj.KlassName))) {
 						glob.ErrorGoStack = string(debug.Stack())
-						errMsg := fmt.Sprintf("CHECKCAST: %s is not castable with respect to %s", className, classPtr.Data.Name)
+						errMsg := classCastMessage(*(stringPool.GetStringPointer(obj.KlassName)), className)
 						status := exceptions.ThrowEx(excNames.ClassCastException, errMsg, f)
 						if status != exceptions.Caught {
 							return errors.New(errMsg) // applies only if in test
@@ -3063,12 +3151,66 @@ frameInterpreter:
 				}
 			}
 
-		case opcodes.MONITORENTER, opcodes.MONITOREXIT: // OxC2 and OxC3. These  are not implemented in the JDK JVM
-			_ = pop(f) // so just pop off the reference on the stack
+		case opcodes.MONITORENTER: // OxC2 enter the monitor of the object (the start of a synchronized block)
+			objRef, ok := pop(f).(*object.Object)
+			if !ok || object.IsNull(objRef) {
+				glob.ErrorGoStack = string(debug.Stack())
+				errMsg := "MONITORENTER: Invalid (null) object reference"
+				status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, f)
+				if status != exceptions.Caught {
+					return errors.New(errMsg) // applies only if in test
+				}
+				goto frameInterpreter // the exception was caught, so execute the catch block
+			}
+			objRef.MonitorEnter(f.Thread) // blocks until no other thread holds the monitor
+
+		case opcodes.MONITOREXIT: // OxC3 exit the monitor of the object (the end of a synchronized block)
+			objRef, ok := pop(f).(*object.Object)
+			if !ok || object.IsNull(objRef) {
+				glob.ErrorGoStack = string(debug.Stack())
+				errMsg := "MONITOREXIT: Invalid (null) object reference"
+				status := exceptions.ThrowEx(excNames.NullPointerException, errMsg, f)
+				if status != exceptions.Caught {
+					return errors.New(errMsg) // applies only if in test
+				}
+				goto frameInterpreter // the exception was caught, so execute the catch block
+			}
+			if err := objRef.MonitorExit(f.Thread); err != nil {
+				glob.ErrorGoStack = string(debug.Stack())
+				errMsg := "MONITOREXIT: " + err.Error()
+				status := exceptions.ThrowEx(excNames.IllegalMonitorStateException, errMsg, f)
+				if status != exceptions.Caught {
+					return errors.New(errMsg) // applies only if in test
+				}
+				goto frameInterpreter // the exception was caught, so execute the catch block
+			}
 
 		case opcodes.WIDE: // 0xC4 Make some bytecodes operate on larger sized operands
 			// https://docs.oracle.com/javase/specs/jvms/se17/html/jvms-6.html#jvms-6.5.wide
-			wideInEffect = true
+			// Only the loads, stores, IINC, and RET can be modified by WIDE.
+			var nextOpcode byte
+			if f.PC+1 < len(f.Meth) {
+				nextOpcode = f.Meth[f.PC+1]
+			}
+			switch nextOpcode {
+			case opcodes.ILOAD, opcodes.LLOAD, opcodes.FLOAD, opcodes.DLOAD, opcodes.ALOAD,
+				opcodes.ISTORE, opcodes.LSTORE, opcodes.FSTORE, opcodes.DSTORE, opcodes.ASTORE,
+				opcodes.IINC, opcodes.RET:
+				wideInEffect = true
+			default:
+				badOpcode := fmt.Sprintf("0x%X", nextOpcode)
+				if int(nextOpcode) < len(opcodes.BytecodeNames) {
+					badOpcode = fmt.Sprintf("%s (0x%X)", opcodes.BytecodeNames[nextOpcode], nextOpcode)
+				}
+				glob.ErrorGoStack = string(debug.Stack())
+				errMsg := fmt.Sprintf("WIDE: %s at location %d in %s.%s%s cannot be modified by WIDE",
+					badOpcode, f.PC+1, f.ClName, f.MethName, f.MethType)
+				status := exceptions.ThrowEx(excNames.VerifyError, errMsg, f)
+				if status != exceptions.Caught {
+					return errors.New(errMsg) // applies only if in test
+				}
+				goto frameInterpreter // the exception was caught, so execute the catch block
+			}
 
 		case opcodes.MULTIANEWARRAY: // 0xC5 create multi-dimensional array
 			var arrayDesc string
@@ -3205,6 +3347,13 @@ frameInterpreter:
 				f.Meth[f.PC+1], f.Meth[f.PC+2], f.Meth[f.PC+3], f.Meth[f.PC+4])
 			f.PC = f.PC + int(jumpTo) - 1 // -1 because this loop will increment f.PC by 1
 
+		case opcodes.JSR_W: // 0xC9 jump to subroutine at a four-byte offset from the current PC
+			jumpTo := fourBytesToInt64(
+				f.Meth[f.PC+1], f.Meth[f.PC+2], f.Meth[f.PC+3], f.Meth[f.PC+4])
+			// as in JSR, the return address is the last byte of this instruction
+			push(f, int64(f.PC+4))
+			f.PC = f.PC + int(jumpTo) - 1 // -1 because this loop will increment f.PC by 1
+
 		default:
 			missingOpCode := fmt.Sprintf("%d (0x%X)", opcode, opcode)
 
@@ -3271,6 +3420,7 @@ func createAndInitNewFrame(
 	fram.MethName = methodName
 	fram.MethType = methodType
 	fram.CP = m.Cp                           // add its pointer to the class CP
+	fram.LineTable = m.LineTable             // for the source line numbers in stack traces
 	fram.Meth = append(fram.Meth, m.Code...) // copy the method's bytecodes over
 
 	// pop the parameters off the present stack and put them in
@@ -3396,6 +3546,17 @@ func createAndInitNewFrame(
 		destLocal += 1
 	}
 
+	// a synchronized method holds a monitor for as long as it runs: an instance
+	// method locks its object; a static method locks its class.
+	if m.AccessFlags&0x0020 > 0 { // ACC_SYNCHRONIZED
+		if objRef, ok := fram.Locals[0].(*object.Object); includeObjectRef && ok && !object.IsNull(objRef) {
+			fram.Monitor = objRef
+		} else {
+			fram.Monitor = getClassMonitorObject(className)
+		}
+		fram.Monitor.MonitorEnter(fram.Thread)
+	}
+
 	fram.TOS = -1
 
 	return fram, nil
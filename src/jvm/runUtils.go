@@ -8,16 +8,22 @@ package jvm
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"jacobin/classloader"
 	"jacobin/excNames"
 	"jacobin/exceptions"
 	"jacobin/frames"
+	"jacobin/globals"
 	"jacobin/log"
 	"jacobin/object"
 	"jacobin/opcodes"
+	"jacobin/stringPool"
 	"jacobin/types"
 	"jacobin/util"
 	"math"
+	"runtime/debug"
+	"strings"
 	"unsafe"
 )
 
@@ -389,3 +395,97 @@ func push(f *frames.Frame, x interface{}) {
 	} // trace the resultant stack
 
 }
+
+// paramSlotCount returns the number of operand-stack slots taken by the parameters
+// of a method with the given type: longs and doubles take two slots, all others one.
+func paramSlotCount(methodType string) int {
+	slots := 0
+	for _, param := range util.ParseIncomingParamsFromMethTypeString(methodType) {
+		if param == types.Long || param == types.Double {
+			slots += 2
+		} else {
+			slots += 1
+		}
+	}
+	return slots
+}
+
+// runtimeClassOf returns the name of the class of an object reference on the operand stack,
+// or "" if the reference is null or is not to an object whose class is known.
+func runtimeClassOf(receiver interface{}) string {
+	obj, ok := receiver.(*object.Object)
+	if !ok || object.IsNull(obj) || obj.KlassName == types.InvalidStringIndex {
+		return ""
+	}
+	return *stringPool.GetStringPointer(obj.KlassName)
+}
+
+// classCastMessage returns the message of the ClassCastException thrown when an object
+// of class fromClass can't be cast to class toClass. The format is the JDK's.
+func classCastMessage(fromClass, toClass string) string {
+	return fmt.Sprintf("class %s cannot be cast to class %s",
+		util.ConvertInternalClassNameToUserFormat(fromClass), util.ConvertInternalClassNameToUserFormat(toClass))
+}
+
+// isAbstractMethod reports whether a method is a Java method that has no body, and so
+// whose invocation throws an AbstractMethodError.
+func isAbstractMethod(mtEntry classloader.MTentry) bool {
+	if mtEntry.MType != 'J' {
+		return false
+	}
+	m, ok := mtEntry.Meth.(classloader.JmEntry)
+	return ok && m.AccessFlags&0x0400 != 0
+}
+
+// resolveVirtualMethod does the dynamic dispatch for INVOKEVIRTUAL. The method ref names
+// the class of the receiver's static type (className), but the method to run is the one
+// declared closest to the receiver's runtime class. So, the runtime class and then its
+// superclasses are searched, up to className, for a class that declares the method, and
+// the name of that class is returned. If there is no such class (or the runtime class is
+// not one that's been loaded, such as an array class), className is returned. It is also
+// returned if className's method is private, as private methods are not overridden.
+func resolveVirtualMethod(className, methodName, methodType string, receiver interface{}) string {
+	runtimeClassName := runtimeClassOf(receiver)
+	if runtimeClassName == "" || runtimeClassName == className || strings.HasPrefix(runtimeClassName, types.Array) {
+		return className // arrays have only the methods of java.lang.Object
+	}
+
+	searchName := methodName + methodType
+	if staticClass := classloader.MethAreaFetch(className); staticClass != nil {
+		if m, ok := staticClass.Data.MethodTable[searchName]; ok && m.AccessFlags&0x0002 != 0 { // private
+			return className
+		}
+	}
+
+	for name := runtimeClassName; name != className; {
+		if classloader.MTable[name+"."+searchName].Meth != nil {
+			return name // includes gfunctions of classes that are never loaded
+		}
+		k := classloader.MethAreaFetch(name)
+		if k == nil {
+			break
+		}
+		if m, ok := k.Data.MethodTable[searchName]; ok && m.AccessFlags&0x0400 == 0 { // not abstract
+			return name
+		}
+		if name == types.ObjectClassName {
+			break
+		}
+		name = *stringPool.GetStringPointer(k.Data.SuperclassIndex)
+	}
+	return className
+}
+
+// throwLocalsIndexError throws a VerifyError for a bytecode whose local-variable index, along
+// with the second slot of a long or double stored there (width 2), is beyond the frame's local variables.
+// Returns nil if the error was caught, so the caller can resume with the catching frame.
+func throwLocalsIndexError(f *frames.Frame, opcode byte, index, width int) error {
+	globals.GetGlobalRef().ErrorGoStack = string(debug.Stack())
+	errMsg := fmt.Sprintf("in %s.%s, %s: local variable index %d is out of range (%d local slots)",
+		util.ConvertInternalClassNameToUserFormat(f.ClName), f.MethName, opcodes.BytecodeNames[opcode],
+		index+width-1, len(f.Locals))
+	if exceptions.ThrowEx(excNames.VerifyError, errMsg, f) != exceptions.Caught {
+		return errors.New(errMsg)
+	}
+	return nil
+}
@@ -6,7 +6,14 @@
 
 package jvm
 
-import "testing"
+import (
+	"jacobin/classloader"
+	"jacobin/globals"
+	"jacobin/object"
+	"jacobin/stringPool"
+	"jacobin/types"
+	"testing"
+)
 
 // tests for runUtils.go. Note that most functions are tested inside the tests for run.go,
 // but several benefit from standalone testing. Those are tested here
@@ -23,3 +30,75 @@ func TestConvertBoolByteToInt64(t *testing.T) {
 		t.Errorf("convertBoolByteToInt64(bool) != 1 (true), got %d", res)
 	}
 }
+
+func TestParamSlotCount(t *testing.T) {
+	tests := map[string]int{
+		"()V":                       0,
+		"(I)V":                      1,
+		"(JD)V":                     4,
+		"(Ljava/lang/String;J)I":    3,
+		"([J[[Ljava/lang/Object;)V": 2,
+	}
+	for methodType, expected := range tests {
+		if slots := paramSlotCount(methodType); slots != expected {
+			t.Errorf("paramSlotCount(%s): expected %d, got %d", methodType, expected, slots)
+		}
+	}
+}
+
+// makeTestClass puts a class with the given superclass and methods in the method area
+func makeTestClass(name, superclass string, methods map[string]int) {
+	methodTable := make(map[string]*classloader.Method)
+	for method, accessFlags := range methods {
+		methodTable[method] = &classloader.Method{AccessFlags: accessFlags}
+	}
+	classloader.MethAreaInsert(name, &classloader.Klass{
+		Status: 'X',
+		Loader: "bootstrap",
+		Data: &classloader.ClData{
+			Name:            name,
+			Superclass:      superclass,
+			SuperclassIndex: stringPool.GetStringIndex(&superclass),
+			MethodTable:     methodTable,
+		},
+	})
+}
+
+func TestResolveVirtualMethod(t *testing.T) {
+	globals.InitGlobals("test")
+	classloader.InitMethodArea()
+	classloader.MTable = make(map[string]classloader.MTentry)
+
+	// A declares m() and the private p(); B extends A and overrides both; C extends B
+	makeTestClass(types.ObjectClassName, "", map[string]int{})
+	makeTestClass("A", types.ObjectClassName, map[string]int{"m()V": 0x0001, "p()V": 0x0002})
+	makeTestClass("B", "A", map[string]int{"m()V": 0x0001, "p()V": 0x0002})
+	makeTestClass("C", "B", map[string]int{})
+
+	for _, test := range []struct {
+		runtimeClass string
+		method       string
+		expected     string
+	}{
+		{"A", "m", "A"},
+		{"B", "m", "B"}, // B's override is run through an A reference
+		{"C", "m", "B"}, // C inherits B's override
+		{"B", "p", "A"}, // private methods are not overridden
+	} {
+		receiver := object.MakeEmptyObjectWithClassName(&test.runtimeClass)
+		resolved := resolveVirtualMethod("A", test.method, "()V", receiver)
+		if resolved != test.expected {
+			t.Errorf("resolveVirtualMethod(A.%s() on a %s): expected %s, got %s",
+				test.method, test.runtimeClass, test.expected, resolved)
+		}
+	}
+
+	// receivers whose class is not in the method area use the class in the method ref
+	arrayClass := "[LA;"
+	if resolved := resolveVirtualMethod("A", "m", "()V", object.MakeEmptyObjectWithClassName(&arrayClass)); resolved != "A" {
+		t.Errorf("resolveVirtualMethod on an array: expected A, got %s", resolved)
+	}
+	if resolved := resolveVirtualMethod("A", "m", "()V", object.Null); resolved != "A" {
+		t.Errorf("resolveVirtualMethod on null: expected A, got %s", resolved)
+	}
+}
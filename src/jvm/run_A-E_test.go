@@ -7,12 +7,14 @@
 package jvm
 
 import (
+	"io"
 	"jacobin/classloader"
 	"jacobin/frames"
 	"jacobin/globals"
 	"jacobin/log"
 	"jacobin/object"
 	"jacobin/opcodes"
+	"jacobin/stringPool"
 	"jacobin/types"
 	"math"
 	"os"
@@ -64,6 +66,33 @@ func TestAconstNull(t *testing.T) {
 	}
 }
 
+// ACONST_NULL: the null it pushes can be stored in a local, loaded, and returned, and
+// reaches the caller as null
+func TestAconstNullStoredAndReturned(t *testing.T) {
+	globals.InitGlobals("test")
+	f0 := newFrame(0)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f0)
+
+	f1 := frames.CreateFrame(2)
+	f1.Meth = []byte{opcodes.ACONST_NULL, opcodes.ASTORE_1, opcodes.ALOAD_1, opcodes.ARETURN}
+	f1.Locals = []interface{}{zero, object.StringObjectFromGoString("not yet null")}
+	fs.PushFront(f1)
+	if err := runFrame(fs); err != nil {
+		t.Fatalf("ACONST_NULL: unexpected error: %s", err.Error())
+	}
+
+	if !object.IsNull(f1.Locals[1]) {
+		t.Errorf("ACONST_NULL: expected null to be stored in local 1, got: %v", f1.Locals[1])
+	}
+	if f0.TOS != 0 {
+		t.Fatalf("ARETURN: expected the caller's TOS to be 0, got: %d", f0.TOS)
+	}
+	if ret := pop(&f0); ret != object.Null {
+		t.Errorf("ARETURN: expected null to be returned as object.Null, got: %v", ret)
+	}
+}
+
 // ALOAD: test load of reference in locals[index] on to stack
 func TestAload(t *testing.T) {
 	f := newFrame(opcodes.ALOAD)
@@ -459,6 +488,62 @@ func TestCheckcastOfInvalidReference(t *testing.T) {
 	}
 }
 
+// CHECKCAST: casting a String to an Integer throws a ClassCastException whose
+// message, as in the JDK, names both classes
+func TestCheckcastStringToInteger(t *testing.T) {
+	g := globals.GetGlobalRef()
+	globals.InitGlobals("test")
+	g.JacobinName = "test" // prevents a shutdown when the exception hits.
+	log.Init()
+
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	for _, className := range []string{types.StringClassName, "java/lang/Integer"} {
+		classloader.MethAreaInsert(className,
+			&(classloader.Klass{
+				Status: 'X', // use a status that's not subsequently tested for.
+				Loader: "bootstrap",
+				Data:   &classloader.ClData{Name: className},
+			}))
+	}
+	s := object.StringObjectFromGoString("hello world")
+
+	f := newFrame(opcodes.CHECKCAST)
+	f.Meth = append(f.Meth, 0) // point to entry [1] in CP
+	f.Meth = append(f.Meth, 1) // " "
+
+	integerClassName := "java/lang/Integer"
+	CP := classloader.CPool{}
+	CP.CpIndex = make([]classloader.CpEntry, 10, 10)
+	CP.CpIndex[0] = classloader.CpEntry{Type: 0, Slot: 0}
+	CP.CpIndex[1] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 0}
+	CP.ClassRefs = append(CP.ClassRefs, stringPool.GetStringIndex(&integerClassName))
+	f.CP = &CP
+
+	push(&f, s)
+
+	// redirect stderr to avoid printing error message to console
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	err := runFrame(fs)
+
+	_ = w.Close()
+	msg, _ := io.ReadAll(r)
+	os.Stderr = normalStderr // restore stderr
+
+	expected := "class java.lang.String cannot be cast to class java.lang.Integer"
+	if err == nil || err.Error() != expected {
+		t.Errorf("CHECKCAST: Expected error %q, got: %v", expected, err)
+	}
+	if !strings.Contains(string(msg), "java.lang.ClassCastException") || !strings.Contains(string(msg), expected) {
+		t.Errorf("CHECKCAST: Expected a ClassCastException with message %q, got: %s", expected, string(msg))
+	}
+}
+
 // D2F: test convert double to float
 func TestD2f(t *testing.T) {
 	f := newFrame(opcodes.D2F)
@@ -776,6 +861,31 @@ func TestDconst1(t *testing.T) {
 	}
 }
 
+// DCONST_0 and DCONST_1: a double is a category-2 value, so it occupies two slots, each holding
+// the value as a float64
+func TestDconstBothSlots(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		opcode byte
+		value  float64
+	}{{"DCONST_0", opcodes.DCONST_0, 0.0}, {"DCONST_1", opcodes.DCONST_1, 1.0}} {
+		f := newFrame(test.opcode)
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		_ = runFrame(fs)
+
+		if f.TOS != 1 {
+			t.Errorf("%s: Expected top of stack to be 1, got: %d", test.name, f.TOS)
+			continue
+		}
+		for slot := 1; slot >= 0; slot-- {
+			if value, ok := pop(&f).(float64); !ok || value != test.value {
+				t.Errorf("%s: Expected slot %d to hold float64 %v, got: %v", test.name, slot, test.value, value)
+			}
+		}
+	}
+}
+
 // DDIV: double divide of.TOS-1 by tos, push result
 func TestDdiv(t *testing.T) {
 	f := newFrame(opcodes.DDIV)
@@ -998,6 +1108,37 @@ func TestDnegInf(t *testing.T) {
 	}
 }
 
+// DNEG: negating 0.0 yields -0.0, which differs from 0.0 in its bits (as by
+// Double.doubleToLongBits()), and negating NaN yields NaN
+func TestDnegZeroAndNaN(t *testing.T) {
+	for _, test := range []struct{ value, expected float64 }{
+		{0.0, math.Copysign(0, -1)}, {math.Copysign(0, -1), 0.0}, {math.NaN(), math.NaN()},
+	} {
+		f := newFrame(opcodes.DNEG)
+		push(&f, test.value)
+		push(&f, test.value)
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		_ = runFrame(fs)
+
+		pop(&f)
+		value := pop(&f).(float64)
+		if math.IsNaN(test.expected) {
+			if !math.IsNaN(value) {
+				t.Errorf("DNEG: Expected negating NaN to yield NaN, got: %f", value)
+			}
+		} else if math.Float64bits(value) != math.Float64bits(test.expected) {
+			t.Errorf("DNEG: Expected negating %f to yield bits 0x%016X, got: 0x%016X", test.value,
+				math.Float64bits(test.expected), math.Float64bits(value))
+		}
+
+		if f.TOS != -1 {
+			t.Errorf("DNEG, Top of stack, expected -1, got: %d", f.TOS)
+		}
+	}
+}
+
 // DREM: remainder of float division (the % operator)
 func TestDrem(t *testing.T) {
 	f := newFrame(opcodes.DREM)
@@ -1235,6 +1376,51 @@ func TestDup2(t *testing.T) {
 	}
 }
 
+// DUP2: a long occupies two slots, so duplicating the top two slots duplicates the long
+func TestDup2Long(t *testing.T) {
+	f := newFrame(opcodes.DUP2)
+	push(&f, int64(7)) // an int below the long, which is not duplicated
+	push(&f, int64(0x123456789A))
+	push(&f, int64(0x123456789A))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	if f.TOS != 4 {
+		t.Errorf("DUP2: expected the int and two longs (five slots), got tos: %d", f.TOS)
+	}
+	for i := 0; i < 2; i++ { // each long is popped as two slots
+		pop(&f)
+		if long := pop(&f).(int64); long != 0x123456789A {
+			t.Errorf("DUP2: expected the long 0x123456789A, got: 0x%X", long)
+		}
+	}
+	if val := pop(&f).(int64); val != 7 {
+		t.Errorf("DUP2: expected the int below the long to be unchanged, got: %d", val)
+	}
+}
+
+// DUP2: likewise for a double
+func TestDup2Double(t *testing.T) {
+	f := newFrame(opcodes.DUP2)
+	push(&f, 2.5)
+	push(&f, 2.5)
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	if f.TOS != 3 {
+		t.Errorf("DUP2: expected two doubles (four slots), got tos: %d", f.TOS)
+	}
+	for i := 0; i < 4; i++ {
+		if val := pop(&f).(float64); val != 2.5 {
+			t.Errorf("DUP2: expected each slot to hold 2.5, got: %f", val)
+		}
+	}
+}
+
 // DUP_X1: Duplicate the top stack value and insert it two slots down
 func TestDupX1(t *testing.T) {
 	f := newFrame(opcodes.DUP_X1)
@@ -441,6 +441,31 @@ func TestFneg(t *testing.T) {
 	}
 }
 
+// FNEG: negating zero flips its sign, as seen in its bits (as by Float.floatToIntBits()),
+// and negating NaN yields NaN
+func TestFnegZeroAndNaN(t *testing.T) {
+	for _, test := range []struct{ value, expected float64 }{
+		{0.0, math.Copysign(0, -1)}, {math.Copysign(0, -1), 0.0}, {2.5, -2.5}, {math.NaN(), math.NaN()},
+	} {
+		f := newFrame(opcodes.FNEG)
+		push(&f, test.value)
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		_ = runFrame(fs)
+
+		value := pop(&f).(float64)
+		if math.IsNaN(test.expected) {
+			if !math.IsNaN(value) {
+				t.Errorf("FNEG: Expected negating NaN to yield NaN, got: %f", value)
+			}
+		} else if math.Float32bits(float32(value)) != math.Float32bits(float32(test.expected)) {
+			t.Errorf("FNEG: Expected negating %f to yield bits 0x%08X, got: 0x%08X", test.value,
+				math.Float32bits(float32(test.expected)), math.Float32bits(float32(value)))
+		}
+	}
+}
+
 // FREM: remainder of float division (the % operator)
 func TestFrem(t *testing.T) {
 	f := newFrame(opcodes.FREM)
@@ -835,6 +860,71 @@ func TestGotowBackward(t *testing.T) {
 	}
 }
 
+// GOTO_W: jump backward by an offset too large to fit in GOTO's two bytes
+func TestGotowBackwardLargeOffset(t *testing.T) {
+	const distance = 70000 // > 32767, the largest GOTO offset
+	f := newFrame(opcodes.RETURN)
+	for len(f.Meth) < distance {
+		f.Meth = append(f.Meth, opcodes.NOP)
+	}
+	f.Meth = append(f.Meth, opcodes.GOTO_W)
+	offset := int32(-distance)
+	f.Meth = append(f.Meth, byte(offset>>24), byte(offset>>16), byte(offset>>8), byte(offset))
+	f.Meth = append(f.Meth, opcodes.BIPUSH)
+	f.PC = distance // start at the GOTO_W
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	if f.PC != 0 || f.Meth[f.PC] != opcodes.RETURN {
+		t.Errorf("GOTO_W backward large offset: Expected PC to be 0 (RETURN), but got PC %d: %s",
+			f.PC, opcodes.BytecodeNames[f.Meth[f.PC]])
+	}
+}
+
+// JSR_W: jump to a subroutine at a large offset, which stores the return address
+// and returns to the instruction following the JSR_W with RET
+func TestJsrwAndRet(t *testing.T) {
+	const distance = 70000
+	f := newFrame(opcodes.JSR_W)
+	offset := int32(distance)
+	f.Meth = append(f.Meth, byte(offset>>24), byte(offset>>16), byte(offset>>8), byte(offset))
+	f.Meth = append(f.Meth, opcodes.RETURN) // at 5, the instruction after the JSR_W
+	for len(f.Meth) < distance {
+		f.Meth = append(f.Meth, opcodes.NOP)
+	}
+	f.Meth = append(f.Meth, opcodes.ASTORE_1) // the subroutine: store the return address
+	f.Meth = append(f.Meth, opcodes.RET, 0x01)
+	f.Locals = append(f.Locals, int64(0), int64(0))
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	if f.PC != 5 || f.Meth[f.PC] != opcodes.RETURN {
+		t.Errorf("JSR_W: Expected PC to be 5 (RETURN), but got PC %d: %s",
+			f.PC, opcodes.BytecodeNames[f.Meth[f.PC]])
+	}
+	if f.TOS != -1 {
+		t.Errorf("JSR_W: Expected an empty stack, but got a tos of: %d", f.TOS)
+	}
+}
+
+// JSR: jump to a subroutine and return to the instruction following the JSR with RET
+func TestJsrAndRet(t *testing.T) {
+	f := newFrame(opcodes.JSR)
+	f.Meth = append(f.Meth, 0x00, 0x05)
+	f.Meth = append(f.Meth, opcodes.RETURN) // at 3, the instruction after the JSR
+	f.Meth = append(f.Meth, opcodes.NOP)
+	f.Meth = append(f.Meth, opcodes.ASTORE_1) // at 5, the subroutine
+	f.Meth = append(f.Meth, opcodes.RET, 0x01)
+	f.Locals = append(f.Locals, int64(0), int64(0))
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	if f.PC != 3 || f.Meth[f.PC] != opcodes.RETURN {
+		t.Errorf("JSR: Expected PC to be 3 (RETURN), but got PC %d: %s",
+			f.PC, opcodes.BytecodeNames[f.Meth[f.PC]])
+	}
+}
+
 // I2B: convert int to Java char (16-bit value)
 func TestI2B(t *testing.T) {
 	f := newFrame(opcodes.I2B)
@@ -922,6 +1012,82 @@ func TestI2f(t *testing.T) {
 	}
 }
 
+// I2F: Integer.MAX_VALUE has more significant bits than a float can hold,
+// so it's rounded to the nearest float, 2.14748365E9 (that is, 2^31)
+func TestI2fMaxIntRounds(t *testing.T) {
+	f := newFrame(opcodes.I2F)
+	push(&f, int64(math.MaxInt32))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	value := pop(&f).(float64)
+	if value != float64(float32(2.1474836e9)) {
+		t.Errorf("I2F: expected a result of 2.14748365E9, but got: %f", value)
+	}
+}
+
+// I2F: rounding is to the nearest float and preserves the sign
+func TestI2fNegativeRounds(t *testing.T) {
+	f := newFrame(opcodes.I2F)
+	push(&f, int64(-16777217)) // -(2^24 + 1): halfway between two floats, so rounds to even
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	value := pop(&f).(float64)
+	if value != -16777216.0 {
+		t.Errorf("I2F: expected a result of -16777216.0, but got: %f", value)
+	}
+}
+
+// I2D: every int fits exactly in a double, including Integer.MIN_VALUE
+func TestI2dMinIntExact(t *testing.T) {
+	f := newFrame(opcodes.I2D)
+	push(&f, int64(math.MinInt32))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	value := pop(&f).(float64)
+	if value != -2147483648.0 {
+		t.Errorf("I2D: expected a result of -2147483648.0, but got: %f", value)
+	}
+	if pop(&f).(float64) != value {
+		t.Errorf("I2D: expected both slots of the double to hold the same value")
+	}
+}
+
+// I2L: the sign of a negative int is preserved
+func TestI2lNegative(t *testing.T) {
+	f := newFrame(opcodes.I2L)
+	push(&f, int64(math.MinInt32))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	value := pop(&f).(int64)
+	if value != math.MinInt32 {
+		t.Errorf("I2L: expected a result of %d, but got: %d", math.MinInt32, value)
+	}
+}
+
+// F2D: a float converted to a double keeps exactly the float's value
+func TestF2dKeepsFloatValue(t *testing.T) {
+	f := newFrame(opcodes.F2D)
+	floatVal := float64(float32(0.1)) // as an FLOAD of 0.1f would push it
+	push(&f, floatVal)
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	val := pop(&f).(float64)
+	if val != floatVal || val == 0.1 {
+		t.Errorf("F2D: expected a result of %.17g, but got: %.17g", floatVal, val)
+	}
+}
+
 // I2L: Convert int to long
 // Note that since ints in Jacobin are int64--which is the same size as a long--
 // so no conversion takes place. However, while ints are stored in one opStack
@@ -1010,6 +1176,20 @@ func TestIdiv(t *testing.T) {
 	}
 }
 
+// IDIV: Integer.MIN_VALUE / -1 overflows, and so wraps to Integer.MIN_VALUE
+func TestIdivMinValueByMinusOne(t *testing.T) {
+	f := newFrame(opcodes.IDIV)
+	push(&f, int64(math.MinInt32))
+	push(&f, int64(-1))
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	value := pop(&f).(int64)
+	if value != math.MinInt32 {
+		t.Errorf("IDIV: expected MIN_VALUE / -1 to be %d, but got: %d", math.MinInt32, value)
+	}
+}
+
 // IDIV: Testing the exception is done in TestHexIDIVexception.go
 
 // ICONST_M1:
@@ -1117,6 +1297,44 @@ func TestIconst5(t *testing.T) {
 	}
 }
 
+// Each of the opcodes that push an int or float constant must push a value of exactly the right
+// type: int64 for ints, float64 for floats. (For longs and doubles, see TestLconstBothSlots and
+// TestDconstBothSlots.)
+func TestConstOpcodesPushExactValues(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		opcode byte
+		value  interface{}
+		slots  int
+	}{
+		{"ICONST_M1", opcodes.ICONST_M1, int64(-1), 1},
+		{"ICONST_0", opcodes.ICONST_0, int64(0), 1},
+		{"ICONST_1", opcodes.ICONST_1, int64(1), 1},
+		{"ICONST_2", opcodes.ICONST_2, int64(2), 1},
+		{"ICONST_3", opcodes.ICONST_3, int64(3), 1},
+		{"ICONST_4", opcodes.ICONST_4, int64(4), 1},
+		{"ICONST_5", opcodes.ICONST_5, int64(5), 1},
+		{"FCONST_0", opcodes.FCONST_0, float64(0), 1},
+		{"FCONST_1", opcodes.FCONST_1, float64(1), 1},
+		{"FCONST_2", opcodes.FCONST_2, float64(2), 1},
+	} {
+		f := newFrame(test.opcode)
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		_ = runFrame(fs)
+
+		if f.TOS != test.slots-1 {
+			t.Errorf("%s: Expected top of stack to be %d, got: %d", test.name, test.slots-1, f.TOS)
+			continue
+		}
+		for slot := 0; slot < test.slots; slot++ {
+			if value := pop(&f); value != test.value {
+				t.Errorf("%s: Expected %T %v, got: %T %v", test.name, test.value, test.value, value, value)
+			}
+		}
+	}
+}
+
 // IF_ACMPEQ: jump if two addresses are equal
 func TestIfAcmpEq(t *testing.T) {
 	f := newFrame(opcodes.IF_ACMPEQ)
@@ -1446,6 +1664,59 @@ func TestIfIcmpneAreEqual(t *testing.T) {
 	}
 }
 
+// IF_ICMPxx: the comparisons are of 32-bit signed ints, so they must hold at the
+// boundaries of the int range, and any bits above the low 32 must be ignored.
+func TestIfIcmpAtIntBoundaries(t *testing.T) {
+	const maxInt, minInt = int64(math.MaxInt32), int64(math.MinInt32)
+	for _, test := range []struct {
+		name       string
+		opcode     byte
+		val1, val2 int64
+		jump       bool
+	}{
+		{"IF_ICMPEQ", opcodes.IF_ICMPEQ, maxInt, maxInt, true},
+		{"IF_ICMPEQ", opcodes.IF_ICMPEQ, minInt, maxInt, false},
+		{"IF_ICMPNE", opcodes.IF_ICMPNE, minInt, maxInt, true},
+		{"IF_ICMPNE", opcodes.IF_ICMPNE, minInt, minInt, false},
+		{"IF_ICMPLT", opcodes.IF_ICMPLT, minInt, maxInt, true},
+		{"IF_ICMPLT", opcodes.IF_ICMPLT, maxInt, minInt, false},
+		{"IF_ICMPLT", opcodes.IF_ICMPLT, maxInt, maxInt, false},
+		{"IF_ICMPGE", opcodes.IF_ICMPGE, maxInt, minInt, true},
+		{"IF_ICMPGE", opcodes.IF_ICMPGE, minInt, minInt, true},
+		{"IF_ICMPGE", opcodes.IF_ICMPGE, minInt, maxInt, false},
+		{"IF_ICMPGT", opcodes.IF_ICMPGT, maxInt, minInt, true},
+		{"IF_ICMPGT", opcodes.IF_ICMPGT, maxInt, maxInt, false},
+		{"IF_ICMPGT", opcodes.IF_ICMPGT, minInt, maxInt, false},
+		{"IF_ICMPLE", opcodes.IF_ICMPLE, minInt, maxInt, true},
+		{"IF_ICMPLE", opcodes.IF_ICMPLE, maxInt, maxInt, true},
+		{"IF_ICMPLE", opcodes.IF_ICMPLE, maxInt, minInt, false},
+		// values whose upper bits differ from the sign extension of their low 32 bits
+		{"IF_ICMPEQ", opcodes.IF_ICMPEQ, 1 << 32, 0, true},
+		{"IF_ICMPLT", opcodes.IF_ICMPLT, maxInt + 1, 0, true},
+		{"IF_ICMPGE", opcodes.IF_ICMPGE, maxInt + 1, 0, false},
+		{"IF_ICMPGT", opcodes.IF_ICMPGT, 0, minInt - 1, false},
+		{"IF_ICMPLE", opcodes.IF_ICMPLE, 0, minInt - 1, true},
+	} {
+		f := newFrame(test.opcode)
+		push(&f, test.val1)
+		push(&f, test.val2)
+
+		// a jump skips the ICONST_0 and executes only the ICONST_1
+		f.Meth = append(f.Meth, 0, 4, opcodes.ICONST_0, opcodes.ICONST_1)
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		if err := runFrame(fs); err != nil {
+			t.Errorf("%s %d, %d: Got unexpected error: %s", test.name, test.val1, test.val2, err.Error())
+			continue
+		}
+
+		jumped := f.TOS == 0
+		if jumped != test.jump {
+			t.Errorf("%s %d, %d: Expected jump to be %v, got %v", test.name, test.val1, test.val2, test.jump, jumped)
+		}
+	}
+}
+
 // IFEQ: jump if int popped off TOS is = 0
 func TestIfeq(t *testing.T) {
 	f := newFrame(opcodes.IFEQ)
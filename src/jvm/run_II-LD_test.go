@@ -7,14 +7,18 @@
 package jvm
 
 import (
+	"container/list"
 	"jacobin/classloader"
 	"jacobin/frames"
+	"jacobin/gfunction"
 	"jacobin/globals"
 	"jacobin/log"
 	"jacobin/object"
 	"jacobin/opcodes"
 	"jacobin/stringPool"
+	"jacobin/thread"
 	"jacobin/types"
+	"math"
 	"os"
 	"strings"
 	"testing"
@@ -90,6 +94,63 @@ func TestIload(t *testing.T) {
 	}
 }
 
+// ILOAD with an index beyond the frame's local variables: a VerifyError, not a Go panic
+func TestIloadIndexBeyondLocals(t *testing.T) {
+	globals.InitGlobals("test")
+
+	f := newFrame(opcodes.ILOAD)
+	f.Meth = append(f.Meth, 0x04) // local var #4, but there are only two
+	f.Locals = append(f.Locals, zero, zero)
+
+	// redirect stderr to avoid printing error message to console
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	err := runFrame(fs)
+
+	_ = w.Close()
+	os.Stderr = normalStderr // restore stderr
+
+	if err == nil {
+		t.Fatal("ILOAD: Expected an error for an index beyond the locals, but got none")
+	}
+	if !strings.Contains(err.Error(), "ILOAD: local variable index 4 is out of range (2 local slots)") {
+		t.Errorf("ILOAD: Unexpected error message: %s", err.Error())
+	}
+	if f.TOS != -1 {
+		t.Errorf("ILOAD: Expecting an empty stack, but tos points to item: %d", f.TOS)
+	}
+}
+
+// LSTORE whose second slot is beyond the frame's local variables
+func TestLstoreSecondSlotBeyondLocals(t *testing.T) {
+	globals.InitGlobals("test")
+
+	f := newFrame(opcodes.LSTORE)
+	f.Meth = append(f.Meth, 0x01) // locals 1 and 2, but there are only two
+	f.Locals = append(f.Locals, zero, zero)
+	push(&f, int64(5))
+	push(&f, int64(5))
+
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	err := runFrame(fs)
+
+	_ = w.Close()
+	os.Stderr = normalStderr // restore stderr
+
+	if err == nil || !strings.Contains(err.Error(), "LSTORE: local variable index 2 is out of range") {
+		t.Errorf("LSTORE: Expected an out-of-range error for local 2, got: %v", err)
+	}
+}
+
 // ILOAD_0: load of int in locals[0] onto stack
 func TestIload0(t *testing.T) {
 	f := newFrame(opcodes.ILOAD_0)
@@ -305,6 +366,234 @@ func TestInvokevirtualInvalid(t *testing.T) {
 	os.Stderr = normalStderr
 }
 
+// INVOKEVIRTUAL: invoking an abstract method (one with no implementation) throws an AbstractMethodError
+func TestInvokevirtualAbstractMethod(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+
+	// redirect stderr so as not to pollute the test output with the expected error message
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+
+	className := "test/AbstractShape"
+	classloader.MTable = make(map[string]classloader.MTentry)
+	classloader.MTable[className+".name()V"] = classloader.MTentry{
+		Meth:  classloader.JmEntry{AccessFlags: 0x0401}, // public abstract
+		MType: 'J',
+	}
+
+	CP := classloader.CPool{}
+	CP.CpIndex = make([]classloader.CpEntry, 10)
+	CP.CpIndex[0] = classloader.CpEntry{Type: 0, Slot: 0}
+	CP.CpIndex[1] = classloader.CpEntry{Type: classloader.MethodRef, Slot: 0}
+	CP.CpIndex[2] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 0}
+	CP.CpIndex[3] = classloader.CpEntry{Type: classloader.NameAndType, Slot: 0}
+	CP.CpIndex[4] = classloader.CpEntry{Type: classloader.UTF8, Slot: 0}
+	CP.CpIndex[5] = classloader.CpEntry{Type: classloader.UTF8, Slot: 1}
+	CP.MethodRefs = []classloader.MethodRefEntry{{ClassIndex: 2, NameAndType: 3}}
+	CP.ClassRefs = append(CP.ClassRefs, stringPool.GetStringIndex(&className))
+	CP.Utf8Refs = append(CP.Utf8Refs, "name", "()V")
+	CP.NameAndTypes = append(CP.NameAndTypes, classloader.NameAndTypeEntry{NameIndex: 4, DescIndex: 5})
+
+	f := newFrame(opcodes.INVOKEVIRTUAL)
+	f.Meth = append(f.Meth, 0x00)
+	f.Meth = append(f.Meth, 0x01) // Go to slot 0x0001 in the CP
+	f.CP = &CP
+	push(&f, object.MakeEmptyObjectWithClassName(&className))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	err := runFrame(fs)
+
+	// restore stderr
+	_ = w.Close()
+	os.Stderr = normalStderr
+
+	if err == nil {
+		t.Errorf("INVOKEVIRTUAL: Expected an AbstractMethodError but did not get one.")
+	} else if !strings.Contains(err.Error(), "Method has no implementation") {
+		t.Errorf("INVOKEVIRTUAL: Did not get expected error message, got: %s", err.Error())
+	}
+}
+
+// INVOKEVIRTUAL and INVOKEINTERFACE: when the AbstractMethodError is caught, execution resumes at
+// the handler, here: POP (the error); ICONST_5; RETURN
+func TestInvokeAbstractMethodErrorCaught(t *testing.T) {
+	globals.InitGlobals("testWithoutShutdown")
+	log.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	gl := globals.GetGlobalRef()
+	gl.FuncInstantiateClass = func(name string, _ *list.List) (any, error) {
+		return object.MakeEmptyObjectWithClassName(&name), nil
+	}
+
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() {
+		_ = w.Close()
+		os.Stderr = normalStderr
+	}()
+
+	shapeName := "test/Shape" // an interface, implemented by test/AbstractShape, which is abstract
+	className := "test/AbstractShape"
+	errorName := "java/lang/AbstractMethodError"
+	makeTestClass(types.ObjectClassName, "", map[string]int{})
+	makeTestClass(shapeName, types.ObjectClassName, map[string]int{"name()V": 0x0401})
+	makeTestClass(className, types.ObjectClassName, map[string]int{"name()V": 0x0401})
+	classloader.MethAreaFetch(className).Data.Interfaces = []uint16{uint16(stringPool.GetStringIndex(&shapeName))}
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	classloader.MTable[className+".name()V"] = classloader.MTentry{
+		Meth:  classloader.JmEntry{AccessFlags: 0x0401}, // public abstract
+		MType: 'J',
+	}
+
+	tests := []struct {
+		opcode    byte
+		refType   uint16
+		classRef  string
+		operands  []byte
+		handlerPC int
+	}{
+		{opcodes.INVOKEVIRTUAL, classloader.MethodRef, className, []byte{0x00, 0x01}, 3},
+		{opcodes.INVOKEINTERFACE, classloader.Interface, shapeName, []byte{0x00, 0x01, 0x01, 0x00}, 5},
+	}
+	for _, test := range tests {
+		CP := classloader.CPool{}
+		CP.CpIndex = make([]classloader.CpEntry, 7)
+		CP.CpIndex[0] = classloader.CpEntry{Type: 0, Slot: 0}
+		CP.CpIndex[1] = classloader.CpEntry{Type: test.refType, Slot: 0}
+		CP.CpIndex[2] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 0}
+		CP.CpIndex[3] = classloader.CpEntry{Type: classloader.NameAndType, Slot: 0}
+		CP.CpIndex[4] = classloader.CpEntry{Type: classloader.UTF8, Slot: 0}
+		CP.CpIndex[5] = classloader.CpEntry{Type: classloader.UTF8, Slot: 1}
+		CP.CpIndex[6] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 1}
+		CP.MethodRefs = []classloader.MethodRefEntry{{ClassIndex: 2, NameAndType: 3}}
+		CP.InterfaceRefs = []classloader.InterfaceRefEntry{{ClassIndex: 2, NameAndType: 3}}
+		CP.ClassRefs = []uint32{stringPool.GetStringIndex(&test.classRef), stringPool.GetStringIndex(&errorName)}
+		CP.Utf8Refs = []string{"name", "()V"}
+		CP.NameAndTypes = []classloader.NameAndTypeEntry{{NameIndex: 4, DescIndex: 5}}
+
+		// the caller, test/Caller.call(), catches the AbstractMethodError thrown by its invocation
+		code := append([]byte{test.opcode}, test.operands...)
+		code = append(code, opcodes.POP, opcodes.ICONST_5, opcodes.RETURN)
+		classloader.MTable["test/Caller.call()V"] = classloader.MTentry{
+			Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 2, MaxLocals: 1, Code: code, Cp: &CP,
+				Exceptions: []classloader.CodeException{{StartPc: 0, EndPc: test.handlerPC, HandlerPc: test.handlerPC, CatchType: 6}}},
+			MType: 'J',
+		}
+
+		th := thread.CreateThread()
+		th.AddThreadToTable(gl)
+		f := frames.CreateFrame(2)
+		f.ClName, f.MethName, f.MethType, f.CP, f.Meth = "test/Caller", "call", "()V", &CP, code
+		f.Thread = th.ID
+		push(f, object.MakeEmptyObjectWithClassName(&className))
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(f)
+		th.Stack = fs
+		if err := runFrame(fs); err != nil {
+			t.Errorf("%s: Unexpected error: %s", opcodes.BytecodeNames[test.opcode], err.Error())
+			continue
+		}
+		if fs.Len() != 1 || f.OpStack[0] != int64(5) {
+			t.Errorf("%s: Expected the handler to run, got frames=%d, stack=%v",
+				opcodes.BytecodeNames[test.opcode], fs.Len(), f.OpStack)
+		}
+	}
+}
+
+// INVOKEINTERFACE: a Map that defines only get() and put() inherits Map's default getOrDefault(),
+// here the gfunction, which calls the map's own get(), a Java method
+func TestInvokeinterfaceMapDefaultMethod(t *testing.T) {
+	globals.InitGlobals("test")
+	log.Init()
+	classloader.InitMethodArea()
+	defer classloader.InitMethodArea()
+	globals.GetGlobalRef().FuncInvokeMethod = invokeMethodFromGfunction
+
+	// redirect stderr, as INVOKEINTERFACE's attempt to load test/UserMap from a jmod logs an error
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() {
+		_ = w.Close()
+		os.Stderr = normalStderr
+	}()
+
+	classloader.MTable = make(map[string]classloader.MTentry)
+	gfunction.MTableLoadGFunctions(&classloader.MTable)
+
+	// test/UserMap's get(key) returns the key itself; put() returns null, and containsKey() false
+	mapName := "java/util/Map"
+	userMapName := "test/UserMap"
+	getType := "(Ljava/lang/Object;)Ljava/lang/Object;"
+	putType := "(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+	containsKeyType := "(Ljava/lang/Object;)Z"
+	getOrDefaultType := "(Ljava/lang/Object;Ljava/lang/Object;)Ljava/lang/Object;"
+	makeTestClass(types.ObjectClassName, "", map[string]int{})
+	makeTestClass(mapName, types.ObjectClassName,
+		map[string]int{"get" + getType: 0x0401, "put" + putType: 0x0401, "getOrDefault" + getOrDefaultType: 0x0001})
+	makeTestClass(userMapName, types.ObjectClassName,
+		map[string]int{"get" + getType: 0x0001, "put" + putType: 0x0001, "containsKey" + containsKeyType: 0x0001})
+	classloader.MethAreaFetch(userMapName).Data.Interfaces = []uint16{uint16(stringPool.GetStringIndex(&mapName))}
+	classloader.MTable[userMapName+".get"+getType] = classloader.MTentry{
+		Meth:  classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 1, MaxLocals: 2, Code: []byte{opcodes.ALOAD_1, opcodes.ARETURN}},
+		MType: 'J',
+	}
+	classloader.MTable[userMapName+".put"+putType] = classloader.MTentry{
+		Meth:  classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 1, MaxLocals: 3, Code: []byte{opcodes.ACONST_NULL, opcodes.ARETURN}},
+		MType: 'J',
+	}
+	classloader.MTable[userMapName+".containsKey"+containsKeyType] = classloader.MTentry{
+		Meth:  classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 1, MaxLocals: 2, Code: []byte{opcodes.ICONST_0, opcodes.IRETURN}},
+		MType: 'J',
+	}
+
+	CP := classloader.CPool{}
+	CP.CpIndex = make([]classloader.CpEntry, 6)
+	CP.CpIndex[0] = classloader.CpEntry{Type: 0, Slot: 0}
+	CP.CpIndex[1] = classloader.CpEntry{Type: classloader.Interface, Slot: 0}
+	CP.CpIndex[2] = classloader.CpEntry{Type: classloader.ClassRef, Slot: 0}
+	CP.CpIndex[3] = classloader.CpEntry{Type: classloader.NameAndType, Slot: 0}
+	CP.CpIndex[4] = classloader.CpEntry{Type: classloader.UTF8, Slot: 0}
+	CP.CpIndex[5] = classloader.CpEntry{Type: classloader.UTF8, Slot: 1}
+	CP.InterfaceRefs = []classloader.InterfaceRefEntry{{ClassIndex: 2, NameAndType: 3}}
+	CP.ClassRefs = append(CP.ClassRefs, stringPool.GetStringIndex(&mapName))
+	CP.Utf8Refs = append(CP.Utf8Refs, "getOrDefault", getOrDefaultType)
+	CP.NameAndTypes = append(CP.NameAndTypes, classloader.NameAndTypeEntry{NameIndex: 4, DescIndex: 5})
+
+	for _, test := range []struct {
+		key      *object.Object
+		expected string
+	}{
+		{object.StringObjectFromGoString("present"), "present"},
+		{object.Null, "default"},
+	} {
+		f := newFrame(opcodes.INVOKEINTERFACE)
+		f.Meth = append(f.Meth, 0x00, 0x01, 0x03, 0x00, opcodes.RETURN) // CP slot 1, 3 arg slots
+		f.CP = &CP
+		push(&f, object.MakeEmptyObjectWithClassName(&userMapName))
+		push(&f, test.key)
+		push(&f, object.StringObjectFromGoString("default"))
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		if err := runFrame(fs); err != nil {
+			t.Fatalf("INVOKEINTERFACE: Unexpected error calling getOrDefault(): %s", err.Error())
+		}
+
+		ret, ok := f.OpStack[0].(*object.Object)
+		if !ok || object.GoStringFromStringObject(ret) != test.expected {
+			t.Errorf("INVOKEINTERFACE: Expected getOrDefault() to return %q, got: %v", test.expected, f.OpStack[0])
+		}
+	}
+}
+
 // IOR: Logical OR of two ints
 func TestIor(t *testing.T) {
 	f := newFrame(opcodes.IOR)
@@ -345,6 +634,22 @@ func TestIrem(t *testing.T) {
 	}
 }
 
+// IREM: the remainder of Integer.MIN_VALUE / -1 is 0
+func TestIremMinValueByMinusOne(t *testing.T) {
+	f := newFrame(opcodes.IREM)
+	push(&f, int64(math.MinInt32))
+	push(&f, int64(-1))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	value := pop(&f).(int64)
+	if value != 0 {
+		t.Errorf("IREM: Expected MIN_VALUE %% -1 to be 0, got: %d", value)
+	}
+}
+
 // IREM: int modulo -- divide by zero
 // Because this test requires a full class set up due to IREM now throwing a full exception,
 // the test code has been moved to ThrowIREMexception.go in wholeClassTests.
@@ -626,6 +931,61 @@ func TestL2f(t *testing.T) {
 	}
 }
 
+// L2D: Long.MAX_VALUE has more significant bits than a double can hold,
+// so it's rounded to the nearest double, 9.223372036854775807E18 (that is, 2^63)
+func TestL2dMaxLongRounds(t *testing.T) {
+	f := newFrame(opcodes.L2D)
+	push(&f, int64(math.MaxInt64)) // longs require two slots, so pushed twice
+	push(&f, int64(math.MaxInt64))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	val := pop(&f).(float64)
+	if val != 9223372036854775808.0 {
+		t.Errorf("L2D: expected a result of 9.223372036854775807E18, but got: %f", val)
+	}
+	if f.TOS != 0 {
+		t.Errorf("L2D: Expected stack with 1 item, but got a TOS of: %d", f.TOS)
+	}
+}
+
+// L2D: rounding is to the nearest double and preserves the sign
+func TestL2dNegativeRounds(t *testing.T) {
+	f := newFrame(opcodes.L2D)
+	push(&f, int64(-9007199254740993)) // -(2^53 + 1): halfway between two doubles, so rounds to even
+	push(&f, int64(-9007199254740993))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	val := pop(&f).(float64)
+	if val != -9007199254740992.0 {
+		t.Errorf("L2D: expected a result of -9007199254740992.0, but got: %f", val)
+	}
+}
+
+// L2F: Long.MAX_VALUE rounds to the nearest float, 9.223372E18 (that is, 2^63)
+func TestL2fMaxLongRounds(t *testing.T) {
+	f := newFrame(opcodes.L2F)
+	push(&f, int64(math.MaxInt64)) // longs require two slots, so pushed twice
+	push(&f, int64(math.MaxInt64))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	val := pop(&f).(float64)
+	if val != 9223372036854775808.0 {
+		t.Errorf("L2F: expected a result of 9.223372E18, but got: %f", val)
+	}
+	if f.TOS != -1 {
+		t.Errorf("L2F: Expected stack with 0 items, but got a TOS of: %d", f.TOS)
+	}
+}
+
 // L2I: Convert long to int
 func TestL2i(t *testing.T) {
 	f := newFrame(opcodes.L2I)
@@ -808,6 +1168,31 @@ func TestLconst1(t *testing.T) {
 	}
 }
 
+// LCONST_0 and LCONST_1: a long is a category-2 value, so it occupies two slots, each holding
+// the value as an int64
+func TestLconstBothSlots(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		opcode byte
+		value  int64
+	}{{"LCONST_0", opcodes.LCONST_0, 0}, {"LCONST_1", opcodes.LCONST_1, 1}} {
+		f := newFrame(test.opcode)
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f) // push the new frame
+		_ = runFrame(fs)
+
+		if f.TOS != 1 {
+			t.Errorf("%s: Expected top of stack to be 1, got: %d", test.name, f.TOS)
+			continue
+		}
+		for slot := 1; slot >= 0; slot-- {
+			if value, ok := pop(&f).(int64); !ok || value != test.value {
+				t.Errorf("%s: Expected slot %d to hold int64 %d, got: %v", test.name, slot, test.value, value)
+			}
+		}
+	}
+}
+
 // LDC: get CP entry indexed by following byte
 func TestLdc(t *testing.T) {
 	f := newFrame(opcodes.LDC)
@@ -1016,6 +1401,71 @@ func TestLdc2wForLong(t *testing.T) {
 	}
 }
 
+// LDC2_W: load a long that needs all 64 bits; it occupies two stack slots, both holding the value
+func TestLdc2wForLongAllBits(t *testing.T) {
+	f := newFrame(opcodes.LDC2_W)
+	f.Meth = append(f.Meth, 0x00)
+	f.Meth = append(f.Meth, 0x01)
+
+	CP := classloader.CPool{}
+	f.CP = &CP
+	CP.LongConsts = []int64{0x1122334455667788}
+	CP.CpIndex = []classloader.CpEntry{
+		{}, // dummy entry at index 0
+		{Type: classloader.LongConst, Slot: 0},
+		{Type: classloader.Dummy, Slot: 0}, // a long takes up two CP slots
+	}
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	if f.TOS != 1 {
+		t.Errorf("LDC2_W: Top of stack, expected 1, got: %d", f.TOS)
+	}
+	if f.PC != 3 {
+		t.Errorf("LDC2_W: Expected PC to be 3 after the two operand bytes, got: %d", f.PC)
+	}
+	value := pop(&f).(int64)
+	if value != 0x1122334455667788 {
+		t.Errorf("LDC2_W: Expected popped value to be 0x1122334455667788, got: 0x%X", value)
+	}
+	value = pop(&f).(int64)
+	if value != 0x1122334455667788 {
+		t.Errorf("LDC2_W: Expected second slot to be 0x1122334455667788, got: 0x%X", value)
+	}
+}
+
+// LDC2_W: load a double with a fractional part; it occupies two stack slots, both holding the value
+func TestLdc2wForDoubleFraction(t *testing.T) {
+	f := newFrame(opcodes.LDC2_W)
+	f.Meth = append(f.Meth, 0x00)
+	f.Meth = append(f.Meth, 0x01)
+
+	CP := classloader.CPool{}
+	f.CP = &CP
+	CP.Doubles = []float64{3.14159}
+	CP.CpIndex = []classloader.CpEntry{
+		{}, // dummy entry at index 0
+		{Type: classloader.DoubleConst, Slot: 0},
+		{Type: classloader.Dummy, Slot: 0}, // a double takes up two CP slots
+	}
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+	if f.TOS != 1 {
+		t.Errorf("LDC2_W: Top of stack, expected 1, got: %d", f.TOS)
+	}
+	value := pop(&f).(float64)
+	if value != 3.14159 {
+		t.Errorf("LDC2_W: Expected popped value to be 3.14159, got: %f", value)
+	}
+	value = pop(&f).(float64)
+	if value != 3.14159 {
+		t.Errorf("LDC2_W: Expected second slot to be 3.14159, got: %f", value)
+	}
+}
+
 // LDIV: (pop 2 longs, divide second term by top of stack, push result)
 func TestLdiv(t *testing.T) {
 	f := newFrame(opcodes.LDIV)
@@ -1040,4 +1490,24 @@ func TestLdiv(t *testing.T) {
 	}
 }
 
+// LDIV: Long.MIN_VALUE / -1 overflows, and so wraps to Long.MIN_VALUE
+func TestLdivMinValueByMinusOne(t *testing.T) {
+	f := newFrame(opcodes.LDIV)
+	push(&f, int64(math.MinInt64))
+	push(&f, int64(math.MinInt64))
+
+	push(&f, int64(-1))
+	push(&f, int64(-1))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	value := pop(&f).(int64)
+	pop(&f)
+	if value != math.MinInt64 {
+		t.Errorf("LDIV: Expected MIN_VALUE / -1 to be %d, got: %d", int64(math.MinInt64), value)
+	}
+}
+
 // LDIV: with divide by zero error. This is handled in the wholeClassTests package
@@ -7,6 +7,7 @@
 package jvm
 
 import (
+	"container/list"
 	"io"
 	"jacobin/classloader"
 	"jacobin/exceptions"
@@ -19,8 +20,10 @@ import (
 	"jacobin/stringPool"
 	"jacobin/thread"
 	"jacobin/types"
+	"math"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"unsafe"
 )
@@ -250,6 +253,26 @@ func TestLrem(t *testing.T) {
 	}
 }
 
+// LREM: the remainder of Long.MIN_VALUE / -1 is 0
+func TestLremMinValueByMinusOne(t *testing.T) {
+	f := newFrame(opcodes.LREM)
+	push(&f, int64(math.MinInt64))
+	push(&f, int64(math.MinInt64))
+
+	push(&f, int64(-1))
+	push(&f, int64(-1))
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	value := pop(&f).(int64)
+	pop(&f)
+	if value != 0 {
+		t.Errorf("LREM: Expected MIN_VALUE %% -1 to be 0, got: %d", value)
+	}
+}
+
 // LREM: long modulo -- divide by zero
 func TestLremDivideByZero(t *testing.T) {
 	f := newFrame(opcodes.LREM)
@@ -544,10 +567,11 @@ func TestLxor(t *testing.T) {
 	}
 }
 
-// MONITORENTER: The JDK JVM does not implement this, nor do we. So just pop the ref off stack
+// MONITORENTER: Enter the monitor of the object on the stack
 func TestMonitorEnter(t *testing.T) {
 	f := newFrame(opcodes.MONITORENTER)
-	push(&f, &f) // push any value and make sure it gets popped off
+	obj := object.MakeEmptyObject()
+	push(&f, obj)
 
 	fs := frames.CreateFrameStack()
 	fs.PushFront(&f) // push the new frame
@@ -556,12 +580,19 @@ func TestMonitorEnter(t *testing.T) {
 	if f.TOS != -1 {
 		t.Errorf("MONITORENTER: Expected an empty stack, but got a tos of: %d", f.TOS)
 	}
+
+	// the frame's thread now holds the monitor, so it can exit it
+	if err := obj.MonitorExit(f.Thread); err != nil {
+		t.Errorf("MONITORENTER: Expected the monitor to be held, but got: %s", err.Error())
+	}
 }
 
-// MONITOREXIT: The JDK JVM does not implement this, nor do we. So just pop the ref off stack
+// MONITOREXIT: Exit the monitor of the object on the stack
 func TestMonitorExit(t *testing.T) {
 	f := newFrame(opcodes.MONITOREXIT)
-	push(&f, &f) // push any value and make sure it gets popped off
+	obj := object.MakeEmptyObject()
+	obj.MonitorEnter(f.Thread)
+	push(&f, obj)
 
 	fs := frames.CreateFrameStack()
 	fs.PushFront(&f) // push the new frame
@@ -570,6 +601,111 @@ func TestMonitorExit(t *testing.T) {
 	if f.TOS != -1 {
 		t.Errorf("MONITOREXIT: Expected an empty stack, but got a tos of: %d", f.TOS)
 	}
+
+	// the monitor was released, so exiting it again is an error
+	if err := obj.MonitorExit(f.Thread); err == nil {
+		t.Errorf("MONITOREXIT: Expected the monitor to have been released")
+	}
+}
+
+// MONITORENTER and MONITOREXIT: two threads increment a shared counter inside
+// a synchronized block. Run with -race to confirm mutual exclusion.
+func TestMonitorEnterExitTwoThreads(t *testing.T) {
+	obj := object.MakeEmptyObject()
+	counter := 0
+	const increments = 1000
+
+	runOpcode := func(opcode byte, threadID int) {
+		f := newFrame(opcode)
+		f.Thread = threadID
+		push(&f, obj)
+		fs := frames.CreateFrameStack()
+		fs.PushFront(&f)
+		_ = runFrame(fs)
+	}
+
+	var wg sync.WaitGroup
+	for threadID := 1; threadID <= 2; threadID++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < increments; i++ {
+				runOpcode(opcodes.MONITORENTER, id)
+				counter += 1
+				runOpcode(opcodes.MONITOREXIT, id)
+			}
+		}(threadID)
+	}
+	wg.Wait()
+
+	if counter != 2*increments {
+		t.Errorf("MONITORENTER/MONITOREXIT: Expected counter to be %d, got: %d", 2*increments, counter)
+	}
+}
+
+// MONITORENTER and MONITOREXIT: when the exception for a null object, or for exiting a monitor
+// the thread does not hold, is caught, execution resumes at the handler, here: POP (the
+// exception); ICONST_5; RETURN
+func TestMonitorExceptionsCaught(t *testing.T) {
+	globals.InitGlobals("testWithoutShutdown")
+	log.Init()
+	gl := globals.GetGlobalRef()
+	gl.FuncInstantiateClass = func(name string, _ *list.List) (any, error) {
+		return object.MakeEmptyObjectWithClassName(&name), nil
+	}
+
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() {
+		_ = w.Close()
+		os.Stderr = normalStderr
+	}()
+
+	npeName := "java/lang/NullPointerException"
+	imseName := "java/lang/IllegalMonitorStateException"
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{{Type: 0, Slot: 0},
+		{Type: classloader.ClassRef, Slot: 0}, {Type: classloader.ClassRef, Slot: 1}}
+	CP.ClassRefs = []uint32{stringPool.GetStringIndex(&npeName), stringPool.GetStringIndex(&imseName)}
+
+	tests := []struct {
+		name      string
+		opcode    byte
+		objRef    any
+		catchType uint16
+	}{
+		{"MONITORENTER of null", opcodes.MONITORENTER, object.Null, 1},
+		{"MONITOREXIT of null", opcodes.MONITOREXIT, object.Null, 1},
+		{"MONITOREXIT of a monitor not held", opcodes.MONITOREXIT, object.MakeEmptyObject(), 2},
+	}
+	for _, test := range tests {
+		code := []byte{test.opcode, opcodes.POP, opcodes.ICONST_5, opcodes.RETURN}
+		classloader.MTable = make(map[string]classloader.MTentry)
+		classloader.MTable["test/Caller.call()V"] = classloader.MTentry{
+			Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 3, MaxLocals: 1, Code: code, Cp: &CP,
+				Exceptions: []classloader.CodeException{{StartPc: 0, EndPc: 1, HandlerPc: 1, CatchType: test.catchType}}},
+			MType: 'J',
+		}
+
+		th := thread.CreateThread()
+		th.AddThreadToTable(gl)
+		f := frames.CreateFrame(3)
+		f.ClName, f.MethName, f.MethType, f.CP, f.Meth = "test/Caller", "call", "()V", &CP, code
+		f.Thread = th.ID
+		push(f, test.objRef)
+
+		fs := frames.CreateFrameStack()
+		fs.PushFront(f)
+		th.Stack = fs
+		if err := runFrame(fs); err != nil {
+			t.Errorf("%s: Unexpected error: %s", test.name, err.Error())
+			continue
+		}
+		if f.OpStack[0] != int64(5) {
+			t.Errorf("%s: Expected the handler to run, got stack=%v", test.name, f.OpStack)
+		}
+	}
 }
 
 // NEW: Instantiate object -- here with an error
@@ -1267,6 +1403,142 @@ func TestWideDSTORE(t *testing.T) {
 	}
 }
 
+// WIDE version of FLOAD, with an index that doesn't fit in one byte
+func TestWideFLOADIndexOver255(t *testing.T) {
+	globals.InitGlobals("test")
+	_ = log.SetLogLevel(log.WARNING)
+
+	f := newFrame(opcodes.WIDE)
+	f.Meth = append(f.Meth, opcodes.FLOAD)
+	f.Meth = append(f.Meth, 0x01) // index pointing to local variable 300
+	f.Meth = append(f.Meth, 0x2C)
+	fs := frames.CreateFrameStack()
+	f.Locals = make([]interface{}, 302)
+	for i := range f.Locals {
+		f.Locals[i] = float64(i) // so that the value of each local identifies it
+	}
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	if f.TOS != 0 {
+		t.Fatalf("WIDE,FLOAD: expected one value on the stack, got TOS of: %d", f.TOS)
+	}
+	ret := pop(&f).(float64)
+	if ret != 300 {
+		t.Errorf("WIDE,FLOAD: expected the value of local 300, got that of local %f", ret)
+	}
+	if f.PC != 4 { // 1 for WIDE, 3 for FLOAD and its two-byte index
+		t.Errorf("WIDE,FLOAD: expected PC to be 4, got: %d", f.PC)
+	}
+}
+
+// WIDE version of ASTORE, with an index that doesn't fit in one byte
+func TestWideASTOREIndexOver255(t *testing.T) {
+	globals.InitGlobals("test")
+	_ = log.SetLogLevel(log.WARNING)
+
+	f := newFrame(opcodes.WIDE)
+	f.Meth = append(f.Meth, opcodes.ASTORE)
+	f.Meth = append(f.Meth, 0x01) // index pointing to local variable 257
+	f.Meth = append(f.Meth, 0x01)
+	obj := object.MakeEmptyObject()
+	push(&f, obj)
+	fs := frames.CreateFrameStack()
+	f.Locals = make([]interface{}, 300)
+	fs.PushFront(&f) // push the new frame
+	_ = runFrame(fs)
+
+	if f.Locals[257] != obj {
+		t.Errorf("WIDE,ASTORE: expected locals[257] to hold the object, got: %v", f.Locals[257])
+	}
+	for _, i := range []int{1, 256, 258} { // the locals the low or high byte alone would select
+		if f.Locals[i] != nil {
+			t.Errorf("WIDE,ASTORE: expected locals[%d] to be untouched, got: %v", i, f.Locals[i])
+		}
+	}
+	if f.TOS != -1 {
+		t.Errorf("WIDE,ASTORE: expected an empty stack, got TOS of: %d", f.TOS)
+	}
+}
+
+// WIDE followed by an opcode that it can't modify is a verification error
+func TestWideInvalidOpcode(t *testing.T) {
+	globals.InitGlobals("test")
+	_ = log.SetLogLevel(log.WARNING)
+
+	normalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	f := newFrame(opcodes.WIDE)
+	f.Meth = append(f.Meth, opcodes.IADD)
+	f.Meth = append(f.Meth, 0x00)
+	f.Meth = append(f.Meth, 0x01)
+	fs := frames.CreateFrameStack()
+	fs.PushFront(&f) // push the new frame
+	err := runFrame(fs)
+
+	_ = w.Close()
+	msg, _ := io.ReadAll(r)
+	os.Stderr = normalStderr
+
+	if err == nil || !strings.Contains(err.Error(), "IADD (0x60)") ||
+		!strings.Contains(err.Error(), "cannot be modified by WIDE") {
+		t.Errorf("WIDE,IADD: expected an error naming IADD, got: %v", err)
+	}
+	if !strings.Contains(string(msg), "java.lang.VerifyError") {
+		t.Errorf("WIDE,IADD: expected a VerifyError, got: %s", string(msg))
+	}
+}
+
+// WIDE followed by an opcode that it can't modify: when the VerifyError is caught, execution
+// resumes at the handler, here: POP (the error); ICONST_5; RETURN
+func TestWideInvalidOpcodeCaught(t *testing.T) {
+	globals.InitGlobals("testWithoutShutdown")
+	log.Init()
+	gl := globals.GetGlobalRef()
+	gl.FuncInstantiateClass = func(name string, _ *list.List) (any, error) {
+		return object.MakeEmptyObjectWithClassName(&name), nil
+	}
+
+	normalStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	defer func() {
+		_ = w.Close()
+		os.Stderr = normalStderr
+	}()
+
+	verifyErrorName := "java/lang/VerifyError"
+	CP := classloader.CPool{}
+	CP.CpIndex = []classloader.CpEntry{{Type: 0, Slot: 0}, {Type: classloader.ClassRef, Slot: 0}}
+	CP.ClassRefs = []uint32{stringPool.GetStringIndex(&verifyErrorName)}
+
+	code := []byte{opcodes.WIDE, opcodes.IADD, opcodes.POP, opcodes.ICONST_5, opcodes.RETURN}
+	classloader.MTable = make(map[string]classloader.MTentry)
+	classloader.MTable["test/Caller.call()V"] = classloader.MTentry{
+		Meth: classloader.JmEntry{AccessFlags: 0x0001, MaxStack: 3, MaxLocals: 1, Code: code, Cp: &CP,
+			Exceptions: []classloader.CodeException{{StartPc: 0, EndPc: 2, HandlerPc: 2, CatchType: 1}}},
+		MType: 'J',
+	}
+
+	th := thread.CreateThread()
+	th.AddThreadToTable(gl)
+	f := frames.CreateFrame(3)
+	f.ClName, f.MethName, f.MethType, f.CP, f.Meth = "test/Caller", "call", "()V", &CP, code
+	f.Thread = th.ID
+
+	fs := frames.CreateFrameStack()
+	fs.PushFront(f)
+	th.Stack = fs
+	if err := runFrame(fs); err != nil {
+		t.Fatalf("WIDE,IADD: Unexpected error: %s", err.Error())
+	}
+	if f.OpStack[0] != int64(5) {
+		t.Errorf("WIDE,IADD: Expected the handler to run, got stack=%v", f.OpStack)
+	}
+}
+
 // WIDE version of IINC
 func TestWideIINC(t *testing.T) {
 	globals.InitGlobals("test")
@@ -1465,3 +1737,29 @@ func TestConvertInterfaceToUint64(t *testing.T) {
 		t.Error("Expected TestConvertInterfaceToUint64() to !=0, got 0\n")
 	}
 }
+
+// a synchronized instance method holds the monitor of its object until its frame is discarded
+func TestSynchronizedMethodHoldsMonitor(t *testing.T) {
+	caller := newFrame(opcodes.NOP)
+	caller.Thread = 1
+	obj := object.MakeEmptyObject()
+	push(&caller, obj)
+
+	m := classloader.JmEntry{AccessFlags: 0x0020, MaxStack: 1, MaxLocals: 1} // ACC_SYNCHRONIZED
+	fram, err := createAndInitNewFrame("testClass", "syncMeth", "()V", &m, true, &caller)
+	if err != nil {
+		t.Fatalf("Unexpected error creating frame: %s", err.Error())
+	}
+
+	if fram.Monitor != obj {
+		t.Errorf("Expected the frame to hold the monitor of its object")
+	}
+	if err = obj.MonitorExit(2); err == nil {
+		t.Errorf("Expected another thread to be unable to exit the monitor")
+	}
+
+	frames.ReleaseMonitor(fram)
+	if err = obj.MonitorExit(1); err == nil {
+		t.Errorf("Expected the monitor to have been released with the frame")
+	}
+}
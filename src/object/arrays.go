@@ -95,6 +95,7 @@ func Make2DimArray(ptrArrSize, leafArrSize int64, arrType uint8) (*Object, error
 	}
 
 	ptrArr.KlassName = firstArray.KlassName
+	TrackObject(ptrArr)
 	return ptrArr, nil
 }
 
@@ -126,6 +127,7 @@ func Make1DimArray(arrType uint8, size int64) *Object {
 	}
 	value := o.FieldTable["value"]
 	o.KlassName = stringPool.GetStringIndex(&value.Ftype) // in arrays, Klass field is a pointer to the array type string
+	TrackObject(o)
 	return o
 }
 
@@ -140,6 +142,7 @@ func Make1DimRefArray(objType *string, size int64) *Object {
 	o.FieldTable["value"] = of
 	o.KlassName = stringPool.GetStringIndex(&of.Ftype)
 	// o.Klass = &of.Ftype
+	TrackObject(o)
 	return o
 }
 
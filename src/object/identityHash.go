@@ -0,0 +1,36 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package object
+
+import (
+	"jacobin/globals"
+	"sync/atomic"
+)
+
+// An object's identity hash code (the value returned by Object.hashCode() when it
+// is not overridden, and by System.identityHashCode()) is normally the lower 32 bits
+// of the object's address. Addresses differ from run to run, and so does the order
+// in which hash-based collections, such as HashMap and HashSet, return their
+// entries. When the -deterministicHash option is specified, identity hash codes are
+// instead assigned in sequence, as objects are created, so that a program that
+// creates its objects in the same order sees the same hash codes and collection
+// ordering on every run. (This is similar to HotSpot's -XX:hashCode=3.)
+
+var hashSequence atomic.Uint32
+
+// IdentityHash returns the identity hash code for a new object at the given address.
+func IdentityHash(addr uintptr) uint32 {
+	if globals.GetGlobalRef().DeterministicHash {
+		return hashSequence.Add(1)
+	}
+	return uint32(addr)
+}
+
+// ResetHashSequence restarts the sequence of deterministic hash codes. Used in testing.
+func ResetHashSequence() {
+	hashSequence.Store(0)
+}
@@ -0,0 +1,138 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package object
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Monitor is the monitor that every Java object has. It is used by synchronized
+// blocks (the MONITORENTER and MONITOREXIT bytecodes) and by synchronized methods.
+// At most one thread at a time owns the monitor. The owning thread can enter it
+// again (monitors are reentrant); the monitor is released when the owner has
+// exited it as many times as it entered it.
+//
+// The monitor also holds the wait set used by Object.wait(), notify(), and notifyAll().
+//
+// Monitors are created only when an object is first locked, so most objects never have one.
+type Monitor struct {
+	lock     sync.Mutex // guards the fields below
+	release  *sync.Cond // signaled whenever the monitor becomes free
+	notified *sync.Cond // signaled by notify() and broadcast by notifyAll() to wake waiting threads
+	owner    int        // the ID of the thread that owns the monitor
+	count    int        // the number of times the owner has entered the monitor; 0 means it's free
+}
+
+var errNotOwner = errors.New("current thread is not owner")
+
+// monitorCreation serializes the creation of monitors so that two threads locking
+// an object for the first time at the same moment end up sharing the same monitor.
+var monitorCreation sync.Mutex
+
+// getMonitor returns the object's monitor, creating it if need be
+func (objPtr *Object) getMonitor() *Monitor {
+	monitorCreation.Lock()
+	defer monitorCreation.Unlock()
+
+	if objPtr.Mark.Monitor == nil {
+		m := &Monitor{}
+		m.release = sync.NewCond(&m.lock)
+		m.notified = sync.NewCond(&m.lock)
+		objPtr.Mark.Monitor = m
+	}
+	return objPtr.Mark.Monitor
+}
+
+// MonitorEnter acquires the object's monitor for the thread, waiting until
+// any other thread that owns the monitor has released it.
+func (objPtr *Object) MonitorEnter(threadID int) {
+	m := objPtr.getMonitor()
+	m.lock.Lock()
+	for m.count > 0 && m.owner != threadID {
+		m.release.Wait()
+	}
+	m.owner = threadID
+	m.count += 1
+	m.lock.Unlock()
+}
+
+// MonitorExit exits the object's monitor once. It returns an error if the
+// thread does not own the monitor, which the JVM reports as an
+// IllegalMonitorStateException.
+func (objPtr *Object) MonitorExit(threadID int) error {
+	m := objPtr.getMonitor()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.count == 0 || m.owner != threadID {
+		return errNotOwner
+	}
+	m.count -= 1
+	if m.count == 0 {
+		m.release.Signal()
+	}
+	return nil
+}
+
+// MonitorWait implements Object.wait(): the thread, which must own the monitor,
+// releases it entirely and waits until it is notified or, if timeout is greater
+// than zero, until the timeout elapses. It then reacquires the monitor with the
+// same entry count as before. As Java permits, a thread can occasionally wake
+// up without having been notified (a spurious wakeup).
+func (objPtr *Object) MonitorWait(threadID int, timeout time.Duration) error {
+	m := objPtr.getMonitor()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.count == 0 || m.owner != threadID {
+		return errNotOwner
+	}
+
+	// release the monitor, remembering how many times it was entered
+	entries := m.count
+	m.count = 0
+	m.release.Signal()
+
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			m.lock.Lock()
+			m.notified.Broadcast() // other waiters wake up spuriously, which Java allows
+			m.lock.Unlock()
+		})
+		defer timer.Stop()
+	}
+	m.notified.Wait()
+
+	// reacquire the monitor
+	for m.count > 0 {
+		m.release.Wait()
+	}
+	m.owner = threadID
+	m.count = entries
+	return nil
+}
+
+// MonitorNotify implements Object.notify() and, if all is true, Object.notifyAll().
+// The thread must own the monitor.
+func (objPtr *Object) MonitorNotify(threadID int, all bool) error {
+	m := objPtr.getMonitor()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.count == 0 || m.owner != threadID {
+		return errNotOwner
+	}
+
+	if all {
+		m.notified.Broadcast()
+	} else {
+		m.notified.Signal()
+	}
+	return nil
+}
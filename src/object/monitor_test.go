@@ -0,0 +1,172 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package object
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMonitorIsReentrant(t *testing.T) {
+	obj := MakeEmptyObject()
+	obj.MonitorEnter(1)
+	obj.MonitorEnter(1) // the owner can enter again without blocking
+
+	if err := obj.MonitorExit(1); err != nil {
+		t.Errorf("Unexpected error on first exit: %s", err.Error())
+	}
+	if err := obj.MonitorExit(1); err != nil {
+		t.Errorf("Unexpected error on second exit: %s", err.Error())
+	}
+
+	// the monitor has been exited as many times as it was entered, so a third exit is an error
+	if err := obj.MonitorExit(1); err == nil {
+		t.Errorf("Expected an error on exiting a monitor that is not held")
+	}
+}
+
+func TestMonitorExitByNonOwner(t *testing.T) {
+	obj := MakeEmptyObject()
+	obj.MonitorEnter(1)
+
+	if err := obj.MonitorExit(2); err == nil {
+		t.Errorf("Expected an error when a thread exits a monitor owned by another thread")
+	}
+	if err := obj.MonitorExit(1); err != nil {
+		t.Errorf("Unexpected error on exit by owner: %s", err.Error())
+	}
+}
+
+// two threads increment a shared counter while holding the monitor. Run with -race
+// to confirm that the monitor provides mutual exclusion.
+func TestMonitorMutualExclusion(t *testing.T) {
+	obj := MakeEmptyObject()
+	counter := 0
+	const increments = 10000
+
+	var wg sync.WaitGroup
+	for threadID := 1; threadID <= 2; threadID++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < increments; i++ {
+				obj.MonitorEnter(id)
+				counter += 1
+				_ = obj.MonitorExit(id)
+			}
+		}(threadID)
+	}
+	wg.Wait()
+
+	if counter != 2*increments {
+		t.Errorf("Expected counter to be %d, got: %d", 2*increments, counter)
+	}
+}
+
+// the consumer waits until the producer has produced an item and notified it
+func TestMonitorWaitAndNotify(t *testing.T) {
+	obj := MakeEmptyObject()
+	var queue []int
+	consumed := make(chan int)
+
+	go func() { // consumer, thread 2
+		obj.MonitorEnter(2)
+		for len(queue) == 0 {
+			if err := obj.MonitorWait(2, 0); err != nil {
+				t.Errorf("Unexpected error from wait: %s", err.Error())
+			}
+		}
+		item := queue[0]
+		queue = queue[1:]
+		_ = obj.MonitorExit(2)
+		consumed <- item
+	}()
+
+	time.Sleep(10 * time.Millisecond) // give the consumer a chance to start waiting
+
+	// producer, thread 1
+	obj.MonitorEnter(1)
+	queue = append(queue, 42)
+	if err := obj.MonitorNotify(1, false); err != nil {
+		t.Errorf("Unexpected error from notify: %s", err.Error())
+	}
+	_ = obj.MonitorExit(1)
+
+	select {
+	case item := <-consumed:
+		if item != 42 {
+			t.Errorf("Expected consumer to get 42, got: %d", item)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Consumer was never notified")
+	}
+}
+
+// notifyAll wakes every waiting thread
+func TestMonitorNotifyAll(t *testing.T) {
+	obj := MakeEmptyObject()
+	ready := false
+	var wg sync.WaitGroup
+
+	for threadID := 2; threadID <= 4; threadID++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			obj.MonitorEnter(id)
+			for !ready {
+				_ = obj.MonitorWait(id, 0)
+			}
+			_ = obj.MonitorExit(id)
+		}(threadID)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	obj.MonitorEnter(1)
+	ready = true
+	_ = obj.MonitorNotify(1, true)
+	_ = obj.MonitorExit(1)
+
+	done := make(chan bool)
+	go func() {
+		wg.Wait()
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Not all waiting threads were woken by notifyAll")
+	}
+}
+
+func TestMonitorWaitTimesOut(t *testing.T) {
+	obj := MakeEmptyObject()
+	obj.MonitorEnter(1)
+	obj.MonitorEnter(1)
+
+	if err := obj.MonitorWait(1, 10*time.Millisecond); err != nil {
+		t.Errorf("Unexpected error from wait: %s", err.Error())
+	}
+
+	// the monitor is held again, with both entries restored
+	if err := obj.MonitorExit(1); err != nil {
+		t.Errorf("Unexpected error on first exit after wait: %s", err.Error())
+	}
+	if err := obj.MonitorExit(1); err != nil {
+		t.Errorf("Unexpected error on second exit after wait: %s", err.Error())
+	}
+}
+
+func TestMonitorWaitAndNotifyWithoutMonitor(t *testing.T) {
+	obj := MakeEmptyObject()
+	if err := obj.MonitorWait(1, 0); err == nil {
+		t.Errorf("Expected an error on wait without holding the monitor")
+	}
+	if err := obj.MonitorNotify(1, false); err == nil {
+		t.Errorf("Expected an error on notify without holding the monitor")
+	}
+}
@@ -28,11 +28,13 @@ type Object struct {
 
 // These mark word contains values for different purposes. Here,
 // we use the first four bytes for a hash value, which is taken
-// from the address of the object. The 'misc' field will eventually
-// contain other values, such as locking and monitoring items.
+// from the address of the object (see identityHash.go). The 'misc' field will eventually
+// contain other values. The monitor, used for locking, is created
+// only when the object is first locked. (See monitor.go.)
 type MarkWord struct {
-	Hash uint32 // contains hash code which is the lower 32 bits of the address
-	Misc uint32 // at present unused
+	Hash    uint32   // contains hash code which is the lower 32 bits of the address
+	Misc    uint32   // at present unused
+	Monitor *Monitor // nil until the object is first locked
 }
 
 // We need to know the type of the field only to tell whether
@@ -49,11 +51,11 @@ type Field struct {
 var Null *Object = nil
 
 // MakeEmptyObject() creates an empty basis Object. It is expected that other
-// code will fill in the Klass header field and the data fields.
+// code will fill in the Klass header field and the data fields, and then pass
+// the object to TrackObject() (see objectDump.go).
 func MakeEmptyObject() *Object {
 	o := Object{}
-	h := uintptr(unsafe.Pointer(&o))
-	o.Mark.Hash = uint32(h)
+	o.Mark.Hash = IdentityHash(uintptr(unsafe.Pointer(&o)))
 	o.KlassName = types.InvalidStringIndex // s/be filled in later, when class is filled in.
 
 	// initialize the map of this object's fields
@@ -64,12 +66,12 @@ func MakeEmptyObject() *Object {
 // MakeEmptyObjectWithClassName() creates an empty Object using the passed-in class name
 func MakeEmptyObjectWithClassName(className *string) *Object {
 	o := Object{}
-	h := uintptr(unsafe.Pointer(&o))
-	o.Mark.Hash = uint32(h)
+	o.Mark.Hash = IdentityHash(uintptr(unsafe.Pointer(&o)))
 	o.KlassName = stringPool.GetStringIndex(className)
 
 	// initialize the map of this object's fields
 	o.FieldTable = make(map[string]Field)
+	TrackObject(&o)
 	return &o
 }
 
@@ -82,6 +84,7 @@ func MakePrimitiveObject(classString string, ftype string, arg any) *Object {
 	field.Ftype = ftype
 	field.Fvalue = arg
 	(*objPtr).FieldTable["value"] = field
+	TrackObject(objPtr)
 	return objPtr
 }
 
@@ -0,0 +1,98 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package object
+
+import (
+	"fmt"
+	"io"
+	"jacobin/globals"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// The -Xdump:objects option has Jacobin count the live objects of each class, so that
+// DumpObjects() can print a summary of them. The dump is printed when the VM shuts down, and
+// can also be requested at any point by calling DumpObjects(). The objects themselves are not
+// referenced from here: each count goes up when an object is created and down when the
+// object is garbage collected, through a finalizer set on the object. (As Go does not collect
+// a cycle of objects that have finalizers, objects in such a cycle remain counted.)
+
+var trackedObjects struct {
+	sync.Mutex
+	counts map[string]int // the number of live objects of each class
+}
+
+// TrackObject counts a newly created object among the live objects reported by DumpObjects(),
+// if the -Xdump:objects option was specified. It must be called once the object's class name
+// is filled in. Objects created by MakeEmptyObjectWithClassName() and NewStringObject() are
+// tracked automatically; the callers of MakeEmptyObject() track the object themselves.
+func TrackObject(obj *Object) {
+	if !globals.GetGlobalRef().DumpObjects {
+		return
+	}
+	className := GoStringFromStringPoolIndex(obj.KlassName)
+	if className == "" {
+		className = "<unknown class>"
+	}
+
+	trackedObjects.Lock()
+	if trackedObjects.counts == nil {
+		trackedObjects.counts = make(map[string]int)
+	}
+	trackedObjects.counts[className]++
+	trackedObjects.Unlock()
+
+	runtime.SetFinalizer(obj, func(*Object) {
+		trackedObjects.Lock()
+		if trackedObjects.counts[className] > 0 {
+			trackedObjects.counts[className]--
+		}
+		trackedObjects.Unlock()
+	})
+}
+
+// DumpObjects prints the number of live objects of each class, in descending order of count.
+// A garbage collection is run first, so that the objects no longer reachable are collected,
+// but their finalizers run concurrently, so some of them might still be counted.
+func DumpObjects(w io.Writer) {
+	runtime.GC()
+
+	trackedObjects.Lock()
+	counts := make(map[string]int)
+	total := 0
+	for className, count := range trackedObjects.counts {
+		if count > 0 {
+			counts[className] = count
+			total += count
+		}
+	}
+	trackedObjects.Unlock()
+
+	classNames := make([]string, 0, len(counts))
+	for className := range counts {
+		classNames = append(classNames, className)
+	}
+	sort.Slice(classNames, func(i, j int) bool {
+		if counts[classNames[i]] != counts[classNames[j]] {
+			return counts[classNames[i]] > counts[classNames[j]]
+		}
+		return classNames[i] < classNames[j]
+	})
+
+	_, _ = fmt.Fprintf(w, "Object dump: %d objects in %d classes\n", total, len(classNames))
+	for _, className := range classNames {
+		_, _ = fmt.Fprintf(w, "%10d  %s\n", counts[className], className)
+	}
+}
+
+// ResetTrackedObjects clears the counts of tracked objects. Used in testing.
+func ResetTrackedObjects() {
+	trackedObjects.Lock()
+	trackedObjects.counts = nil
+	trackedObjects.Unlock()
+}
@@ -0,0 +1,92 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"jacobin/globals"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDumpObjectsCountsStrings(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().DumpObjects = true
+	defer func() { globals.GetGlobalRef().DumpObjects = false }()
+	ResetTrackedObjects()
+	defer ResetTrackedObjects()
+
+	const n = 25
+	var live []*Object
+	for i := 0; i < n; i++ {
+		live = append(live, StringObjectFromGoString(fmt.Sprintf("string %d", i)))
+	}
+	className := "java/lang/Integer"
+	live = append(live, MakeEmptyObjectWithClassName(&className))
+
+	var out bytes.Buffer
+	DumpObjects(&out)
+	dump := out.String()
+	runtime.KeepAlive(live)
+
+	var count int
+	for _, line := range strings.Split(dump, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == "java/lang/String" {
+			_, _ = fmt.Sscan(fields[0], &count)
+		}
+	}
+	if count < n {
+		t.Errorf("Expected at least %d String objects in the dump, got %d:\n%s", n, count, dump)
+	}
+	if !strings.Contains(dump, "java/lang/Integer") {
+		t.Errorf("Expected the Integer object in the dump, got:\n%s", dump)
+	}
+}
+
+func TestDumpObjectsNotTrackedByDefault(t *testing.T) {
+	globals.InitGlobals("test")
+	ResetTrackedObjects()
+
+	_ = StringObjectFromGoString("untracked")
+
+	var out bytes.Buffer
+	DumpObjects(&out)
+	if !strings.HasPrefix(out.String(), "Object dump: 0 objects") {
+		t.Errorf("Expected no objects to be tracked without -Xdump:objects, got:\n%s", out.String())
+	}
+}
+
+// An object that is garbage collected is no longer counted
+func TestDumpObjectsDropsCollectedObjects(t *testing.T) {
+	globals.InitGlobals("test")
+	globals.GetGlobalRef().DumpObjects = true
+	defer func() { globals.GetGlobalRef().DumpObjects = false }()
+	ResetTrackedObjects()
+	defer ResetTrackedObjects()
+
+	className := "test/Garbage"
+	for i := 0; i < 10; i++ {
+		_ = MakeEmptyObjectWithClassName(&className)
+	}
+
+	// the finalizers run concurrently after each collection, so wait a while for them
+	var dump string
+	for i := 0; i < 100; i++ {
+		var out bytes.Buffer
+		DumpObjects(&out)
+		dump = out.String()
+		if !strings.Contains(dump, className) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Expected the collected objects not to be in the dump, got:\n%s", dump)
+}
@@ -29,6 +29,7 @@ func NewStringObject() *Object {
 	s.Mark.Hash = 0
 	s.KlassName = types.StringPoolStringIndex // =  java/lang/String
 	s.FieldTable = make(map[string]Field)
+	TrackObject(s)
 
 	// ==== now the fields ====
 
@@ -74,21 +75,17 @@ func StringObjectFromGoString(str string) *Object {
 
 // GoStringFromStringObject: convenience method to extract a Go string from a String object (Java string)
 func GoStringFromStringObject(obj *Object) string {
-	if obj != nil && obj.KlassName == types.StringPoolStringIndex {
-		if obj.FieldTable["value"].Fvalue != nil {
-			return string(obj.FieldTable["value"].Fvalue.([]byte))
-		}
-	}
-	return ""
+	return string(ByteArrayFromStringObject(obj))
 }
 
-// ByteArrayFromStringObject: convenience method to extract a byte array from a String object (Java string)
+// ByteArrayFromStringObject: convenience method to extract a byte array from a String object (Java string).
+// A String whose value has not been set is empty, so its byte array is nil.
 func ByteArrayFromStringObject(obj *Object) []byte {
 	if obj != nil && obj.KlassName == types.StringPoolStringIndex {
-		return obj.FieldTable["value"].Fvalue.([]byte)
-	} else {
-		return nil
+		bytes, _ := obj.FieldTable["value"].Fvalue.([]byte)
+		return bytes
 	}
+	return nil
 }
 
 // StringObjectFromByteArray: convenience method to create a string object from a byte array
@@ -155,6 +152,32 @@ func IsStringObject(unknown any) bool {
 	return false
 }
 
+// CharSequenceToGoString returns the characters of an object that implements
+// java.lang.CharSequence: a String, StringBuilder, or StringBuffer. The latter two
+// keep their characters in a byte array named value, of which only the first
+// count bytes are in use. The boolean is false if the object is not one of these.
+func CharSequenceToGoString(obj *Object) (string, bool) {
+	if IsNull(obj) {
+		return "", false
+	}
+	if IsStringObject(obj) {
+		return GoStringFromStringObject(obj), true
+	}
+
+	className := GoStringFromStringPoolIndex(obj.KlassName)
+	if className != "java/lang/StringBuilder" && className != "java/lang/StringBuffer" {
+		return "", false
+	}
+	bytes, ok := obj.FieldTable["value"].Fvalue.([]byte)
+	if !ok {
+		return "", false
+	}
+	if count, ok := obj.FieldTable["count"].Fvalue.(int64); ok && count >= 0 && count <= int64(len(bytes)) {
+		bytes = bytes[:count]
+	}
+	return string(bytes), true
+}
+
 // UpdateStringObjectFromBytes: Set the value field of the given object to the given byte array
 func UpdateStringObjectFromBytes(objPtr *Object, argBytes []byte) {
 	fld := Field{Ftype: types.ByteArray, Fvalue: argBytes}
@@ -210,3 +210,31 @@ func TestIsStringObject(t *testing.T) {
 		t.Errorf("expected IsStringObject(emptyObj) to be false, got true")
 	}
 }
+
+func TestCharSequenceToGoString(t *testing.T) {
+	globals.InitGlobals("test")
+
+	str, ok := CharSequenceToGoString(StringObjectFromGoString("a String"))
+	if !ok || str != "a String" {
+		t.Errorf("String: expected (\"a String\", true), got (%q, %v)", str, ok)
+	}
+
+	// StringBuilders and StringBuffers use only the first count bytes of value
+	for _, className := range []string{"java/lang/StringBuilder", "java/lang/StringBuffer"} {
+		sb := MakeEmptyObjectWithClassName(&className)
+		sb.FieldTable["value"] = Field{Ftype: types.ByteArray, Fvalue: []byte("builder\x00\x00\x00")}
+		sb.FieldTable["count"] = Field{Ftype: types.Int, Fvalue: int64(7)}
+		str, ok = CharSequenceToGoString(sb)
+		if !ok || str != "builder" {
+			t.Errorf("%s: expected (\"builder\", true), got (%q, %v)", className, str, ok)
+		}
+	}
+
+	notCharSeq := MakePrimitiveObject("java/lang/Integer", types.Int, int64(42))
+	if _, ok = CharSequenceToGoString(notCharSeq); ok {
+		t.Errorf("Integer: expected it not to be treated as a CharSequence")
+	}
+	if _, ok = CharSequenceToGoString(nil); ok {
+		t.Errorf("nil: expected it not to be treated as a CharSequence")
+	}
+}
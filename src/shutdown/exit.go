@@ -39,6 +39,13 @@ func Exit(errorCondition ExitStatus) int {
 		}
 	}
 
+	if g.DumpObjects && g.FuncDumpObjects != nil {
+		g.FuncDumpObjects()
+	}
+	if g.DumpBytecodes && g.FuncDumpBytecodes != nil {
+		g.FuncDumpBytecodes()
+	}
+
 	msg := fmt.Sprintf("shutdown.Exit(%d) requested", errorCondition)
 	if log.Log(msg, log.INFO) != nil {
 		errorCondition = UNKNOWN_ERROR
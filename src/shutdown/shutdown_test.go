@@ -77,3 +77,43 @@ func TestShutdownReturn(t *testing.T) {
 		t.Errorf("Expecting exit() return value of 0, but got %d", ret)
 	}
 }
+
+func TestShutdownDumpsObjects(t *testing.T) {
+	globals.InitGlobals("test")
+	gl := globals.GetGlobalRef()
+	log.Init()
+
+	dumped := false
+	gl.FuncDumpObjects = func() { dumped = true }
+
+	Exit(OK)
+	if dumped {
+		t.Error("Objects were dumped at shutdown without -Xdump:objects")
+	}
+
+	gl.DumpObjects = true
+	Exit(OK)
+	if !dumped {
+		t.Error("Objects were not dumped at shutdown with -Xdump:objects")
+	}
+}
+
+func TestShutdownDumpsBytecodeCoverage(t *testing.T) {
+	globals.InitGlobals("test")
+	gl := globals.GetGlobalRef()
+	log.Init()
+
+	dumped := false
+	gl.FuncDumpBytecodes = func() { dumped = true }
+
+	Exit(OK)
+	if dumped {
+		t.Error("Bytecode coverage was dumped at shutdown without -Xdump:bytecodes")
+	}
+
+	gl.DumpBytecodes = true
+	Exit(OK)
+	if !dumped {
+		t.Error("Bytecode coverage was not dumped at shutdown with -Xdump:bytecodes")
+	}
+}
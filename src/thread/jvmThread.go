@@ -21,6 +21,13 @@ type ExecThread struct {
 	Trace bool       // do we trace instructions?
 }
 
+// the thread priorities, as in java.lang.Thread
+const (
+	MinPriority  = 1
+	NormPriority = 5
+	MaxPriority  = 10
+)
+
 // CreateThread creates an execution thread and initializes it with default values
 // All Jacobin execution threads *must* use this function to create a thread
 func CreateThread() ExecThread {
@@ -36,6 +36,7 @@ const StringIndex = "T"
 const GolangString = "G"
 const FileHandle = "FH" // The related Fvalue is a Golang *os.File
 const BigInteger = "BI" // The related Fvalue is a Golang *big.Int
+const Properties = "PR" // The related Fvalue is a Golang map[string]string
 
 const Static = "X"
 const StaticDouble = "XD"
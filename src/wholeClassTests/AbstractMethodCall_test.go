@@ -0,0 +1,103 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package wholeClassTests
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+/*
+ * Tests for AbstractMethodCall.class, which checks that invoking an interface method that
+ * the object's class does not implement throws an AbstractMethodError. This happens when the
+ * class was compiled against an earlier version of the interface, so Square.class was compiled
+ * before name() was added to Shape. (Shape and Square are in testdata.)
+ *
+ * public class AbstractMethodCall {
+ *     public static void main(String[] args) {
+ *         Shape s = new Square();
+ *         System.out.println(s.name());
+ *     }
+ * }
+ *
+ * interface Shape {
+ *     String name();
+ * }
+ *
+ * class Square implements Shape {
+ * }
+ *
+ * When working correctly, an AbstractMethodError is reported on stderr.
+ */
+
+func initVarsAbstractMethodCall() error {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		return fmt.Errorf("test not run due to -short")
+	}
+
+	_JACOBIN = os.Getenv("JACOBIN_EXE") // returns "" if JACOBIN_EXE has not been specified.
+	_JVM_ARGS = ""
+	_TESTCLASS = "AbstractMethodCall.class" // the class to test
+	_APP_ARGS = ""
+
+	if _JACOBIN == "" {
+		return fmt.Errorf("test failure due to missing Jacobin executable. Please specify it in JACOBIN_EXE")
+	} else if _, err := os.Stat(_JACOBIN); err != nil {
+		return fmt.Errorf("missing Jacobin executable, which was specified as %s", _JACOBIN)
+	}
+
+	if _TESTCLASS != "" {
+		testClass := os.Getenv("JACOBIN_TESTDATA") + string(os.PathSeparator) + _TESTCLASS
+		if _, err := os.Stat(testClass); err != nil {
+			return fmt.Errorf("missing class to test, which was specified as %s", testClass)
+		} else {
+			_TESTCLASS = testClass
+		}
+	}
+	return nil
+}
+
+func TestRunAbstractMethodCall(t *testing.T) {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		t.Skip()
+	}
+
+	initErr := initVarsAbstractMethodCall()
+	if initErr != nil {
+		t.Fatalf("Test failure due to: %s", initErr.Error())
+	}
+
+	cmd := exec.Command(_JACOBIN, _TESTCLASS)
+
+	// get the stdout and stderr contents from the file execution
+	stderr, err := cmd.StderrPipe()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// run the command
+	if err = cmd.Start(); err != nil {
+		t.Errorf("Got error running Jacobin: %s", err.Error())
+	}
+
+	// Here begin the actual tests on the output to stderr and stdout
+	slurp, _ := io.ReadAll(stderr)
+	if !strings.Contains(string(slurp), "java.lang.AbstractMethodError") {
+		t.Errorf("Did not get expected AbstractMethodError on stderr. Got: %s", string(slurp))
+	}
+
+	slurp, _ = io.ReadAll(stdout)
+	if len(slurp) != 0 {
+		t.Errorf("Got unexpected output to stdout: %s", string(slurp))
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package wholeClassTests
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+/*
+ * Tests for AssertTest.class. Source code:
+ *
+ *  public class AssertTest {
+ *      public static void main(String[] args) {
+ *          int x = 42;
+ *          assert x == 0 : "x should be zero";
+ *          System.out.println("assertions passed");
+ *      }
+ *  }
+ *
+ * With -ea, the assertion fails and an AssertionError is thrown. With -da, the assert is skipped.
+ */
+
+// To run your class, enter its name in _TESTCLASS, any args in their respective variables and then run the tests.
+// This test harness expects that environmental variable JACOBIN_EXE gives the full name and path of the executable
+// we're running the tests on. The folder which contains the test class should be specified in the environmental
+// variable JACOBIN_TESTDATA (without a terminating slash).
+func initVarsAssertTest() error {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		return fmt.Errorf("test not run due to -short")
+	}
+
+	_JACOBIN = os.Getenv("JACOBIN_EXE") // returns "" if JACOBIN_EXE has not been specified.
+	_JVM_ARGS = ""
+	_TESTCLASS = "AssertTest.class" // the class to test
+	_APP_ARGS = ""
+
+	if _JACOBIN == "" {
+		return fmt.Errorf("missing Jacobin executable. Please specify it in JACOBIN_EXE")
+	} else if _, err := os.Stat(_JACOBIN); err != nil {
+		return fmt.Errorf("missing Jacobin executable, which was specified as %s", _JACOBIN)
+	}
+
+	if _TESTCLASS != "" {
+		testClass := os.Getenv("JACOBIN_TESTDATA") + string(os.PathSeparator) + _TESTCLASS
+		if _, err := os.Stat(testClass); err != nil {
+			return fmt.Errorf("missing class to test, which was specified as %s", testClass)
+		} else {
+			_TESTCLASS = testClass
+		}
+	}
+	return nil
+}
+
+// with assertions enabled, the failing assert should throw an AssertionError with the message
+func TestRunAssertEnabled(t *testing.T) {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		t.Skip()
+	}
+
+	initErr := initVarsAssertTest()
+	if initErr != nil {
+		t.Fatalf("Test failure due to: %s", initErr.Error())
+	}
+
+	_JVM_ARGS = "-ea"
+	cmd := exec.Command(_JACOBIN, _JVM_ARGS, _TESTCLASS)
+
+	// get the stdout and stderr contents from the file execution
+	stderr, err := cmd.StderrPipe()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// run the command
+	if err = cmd.Start(); err != nil {
+		t.Errorf("Got error running Jacobin: %s", err.Error())
+	}
+
+	// Here begin the actual tests on the output to stderr and stdout
+	slurp, _ := io.ReadAll(stderr)
+	if !strings.Contains(string(slurp), "java.lang.AssertionError") ||
+		!strings.Contains(string(slurp), "x should be zero") {
+		t.Errorf("Did not get expected AssertionError on stderr. Got: %s", string(slurp))
+	}
+
+	slurp, _ = io.ReadAll(stdout)
+	if strings.Contains(string(slurp), "assertions passed") {
+		t.Errorf("Assertion did not stop execution. Got on stdout: %s", string(slurp))
+	}
+
+	if err = cmd.Wait(); err == nil {
+		t.Errorf("Expected a non-zero exit code, but Jacobin exited normally")
+	}
+}
+
+// with assertions disabled, the assert is not evaluated and the program ends normally
+func TestRunAssertDisabled(t *testing.T) {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		t.Skip()
+	}
+
+	initErr := initVarsAssertTest()
+	if initErr != nil {
+		t.Fatalf("Test failure due to: %s", initErr.Error())
+	}
+
+	_JVM_ARGS = "-da"
+	cmd := exec.Command(_JACOBIN, _JVM_ARGS, _TESTCLASS)
+
+	// get the stdout and stderr contents from the file execution
+	stderr, err := cmd.StderrPipe()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// run the command
+	if err = cmd.Start(); err != nil {
+		t.Errorf("Got error running Jacobin: %s", err.Error())
+	}
+
+	// Here begin the actual tests on the output to stderr and stdout
+	slurp, _ := io.ReadAll(stderr)
+	if len(slurp) != 0 {
+		t.Errorf("Got unexpected output to stderr: %s", string(slurp))
+	}
+
+	slurp, _ = io.ReadAll(stdout)
+	if !strings.Contains(string(slurp), "assertions passed") {
+		t.Errorf("Did not get expected output to stdout. Got: %s", string(slurp))
+	}
+}
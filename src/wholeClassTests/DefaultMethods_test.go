@@ -0,0 +1,116 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin Authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)  Consult jacobin.org.
+ */
+
+package wholeClassTests
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+/*
+ * Tests for DefaultMethods.class, which checks that a class that does not implement an
+ * interface method inherits the interface's default method, whether the method is
+ * invoked through the interface (INVOKEINTERFACE) or through the class (INVOKEVIRTUAL).
+ * (Greeter, PoliteGreeter, and FriendlyGreeter are in the same source file, and so in testdata.)
+ *
+ * public class DefaultMethods {
+ *     public static void main(String[] args) {
+ *         Greeter g = new PoliteGreeter();
+ *         System.out.println(g.greet()); // invokeinterface of the inherited default method
+ *         PoliteGreeter p = new PoliteGreeter();
+ *         System.out.println(p.greet()); // invokevirtual of the inherited default method
+ *         Greeter f = new FriendlyGreeter();
+ *         System.out.println(f.greet()); // the class's override of the default method
+ *     }
+ * }
+ *
+ * interface Greeter {
+ *     default String greet() { return "Hello from Greeter"; }
+ * }
+ *
+ * class PoliteGreeter implements Greeter {
+ * }
+ *
+ * class FriendlyGreeter implements Greeter {
+ *     public String greet() { return "Hi from FriendlyGreeter"; }
+ * }
+ *
+ * When working correctly, the output should be:
+ *     Hello from Greeter
+ *     Hello from Greeter
+ *     Hi from FriendlyGreeter
+ */
+
+func initVarsDefaultMethods() error {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		return fmt.Errorf("test not run due to -short")
+	}
+
+	_JACOBIN = os.Getenv("JACOBIN_EXE") // returns "" if JACOBIN_EXE has not been specified.
+	_JVM_ARGS = ""
+	_TESTCLASS = "DefaultMethods.class" // the class to test
+	_APP_ARGS = ""
+
+	if _JACOBIN == "" {
+		return fmt.Errorf("test failure due to missing Jacobin executable. Please specify it in JACOBIN_EXE")
+	} else if _, err := os.Stat(_JACOBIN); err != nil {
+		return fmt.Errorf("missing Jacobin executable, which was specified as %s", _JACOBIN)
+	}
+
+	if _TESTCLASS != "" {
+		testClass := os.Getenv("JACOBIN_TESTDATA") + string(os.PathSeparator) + _TESTCLASS
+		if _, err := os.Stat(testClass); err != nil {
+			return fmt.Errorf("missing class to test, which was specified as %s", testClass)
+		} else {
+			_TESTCLASS = testClass
+		}
+	}
+	return nil
+}
+
+func TestRunDefaultMethods(t *testing.T) {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		t.Skip()
+	}
+
+	initErr := initVarsDefaultMethods()
+	if initErr != nil {
+		t.Fatalf("Test failure due to: %s", initErr.Error())
+	}
+
+	cmd := exec.Command(_JACOBIN, _TESTCLASS)
+
+	// get the stdout and stderr contents from the file execution
+	stderr, err := cmd.StderrPipe()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// run the command
+	if err = cmd.Start(); err != nil {
+		t.Errorf("Got error running Jacobin: %s", err.Error())
+	}
+
+	// Here begin the actual tests on the output to stderr and stdout
+	slurp, _ := io.ReadAll(stderr)
+	if len(slurp) != 0 {
+		t.Errorf("Got unexpected output to stderr: %s", string(slurp))
+	}
+
+	slurp, _ = io.ReadAll(stdout)
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(slurp), "\r\n", "\n")), "\n")
+	expected := []string{"Hello from Greeter", "Hello from Greeter", "Hi from FriendlyGreeter"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected output %v to stdout, got: %s", expected, string(slurp))
+	}
+}
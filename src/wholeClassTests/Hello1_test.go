@@ -162,6 +162,14 @@ func TestRunHelloVerboseClass(t *testing.T) {
 		t.Errorf("Got unexpected output to stderr: %s", string(slurp))
 	}
 
+	// the HotSpot-style trace of loaded classes
+	if !strings.Contains(string(slurp), "[Loaded Hello from file:") {
+		t.Errorf("Did not get -verbose:class trace of main class on stderr. Got: %s", string(slurp))
+	}
+	if !strings.Contains(string(slurp), "[Loaded java.lang.Object from jrt:/java.base]") {
+		t.Errorf("Did not get -verbose:class trace of java.lang.Object on stderr. Got: %s", string(slurp))
+	}
+
 	slurp, _ = io.ReadAll(stdout)
 
 	if !strings.Contains(string(slurp), helloMsg) {
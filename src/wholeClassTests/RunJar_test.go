@@ -0,0 +1,122 @@
+/*
+ * Jacobin VM - A Java virtual machine
+ * Copyright (c) 2024 by the Jacobin authors. All rights reserved.
+ * Licensed under Mozilla Public License 2.0 (MPL 2.0)
+ */
+
+package wholeClassTests
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/*
+ * Tests running an executable jar with -jar. The jar is built from Hello.class (see Hello1_test.go)
+ * and a manifest whose Main-Class is Hello.
+ */
+
+// initVarsRunJar builds the jar in dir from Hello.class in the test data folder,
+// and sets _TESTCLASS to the name of the jar.
+func initVarsRunJar(dir string) error {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		return fmt.Errorf("test not run due to -short")
+	}
+
+	_JACOBIN = os.Getenv("JACOBIN_EXE") // returns "" if JACOBIN_EXE has not been specified.
+	_JVM_ARGS = "-jar"
+	_APP_ARGS = ""
+
+	if _JACOBIN == "" {
+		return fmt.Errorf("missing Jacobin executable. Please specify it in JACOBIN_EXE")
+	} else if _, err := os.Stat(_JACOBIN); err != nil {
+		return fmt.Errorf("missing Jacobin executable, which was specified as %s", _JACOBIN)
+	}
+
+	testClass := os.Getenv("JACOBIN_TESTDATA") + string(os.PathSeparator) + "Hello.class"
+	classBytes, err := os.ReadFile(testClass)
+	if err != nil {
+		return fmt.Errorf("missing class to test, which was specified as %s", testClass)
+	}
+
+	_TESTCLASS = filepath.Join(dir, "hello.jar")
+	jarFile, err := os.Create(_TESTCLASS)
+	if err != nil {
+		return err
+	}
+	defer jarFile.Close()
+
+	writer := zip.NewWriter(jarFile)
+	manifest, _ := writer.Create("META-INF/MANIFEST.MF")
+	_, _ = manifest.Write([]byte("Manifest-Version: 1.0\r\nMain-Class: Hello\r\n\r\n"))
+	class, _ := writer.Create("Hello.class")
+	_, _ = class.Write(classBytes)
+	return writer.Close()
+}
+
+func TestRunJar(t *testing.T) {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		t.Skip()
+	}
+
+	initErr := initVarsRunJar(t.TempDir())
+	if initErr != nil {
+		t.Fatalf("Test failure due to: %s", initErr.Error())
+	}
+
+	cmd := exec.Command(_JACOBIN, _JVM_ARGS, _TESTCLASS)
+
+	// get the stdout and stderr contents from the file execution
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// run the command
+	if err = cmd.Start(); err != nil {
+		t.Fatalf("Got error running Jacobin: %s", err.Error())
+	}
+
+	// Here begin the actual tests on the output to stderr and stdout
+	slurp, _ := io.ReadAll(stderr)
+	if len(slurp) != 0 {
+		t.Errorf("Got unexpected output to stderr: %s", string(slurp))
+	}
+
+	slurp, _ = io.ReadAll(stdout)
+	if !strings.Contains(string(slurp), helloMsg) {
+		t.Errorf("Did not get expected output to stdout. Got: %s", string(slurp))
+	}
+
+	if err = cmd.Wait(); err != nil {
+		t.Errorf("Got error from Jacobin: %s", err.Error())
+	}
+}
+
+func TestRunJarMissing(t *testing.T) {
+	if testing.Short() { // don't run if running quick tests only. (Used primarily so GitHub doesn't run and bork)
+		t.Skip()
+	}
+
+	initErr := initVarsRunJar(t.TempDir())
+	if initErr != nil {
+		t.Fatalf("Test failure due to: %s", initErr.Error())
+	}
+
+	missingJar := filepath.Join(t.TempDir(), "missing.jar")
+	cmd := exec.Command(_JACOBIN, _JVM_ARGS, missingJar)
+	output, _ := cmd.CombinedOutput()
+	if !strings.Contains(string(output), "Error: Unable to access jarfile "+missingJar) {
+		t.Errorf("Did not get expected error for a missing jar. Got: %s", string(output))
+	}
+}
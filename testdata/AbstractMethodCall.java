@@ -0,0 +1,17 @@
+// Tests that calling an interface method that a class does not implement throws an
+// AbstractMethodError. This happens when the class was compiled against an earlier version
+// of the interface, so Square.class was compiled before name() was added to Shape.
+// Expected output, to stderr: java.lang.AbstractMethodError
+public class AbstractMethodCall {
+    public static void main(String[] args) {
+        Shape s = new Square();
+        System.out.println(s.name());
+    }
+}
+
+interface Shape {
+    String name();
+}
+
+class Square implements Shape {
+}
@@ -0,0 +1,9 @@
+// assert statement that fails when assertions are enabled (-ea)
+// and passes silently when they are disabled (-da, the default)
+public class AssertTest {
+    public static void main(String[] args) {
+        int x = 42;
+        assert x == 0 : "x should be zero";
+        System.out.println("assertions passed");
+    }
+}
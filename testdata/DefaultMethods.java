@@ -0,0 +1,26 @@
+// Tests the selection of interface default methods by INVOKEINTERFACE and INVOKEVIRTUAL.
+// Expected output:
+//     Hello from Greeter
+//     Hello from Greeter
+//     Hi from FriendlyGreeter
+public class DefaultMethods {
+    public static void main(String[] args) {
+        Greeter g = new PoliteGreeter();
+        System.out.println(g.greet()); // invokeinterface of the inherited default method
+        PoliteGreeter p = new PoliteGreeter();
+        System.out.println(p.greet()); // invokevirtual of the inherited default method
+        Greeter f = new FriendlyGreeter();
+        System.out.println(f.greet()); // the class's override of the default method
+    }
+}
+
+interface Greeter {
+    default String greet() { return "Hello from Greeter"; }
+}
+
+class PoliteGreeter implements Greeter {
+}
+
+class FriendlyGreeter implements Greeter {
+    public String greet() { return "Hi from FriendlyGreeter"; }
+}
@@ -0,0 +1,7 @@
+class SystemProperty {
+
+	public static void main(String[] args) {
+		System.out.println(System.getProperty("foo"));
+	}
+
+}
@@ -0,0 +1,27 @@
+// Tests that INVOKEVIRTUAL runs the method of the receiver's runtime class.
+// Each call is made through a VirtualA reference. Expected output:
+//     A.m
+//     B.m
+//     B.m
+public class VirtualDispatch {
+    public static void main(String[] args) {
+        VirtualA a;
+        a = new VirtualA();
+        System.out.println(a.m());
+        a = new VirtualB();
+        System.out.println(a.m()); // B overrides m()
+        a = new VirtualC();
+        System.out.println(a.m()); // C inherits B's m()
+    }
+}
+
+class VirtualA {
+    public String m() { return "A.m"; }
+}
+
+class VirtualB extends VirtualA {
+    public String m() { return "B.m"; }
+}
+
+class VirtualC extends VirtualB {
+}